@@ -54,6 +54,8 @@ func addKnownTypes(scheme *runtime.Scheme) error {
 		&PipelineActivityList{},
 		&Release{},
 		&ReleaseList{},
+		&SourceRepository{},
+		&SourceRepositoryList{},
 		&Team{},
 		&TeamList{},
 		&User{},
@@ -2,6 +2,7 @@ package v1
 
 import (
 	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
 	rbacv1 "k8s.io/api/rbac/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
@@ -150,6 +151,161 @@ type TeamSettings struct {
 	DockerRegistryOrg   string               `json:"dockerRegistryOrg,omitempty" protobuf:"bytes,16,opt,name=dockerRegistryOrg" command:"dockerregistryorg" commandUsage:"Docker registry organisation used for new projects in Jenkins X."`
 	GitPrivate          bool                 `json:"gitPrivate,omitempty" protobuf:"bytes,17,opt,name=gitPrivate" command:"gitprivate" commandUsage:"Are new repositories private by default"`
 	KubeProvider        string               `json:"kubeProvider,omitempty" protobuf:"bytes,18,opt,name=kubeProvider"`
+	BuildDefaults       BuildDefaults        `json:"buildDefaults,omitempty" protobuf:"bytes,19,opt,name=buildDefaults"`
+}
+
+// BuildDefaults the team defaults used when generating build pipelines for projects so that
+// they can be configured once for the team rather than on each invocation
+type BuildDefaults struct {
+	// DefaultImage the container image used for steps which do not specify an image and have no pod template
+	DefaultImage string `json:"defaultImage,omitempty" protobuf:"bytes,1,opt,name=defaultImage"`
+	// EngineVersion the API version of the pipeline engine resources to generate such as 'v1alpha1'
+	EngineVersion string `json:"engineVersion,omitempty" protobuf:"bytes,2,opt,name=engineVersion"`
+	// EnvAllowList if specified only these environment variables are inherited from the pod templates
+	EnvAllowList []string `json:"envAllowList,omitempty" protobuf:"bytes,3,rep,name=envAllowList"`
+	// ImageMirrors maps an image prefix (such as 'docker.io/') to the prefix of a mirror registry to use instead
+	ImageMirrors map[string]string `json:"imageMirrors,omitempty" protobuf:"bytes,4,rep,name=imageMirrors"`
+	// Resources the default resource requests and limits for steps which do not specify any
+	Resources *corev1.ResourceRequirements `json:"resources,omitempty" protobuf:"bytes,5,opt,name=resources"`
+	// UntrustedServiceAccount the restricted service account of the builds of untrusted pull requests such as pull requests from forks
+	UntrustedServiceAccount string `json:"untrustedServiceAccount,omitempty" protobuf:"bytes,6,opt,name=untrustedServiceAccount"`
+	// EgressEndpoints the URLs or host:port endpoints such as the artifact repository which the builds may connect to in addition to the git server and the docker registry when a NetworkPolicy is generated
+	EgressEndpoints []string `json:"egressEndpoints,omitempty" protobuf:"bytes,7,rep,name=egressEndpoints"`
+	// Identity the cloud identity of the service account of the builds so that they can push to cloud registries without long lived keys
+	Identity BuildIdentity `json:"identity,omitempty" protobuf:"bytes,8,opt,name=identity"`
+	// LogsBucketURL the URL of the bucket such as 's3://mybucket/logs' or 'gs://mybucket/logs' which the logs of completed builds are archived to so that they survive the garbage collection of the build pods
+	LogsBucketURL string `json:"logsBucketUrl,omitempty" protobuf:"bytes,9,opt,name=logsBucketUrl"`
+	// HistoryBucketURL the URL of the bucket which the completed pipeline activities are compacted into by 'jx gc activities' so that the history of the pipelines can be reported on after the activities are deleted
+	HistoryBucketURL string `json:"historyBucketUrl,omitempty" protobuf:"bytes,10,opt,name=historyBucketUrl"`
+	// BuildpacksBuilder the Cloud Native Buildpacks builder image such as 'paketobuildpacks/builder:base' which builds the images of projects without a Dockerfile
+	BuildpacksBuilder string `json:"buildpacksBuilder,omitempty" protobuf:"bytes,11,opt,name=buildpacksBuilder"`
+	// Bazel the remote cache and execution endpoints of the Bazel builds
+	Bazel BazelRemote `json:"bazel,omitempty" protobuf:"bytes,12,opt,name=bazel"`
+	// ToolImages maps a tool such as 'node', 'go', 'java' or 'python' to the image of the steps of the projects which pin the version of the tool such as 'node:{{version}}' where {{version}} is replaced with the version from the files of the project such as .nvmrc or go.mod
+	ToolImages map[string]string `json:"toolImages,omitempty" protobuf:"bytes,13,rep,name=toolImages"`
+	// StepMutators the names of the step mutators such as 'image-mirrors', 'env-injection' or 'security-hardening' to apply to the generated steps in addition to those enabled by default. A name prefixed with '-' disables a step mutator which is enabled by default
+	StepMutators []string `json:"stepMutators,omitempty" protobuf:"bytes,14,rep,name=stepMutators"`
+	// StepEnv the environment variables which the 'env-injection' step mutator adds to every step which does not define them
+	StepEnv []corev1.EnvVar `json:"stepEnv,omitempty" protobuf:"bytes,15,rep,name=stepEnv"`
+	// Plugins the OCI images of the plugins such as 'myorg/jx-taskgen-policy:1.0.0' which are run with docker after the 'jx-taskgen-*' plugins on the PATH to mutate the generated builds
+	Plugins []string `json:"plugins,omitempty" protobuf:"bytes,16,rep,name=plugins"`
+	// SonarQube the SonarQube server which analyses the code of the builds
+	SonarQube SonarQube `json:"sonarQube,omitempty" protobuf:"bytes,17,opt,name=sonarQube"`
+	// DependencyScan the scanning of the dependencies of every project for known vulnerabilities and license issues
+	DependencyScan DependencyScan `json:"dependencyScan,omitempty" protobuf:"bytes,18,opt,name=dependencyScan"`
+	// GitProxy the corporate proxy which the git repositories such as the build packs are cloned through
+	GitProxy GitProxy `json:"gitProxy,omitempty" protobuf:"bytes,19,opt,name=gitProxy"`
+	// PriorityClassName the PriorityClass of the build pods such as 'ci-low' so that the builds are preempted before the production workloads of shared clusters
+	PriorityClassName string `json:"priorityClassName,omitempty" protobuf:"bytes,20,opt,name=priorityClassName"`
+	// QuotaResources the resource requests and limits of the steps which do not specify the resources the ResourceQuota of the namespace of the builds requires, as pods without them are rejected. Defaults to the resources
+	QuotaResources *corev1.ResourceRequirements `json:"quotaResources,omitempty" protobuf:"bytes,21,opt,name=quotaResources"`
+	// Spot the scheduling of the build pods on spot or preemptible nodes which are cheaper but can be preempted at any time
+	Spot BuildSpot `json:"spot,omitempty" protobuf:"bytes,22,opt,name=spot"`
+	// Autoscaling the settings of the build pods which help the cluster autoscaler scale the build node pools up and down
+	Autoscaling BuildAutoscaling `json:"autoscaling,omitempty" protobuf:"bytes,23,opt,name=autoscaling"`
+	// Tracing the distributed tracing of the pipelines whose stages 'jx controller tracing' exports as OpenTelemetry spans
+	Tracing BuildTracing `json:"tracing,omitempty" protobuf:"bytes,24,opt,name=tracing"`
+	// VaultAddress the address of Vault which the steps of the builds read the secrets referenced by their environment variables from
+	VaultAddress string `json:"vaultAddress,omitempty" protobuf:"bytes,25,opt,name=vaultAddress"`
+}
+
+// BuildTracing the propagation of the W3C trace context to the steps of the builds and the OTLP endpoint the spans of
+// the pipelines are exported to
+type BuildTracing struct {
+	// Enabled propagates the trace context of the pipeline to the steps of the builds
+	Enabled bool `json:"enabled,omitempty" protobuf:"bytes,1,opt,name=enabled"`
+	// Endpoint the OTLP/HTTP endpoint such as 'http://jaeger-collector:4318' of Jaeger or of an OpenTelemetry collector exporting to Cloud Trace
+	Endpoint string `json:"endpoint,omitempty" protobuf:"bytes,2,opt,name=endpoint"`
+	// WrapSteps runs the command of each step with 'jx step trace' which exports a span of the command. The images of the steps must contain jx
+	WrapSteps bool `json:"wrapSteps,omitempty" protobuf:"bytes,3,opt,name=wrapSteps"`
+}
+
+// BuildAutoscaling the annotations and affinities of the build pods which are tuned for autoscaled build node pools
+type BuildAutoscaling struct {
+	// SafeToEvict the value of the 'cluster-autoscaler.kubernetes.io/safe-to-evict' annotation of the build pods. 'true' lets the autoscaler remove the nodes running builds whose local workspaces would otherwise block scale downs while 'false' protects running builds from being evicted
+	SafeToEvict *bool `json:"safeToEvict,omitempty" protobuf:"bytes,1,opt,name=safeToEvict"`
+	// PreferredNodeLabels the labels of the nodes of the autoscaled build node pool which the build pods prefer to run on
+	PreferredNodeLabels map[string]string `json:"preferredNodeLabels,omitempty" protobuf:"bytes,2,rep,name=preferredNodeLabels"`
+	// SpreadTopologyKey the node label such as 'topology.kubernetes.io/zone' which the build pods are spread across
+	SpreadTopologyKey string `json:"spreadTopologyKey,omitempty" protobuf:"bytes,3,opt,name=spreadTopologyKey"`
+	// PackBuilds prefers the nodes already running build pods so that the nodes of the build node pool empty out and are scaled down sooner
+	PackBuilds bool `json:"packBuilds,omitempty" protobuf:"bytes,4,opt,name=packBuilds"`
+}
+
+// BuildSpot the spot or preemptible nodes the build pods are scheduled on. 'jx controller preemption' retries the
+// build pods whose node was preempted from the step they were preempted in
+type BuildSpot struct {
+	// Enabled schedules the build pods on the spot nodes unless the builds are generated with --no-spot
+	Enabled bool `json:"enabled,omitempty" protobuf:"bytes,1,opt,name=enabled"`
+	// NodeSelector the labels of the spot nodes such as 'cloud.google.com/gke-preemptible: "true"'
+	NodeSelector map[string]string `json:"nodeSelector,omitempty" protobuf:"bytes,2,rep,name=nodeSelector"`
+	// Tolerations the tolerations of the taints of the spot nodes
+	Tolerations []corev1.Toleration `json:"tolerations,omitempty" protobuf:"bytes,3,rep,name=tolerations"`
+	// MaxRetries the number of times a preempted build pod is retried. Defaults to 2
+	MaxRetries int `json:"maxRetries,omitempty" protobuf:"bytes,4,opt,name=maxRetries"`
+}
+
+// GitProxy the corporate proxy which git connects to the git servers through when generating the builds
+type GitProxy struct {
+	// URL the URL of the proxy such as 'http://proxy.example.com:8080'. Git connects to the git servers directly if it is not set
+	URL string `json:"url,omitempty" protobuf:"bytes,1,opt,name=url"`
+	// AuthMethod the method git authenticates with the proxy: 'negotiate' for Kerberos, 'ntlm', 'basic', 'digest' or 'anyauth'. Defaults to 'anyauth'
+	AuthMethod string `json:"authMethod,omitempty" protobuf:"bytes,2,opt,name=authMethod"`
+	// CredentialsSecret the name of the Secret in the dev namespace containing the 'username' and 'password' of the proxy. Kerberos authenticates with the ticket of the user running jx when it is not set
+	CredentialsSecret string `json:"credentialsSecret,omitempty" protobuf:"bytes,3,opt,name=credentialsSecret"`
+}
+
+// DependencyScan the team policy of the scanning of the dependencies of the projects which the dependencyScan of the
+// jenkins-x.yml file of a project overrides
+type DependencyScan struct {
+	// Enabled enables the scanning of the dependencies of all projects
+	Enabled bool `json:"enabled,omitempty" protobuf:"bytes,1,opt,name=enabled"`
+	// Tool the tool which scans the dependencies such as 'owasp' or 'snyk'. Defaults to 'owasp'
+	Tool string `json:"tool,omitempty" protobuf:"bytes,2,opt,name=tool"`
+	// Threshold the lowest severity of the vulnerabilities which fail the builds such as 'high' or 'none' to only report them. Defaults to 'high'
+	Threshold string `json:"threshold,omitempty" protobuf:"bytes,3,opt,name=threshold"`
+	// Image the image of the tool
+	Image string `json:"image,omitempty" protobuf:"bytes,4,opt,name=image"`
+	// ReportBucketURL the URL of the bucket such as 's3://mybucket/reports' which the reports are uploaded to
+	ReportBucketURL string `json:"reportBucketUrl,omitempty" protobuf:"bytes,5,opt,name=reportBucketUrl"`
+	// TokenSecret the name of the Secret containing the token of the tool in the key 'token'
+	TokenSecret string `json:"tokenSecret,omitempty" protobuf:"bytes,6,opt,name=tokenSecret"`
+}
+
+// SonarQube the SonarQube server which the generated builds analyse the code of the projects with and whose quality
+// gates decorate the pull requests
+type SonarQube struct {
+	// ServerURL the URL of the SonarQube server such as 'https://sonar.example.com'. The builds are only analysed if it is set
+	ServerURL string `json:"serverUrl,omitempty" protobuf:"bytes,1,opt,name=serverUrl"`
+	// TokenSecret the name of the Secret in the namespace of the builds containing the token of the SonarQube server
+	TokenSecret string `json:"tokenSecret,omitempty" protobuf:"bytes,2,opt,name=tokenSecret"`
+	// TokenSecretKey the key of the token in the Secret. Defaults to 'token'
+	TokenSecretKey string `json:"tokenSecretKey,omitempty" protobuf:"bytes,3,opt,name=tokenSecretKey"`
+	// DisablePRDecoration disables commenting the quality gate of the analysis on pull requests
+	DisablePRDecoration bool `json:"disablePRDecoration,omitempty" protobuf:"bytes,4,opt,name=disablePRDecoration"`
+}
+
+// BazelRemote the remote cache and remote execution endpoints which the steps generated for Bazel projects use so
+// that the builds share their outputs rather than rebuilding every target from scratch
+type BazelRemote struct {
+	// RemoteCache the URL of the remote cache such as 'grpcs://cache.example.com' or 'https://storage.googleapis.com/mybucket'
+	RemoteCache string `json:"remoteCache,omitempty" protobuf:"bytes,1,opt,name=remoteCache"`
+	// RemoteExecutor the URL of the remote execution service such as 'grpcs://remotebuildexecution.googleapis.com'
+	RemoteExecutor string `json:"remoteExecutor,omitempty" protobuf:"bytes,2,opt,name=remoteExecutor"`
+	// InstanceName the instance name of the remote execution service
+	InstanceName string `json:"instanceName,omitempty" protobuf:"bytes,3,opt,name=instanceName"`
+	// GoogleCredentials uses the cloud identity of the build to authenticate with the remote cache and executor
+	GoogleCredentials bool `json:"googleCredentials,omitempty" protobuf:"bytes,4,opt,name=googleCredentials"`
+}
+
+// BuildIdentity the cloud identities which the service account of the builds is bound to by annotations
+type BuildIdentity struct {
+	// GCPServiceAccount the email of the Google service account bound via GKE Workload Identity
+	GCPServiceAccount string `json:"gcpServiceAccount,omitempty" protobuf:"bytes,1,opt,name=gcpServiceAccount"`
+	// AWSRoleARN the ARN of the AWS IAM role bound via IAM Roles for Service Accounts on EKS
+	AWSRoleARN string `json:"awsRoleArn,omitempty" protobuf:"bytes,2,opt,name=awsRoleArn"`
+	// AzureClientID the client ID of the Azure managed identity bound via Azure Workload Identity
+	AzureClientID string `json:"azureClientId,omitempty" protobuf:"bytes,3,opt,name=azureClientId"`
 }
 
 // QuickStartLocation
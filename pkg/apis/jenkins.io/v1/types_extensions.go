@@ -59,6 +59,20 @@ type ExtensionSpec struct {
 	Namespace   string               `json:"namespace,omitempty"  protobuf:"bytes,9,opt,name=namespace"`
 	UUID        string               `json:"uuid,omitempty"  protobuf:"bytes,10,opt,name=uuid"`
 	Children    []string             `json:"children,omitempty"  protobuf:"bytes,11,opt,name=children"`
+	// PipelineSteps the steps which the extension adds to the builds generated by 'jx step create build'
+	PipelineSteps []ExtensionPipelineStep `json:"pipelineSteps,omitempty"  protobuf:"bytes,12,opt,name=pipelineSteps"`
+}
+
+// ExtensionPipelineStep a step which an installed extension such as a Sonar App contributes to the generated builds
+// so that pipeline capabilities can be installed into a team
+type ExtensionPipelineStep struct {
+	// When whether the step is added before (pre) or after (post) the steps of the builds. Defaults to post
+	When ExtensionWhen `json:"when,omitempty"  protobuf:"bytes,1,opt,name=when"`
+	// Kinds the kinds of build such as release or pullRequest the step is added to. Defaults to all kinds
+	Kinds []string `json:"kinds,omitempty"  protobuf:"bytes,2,rep,name=kinds"`
+	// Step the step to add. The name defaults to the name of the extension and the image to the image of the first
+	// or last step of the build
+	Step corev1.Container `json:"step"  protobuf:"bytes,3,opt,name=step"`
 }
 
 // ExtensionWhen specifies when in the lifecycle an extension should execute. By default Post.
@@ -51,10 +51,11 @@ type PipelineActivitySpec struct {
 
 // PipelineActivityStep represents a step in a pipeline activity
 type PipelineActivityStep struct {
-	Kind    ActivityStepKindType `json:"kind,omitempty" protobuf:"bytes,1,opt,name=kind"`
-	Stage   *StageActivityStep   `json:"stage,omitempty" protobuf:"bytes,2,opt,name=stage"`
-	Promote *PromoteActivityStep `json:"promote,omitempty" protobuf:"bytes,3,opt,name=promote"`
-	Preview *PreviewActivityStep `json:"preview,omitempty" protobuf:"bytes,4,opt,name=preview"`
+	Kind     ActivityStepKindType  `json:"kind,omitempty" protobuf:"bytes,1,opt,name=kind"`
+	Stage    *StageActivityStep    `json:"stage,omitempty" protobuf:"bytes,2,opt,name=stage"`
+	Promote  *PromoteActivityStep  `json:"promote,omitempty" protobuf:"bytes,3,opt,name=promote"`
+	Preview  *PreviewActivityStep  `json:"preview,omitempty" protobuf:"bytes,4,opt,name=preview"`
+	Approval *ApprovalActivityStep `json:"approval,omitempty" protobuf:"bytes,5,opt,name=approval"`
 }
 
 // CoreActivityStep is a base step included in Stages of a pipeline or other kinds of step
@@ -92,6 +93,14 @@ type PromoteActivityStep struct {
 	ApplicationURL string                  `json:"applicationURL,omitempty" protobuf:"bytes,4,opt,name=environment"`
 }
 
+// ApprovalActivityStep is a manual approval gate which pauses the pipeline until it is approved or rejected
+type ApprovalActivityStep struct {
+	CoreActivityStep
+
+	Approvers  []string `json:"approvers,omitempty" protobuf:"bytes,1,opt,name=approvers"`
+	ApprovedBy string   `json:"approvedBy,omitempty" protobuf:"bytes,2,opt,name=approvedBy"`
+}
+
 // GitStatus the status of a git commit in terms of CI/CD
 type GitStatus struct {
 	URL    string `json:"url,omitempty" protobuf:"bytes,1,opt,name=url"`
@@ -141,6 +150,8 @@ const (
 	ActivityStepKindTypePreview ActivityStepKindType = "Preview"
 	// ActivityStepKindTypePromote a promote activity
 	ActivityStepKindTypePromote ActivityStepKindType = "Promote"
+	// ActivityStepKindTypeApproval a manual approval gate
+	ActivityStepKindTypeApproval ActivityStepKindType = "Approval"
 )
 
 // ActivityStatusType is the status of an activity; usually succeeded or failed/error on completion
@@ -0,0 +1,42 @@
+package v1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// +genclient
+// +genclient:noStatus
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+// +k8s:openapi-gen=true
+
+// SourceRepository represents a git repository which has CI/CD pipelines enabled
+type SourceRepository struct {
+	metav1.TypeMeta `json:",inline"`
+	// Standard object's metadata.
+	// More info: https://git.k8s.io/community/contributors/devel/api-conventions.md#metadata
+	// +optional
+	metav1.ObjectMeta `json:"metadata,omitempty" protobuf:"bytes,1,opt,name=metadata"`
+
+	Spec SourceRepositorySpec `json:"spec,omitempty" protobuf:"bytes,2,opt,name=spec"`
+}
+
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+// SourceRepositoryList is a list of SourceRepository resources
+type SourceRepositoryList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata"`
+
+	Items []SourceRepository `json:"items"`
+}
+
+// SourceRepositorySpec is the specification of a SourceRepository
+type SourceRepositorySpec struct {
+	Org           string   `json:"org,omitempty" protobuf:"bytes,1,opt,name=org"`
+	Repo          string   `json:"repo,omitempty" protobuf:"bytes,2,opt,name=repo"`
+	Provider      string   `json:"provider,omitempty" protobuf:"bytes,3,opt,name=provider"`
+	ProviderKind  string   `json:"providerKind,omitempty" protobuf:"bytes,4,opt,name=providerKind"`
+	URL           string   `json:"url,omitempty" protobuf:"bytes,5,opt,name=url"`
+	BuildPack     string   `json:"buildPack,omitempty" protobuf:"bytes,6,opt,name=buildPack"`
+	PipelineKinds []string `json:"pipelineKinds,omitempty" protobuf:"bytes,7,opt,name=pipelineKinds"`
+}
@@ -6,10 +6,33 @@ package v1
 
 import (
 	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
 	rbacv1 "k8s.io/api/rbac/v1"
 	runtime "k8s.io/apimachinery/pkg/runtime"
 )
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ApprovalActivityStep) DeepCopyInto(out *ApprovalActivityStep) {
+	*out = *in
+	in.CoreActivityStep.DeepCopyInto(&out.CoreActivityStep)
+	if in.Approvers != nil {
+		in, out := &in.Approvers, &out.Approvers
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ApprovalActivityStep.
+func (in *ApprovalActivityStep) DeepCopy() *ApprovalActivityStep {
+	if in == nil {
+		return nil
+	}
+	out := new(ApprovalActivityStep)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *Attachment) DeepCopyInto(out *Attachment) {
 	*out = *in
@@ -31,6 +54,181 @@ func (in *Attachment) DeepCopy() *Attachment {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *BazelRemote) DeepCopyInto(out *BazelRemote) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new BazelRemote.
+func (in *BazelRemote) DeepCopy() *BazelRemote {
+	if in == nil {
+		return nil
+	}
+	out := new(BazelRemote)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *BuildAutoscaling) DeepCopyInto(out *BuildAutoscaling) {
+	*out = *in
+	if in.SafeToEvict != nil {
+		in, out := &in.SafeToEvict, &out.SafeToEvict
+		*out = new(bool)
+		**out = **in
+	}
+	if in.PreferredNodeLabels != nil {
+		in, out := &in.PreferredNodeLabels, &out.PreferredNodeLabels
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new BuildAutoscaling.
+func (in *BuildAutoscaling) DeepCopy() *BuildAutoscaling {
+	if in == nil {
+		return nil
+	}
+	out := new(BuildAutoscaling)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *BuildDefaults) DeepCopyInto(out *BuildDefaults) {
+	*out = *in
+	if in.EnvAllowList != nil {
+		in, out := &in.EnvAllowList, &out.EnvAllowList
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.ImageMirrors != nil {
+		in, out := &in.ImageMirrors, &out.ImageMirrors
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.Resources != nil {
+		in, out := &in.Resources, &out.Resources
+		*out = new(corev1.ResourceRequirements)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.EgressEndpoints != nil {
+		in, out := &in.EgressEndpoints, &out.EgressEndpoints
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.ToolImages != nil {
+		in, out := &in.ToolImages, &out.ToolImages
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.StepMutators != nil {
+		in, out := &in.StepMutators, &out.StepMutators
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.StepEnv != nil {
+		in, out := &in.StepEnv, &out.StepEnv
+		*out = make([]corev1.EnvVar, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.Plugins != nil {
+		in, out := &in.Plugins, &out.Plugins
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.QuotaResources != nil {
+		in, out := &in.QuotaResources, &out.QuotaResources
+		*out = new(corev1.ResourceRequirements)
+		(*in).DeepCopyInto(*out)
+	}
+	in.Spot.DeepCopyInto(&out.Spot)
+	in.Autoscaling.DeepCopyInto(&out.Autoscaling)
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new BuildDefaults.
+func (in *BuildDefaults) DeepCopy() *BuildDefaults {
+	if in == nil {
+		return nil
+	}
+	out := new(BuildDefaults)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *BuildIdentity) DeepCopyInto(out *BuildIdentity) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new BuildIdentity.
+func (in *BuildIdentity) DeepCopy() *BuildIdentity {
+	if in == nil {
+		return nil
+	}
+	out := new(BuildIdentity)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *BuildSpot) DeepCopyInto(out *BuildSpot) {
+	*out = *in
+	if in.NodeSelector != nil {
+		in, out := &in.NodeSelector, &out.NodeSelector
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.Tolerations != nil {
+		in, out := &in.Tolerations, &out.Tolerations
+		*out = make([]corev1.Toleration, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new BuildSpot.
+func (in *BuildSpot) DeepCopy() *BuildSpot {
+	if in == nil {
+		return nil
+	}
+	out := new(BuildSpot)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *BuildTracing) DeepCopyInto(out *BuildTracing) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new BuildTracing.
+func (in *BuildTracing) DeepCopy() *BuildTracing {
+	if in == nil {
+		return nil
+	}
+	out := new(BuildTracing)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *CommitSummary) DeepCopyInto(out *CommitSummary) {
 	*out = *in
@@ -184,6 +382,22 @@ func (in *CoreActivityStep) DeepCopy() *CoreActivityStep {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *DependencyScan) DeepCopyInto(out *DependencyScan) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DependencyScan.
+func (in *DependencyScan) DeepCopy() *DependencyScan {
+	if in == nil {
+		return nil
+	}
+	out := new(DependencyScan)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *Environment) DeepCopyInto(out *Environment) {
 	*out = *in
@@ -708,6 +922,28 @@ func (in *ExtensionParameterValue) DeepCopy() *ExtensionParameterValue {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ExtensionPipelineStep) DeepCopyInto(out *ExtensionPipelineStep) {
+	*out = *in
+	if in.Kinds != nil {
+		in, out := &in.Kinds, &out.Kinds
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	in.Step.DeepCopyInto(&out.Step)
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ExtensionPipelineStep.
+func (in *ExtensionPipelineStep) DeepCopy() *ExtensionPipelineStep {
+	if in == nil {
+		return nil
+	}
+	out := new(ExtensionPipelineStep)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *ExtensionRepositoryLockList) DeepCopyInto(out *ExtensionRepositoryLockList) {
 	*out = *in
@@ -749,6 +985,13 @@ func (in *ExtensionSpec) DeepCopyInto(out *ExtensionSpec) {
 		*out = make([]string, len(*in))
 		copy(*out, *in)
 	}
+	if in.PipelineSteps != nil {
+		in, out := &in.PipelineSteps, &out.PipelineSteps
+		*out = make([]ExtensionPipelineStep, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
 	return
 }
 
@@ -798,6 +1041,22 @@ func (in *Fact) DeepCopy() *Fact {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *GitProxy) DeepCopyInto(out *GitProxy) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new GitProxy.
+func (in *GitProxy) DeepCopy() *GitProxy {
+	if in == nil {
+		return nil
+	}
+	out := new(GitProxy)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *GitService) DeepCopyInto(out *GitService) {
 	*out = *in
@@ -1137,6 +1396,11 @@ func (in *PipelineActivityStep) DeepCopyInto(out *PipelineActivityStep) {
 		*out = new(PreviewActivityStep)
 		(*in).DeepCopyInto(*out)
 	}
+	if in.Approval != nil {
+		in, out := &in.Approval, &out.Approval
+		*out = new(ApprovalActivityStep)
+		(*in).DeepCopyInto(*out)
+	}
 	return
 }
 
@@ -1422,6 +1686,103 @@ func (in *ResourceReference) DeepCopy() *ResourceReference {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SonarQube) DeepCopyInto(out *SonarQube) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SonarQube.
+func (in *SonarQube) DeepCopy() *SonarQube {
+	if in == nil {
+		return nil
+	}
+	out := new(SonarQube)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SourceRepository) DeepCopyInto(out *SourceRepository) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SourceRepository.
+func (in *SourceRepository) DeepCopy() *SourceRepository {
+	if in == nil {
+		return nil
+	}
+	out := new(SourceRepository)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *SourceRepository) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SourceRepositoryList) DeepCopyInto(out *SourceRepositoryList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	out.ListMeta = in.ListMeta
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]SourceRepository, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SourceRepositoryList.
+func (in *SourceRepositoryList) DeepCopy() *SourceRepositoryList {
+	if in == nil {
+		return nil
+	}
+	out := new(SourceRepositoryList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *SourceRepositoryList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SourceRepositorySpec) DeepCopyInto(out *SourceRepositorySpec) {
+	*out = *in
+	if in.PipelineKinds != nil {
+		in, out := &in.PipelineKinds, &out.PipelineKinds
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SourceRepositorySpec.
+func (in *SourceRepositorySpec) DeepCopy() *SourceRepositorySpec {
+	if in == nil {
+		return nil
+	}
+	out := new(SourceRepositorySpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *StageActivityStep) DeepCopyInto(out *StageActivityStep) {
 	*out = *in
@@ -1545,6 +1906,7 @@ func (in *TeamSettings) DeepCopyInto(out *TeamSettings) {
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
 	}
+	in.BuildDefaults.DeepCopyInto(&out.BuildDefaults)
 	return
 }
 
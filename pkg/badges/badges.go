@@ -0,0 +1,157 @@
+package badges
+
+import (
+	"bytes"
+	"fmt"
+	"html"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/jenkins-x/jx/pkg/apis/jenkins.io/v1"
+	"github.com/jenkins-x/jx/pkg/client/clientset/versioned"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+const (
+	// DefaultBranch the branch of the badge of a repository if the path does not specify one
+	DefaultBranch = "master"
+
+	// badgeLabel the label on the left hand side of the badges
+	badgeLabel = "build"
+	// charWidth the approximate width in pixels of a character of the 11px font of the badges
+	charWidth = 7
+	// padding the horizontal padding in pixels of each side of a badge
+	padding = 10
+
+	colorPassing = "#4c1"
+	colorFailing = "#e05d44"
+	colorRunning = "#dfb317"
+	colorUnknown = "#9f9f9f"
+)
+
+// Handler serves the SVG status badges of the pipelines of the PipelineActivity resources in a namespace at paths
+// of the form /owner/repository.svg or /owner/repository/branch.svg
+type Handler struct {
+	jxClient versioned.Interface
+	ns       string
+}
+
+// NewHandler creates a handler of the badges of the pipelines in the given namespace
+func NewHandler(jxClient versioned.Interface, ns string) *Handler {
+	return &Handler{
+		jxClient: jxClient,
+		ns:       ns,
+	}
+}
+
+// ServeHTTP serves the badge of the latest build of the pipeline of the path
+func (h *Handler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet && r.Method != http.MethodHead {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	pipeline, ok := PipelineForPath(r.URL.Path)
+	if !ok {
+		http.Error(w, "expected a path of the form /owner/repository.svg or /owner/repository/branch.svg", http.StatusNotFound)
+		return
+	}
+	activity, err := h.latestActivity(pipeline)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	message, color := Status(activity)
+	w.Header().Set("Content-Type", "image/svg+xml")
+	// badges must not be cached by the proxies of the git providers so that READMEs show the current status
+	w.Header().Set("Cache-Control", "no-cache, no-store, must-revalidate")
+	w.Write(Render(badgeLabel, message, color))
+}
+
+// PipelineForPath returns the name of the pipeline such as 'owner/repository/branch' of the path of a badge
+func PipelineForPath(path string) (string, bool) {
+	path = strings.Trim(path, "/")
+	if !strings.HasSuffix(path, ".svg") {
+		return "", false
+	}
+	paths := strings.Split(strings.TrimSuffix(path, ".svg"), "/")
+	for _, p := range paths {
+		if p == "" {
+			return "", false
+		}
+	}
+	switch len(paths) {
+	case 2:
+		return strings.Join(append(paths, DefaultBranch), "/"), true
+	case 3:
+		return strings.Join(paths, "/"), true
+	default:
+		return "", false
+	}
+}
+
+// latestActivity returns the activity of the pipeline with the highest build number or nil if there is none
+func (h *Handler) latestActivity(pipeline string) (*v1.PipelineActivity, error) {
+	list, err := h.jxClient.JenkinsV1().PipelineActivities(h.ns).List(metav1.ListOptions{})
+	if err != nil {
+		return nil, err
+	}
+	var answer *v1.PipelineActivity
+	latest := -1
+	for i := range list.Items {
+		activity := &list.Items[i]
+		if !strings.EqualFold(activity.Spec.Pipeline, pipeline) {
+			continue
+		}
+		build, err := strconv.Atoi(activity.Spec.Build)
+		if err != nil {
+			continue
+		}
+		if build > latest {
+			latest = build
+			answer = activity
+		}
+	}
+	return answer, nil
+}
+
+// Status returns the message and color of the badge of the activity
+func Status(activity *v1.PipelineActivity) (string, string) {
+	if activity == nil {
+		return "unknown", colorUnknown
+	}
+	switch activity.Spec.Status {
+	case v1.ActivityStatusTypeSucceeded:
+		return "passing", colorPassing
+	case v1.ActivityStatusTypeFailed, v1.ActivityStatusTypeError:
+		return "failing", colorFailing
+	case v1.ActivityStatusTypeAborted:
+		return "aborted", colorUnknown
+	case v1.ActivityStatusTypeRunning, v1.ActivityStatusTypePending, v1.ActivityStatusTypeWaitingForApproval:
+		return "running", colorRunning
+	default:
+		return "unknown", colorUnknown
+	}
+}
+
+// Render renders a flat SVG badge with the label on a grey background on the left and the message on the given color
+// on the right
+func Render(label string, message string, color string) []byte {
+	labelWidth := len(label)*charWidth + padding
+	messageWidth := len(message)*charWidth + padding
+	width := labelWidth + messageWidth
+	label = html.EscapeString(label)
+	message = html.EscapeString(message)
+
+	var buf bytes.Buffer
+	fmt.Fprintf(&buf, `<svg xmlns="http://www.w3.org/2000/svg" width="%d" height="20">`, width)
+	fmt.Fprintf(&buf, `<title>%s: %s</title>`, label, message)
+	fmt.Fprintf(&buf, `<rect width="%d" height="20" rx="3" fill="#555"/>`, width)
+	fmt.Fprintf(&buf, `<rect x="%d" width="%d" height="20" rx="3" fill="%s"/>`, labelWidth, messageWidth, color)
+	fmt.Fprintf(&buf, `<path fill="%s" d="M%d 0h4v20h-4z"/>`, color, labelWidth)
+	buf.WriteString(`<g fill="#fff" text-anchor="middle" font-family="DejaVu Sans,Verdana,Geneva,sans-serif" font-size="11">`)
+	fmt.Fprintf(&buf, `<text x="%d" y="14">%s</text>`, labelWidth/2, label)
+	fmt.Fprintf(&buf, `<text x="%d" y="14">%s</text>`, labelWidth+messageWidth/2, message)
+	buf.WriteString(`</g></svg>`)
+	return buf.Bytes()
+}
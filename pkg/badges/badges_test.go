@@ -0,0 +1,70 @@
+package badges_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/jenkins-x/jx/pkg/apis/jenkins.io/v1"
+	"github.com/jenkins-x/jx/pkg/badges"
+	"github.com/jenkins-x/jx/pkg/client/clientset/versioned/fake"
+	"github.com/stretchr/testify/assert"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestPipelineForPath(t *testing.T) {
+	t.Parallel()
+	tests := map[string]string{
+		"/myorg/myapp.svg":         "myorg/myapp/master",
+		"/myorg/myapp/develop.svg": "myorg/myapp/develop",
+		"/myorg/myapp":             "",
+		"/myorg.svg":               "",
+		"/myorg//myapp.svg":        "",
+		"/a/b/c/d.svg":             "",
+	}
+	for path, expected := range tests {
+		actual, ok := badges.PipelineForPath(path)
+		assert.Equal(t, expected != "", ok, "path %s", path)
+		assert.Equal(t, expected, actual, "path %s", path)
+	}
+}
+
+func TestHandler(t *testing.T) {
+	t.Parallel()
+	activity := func(name string, pipeline string, build string, status v1.ActivityStatusType) *v1.PipelineActivity {
+		return &v1.PipelineActivity{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      name,
+				Namespace: "jx",
+			},
+			Spec: v1.PipelineActivitySpec{
+				Pipeline: pipeline,
+				Build:    build,
+				Status:   status,
+			},
+		}
+	}
+	jxClient := fake.NewSimpleClientset(
+		activity("myorg-myapp-master-9", "myorg/myapp/master", "9", v1.ActivityStatusTypeSucceeded),
+		activity("myorg-myapp-master-10", "myorg/myapp/master", "10", v1.ActivityStatusTypeFailed),
+		activity("myorg-myapp-develop-1", "myorg/myapp/develop", "1", v1.ActivityStatusTypeRunning),
+	)
+	handler := badges.NewHandler(jxClient, "jx")
+
+	tests := map[string]string{
+		"/myorg/myapp.svg":         "failing",
+		"/myorg/myapp/develop.svg": "running",
+		"/myorg/other.svg":         "unknown",
+	}
+	for path, expected := range tests {
+		w := httptest.NewRecorder()
+		handler.ServeHTTP(w, httptest.NewRequest(http.MethodGet, path, nil))
+		assert.Equal(t, http.StatusOK, w.Code, "path %s", path)
+		assert.Equal(t, "image/svg+xml", w.Header().Get("Content-Type"), "path %s", path)
+		assert.Contains(t, w.Body.String(), "<title>build: "+expected+"</title>", "path %s", path)
+	}
+
+	w := httptest.NewRecorder()
+	handler.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/myorg", nil))
+	assert.Equal(t, http.StatusNotFound, w.Code)
+}
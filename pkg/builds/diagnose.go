@@ -0,0 +1,140 @@
+package builds
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+)
+
+// ProbableCause a probable cause of a failed build along with a suggested fix
+type ProbableCause struct {
+	// Score how likely this is the cause of the failure; higher is more likely
+	Score int
+	// Container the name of the container or step which failed if known
+	Container string
+	// Cause a description of the probable cause
+	Cause string
+	// Fix a suggestion of how to fix the problem
+	Fix string
+}
+
+// DiagnoseBuildPod analyses the container statuses of a build pod and its events returning a list of the probable
+// causes of a build failure sorted with the most probable first
+func DiagnoseBuildPod(pod *corev1.Pod, events []corev1.Event) []*ProbableCause {
+	answer := []*ProbableCause{}
+	statuses := append([]corev1.ContainerStatus{}, pod.Status.InitContainerStatuses...)
+	statuses = append(statuses, pod.Status.ContainerStatuses...)
+	for _, s := range statuses {
+		if s.State.Waiting != nil {
+			cause := diagnoseWaitingContainer(s.Name, s.State.Waiting)
+			if cause != nil {
+				answer = append(answer, cause)
+			}
+		}
+		if s.State.Terminated != nil && s.State.Terminated.ExitCode != 0 {
+			answer = append(answer, diagnoseTerminatedContainer(s.Name, s.State.Terminated))
+		}
+	}
+	for _, event := range events {
+		if event.Type != corev1.EventTypeWarning {
+			continue
+		}
+		cause := diagnoseEvent(&event)
+		if cause != nil {
+			answer = append(answer, cause)
+		}
+	}
+	sort.SliceStable(answer, func(i, j int) bool {
+		return answer[i].Score > answer[j].Score
+	})
+	return answer
+}
+
+func diagnoseWaitingContainer(name string, state *corev1.ContainerStateWaiting) *ProbableCause {
+	switch state.Reason {
+	case "ErrImagePull", "ImagePullBackOff", "InvalidImageName":
+		return &ProbableCause{
+			Score:     90,
+			Container: name,
+			Cause:     fmt.Sprintf("the image could not be pulled: %s", state.Message),
+			Fix:       "check the image name and tag exist and that an image pull secret is configured for private registries",
+		}
+	case "CreateContainerConfigError":
+		return &ProbableCause{
+			Score:     85,
+			Container: name,
+			Cause:     fmt.Sprintf("the container could not be configured: %s", state.Message),
+			Fix:       "check the Secrets and ConfigMaps referenced by the environment variables of the step exist",
+		}
+	case "CrashLoopBackOff":
+		return &ProbableCause{
+			Score:     60,
+			Container: name,
+			Cause:     "the container keeps crashing on startup",
+			Fix:       "view the logs of the step to find out why it is failing",
+		}
+	}
+	return nil
+}
+
+func diagnoseTerminatedContainer(name string, state *corev1.ContainerStateTerminated) *ProbableCause {
+	cause := &ProbableCause{
+		Score:     50,
+		Container: name,
+		Cause:     fmt.Sprintf("the step failed with exit code %d", state.ExitCode),
+		Fix:       "view the logs of the step to find out why the command failed",
+	}
+	switch {
+	case state.Reason == "OOMKilled" || state.ExitCode == 137:
+		cause.Score = 80
+		cause.Cause = "the step ran out of memory and was killed"
+		cause.Fix = "increase the memory limit of the step or of the pod template"
+	case state.ExitCode == 126 || state.ExitCode == 127:
+		cause.Score = 75
+		cause.Cause = fmt.Sprintf("the command of the step could not be found or executed (exit code %d)", state.ExitCode)
+		cause.Fix = "check the command exists in the image of the step or use a different builder image"
+	}
+	if state.Message != "" {
+		cause.Cause += ": " + state.Message
+	}
+	return cause
+}
+
+func diagnoseEvent(event *corev1.Event) *ProbableCause {
+	message := event.Message
+	switch {
+	case event.Reason == "FailedMount" && strings.Contains(message, "secret") && strings.Contains(message, "not found"):
+		return &ProbableCause{
+			Score: 95,
+			Cause: fmt.Sprintf("a Secret used by a volume is missing: %s", message),
+			Fix:   "create the missing Secret or remove the volume from the pod template",
+		}
+	case event.Reason == "FailedMount" && strings.Contains(message, "configmap") && strings.Contains(message, "not found"):
+		return &ProbableCause{
+			Score: 95,
+			Cause: fmt.Sprintf("a ConfigMap used by a volume is missing: %s", message),
+			Fix:   "create the missing ConfigMap or remove the volume from the pod template",
+		}
+	case event.Reason == "FailedMount" || event.Reason == "FailedAttachVolume":
+		return &ProbableCause{
+			Score: 70,
+			Cause: fmt.Sprintf("a volume could not be mounted: %s", message),
+			Fix:   "check the PersistentVolumeClaims and Secrets used by the build exist and are bound",
+		}
+	case event.Reason == "FailedScheduling":
+		return &ProbableCause{
+			Score: 65,
+			Cause: fmt.Sprintf("the build pod could not be scheduled: %s", message),
+			Fix:   "reduce the resource requests of the build or add more nodes to the cluster",
+		}
+	case event.Reason == "Evicted":
+		return &ProbableCause{
+			Score: 65,
+			Cause: fmt.Sprintf("the build pod was evicted: %s", message),
+			Fix:   "increase the resource requests of the build so it is not scheduled on an overloaded node",
+		}
+	}
+	return nil
+}
@@ -0,0 +1,50 @@
+package builds_test
+
+import (
+	"testing"
+
+	"github.com/jenkins-x/jx/pkg/builds"
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+)
+
+func TestDiagnoseBuildPod(t *testing.T) {
+	t.Parallel()
+	pod := &corev1.Pod{
+		Status: corev1.PodStatus{
+			InitContainerStatuses: []corev1.ContainerStatus{
+				{
+					Name: "build-step-credential-initializer",
+					State: corev1.ContainerState{
+						Terminated: &corev1.ContainerStateTerminated{ExitCode: 0},
+					},
+				},
+				{
+					Name: "build-step-mvn-deploy",
+					State: corev1.ContainerState{
+						Terminated: &corev1.ContainerStateTerminated{ExitCode: 127},
+					},
+				},
+			},
+		},
+	}
+	events := []corev1.Event{
+		{
+			Type:    corev1.EventTypeNormal,
+			Reason:  "Pulled",
+			Message: "Successfully pulled image",
+		},
+		{
+			Type:    corev1.EventTypeWarning,
+			Reason:  "FailedMount",
+			Message: `MountVolume.SetUp failed for volume "volume-1" : secret "jenkins-docker-cfg" not found`,
+		},
+	}
+
+	causes := builds.DiagnoseBuildPod(pod, events)
+	if assert.Equal(t, 2, len(causes)) {
+		assert.Contains(t, causes[0].Cause, "jenkins-docker-cfg")
+		assert.Equal(t, "build-step-mvn-deploy", causes[1].Container)
+		assert.Contains(t, causes[1].Cause, "exit code 127")
+	}
+}
@@ -0,0 +1,124 @@
+package builds
+
+import (
+	"strconv"
+	"strings"
+
+	"github.com/jenkins-x/jx/pkg/kube"
+	"github.com/jenkins-x/jx/pkg/util"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+const (
+	// StepContainerPrefix the prefix of the names of the init containers running the steps of a Knative build
+	StepContainerPrefix = "build-step-"
+
+	maxPodNameLength = 63
+)
+
+// PreemptionReasons the reasons of the failed pods whose node was preempted, shut down or lost
+var PreemptionReasons = []string{"Preempting", "Shutdown", "NodeShutdown", "Terminated", "NodeLost"}
+
+// WorkspaceContainers the init containers of a Knative build which prepare the workspace of the steps so that they
+// are run again when a preempted build pod is retried
+var WorkspaceContainers = []string{StepContainerPrefix + "credential-initializer", StepContainerPrefix + "git-source"}
+
+// IsPreempted returns true if the build pod failed because its node was preempted
+func IsPreempted(pod *corev1.Pod) bool {
+	return pod.Status.Phase == corev1.PodFailed && util.StringArrayIndex(PreemptionReasons, pod.Status.Reason) >= 0
+}
+
+// PreemptionRetries returns the number of times the build pod has already been retried after being preempted
+func PreemptionRetries(pod *corev1.Pod) int {
+	n, err := strconv.Atoi(pod.Annotations[kube.AnnotationPreemptionRetries])
+	if err != nil {
+		return 0
+	}
+	return n
+}
+
+// MaxPreemptionRetries returns the number of times the build pod may be retried after being preempted. Returns 0 if
+// the pod is not retried
+func MaxPreemptionRetries(pod *corev1.Pod) int {
+	n, err := strconv.Atoi(pod.Annotations[kube.AnnotationMaxPreemptionRetries])
+	if err != nil || n < 0 {
+		return 0
+	}
+	return n
+}
+
+// RetryPod returns a copy of the preempted build pod which resumes the build from the step it was preempted in. The
+// steps which completed before it are skipped while the workspace containers and the given workspace steps are run
+// again as the workspace was lost along with the node
+func RetryPod(pod *corev1.Pod, workspaceSteps []string) *corev1.Pod {
+	completed := completedContainers(pod)
+	keep := append([]string{}, WorkspaceContainers...)
+	for _, step := range workspaceSteps {
+		keep = append(keep, StepContainerPrefix+step)
+	}
+	retries := PreemptionRetries(pod) + 1
+	answer := &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:            retryPodName(pod.Name, retries),
+			Namespace:       pod.Namespace,
+			Labels:          map[string]string{},
+			Annotations:     map[string]string{},
+			OwnerReferences: pod.OwnerReferences,
+		},
+		Spec: *pod.Spec.DeepCopy(),
+	}
+	for k, v := range pod.Labels {
+		answer.Labels[k] = v
+	}
+	for k, v := range pod.Annotations {
+		answer.Annotations[k] = v
+	}
+	delete(answer.Annotations, kube.AnnotationRetriedBy)
+	answer.Annotations[kube.AnnotationPreemptionRetries] = strconv.Itoa(retries)
+	answer.Spec.NodeName = ""
+
+	initContainers := []corev1.Container{}
+	checkpoint := false
+	for _, c := range answer.Spec.InitContainers {
+		if !completed[c.Name] {
+			checkpoint = true
+		} else if !checkpoint && util.StringArrayIndex(keep, c.Name) < 0 {
+			continue
+		}
+		initContainers = append(initContainers, c)
+	}
+	answer.Spec.InitContainers = initContainers
+	return answer
+}
+
+// CheckpointStep returns the name of the first step of the build pod which did not complete which a retried pod
+// resumes from. Returns an empty string if every step completed
+func CheckpointStep(pod *corev1.Pod) string {
+	completed := completedContainers(pod)
+	for _, c := range pod.Spec.InitContainers {
+		if !completed[c.Name] {
+			return strings.TrimPrefix(c.Name, StepContainerPrefix)
+		}
+	}
+	return ""
+}
+
+func completedContainers(pod *corev1.Pod) map[string]bool {
+	answer := map[string]bool{}
+	for _, s := range pod.Status.InitContainerStatuses {
+		if s.State.Terminated != nil && s.State.Terminated.ExitCode == 0 {
+			answer[s.Name] = true
+		}
+	}
+	return answer
+}
+
+func retryPodName(name string, retries int) string {
+	suffix := "-retry" + strconv.Itoa(retries)
+	name = strings.TrimSuffix(name, "-retry"+strconv.Itoa(retries-1))
+	if len(name)+len(suffix) > maxPodNameLength {
+		name = name[:maxPodNameLength-len(suffix)]
+	}
+	return name + suffix
+}
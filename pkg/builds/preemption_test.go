@@ -0,0 +1,81 @@
+package builds_test
+
+import (
+	"testing"
+
+	"github.com/jenkins-x/jx/pkg/builds"
+	"github.com/jenkins-x/jx/pkg/kube"
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestRetryPreemptedPod(t *testing.T) {
+	t.Parallel()
+	completed := corev1.ContainerState{
+		Terminated: &corev1.ContainerStateTerminated{ExitCode: 0},
+	}
+	pod := &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "myapp-1-pod-abcdef",
+			Namespace: "jx",
+			Labels: map[string]string{
+				builds.LabelBuildName: "myapp-1",
+			},
+			Annotations: map[string]string{
+				kube.AnnotationMaxPreemptionRetries: "2",
+			},
+		},
+		Spec: corev1.PodSpec{
+			NodeName: "spot-node-1",
+			InitContainers: []corev1.Container{
+				{Name: "build-step-credential-initializer"},
+				{Name: "build-step-git-source"},
+				{Name: "build-step-generate-dockerfile"},
+				{Name: "build-step-build"},
+				{Name: "build-step-test"},
+				{Name: "build-step-deploy"},
+			},
+		},
+		Status: corev1.PodStatus{
+			Phase:  corev1.PodFailed,
+			Reason: "Terminated",
+			InitContainerStatuses: []corev1.ContainerStatus{
+				{Name: "build-step-credential-initializer", State: completed},
+				{Name: "build-step-git-source", State: completed},
+				{Name: "build-step-generate-dockerfile", State: completed},
+				{Name: "build-step-build", State: completed},
+				{
+					Name: "build-step-test",
+					State: corev1.ContainerState{
+						Terminated: &corev1.ContainerStateTerminated{ExitCode: 137},
+					},
+				},
+			},
+		},
+	}
+
+	assert.True(t, builds.IsPreempted(pod))
+	assert.Equal(t, 0, builds.PreemptionRetries(pod))
+	assert.Equal(t, 2, builds.MaxPreemptionRetries(pod))
+	assert.Equal(t, "test", builds.CheckpointStep(pod))
+
+	retry := builds.RetryPod(pod, []string{"generate-dockerfile"})
+	assert.Equal(t, "myapp-1-pod-abcdef-retry1", retry.Name)
+	assert.Equal(t, "jx", retry.Namespace)
+	assert.Equal(t, "myapp-1", retry.Labels[builds.LabelBuildName])
+	assert.Equal(t, "1", retry.Annotations[kube.AnnotationPreemptionRetries])
+	assert.Empty(t, retry.Spec.NodeName)
+	names := []string{}
+	for _, c := range retry.Spec.InitContainers {
+		names = append(names, c.Name)
+	}
+	assert.Equal(t, []string{"build-step-credential-initializer", "build-step-git-source", "build-step-generate-dockerfile", "build-step-test", "build-step-deploy"}, names)
+	assert.Len(t, pod.Spec.InitContainers, 6, "the preempted pod should not be modified")
+
+	retry.Status = pod.Status
+	assert.Equal(t, "myapp-1-pod-abcdef-retry2", builds.RetryPod(retry, nil).Name)
+
+	pod.Status.Reason = "Error"
+	assert.False(t, builds.IsPreempted(pod))
+}
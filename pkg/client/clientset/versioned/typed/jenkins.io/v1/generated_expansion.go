@@ -16,6 +16,8 @@ type PipelineActivityExpansion interface{}
 
 type ReleaseExpansion interface{}
 
+type SourceRepositoryExpansion interface{}
+
 type TeamExpansion interface{}
 
 type UserExpansion interface{}
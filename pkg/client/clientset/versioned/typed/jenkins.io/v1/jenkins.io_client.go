@@ -18,6 +18,7 @@ type JenkinsV1Interface interface {
 	GitServicesGetter
 	PipelineActivitiesGetter
 	ReleasesGetter
+	SourceRepositoriesGetter
 	TeamsGetter
 	UsersGetter
 	WorkflowsGetter
@@ -56,6 +57,10 @@ func (c *JenkinsV1Client) Releases(namespace string) ReleaseInterface {
 	return newReleases(c, namespace)
 }
 
+func (c *JenkinsV1Client) SourceRepositories(namespace string) SourceRepositoryInterface {
+	return newSourceRepositories(c, namespace)
+}
+
 func (c *JenkinsV1Client) Teams(namespace string) TeamInterface {
 	return newTeams(c, namespace)
 }
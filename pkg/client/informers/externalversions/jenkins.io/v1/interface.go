@@ -22,6 +22,8 @@ type Interface interface {
 	PipelineActivities() PipelineActivityInformer
 	// Releases returns a ReleaseInformer.
 	Releases() ReleaseInformer
+	// SourceRepositories returns a SourceRepositoryInformer.
+	SourceRepositories() SourceRepositoryInformer
 	// Teams returns a TeamInformer.
 	Teams() TeamInformer
 	// Users returns a UserInformer.
@@ -76,6 +78,11 @@ func (v *version) Releases() ReleaseInformer {
 	return &releaseInformer{factory: v.factory, namespace: v.namespace, tweakListOptions: v.tweakListOptions}
 }
 
+// SourceRepositories returns a SourceRepositoryInformer.
+func (v *version) SourceRepositories() SourceRepositoryInformer {
+	return &sourceRepositoryInformer{factory: v.factory, namespace: v.namespace, tweakListOptions: v.tweakListOptions}
+}
+
 // Teams returns a TeamInformer.
 func (v *version) Teams() TeamInformer {
 	return &teamInformer{factory: v.factory, namespace: v.namespace, tweakListOptions: v.tweakListOptions}
@@ -0,0 +1,153 @@
+package buckets
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io/ioutil"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"cloud.google.com/go/storage"
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/awserr"
+	"github.com/aws/aws-sdk-go/service/s3"
+	"github.com/jenkins-x/jx/pkg/cloud/amazon"
+	"github.com/pkg/errors"
+)
+
+// ErrNotFound the cause of the error returned when reading a file which does not exist in the bucket
+var ErrNotFound = errors.New("file not found")
+
+// IsNotFound returns true if the error is caused by reading a file which does not exist in the bucket
+func IsNotFound(err error) bool {
+	return errors.Cause(err) == ErrNotFound
+}
+
+// WriteFile writes the data to the file with the given path in the bucket of the URL such as 's3://mybucket/logs',
+// 'gs://mybucket/logs' or 'file:///var/logs' and returns the URL of the file
+func WriteFile(bucketURL string, path string, data []byte, contentType string) (string, error) {
+	u, err := url.Parse(bucketURL)
+	if err != nil {
+		return "", errors.Wrapf(err, "failed to parse the bucket URL %s", bucketURL)
+	}
+	key := strings.TrimPrefix(strings.TrimSuffix(u.Path, "/")+"/"+strings.TrimPrefix(path, "/"), "/")
+	fileURL := fmt.Sprintf("%s://%s/%s", u.Scheme, u.Host, key)
+	switch u.Scheme {
+	case "s3":
+		err = writeS3(u.Host, key, data, contentType)
+	case "gs":
+		err = writeGCS(u.Host, key, data, contentType)
+	case "file":
+		fileName := filepath.Join(u.Host, filepath.FromSlash("/"+key))
+		err = os.MkdirAll(filepath.Dir(fileName), 0755)
+		if err == nil {
+			err = ioutil.WriteFile(fileName, data, 0644)
+		}
+		fileURL = "file://" + filepath.ToSlash(fileName)
+	default:
+		return "", fmt.Errorf("Unsupported bucket URL %s. Supported schemes are s3, gs and file", bucketURL)
+	}
+	if err != nil {
+		return "", errors.Wrapf(err, "failed to write %s", fileURL)
+	}
+	return fileURL, nil
+}
+
+// ReadFile reads the file with the given URL such as 's3://mybucket/logs/1.log', 'gs://mybucket/logs/1.log' or
+// 'file:///var/logs/1.log'
+func ReadFile(fileURL string) ([]byte, error) {
+	u, err := url.Parse(fileURL)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to parse the URL %s", fileURL)
+	}
+	key := strings.TrimPrefix(u.Path, "/")
+	var data []byte
+	switch u.Scheme {
+	case "s3":
+		data, err = readS3(u.Host, key)
+	case "gs":
+		data, err = readGCS(u.Host, key)
+	case "file":
+		data, err = ioutil.ReadFile(filepath.Join(u.Host, filepath.FromSlash(u.Path)))
+		if os.IsNotExist(err) {
+			err = ErrNotFound
+		}
+	default:
+		return nil, fmt.Errorf("Unsupported bucket URL %s. Supported schemes are s3, gs and file", fileURL)
+	}
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to read %s", fileURL)
+	}
+	return data, nil
+}
+
+func writeS3(bucket string, key string, data []byte, contentType string) error {
+	sess, err := amazon.NewAwsSessionWithoutOptions()
+	if err != nil {
+		return err
+	}
+	_, err = s3.New(sess).PutObject(&s3.PutObjectInput{
+		Bucket:      aws.String(bucket),
+		Key:         aws.String(key),
+		Body:        bytes.NewReader(data),
+		ContentType: aws.String(contentType),
+	})
+	return err
+}
+
+func writeGCS(bucket string, key string, data []byte, contentType string) error {
+	ctx := context.Background()
+	client, err := storage.NewClient(ctx)
+	if err != nil {
+		return err
+	}
+	defer client.Close()
+	writer := client.Bucket(bucket).Object(key).NewWriter(ctx)
+	writer.ContentType = contentType
+	_, err = writer.Write(data)
+	if err != nil {
+		writer.CloseWithError(err)
+		return err
+	}
+	return writer.Close()
+}
+
+func readS3(bucket string, key string) ([]byte, error) {
+	sess, err := amazon.NewAwsSessionWithoutOptions()
+	if err != nil {
+		return nil, err
+	}
+	output, err := s3.New(sess).GetObject(&s3.GetObjectInput{
+		Bucket: aws.String(bucket),
+		Key:    aws.String(key),
+	})
+	if err != nil {
+		if awsErr, ok := err.(awserr.Error); ok && awsErr.Code() == s3.ErrCodeNoSuchKey {
+			return nil, ErrNotFound
+		}
+		return nil, err
+	}
+	defer output.Body.Close()
+	return ioutil.ReadAll(output.Body)
+}
+
+func readGCS(bucket string, key string) ([]byte, error) {
+	ctx := context.Background()
+	client, err := storage.NewClient(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer client.Close()
+	reader, err := client.Bucket(bucket).Object(key).NewReader(ctx)
+	if err != nil {
+		if err == storage.ErrObjectNotExist {
+			return nil, ErrNotFound
+		}
+		return nil, err
+	}
+	defer reader.Close()
+	return ioutil.ReadAll(reader)
+}
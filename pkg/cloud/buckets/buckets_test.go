@@ -0,0 +1,45 @@
+package buckets_test
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/jenkins-x/jx/pkg/cloud/buckets"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWriteFileToFileBucket(t *testing.T) {
+	dir, err := ioutil.TempDir("", "test-buckets")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	u, err := buckets.WriteFile("file://"+filepath.ToSlash(dir)+"/logs/", "/myorg/myapp/1.log", []byte("hello"), "text/plain")
+	require.NoError(t, err)
+
+	fileName := filepath.Join(dir, "logs", "myorg", "myapp", "1.log")
+	assert.Equal(t, "file://"+filepath.ToSlash(fileName), u)
+	data, err := ioutil.ReadFile(fileName)
+	require.NoError(t, err)
+	assert.Equal(t, "hello", string(data))
+
+	data, err = buckets.ReadFile(u)
+	require.NoError(t, err)
+	assert.Equal(t, "hello", string(data))
+}
+
+func TestReadFileNotFound(t *testing.T) {
+	dir, err := ioutil.TempDir("", "test-buckets")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	_, err = buckets.ReadFile("file://" + filepath.ToSlash(dir) + "/missing.json")
+	assert.True(t, buckets.IsNotFound(err))
+}
+
+func TestWriteFileUnsupportedBucket(t *testing.T) {
+	_, err := buckets.WriteFile("ftp://myhost/logs", "1.log", []byte("hello"), "text/plain")
+	assert.Error(t, err)
+}
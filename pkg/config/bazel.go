@@ -0,0 +1,30 @@
+package config
+
+const (
+	// BazelBuildPack the build pack whose pod template provides the bazel and jx binaries used by the steps generated
+	// for Bazel projects
+	BazelBuildPack = "bazel"
+
+	// DefaultBazelTargets the target pattern of all the targets of the workspace
+	DefaultBazelTargets = "//..."
+)
+
+// BazelConfig configures the steps generated for the builds of a Bazel workspace which do not define their own steps.
+// Each lifecycle of the build maps to the Bazel targets it builds, tests or runs and the steps use the remote cache
+// and remote execution endpoints of the build defaults of the team
+type BazelConfig struct {
+	// the image of the generated steps. Defaults to the image of the bazel build pack
+	Image string `yaml:"image,omitempty"`
+
+	// the targets built by the build lifecycle. Defaults to all the targets of the workspace
+	Build []string `yaml:"build,omitempty"`
+
+	// the targets tested by the test lifecycle. Defaults to all the targets of the workspace
+	Test []string `yaml:"test,omitempty"`
+
+	// the targets run by the release lifecycle of release builds such as the targets which push the images and charts
+	Release []string `yaml:"release,omitempty"`
+
+	// the additional flags of every bazel command such as '--config=ci'
+	Flags []string `yaml:"flags,omitempty"`
+}
@@ -0,0 +1,48 @@
+package config
+
+import (
+	"strings"
+)
+
+// SkipCIDirectives the directives in the message of the head commit which skip creating the builds altogether
+var SkipCIDirectives = []string{"skip ci", "ci skip"}
+
+// CommitDirectiveConfig maps a directive such as '[skip tests]' in the message of the head commit to the steps which
+// are skipped when the builds are created
+type CommitDirectiveConfig struct {
+	// the directive such as 'skip tests' which is matched in square brackets ignoring case
+	Directive string `yaml:"directive"`
+
+	// the names of the steps which are skipped when the head commit contains the directive
+	Skip []string `yaml:"skip,omitempty"`
+}
+
+// HasCommitDirective returns true if the commit message contains the directive in square brackets ignoring case
+func HasCommitDirective(message string, directive string) bool {
+	directive = strings.TrimSpace(strings.TrimSuffix(strings.TrimPrefix(strings.TrimSpace(directive), "["), "]"))
+	if directive == "" {
+		return false
+	}
+	return strings.Contains(strings.ToLower(message), "["+strings.ToLower(directive)+"]")
+}
+
+// SkipsCI returns true if the commit message contains a directive such as '[skip ci]' which skips the builds
+func SkipsCI(message string) bool {
+	for _, directive := range SkipCIDirectives {
+		if HasCommitDirective(message, directive) {
+			return true
+		}
+	}
+	return false
+}
+
+// CommitDirectivesFor returns the configurations of the directives in the given commit message
+func (c *ProjectConfig) CommitDirectivesFor(message string) []*CommitDirectiveConfig {
+	answer := []*CommitDirectiveConfig{}
+	for _, config := range c.CommitDirectives {
+		if config != nil && HasCommitDirective(message, config.Directive) {
+			answer = append(answer, config)
+		}
+	}
+	return answer
+}
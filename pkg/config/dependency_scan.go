@@ -0,0 +1,64 @@
+package config
+
+import (
+	"github.com/jenkins-x/jx/pkg/util"
+)
+
+const (
+	// DependencyScanOWASP scans the dependencies with OWASP dependency-check
+	DependencyScanOWASP = "owasp"
+	// DependencyScanSnyk scans the dependencies and their licenses with snyk
+	DependencyScanSnyk = "snyk"
+
+	// SeverityLow the severity of low vulnerabilities
+	SeverityLow = "low"
+	// SeverityMedium the severity of medium vulnerabilities
+	SeverityMedium = "medium"
+	// SeverityHigh the severity of high vulnerabilities
+	SeverityHigh = "high"
+	// SeverityCritical the severity of critical vulnerabilities
+	SeverityCritical = "critical"
+	// SeverityNone never fails the builds so that the vulnerabilities are only reported
+	SeverityNone = "none"
+)
+
+var (
+	// DependencyScanTools the tools which can scan the dependencies of a project
+	DependencyScanTools = []string{DependencyScanOWASP, DependencyScanSnyk}
+
+	// Severities the severities of vulnerabilities from the lowest to the highest
+	Severities = []string{SeverityLow, SeverityMedium, SeverityHigh, SeverityCritical}
+)
+
+// DependencyScanConfig the scanning of the dependencies of a project for known vulnerabilities and license issues
+type DependencyScanConfig struct {
+	// the tool which scans the dependencies such as 'owasp' or 'snyk'. Defaults to 'owasp'
+	Tool string `yaml:"tool,omitempty"`
+
+	// the lowest severity of the vulnerabilities which fail the build such as 'medium', 'high' or 'critical' or 'none'
+	// to only report the vulnerabilities. Defaults to 'high'
+	Threshold string `yaml:"threshold,omitempty"`
+
+	// the image of the tool. Defaults to the image of the tool published by its vendor
+	Image string `yaml:"image,omitempty"`
+
+	// the URL of the bucket such as 's3://mybucket/reports' or 'gs://mybucket/reports' which the reports are uploaded to
+	ReportBucketURL string `yaml:"reportBucketUrl,omitempty"`
+
+	// the name of the Secret containing the token of the tool in the key 'token' such as the API token of snyk
+	TokenSecret string `yaml:"tokenSecret,omitempty"`
+
+	// disables the scanning of the dependencies which the team enables for all projects
+	Disabled bool `yaml:"disabled,omitempty"`
+}
+
+// Validate returns an error if the tool or threshold is not supported
+func (c *DependencyScanConfig) Validate() error {
+	if c.Tool != "" && util.StringArrayIndex(DependencyScanTools, c.Tool) < 0 {
+		return util.InvalidOption("dependencyScan.tool", c.Tool, DependencyScanTools)
+	}
+	if c.Threshold != "" && c.Threshold != SeverityNone && util.StringArrayIndex(Severities, c.Threshold) < 0 {
+		return util.InvalidOption("dependencyScan.threshold", c.Threshold, append(append([]string{}, Severities...), SeverityNone))
+	}
+	return nil
+}
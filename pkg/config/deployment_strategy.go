@@ -0,0 +1,65 @@
+package config
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/jenkins-x/jx/pkg/util"
+)
+
+const (
+	// DeploymentStrategyCanary deploys a new version as a canary which Flagger progressively shifts traffic to
+	// while analysing its metrics, rolling back if the analysis fails
+	DeploymentStrategyCanary = "canary"
+	// DeploymentStrategyBlueGreen deploys a new version alongside the live version then switches the service to it
+	// once it is verified, retiring the previous version or the new version if the verification fails
+	DeploymentStrategyBlueGreen = "blueGreen"
+)
+
+// DeploymentStrategyNames the names of the deployment strategies
+var DeploymentStrategyNames = []string{DeploymentStrategyCanary, DeploymentStrategyBlueGreen}
+
+// CanaryConfig configures the Flagger analysis of a canary deployment
+type CanaryConfig struct {
+	// the port the service of the application listens on
+	Port int `yaml:"port,omitempty"`
+
+	// how often the metrics are analysed such as '1m'
+	Interval string `yaml:"interval,omitempty"`
+
+	// the number of failed metric checks before the canary is rolled back
+	Threshold int `yaml:"threshold,omitempty"`
+
+	// the maximum percentage of traffic routed to the canary
+	MaxWeight int `yaml:"maxWeight,omitempty"`
+
+	// the percentage of traffic added to the canary after each successful analysis
+	StepWeight int `yaml:"stepWeight,omitempty"`
+
+	// how long to wait for the analysis to complete such as '30m' before failing the build
+	Timeout string `yaml:"timeout,omitempty"`
+}
+
+// BlueGreenConfig configures the verification of a blue green deployment
+type BlueGreenConfig struct {
+	// how long to wait for the new version to become available such as '5m'
+	Timeout string `yaml:"timeout,omitempty"`
+
+	// the shell command which verifies the new version before the service is switched to it. The track of the new
+	// version is available as $JX_TRACK
+	Verify string `yaml:"verify,omitempty"`
+}
+
+// ValidateDeploymentStrategy returns an error if the deployment strategy is not empty or one of the
+// DeploymentStrategyNames
+func ValidateDeploymentStrategy(name string) error {
+	if name == "" {
+		return nil
+	}
+	for _, n := range DeploymentStrategyNames {
+		if n == name {
+			return nil
+		}
+	}
+	return util.WithExitCode(fmt.Errorf("Unknown deployment strategy %s. Possible values: %s", name, strings.Join(DeploymentStrategyNames, ", ")), util.ExitCodeValidation)
+}
@@ -0,0 +1,79 @@
+package config
+
+import (
+	"regexp"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+)
+
+const (
+	// EnvFeatureName the environment variable containing the sanitized name of the feature of a feature build which
+	// is a valid Kubernetes name such as 'add-login'
+	EnvFeatureName = "JX_FEATURE_NAME"
+	// EnvFeatureBranch the environment variable containing the branch of the feature of a feature build
+	EnvFeatureBranch = "JX_FEATURE_BRANCH"
+
+	// maxFeatureNameLength the maximum length of the name of a feature which leaves room for the owner and repository
+	// in the names of the preview environment and namespace of the feature
+	maxFeatureNameLength = 40
+)
+
+// DefaultFeatureBranchPrefixes the prefixes of the branch naming conventions of features such as 'feature/add-login'
+var DefaultFeatureBranchPrefixes = []string{"feature/", "features/", "feat/", "feature-", "feat-", "feature_"}
+
+// invalidFeatureNameChars matches the characters which are not valid in a Kubernetes name
+var invalidFeatureNameChars = regexp.MustCompile(`[^a-z0-9]+`)
+
+// FeatureConfig the feature builds of the branches of features such as 'feature/add-login'
+type FeatureConfig struct {
+	// the prefixes of the branch naming convention of the features of the project. Defaults to the common
+	// conventions such as 'feature/' and 'feat/'
+	BranchPrefixes []string `yaml:"branchPrefixes,omitempty"`
+
+	// the steps added to the end of the feature builds such as the steps which deploy each feature to its own
+	// environment. The steps can use $JX_FEATURE_NAME to name the resources of the feature
+	Steps []corev1.Container `yaml:"steps,omitempty"`
+}
+
+// FeatureBranch the feature a branch is building
+type FeatureBranch struct {
+	// Branch the branch of the feature such as 'feature/JIRA-123_Add-Login'
+	Branch string
+	// Prefix the prefix of the branch naming convention such as 'feature/' or an empty string if the branch follows
+	// no convention
+	Prefix string
+	// Name the sanitized name of the feature which is a valid Kubernetes name such as 'jira-123-add-login'
+	Name string
+}
+
+// FeatureBranchFor detects the naming convention of the feature branch and derives the sanitized name of its feature.
+// The whole branch names the feature when it follows no convention such as the 'develop' branch of GitFlow
+func (c *ProjectConfig) FeatureBranchFor(branch string) *FeatureBranch {
+	prefixes := DefaultFeatureBranchPrefixes
+	if c.Feature != nil && len(c.Feature.BranchPrefixes) > 0 {
+		prefixes = c.Feature.BranchPrefixes
+	}
+	answer := &FeatureBranch{
+		Branch: branch,
+	}
+	name := branch
+	for _, prefix := range prefixes {
+		if strings.HasPrefix(strings.ToLower(branch), strings.ToLower(prefix)) && len(branch) > len(prefix) {
+			answer.Prefix = prefix
+			name = branch[len(prefix):]
+			break
+		}
+	}
+	answer.Name = SanitizeFeatureName(name)
+	return answer
+}
+
+// SanitizeFeatureName converts the name of a feature into a valid Kubernetes name of at most 40 characters
+func SanitizeFeatureName(name string) string {
+	answer := strings.Trim(invalidFeatureNameChars.ReplaceAllString(strings.ToLower(name), "-"), "-")
+	if len(answer) > maxFeatureNameLength {
+		answer = strings.TrimRight(answer[:maxFeatureNameLength], "-")
+	}
+	return answer
+}
@@ -0,0 +1,31 @@
+package config_test
+
+import (
+	"testing"
+
+	"github.com/jenkins-x/jx/pkg/config"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFeatureBranchFor(t *testing.T) {
+	t.Parallel()
+	pc := &config.ProjectConfig{}
+
+	assertFeature := func(branch string, prefix string, name string) {
+		feature := pc.FeatureBranchFor(branch)
+		assert.Equal(t, branch, feature.Branch)
+		assert.Equal(t, prefix, feature.Prefix, "prefix of branch %s", branch)
+		assert.Equal(t, name, feature.Name, "name of branch %s", branch)
+	}
+	assertFeature("feature/JIRA-123_Add Login", "feature/", "jira-123-add-login")
+	assertFeature("feat/cheese", "feat/", "cheese")
+	assertFeature("Feature-cheese", "feature-", "cheese")
+	assertFeature("develop", "", "develop")
+	assertFeature("feature/this-is-a-very-long-feature-name-which-is-truncated-to-forty", "feature/", "this-is-a-very-long-feature-name-which-i")
+
+	pc.Feature = &config.FeatureConfig{
+		BranchPrefixes: []string{"story/"},
+	}
+	assertFeature("story/cheese", "story/", "cheese")
+	assertFeature("feature/cheese", "", "feature-cheese")
+}
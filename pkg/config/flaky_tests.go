@@ -0,0 +1,21 @@
+package config
+
+// DefaultFlakyTestMinFlips the number of times the outcome of a test must flip between passing and failing in its
+// recent runs for the test to be flaky
+const DefaultFlakyTestMinFlips = 3
+
+// FlakyTestsConfig the configuration of the detection of flaky tests from the JUnit reports of the test steps
+type FlakyTestsConfig struct {
+	// the names of the steps which run the tests
+	Steps []string `yaml:"steps,omitempty"`
+
+	// the glob patterns of the JUnit reports of the tests such as 'target/surefire-reports/TEST-*.xml'
+	Reports []string `yaml:"reports,omitempty"`
+
+	// the command which reruns the failed tests if they are all flaky. The tests are passed in $FLAKY_TESTS as a comma
+	// separated list of Class#method such as 'mvn test -Dtest=$FLAKY_TESTS'
+	RetryCommand string `yaml:"retryCommand,omitempty"`
+
+	// the number of times the outcome of a test must flip in its recent runs for the test to be flaky
+	MinFlips int `yaml:"minFlips,omitempty"`
+}
@@ -0,0 +1,15 @@
+package config
+
+const (
+	// ImageBuilderDocker builds the image of the application from its Dockerfile
+	ImageBuilderDocker = "docker"
+	// ImageBuilderBuildpacks builds the image of the application with the Cloud Native Buildpacks builder of the team
+	ImageBuilderBuildpacks = "buildpacks"
+	// ImageBuilderJib builds the image of a Maven or Gradle application with Jib without a Docker daemon or Dockerfile
+	ImageBuilderJib = "jib"
+	// ImageBuilderKo builds the image of a Go application with ko without a Docker daemon or Dockerfile
+	ImageBuilderKo = "ko"
+)
+
+// ImageBuilders the tools which can build the image of an application
+var ImageBuilders = []string{ImageBuilderDocker, ImageBuilderBuildpacks, ImageBuilderJib, ImageBuilderKo}
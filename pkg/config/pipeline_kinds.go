@@ -0,0 +1,185 @@
+package config
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"github.com/jenkins-x/jx/pkg/util"
+	"gopkg.in/yaml.v2"
+)
+
+// PipelineKindsFileName the file of a build pack defining the custom kinds of pipeline of the projects using the pack
+const PipelineKindsFileName = "pipeline-kinds.yml"
+
+// PipelineKindConfig a custom kind of pipeline such as 'nightly', 'perf' or 'security' which is generated like the
+// built in kinds
+type PipelineKindConfig struct {
+	// the name of the kind used in the builds of jenkins-x.yml and with --kind
+	Name string `yaml:"name"`
+
+	// the other names of the kind which can be used instead of its name
+	Aliases []string `yaml:"aliases,omitempty"`
+
+	// the kind such as 'release' or another custom kind whose behaviour the kind inherits and whose build is used
+	// when the project has no build of the kind
+	Extends string `yaml:"extends,omitempty"`
+
+	// the regular expression matching the branches the kind is generated for. Defaults to all branches
+	Branches string `yaml:"branches,omitempty"`
+}
+
+// LoadPipelineKinds loads the custom kinds of pipeline defined in the pipeline-kinds.yml file of the directory of a
+// build pack returning no kinds if it has no such file
+func LoadPipelineKinds(dir string) ([]*PipelineKindConfig, error) {
+	fileName := filepath.Join(dir, PipelineKindsFileName)
+	data, err := ioutil.ReadFile(fileName)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	kinds := []*PipelineKindConfig{}
+	err = yaml.Unmarshal(data, &kinds)
+	if err != nil {
+		return nil, fmt.Errorf("Failed to parse the pipeline kinds of %s due to %s", fileName, err)
+	}
+	return kinds, nil
+}
+
+// AddPipelineKinds adds the custom kinds of pipeline which the project does not define itself such as the kinds of
+// its build pack
+func (c *ProjectConfig) AddPipelineKinds(kinds []*PipelineKindConfig) {
+	for _, k := range kinds {
+		if k != nil && c.PipelineKind(k.Name) == nil {
+			c.PipelineKinds = append(c.PipelineKinds, k)
+		}
+	}
+}
+
+// buildKindAliases the alternative names of the built in kinds of build
+var buildKindAliases = map[string]string{
+	"pr":           BuildKindPullRequest,
+	"pullrequest":  BuildKindPullRequest,
+	"pull-request": BuildKindPullRequest,
+}
+
+// PipelineKind returns the custom kind of pipeline of the given name or alias or nil if it is not a custom kind
+func (c *ProjectConfig) PipelineKind(kind string) *PipelineKindConfig {
+	for _, k := range c.PipelineKinds {
+		if k != nil && (k.Name == kind || util.StringArrayIndex(k.Aliases, kind) >= 0) {
+			return k
+		}
+	}
+	return nil
+}
+
+// CanonicalKind returns the name of the kind of the given name or alias. Unknown kinds are returned unchanged
+func (c *ProjectConfig) CanonicalKind(kind string) string {
+	if k := c.PipelineKind(kind); k != nil {
+		return k.Name
+	}
+	for _, name := range BuildKindNames {
+		if strings.EqualFold(name, kind) {
+			return name
+		}
+	}
+	if name, ok := buildKindAliases[strings.ToLower(kind)]; ok {
+		return name
+	}
+	return kind
+}
+
+// BaseKind returns the built in kind whose behaviour the kind inherits by following the kinds it extends or the kind
+// itself if it extends no kind
+func (c *ProjectConfig) BaseKind(kind string) string {
+	kind = c.CanonicalKind(kind)
+	visited := map[string]bool{}
+	for !visited[kind] {
+		visited[kind] = true
+		k := c.PipelineKind(kind)
+		if k == nil || k.Extends == "" {
+			return kind
+		}
+		kind = c.CanonicalKind(k.Extends)
+	}
+	return kind
+}
+
+// ValidatePipelineKinds verifies the custom kinds have unique names and aliases which do not clash with the built in
+// kinds and that they extend known kinds without a cycle
+func (c *ProjectConfig) ValidatePipelineKinds() error {
+	names := map[string]bool{}
+	for i, k := range c.PipelineKinds {
+		if k == nil || k.Name == "" {
+			return fmt.Errorf("the pipelineKinds[%d] of %s has no name", i, ProjectConfigFileName)
+		}
+		for _, name := range append([]string{k.Name}, k.Aliases...) {
+			if isBuiltInKind(name) {
+				return fmt.Errorf("the pipeline kind %s of %s clashes with the built in kind %s", k.Name, ProjectConfigFileName, name)
+			}
+			if names[name] {
+				return fmt.Errorf("the pipeline kind %s of %s is defined more than once", name, ProjectConfigFileName)
+			}
+			names[name] = true
+		}
+		if k.Branches != "" {
+			_, err := regexp.Compile("^(?:" + k.Branches + ")$")
+			if err != nil {
+				return fmt.Errorf("the branches %s of pipeline kind %s is not a valid regular expression: %s", k.Branches, k.Name, err)
+			}
+		}
+	}
+	for _, k := range c.PipelineKinds {
+		if k.Extends == "" {
+			continue
+		}
+		extends := c.CanonicalKind(k.Extends)
+		if c.PipelineKind(extends) == nil && util.StringArrayIndex(BuildKindNames, extends) < 0 {
+			return fmt.Errorf("the pipeline kind %s extends the unknown kind %s. Possible values: %s", k.Name, k.Extends, strings.Join(append(append([]string{}, BuildKindNames...), c.pipelineKindNames()...), ", "))
+		}
+		if base := c.PipelineKind(c.BaseKind(k.Name)); base != nil && base.Extends != "" {
+			return fmt.Errorf("the pipeline kind %s is part of a cycle of kinds extending each other", k.Name)
+		}
+	}
+	return nil
+}
+
+// PipelineKindMatchesBranch returns true if the kind is generated for the branch. Built in kinds and custom kinds
+// without branches match every branch
+func (c *ProjectConfig) PipelineKindMatchesBranch(kind string, branch string) (bool, error) {
+	k := c.PipelineKind(kind)
+	if k == nil || k.Branches == "" || branch == "" {
+		return true, nil
+	}
+	r, err := regexp.Compile("^(?:" + k.Branches + ")$")
+	if err != nil {
+		return false, fmt.Errorf("Failed to parse the branch regular expression %s due to %s", k.Branches, err)
+	}
+	return r.MatchString(branch), nil
+}
+
+// isBuiltInKind returns true if the name is the name or an alias of a built in kind ignoring case
+func isBuiltInKind(name string) bool {
+	for _, kind := range BuildKindNames {
+		if strings.EqualFold(kind, name) {
+			return true
+		}
+	}
+	return buildKindAliases[strings.ToLower(name)] != ""
+}
+
+// pipelineKindNames returns the names of the custom kinds
+func (c *ProjectConfig) pipelineKindNames() []string {
+	answer := []string{}
+	for _, k := range c.PipelineKinds {
+		if k != nil {
+			answer = append(answer, k.Name)
+		}
+	}
+	return answer
+}
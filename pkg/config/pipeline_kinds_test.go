@@ -0,0 +1,56 @@
+package config_test
+
+import (
+	"testing"
+
+	"github.com/jenkins-x/jx/pkg/config"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPipelineKinds(t *testing.T) {
+	t.Parallel()
+	pc := &config.ProjectConfig{
+		PipelineKinds: []*config.PipelineKindConfig{
+			{Name: "nightly", Aliases: []string{"cron"}, Extends: config.BuildKindRelease, Branches: "master"},
+			{Name: "perf", Extends: "pr"},
+			{Name: "soak", Extends: "nightly"},
+			{Name: "security"},
+		},
+	}
+	assert.NoError(t, pc.ValidatePipelineKinds())
+
+	assert.Equal(t, "nightly", pc.CanonicalKind("cron"))
+	assert.Equal(t, config.BuildKindPullRequest, pc.CanonicalKind("PR"))
+	assert.Equal(t, config.BuildKindPullRequest, pc.CanonicalKind("pullrequest"))
+	assert.Equal(t, "unknown", pc.CanonicalKind("unknown"))
+
+	assert.Equal(t, config.BuildKindRelease, pc.BaseKind("soak"))
+	assert.Equal(t, config.BuildKindPullRequest, pc.BaseKind("perf"))
+	assert.Equal(t, "security", pc.BaseKind("security"))
+	assert.Equal(t, config.BuildKindRelease, pc.BaseKind(config.BuildKindRelease))
+
+	matches, err := pc.PipelineKindMatchesBranch("cron", "master")
+	assert.NoError(t, err)
+	assert.True(t, matches)
+	matches, err = pc.PipelineKindMatchesBranch("nightly", "PR-12")
+	assert.NoError(t, err)
+	assert.False(t, matches)
+	matches, err = pc.PipelineKindMatchesBranch(config.BuildKindPullRequest, "PR-12")
+	assert.NoError(t, err)
+	assert.True(t, matches)
+
+	invalid := map[string][]*config.PipelineKindConfig{
+		"clashes with a built in kind":  {{Name: "Release"}},
+		"clashes with a built in alias": {{Name: "nightly", Aliases: []string{"pr"}}},
+		"is defined twice":              {{Name: "nightly"}, {Name: "nightly"}},
+		"extends an unknown kind":       {{Name: "nightly", Extends: "does-not-exist"}},
+		"extends itself":                {{Name: "a", Extends: "b"}, {Name: "b", Extends: "a"}},
+		"has invalid branches":          {{Name: "nightly", Branches: "("}},
+	}
+	for message, kinds := range invalid {
+		pc := &config.ProjectConfig{
+			PipelineKinds: kinds,
+		}
+		assert.Error(t, pc.ValidatePipelineKinds(), "should fail when a pipeline kind %s", message)
+	}
+}
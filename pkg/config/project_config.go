@@ -5,6 +5,8 @@ import (
 	"io/ioutil"
 	"path/filepath"
 	"reflect"
+	"regexp"
+	"time"
 
 	"github.com/jenkins-x/jx/pkg/util"
 	"gopkg.in/yaml.v2"
@@ -14,6 +16,9 @@ import (
 const (
 	// ProjectConfigFileName is the name of the project configuration file
 	ProjectConfigFileName = "jenkins-x.yml"
+
+	// DefaultPipelinesDir is the directory of a project containing its committed pre-generated builds
+	DefaultPipelinesDir = ".jx/pipelines"
 )
 
 type ProjectConfig struct {
@@ -21,6 +26,7 @@ type ProjectConfig struct {
 	Env []corev1.EnvVar `yaml:"env,omitempty"`
 
 	Builds              []*BranchBuild            `yaml:"builds,omitempty"`
+	Branches            []*BranchConfig           `yaml:"branches,omitempty"`
 	PreviewEnvironments *PreviewEnvironmentConfig `yaml:"previewEnvironments,omitempty"`
 	IssueTracker        *IssueTrackerConfig       `yaml:"issueTracker,omitempty"`
 	Chat                *ChatConfig               `yaml:"chat,omitempty"`
@@ -29,7 +35,118 @@ type ProjectConfig struct {
 	BuildPack           string                    `yaml:"buildPack,omitempty"`
 	BuildPackGitURL     string                    `yaml:"buildPackGitURL,omitempty"`
 	BuildPackGitURef    string                    `yaml:"buildPackGitRef,omitempty"`
+	Library             *LibraryConfig            `yaml:"library,omitempty"`
+	Dependents          []*DependentConfig        `yaml:"dependents,omitempty"`
+	Triggers            []string                  `yaml:"triggers,omitempty"`
+	Budget              *BudgetConfig             `yaml:"budget,omitempty"`
 	Workflow            string                    `yaml:"workflow,omitempty"`
+	WorkflowPreset      string                    `yaml:"workflowPreset,omitempty"`
+
+	// the directory containing committed pre-generated builds which are applied rather than generating the builds
+	PipelinesDir string `yaml:"pipelinesDir,omitempty"`
+
+	// disables adding the image and version values to the steps which run 'helm upgrade' or 'helm install'
+	DisableChartValues bool `yaml:"disableChartValues,omitempty"`
+
+	// how the steps which run 'helm upgrade' or 'helm install' deploy the new version such as 'canary' or 'blueGreen'
+	DeploymentStrategy string `yaml:"deploymentStrategy,omitempty"`
+
+	// the analysis of the canary deployment strategy
+	Canary *CanaryConfig `yaml:"canary,omitempty"`
+
+	// the verification of the blue green deployment strategy
+	BlueGreen *BlueGreenConfig `yaml:"blueGreen,omitempty"`
+
+	// the Terraform configuration planned and applied by the builds of the infra kind
+	Terraform *TerraformConfig `yaml:"terraform,omitempty"`
+
+	// the Bazel targets of each lifecycle of the builds of a Bazel workspace
+	Bazel *BazelConfig `yaml:"bazel,omitempty"`
+
+	// the labels of pull requests which skip steps or add pipeline parameters to the builds of the pull request
+	PullRequestLabels []*PullRequestLabelConfig `yaml:"pullRequestLabels,omitempty"`
+
+	// the directives such as '[skip tests]' in the message of the head commit which skip steps of the builds
+	CommitDirectives []*CommitDirectiveConfig `yaml:"commitDirectives,omitempty"`
+
+	// the detection and retrying of flaky tests from the JUnit reports of the test steps
+	FlakyTests *FlakyTestsConfig `yaml:"flakyTests,omitempty"`
+
+	// the tool which builds the image of the application such as 'jib' or 'ko' instead of the Dockerfile. Defaults to
+	// the image builder of the build pack
+	ImageBuilder string `yaml:"imageBuilder,omitempty"`
+
+	// disables the SonarQube analysis of the builds when the team has a SonarQube server
+	DisableSonarQube bool `yaml:"disableSonarQube,omitempty"`
+
+	// the key of the project in SonarQube. Defaults to the name of the project
+	SonarQubeProjectKey string `yaml:"sonarQubeProjectKey,omitempty"`
+
+	// the scanning of the dependencies for known vulnerabilities and license issues. Defaults to the dependency scan
+	// of the team
+	DependencyScan *DependencyScanConfig `yaml:"dependencyScan,omitempty"`
+
+	// the merging of the consecutive steps of the builds which run in the same container into one step
+	MergeSteps *MergeStepsConfig `yaml:"mergeSteps,omitempty"`
+
+	// the custom kinds of pipeline such as 'nightly', 'perf' or 'security' in addition to the built in kinds
+	PipelineKinds []*PipelineKindConfig `yaml:"pipelineKinds,omitempty"`
+
+	// the naming convention of the feature branches and the steps which deploy each feature
+	Feature *FeatureConfig `yaml:"feature,omitempty"`
+}
+
+// MergeStepsConfig the merging of consecutive steps which share their image, working directory, environment and
+// volumes into one step running their commands in turn to save the startup of a container per step
+type MergeStepsConfig struct {
+	// merges the consecutive steps of the builds
+	Enabled bool `yaml:"enabled,omitempty"`
+
+	// the names of the steps which are never merged with other steps such as steps whose logs should stay separate
+	Exclude []string `yaml:"exclude,omitempty"`
+}
+
+// BranchConfig overrides the builds of a project for the branches or tags whose name matches a regular expression
+type BranchConfig struct {
+	// the regular expression matching the branch or tag names such as 'release-.*'
+	Name string `yaml:"name,omitempty"`
+
+	// the builds used for matching branches which replace the builds of the same kind
+	Builds []*BranchBuild `yaml:"builds,omitempty"`
+}
+
+// LibraryConfig the configuration of a library which publishes artifacts instead of container images and charts
+type LibraryConfig struct {
+	// the kind of library such as 'maven', 'npm' or 'go'
+	Kind string `yaml:"kind,omitempty"`
+
+	// the command used to publish the artifacts of a release which defaults based on the kind of library
+	Publish []string `yaml:"publish,omitempty"`
+
+	// disables the pull requests which update the version of the library in downstream projects
+	DisableVersionPR bool `yaml:"disableVersionPR,omitempty"`
+}
+
+// DependentConfig a downstream git repository which depends on this project and which is sent a pull request
+// updating the version whenever this project is released
+type DependentConfig struct {
+	// the git URL of the dependent repository
+	GitURL string `yaml:"gitUrl,omitempty"`
+
+	// the branch the pull requests are created against which defaults to master
+	Branch string `yaml:"branch,omitempty"`
+
+	// the files in the dependent repository which contain the version of this project
+	Files []*DependentFile `yaml:"files,omitempty"`
+}
+
+// DependentFile a file in a dependent repository containing the version of this project
+type DependentFile struct {
+	// the path of the file relative to the root of the repository
+	Path string `yaml:"path,omitempty"`
+
+	// a regular expression whose first group matches the version to replace such as '<foo.version>(.+)</foo.version>'
+	Pattern string `yaml:"pattern,omitempty"`
 }
 
 type PreviewEnvironmentConfig struct {
@@ -41,6 +158,16 @@ type IssueTrackerConfig struct {
 	Kind    string `yaml:"kind,omitempty"`
 	URL     string `yaml:"url,omitempty"`
 	Project string `yaml:"project,omitempty"`
+	// transitions the issues referenced by a release when the release pipeline succeeds
+	Release *IssueReleaseConfig `yaml:"release,omitempty"`
+}
+
+// IssueReleaseConfig the lifecycle of the issues referenced by the commits of a release
+type IssueReleaseConfig struct {
+	// the statuses the issues are transitioned to in order of preference which defaults to 'Released' then 'Done'
+	Statuses []string `yaml:"statuses,omitempty"`
+	// disables adding the version of the release to the fix versions of the issues
+	NoFixVersion bool `yaml:"noFixVersion,omitempty"`
 }
 
 type WikiConfig struct {
@@ -72,6 +199,9 @@ type BranchBuild struct {
 	// display name
 	Name string `yaml:"name,omitempty"`
 
+	// the context name reported on pull requests and used by chat commands such as '/test <context>'
+	Context string `yaml:"context,omitempty"`
+
 	// List of sources to populate environment variables in all the steps if there is not already
 	// an environment variable defined on that step
 	EnvFrom []corev1.EnvFromSource `yaml:"envFrom,omitempty"`
@@ -81,6 +211,48 @@ type BranchBuild struct {
 
 	ExcludePodTemplateEnv     bool `yaml:"excludePodTemplateEnv,omitempty"`
 	ExcludePodTemplateVolumes bool `yaml:"excludePodTemplateVolumes,omitempty"`
+
+	// List of manual approval gates which pause the build before a step until they are approved
+	Approvals []*ApprovalConfig `yaml:"approvals,omitempty"`
+
+	// the database migrations which run before each step deploying a chart via 'helm upgrade' or 'helm install'.
+	// The migrations of an application run one at a time in each namespace and the build fails if one of them fails
+	Migrations []corev1.Container `yaml:"migrations,omitempty"`
+
+	// the steps such as smoke tests which run after each step deploying a chart via 'helm upgrade' or 'helm install'.
+	// If one of them fails the release is rolled back to its previous revision and the build fails
+	PostDeploy []corev1.Container `yaml:"postDeploy,omitempty"`
+
+	// the budget of this kind of build which overrides the budget of the project
+	Budget *BudgetConfig `yaml:"budget,omitempty"`
+}
+
+// BudgetConfig limits how long the builds of a pipeline can run for and how many can run at the same time.
+// Builds which exceed the budget are cancelled by 'jx controller budget'
+type BudgetConfig struct {
+	// the maximum duration of a build such as '30m'
+	MaxDuration string `yaml:"maxDuration,omitempty"`
+
+	// the maximum number of build pods of the pipeline which can run at the same time
+	MaxParallelPods int `yaml:"maxParallelPods,omitempty"`
+}
+
+// ApprovalConfig a manual approval gate which pauses the build before the given step until approved via 'jx approve'
+type ApprovalConfig struct {
+	// the name of the approval gate
+	Name string `yaml:"name,omitempty"`
+
+	// the name of the step which requires approval before it runs
+	Before string `yaml:"before,omitempty"`
+
+	// the users who can approve; if empty then anyone can approve
+	Approvers []string `yaml:"approvers,omitempty"`
+
+	// how long to wait for approval such as '2h' before failing the build
+	Timeout string `yaml:"timeout,omitempty"`
+
+	// the image used to wait for approval which must contain the jx binary; defaults to the image of the step
+	Image string `yaml:"image,omitempty"`
 }
 
 type Build struct {
@@ -107,8 +279,43 @@ type Build struct {
 	NodeSelector map[string]string `yaml:"nodeSelector,omitempty"`
 }
 
+// BudgetForBuild returns the budget of the given build which defaults to the budget of the project
+func (c *ProjectConfig) BudgetForBuild(build *BranchBuild) *BudgetConfig {
+	if build != nil && build.Budget != nil {
+		return build.Budget
+	}
+	return c.Budget
+}
+
+// Validate validates the budget
+func (b *BudgetConfig) Validate() error {
+	if b.MaxDuration != "" {
+		d, err := time.ParseDuration(b.MaxDuration)
+		if err != nil {
+			return util.WithExitCode(fmt.Errorf("Invalid maxDuration %s in budget: %s", b.MaxDuration, err), util.ExitCodeValidation)
+		}
+		if d <= 0 {
+			return util.WithExitCode(fmt.Errorf("The maxDuration %s in budget must be positive", b.MaxDuration), util.ExitCodeValidation)
+		}
+	}
+	if b.MaxParallelPods < 0 {
+		return util.WithExitCode(fmt.Errorf("The maxParallelPods %d in budget must not be negative", b.MaxParallelPods), util.ExitCodeValidation)
+	}
+	return nil
+}
+
 // LoadProjectConfig loads the project configuration if there is a project configuration file
 func LoadProjectConfig(projectDir string) (*ProjectConfig, string, error) {
+	return loadProjectConfig(projectDir, false)
+}
+
+// LoadProjectConfigStrict loads the project configuration if there is a project configuration file failing if the
+// file contains keys which are not fields of the configuration such as a misspelled 'comand'
+func LoadProjectConfigStrict(projectDir string) (*ProjectConfig, string, error) {
+	return loadProjectConfig(projectDir, true)
+}
+
+func loadProjectConfig(projectDir string, strict bool) (*ProjectConfig, string, error) {
 	fileName := ProjectConfigFileName
 	if projectDir != "" {
 		fileName = filepath.Join(projectDir, fileName)
@@ -122,11 +329,87 @@ func LoadProjectConfig(projectDir string) (*ProjectConfig, string, error) {
 	if err != nil {
 		return &config, fileName, fmt.Errorf("Failed to load file %s due to %s", fileName, err)
 	}
-	err = yaml.Unmarshal(data, &config)
+	pc, err := ParseProjectConfig(data, strict)
 	if err != nil {
-		return &config, fileName, fmt.Errorf("Failed to unmarshal YAML file %s due to %s", fileName, err)
+		return pc, fileName, fmt.Errorf("Failed to unmarshal YAML file %s due to %s", fileName, err)
+	}
+	return pc, fileName, nil
+}
+
+// ParseProjectConfig parses the YAML of a project configuration such as one received by a service rather than read
+// from a checkout. If strict it fails if the YAML contains keys which are not fields of the configuration
+func ParseProjectConfig(data []byte, strict bool) (*ProjectConfig, error) {
+	config := ProjectConfig{}
+	var err error
+	if strict {
+		err = UnmarshalStrict(data, &config)
+	} else {
+		err = yaml.Unmarshal(data, &config)
+	}
+	return &config, err
+}
+
+// BuildsForBranch returns the builds to use for the given branch or tag name. The builds of the first branch
+// configuration whose regular expression matches the whole branch name replace the builds of the same kind
+func (c *ProjectConfig) BuildsForBranch(branch string) ([]*BranchBuild, error) {
+	if branch == "" {
+		return c.Builds, nil
+	}
+	for _, bc := range c.Branches {
+		if bc == nil || bc.Name == "" {
+			continue
+		}
+		r, err := regexp.Compile("^(?:" + bc.Name + ")$")
+		if err != nil {
+			return c.Builds, fmt.Errorf("Failed to parse the branch regular expression %s due to %s", bc.Name, err)
+		}
+		if !r.MatchString(branch) {
+			continue
+		}
+		answer := []*BranchBuild{}
+		for _, build := range c.Builds {
+			overridden := false
+			for _, override := range bc.Builds {
+				if override != nil && override.Kind == build.Kind {
+					overridden = true
+					break
+				}
+			}
+			if !overridden {
+				answer = append(answer, build)
+			}
+		}
+		for _, override := range bc.Builds {
+			if override != nil {
+				answer = append(answer, override)
+			}
+		}
+		return answer, nil
+	}
+	return c.Builds, nil
+}
+
+// UpdateVersion replaces the versions matched by the first group of the pattern in the given text with the version
+// returning the new text and whether it changed
+func (f *DependentFile) UpdateVersion(text string, version string) (string, bool, error) {
+	r, err := regexp.Compile(f.Pattern)
+	if err != nil {
+		return text, false, fmt.Errorf("Failed to parse the version pattern %s for file %s due to %s", f.Pattern, f.Path, err)
+	}
+	if r.NumSubexp() < 1 {
+		return text, false, fmt.Errorf("The version pattern %s for file %s has no group matching the version", f.Pattern, f.Path)
+	}
+	answer := ""
+	last := 0
+	for _, match := range r.FindAllStringSubmatchIndex(text, -1) {
+		if match[2] < 0 {
+			continue
+		}
+		answer += text[last:match[2]] + version
+		last = match[3]
 	}
-	return &config, fileName, nil
+	answer += text[last:]
+	return answer, answer != text, nil
 }
 
 // IsEmpty returns true if this configuration is empty
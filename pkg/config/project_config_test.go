@@ -1,11 +1,15 @@
 package config_test
 
 import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
 	"testing"
 
 	"github.com/jenkins-x/jx/pkg/config"
 	"github.com/jenkins-x/jx/pkg/log"
 	"github.com/jenkins-x/jx/pkg/tests"
+	"github.com/jenkins-x/jx/pkg/util"
 	"gopkg.in/yaml.v2"
 	corev1 "k8s.io/api/core/v1"
 
@@ -47,3 +51,122 @@ func TestProjectConfigMarshal(t *testing.T) {
 	assert.True(t, projectConfig.Builds[0].ExcludePodTemplateEnv)
 	assert.True(t, projectConfig.Builds[0].ExcludePodTemplateVolumes)
 }
+
+func TestProjectConfigBuildsForBranch(t *testing.T) {
+	t.Parallel()
+	projectConfig := &config.ProjectConfig{
+		Builds: []*config.BranchBuild{
+			{
+				Kind: "release",
+				Name: "default-release",
+			},
+			{
+				Kind: "pullRequest",
+				Name: "default-pr",
+			},
+		},
+		Branches: []*config.BranchConfig{
+			{
+				Name: "release-.*|v\\d+\\.\\d+\\.\\d+",
+				Builds: []*config.BranchBuild{
+					{
+						Kind: "release",
+						Name: "maintenance-release",
+					},
+				},
+			},
+		},
+	}
+
+	for _, branch := range []string{"", "master", "feature-release-1"} {
+		builds, err := projectConfig.BuildsForBranch(branch)
+		assert.NoError(t, err)
+		assert.Equal(t, projectConfig.Builds, builds, "builds for branch %s", branch)
+	}
+
+	for _, branch := range []string{"release-1.x", "v1.2.3"} {
+		builds, err := projectConfig.BuildsForBranch(branch)
+		assert.NoError(t, err)
+		if assert.Equal(t, 2, len(builds), "builds for branch %s", branch) {
+			assert.Equal(t, "default-pr", builds[0].Name)
+			assert.Equal(t, "maintenance-release", builds[1].Name)
+		}
+	}
+}
+
+func TestDependentFileUpdateVersion(t *testing.T) {
+	t.Parallel()
+	file := &config.DependentFile{
+		Path:    "pom.xml",
+		Pattern: `<cheese.version>(.+)</cheese.version>`,
+	}
+	text := "<properties>\n  <cheese.version>1.0.1</cheese.version>\n</properties>\n"
+
+	actual, changed, err := file.UpdateVersion(text, "1.2.0")
+	assert.NoError(t, err)
+	assert.True(t, changed)
+	assert.Equal(t, "<properties>\n  <cheese.version>1.2.0</cheese.version>\n</properties>\n", actual)
+
+	_, changed, err = file.UpdateVersion(actual, "1.2.0")
+	assert.NoError(t, err)
+	assert.False(t, changed)
+
+	file.Pattern = "no-group"
+	_, _, err = file.UpdateVersion(text, "1.2.0")
+	assert.Error(t, err)
+}
+
+func TestBudgetForBuild(t *testing.T) {
+	t.Parallel()
+	releaseBudget := &config.BudgetConfig{MaxDuration: "1h", MaxParallelPods: 1}
+	release := &config.BranchBuild{Kind: config.BuildKindRelease, Budget: releaseBudget}
+	pullRequest := &config.BranchBuild{Kind: config.BuildKindPullRequest}
+	projectConfig := &config.ProjectConfig{
+		Budget: &config.BudgetConfig{MaxDuration: "20m"},
+		Builds: []*config.BranchBuild{release, pullRequest},
+	}
+
+	assert.Equal(t, releaseBudget, projectConfig.BudgetForBuild(release))
+	assert.Equal(t, projectConfig.Budget, projectConfig.BudgetForBuild(pullRequest))
+
+	assert.NoError(t, releaseBudget.Validate())
+	assert.Error(t, (&config.BudgetConfig{MaxDuration: "forever"}).Validate())
+	assert.Error(t, (&config.BudgetConfig{MaxParallelPods: -1}).Validate())
+}
+
+func TestLoadProjectConfigStrict(t *testing.T) {
+	t.Parallel()
+	dir, err := ioutil.TempDir("", "test-load-project-config-strict")
+	assert.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	projectYaml := `buildPack: maven
+builds:
+  - kind: release
+    build:
+      steps:
+        - name: build
+          comand:
+          - mvn
+          args:
+          - install
+`
+	err = ioutil.WriteFile(filepath.Join(dir, config.ProjectConfigFileName), []byte(projectYaml), util.DefaultWritePermissions)
+	assert.NoError(t, err)
+
+	pc, _, err := config.LoadProjectConfig(dir)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"install"}, pc.Builds[0].Build.Steps[0].Args)
+
+	_, _, err = config.LoadProjectConfigStrict(dir)
+	if assert.Error(t, err) {
+		assert.Contains(t, err.Error(), "line 7: field comand not found in type v1.Container. Did you mean: command")
+	}
+
+	err = ioutil.WriteFile(filepath.Join(dir, config.ProjectConfigFileName), []byte("biuldPack: maven\n"), util.DefaultWritePermissions)
+	assert.NoError(t, err)
+	_, _, err = config.LoadProjectConfigStrict(dir)
+	if assert.Error(t, err) {
+		assert.Contains(t, err.Error(), "field biuldPack not found in type config.ProjectConfig. Did you mean: buildPack")
+	}
+}
@@ -0,0 +1,31 @@
+package config
+
+import (
+	"github.com/jenkins-x/jx/pkg/util"
+	corev1 "k8s.io/api/core/v1"
+)
+
+// PullRequestLabelConfig changes the builds of the pull requests which have a label such as 'skip-e2e' or 'perf-test'
+// when the builds are created
+type PullRequestLabelConfig struct {
+	// the name of the label on the pull request
+	Label string `yaml:"label"`
+
+	// the names of the steps which are skipped when the pull request has the label
+	Skip []string `yaml:"skip,omitempty"`
+
+	// the environment variables added to each step when the pull request has the label which are used as parameters
+	// of the pipeline
+	Env []corev1.EnvVar `yaml:"env,omitempty"`
+}
+
+// PullRequestLabelsFor returns the configurations of the given labels of a pull request
+func (c *ProjectConfig) PullRequestLabelsFor(labels []string) []*PullRequestLabelConfig {
+	answer := []*PullRequestLabelConfig{}
+	for _, config := range c.PullRequestLabels {
+		if config != nil && config.Label != "" && util.StringArrayIndex(labels, config.Label) >= 0 {
+			answer = append(answer, config)
+		}
+	}
+	return answer
+}
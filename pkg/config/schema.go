@@ -0,0 +1,165 @@
+package config
+
+import (
+	"encoding/json"
+	"fmt"
+	"path"
+	"reflect"
+	"strings"
+	"time"
+)
+
+const (
+	// JSONSchemaVersion the version of JSON Schema of the generated schemas
+	JSONSchemaVersion = "http://json-schema.org/draft-07/schema#"
+
+	definitionsRef = "#/definitions/"
+)
+
+var (
+	timeType     = reflect.TypeOf(time.Time{})
+	configPkg    = reflect.TypeOf(ProjectConfig{}).PkgPath()
+	bytesType    = reflect.TypeOf([]byte{})
+	emptySchema  = map[string]interface{}{}
+	stringSchema = map[string]interface{}{"type": "string"}
+)
+
+// schemaGenerator generates the JSON Schema of a type from the fields which gopkg.in/yaml.v2 marshals so that the
+// schema matches the files which are loaded
+type schemaGenerator struct {
+	definitions map[string]interface{}
+	names       map[reflect.Type]string
+}
+
+// ProjectConfigSchema returns the JSON Schema of the jenkins-x.yml file of a project which editors use to validate
+// and complete the file
+func ProjectConfigSchema() ([]byte, error) {
+	g := &schemaGenerator{
+		definitions: map[string]interface{}{},
+		names:       map[reflect.Type]string{},
+	}
+	schema := g.structSchema(reflect.TypeOf(ProjectConfig{}))
+	schema["$schema"] = JSONSchemaVersion
+	schema["title"] = ProjectConfigFileName
+	schema["definitions"] = g.definitions
+	return json.MarshalIndent(schema, "", "  ")
+}
+
+// typeSchema returns the schema of a type referencing the definitions of structs
+func (g *schemaGenerator) typeSchema(t reflect.Type) map[string]interface{} {
+	switch t.Kind() {
+	case reflect.Ptr:
+		return g.typeSchema(t.Elem())
+	case reflect.String:
+		return stringSchema
+	case reflect.Bool:
+		return map[string]interface{}{"type": "boolean"}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return map[string]interface{}{"type": "integer"}
+	case reflect.Float32, reflect.Float64:
+		return map[string]interface{}{"type": "number"}
+	case reflect.Slice, reflect.Array:
+		if t == bytesType {
+			return stringSchema
+		}
+		return map[string]interface{}{
+			"type":  "array",
+			"items": g.typeSchema(t.Elem()),
+		}
+	case reflect.Map:
+		return map[string]interface{}{
+			"type":                 "object",
+			"additionalProperties": g.typeSchema(t.Elem()),
+		}
+	case reflect.Struct:
+		if t == timeType {
+			return map[string]interface{}{"type": "string", "format": "date-time"}
+		}
+		return map[string]interface{}{"$ref": definitionsRef + g.definition(t)}
+	default:
+		return emptySchema
+	}
+}
+
+// definition returns the name of the definition of the struct generating it the first time the struct is used
+func (g *schemaGenerator) definition(t reflect.Type) string {
+	name, ok := g.names[t]
+	if ok {
+		return name
+	}
+	base := path.Base(t.PkgPath()) + "." + t.Name()
+	name = base
+	for i := 2; g.definitions[name] != nil; i++ {
+		name = fmt.Sprintf("%s%d", base, i)
+	}
+	g.names[t] = name
+	// reserve the name before generating the fields of recursive structs
+	g.definitions[name] = emptySchema
+	g.definitions[name] = g.structSchema(t)
+	return name
+}
+
+// structSchema returns the object schema of the fields of a struct
+func (g *schemaGenerator) structSchema(t reflect.Type) map[string]interface{} {
+	properties := map[string]interface{}{}
+	g.addFields(t, properties)
+	schema := map[string]interface{}{
+		"type":       "object",
+		"properties": properties,
+	}
+	// typos in the configuration of Jenkins X are reported whereas other types may be extended by newer versions
+	if t.PkgPath() == configPkg {
+		schema["additionalProperties"] = false
+	}
+	return schema
+}
+
+// addFields adds the schemas of the fields of a struct to the properties using the names of gopkg.in/yaml.v2
+func (g *schemaGenerator) addFields(t reflect.Type, properties map[string]interface{}) {
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		name, inline, ok := yamlField(field)
+		if !ok {
+			continue
+		}
+		if inline {
+			if fieldType := derefType(field.Type); fieldType.Kind() == reflect.Struct {
+				g.addFields(fieldType, properties)
+			}
+			continue
+		}
+		properties[name] = g.typeSchema(field.Type)
+	}
+}
+
+// yamlField returns the key of a struct field in gopkg.in/yaml.v2 and whether its fields are inlined or false if
+// the field is not marshalled
+func yamlField(field reflect.StructField) (string, bool, bool) {
+	if field.PkgPath != "" && !field.Anonymous {
+		return "", false, false
+	}
+	tag := field.Tag.Get("yaml")
+	if tag == "-" {
+		return "", false, false
+	}
+	values := strings.Split(tag, ",")
+	name := values[0]
+	for _, flag := range values[1:] {
+		if flag == "inline" {
+			return name, true, true
+		}
+	}
+	if name == "" {
+		name = strings.ToLower(field.Name)
+	}
+	return name, false, true
+}
+
+// derefType returns the type of the elements of pointers
+func derefType(t reflect.Type) reflect.Type {
+	for t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	return t
+}
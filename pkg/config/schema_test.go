@@ -0,0 +1,43 @@
+package config_test
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/jenkins-x/jx/pkg/config"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestProjectConfigSchema(t *testing.T) {
+	t.Parallel()
+	data, err := config.ProjectConfigSchema()
+	assert.NoError(t, err)
+
+	schema := map[string]interface{}{}
+	err = json.Unmarshal(data, &schema)
+	assert.NoError(t, err)
+	assert.Equal(t, config.JSONSchemaVersion, schema["$schema"])
+	assert.Equal(t, false, schema["additionalProperties"])
+
+	properties, ok := schema["properties"].(map[string]interface{})
+	if !assert.True(t, ok) {
+		return
+	}
+	assert.Equal(t, map[string]interface{}{"type": "string"}, properties["buildPack"])
+	assert.Equal(t, map[string]interface{}{
+		"type":  "array",
+		"items": map[string]interface{}{"$ref": "#/definitions/config.BranchBuild"},
+	}, properties["builds"])
+
+	definitions := schema["definitions"].(map[string]interface{})
+	branchBuild := definitions["config.BranchBuild"].(map[string]interface{})
+	branchBuildProperties := branchBuild["properties"].(map[string]interface{})
+	assert.Equal(t, map[string]interface{}{"type": "string"}, branchBuildProperties["kind"])
+	assert.Contains(t, branchBuildProperties, "build")
+
+	container := definitions["v1.Container"].(map[string]interface{})
+	assert.NotContains(t, container, "additionalProperties")
+	containerProperties := container["properties"].(map[string]interface{})
+	assert.Contains(t, containerProperties, "image", "fields without yaml tags use their lower case names")
+	assert.Contains(t, containerProperties, "workingdir", "fields without yaml tags use their lower case names")
+}
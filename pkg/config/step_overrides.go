@@ -0,0 +1,59 @@
+package config
+
+import (
+	"reflect"
+
+	corev1 "k8s.io/api/core/v1"
+)
+
+const (
+	// StepUnchanged the step of the build pack is used as it is
+	StepUnchanged = ""
+	// StepOverridden the step of the build pack is replaced by the step of the same name of the project
+	StepOverridden = "overridden"
+	// StepAdded the step of the project is not a step of the build pack
+	StepAdded = "added"
+	// StepRemoved the step of the build pack is not a step of the project
+	StepRemoved = "removed"
+)
+
+// StepOverride how a step of a build of the project differs from the step of the same name of the build pack
+type StepOverride struct {
+	Name   string
+	Change string
+}
+
+// StepOverrides compares the steps of a build of the project with the steps of the build of the same kind of the
+// build pack by their names. The steps of the project are returned in their order followed by the removed steps of
+// the build pack. Returns nil if the project uses the steps of the build pack as they are
+func StepOverrides(packSteps []corev1.Container, steps []corev1.Container) []StepOverride {
+	changed := false
+	answer := []StepOverride{}
+	projectSteps := map[string]bool{}
+	for i := range steps {
+		step := &steps[i]
+		projectSteps[step.Name] = true
+		change := StepAdded
+		for j := range packSteps {
+			if packSteps[j].Name == step.Name {
+				change = StepUnchanged
+				if !reflect.DeepEqual(packSteps[j], *step) {
+					change = StepOverridden
+				}
+				break
+			}
+		}
+		changed = changed || change != StepUnchanged
+		answer = append(answer, StepOverride{Name: step.Name, Change: change})
+	}
+	for _, step := range packSteps {
+		if !projectSteps[step.Name] {
+			changed = true
+			answer = append(answer, StepOverride{Name: step.Name, Change: StepRemoved})
+		}
+	}
+	if !changed {
+		return nil
+	}
+	return answer
+}
@@ -0,0 +1,32 @@
+package config_test
+
+import (
+	"testing"
+
+	"github.com/jenkins-x/jx/pkg/config"
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+)
+
+func TestStepOverrides(t *testing.T) {
+	t.Parallel()
+	packSteps := []corev1.Container{
+		{Name: "build", Image: "maven", Args: []string{"mvn", "install"}},
+		{Name: "test", Image: "maven", Args: []string{"mvn", "test"}},
+		{Name: "deploy", Image: "maven", Args: []string{"mvn", "deploy"}},
+	}
+
+	assert.Nil(t, config.StepOverrides(packSteps, packSteps), "the steps of the build pack should not be overridden")
+
+	steps := []corev1.Container{
+		{Name: "build", Image: "maven", Args: []string{"mvn", "install"}},
+		{Name: "lint", Image: "maven", Args: []string{"mvn", "checkstyle:check"}},
+		{Name: "test", Image: "maven", Args: []string{"mvn", "verify"}},
+	}
+	assert.Equal(t, []config.StepOverride{
+		{Name: "build", Change: config.StepUnchanged},
+		{Name: "lint", Change: config.StepAdded},
+		{Name: "test", Change: config.StepOverridden},
+		{Name: "deploy", Change: config.StepRemoved},
+	}, config.StepOverrides(packSteps, steps))
+}
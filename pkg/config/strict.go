@@ -0,0 +1,95 @@
+package config
+
+import (
+	"fmt"
+	"reflect"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/jenkins-x/jx/pkg/util"
+	"gopkg.in/yaml.v2"
+)
+
+// unknownFieldError matches the errors of gopkg.in/yaml.v2 for keys which are not fields of the type they are loaded into
+var unknownFieldError = regexp.MustCompile(`^line \d+: field (\S+) not found in type (\S+)$`)
+
+// UnmarshalStrict loads the YAML into the value failing if it contains keys which are not fields of the value. The
+// errors of misspelled keys suggest the fields of similar names
+func UnmarshalStrict(data []byte, value interface{}) error {
+	err := yaml.UnmarshalStrict(data, value)
+	if err == nil {
+		return nil
+	}
+	typeErr, ok := err.(*yaml.TypeError)
+	if !ok {
+		return err
+	}
+	fields := map[string][]string{}
+	addYamlFields(reflect.TypeOf(value), fields)
+	messages := make([]string, 0, len(typeErr.Errors))
+	for _, message := range typeErr.Errors {
+		messages = append(messages, withFieldSuggestions(message, fields))
+	}
+	return fmt.Errorf("%s", strings.Join(messages, "\n"))
+}
+
+// withFieldSuggestions appends the fields of similar names to the error of an unknown field
+func withFieldSuggestions(message string, fields map[string][]string) string {
+	m := unknownFieldError.FindStringSubmatch(message)
+	if m == nil {
+		return message
+	}
+	suggestions := util.SuggestionsFor(m[1], fields[m[2]], util.DefaultSuggestionsMinimumDistance)
+	switch len(suggestions) {
+	case 0:
+		return message
+	case 1:
+		return fmt.Sprintf("%s. Did you mean: %s", message, suggestions[0])
+	default:
+		return fmt.Sprintf("%s. Did you mean one of: %s", message, strings.Join(suggestions, ", "))
+	}
+}
+
+// addYamlFields adds the keys of the structs reachable from the type indexed by the names of the structs which
+// gopkg.in/yaml.v2 reports in its errors
+func addYamlFields(t reflect.Type, fields map[string][]string) {
+	t = derefType(t)
+	switch t.Kind() {
+	case reflect.Slice, reflect.Array, reflect.Map:
+		addYamlFields(t.Elem(), fields)
+		return
+	case reflect.Struct:
+	default:
+		return
+	}
+	name := t.String()
+	if _, ok := fields[name]; ok {
+		return
+	}
+	// register the struct before its fields so that recursive structs terminate
+	fields[name] = []string{}
+	keys := []string{}
+	addStructKeys(t, &keys, fields)
+	sort.Strings(keys)
+	fields[name] = append(fields[name], keys...)
+}
+
+// addStructKeys adds the keys of the fields of the struct including those of inlined structs
+func addStructKeys(t reflect.Type, keys *[]string, fields map[string][]string) {
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		key, inline, ok := yamlField(field)
+		if !ok {
+			continue
+		}
+		if inline {
+			if fieldType := derefType(field.Type); fieldType.Kind() == reflect.Struct {
+				addStructKeys(fieldType, keys, fields)
+			}
+			continue
+		}
+		*keys = append(*keys, key)
+		addYamlFields(field.Type, fields)
+	}
+}
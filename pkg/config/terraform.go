@@ -0,0 +1,24 @@
+package config
+
+const (
+	// TerraformBuildPack the build pack whose pod template provides the terraform and jx binaries used by the steps
+	// generated for the builds of the infra kind
+	TerraformBuildPack = "terraform"
+
+	// DefaultTerraformLockTimeout how long the generated steps wait for the lock of the Terraform state by default
+	DefaultTerraformLockTimeout = "5m"
+)
+
+// TerraformConfig configures the steps generated for the builds of the infra kind. Pull requests are planned with the
+// plan added as a comment on the pull request and merges are applied. The state must be stored in a backend which
+// supports locking so that only one build changes the infrastructure at a time
+type TerraformConfig struct {
+	// the directory containing the Terraform configuration. Defaults to the root of the repository
+	Dir string `yaml:"dir,omitempty"`
+
+	// the image of the generated steps. Defaults to the image of the terraform build pack
+	Image string `yaml:"image,omitempty"`
+
+	// how long to wait for the lock of the state such as '5m'
+	LockTimeout string `yaml:"lockTimeout,omitempty"`
+}
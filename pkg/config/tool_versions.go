@@ -0,0 +1,92 @@
+package config
+
+import (
+	"bufio"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+)
+
+const (
+	// ToolGo the Go toolchain whose version is the go directive of go.mod
+	ToolGo = "go"
+	// ToolJava the JDK whose version is the content of .java-version
+	ToolJava = "java"
+	// ToolNode the Node.js runtime whose version is the content of .nvmrc or .node-version
+	ToolNode = "node"
+	// ToolPython the Python interpreter whose version is the content of .python-version or the requires-python of
+	// pyproject.toml
+	ToolPython = "python"
+)
+
+// ToolNames the tools whose versions are detected in the order in which they select the image of a build
+var ToolNames = []string{ToolGo, ToolJava, ToolNode, ToolPython}
+
+var (
+	goDirective    = regexp.MustCompile(`^go\s+([0-9][0-9.]*)\s*$`)
+	requiresPython = regexp.MustCompile(`^requires-python\s*=\s*["'][^0-9]*([0-9][0-9.]*)`)
+	toolVersion    = regexp.MustCompile(`^v?([0-9][0-9.]*)$`)
+)
+
+// DetectToolVersions returns the versions of the tools which the files of the project directory such as .nvmrc or
+// go.mod pin indexed by the tool name. Aliases such as 'lts/*' which are not versions are ignored
+func DetectToolVersions(dir string) (map[string]string, error) {
+	answer := map[string]string{}
+	detectors := []struct {
+		tool  string
+		file  string
+		parse func(line string) string
+	}{
+		{ToolGo, "go.mod", matchGroup(goDirective)},
+		{ToolJava, ".java-version", matchGroup(toolVersion)},
+		{ToolNode, ".nvmrc", matchGroup(toolVersion)},
+		{ToolNode, ".node-version", matchGroup(toolVersion)},
+		{ToolPython, ".python-version", matchGroup(toolVersion)},
+		{ToolPython, "pyproject.toml", matchGroup(requiresPython)},
+	}
+	for _, d := range detectors {
+		if answer[d.tool] != "" {
+			continue
+		}
+		version, err := detectVersion(filepath.Join(dir, d.file), d.parse)
+		if err != nil {
+			return answer, err
+		}
+		if version != "" {
+			answer[d.tool] = version
+		}
+	}
+	return answer, nil
+}
+
+// detectVersion returns the first version parsed from the lines of the file or an empty string if the file does not
+// exist or contains no version
+func detectVersion(fileName string, parse func(line string) string) (string, error) {
+	data, err := ioutil.ReadFile(fileName)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return "", nil
+		}
+		return "", err
+	}
+	scanner := bufio.NewScanner(strings.NewReader(string(data)))
+	for scanner.Scan() {
+		version := parse(strings.TrimSpace(scanner.Text()))
+		if version != "" {
+			return version, nil
+		}
+	}
+	return "", nil
+}
+
+func matchGroup(re *regexp.Regexp) func(line string) string {
+	return func(line string) string {
+		groups := re.FindStringSubmatch(line)
+		if len(groups) < 2 {
+			return ""
+		}
+		return strings.TrimSuffix(groups[1], ".")
+	}
+}
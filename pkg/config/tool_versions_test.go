@@ -0,0 +1,47 @@
+package config_test
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/jenkins-x/jx/pkg/config"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDetectToolVersions(t *testing.T) {
+	t.Parallel()
+	dir, err := ioutil.TempDir("", "test-detect-tool-versions")
+	assert.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	files := map[string]string{
+		"go.mod":         "module github.com/myorg/myapp\n\ngo 1.21\n\nrequire github.com/pkg/errors v0.8.0\n",
+		".java-version":  "17\n",
+		".nvmrc":         "v18.17.0\n",
+		"pyproject.toml": "[project]\nname = \"myapp\"\nrequires-python = \">=3.9\"\n",
+	}
+	for name, content := range files {
+		err = ioutil.WriteFile(filepath.Join(dir, name), []byte(content), 0644)
+		assert.NoError(t, err)
+	}
+
+	versions, err := config.DetectToolVersions(dir)
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]string{
+		config.ToolGo:     "1.21",
+		config.ToolJava:   "17",
+		config.ToolNode:   "18.17.0",
+		config.ToolPython: "3.9",
+	}, versions)
+
+	err = ioutil.WriteFile(filepath.Join(dir, ".nvmrc"), []byte("lts/*\n"), 0644)
+	assert.NoError(t, err)
+	err = ioutil.WriteFile(filepath.Join(dir, ".python-version"), []byte("3.11.4\n"), 0644)
+	assert.NoError(t, err)
+	versions, err = config.DetectToolVersions(dir)
+	assert.NoError(t, err)
+	assert.Equal(t, "", versions[config.ToolNode], "aliases should be ignored")
+	assert.Equal(t, "3.11.4", versions[config.ToolPython], ".python-version should take precedence over pyproject.toml")
+}
@@ -0,0 +1,119 @@
+package config
+
+import (
+	"fmt"
+	"regexp"
+
+	"github.com/jenkins-x/jx/pkg/util"
+	corev1 "k8s.io/api/core/v1"
+)
+
+const (
+	// WorkflowPresetEnvironments the default Jenkins X model of releasing master and promoting through environments
+	WorkflowPresetEnvironments = "environments"
+	// WorkflowPresetTrunk trunk based development where every merge to master is released
+	WorkflowPresetTrunk = "trunk"
+	// WorkflowPresetGitFlow GitFlow where develop and feature branches create snapshots and master and release branches are released
+	WorkflowPresetGitFlow = "gitflow"
+
+	// BuildKindRelease the kind of build which releases a new version
+	BuildKindRelease = "release"
+	// BuildKindPullRequest the kind of build which verifies a pull request
+	BuildKindPullRequest = "pullRequest"
+	// BuildKindFeature the kind of build which creates snapshots of a long lived branch
+	BuildKindFeature = "feature"
+	// BuildKindInfra the kind of build which plans the Terraform changes of a pull request and applies them once merged
+	BuildKindInfra = "infra"
+
+	// EnvTagRelease the environment variable which indicates if a build should tag the git repository with its version
+	EnvTagRelease = "JX_TAG_RELEASE"
+	// EnvVersionSuffix the environment variable containing the suffix added to the version of the build such as '-SNAPSHOT'
+	EnvVersionSuffix = "JX_VERSION_SUFFIX"
+)
+
+// BuildKindNames the kinds of build
+var BuildKindNames = []string{BuildKindRelease, BuildKindPullRequest, BuildKindFeature, BuildKindInfra}
+
+// WorkflowPresetNames the names of the workflow presets
+var WorkflowPresetNames = []string{WorkflowPresetEnvironments, WorkflowPresetTrunk, WorkflowPresetGitFlow}
+
+// WorkflowPreset defines which kinds of builds are generated for each branch and how versioning behaves
+type WorkflowPreset struct {
+	Name string
+
+	// Kinds the kinds of build generated for the branches they match
+	Kinds []WorkflowPresetKind
+}
+
+// WorkflowPresetKind a kind of build generated by a workflow preset for the matching branches
+type WorkflowPresetKind struct {
+	Kind          string
+	Branches      string
+	TagRelease    bool
+	VersionSuffix string
+}
+
+// GetWorkflowPreset returns the workflow preset of the given name
+func GetWorkflowPreset(name string) (*WorkflowPreset, error) {
+	switch name {
+	case WorkflowPresetEnvironments:
+		return &WorkflowPreset{
+			Name: name,
+			Kinds: []WorkflowPresetKind{
+				{Kind: BuildKindRelease, Branches: "master", TagRelease: true},
+				{Kind: BuildKindPullRequest, Branches: "PR-.*"},
+			},
+		}, nil
+	case WorkflowPresetTrunk:
+		return &WorkflowPreset{
+			Name: name,
+			Kinds: []WorkflowPresetKind{
+				{Kind: BuildKindRelease, Branches: "master|v\\d+\\.\\d+\\.\\d+", TagRelease: true},
+				{Kind: BuildKindPullRequest, Branches: "PR-.*"},
+			},
+		}, nil
+	case WorkflowPresetGitFlow:
+		return &WorkflowPreset{
+			Name: name,
+			Kinds: []WorkflowPresetKind{
+				{Kind: BuildKindRelease, Branches: "master|release/.*|hotfix/.*", TagRelease: true},
+				{Kind: BuildKindFeature, Branches: "develop|feature/.*", VersionSuffix: "-SNAPSHOT"},
+				{Kind: BuildKindPullRequest, Branches: "PR-.*"},
+			},
+		}, nil
+	}
+	return nil, util.InvalidOption("workflow", name, WorkflowPresetNames)
+}
+
+// KindsForBranch returns the kinds of build to generate for the given branch or all of the kinds if the branch is empty
+func (p *WorkflowPreset) KindsForBranch(branch string) ([]*WorkflowPresetKind, error) {
+	answer := []*WorkflowPresetKind{}
+	for i := range p.Kinds {
+		k := &p.Kinds[i]
+		if branch != "" {
+			r, err := regexp.Compile("^(?:" + k.Branches + ")$")
+			if err != nil {
+				return answer, fmt.Errorf("Failed to parse the branch regular expression %s due to %s", k.Branches, err)
+			}
+			if !r.MatchString(branch) {
+				continue
+			}
+		}
+		answer = append(answer, k)
+	}
+	return answer, nil
+}
+
+// EnvVars returns the environment variables added to the steps of this kind of build to control versioning
+func (k *WorkflowPresetKind) EnvVars() []corev1.EnvVar {
+	return []corev1.EnvVar{
+		{
+			Name:  EnvTagRelease,
+			Value: fmt.Sprintf("%t", k.TagRelease),
+		},
+		{
+			Name:  EnvVersionSuffix,
+			Value: k.VersionSuffix,
+		},
+	}
+}
@@ -0,0 +1,32 @@
+package config_test
+
+import (
+	"testing"
+
+	"github.com/jenkins-x/jx/pkg/config"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWorkflowPresetKindsForBranch(t *testing.T) {
+	t.Parallel()
+	preset, err := config.GetWorkflowPreset(config.WorkflowPresetGitFlow)
+	assert.NoError(t, err)
+
+	assertKinds := func(branch string, expected ...string) {
+		kinds, err := preset.KindsForBranch(branch)
+		assert.NoError(t, err)
+		var actual []string
+		for _, k := range kinds {
+			actual = append(actual, k.Kind)
+		}
+		assert.Equal(t, expected, actual, "kinds for branch %s", branch)
+	}
+	assertKinds("", config.BuildKindRelease, config.BuildKindFeature, config.BuildKindPullRequest)
+	assertKinds("release/1.2", config.BuildKindRelease)
+	assertKinds("feature/cheese", config.BuildKindFeature)
+	assertKinds("PR-12", config.BuildKindPullRequest)
+	assertKinds("wip")
+
+	_, err = config.GetWorkflowPreset("does-not-exist")
+	assert.Error(t, err)
+}
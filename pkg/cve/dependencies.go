@@ -0,0 +1,117 @@
+package cve
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"strings"
+
+	"github.com/jenkins-x/jx/pkg/config"
+	"github.com/jenkins-x/jx/pkg/util"
+)
+
+// DependencyVulnerability a known vulnerability or license issue of a dependency of a project
+type DependencyVulnerability struct {
+	// ID the identifier of the vulnerability such as a CVE
+	ID string
+	// Dependency the dependency such as a jar file or package and version
+	Dependency string
+	// Severity the normalized severity such as 'high'
+	Severity string
+}
+
+// owaspReport the JSON report of OWASP dependency-check
+type owaspReport struct {
+	Dependencies []struct {
+		FileName        string `json:"fileName"`
+		Vulnerabilities []struct {
+			Name     string `json:"name"`
+			Severity string `json:"severity"`
+		} `json:"vulnerabilities"`
+	} `json:"dependencies"`
+}
+
+// snykReport the JSON report of snyk test for a single project
+type snykReport struct {
+	Vulnerabilities []struct {
+		ID          string `json:"id"`
+		Severity    string `json:"severity"`
+		PackageName string `json:"packageName"`
+		Version     string `json:"version"`
+	} `json:"vulnerabilities"`
+}
+
+// LoadDependencyReport loads the vulnerabilities of the JSON report of the dependency scanning tool
+func LoadDependencyReport(tool string, fileName string) ([]DependencyVulnerability, error) {
+	data, err := ioutil.ReadFile(fileName)
+	if err != nil {
+		return nil, err
+	}
+	answer := []DependencyVulnerability{}
+	switch tool {
+	case config.DependencyScanOWASP:
+		report := owaspReport{}
+		err = json.Unmarshal(data, &report)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse the OWASP dependency-check report %s: %s", fileName, err)
+		}
+		for _, d := range report.Dependencies {
+			for _, v := range d.Vulnerabilities {
+				answer = append(answer, DependencyVulnerability{
+					ID:         v.Name,
+					Dependency: d.FileName,
+					Severity:   NormalizeSeverity(v.Severity),
+				})
+			}
+		}
+	case config.DependencyScanSnyk:
+		// snyk writes an array of reports when it tests all the projects of a repository
+		reports := []snykReport{}
+		if strings.HasPrefix(strings.TrimSpace(string(data)), "[") {
+			err = json.Unmarshal(data, &reports)
+		} else {
+			report := snykReport{}
+			err = json.Unmarshal(data, &report)
+			reports = append(reports, report)
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse the snyk report %s: %s", fileName, err)
+		}
+		for _, report := range reports {
+			for _, v := range report.Vulnerabilities {
+				answer = append(answer, DependencyVulnerability{
+					ID:         v.ID,
+					Dependency: v.PackageName + "@" + v.Version,
+					Severity:   NormalizeSeverity(v.Severity),
+				})
+			}
+		}
+	default:
+		return nil, util.InvalidOption("tool", tool, config.DependencyScanTools)
+	}
+	return answer, nil
+}
+
+// NormalizeSeverity returns the severity of the config package of the severity of a tool such as 'HIGH' or 'moderate'
+func NormalizeSeverity(severity string) string {
+	severity = strings.ToLower(severity)
+	if severity == "moderate" {
+		return config.SeverityMedium
+	}
+	return severity
+}
+
+// AtLeast returns the vulnerabilities whose severity is at least the threshold
+func AtLeast(vulnerabilities []DependencyVulnerability, threshold string) []DependencyVulnerability {
+	answer := []DependencyVulnerability{}
+	min := util.StringArrayIndex(config.Severities, threshold)
+	if min < 0 {
+		return answer
+	}
+	for _, v := range vulnerabilities {
+		if util.StringArrayIndex(config.Severities, v.Severity) >= min {
+			answer = append(answer, v)
+		}
+	}
+	return answer
+}
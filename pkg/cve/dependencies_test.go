@@ -0,0 +1,36 @@
+package cve_test
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/jenkins-x/jx/pkg/config"
+	"github.com/jenkins-x/jx/pkg/cve"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLoadDependencyReport(t *testing.T) {
+	t.Parallel()
+	vulnerabilities, err := cve.LoadDependencyReport(config.DependencyScanOWASP, filepath.Join("test_data", "dependencies", "dependency-check-report.json"))
+	assert.NoError(t, err)
+	assert.Equal(t, []cve.DependencyVulnerability{
+		{ID: "CVE-2018-7489", Dependency: "jackson-databind-2.9.5.jar", Severity: config.SeverityCritical},
+		{ID: "CVE-2018-14719", Dependency: "jackson-databind-2.9.5.jar", Severity: config.SeverityHigh},
+		{ID: "CVE-2018-10237", Dependency: "guava-20.0.jar", Severity: config.SeverityMedium},
+	}, vulnerabilities)
+	assert.Len(t, cve.AtLeast(vulnerabilities, config.SeverityHigh), 2)
+	assert.Len(t, cve.AtLeast(vulnerabilities, config.SeverityCritical), 1)
+	assert.Len(t, cve.AtLeast(vulnerabilities, config.SeverityNone), 0)
+
+	vulnerabilities, err = cve.LoadDependencyReport(config.DependencyScanSnyk, filepath.Join("test_data", "dependencies", "snyk.json"))
+	assert.NoError(t, err)
+	if assert.Len(t, vulnerabilities, 3) {
+		assert.Equal(t, "lodash@4.17.15", vulnerabilities[0].Dependency)
+		assert.Equal(t, config.SeverityMedium, vulnerabilities[1].Severity, "license issues should be reported")
+		assert.Equal(t, config.SeverityMedium, vulnerabilities[2].Severity, "moderate should be normalized to medium")
+	}
+	assert.Len(t, cve.AtLeast(vulnerabilities, config.SeverityMedium), 3)
+
+	_, err = cve.LoadDependencyReport("clair", filepath.Join("test_data", "dependencies", "snyk.json"))
+	assert.Error(t, err)
+}
@@ -0,0 +1,301 @@
+package draft
+
+import (
+	"fmt"
+	"io/ioutil"
+	"path"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"github.com/jenkins-x/jx/pkg/config"
+	"github.com/jenkins-x/jx/pkg/kube"
+	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
+)
+
+// JenkinsfileName the name of the file of a draft pack containing its declarative pipeline
+const JenkinsfileName = "Jenkinsfile"
+
+const (
+	blockOther = iota
+	blockStage
+	blockWhen
+	blockSteps
+	blockContainer
+	blockDir
+	blockEnvironment
+)
+
+var (
+	blockHeader      = regexp.MustCompile(`^(\w+)\s*(?:\((.*)\))?$`)
+	envAssignment    = regexp.MustCompile(`^(\w+)\s*=\s*(.+)$`)
+	groovyEnvBinding = regexp.MustCompile(`\$\{env\.(\w+)\}`)
+)
+
+// packBlock a block of the declarative pipeline such as a stage or a dir
+type packBlock struct {
+	kind int
+	arg  string
+}
+
+// packStage a stage of the declarative pipeline
+type packStage struct {
+	name     string
+	branches []string
+	env      []corev1.EnvVar
+	steps    []corev1.Container
+}
+
+// ConvertPack converts the Jenkinsfile of the legacy draft pack in the given directory into the equivalent builds of
+// a project configuration. The warnings describe the parts of the pipeline which could not be converted
+func ConvertPack(packDir string) (*config.ProjectConfig, []string, error) {
+	fileName := filepath.Join(packDir, JenkinsfileName)
+	data, err := ioutil.ReadFile(fileName)
+	if err != nil {
+		return nil, nil, errors.Wrapf(err, "failed to load the Jenkinsfile of the pack %s", packDir)
+	}
+	pc, warnings, err := ConvertJenkinsfile(string(data))
+	if err != nil {
+		return nil, warnings, errors.Wrapf(err, "failed to convert %s", fileName)
+	}
+	return pc, warnings, nil
+}
+
+// ConvertJenkinsfile converts the declarative pipeline of a draft pack into the equivalent builds of a project
+// configuration. Stages for the 'PR-*' branches become steps of the pullRequest build, stages for the 'master' branch
+// steps of the release build and stages without a when clause steps of both. Each 'sh' becomes a step which runs in
+// the image of the pod template of the pack
+func ConvertJenkinsfile(source string) (*config.ProjectConfig, []string, error) {
+	pc := &config.ProjectConfig{}
+	warnings := []string{}
+	warnf := func(line int, format string, args ...interface{}) {
+		warnings = append(warnings, fmt.Sprintf("line %d: %s", line, fmt.Sprintf(format, args...)))
+	}
+	builds := map[string]*config.BranchBuild{}
+	kinds := []string{config.BuildKindPullRequest, config.BuildKindRelease}
+
+	var stack []packBlock
+	var stage *packStage
+	lines := strings.Split(source, "\n")
+	for i := 0; i < len(lines); i++ {
+		lineNumber := i + 1
+		line := strings.TrimSpace(lines[i])
+		if line == "" || strings.HasPrefix(line, "//") {
+			continue
+		}
+		if strings.HasPrefix(line, "}") {
+			if len(stack) == 0 {
+				return nil, warnings, fmt.Errorf("line %d: unexpected }", lineNumber)
+			}
+			block := stack[len(stack)-1]
+			stack = stack[:len(stack)-1]
+			if block.kind == blockStage && stage != nil {
+				addStage(builds, kinds, stage, func(format string, args ...interface{}) {
+					warnf(lineNumber, format, args...)
+				})
+				stage = nil
+			}
+			continue
+		}
+		if strings.HasSuffix(line, "{") {
+			block := packBlock{kind: blockOther}
+			m := blockHeader.FindStringSubmatch(strings.TrimSpace(strings.TrimSuffix(line, "{")))
+			if m != nil {
+				arg, _, _ := groovyString(strings.TrimSpace(m[2]))
+				block.arg = arg
+				switch m[1] {
+				case "stage":
+					block.kind = blockStage
+					stage = &packStage{name: arg}
+				case "when":
+					block.kind = blockWhen
+				case "steps":
+					block.kind = blockSteps
+				case "container":
+					block.kind = blockContainer
+				case "dir":
+					block.kind = blockDir
+				case "environment":
+					block.kind = blockEnvironment
+				}
+			}
+			stack = append(stack, block)
+			continue
+		}
+		if len(stack) == 0 {
+			continue
+		}
+		switch stack[len(stack)-1].kind {
+		case blockEnvironment:
+			m := envAssignment.FindStringSubmatch(line)
+			if m == nil {
+				warnf(lineNumber, "ignoring the environment entry %s", line)
+				continue
+			}
+			value, rest, ok := groovyString(m[2])
+			if !ok || rest != "" {
+				warnf(lineNumber, "ignoring the environment variable %s as its value %s is not a string literal", m[1], m[2])
+				continue
+			}
+			env := corev1.EnvVar{Name: m[1], Value: value}
+			if stage != nil {
+				stage.env = append(stage.env, env)
+			} else {
+				pc.Env = append(pc.Env, env)
+			}
+		case blockWhen:
+			if stage == nil || !strings.HasPrefix(line, "branch ") {
+				warnf(lineNumber, "ignoring the condition %s", line)
+				continue
+			}
+			branch, _, ok := groovyString(strings.TrimSpace(strings.TrimPrefix(line, "branch ")))
+			if !ok {
+				warnf(lineNumber, "ignoring the condition %s", line)
+				continue
+			}
+			stage.branches = append(stage.branches, branch)
+		case blockSteps, blockContainer, blockDir:
+			if stage == nil {
+				continue
+			}
+			if !strings.HasPrefix(line, "sh ") && !strings.HasPrefix(line, "sh(") {
+				warnf(lineNumber, "ignoring the step %s", line)
+				continue
+			}
+			arg := strings.TrimSpace(strings.TrimPrefix(line, "sh"))
+			script, ok := shScript(arg)
+			// multi-line scripts continue until the closing triple quote
+			for !ok && isTripleQuoted(arg) && i+1 < len(lines) {
+				i++
+				arg += "\n" + lines[i]
+				script, ok = shScript(arg)
+			}
+			if !ok {
+				warnf(lineNumber, "ignoring the step %s as its script is not a string literal", line)
+				continue
+			}
+			stage.steps = append(stage.steps, corev1.Container{
+				Name:       fmt.Sprintf("%s-%d", kube.ToValidName(stage.name), len(stage.steps)+1),
+				Command:    []string{"/bin/sh"},
+				Args:       []string{"-c", script},
+				WorkingDir: workingDir(stack),
+				Env:        stage.env,
+			})
+		}
+	}
+	if len(stack) > 0 {
+		return nil, warnings, fmt.Errorf("missing } of %d blocks", len(stack))
+	}
+	for _, kind := range kinds {
+		if build := builds[kind]; build != nil {
+			pc.Builds = append(pc.Builds, build)
+		}
+	}
+	return pc, warnings, nil
+}
+
+// addStage appends the steps of the stage to the builds of the kinds of branches of the stage
+func addStage(builds map[string]*config.BranchBuild, kinds []string, stage *packStage, warnf func(format string, args ...interface{})) {
+	if len(stage.steps) == 0 {
+		return
+	}
+	stageKinds := []string{}
+	if len(stage.branches) == 0 {
+		stageKinds = kinds
+	}
+	for _, branch := range stage.branches {
+		switch branch {
+		case "PR-*":
+			stageKinds = append(stageKinds, config.BuildKindPullRequest)
+		case "master":
+			stageKinds = append(stageKinds, config.BuildKindRelease)
+		default:
+			warnf("ignoring the branch %s of the stage %s", branch, stage.name)
+		}
+	}
+	for _, kind := range stageKinds {
+		build := builds[kind]
+		if build == nil {
+			build = &config.BranchBuild{Kind: kind}
+			builds[kind] = build
+		}
+		build.Build.Steps = append(build.Build.Steps, stage.steps...)
+	}
+}
+
+// workingDir returns the working directory of the steps in the dir blocks of the stack
+func workingDir(stack []packBlock) string {
+	dirs := []string{}
+	for _, block := range stack {
+		if block.kind == blockDir {
+			dirs = append(dirs, block.arg)
+		}
+	}
+	if len(dirs) == 0 {
+		return ""
+	}
+	return path.Join(append([]string{"/workspace"}, dirs...)...)
+}
+
+// shScript returns the script of the argument of an sh step such as "make", ('make') or (script: 'make')
+func shScript(arg string) (string, bool) {
+	if strings.HasPrefix(arg, "(") {
+		if !strings.HasSuffix(arg, ")") {
+			return "", false
+		}
+		arg = strings.TrimSpace(arg[1 : len(arg)-1])
+	}
+	arg = strings.TrimSpace(strings.TrimPrefix(arg, "script:"))
+	script, rest, ok := groovyString(arg)
+	if !ok || rest != "" {
+		return "", false
+	}
+	if strings.HasPrefix(arg, `"`) {
+		script = groovyEnvBinding.ReplaceAllString(script, "$${$1}")
+	}
+	return script, true
+}
+
+// isTripleQuoted returns true if the argument of an sh step starts with a multi-line string
+func isTripleQuoted(arg string) bool {
+	arg = strings.TrimSpace(strings.TrimPrefix(arg, "("))
+	arg = strings.TrimSpace(strings.TrimPrefix(arg, "script:"))
+	return strings.HasPrefix(arg, `"""`) || strings.HasPrefix(arg, `'''`)
+}
+
+// groovyString parses the Groovy string literal at the start of the text returning its unescaped value and the
+// remaining text after the closing quote
+func groovyString(text string) (string, string, bool) {
+	quote := ""
+	for _, q := range []string{`"""`, `'''`, `"`, `'`} {
+		if strings.HasPrefix(text, q) {
+			quote = q
+			break
+		}
+	}
+	if quote == "" {
+		return "", text, false
+	}
+	var buf strings.Builder
+	for i := len(quote); i < len(text); i++ {
+		c := text[i]
+		if c == '\\' && i+1 < len(text) {
+			i++
+			switch text[i] {
+			case 'n':
+				buf.WriteByte('\n')
+			case 't':
+				buf.WriteByte('\t')
+			default:
+				buf.WriteByte(text[i])
+			}
+			continue
+		}
+		if strings.HasPrefix(text[i:], quote) {
+			return buf.String(), strings.TrimSpace(text[i+len(quote):]), true
+		}
+		buf.WriteByte(c)
+	}
+	return "", text, false
+}
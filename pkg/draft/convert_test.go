@@ -0,0 +1,93 @@
+package draft_test
+
+import (
+	"testing"
+
+	"github.com/jenkins-x/jx/pkg/config"
+	"github.com/jenkins-x/jx/pkg/draft"
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+)
+
+func TestConvertPack(t *testing.T) {
+	t.Parallel()
+	pc, warnings, err := draft.ConvertPack("test_data")
+	assert.NoError(t, err)
+	if !assert.NotNil(t, pc) {
+		return
+	}
+	assert.Equal(t, []corev1.EnvVar{{Name: "ORG", Value: "jstrachan"}, {Name: "APP_NAME", Value: "mynode1"}}, pc.Env)
+	assert.Len(t, warnings, 3, "warnings %v", warnings)
+
+	if !assert.Len(t, pc.Builds, 2) {
+		return
+	}
+	pr := pc.Builds[0]
+	assert.Equal(t, config.BuildKindPullRequest, pr.Kind)
+	steps := pr.Build.Steps
+	if assert.Len(t, steps, 7) {
+		assert.Equal(t, "ci-build-and-push-snapshot-1", steps[0].Name)
+		assert.Equal(t, []string{"/bin/sh"}, steps[0].Command)
+		assert.Equal(t, []string{"-c", "npm install"}, steps[0].Args)
+		assert.Equal(t, "", steps[0].WorkingDir)
+		assert.Equal(t, []corev1.EnvVar{{Name: "PREVIEW_VERSION", Value: "0.0.0-SNAPSHOT-$BRANCH_NAME-$BUILD_NUMBER"}}, steps[0].Env)
+		assert.Equal(t, "jx step post build --image $JENKINS_X_DOCKER_REGISTRY_SERVICE_HOST:$JENKINS_X_DOCKER_REGISTRY_SERVICE_PORT/$ORG/$APP_NAME:$PREVIEW_VERSION", steps[4].Args[1])
+		assert.Equal(t, "/workspace/charts/preview", steps[5].WorkingDir)
+		assert.Equal(t, "make preview", steps[5].Args[1])
+	}
+
+	release := pc.Builds[1]
+	assert.Equal(t, config.BuildKindRelease, release.Kind)
+	steps = release.Build.Steps
+	if assert.Len(t, steps, 14) {
+		assert.Equal(t, "build-release-1", steps[0].Name)
+		assert.Equal(t, "git checkout master", steps[0].Args[1])
+		assert.Equal(t, "echo $(jx-release-version) > VERSION", steps[4].Args[1])
+		assert.Equal(t, "/workspace/charts/mynode1", steps[5].WorkingDir)
+		assert.Equal(t, "promote-to-environments-1", steps[11].Name)
+		assert.Equal(t, "jx step changelog --version v$(cat ../../VERSION)", steps[11].Args[1])
+	}
+}
+
+func TestConvertJenkinsfile(t *testing.T) {
+	t.Parallel()
+	pc, warnings, err := draft.ConvertJenkinsfile(`pipeline {
+  agent any
+  stages {
+    stage('Build') {
+      steps {
+        sh """
+          make build
+          make test
+        """
+        sh(script: "echo \${env.BUILD_NUMBER}")
+        sh script: 'make', returnStdout: true
+        echo "done"
+      }
+    }
+    stage('Develop') {
+      when {
+        branch 'develop'
+      }
+      steps {
+        sh 'make develop'
+      }
+    }
+  }
+}
+`)
+	assert.NoError(t, err)
+	assert.Len(t, warnings, 3, "warnings %v", warnings)
+	if assert.Len(t, pc.Builds, 2) {
+		for _, build := range pc.Builds {
+			steps := build.Build.Steps
+			if assert.Len(t, steps, 2, "kind %s", build.Kind) {
+				assert.Equal(t, "\n          make build\n          make test\n        ", steps[0].Args[1])
+				assert.Equal(t, "echo ${BUILD_NUMBER}", steps[1].Args[1])
+			}
+		}
+	}
+
+	_, _, err = draft.ConvertJenkinsfile("pipeline {\n  stages {\n")
+	assert.Error(t, err)
+}
@@ -820,6 +820,15 @@ func (b *BitbucketCloudProvider) AddPRComment(pr *GitPullRequest, comment string
 	return nil
 }
 
+func (b *BitbucketCloudProvider) ListPRComments(pr *GitPullRequest) ([]*GitPRComment, error) {
+	return []*GitPRComment{}, nil
+}
+
+func (b *BitbucketCloudProvider) UpdatePRComment(pr *GitPullRequest, id int64, comment string) error {
+	log.Warn("Bitbucket Cloud doesn't support updating PR comments via the REST API")
+	return nil
+}
+
 func (b *BitbucketCloudProvider) CreateIssueComment(owner string, repo string, number int, comment string) error {
 	log.Warn("Bitbucket Cloud doesn't support adding issue comments viea the REST API")
 	return nil
@@ -735,6 +735,15 @@ func (b *BitbucketServerProvider) AddPRComment(pr *GitPullRequest, comment strin
 	return nil
 }
 
+func (b *BitbucketServerProvider) ListPRComments(pr *GitPullRequest) ([]*GitPRComment, error) {
+	return []*GitPRComment{}, nil
+}
+
+func (b *BitbucketServerProvider) UpdatePRComment(pr *GitPullRequest, id int64, comment string) error {
+	log.Warn("Bitbucket Server doesn't support updating PR comments via the REST API")
+	return nil
+}
+
 func (b *BitbucketServerProvider) CreateIssueComment(owner string, repo string, number int, comment string) error {
 	log.Warn("Bitbucket Server doesn't support adding issue comments via the REST API")
 	return nil
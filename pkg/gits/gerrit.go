@@ -136,6 +136,14 @@ func (p *GerritProvider) AddPRComment(pr *GitPullRequest, comment string) error
 	return nil
 }
 
+func (p *GerritProvider) ListPRComments(pr *GitPullRequest) ([]*GitPRComment, error) {
+	return []*GitPRComment{}, nil
+}
+
+func (p *GerritProvider) UpdatePRComment(pr *GitPullRequest, id int64, comment string) error {
+	return nil
+}
+
 func (p *GerritProvider) CreateIssueComment(owner string, repo string, number int, comment string) error {
 	return nil
 }
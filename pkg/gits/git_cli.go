@@ -2,15 +2,20 @@ package gits
 
 import (
 	"bytes"
+	"context"
+	"encoding/base64"
 	"fmt"
 	"io"
 	"io/ioutil"
 	"net/url"
 	"os"
 	"path/filepath"
+	"sort"
+	"strconv"
 	"strings"
 	"time"
 
+	"github.com/blang/semver"
 	"github.com/jenkins-x/jx/pkg/auth"
 	"github.com/jenkins-x/jx/pkg/util"
 
@@ -19,16 +24,119 @@ import (
 
 const (
 	replaceInvalidBranchChars = '_'
+
+	// ConfigEnvMinVersion the first version of git which reads the configuration of NewGitCLIWithConfig from the
+	// GIT_CONFIG_COUNT, GIT_CONFIG_KEY_<n> and GIT_CONFIG_VALUE_<n> environment variables. Older versions silently
+	// ignore them
+	ConfigEnvMinVersion = "2.31.0"
 )
 
 // GitCLI implements common git actions based on git CLI
-type GitCLI struct{}
+type GitCLI struct {
+	// env the environment variables of the git commands such as the configuration of a proxy
+	env map[string]string
+	// ctx the context which kills the git commands when it is cancelled
+	ctx context.Context
+}
 
 // NewGitCLI creates a new GitCLI instance
 func NewGitCLI() *GitCLI {
 	return &GitCLI{}
 }
 
+// NewGitCLIWithConfig creates a new GitCLI instance which runs git with the configuration such as 'http.proxy' in
+// addition to the configuration files of git. The configuration is passed in the environment rather than the command
+// line so that credentials are not visible to other users
+func NewGitCLIWithConfig(config map[string]string) *GitCLI {
+	return NewGitCLI().WithConfig(config)
+}
+
+// WithConfig returns a copy of the GitCLI which also runs git with the configuration such as the credentials of a git
+// server. The configuration is passed in the environment like NewGitCLIWithConfig
+func (g *GitCLI) WithConfig(config map[string]string) *GitCLI {
+	env := map[string]string{}
+	for k, v := range g.env {
+		env[k] = v
+	}
+	keys := []string{}
+	for k := range config {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	count, _ := strconv.Atoi(env["GIT_CONFIG_COUNT"])
+	for _, k := range keys {
+		env[fmt.Sprintf("GIT_CONFIG_KEY_%d", count)] = k
+		env[fmt.Sprintf("GIT_CONFIG_VALUE_%d", count)] = config[k]
+		count++
+	}
+	env["GIT_CONFIG_COUNT"] = strconv.Itoa(count)
+	return &GitCLI{
+		env: env,
+		ctx: g.ctx,
+	}
+}
+
+// WithContext returns a copy of the GitCLI whose git commands are killed when the context is cancelled such as when a
+// command times out so that the command does not return while git is still writing into a directory
+func (g *GitCLI) WithContext(ctx context.Context) *GitCLI {
+	return &GitCLI{
+		env: g.env,
+		ctx: ctx,
+	}
+}
+
+// VerifyConfigEnv returns an error if git is older than ConfigEnvMinVersion as the configuration of
+// NewGitCLIWithConfig would be silently ignored
+func (g *GitCLI) VerifyConfigEnv() error {
+	output, err := g.Version()
+	if err != nil {
+		return err
+	}
+	version, err := ParseGitVersion(output)
+	if err != nil {
+		return err
+	}
+	if version.LT(semver.MustParse(ConfigEnvMinVersion)) {
+		return fmt.Errorf("git %s or later is required but the version is %s", ConfigEnvMinVersion, version)
+	}
+	return nil
+}
+
+// ParseGitVersion parses the output of 'git version' such as 'git version 2.20.1 (Apple Git-117)' or
+// 'git version 2.31.1.windows.1'
+func ParseGitVersion(output string) (semver.Version, error) {
+	fields := strings.Fields(output)
+	if len(fields) < 3 || fields[0] != "git" || fields[1] != "version" {
+		return semver.Version{}, fmt.Errorf("failed to parse the git version %s", output)
+	}
+	parts := strings.Split(fields[2], ".")
+	if len(parts) > 3 {
+		parts = parts[0:3]
+	}
+	version, err := semver.ParseTolerant(strings.Join(parts, "."))
+	if err != nil {
+		return semver.Version{}, fmt.Errorf("failed to parse the git version %s: %s", output, err)
+	}
+	return version, nil
+}
+
+// WithoutPrompts returns a copy of the GitCLI whose git commands fail rather than prompt on the terminal for the
+// credentials of a git server or to confirm the host key of an SSH server so that commands in batch mode never hang
+func (g *GitCLI) WithoutPrompts() *GitCLI {
+	env := map[string]string{}
+	for k, v := range g.env {
+		env[k] = v
+	}
+	env["GIT_TERMINAL_PROMPT"] = "0"
+	if os.Getenv("GIT_SSH_COMMAND") == "" && os.Getenv("GIT_SSH") == "" {
+		env["GIT_SSH_COMMAND"] = "ssh -o BatchMode=yes"
+	}
+	return &GitCLI{
+		env: env,
+		ctx: g.ctx,
+	}
+}
+
 // FindGitConfigDir tries to find the `.git` directory either in the current directory or in parent directories
 func (g *GitCLI) FindGitConfigDir(dir string) (string, string, error) {
 	d := dir
@@ -62,7 +170,7 @@ func (g *GitCLI) FindGitConfigDir(dir string) (string, string, error) {
 
 // Clone clones the given git URL into the given directory
 func (g *GitCLI) Clone(url string, dir string) error {
-	return g.gitCmd(dir, "clone", url, ".")
+	return g.gitCmd(dir, "clone", "--", url, ".")
 }
 
 // Pull pulls the Git repository in the given directory
@@ -155,7 +263,8 @@ func (g *GitCLI) RemoteBranches(dir string) ([]string, error) {
 
 // Checkout checks out the given branch
 func (g *GitCLI) Checkout(dir string, branch string) error {
-	return g.gitCmd(dir, "checkout", branch)
+	// the branch is followed by -- so that it is never taken for a path
+	return g.gitCmd(dir, "checkout", branch, "--")
 }
 
 // Checkout checks out the given branch
@@ -253,9 +362,11 @@ func (g *GitCLI) AddCommmit(dir string, msg string) error {
 
 func (g *GitCLI) gitCmd(dir string, args ...string) error {
 	cmd := util.Command{
-		Dir:  dir,
-		Name: "git",
-		Args: args,
+		Dir:     dir,
+		Name:    "git",
+		Args:    args,
+		Env:     g.env,
+		Context: g.ctx,
 	}
 	_, err := cmd.RunWithoutRetry()
 	return err
@@ -263,13 +374,32 @@ func (g *GitCLI) gitCmd(dir string, args ...string) error {
 
 func (g *GitCLI) gitCmdWithOutput(dir string, args ...string) (string, error) {
 	cmd := util.Command{
-		Dir:  dir,
-		Name: "git",
-		Args: args,
+		Dir:     dir,
+		Name:    "git",
+		Args:    args,
+		Env:     g.env,
+		Context: g.ctx,
 	}
 	return cmd.RunWithoutRetry()
 }
 
+// CredentialsConfig returns the git configuration which authenticates the HTTP requests to the git server of the URL
+// with the username and API token of the user. The credentials are sent in a header rather than in the URL so that they
+// are neither visible in the command line of git nor written into the remote of a clone
+func CredentialsConfig(gitURL string, userAuth *auth.UserAuth) (map[string]string, error) {
+	u, err := url.Parse(gitURL)
+	if err != nil {
+		return nil, err
+	}
+	if u.Scheme != "http" && u.Scheme != "https" || u.Host == "" {
+		return nil, fmt.Errorf("the git URL %s is not an http or https URL", gitURL)
+	}
+	credentials := base64.StdEncoding.EncodeToString([]byte(userAuth.Username + ":" + userAuth.ApiToken))
+	return map[string]string{
+		fmt.Sprintf("http.%s://%s/.extraheader", u.Scheme, u.Host): "Authorization: Basic " + credentials,
+	}, nil
+}
+
 // CreatePushURL creates the Git repository URL with the username and password encoded for HTTPS based URLs
 func (g *GitCLI) CreatePushURL(cloneURL string, userAuth *auth.UserAuth) (string, error) {
 	u, err := url.Parse(cloneURL)
@@ -498,6 +628,16 @@ func (g *GitCLI) GetCurrentGitTagSHA(dir string) (string, error) {
 	return g.gitCmdWithOutput(dir, "rev-list", "--tags", "--max-count=1")
 }
 
+// GetLatestCommitSha returns the SHA of the commit checked out in the given directory
+func (g *GitCLI) GetLatestCommitSha(dir string) (string, error) {
+	return g.gitCmdWithOutput(dir, "rev-parse", "HEAD")
+}
+
+// GetLatestCommitMessage returns the message of the commit checked out in the given directory
+func (g *GitCLI) GetLatestCommitMessage(dir string) (string, error) {
+	return g.gitCmdWithOutput(dir, "log", "-1", "--pretty=%B")
+}
+
 // FetchTags fetches all the tags
 func (g *GitCLI) FetchTags(dir string) error {
 	return g.gitCmd("", "fetch", "--tags", "-v")
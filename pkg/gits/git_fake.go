@@ -364,6 +364,22 @@ func (g *GitFake) GetPreviousGitTagSHA(dir string) (string, error) {
 	return g.Commits[len-2].SHA, nil
 }
 
+func (g *GitFake) GetLatestCommitSha(dir string) (string, error) {
+	len := len(g.Commits)
+	if len < 1 {
+		return "", errors.New("no commit found")
+	}
+	return g.Commits[len-1].SHA, nil
+}
+
+func (g *GitFake) GetLatestCommitMessage(dir string) (string, error) {
+	len := len(g.Commits)
+	if len < 1 {
+		return "", errors.New("no commit found")
+	}
+	return g.Commits[len-1].Message, nil
+}
+
 func (g *GitFake) GetCurrentGitTagSHA(dir string) (string, error) {
 	len := len(g.Commits)
 	if len < 1 {
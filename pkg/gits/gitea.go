@@ -489,6 +489,40 @@ func (p *GiteaProvider) AddPRComment(pr *GitPullRequest, comment string) error {
 	return err
 }
 
+func (p *GiteaProvider) ListPRComments(pr *GitPullRequest) ([]*GitPRComment, error) {
+	if pr.Number == nil {
+		return nil, fmt.Errorf("Missing Number for GitPullRequest %#v", pr)
+	}
+	comments, err := p.Client.ListIssueComments(pr.Owner, pr.Repo, int64(*pr.Number))
+	if err != nil {
+		return nil, err
+	}
+	answer := []*GitPRComment{}
+	for _, comment := range comments {
+		c := &GitPRComment{
+			ID:   comment.ID,
+			Body: comment.Body,
+		}
+		if comment.Poster != nil {
+			c.Author = &GitUser{
+				Login: comment.Poster.UserName,
+			}
+		}
+		answer = append(answer, c)
+	}
+	return answer, nil
+}
+
+func (p *GiteaProvider) UpdatePRComment(pr *GitPullRequest, id int64, comment string) error {
+	if pr.Number == nil {
+		return fmt.Errorf("Missing Number for GitPullRequest %#v", pr)
+	}
+	_, err := p.Client.EditIssueComment(pr.Owner, pr.Repo, int64(*pr.Number), id, gitea.EditIssueCommentOption{
+		Body: comment,
+	})
+	return err
+}
+
 func (p *GiteaProvider) CreateIssueComment(owner string, repo string, number int, comment string) error {
 	issueComment := gitea.CreateIssueCommentOption{
 		Body: comment,
@@ -422,6 +422,12 @@ func (p *GitHubProvider) UpdatePullRequestStatus(pr *GitPullRequest) error {
 	if result.Body != nil {
 		pr.Body = *result.Body
 	}
+	pr.Labels = nil
+	for _, label := range result.Labels {
+		if label != nil && label.Name != nil {
+			pr.Labels = append(pr.Labels, *label.Name)
+		}
+	}
 	return nil
 }
 
@@ -543,6 +549,48 @@ func (p *GitHubProvider) AddPRComment(pr *GitPullRequest, comment string) error
 	return nil
 }
 
+func (p *GitHubProvider) ListPRComments(pr *GitPullRequest) ([]*GitPRComment, error) {
+	if pr.Number == nil {
+		return nil, fmt.Errorf("Missing Number for GitPullRequest %#v", pr)
+	}
+	answer := []*GitPRComment{}
+	opt := &github.IssueListCommentsOptions{
+		ListOptions: github.ListOptions{
+			PerPage: pageSize,
+		},
+	}
+	for {
+		comments, resp, err := p.Client.Issues.ListComments(p.Context, pr.Owner, pr.Repo, *pr.Number, opt)
+		if err != nil {
+			return nil, err
+		}
+		for _, comment := range comments {
+			c := &GitPRComment{
+				ID:   comment.GetID(),
+				Body: comment.GetBody(),
+			}
+			if comment.User != nil {
+				c.Author = &GitUser{
+					Login: comment.User.GetLogin(),
+				}
+			}
+			answer = append(answer, c)
+		}
+		if resp.NextPage == 0 {
+			break
+		}
+		opt.Page = resp.NextPage
+	}
+	return answer, nil
+}
+
+func (p *GitHubProvider) UpdatePRComment(pr *GitPullRequest, id int64, comment string) error {
+	_, _, err := p.Client.Issues.EditComment(p.Context, pr.Owner, pr.Repo, id, &github.IssueComment{
+		Body: &comment,
+	})
+	return err
+}
+
 func (p *GitHubProvider) CreateIssueComment(owner string, repo string, number int, comment string) error {
 	issueComment := &github.IssueComment{
 		Body: &comment,
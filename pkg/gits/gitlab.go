@@ -255,6 +255,7 @@ func fromMergeRequest(mr *gitlab.MergeRequest, owner, repo string) *GitPullReque
 		LastCommitSha:  mr.SHA,
 		MergedAt:       mr.MergedAt,
 		ClosedAt:       mr.ClosedAt,
+		Labels:         mr.Labels,
 	}
 }
 
@@ -516,6 +517,47 @@ func (g *GitlabProvider) AddPRComment(pr *GitPullRequest, comment string) error
 	return err
 }
 
+func (g *GitlabProvider) ListPRComments(pr *GitPullRequest) ([]*GitPRComment, error) {
+	pid, err := g.projectId(pr.Owner, g.Username, pr.Repo)
+	if err != nil {
+		return nil, err
+	}
+	answer := []*GitPRComment{}
+	opt := &gitlab.ListMergeRequestNotesOptions{
+		PerPage: pageSize,
+	}
+	for {
+		notes, resp, err := g.Client.Notes.ListMergeRequestNotes(pid, *pr.Number, opt)
+		if err != nil {
+			return nil, err
+		}
+		for _, note := range notes {
+			answer = append(answer, &GitPRComment{
+				ID:   int64(note.ID),
+				Body: note.Body,
+				Author: &GitUser{
+					Login: note.Author.Username,
+				},
+			})
+		}
+		if resp.NextPage == 0 {
+			break
+		}
+		opt.Page = resp.NextPage
+	}
+	return answer, nil
+}
+
+func (g *GitlabProvider) UpdatePRComment(pr *GitPullRequest, id int64, comment string) error {
+	pid, err := g.projectId(pr.Owner, g.Username, pr.Repo)
+	if err != nil {
+		return err
+	}
+	opt := &gitlab.UpdateMergeRequestNoteOptions{Body: &comment}
+	_, _, err = g.Client.Notes.UpdateMergeRequestNote(pid, *pr.Number, int(id), opt)
+	return err
+}
+
 func (g *GitlabProvider) CreateIssueComment(owner string, repo string, number int, comment string) error {
 	opt := &gitlab.CreateIssueNoteOptions{Body: &comment}
 
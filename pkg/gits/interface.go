@@ -82,6 +82,12 @@ type GitProvider interface {
 
 	AddPRComment(pr *GitPullRequest, comment string) error
 
+	// ListPRComments returns the comments of the pull request
+	ListPRComments(pr *GitPullRequest) ([]*GitPRComment, error)
+
+	// UpdatePRComment replaces the body of the comment of the pull request with the given ID
+	UpdatePRComment(pr *GitPullRequest, id int64, comment string) error
+
 	CreateIssueComment(owner string, repo string, number int, comment string) error
 
 	UpdateRelease(owner string, repo string, tag string, releaseInfo *GitRelease) error
@@ -197,6 +203,8 @@ type Gitter interface {
 	HasChanges(dir string) (bool, error)
 	Diff(dir string) (string, error)
 
+	GetLatestCommitSha(dir string) (string, error)
+	GetLatestCommitMessage(dir string) (string, error)
 	GetPreviousGitTagSHA(dir string) (string, error)
 	GetCurrentGitTagSHA(dir string) (string, error)
 	FetchTags(dir string) error
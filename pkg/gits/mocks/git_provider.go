@@ -69,6 +69,40 @@ func (mock *MockGitProvider) AddPRComment(_param0 *gits.GitPullRequest, _param1
 	return ret0
 }
 
+func (mock *MockGitProvider) ListPRComments(_param0 *gits.GitPullRequest) ([]*gits.GitPRComment, error) {
+	if mock == nil {
+		panic("mock must not be nil. Use myMock := NewMockGitProvider().")
+	}
+	params := []pegomock.Param{_param0}
+	result := pegomock.GetGenericMockFrom(mock).Invoke("ListPRComments", params, []reflect.Type{reflect.TypeOf((*[]*gits.GitPRComment)(nil)).Elem(), reflect.TypeOf((*error)(nil)).Elem()})
+	var ret0 []*gits.GitPRComment
+	var ret1 error
+	if len(result) != 0 {
+		if result[0] != nil {
+			ret0 = result[0].([]*gits.GitPRComment)
+		}
+		if result[1] != nil {
+			ret1 = result[1].(error)
+		}
+	}
+	return ret0, ret1
+}
+
+func (mock *MockGitProvider) UpdatePRComment(_param0 *gits.GitPullRequest, _param1 int64, _param2 string) error {
+	if mock == nil {
+		panic("mock must not be nil. Use myMock := NewMockGitProvider().")
+	}
+	params := []pegomock.Param{_param0, _param1, _param2}
+	result := pegomock.GetGenericMockFrom(mock).Invoke("UpdatePRComment", params, []reflect.Type{reflect.TypeOf((*error)(nil)).Elem()})
+	var ret0 error
+	if len(result) != 0 {
+		if result[0] != nil {
+			ret0 = result[0].(error)
+		}
+	}
+	return ret0
+}
+
 func (mock *MockGitProvider) BranchArchiveURL(_param0 string, _param1 string, _param2 string) string {
 	if mock == nil {
 		panic("mock must not be nil. Use myMock := NewMockGitProvider().")
@@ -837,6 +871,68 @@ func (c *GitProvider_AddPRComment_OngoingVerification) GetAllCapturedArguments()
 	return
 }
 
+func (verifier *VerifierGitProvider) ListPRComments(_param0 *gits.GitPullRequest) *GitProvider_ListPRComments_OngoingVerification {
+	params := []pegomock.Param{_param0}
+	methodInvocations := pegomock.GetGenericMockFrom(verifier.mock).Verify(verifier.inOrderContext, verifier.invocationCountMatcher, "ListPRComments", params)
+	return &GitProvider_ListPRComments_OngoingVerification{mock: verifier.mock, methodInvocations: methodInvocations}
+}
+
+type GitProvider_ListPRComments_OngoingVerification struct {
+	mock              *MockGitProvider
+	methodInvocations []pegomock.MethodInvocation
+}
+
+func (c *GitProvider_ListPRComments_OngoingVerification) GetCapturedArguments() *gits.GitPullRequest {
+	_param0 := c.GetAllCapturedArguments()
+	return _param0[len(_param0)-1]
+}
+
+func (c *GitProvider_ListPRComments_OngoingVerification) GetAllCapturedArguments() (_param0 []*gits.GitPullRequest) {
+	params := pegomock.GetGenericMockFrom(c.mock).GetInvocationParams(c.methodInvocations)
+	if len(params) > 0 {
+		_param0 = make([]*gits.GitPullRequest, len(params[0]))
+		for u, param := range params[0] {
+			_param0[u] = param.(*gits.GitPullRequest)
+		}
+	}
+	return
+}
+
+func (verifier *VerifierGitProvider) UpdatePRComment(_param0 *gits.GitPullRequest, _param1 int64, _param2 string) *GitProvider_UpdatePRComment_OngoingVerification {
+	params := []pegomock.Param{_param0, _param1, _param2}
+	methodInvocations := pegomock.GetGenericMockFrom(verifier.mock).Verify(verifier.inOrderContext, verifier.invocationCountMatcher, "UpdatePRComment", params)
+	return &GitProvider_UpdatePRComment_OngoingVerification{mock: verifier.mock, methodInvocations: methodInvocations}
+}
+
+type GitProvider_UpdatePRComment_OngoingVerification struct {
+	mock              *MockGitProvider
+	methodInvocations []pegomock.MethodInvocation
+}
+
+func (c *GitProvider_UpdatePRComment_OngoingVerification) GetCapturedArguments() (*gits.GitPullRequest, int64, string) {
+	_param0, _param1, _param2 := c.GetAllCapturedArguments()
+	return _param0[len(_param0)-1], _param1[len(_param1)-1], _param2[len(_param2)-1]
+}
+
+func (c *GitProvider_UpdatePRComment_OngoingVerification) GetAllCapturedArguments() (_param0 []*gits.GitPullRequest, _param1 []int64, _param2 []string) {
+	params := pegomock.GetGenericMockFrom(c.mock).GetInvocationParams(c.methodInvocations)
+	if len(params) > 0 {
+		_param0 = make([]*gits.GitPullRequest, len(params[0]))
+		for u, param := range params[0] {
+			_param0[u] = param.(*gits.GitPullRequest)
+		}
+		_param1 = make([]int64, len(params[1]))
+		for u, param := range params[1] {
+			_param1[u] = param.(int64)
+		}
+		_param2 = make([]string, len(params[2]))
+		for u, param := range params[2] {
+			_param2[u] = param.(string)
+		}
+	}
+	return
+}
+
 func (verifier *VerifierGitProvider) BranchArchiveURL(_param0 string, _param1 string, _param2 string) *GitProvider_BranchArchiveURL_OngoingVerification {
 	params := []pegomock.Param{_param0, _param1, _param2}
 	methodInvocations := pegomock.GetGenericMockFrom(verifier.mock).Verify(verifier.inOrderContext, verifier.invocationCountMatcher, "BranchArchiveURL", params)
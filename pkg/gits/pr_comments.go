@@ -0,0 +1,32 @@
+package gits
+
+import (
+	"fmt"
+	"strings"
+)
+
+// PRCommentMarker returns the hidden HTML comment which identifies the comments of a pull request added for the given
+// marker
+func PRCommentMarker(marker string) string {
+	return fmt.Sprintf("<!-- %s -->", marker)
+}
+
+// AddOrUpdatePRComment updates the comment of the pull request which contains the hidden marker or adds a new comment
+// if there is none so that the pull request only ever has a single comment for the marker
+func AddOrUpdatePRComment(provider GitProvider, pr *GitPullRequest, marker string, comment string) error {
+	hidden := PRCommentMarker(marker)
+	body := hidden + "\n" + comment
+	comments, err := provider.ListPRComments(pr)
+	if err != nil {
+		return err
+	}
+	for _, c := range comments {
+		if c != nil && strings.Contains(c.Body, hidden) {
+			if c.Body == body {
+				return nil
+			}
+			return provider.UpdatePRComment(pr, c.ID, body)
+		}
+	}
+	return provider.AddPRComment(pr, body)
+}
@@ -47,6 +47,14 @@ type GitPullRequest struct {
 	LastCommitSha  string
 	Title          string
 	Body           string
+	Labels         []string
+}
+
+// GitPRComment a comment on a pull request
+type GitPRComment struct {
+	ID     int64
+	Body   string
+	Author *GitUser
 }
 
 type GitCommit struct {
@@ -44,6 +44,7 @@ type FakePullRequest struct {
 	PullRequest *GitPullRequest
 	Commits     []*FakeCommit
 	Comment     string
+	Comments    []*GitPRComment
 }
 
 type FakeIssue struct {
@@ -470,12 +471,56 @@ func (f *FakeProvider) AddPRComment(pr *GitPullRequest, comment string) error {
 				return fmt.Errorf("pull request with id '%d' not found", number)
 			}
 			pr.Comment = comment
+			pr.Comments = append(pr.Comments, &GitPRComment{
+				ID:   int64(len(pr.Comments) + 1),
+				Body: comment,
+			})
 			return nil
 		}
 	}
 	return fmt.Errorf("repository with name '%s' not found", repoName)
 }
 
+func (f *FakeProvider) ListPRComments(pr *GitPullRequest) ([]*GitPRComment, error) {
+	fakePR, err := f.fakePullRequest(pr)
+	if err != nil {
+		return nil, err
+	}
+	return fakePR.Comments, nil
+}
+
+func (f *FakeProvider) UpdatePRComment(pr *GitPullRequest, id int64, comment string) error {
+	fakePR, err := f.fakePullRequest(pr)
+	if err != nil {
+		return err
+	}
+	for _, c := range fakePR.Comments {
+		if c.ID == id {
+			c.Body = comment
+			fakePR.Comment = comment
+			return nil
+		}
+	}
+	return fmt.Errorf("comment with id '%d' not found", id)
+}
+
+func (f *FakeProvider) fakePullRequest(pr *GitPullRequest) (*FakePullRequest, error) {
+	repos, ok := f.Repositories[pr.Owner]
+	if !ok {
+		return nil, fmt.Errorf("no repositories found for '%s'", pr.Owner)
+	}
+	for _, r := range repos {
+		if r.GitRepo.Name == pr.Repo {
+			fakePR, ok := r.PullRequests[*pr.Number]
+			if !ok {
+				return nil, fmt.Errorf("pull request with id '%d' not found", *pr.Number)
+			}
+			return fakePR, nil
+		}
+	}
+	return nil, fmt.Errorf("repository with name '%s' not found", pr.Repo)
+}
+
 func (f *FakeProvider) CreateIssueComment(owner string, repoName string, number int, comment string) error {
 	repos, ok := f.Repositories[owner]
 	if !ok {
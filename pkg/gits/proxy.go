@@ -0,0 +1,53 @@
+package gits
+
+import (
+	"fmt"
+	"net/url"
+	"strings"
+
+	"github.com/jenkins-x/jx/pkg/util"
+)
+
+const (
+	// ProxyAuthAny lets git pick the most secure method the proxy supports
+	ProxyAuthAny = "anyauth"
+	// ProxyAuthBasic authenticates with the username and password in clear text
+	ProxyAuthBasic = "basic"
+	// ProxyAuthDigest authenticates with a digest of the password
+	ProxyAuthDigest = "digest"
+	// ProxyAuthNegotiate authenticates with the Kerberos ticket of the user
+	ProxyAuthNegotiate = "negotiate"
+	// ProxyAuthNTLM authenticates with NTLM
+	ProxyAuthNTLM = "ntlm"
+)
+
+// ProxyAuthMethods the methods git can authenticate with a proxy
+var ProxyAuthMethods = []string{ProxyAuthAny, ProxyAuthBasic, ProxyAuthDigest, ProxyAuthNegotiate, ProxyAuthNTLM}
+
+// ProxyConfig returns the git configuration which connects to the git servers through the proxy authenticating with
+// the method and credentials. Kerberos authenticates with the ticket of the current user when no username is given
+func ProxyConfig(proxyURL string, authMethod string, username string, password string) (map[string]string, error) {
+	if authMethod == "" {
+		authMethod = ProxyAuthAny
+	}
+	if util.StringArrayIndex(ProxyAuthMethods, authMethod) < 0 {
+		return nil, fmt.Errorf("invalid proxy authentication method %s. Possible values: %s", authMethod, strings.Join(ProxyAuthMethods, ", "))
+	}
+	u, err := url.Parse(proxyURL)
+	if err != nil {
+		return nil, fmt.Errorf("invalid proxy URL %s: %s", proxyURL, err)
+	}
+	if u.Scheme != "http" && u.Scheme != "https" || u.Host == "" {
+		return nil, fmt.Errorf("invalid proxy URL %s: it should be an http or https URL such as http://proxy.example.com:8080", proxyURL)
+	}
+	if username != "" {
+		u.User = url.UserPassword(username, password)
+	} else if authMethod == ProxyAuthNegotiate {
+		// an empty username makes curl use the Kerberos ticket rather than asking for credentials
+		u.User = url.UserPassword("", "")
+	}
+	return map[string]string{
+		"http.proxy":           u.String(),
+		"http.proxyAuthMethod": authMethod,
+	}, nil
+}
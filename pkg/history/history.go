@@ -0,0 +1,197 @@
+package history
+
+import (
+	"encoding/json"
+	"path"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/jenkins-x/jx/pkg/apis/jenkins.io/v1"
+	"github.com/jenkins-x/jx/pkg/cloud/buckets"
+	"github.com/jenkins-x/jx/pkg/util"
+	"github.com/pkg/errors"
+)
+
+const (
+	// historyPath the path in the history bucket the pipeline history is stored in
+	historyPath = "jenkins-x/history"
+	// indexFile the file in the history path listing the repositories which have history
+	indexFile = "index.json"
+)
+
+// PipelineRun the compacted record of a completed run of a pipeline
+type PipelineRun struct {
+	Name      string     `json:"name"`
+	Pipeline  string     `json:"pipeline"`
+	Build     string     `json:"build"`
+	Status    string     `json:"status"`
+	Started   time.Time  `json:"started"`
+	Completed time.Time  `json:"completed"`
+	Stages    []StageRun `json:"stages,omitempty"`
+}
+
+// StageRun the compacted record of a stage of a completed run of a pipeline
+type StageRun struct {
+	Name     string        `json:"name"`
+	Status   string        `json:"status"`
+	Duration time.Duration `json:"duration,omitempty"`
+}
+
+// Duration returns how long the run took
+func (r *PipelineRun) Duration() time.Duration {
+	return r.Completed.Sub(r.Started)
+}
+
+// Succeeded returns true if the run succeeded
+func (r *PipelineRun) Succeeded() bool {
+	return r.Status == string(v1.ActivityStatusTypeSucceeded)
+}
+
+// Repository returns the 'owner/repository' of the pipeline such as 'myorg/myapp' for the pipeline
+// 'myorg/myapp/master'
+func (r *PipelineRun) Repository() string {
+	paths := strings.Split(r.Pipeline, "/")
+	if len(paths) > 2 {
+		paths = paths[0:2]
+	}
+	return strings.Join(paths, "/")
+}
+
+// NewPipelineRun returns the compacted record of the pipeline activity or nil if it has not completed
+func NewPipelineRun(activity *v1.PipelineActivity) *PipelineRun {
+	spec := &activity.Spec
+	if spec.Status != v1.ActivityStatusTypeSucceeded && spec.Status != v1.ActivityStatusTypeFailed {
+		return nil
+	}
+	if spec.Pipeline == "" || spec.StartedTimestamp == nil || spec.CompletedTimestamp == nil {
+		return nil
+	}
+	answer := &PipelineRun{
+		Name:      activity.Name,
+		Pipeline:  spec.Pipeline,
+		Build:     spec.Build,
+		Status:    string(spec.Status),
+		Started:   spec.StartedTimestamp.Time,
+		Completed: spec.CompletedTimestamp.Time,
+	}
+	for _, step := range spec.Steps {
+		stage := step.Stage
+		if stage == nil {
+			continue
+		}
+		run := StageRun{
+			Name:   stage.Name,
+			Status: string(stage.Status),
+		}
+		if stage.StartedTimestamp != nil && stage.CompletedTimestamp != nil {
+			run.Duration = stage.CompletedTimestamp.Sub(stage.StartedTimestamp.Time)
+		}
+		answer.Stages = append(answer.Stages, run)
+	}
+	return answer
+}
+
+// Archive merges the runs into the history of their repositories in the bucket
+func Archive(bucketURL string, runs []PipelineRun) error {
+	repositories, err := readIndex(bucketURL)
+	if err != nil {
+		return err
+	}
+	byRepository := map[string][]PipelineRun{}
+	for _, run := range runs {
+		byRepository[run.Repository()] = append(byRepository[run.Repository()], run)
+	}
+	for repository, repositoryRuns := range byRepository {
+		existing := []PipelineRun{}
+		err = readJSON(bucketURL, repositoryFile(repository), &existing)
+		if err != nil {
+			return err
+		}
+		err = writeJSON(bucketURL, repositoryFile(repository), Merge(existing, repositoryRuns))
+		if err != nil {
+			return err
+		}
+		if util.StringArrayIndex(repositories, repository) < 0 {
+			repositories = append(repositories, repository)
+		}
+	}
+	sort.Strings(repositories)
+	return writeJSON(bucketURL, path.Join(historyPath, indexFile), repositories)
+}
+
+// Load loads the history of all the repositories in the bucket
+func Load(bucketURL string) ([]PipelineRun, error) {
+	repositories, err := readIndex(bucketURL)
+	if err != nil {
+		return nil, err
+	}
+	answer := []PipelineRun{}
+	for _, repository := range repositories {
+		runs := []PipelineRun{}
+		err = readJSON(bucketURL, repositoryFile(repository), &runs)
+		if err != nil {
+			return nil, err
+		}
+		answer = append(answer, runs...)
+	}
+	return answer, nil
+}
+
+// Merge returns the runs of both lists ordered by when they started where the runs of the second list replace the
+// runs of the first list with the same name
+func Merge(runs []PipelineRun, newRuns []PipelineRun) []PipelineRun {
+	byName := map[string]PipelineRun{}
+	for _, run := range runs {
+		byName[run.Name] = run
+	}
+	for _, run := range newRuns {
+		byName[run.Name] = run
+	}
+	answer := []PipelineRun{}
+	for _, run := range byName {
+		answer = append(answer, run)
+	}
+	sort.Slice(answer, func(i, j int) bool {
+		if answer[i].Started.Equal(answer[j].Started) {
+			return answer[i].Name < answer[j].Name
+		}
+		return answer[i].Started.Before(answer[j].Started)
+	})
+	return answer
+}
+
+func repositoryFile(repository string) string {
+	return path.Join(historyPath, repository+".json")
+}
+
+func readIndex(bucketURL string) ([]string, error) {
+	repositories := []string{}
+	err := readJSON(bucketURL, path.Join(historyPath, indexFile), &repositories)
+	return repositories, err
+}
+
+// readJSON reads the JSON file in the bucket into the result leaving the result unchanged if there is no such file
+func readJSON(bucketURL string, file string, result interface{}) error {
+	data, err := buckets.ReadFile(strings.TrimSuffix(bucketURL, "/") + "/" + file)
+	if err != nil {
+		if buckets.IsNotFound(err) {
+			return nil
+		}
+		return err
+	}
+	err = json.Unmarshal(data, result)
+	if err != nil {
+		return errors.Wrapf(err, "failed to parse the pipeline history %s", file)
+	}
+	return nil
+}
+
+func writeJSON(bucketURL string, file string, value interface{}) error {
+	data, err := json.Marshal(value)
+	if err != nil {
+		return err
+	}
+	_, err = buckets.WriteFile(bucketURL, file, data, "application/json")
+	return err
+}
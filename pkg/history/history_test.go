@@ -0,0 +1,115 @@
+package history_test
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/jenkins-x/jx/pkg/apis/jenkins.io/v1"
+	"github.com/jenkins-x/jx/pkg/history"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+var start = time.Date(2018, 10, 1, 12, 0, 0, 0, time.UTC)
+
+func newRun(pipeline string, build int, minutes int, stageStatuses ...string) history.PipelineRun {
+	started := start.Add(time.Duration(build) * time.Hour)
+	run := history.PipelineRun{
+		Name:      pipeline + "-" + strconv.Itoa(build),
+		Pipeline:  pipeline,
+		Status:    string(v1.ActivityStatusTypeSucceeded),
+		Started:   started,
+		Completed: started.Add(time.Duration(minutes) * time.Minute),
+	}
+	for i, status := range stageStatuses {
+		if status != string(v1.ActivityStatusTypeSucceeded) {
+			run.Status = string(v1.ActivityStatusTypeFailed)
+		}
+		run.Stages = append(run.Stages, history.StageRun{Name: []string{"Build", "Test"}[i], Status: status})
+	}
+	return run
+}
+
+func TestStats(t *testing.T) {
+	succeeded := string(v1.ActivityStatusTypeSucceeded)
+	failed := string(v1.ActivityStatusTypeFailed)
+	runs := []history.PipelineRun{
+		newRun("myorg/myapp/master", 1, 10, succeeded, succeeded),
+		newRun("myorg/myapp/master", 2, 12, succeeded, failed),
+		newRun("myorg/myapp/master", 3, 11, succeeded, succeeded),
+		newRun("myorg/myapp/master", 4, 30, succeeded, failed),
+		newRun("myorg/myapp/PR-1", 5, 20, failed, failed),
+		newRun("myorg/other/master", 6, 5, succeeded),
+		newRun("myorg/other/master", 0, 5, failed),
+	}
+
+	stats := history.Stats(runs, start.Add(30*time.Minute))
+	require.Len(t, stats, 2)
+
+	s := stats[0]
+	assert.Equal(t, "myorg/myapp", s.Repository)
+	assert.Equal(t, 5, s.Runs)
+	assert.Equal(t, 2, s.Succeeded)
+	assert.Equal(t, 40.0, s.SuccessRate())
+	assert.Equal(t, 12*time.Minute, s.P50)
+	assert.Equal(t, 30*time.Minute, s.P95)
+	require.Len(t, s.FlakiestStages, 1)
+	assert.Equal(t, "Test", s.FlakiestStages[0].Name)
+	assert.Equal(t, 3, s.FlakiestStages[0].Flips)
+	assert.Equal(t, 3, s.FlakiestStages[0].Failures)
+	assert.Equal(t, 75.0, s.FlakiestStages[0].Flakiness())
+
+	s = stats[1]
+	assert.Equal(t, "myorg/other", s.Repository)
+	assert.Equal(t, 1, s.Runs, "the run before the since time should be ignored")
+	assert.Equal(t, 100.0, s.SuccessRate())
+	assert.Empty(t, s.FlakiestStages)
+}
+
+func TestArchiveAndLoad(t *testing.T) {
+	dir, err := ioutil.TempDir("", "test-history")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+	bucketURL := "file://" + filepath.ToSlash(dir)
+
+	runs, err := history.Load(bucketURL)
+	require.NoError(t, err)
+	assert.Empty(t, runs)
+
+	startedTimestamp := metav1.NewTime(start)
+	completedTimestamp := metav1.NewTime(start.Add(time.Minute))
+	activity := &v1.PipelineActivity{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: "myorg-myapp-master-1",
+		},
+		Spec: v1.PipelineActivitySpec{
+			Pipeline:           "myorg/myapp/master",
+			Build:              "1",
+			Status:             v1.ActivityStatusTypeSucceeded,
+			StartedTimestamp:   &startedTimestamp,
+			CompletedTimestamp: &completedTimestamp,
+		},
+	}
+	run := history.NewPipelineRun(activity)
+	require.NotNil(t, run)
+	err = history.Archive(bucketURL, []history.PipelineRun{*run, newRun("myorg/other/master", 2, 5)})
+	require.NoError(t, err)
+	err = history.Archive(bucketURL, []history.PipelineRun{*run, newRun("myorg/myapp/master", 3, 5)})
+	require.NoError(t, err)
+
+	runs, err = history.Load(bucketURL)
+	require.NoError(t, err)
+	require.Len(t, runs, 3)
+	assert.Equal(t, "myorg-myapp-master-1", runs[0].Name)
+	assert.Equal(t, time.Minute, runs[0].Duration())
+	assert.Equal(t, "myorg/myapp/master-3", runs[1].Name)
+	assert.Equal(t, "myorg/other/master-2", runs[2].Name)
+
+	activity.Spec.Status = v1.ActivityStatusTypeRunning
+	assert.Nil(t, history.NewPipelineRun(activity))
+}
@@ -0,0 +1,137 @@
+package history
+
+import (
+	"math"
+	"sort"
+	"time"
+
+	"github.com/jenkins-x/jx/pkg/apis/jenkins.io/v1"
+)
+
+// maxFlakiestStages the maximum number of the flakiest stages reported for a repository
+const maxFlakiestStages = 3
+
+// RepositoryStats the statistics of the runs of the pipelines of a repository
+type RepositoryStats struct {
+	Repository     string           `json:"repository"`
+	Runs           int              `json:"runs"`
+	Succeeded      int              `json:"succeeded"`
+	P50            time.Duration    `json:"p50"`
+	P95            time.Duration    `json:"p95"`
+	FlakiestStages []StageFlakiness `json:"flakiestStages,omitempty"`
+}
+
+// StageFlakiness how often a stage of the pipelines of a repository flips between succeeding and failing
+type StageFlakiness struct {
+	Name     string `json:"name"`
+	Runs     int    `json:"runs"`
+	Failures int    `json:"failures"`
+	Flips    int    `json:"flips"`
+}
+
+// SuccessRate returns the percentage of the runs which succeeded
+func (s *RepositoryStats) SuccessRate() float64 {
+	if s.Runs == 0 {
+		return 0
+	}
+	return 100 * float64(s.Succeeded) / float64(s.Runs)
+}
+
+// Flakiness returns the percentage of the consecutive runs of the stage whose status flipped
+func (f *StageFlakiness) Flakiness() float64 {
+	if f.Runs < 2 {
+		return 0
+	}
+	return 100 * float64(f.Flips) / float64(f.Runs-1)
+}
+
+// Stats returns the statistics of each repository of the runs which started after the given time ordered by repository
+func Stats(runs []PipelineRun, since time.Time) []RepositoryStats {
+	byRepository := map[string][]PipelineRun{}
+	for _, run := range Merge(nil, runs) {
+		if run.Started.Before(since) {
+			continue
+		}
+		byRepository[run.Repository()] = append(byRepository[run.Repository()], run)
+	}
+	answer := []RepositoryStats{}
+	for repository, repositoryRuns := range byRepository {
+		answer = append(answer, repositoryStats(repository, repositoryRuns))
+	}
+	sort.Slice(answer, func(i, j int) bool {
+		return answer[i].Repository < answer[j].Repository
+	})
+	return answer
+}
+
+// repositoryStats returns the statistics of the runs of a repository which are ordered by when they started
+func repositoryStats(repository string, runs []PipelineRun) RepositoryStats {
+	answer := RepositoryStats{
+		Repository: repository,
+		Runs:       len(runs),
+	}
+	durations := []time.Duration{}
+	stages := map[string]*StageFlakiness{}
+	// the last status of each stage of each pipeline as flips are only counted between runs of the same branch
+	lastStatus := map[string]string{}
+	for _, run := range runs {
+		if run.Succeeded() {
+			answer.Succeeded++
+		}
+		durations = append(durations, run.Duration())
+		for _, stage := range run.Stages {
+			flakiness := stages[stage.Name]
+			if flakiness == nil {
+				flakiness = &StageFlakiness{Name: stage.Name}
+				stages[stage.Name] = flakiness
+			}
+			flakiness.Runs++
+			if stage.Status != string(v1.ActivityStatusTypeSucceeded) {
+				flakiness.Failures++
+			}
+			key := run.Pipeline + "/" + stage.Name
+			if last, ok := lastStatus[key]; ok && last != stage.Status {
+				flakiness.Flips++
+			}
+			lastStatus[key] = stage.Status
+		}
+	}
+	sort.Slice(durations, func(i, j int) bool {
+		return durations[i] < durations[j]
+	})
+	answer.P50 = percentile(durations, 50)
+	answer.P95 = percentile(durations, 95)
+
+	flaky := []StageFlakiness{}
+	for _, flakiness := range stages {
+		if flakiness.Flips > 0 {
+			flaky = append(flaky, *flakiness)
+		}
+	}
+	sort.Slice(flaky, func(i, j int) bool {
+		fi, fj := flaky[i].Flakiness(), flaky[j].Flakiness()
+		if fi == fj {
+			return flaky[i].Name < flaky[j].Name
+		}
+		return fi > fj
+	})
+	if len(flaky) > maxFlakiestStages {
+		flaky = flaky[0:maxFlakiestStages]
+	}
+	if len(flaky) > 0 {
+		answer.FlakiestStages = flaky
+	}
+	return answer
+}
+
+// percentile returns the nearest rank percentile of the sorted durations
+func percentile(sorted []time.Duration, p float64) time.Duration {
+	if len(sorted) == 0 {
+		return 0
+	}
+	idx := int(math.Ceil(p/100*float64(len(sorted)))) - 1
+	if idx < 0 {
+		idx = 0
+	}
+	return sorted[idx]
+}
@@ -0,0 +1,75 @@
+package history
+
+import (
+	"path"
+	"sort"
+
+	"github.com/jenkins-x/jx/pkg/junit"
+)
+
+const (
+	// maxTestOutcomes the number of the most recent outcomes of each test which are kept
+	maxTestOutcomes = 20
+	// testsFile the file in the history of a repository containing the outcomes of its tests
+	testsFile = "tests.json"
+)
+
+// TestHistory the most recent outcomes of the tests of a repository indexed by the ID of the test where each outcome
+// is true if the test passed
+type TestHistory map[string][]bool
+
+// Record adds the outcomes of the tests which passed or failed to the history
+func (h TestHistory) Record(testCases []junit.TestCase) {
+	for _, testCase := range testCases {
+		if testCase.Status == junit.StatusSkipped {
+			continue
+		}
+		id := testCase.ID()
+		outcomes := append(h[id], testCase.Status == junit.StatusPassed)
+		if len(outcomes) > maxTestOutcomes {
+			outcomes = outcomes[len(outcomes)-maxTestOutcomes:]
+		}
+		h[id] = outcomes
+	}
+}
+
+// Flips returns how many times the outcome of the test flipped between passing and failing in the history
+func (h TestHistory) Flips(id string) int {
+	answer := 0
+	outcomes := h[id]
+	for i := 1; i < len(outcomes); i++ {
+		if outcomes[i] != outcomes[i-1] {
+			answer++
+		}
+	}
+	return answer
+}
+
+// IsFlaky returns true if the outcome of the test flipped at least the given number of times in the history
+func (h TestHistory) IsFlaky(id string, minFlips int) bool {
+	return h.Flips(id) >= minFlips
+}
+
+// FlakyTests returns the sorted IDs of the flaky tests in the history
+func (h TestHistory) FlakyTests(minFlips int) []string {
+	answer := []string{}
+	for id := range h {
+		if h.IsFlaky(id, minFlips) {
+			answer = append(answer, id)
+		}
+	}
+	sort.Strings(answer)
+	return answer
+}
+
+// LoadTestHistory loads the history of the tests of the repository from the bucket
+func LoadTestHistory(bucketURL string, repository string) (TestHistory, error) {
+	answer := TestHistory{}
+	err := readJSON(bucketURL, path.Join(historyPath, repository, testsFile), &answer)
+	return answer, err
+}
+
+// SaveTestHistory saves the history of the tests of the repository to the bucket
+func SaveTestHistory(bucketURL string, repository string, testHistory TestHistory) error {
+	return writeJSON(bucketURL, path.Join(historyPath, repository, testsFile), testHistory)
+}
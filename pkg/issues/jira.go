@@ -4,6 +4,7 @@ import (
 	"bytes"
 	"fmt"
 	"net/http"
+	"strconv"
 	"strings"
 	"time"
 
@@ -108,7 +109,99 @@ func (i *JiraService) CreateIssue(issue *gits.GitIssue) (*gits.GitIssue, error)
 }
 
 func (i *JiraService) CreateIssueComment(key string, comment string) error {
-	return fmt.Errorf("TODO")
+	_, _, err := i.JiraClient.Issue.AddComment(key, &jira.Comment{Body: comment})
+	if err != nil {
+		return fmt.Errorf("Failed to comment on issue %s: %s", key, err)
+	}
+	return nil
+}
+
+// TransitionIssue transitions the issue to the first of the statuses it can be transitioned to and returns the new
+// status of the issue. The issue is not transitioned if it already has one of the statuses
+func (i *JiraService) TransitionIssue(key string, statuses []string) (string, error) {
+	issue, _, err := i.JiraClient.Issue.Get(key, nil)
+	if err != nil {
+		return "", fmt.Errorf("Failed to find issue %s: %s", key, err)
+	}
+	current := ""
+	if issue.Fields != nil && issue.Fields.Status != nil {
+		current = issue.Fields.Status.Name
+	}
+	for _, status := range statuses {
+		if strings.EqualFold(current, status) {
+			return current, nil
+		}
+	}
+	transitions, _, err := i.JiraClient.Issue.GetTransitions(key)
+	if err != nil {
+		return "", fmt.Errorf("Failed to find the transitions of issue %s: %s", key, err)
+	}
+	for _, status := range statuses {
+		for _, t := range transitions {
+			if strings.EqualFold(t.To.Name, status) || strings.EqualFold(t.Name, status) {
+				_, err = i.JiraClient.Issue.DoTransition(key, t.ID)
+				if err != nil {
+					return "", fmt.Errorf("Failed to transition issue %s to %s: %s", key, t.To.Name, err)
+				}
+				return t.To.Name, nil
+			}
+		}
+	}
+	return "", fmt.Errorf("Issue %s in status %s cannot be transitioned to any of %s", key, current, strings.Join(statuses, ", "))
+}
+
+// AddFixVersion adds the version to the fix versions of the issue creating the version in the project of the issue if
+// it does not exist yet
+func (i *JiraService) AddFixVersion(key string, version string) error {
+	projectKey := i.Project
+	if projectKey == "" {
+		idx := strings.LastIndex(key, "-")
+		if idx <= 0 {
+			return fmt.Errorf("Could not find the project of issue %s", key)
+		}
+		projectKey = key[0:idx]
+	}
+	project, _, err := i.JiraClient.Project.Get(projectKey)
+	if err != nil {
+		return fmt.Errorf("Could not find project %s: %s", projectKey, err)
+	}
+	found := false
+	for _, v := range project.Versions {
+		if v.Name == version {
+			found = true
+			break
+		}
+	}
+	if !found {
+		projectID, err := strconv.Atoi(project.ID)
+		if err != nil {
+			return fmt.Errorf("Failed to parse the ID %s of project %s: %s", project.ID, projectKey, err)
+		}
+		_, _, err = i.JiraClient.Version.Create(&jira.Version{
+			Name:        version,
+			ProjectID:   projectID,
+			Released:    true,
+			ReleaseDate: time.Now().Format("2006-01-02"),
+		})
+		if err != nil {
+			return fmt.Errorf("Failed to create version %s of project %s: %s", version, projectKey, err)
+		}
+	}
+	_, err = i.JiraClient.Issue.UpdateIssue(key, map[string]interface{}{
+		"update": map[string]interface{}{
+			"fixVersions": []interface{}{
+				map[string]interface{}{
+					"add": map[string]interface{}{
+						"name": version,
+					},
+				},
+			},
+		},
+	})
+	if err != nil {
+		return fmt.Errorf("Failed to add fix version %s to issue %s: %s", version, key, err)
+	}
+	return nil
 }
 
 func (i *JiraService) IssueURL(key string) string {
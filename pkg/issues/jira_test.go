@@ -0,0 +1,72 @@
+package issues_test
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/jenkins-x/jx/pkg/auth"
+	"github.com/jenkins-x/jx/pkg/issues"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestJiraRelease(t *testing.T) {
+	t.Parallel()
+	requests := map[string]string{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		data, err := ioutil.ReadAll(r.Body)
+		assert.NoError(t, err)
+		requests[r.Method+" "+r.URL.Path] = string(data)
+		switch r.Method + " " + r.URL.Path {
+		case "GET /rest/api/2/issue/JX-12":
+			w.Write([]byte(`{"key": "JX-12", "fields": {"status": {"name": "In Review"}}}`))
+		case "GET /rest/api/2/issue/JX-34":
+			w.Write([]byte(`{"key": "JX-34", "fields": {"status": {"name": "Done"}}}`))
+		case "GET /rest/api/2/issue/JX-12/transitions":
+			w.Write([]byte(`{"transitions": [{"id": "11", "name": "Reopen", "to": {"name": "Open"}}, {"id": "31", "name": "Finish", "to": {"name": "Done"}}]}`))
+		case "POST /rest/api/2/issue/JX-12/transitions", "PUT /rest/api/2/issue/JX-12":
+			w.WriteHeader(http.StatusNoContent)
+		case "GET /rest/api/2/project/JX":
+			w.Write([]byte(`{"id": "10000", "key": "JX", "versions": [{"name": "1.0.0"}]}`))
+		case "POST /rest/api/2/version":
+			w.WriteHeader(http.StatusCreated)
+			w.Write([]byte(`{"id": "2", "name": "1.1.0"}`))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	provider, err := issues.CreateJiraIssueProvider(&auth.AuthServer{URL: server.URL}, nil, "", false, nil)
+	assert.NoError(t, err)
+	jiraService := provider.(*issues.JiraService)
+
+	status, err := jiraService.TransitionIssue("JX-12", []string{"Released", "Done"})
+	assert.NoError(t, err)
+	assert.Equal(t, "Done", status)
+	assert.JSONEq(t, `{"transition": {"id": "31"}, "fields": {}}`, requests["POST /rest/api/2/issue/JX-12/transitions"])
+
+	status, err = jiraService.TransitionIssue("JX-34", []string{"Released", "Done"})
+	assert.NoError(t, err)
+	assert.Equal(t, "Done", status, "issues already in one of the statuses should not be transitioned")
+	assert.NotContains(t, requests, "GET /rest/api/2/issue/JX-34/transitions")
+
+	_, err = jiraService.TransitionIssue("JX-12", []string{"Closed"})
+	assert.Error(t, err)
+
+	err = jiraService.AddFixVersion("JX-12", "1.1.0")
+	assert.NoError(t, err)
+	version := map[string]interface{}{}
+	err = json.Unmarshal([]byte(requests["POST /rest/api/2/version"]), &version)
+	assert.NoError(t, err)
+	assert.Equal(t, "1.1.0", version["name"])
+	assert.Equal(t, float64(10000), version["projectId"])
+	assert.JSONEq(t, `{"update": {"fixVersions": [{"add": {"name": "1.1.0"}}]}}`, requests["PUT /rest/api/2/issue/JX-12"])
+
+	delete(requests, "POST /rest/api/2/version")
+	err = jiraService.AddFixVersion("JX-12", "1.0.0")
+	assert.NoError(t, err)
+	assert.NotContains(t, requests, "POST /rest/api/2/version", "existing versions should not be created")
+}
@@ -0,0 +1,110 @@
+package junit
+
+import (
+	"encoding/xml"
+	"io/ioutil"
+	"path/filepath"
+	"sort"
+
+	"github.com/pkg/errors"
+)
+
+const (
+	// StatusPassed the status of a test which passed
+	StatusPassed = "passed"
+	// StatusFailed the status of a test which failed or errored
+	StatusFailed = "failed"
+	// StatusSkipped the status of a test which was skipped
+	StatusSkipped = "skipped"
+)
+
+// TestCase the result of a test in a JUnit report
+type TestCase struct {
+	ClassName string
+	Name      string
+	Status    string
+}
+
+// ID returns the identifier of the test such as 'com.acme.FooTest#testBar' which is also the form used to select
+// tests in the Maven Surefire -Dtest option
+func (t *TestCase) ID() string {
+	if t.ClassName == "" {
+		return t.Name
+	}
+	return t.ClassName + "#" + t.Name
+}
+
+// testSuite a testsuites or testsuite element which may contain nested test suites
+type testSuite struct {
+	Suites    []testSuite    `xml:"testsuite"`
+	TestCases []testCaseNode `xml:"testcase"`
+}
+
+type testCaseNode struct {
+	ClassName string    `xml:"classname,attr"`
+	Name      string    `xml:"name,attr"`
+	Failure   *struct{} `xml:"failure"`
+	Error     *struct{} `xml:"error"`
+	Skipped   *struct{} `xml:"skipped"`
+}
+
+// ParseReports parses the test cases of the JUnit XML reports matching the glob patterns
+func ParseReports(patterns []string) ([]TestCase, error) {
+	answer := []TestCase{}
+	for _, pattern := range patterns {
+		files, err := filepath.Glob(pattern)
+		if err != nil {
+			return nil, errors.Wrapf(err, "invalid report pattern %s", pattern)
+		}
+		sort.Strings(files)
+		for _, file := range files {
+			testCases, err := ParseReport(file)
+			if err != nil {
+				return nil, err
+			}
+			answer = append(answer, testCases...)
+		}
+	}
+	return answer, nil
+}
+
+// ParseReport parses the test cases of a JUnit XML report whose root element is either testsuites or testsuite
+func ParseReport(file string) ([]TestCase, error) {
+	data, err := ioutil.ReadFile(file)
+	if err != nil {
+		return nil, err
+	}
+	// the name of the root element is ignored so both testsuites and testsuite reports unmarshal into a testSuite
+	root := testSuite{}
+	err = xml.Unmarshal(data, &root)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to parse the JUnit report %s", file)
+	}
+	return suiteTestCases(&root), nil
+}
+
+func suiteTestCases(suite *testSuite) []TestCase {
+	answer := toTestCases(suite.TestCases)
+	for _, child := range suite.Suites {
+		answer = append(answer, suiteTestCases(&child)...)
+	}
+	return answer
+}
+
+func toTestCases(nodes []testCaseNode) []TestCase {
+	answer := []TestCase{}
+	for _, node := range nodes {
+		status := StatusPassed
+		if node.Failure != nil || node.Error != nil {
+			status = StatusFailed
+		} else if node.Skipped != nil {
+			status = StatusSkipped
+		}
+		answer = append(answer, TestCase{
+			ClassName: node.ClassName,
+			Name:      node.Name,
+			Status:    status,
+		})
+	}
+	return answer
+}
@@ -0,0 +1,50 @@
+package junit_test
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/jenkins-x/jx/pkg/junit"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseReports(t *testing.T) {
+	dir, err := ioutil.TempDir("", "test-junit")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	suite := `<?xml version="1.0" encoding="UTF-8"?>
+<testsuite name="com.acme.FooTest" tests="3">
+  <testcase classname="com.acme.FooTest" name="testPass" time="0.1"/>
+  <testcase classname="com.acme.FooTest" name="testFail" time="0.1">
+    <failure message="expected 1">stack</failure>
+  </testcase>
+  <testcase classname="com.acme.FooTest" name="testSkip">
+    <skipped/>
+  </testcase>
+</testsuite>
+`
+	suites := `<testsuites>
+  <testsuite name="pkg">
+    <testcase classname="github.com/acme/pkg" name="TestError"><error message="panic"/></testcase>
+  </testsuite>
+</testsuites>
+`
+	err = ioutil.WriteFile(filepath.Join(dir, "TEST-com.acme.FooTest.xml"), []byte(suite), 0644)
+	require.NoError(t, err)
+	err = ioutil.WriteFile(filepath.Join(dir, "TEST-go.xml"), []byte(suites), 0644)
+	require.NoError(t, err)
+
+	testCases, err := junit.ParseReports([]string{filepath.Join(dir, "TEST-*.xml")})
+	require.NoError(t, err)
+	require.Len(t, testCases, 4)
+	assert.Equal(t, "com.acme.FooTest#testPass", testCases[0].ID())
+	assert.Equal(t, junit.StatusPassed, testCases[0].Status)
+	assert.Equal(t, junit.StatusFailed, testCases[1].Status)
+	assert.Equal(t, junit.StatusSkipped, testCases[2].Status)
+	assert.Equal(t, "github.com/acme/pkg#TestError", testCases[3].ID())
+	assert.Equal(t, junit.StatusFailed, testCases[3].Status)
+}
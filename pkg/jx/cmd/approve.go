@@ -0,0 +1,184 @@
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"os/user"
+	"sort"
+	"time"
+
+	"github.com/jenkins-x/jx/pkg/apis/jenkins.io/v1"
+	"github.com/jenkins-x/jx/pkg/jx/cmd/templates"
+	"github.com/jenkins-x/jx/pkg/kube"
+	"github.com/jenkins-x/jx/pkg/log"
+	"github.com/jenkins-x/jx/pkg/util"
+	"github.com/spf13/cobra"
+	"gopkg.in/AlecAivazis/survey.v1/terminal"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+var (
+	approveLong = templates.LongDesc(`
+		Approves or rejects a manual approval gate of a pipeline which is waiting for approval.
+`)
+
+	approveExample = templates.Examples(`
+		# Pick a pipeline waiting for approval to approve
+		jx approve
+
+		# Approve the 'production' approval gate of a pipeline build
+		jx approve myorg-myapp-master-3 --name production
+
+		# Reject the approval gate of a pipeline build
+		jx approve myorg-myapp-master-3 --reject
+	`)
+)
+
+// ApproveOptions the options for the approve command
+type ApproveOptions struct {
+	CommonOptions
+
+	Name   string
+	Reject bool
+	User   string
+}
+
+// NewCmdApprove creates the command
+func NewCmdApprove(f Factory, in terminal.FileReader, out terminal.FileWriter, errOut io.Writer) *cobra.Command {
+	options := &ApproveOptions{
+		CommonOptions: CommonOptions{
+			Factory: f,
+			In:      in,
+			Out:     out,
+			Err:     errOut,
+		},
+	}
+	cmd := &cobra.Command{
+		Use:     "approve [pipeline-activity]",
+		Short:   "Approves or rejects a pipeline which is waiting for approval",
+		Long:    approveLong,
+		Example: approveExample,
+		Run: func(cmd *cobra.Command, args []string) {
+			options.Cmd = cmd
+			options.Args = args
+			err := options.Run()
+			CheckErr(err)
+		},
+	}
+	cmd.Flags().StringVarP(&options.Name, "name", "n", "", "The name of the approval gate. Only required if the pipeline has more than one approval gate waiting")
+	cmd.Flags().BoolVarP(&options.Reject, "reject", "r", false, "Rejects the approval gate which fails the pipeline")
+	cmd.Flags().StringVarP(&options.User, "user", "u", "", "The user approving the pipeline. Defaults to the current user")
+	options.addCommonFlags(cmd)
+	return cmd
+}
+
+// Run implements the command
+func (o *ApproveOptions) Run() error {
+	apisClient, err := o.CreateApiExtensionsClient()
+	if err != nil {
+		return err
+	}
+	err = kube.RegisterPipelineActivityCRD(apisClient)
+	if err != nil {
+		return err
+	}
+	jxClient, ns, err := o.JXClientAndDevNamespace()
+	if err != nil {
+		return err
+	}
+	activities := jxClient.JenkinsV1().PipelineActivities(ns)
+	list, err := activities.List(metav1.ListOptions{})
+	if err != nil {
+		return err
+	}
+	waiting := map[string]*v1.PipelineActivity{}
+	names := []string{}
+	for i := range list.Items {
+		activity := &list.Items[i]
+		if len(o.waitingApprovals(activity)) > 0 {
+			waiting[activity.Name] = activity
+			names = append(names, activity.Name)
+		}
+	}
+	sort.Strings(names)
+
+	name := ""
+	if len(o.Args) > 0 {
+		name = o.Args[0]
+	} else {
+		if len(names) == 0 {
+			log.Infof("There are no pipelines waiting for approval in namespace %s\n", util.ColorInfo(ns))
+			return nil
+		}
+		name, err = util.PickName(names, "Which pipeline do you want to approve?: ", o.In, o.Out, o.Err)
+		if err != nil {
+			return err
+		}
+	}
+	activity := waiting[name]
+	if activity == nil {
+		return util.InvalidArg(name, names)
+	}
+	approvals := o.waitingApprovals(activity)
+	if len(approvals) > 1 && o.Name == "" {
+		approvalNames := []string{}
+		for _, a := range approvals {
+			approvalNames = append(approvalNames, a.Name)
+		}
+		o.Name, err = util.PickName(approvalNames, "Which approval gate do you want to approve?: ", o.In, o.Out, o.Err)
+		if err != nil {
+			return err
+		}
+	}
+	approval := approvals[0]
+	if o.Name != "" {
+		approval = nil
+		for _, a := range approvals {
+			if a.Name == o.Name {
+				approval = a
+			}
+		}
+		if approval == nil {
+			return fmt.Errorf("The pipeline %s has no approval gate %s waiting for approval", name, o.Name)
+		}
+	}
+
+	approver := o.User
+	if approver == "" {
+		u, err := user.Current()
+		if err != nil {
+			return err
+		}
+		approver = u.Username
+	}
+	if len(approval.Approvers) > 0 && util.StringArrayIndex(approval.Approvers, approver) < 0 {
+		return fmt.Errorf("The user %s is not one of the approvers of approval gate %s: %s", approver, approval.Name, approval.Approvers)
+	}
+
+	approval.ApprovedBy = approver
+	approval.CompletedTimestamp = &metav1.Time{Time: time.Now()}
+	verb := "Approved"
+	if o.Reject {
+		approval.Status = v1.ActivityStatusTypeFailed
+		verb = "Rejected"
+	} else {
+		approval.Status = v1.ActivityStatusTypeSucceeded
+	}
+	_, err = activities.Update(activity)
+	if err != nil {
+		return err
+	}
+	log.Infof("%s approval gate %s of pipeline %s\n", verb, util.ColorInfo(approval.Name), util.ColorInfo(name))
+	return nil
+}
+
+// waitingApprovals returns the approval gates of the activity which are waiting for approval
+func (o *ApproveOptions) waitingApprovals(activity *v1.PipelineActivity) []*v1.ApprovalActivityStep {
+	answer := []*v1.ApprovalActivityStep{}
+	for _, step := range activity.Spec.Steps {
+		if step.Approval != nil && step.Approval.Status == v1.ActivityStatusTypeWaitingForApproval {
+			answer = append(answer, step.Approval)
+		}
+	}
+	return answer
+}
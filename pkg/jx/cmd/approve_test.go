@@ -0,0 +1,57 @@
+package cmd_test
+
+import (
+	"testing"
+
+	"github.com/jenkins-x/jx/pkg/apis/jenkins.io/v1"
+	"github.com/jenkins-x/jx/pkg/gits"
+	"github.com/jenkins-x/jx/pkg/helm"
+	"github.com/jenkins-x/jx/pkg/jx/cmd"
+	"github.com/stretchr/testify/assert"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+)
+
+func TestApprove(t *testing.T) {
+	t.Parallel()
+	activity := &v1.PipelineActivity{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "myorg-myapp-master-3",
+			Namespace: "jx",
+		},
+		Spec: v1.PipelineActivitySpec{
+			Steps: []v1.PipelineActivityStep{
+				{
+					Kind: v1.ActivityStepKindTypeApproval,
+					Approval: &v1.ApprovalActivityStep{
+						CoreActivityStep: v1.CoreActivityStep{
+							Name:   "production",
+							Status: v1.ActivityStatusTypeWaitingForApproval,
+						},
+						Approvers: []string{"alice"},
+					},
+				},
+			},
+		},
+	}
+
+	o := &cmd.ApproveOptions{}
+	cmd.ConfigureTestOptionsWithResources(&o.CommonOptions, nil, []runtime.Object{activity}, gits.NewGitCLI(), helm.NewHelmCLI("helm", helm.V2, "", true))
+	o.Args = []string{activity.Name}
+
+	o.User = "bob"
+	err := o.Run()
+	assert.Error(t, err, "bob should not be able to approve")
+
+	o.User = "alice"
+	err = o.Run()
+	assert.NoError(t, err)
+
+	jxClient, ns, err := o.JXClientAndDevNamespace()
+	assert.NoError(t, err)
+	actual, err := jxClient.JenkinsV1().PipelineActivities(ns).Get(activity.Name, metav1.GetOptions{})
+	assert.NoError(t, err)
+	approval := actual.Spec.Steps[0].Approval
+	assert.Equal(t, v1.ActivityStatusTypeSucceeded, approval.Status)
+	assert.Equal(t, "alice", approval.ApprovedBy)
+}
@@ -28,10 +28,8 @@ func NewJXCommand(f Factory, in terminal.FileReader, out terminal.FileWriter, er
 		Short: "jx is a command line tool for working with Jenkins X",
 		Long: `
  `,
-		Run: runHelp,
-		/*
-			BashCompletionFunction: bash_completion_func,
-		*/
+		Run:                    runHelp,
+		BashCompletionFunction: bashCompletionFunc,
 	}
 
 	createCommands := NewCmdCreate(f, in, out, err)
@@ -68,6 +66,7 @@ func NewJXCommand(f Factory, in terminal.FileReader, out terminal.FileWriter, er
 	addonCommands = append(addonCommands, findCommands("addon", createCommands, deleteCommands)...)
 
 	environmentsCommands := []*cobra.Command{
+		NewCmdApprove(f, in, out, err),
 		NewCmdPreview(f, in, out, err),
 		NewCmdPromote(f, in, out, err),
 	}
@@ -149,6 +148,7 @@ func NewJXCommand(f Factory, in terminal.FileReader, out terminal.FileWriter, er
 			Commands: []*cobra.Command{
 				NewCmdController(f, in, out, err),
 				NewCmdGC(f, in, out, err),
+				NewCmdServe(f, in, out, err),
 			},
 		},
 	}
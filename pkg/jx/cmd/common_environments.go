@@ -197,7 +197,7 @@ func (o *CommonOptions) createEnvironmentPullRequest(env *v1.Environment, modify
 }
 
 func (o *CommonOptions) registerEnvironmentCRD() error {
-	apisClient, err := o.Factory.CreateApiExtensionsClient()
+	apisClient, err := o.CreateApiExtensionsClient()
 	if err != nil {
 		return err
 	}
@@ -222,6 +222,7 @@ func (o *CommonOptions) modifyDevEnvironment(jxClient versioned.Interface, ns st
 	if err != nil {
 		return fmt.Errorf("Failed to update Development environment in namespace %s: %s", ns, err)
 	}
+	invalidateTeamSettingsCache(ns)
 	log.Infof("Updated the team settings in namespace %s\n", ns)
 	return nil
 }
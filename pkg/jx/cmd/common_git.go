@@ -258,6 +258,18 @@ func (o *CommonOptions) GitServerHostURLKind(hostURL string) (string, error) {
 	}
 
 	kind, err := kube.GetGitServiceKind(jxClient, kubeClient, devNs, hostURL)
+	if kind == "" {
+		// fall back to the kind of the server in the auth config so that the servers which are not registered as
+		// git services of the team can be used too
+		authConfigSvc, authErr := o.CreateGitAuthConfigService()
+		if authErr == nil {
+			server := authConfigSvc.Config().GetServer(hostURL)
+			if server != nil && server.Kind != "" {
+				kind = server.Kind
+				err = nil
+			}
+		}
+	}
 	if err != nil {
 		return kind, err
 	}
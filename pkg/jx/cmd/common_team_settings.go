@@ -30,6 +30,17 @@ const (
 
 // TeamSettings returns the team settings
 func (o *CommonOptions) TeamSettings() (*v1.TeamSettings, error) {
+	env, err := o.devEnvironment()
+	if err != nil {
+		return nil, err
+	}
+	teamSettings := &env.Spec.TeamSettings
+	defaultTeamSettings(teamSettings)
+	return teamSettings, nil
+}
+
+// devEnvironment returns the Development environment of the team creating it if it does not exist
+func (o *CommonOptions) devEnvironment() (*v1.Environment, error) {
 	jxClient, ns, err := o.JXClientAndDevNamespace()
 	if err != nil {
 		return nil, err
@@ -46,15 +57,17 @@ func (o *CommonOptions) TeamSettings() (*v1.TeamSettings, error) {
 	if env == nil {
 		return nil, fmt.Errorf("No Development environment found for namespace %s", ns)
 	}
+	return env, nil
+}
 
-	teamSettings := &env.Spec.TeamSettings
+// defaultTeamSettings sets the build packs of the team settings which are not specified to the default build packs
+func defaultTeamSettings(teamSettings *v1.TeamSettings) {
 	if teamSettings.BuildPackURL == "" {
 		teamSettings.BuildPackURL = JenkinsBuildPackURL
 	}
 	if teamSettings.BuildPackRef == "" {
 		teamSettings.BuildPackRef = defaultBuildPackRef
 	}
-	return teamSettings, nil
 }
 
 // TeamBranchPatterns returns the team branch patterns used to enable CI/CD on branches when creating/importing projects
@@ -171,7 +184,7 @@ func (o *CommonOptions) registerEnvironmentRoleBindingCRD() error {
 }
 
 func (o *CommonOptions) registerPipelineActivityCRD() error {
-	apisClient, err := o.Factory.CreateApiExtensionsClient()
+	apisClient, err := o.CreateApiExtensionsClient()
 	if err != nil {
 		return err
 	}
@@ -182,6 +195,18 @@ func (o *CommonOptions) registerPipelineActivityCRD() error {
 	return nil
 }
 
+func (o *CommonOptions) registerSourceRepositoryCRD() error {
+	apisClient, err := o.CreateApiExtensionsClient()
+	if err != nil {
+		return err
+	}
+	err = kube.RegisterSourceRepositoryCRD(apisClient)
+	if err != nil {
+		return errors.Wrap(err, "failed to register the SourceRepository CRD")
+	}
+	return nil
+}
+
 func (o *CommonOptions) registerWorkflowCRD() error {
 	apisClient, err := o.Factory.CreateApiExtensionsClient()
 	if err != nil {
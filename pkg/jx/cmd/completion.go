@@ -73,7 +73,7 @@ func NewCmdCompletion(f Factory, in terminal.FileReader, out terminal.FileWriter
 		},
 		ValidArgs: shells,
 	}
-
+	cmd.AddCommand(NewCmdCompletionValues(f, in, out, errOut))
 	return cmd
 }
 
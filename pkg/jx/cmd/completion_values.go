@@ -0,0 +1,171 @@
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"io/ioutil"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/ghodss/yaml"
+	"github.com/jenkins-x/jx/pkg/config"
+	"github.com/jenkins-x/jx/pkg/kube"
+	"github.com/jenkins-x/jx/pkg/util"
+	"github.com/spf13/cobra"
+	"gopkg.in/AlecAivazis/survey.v1/terminal"
+)
+
+const (
+	completionValuesPacks        = "packs"
+	completionValuesKinds        = "kinds"
+	completionValuesPodTemplates = "podtemplates"
+
+	completionIndexFileName = "completion-index.yml"
+
+	// the bash function which completes a flag with the values output by 'jx completion values'
+	bashCompletionFunc = `__jx_complete_values()
+{
+    local values
+    values=$(jx completion values "$1" 2>/dev/null)
+    COMPREPLY=( $(compgen -W "${values}" -- "$cur") )
+}
+`
+)
+
+var completionValueTypes = []string{completionValuesPacks, completionValuesKinds, completionValuesPodTemplates}
+
+// CompletionIndex the values which flags are completed with cached so that completion does not wait for the cluster
+// or the build packs every time
+type CompletionIndex struct {
+	Packs        []string  `json:"packs,omitempty"`
+	Kinds        []string  `json:"kinds,omitempty"`
+	PodTemplates []string  `json:"podTemplates,omitempty"`
+	Updated      time.Time `json:"updated"`
+}
+
+// CompletionValuesOptions contains the command line flags
+type CompletionValuesOptions struct {
+	CommonOptions
+
+	Refresh bool
+	MaxAge  time.Duration
+	Dir     string
+}
+
+// NewCmdCompletionValues creates the command which outputs the values a flag can be completed with
+func NewCmdCompletionValues(f Factory, in terminal.FileReader, out terminal.FileWriter, errOut io.Writer) *cobra.Command {
+	options := &CompletionValuesOptions{
+		CommonOptions: CommonOptions{
+			Factory: f,
+			In:      in,
+			Out:     out,
+			Err:     errOut,
+		},
+	}
+	cmd := &cobra.Command{
+		Use:    "values [" + strings.Join(completionValueTypes, "|") + "]",
+		Short:  "Outputs the values of a kind of flag used by the shell completion",
+		Hidden: true,
+		Run: func(cmd *cobra.Command, args []string) {
+			options.Cmd = cmd
+			options.Args = args
+			err := options.Run()
+			CheckErr(err)
+		},
+	}
+	cmd.Flags().BoolVarP(&options.Refresh, "refresh", "r", false, "Refreshes the cached values")
+	cmd.Flags().DurationVarP(&options.MaxAge, "max-age", "", 10*time.Minute, "How long the cached values are used before they are refreshed")
+	return cmd
+}
+
+// Run implements this command
+func (o *CompletionValuesOptions) Run() error {
+	if len(o.Args) != 1 || util.StringArrayIndex(completionValueTypes, o.Args[0]) < 0 {
+		return fmt.Errorf("Missing argument: the kind of values which is one of: %s", strings.Join(completionValueTypes, ", "))
+	}
+	index, err := o.loadCompletionIndex()
+	if err != nil {
+		return err
+	}
+	values := index.Kinds
+	switch o.Args[0] {
+	case completionValuesPacks:
+		values = index.Packs
+	case completionValuesPodTemplates:
+		values = index.PodTemplates
+	}
+	for _, v := range values {
+		_, err = fmt.Fprintln(o.Out, v)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// loadCompletionIndex loads the cached index or creates it if it does not exist, is too old or is being refreshed
+func (o *CompletionValuesOptions) loadCompletionIndex() (*CompletionIndex, error) {
+	dir := o.Dir
+	if dir == "" {
+		var err error
+		dir, err = util.CacheDir()
+		if err != nil {
+			return nil, err
+		}
+	}
+	fileName := filepath.Join(dir, completionIndexFileName)
+	index := &CompletionIndex{}
+	if !o.Refresh {
+		data, err := ioutil.ReadFile(fileName)
+		if err == nil && yaml.Unmarshal(data, index) == nil && time.Since(index.Updated) < o.MaxAge {
+			return index, nil
+		}
+	}
+	index = o.createCompletionIndex()
+	data, err := yaml.Marshal(index)
+	if err != nil {
+		return index, err
+	}
+	return index, ioutil.WriteFile(fileName, data, DefaultWritePermissions)
+}
+
+// createCompletionIndex finds the values of the flags. The build packs already cloned by 'jx import' are used and
+// values which cannot be found as the cluster is not available are left out so completion never fails
+func (o *CompletionValuesOptions) createCompletionIndex() *CompletionIndex {
+	index := &CompletionIndex{
+		Kinds:   append([]string{}, config.BuildKindNames...),
+		Updated: time.Now(),
+	}
+	settings, err := o.TeamSettings()
+	if err == nil {
+		dir, err := buildPackDir(settings.BuildPackURL)
+		if err == nil {
+			files, err := ioutil.ReadDir(filepath.Join(dir, "packs"))
+			if err == nil {
+				for _, f := range files {
+					if f.IsDir() {
+						index.Packs = append(index.Packs, f.Name())
+					}
+				}
+			}
+		}
+	}
+	kubeClient, ns, err := o.KubeClientAndDevNamespace()
+	if err == nil {
+		podTemplates, err := kube.LoadUnresolvedPodTemplates(kubeClient, ns)
+		if err == nil {
+			for name := range podTemplates {
+				index.PodTemplates = append(index.PodTemplates, name)
+			}
+			sort.Strings(index.PodTemplates)
+		}
+	}
+	return index
+}
+
+// addValueCompletion completes the flag of the command with the values of the given kind
+func addValueCompletion(cmd *cobra.Command, flag string, valueType string) {
+	cmd.Flags().SetAnnotation(flag, cobra.BashCompCustom, []string{"__jx_complete_values " + valueType})
+}
@@ -0,0 +1,58 @@
+package cmd_test
+
+import (
+	"io/ioutil"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/jenkins-x/jx/pkg/gits"
+	"github.com/jenkins-x/jx/pkg/helm"
+	"github.com/jenkins-x/jx/pkg/jx/cmd"
+	"github.com/jenkins-x/jx/pkg/kube"
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+)
+
+func TestCompletionValues(t *testing.T) {
+	t.Parallel()
+	dir, err := ioutil.TempDir("", "test-completion-values")
+	assert.NoError(t, err)
+	defer os.RemoveAll(dir)
+	cm := &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      kube.ConfigMapJenkinsPodTemplates,
+			Namespace: "jx",
+		},
+		Data: map[string]string{
+			"maven": "metadata:\n  name: maven\n",
+			"go":    "metadata:\n  name: go\n",
+		},
+	}
+
+	complete := func(valueType string, objects ...runtime.Object) string {
+		out, err := ioutil.TempFile("", "test-completion-values")
+		assert.NoError(t, err)
+		defer os.Remove(out.Name())
+		o := &cmd.CompletionValuesOptions{
+			Dir:    dir,
+			MaxAge: time.Hour,
+		}
+		cmd.ConfigureTestOptionsWithResources(&o.CommonOptions, objects, nil, gits.NewGitCLI(), helm.NewHelmCLI("helm", helm.V2, "", true))
+		o.Out = out
+		o.Args = []string{valueType}
+		err = o.Run()
+		assert.NoError(t, err)
+		data, err := ioutil.ReadFile(out.Name())
+		assert.NoError(t, err)
+		return string(data)
+	}
+
+	assert.Equal(t, "go\nmaven\n", complete("podtemplates", cm))
+	assert.Equal(t, "release\npullRequest\nfeature\ninfra\n", complete("kinds"))
+
+	// the cached pod templates are used even though the cluster no longer has them
+	assert.Equal(t, "go\nmaven\n", complete("podtemplates"))
+}
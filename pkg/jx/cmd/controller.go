@@ -49,9 +49,17 @@ func NewCmdController(f Factory, in terminal.FileReader, out terminal.FileWriter
 	}
 
 	cmd.AddCommand(NewCmdControllerBackup(f, in, out, errOut))
+	cmd.AddCommand(NewCmdControllerBadges(f, in, out, errOut))
+	cmd.AddCommand(NewCmdControllerBudget(f, in, out, errOut))
+	cmd.AddCommand(NewCmdControllerBuildPack(f, in, out, errOut))
 	cmd.AddCommand(NewCmdControllerBuild(f, in, out, errOut))
+	cmd.AddCommand(NewCmdControllerChain(f, in, out, errOut))
+	cmd.AddCommand(NewCmdControllerIssues(f, in, out, errOut))
+	cmd.AddCommand(NewCmdControllerPipelineMetrics(f, in, out, errOut))
+	cmd.AddCommand(NewCmdControllerPreemption(f, in, out, errOut))
 	cmd.AddCommand(NewCmdControllerRole(f, in, out, errOut))
 	cmd.AddCommand(NewCmdControllerTeam(f, in, out, errOut))
+	cmd.AddCommand(NewCmdControllerTracing(f, in, out, errOut))
 	cmd.AddCommand(NewCmdControllerWorkflow(f, in, out, errOut))
 	return cmd
 }
@@ -0,0 +1,94 @@
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+
+	"github.com/jenkins-x/jx/pkg/badges"
+	"github.com/jenkins-x/jx/pkg/jx/cmd/templates"
+	"github.com/jenkins-x/jx/pkg/log"
+	"github.com/jenkins-x/jx/pkg/util"
+	"github.com/spf13/cobra"
+	"gopkg.in/AlecAivazis/survey.v1/terminal"
+)
+
+var (
+	controllerBadgesLong = templates.LongDesc(`
+		Runs the badges controller which serves SVG status badges of the latest build of each pipeline from the PipelineActivity resources so that READMEs can show the build status of a repository.
+
+		The badge of the master branch of a repository is served at /owner/repository.svg and the badge of any other branch at /owner/repository/branch.svg
+`)
+
+	controllerBadgesExample = templates.Examples(`
+		# Serves the badges on port 8080
+		jx controller badges
+
+		# Then reference the badge in the README.md of a repository
+		![build status](http://badges.jx.example.com/badges/myorg/myapp.svg)
+	`)
+)
+
+// ControllerBadgesOptions are the flags for the commands
+type ControllerBadgesOptions struct {
+	ControllerOptions
+
+	Namespace string
+	Port      int
+	Path      string
+}
+
+// NewCmdControllerBadges creates the command
+func NewCmdControllerBadges(f Factory, in terminal.FileReader, out terminal.FileWriter, errOut io.Writer) *cobra.Command {
+	options := &ControllerBadgesOptions{
+		ControllerOptions: ControllerOptions{
+			CommonOptions: CommonOptions{
+				Factory: f,
+				In:      in,
+				Out:     out,
+				Err:     errOut,
+			},
+		},
+	}
+
+	cmd := &cobra.Command{
+		Use:     "badges",
+		Short:   "Runs the badges controller which serves SVG status badges of the pipelines",
+		Long:    controllerBadgesLong,
+		Example: controllerBadgesExample,
+		Run: func(cmd *cobra.Command, args []string) {
+			options.Cmd = cmd
+			options.Args = args
+			err := options.Run()
+			CheckErr(err)
+		},
+	}
+
+	options.addCommonFlags(cmd)
+	cmd.Flags().StringVarP(&options.Namespace, "namespace", "n", "", "The namespace of the PipelineActivity resources or defaults to the current namespace")
+	cmd.Flags().IntVarP(&options.Port, "port", "", 8080, "The port the badges are served on")
+	cmd.Flags().StringVarP(&options.Path, "path", "", "/badges", "The HTTP path prefix the badges are served on")
+	return cmd
+}
+
+// Run implements this command
+func (o *ControllerBadgesOptions) Run() error {
+	err := o.registerPipelineActivityCRD()
+	if err != nil {
+		return err
+	}
+	jxClient, devNs, err := o.JXClientAndDevNamespace()
+	if err != nil {
+		return err
+	}
+	ns := o.Namespace
+	if ns == "" {
+		ns = devNs
+	}
+	prefix := strings.TrimSuffix(o.Path, "/")
+	http.Handle(prefix+"/", http.StripPrefix(prefix, badges.NewHandler(jxClient, ns)))
+
+	log.Infof("Serving the badges of pipelines in namespace %s at %s on port %s\n", util.ColorInfo(ns), util.ColorInfo(prefix+"/"), util.ColorInfo(o.Port))
+	return http.ListenAndServe(fmt.Sprintf(":%d", o.Port), nil)
+}
@@ -0,0 +1,213 @@
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+	"time"
+
+	"github.com/jenkins-x/jx/pkg/builds"
+	"github.com/jenkins-x/jx/pkg/jx/cmd/templates"
+	"github.com/jenkins-x/jx/pkg/kube"
+	"github.com/jenkins-x/jx/pkg/log"
+	"github.com/jenkins-x/jx/pkg/util"
+	"github.com/spf13/cobra"
+	"gopkg.in/AlecAivazis/survey.v1/terminal"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+)
+
+const (
+	budgetControllerComponent = "jx-budget-controller"
+
+	// ReasonMaxDurationExceeded the reason of the event emitted when a build pod runs for longer than its budget
+	ReasonMaxDurationExceeded = "MaxDurationExceeded"
+	// ReasonMaxParallelPodsExceeded the reason of the event emitted when a pipeline runs more build pods than its budget
+	ReasonMaxParallelPodsExceeded = "MaxParallelPodsExceeded"
+)
+
+var (
+	controllerBudgetLong = templates.LongDesc(`
+		Runs the budget controller which cancels build pods which exceed the budget of their pipeline.
+
+		The budget is configured via the 'budget' section of the jenkins-x.yml file and is encoded into the builds by 'jx step create build'.
+		A Warning event is emitted for each cancelled build pod so that exceeded budgets can be reported on.
+`)
+
+	controllerBudgetExample = templates.Examples(`
+		# Runs the budget controller
+		jx controller budget
+
+		# Cancels any build pods which currently exceed their budget then exits
+		jx controller budget --no-watch
+	`)
+)
+
+// ControllerBudgetOptions are the flags for the commands
+type ControllerBudgetOptions struct {
+	ControllerOptions
+
+	Namespace    string
+	NoWatch      bool
+	PollDuration time.Duration
+}
+
+// NewCmdControllerBudget creates the command
+func NewCmdControllerBudget(f Factory, in terminal.FileReader, out terminal.FileWriter, errOut io.Writer) *cobra.Command {
+	options := &ControllerBudgetOptions{
+		ControllerOptions: ControllerOptions{
+			CommonOptions: CommonOptions{
+				Factory: f,
+				In:      in,
+				Out:     out,
+				Err:     errOut,
+			},
+		},
+	}
+
+	cmd := &cobra.Command{
+		Use:     "budget",
+		Short:   "Runs the budget controller which cancels build pods which exceed the budget of their pipeline",
+		Long:    controllerBudgetLong,
+		Example: controllerBudgetExample,
+		Run: func(cmd *cobra.Command, args []string) {
+			options.Cmd = cmd
+			options.Args = args
+			err := options.Run()
+			CheckErr(err)
+		},
+	}
+
+	options.addCommonFlags(cmd)
+	cmd.Flags().StringVarP(&options.Namespace, "namespace", "n", "", "The namespace to watch or defaults to the current namespace")
+	cmd.Flags().BoolVarP(&options.NoWatch, "no-watch", "", false, "Disable watch so just cancels any build pods which currently exceed their budget")
+	cmd.Flags().DurationVarP(&options.PollDuration, "poll-duration", "", time.Second*30, "How often to check the build pods")
+	return cmd
+}
+
+// Run implements this command
+func (o *ControllerBudgetOptions) Run() error {
+	kubeClient, devNs, err := o.KubeClientAndDevNamespace()
+	if err != nil {
+		return err
+	}
+	ns := o.Namespace
+	if ns == "" {
+		ns = devNs
+	}
+	if o.NoWatch {
+		return o.checkBudgets(kubeClient, ns, time.Now())
+	}
+
+	log.Infof("Checking the budgets of build pods in namespace %s every %s\n", util.ColorInfo(ns), util.ColorInfo(o.PollDuration.String()))
+	for {
+		err = o.checkBudgets(kubeClient, ns, time.Now())
+		if err != nil {
+			log.Warnf("Failed to check the budgets of build pods: %s\n", err)
+		}
+		time.Sleep(o.PollDuration)
+	}
+}
+
+// checkBudgets cancels the active build pods which have run for longer than their maximum duration or which
+// exceed the maximum number of parallel pods of their pipeline. The most recently created pods are cancelled first
+func (o *ControllerBudgetOptions) checkBudgets(kubeClient kubernetes.Interface, ns string, now time.Time) error {
+	pods, err := builds.GetBuildPods(kubeClient, ns)
+	if err != nil {
+		return err
+	}
+	active := []*corev1.Pod{}
+	for _, pod := range pods {
+		if pod.DeletionTimestamp == nil && (pod.Status.Phase == corev1.PodPending || pod.Status.Phase == corev1.PodRunning) {
+			active = append(active, pod)
+		}
+	}
+	sort.Slice(active, func(i, j int) bool {
+		return active[i].CreationTimestamp.Before(&active[j].CreationTimestamp)
+	})
+
+	pipelinePods := map[string][]*corev1.Pod{}
+	for _, pod := range active {
+		if pod.Annotations == nil {
+			continue
+		}
+		maxDuration := pod.Annotations[kube.AnnotationMaxDuration]
+		if maxDuration != "" {
+			d, err := time.ParseDuration(maxDuration)
+			if err != nil {
+				log.Warnf("Ignoring invalid %s annotation %s on pod %s: %s\n", kube.AnnotationMaxDuration, maxDuration, pod.Name, err)
+			} else {
+				started := pod.CreationTimestamp.Time
+				if pod.Status.StartTime != nil {
+					started = pod.Status.StartTime.Time
+				}
+				if now.Sub(started) > d {
+					o.cancelBuildPod(kubeClient, ns, pod, ReasonMaxDurationExceeded,
+						fmt.Sprintf("Build pod %s exceeded its maximum duration of %s", pod.Name, maxDuration))
+					continue
+				}
+			}
+		}
+		if pod.Annotations[kube.AnnotationMaxParallelPods] != "" && pod.Labels != nil && pod.Labels[kube.LabelPipeline] != "" {
+			pipeline := pod.Labels[kube.LabelPipeline]
+			pipelinePods[pipeline] = append(pipelinePods[pipeline], pod)
+		}
+	}
+
+	for pipeline, pods := range pipelinePods {
+		text := pods[len(pods)-1].Annotations[kube.AnnotationMaxParallelPods]
+		maxParallel, err := strconv.Atoi(text)
+		if err != nil || maxParallel <= 0 {
+			log.Warnf("Ignoring invalid %s annotation %s on pipeline %s\n", kube.AnnotationMaxParallelPods, text, pipeline)
+			continue
+		}
+		if len(pods) <= maxParallel {
+			continue
+		}
+		for _, pod := range pods[maxParallel:] {
+			o.cancelBuildPod(kubeClient, ns, pod, ReasonMaxParallelPodsExceeded,
+				fmt.Sprintf("Build pod %s exceeded the maximum of %d parallel pods of pipeline %s", pod.Name, maxParallel, pipeline))
+		}
+	}
+	return nil
+}
+
+// cancelBuildPod deletes the build pod and emits a Warning event recording why it was cancelled
+func (o *ControllerBudgetOptions) cancelBuildPod(kubeClient kubernetes.Interface, ns string, pod *corev1.Pod, reason string, message string) {
+	err := kubeClient.CoreV1().Pods(ns).Delete(pod.Name, &metav1.DeleteOptions{})
+	if err != nil {
+		log.Warnf("Failed to cancel build pod %s: %s\n", pod.Name, err)
+		return
+	}
+	log.Infof("%s\n", message)
+
+	now := metav1.Now()
+	event := &corev1.Event{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      fmt.Sprintf("%s.%x", pod.Name, now.UnixNano()),
+			Namespace: ns,
+		},
+		InvolvedObject: corev1.ObjectReference{
+			APIVersion: "v1",
+			Kind:       "Pod",
+			Name:       pod.Name,
+			Namespace:  ns,
+			UID:        pod.UID,
+		},
+		Reason:         reason,
+		Message:        message,
+		Type:           corev1.EventTypeWarning,
+		Count:          1,
+		FirstTimestamp: now,
+		LastTimestamp:  now,
+		Source: corev1.EventSource{
+			Component: budgetControllerComponent,
+		},
+	}
+	_, err = kubeClient.CoreV1().Events(ns).Create(event)
+	if err != nil {
+		log.Warnf("Failed to create event for cancelled build pod %s: %s\n", pod.Name, err)
+	}
+}
@@ -0,0 +1,81 @@
+package cmd_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/jenkins-x/jx/pkg/builds"
+	"github.com/jenkins-x/jx/pkg/gits"
+	"github.com/jenkins-x/jx/pkg/helm"
+	"github.com/jenkins-x/jx/pkg/jx/cmd"
+	"github.com/jenkins-x/jx/pkg/kube"
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+)
+
+func TestControllerBudget(t *testing.T) {
+	t.Parallel()
+	now := time.Now()
+	buildPod := func(name string, pipeline string, age time.Duration, annotations map[string]string) *corev1.Pod {
+		created := metav1.NewTime(now.Add(-age))
+		return &corev1.Pod{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:              name,
+				Namespace:         "jx",
+				CreationTimestamp: created,
+				Labels: map[string]string{
+					builds.LabelBuildName: name,
+					kube.LabelPipeline:    pipeline,
+				},
+				Annotations: annotations,
+			},
+			Status: corev1.PodStatus{
+				Phase:     corev1.PodRunning,
+				StartTime: &created,
+			},
+		}
+	}
+	durationBudget := map[string]string{kube.AnnotationMaxDuration: "30m"}
+	parallelBudget := map[string]string{kube.AnnotationMaxParallelPods: "2"}
+
+	k8sObjects := []runtime.Object{
+		buildPod("slow-1", "slow", time.Hour, durationBudget),
+		buildPod("slow-2", "slow", time.Minute*10, durationBudget),
+		buildPod("busy-1", "busy", time.Minute*3, parallelBudget),
+		buildPod("busy-2", "busy", time.Minute*2, parallelBudget),
+		buildPod("busy-3", "busy", time.Minute, parallelBudget),
+		buildPod("quiet-1", "quiet", time.Minute, parallelBudget),
+		buildPod("unlimited-1", "unlimited", time.Hour*24, nil),
+	}
+
+	o := &cmd.ControllerBudgetOptions{
+		NoWatch: true,
+	}
+	cmd.ConfigureTestOptionsWithResources(&o.CommonOptions, k8sObjects, nil, gits.NewGitCLI(), helm.NewHelmCLI("helm", helm.V2, "", true))
+
+	err := o.Run()
+	assert.NoError(t, err)
+
+	kubeClient, ns, err := o.KubeClientAndDevNamespace()
+	assert.NoError(t, err)
+	pods, err := kubeClient.CoreV1().Pods(ns).List(metav1.ListOptions{})
+	assert.NoError(t, err)
+	remaining := []string{}
+	for _, pod := range pods.Items {
+		remaining = append(remaining, pod.Name)
+	}
+	assert.ElementsMatch(t, []string{"slow-2", "busy-1", "busy-2", "quiet-1", "unlimited-1"}, remaining)
+
+	events, err := kubeClient.CoreV1().Events(ns).List(metav1.ListOptions{})
+	assert.NoError(t, err)
+	reasons := map[string]string{}
+	for _, event := range events.Items {
+		reasons[event.InvolvedObject.Name] = event.Reason
+	}
+	assert.Equal(t, map[string]string{
+		"slow-1": cmd.ReasonMaxDurationExceeded,
+		"busy-3": cmd.ReasonMaxParallelPodsExceeded,
+	}, reasons)
+}
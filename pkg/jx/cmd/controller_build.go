@@ -18,6 +18,7 @@ import (
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/fields"
+	"k8s.io/client-go/kubernetes"
 	"k8s.io/client-go/tools/cache"
 
 	"github.com/jenkins-x/jx/pkg/kube"
@@ -27,7 +28,8 @@ import (
 type ControllerBuildOptions struct {
 	ControllerOptions
 
-	Namespace string
+	Namespace     string
+	LogsBucketURL string
 }
 
 // NewCmdControllerBuild creates a command object for the generic "get" action, which
@@ -57,6 +59,7 @@ func NewCmdControllerBuild(f Factory, in terminal.FileReader, out terminal.FileW
 	}
 
 	cmd.Flags().StringVarP(&options.Namespace, "namespace", "n", "", "The namespace to watch or defaults to the current namespace")
+	cmd.Flags().StringVarP(&options.LogsBucketURL, "logs-bucket-url", "", "", "The URL of the bucket such as s3://mybucket/logs or gs://mybucket/logs to archive the logs of completed builds to. Defaults to the logsBucketUrl of the build defaults of the team")
 	return cmd
 }
 
@@ -85,6 +88,16 @@ func (o *ControllerBuildOptions) Run() error {
 	if ns == "" {
 		ns = devNs
 	}
+	if o.LogsBucketURL == "" {
+		teamSettings, err := o.TeamSettings()
+		if err != nil {
+			return err
+		}
+		o.LogsBucketURL = teamSettings.BuildDefaults.LogsBucketURL
+	}
+	if o.LogsBucketURL != "" {
+		log.Infof("Archiving the logs of completed builds to %s\n", util.ColorInfo(o.LogsBucketURL))
+	}
 	pod := &corev1.Pod{}
 	log.Infof("Watching for Knative build pods in namespace %s\n", util.ColorInfo(ns))
 	listWatch := cache.NewListWatchFromClient(client.CoreV1().RESTClient(), "pods", ns, fields.Everything())
@@ -95,10 +108,10 @@ func (o *ControllerBuildOptions) Run() error {
 		time.Minute*10,
 		cache.ResourceEventHandlerFuncs{
 			AddFunc: func(obj interface{}) {
-				o.onPod(obj, jxClient, ns)
+				o.onPod(obj, client, jxClient, ns)
 			},
 			UpdateFunc: func(oldObj, newObj interface{}) {
-				o.onPod(newObj, jxClient, ns)
+				o.onPod(newObj, client, jxClient, ns)
 			},
 			DeleteFunc: func(obj interface{}) {
 			},
@@ -112,7 +125,7 @@ func (o *ControllerBuildOptions) Run() error {
 	select {}
 }
 
-func (o *ControllerBuildOptions) onPod(obj interface{}, jxClient versioned.Interface, ns string) {
+func (o *ControllerBuildOptions) onPod(obj interface{}, kubeClient kubernetes.Interface, jxClient versioned.Interface, ns string) {
 	pod, ok := obj.(*corev1.Pod)
 	if !ok {
 		log.Infof("Object is not a Pod %#v\n", obj)
@@ -137,7 +150,21 @@ func (o *ControllerBuildOptions) onPod(obj interface{}, jxClient versioned.Inter
 						log.Warnf("Failed to %s PipelineActivities for build %s: %s\n", operation, buildName, err)
 					}
 
-					if o.updatePipelineActivity(a, buildName, pod) {
+					updated := o.updatePipelineActivity(a, buildName, pod)
+					if copyBuildLinks(a, pod) {
+						updated = true
+					}
+					if o.LogsBucketURL != "" && a.Spec.BuildLogsURL == "" && isActivityCompleted(a) {
+						logsURL, err := archiveBuildLogs(kubeClient, ns, pod, a, o.LogsBucketURL)
+						if err != nil {
+							log.Warnf("Failed to archive the logs of build %s: %s\n", buildName, err)
+						} else {
+							log.Infof("Archived the logs of build %s to %s\n", util.ColorInfo(buildName), util.ColorInfo(logsURL))
+							a.Spec.BuildLogsURL = logsURL
+							updated = true
+						}
+					}
+					if updated {
 						_, err := activities.Update(a)
 						if err != nil {
 							log.Warnf("Failed to update PipelineActivities%s: %s\n", a.Name, err)
@@ -152,9 +179,9 @@ func (o *ControllerBuildOptions) onPod(obj interface{}, jxClient versioned.Inter
 // createPromoteStepActivityKey deduces the pipeline metadata from the Knative build pod
 func (o *ControllerBuildOptions) createPromoteStepActivityKey(buildName string, pod *corev1.Pod) *kube.PromoteStepActivityKey {
 	branch := ""
-	lastCommitSha := ""
+	lastCommitSha := pod.Annotations[kube.AnnotationCommitSHA]
 	lastCommitMessage := ""
-	lastCommitURL := ""
+	lastCommitURL := pod.Annotations[kube.AnnotationCommitURL]
 	build := DigitSuffix(buildName)
 	if build == "" {
 		build = "1"
@@ -205,13 +232,29 @@ func (o *ControllerBuildOptions) createPromoteStepActivityKey(buildName string,
 	}
 }
 
+// copyBuildLinks copies the links of the build pod to the pull request and the issues it was run for onto the
+// activity returning true if the activity was modified
+func copyBuildLinks(activity *v1.PipelineActivity, pod *corev1.Pod) bool {
+	updated := false
+	for _, k := range []string{kube.AnnotationPullRequestURL, kube.AnnotationIssues, kube.AnnotationIssueTrackerKind, kube.AnnotationIssueTrackerURL} {
+		value := pod.Annotations[k]
+		if value == "" || activity.Annotations[k] == value {
+			continue
+		}
+		if activity.Annotations == nil {
+			activity.Annotations = map[string]string{}
+		}
+		activity.Annotations[k] = value
+		updated = true
+	}
+	return updated
+}
+
 func (o *ControllerBuildOptions) updatePipelineActivity(activity *v1.PipelineActivity, s string, pod *corev1.Pod) bool {
 	copy := *activity
 	// TODO update the steps based on the Knative build pod's init containers
 	for _, c := range pod.Status.InitContainerStatuses {
-		name := strings.Replace(strings.TrimPrefix(c.Name, "build-step-"), "-", " ", -1)
-		title := strings.Title(name)
-		_, stage, _ := kube.GetOrCreateStage(activity, title)
+		_, stage, _ := kube.GetOrCreateStage(activity, buildStepStageName(c.Name))
 
 		running := c.State.Running
 		terminated := c.State.Terminated
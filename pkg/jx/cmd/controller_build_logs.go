@@ -0,0 +1,59 @@
+package cmd
+
+import (
+	"bytes"
+	"fmt"
+	"io/ioutil"
+	"path"
+
+	"github.com/jenkins-x/jx/pkg/apis/jenkins.io/v1"
+	"github.com/jenkins-x/jx/pkg/cloud/buckets"
+	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/client-go/kubernetes"
+)
+
+const (
+	// buildLogsArchivePath the path in the logs bucket the build logs are archived in
+	buildLogsArchivePath = "jenkins-x/logs"
+	// buildLogStepPrefix the prefix of the lines of an archived build log which start the log of a step
+	buildLogStepPrefix = "==> step: "
+)
+
+// isActivityCompleted returns true if the pipeline of the activity has succeeded or failed
+func isActivityCompleted(activity *v1.PipelineActivity) bool {
+	status := activity.Spec.Status
+	return status == v1.ActivityStatusTypeSucceeded || status == v1.ActivityStatusTypeFailed
+}
+
+// buildLogsArchiveFile returns the path of the archived log of the build of the activity in the logs bucket
+func buildLogsArchiveFile(activity *v1.PipelineActivity) string {
+	return path.Join(buildLogsArchivePath, activity.Spec.Pipeline, activity.Spec.Build+".log")
+}
+
+// archiveBuildLogs uploads the logs of the steps of the completed Knative build pod to the bucket and returns the URL
+// of the archived log
+func archiveBuildLogs(kubeClient kubernetes.Interface, ns string, pod *corev1.Pod, activity *v1.PipelineActivity, bucketURL string) (string, error) {
+	var buffer bytes.Buffer
+	for _, c := range pod.Spec.InitContainers {
+		data, err := containerLog(kubeClient, ns, pod.Name, c.Name)
+		if err != nil {
+			return "", errors.Wrapf(err, "failed to get the log of step %s of pod %s", c.Name, pod.Name)
+		}
+		buffer.WriteString(fmt.Sprintf("%s%s\n", buildLogStepPrefix, c.Name))
+		buffer.Write(data)
+		if len(data) > 0 && data[len(data)-1] != '\n' {
+			buffer.WriteString("\n")
+		}
+	}
+	return buckets.WriteFile(bucketURL, buildLogsArchiveFile(activity), buffer.Bytes(), "text/plain; charset=utf-8")
+}
+
+func containerLog(kubeClient kubernetes.Interface, ns string, podName string, containerName string) ([]byte, error) {
+	stream, err := kubeClient.CoreV1().Pods(ns).GetLogs(podName, &corev1.PodLogOptions{Container: containerName}).Stream()
+	if err != nil {
+		return nil, err
+	}
+	defer stream.Close()
+	return ioutil.ReadAll(stream)
+}
@@ -0,0 +1,300 @@
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/jenkins-x/jx/pkg/client/clientset/versioned"
+	"github.com/jenkins-x/jx/pkg/jx/cmd/templates"
+	"github.com/jenkins-x/jx/pkg/kube"
+	"github.com/jenkins-x/jx/pkg/log"
+	"github.com/jenkins-x/jx/pkg/util"
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+	"gopkg.in/AlecAivazis/survey.v1/terminal"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+)
+
+const (
+	// ConfigMapBuildPackController the ConfigMap containing the state of the build pack controller
+	ConfigMapBuildPackController = "jx-buildpack-controller"
+
+	buildPackControllerSHAKey = "buildPackSha"
+)
+
+// RegenerateFn regenerates and applies the builds of the app in the given git repository
+type RegenerateFn func(pipeline string, gitURL string) error
+
+var (
+	controllerBuildPackLong = templates.LongDesc(`
+		Runs the build pack controller which regenerates and applies the builds of every app whenever the team build pack changes.
+
+		The build pack git repository is polled periodically and can also be checked immediately via a webhook.
+		The apps are those whose builds have been generated by 'jx step create build' as recorded in the pipeline audit log.
+`)
+
+	controllerBuildPackExample = templates.Examples(`
+		# Runs the build pack controller
+		jx controller buildpack
+
+		# Runs the build pack controller with a webhook on port 8080 which build pack repository pushes can trigger
+		jx controller buildpack --webhook-port 8080
+	`)
+)
+
+// ControllerBuildPackOptions are the flags for the commands
+type ControllerBuildPackOptions struct {
+	ControllerOptions
+
+	PollDuration time.Duration
+	WebhookPort  int
+	NoWatch      bool
+
+	// testing
+	FakeBuildPackSHA func() (string, error)
+	FakeRegenerate   RegenerateFn
+}
+
+// NewCmdControllerBuildPack creates the command
+func NewCmdControllerBuildPack(f Factory, in terminal.FileReader, out terminal.FileWriter, errOut io.Writer) *cobra.Command {
+	options := &ControllerBuildPackOptions{
+		ControllerOptions: ControllerOptions{
+			CommonOptions: CommonOptions{
+				Factory: f,
+				In:      in,
+				Out:     out,
+				Err:     errOut,
+			},
+		},
+	}
+
+	cmd := &cobra.Command{
+		Use:     "buildpack",
+		Short:   "Runs the build pack controller which regenerates the builds of apps when the team build pack changes",
+		Long:    controllerBuildPackLong,
+		Example: controllerBuildPackExample,
+		Run: func(cmd *cobra.Command, args []string) {
+			options.Cmd = cmd
+			options.Args = args
+			err := options.Run()
+			CheckErr(err)
+		},
+	}
+
+	options.addCommonFlags(cmd)
+	cmd.Flags().DurationVarP(&options.PollDuration, "poll-duration", "", time.Minute*5, "How often to poll the build pack git repository for changes")
+	cmd.Flags().IntVarP(&options.WebhookPort, "webhook-port", "", 0, "The port of a webhook which checks the build pack for changes when invoked. Disabled if 0")
+	cmd.Flags().BoolVarP(&options.NoWatch, "no-watch", "", false, "Disable watch so just checks the build pack for changes once")
+	return cmd
+}
+
+// Run implements this command
+func (o *ControllerBuildPackOptions) Run() error {
+	if o.NoWatch {
+		return o.checkBuildPack()
+	}
+
+	trigger := make(chan struct{}, 1)
+	if o.WebhookPort > 0 {
+		mux := http.NewServeMux()
+		mux.HandleFunc("/", func(w http.ResponseWriter, r *http.Request) {
+			select {
+			case trigger <- struct{}{}:
+			default:
+			}
+			w.WriteHeader(http.StatusAccepted)
+		})
+		go func() {
+			err := http.ListenAndServe(fmt.Sprintf(":%d", o.WebhookPort), mux)
+			if err != nil {
+				log.Warnf("The build pack webhook failed: %s\n", err)
+			}
+		}()
+		log.Infof("Listening for build pack webhooks on port %s\n", util.ColorInfo(o.WebhookPort))
+	}
+
+	log.Infof("Polling the build pack for changes every %s\n", util.ColorInfo(o.PollDuration.String()))
+	for {
+		err := o.checkBuildPack()
+		if err != nil {
+			log.Warnf("Failed to check the build pack for changes: %s\n", err)
+		}
+		select {
+		case <-trigger:
+		case <-time.After(o.PollDuration):
+		}
+	}
+}
+
+// checkBuildPack regenerates the builds of all the apps if the commit of the build pack has changed since
+// the last time the builds were regenerated
+func (o *ControllerBuildPackOptions) checkBuildPack() error {
+	kubeClient, ns, err := o.KubeClientAndDevNamespace()
+	if err != nil {
+		return err
+	}
+	sha, err := o.buildPackSHA()
+	if err != nil {
+		return err
+	}
+	configMaps := kubeClient.CoreV1().ConfigMaps(ns)
+	cm, err := configMaps.Get(ConfigMapBuildPackController, metav1.GetOptions{})
+	if err != nil {
+		cm = &corev1.ConfigMap{
+			ObjectMeta: metav1.ObjectMeta{
+				Name: ConfigMapBuildPackController,
+			},
+		}
+		cm, err = configMaps.Create(cm)
+		if err != nil {
+			return err
+		}
+	}
+	if cm.Data == nil {
+		cm.Data = map[string]string{}
+	}
+	if cm.Data[buildPackControllerSHAKey] == sha {
+		return nil
+	}
+	log.Infof("The build pack has changed to commit %s so regenerating the builds of all apps\n", util.ColorInfo(sha))
+
+	jxClient, _, err := o.JXClient()
+	if err != nil {
+		return err
+	}
+	apps, err := registeredApps(kubeClient, jxClient, ns)
+	if err != nil {
+		return err
+	}
+	pipelines := []string{}
+	for pipeline := range apps {
+		pipelines = append(pipelines, pipeline)
+	}
+	sort.Strings(pipelines)
+	failed := []string{}
+	progress := log.StartProgress(os.Stderr, o.BatchMode, "Regenerating the builds of %d apps", len(pipelines))
+	defer progress.Stop()
+	for i, pipeline := range pipelines {
+		progress.Update("Regenerating the builds of %s (%d/%d)", pipeline, i+1, len(pipelines))
+		err = o.regenerate(pipeline, apps[pipeline])
+		if err != nil {
+			log.Warnf("Failed to regenerate the builds of %s: %s\n", pipeline, err)
+			failed = append(failed, pipeline)
+			continue
+		}
+		log.Infof("Regenerated the builds of %s\n", util.ColorInfo(pipeline))
+	}
+	if len(failed) > 0 {
+		// lets retry on the next check
+		return fmt.Errorf("failed to regenerate the builds of %s", strings.Join(failed, ", "))
+	}
+	cm.Data[buildPackControllerSHAKey] = sha
+	_, err = configMaps.Update(cm)
+	return err
+}
+
+// registeredApps returns the git URLs of the apps keyed by pipeline whose builds have been generated according to
+// the pipeline audit log or which are registered as a SourceRepository
+func registeredApps(kubeClient kubernetes.Interface, jxClient versioned.Interface, ns string) (map[string]string, error) {
+	records, err := kube.LoadPipelineAuditRecords(kubeClient, ns, "")
+	if err != nil {
+		return nil, err
+	}
+	answer := map[string]string{}
+	for _, r := range records {
+		// records are sorted by time so the latest git URL wins
+		if r.Action == kube.PipelineAuditActionGenerate && r.Pipeline != "" && r.SourceURL != "" {
+			answer[r.Pipeline] = r.SourceURL
+		}
+	}
+	repos, err := jxClient.JenkinsV1().SourceRepositories(ns).List(metav1.ListOptions{})
+	if err != nil {
+		// the SourceRepository CRD is optional
+		return answer, nil
+	}
+	for _, repo := range repos.Items {
+		if repo.Spec.Repo != "" && repo.Spec.URL != "" {
+			answer[repo.Spec.Repo] = repo.Spec.URL
+		}
+	}
+	return answer, nil
+}
+
+// buildPackSHA pulls the team build pack git repository returning its latest commit
+func (o *ControllerBuildPackOptions) buildPackSHA() (string, error) {
+	if o.FakeBuildPackSHA != nil {
+		return o.FakeBuildPackSHA()
+	}
+	settings, err := o.TeamSettings()
+	if err != nil {
+		return "", err
+	}
+	dir, err := buildPackDir(settings.BuildPackURL)
+	if err != nil {
+		return "", err
+	}
+	err = os.MkdirAll(dir, DefaultWritePermissions)
+	if err != nil {
+		return "", err
+	}
+	progress := log.StartProgress(os.Stderr, o.BatchMode, "Pulling the build packs %s", settings.BuildPackURL)
+	defer progress.Stop()
+	err = o.Git().CloneOrPull(settings.BuildPackURL, dir)
+	if err != nil {
+		return "", err
+	}
+	if settings.BuildPackRef != "" && settings.BuildPackRef != "master" {
+		err = o.Git().CheckoutRemoteBranch(dir, settings.BuildPackRef)
+		if err != nil {
+			return "", err
+		}
+	}
+	return o.Git().GetLatestCommitSha(dir)
+}
+
+// regenerate clones the app then generates and applies its builds
+func (o *ControllerBuildPackOptions) regenerate(pipeline string, gitURL string) error {
+	if o.FakeRegenerate != nil {
+		return o.FakeRegenerate(pipeline, gitURL)
+	}
+	tmpDir, err := util.CreateWorkspace("jx-buildpack-controller-")
+	if err != nil {
+		return err
+	}
+	defer os.RemoveAll(tmpDir)
+
+	dir := filepath.Join(tmpDir, pipeline)
+	err = o.Git().Clone(gitURL, dir)
+	if err != nil {
+		return errors.Wrapf(err, "failed to clone %s", gitURL)
+	}
+	outDir := filepath.Join(tmpDir, "builds")
+	createBuild := &StepCreateBuildOptions{
+		StepOptions: StepOptions{
+			CommonOptions: o.CommonOptions,
+		},
+		Dir:              dir,
+		OutputDir:        outDir,
+		OutputFilePrefix: "build-",
+		BuildNumber:      1,
+	}
+	err = createBuild.Run()
+	if err != nil {
+		return err
+	}
+	applyBuild := &StepApplyBuildOptions{
+		StepOptions: StepOptions{
+			CommonOptions: o.CommonOptions,
+		},
+		Dir: outDir,
+	}
+	return applyBuild.Run()
+}
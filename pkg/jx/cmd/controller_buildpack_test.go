@@ -0,0 +1,56 @@
+package cmd_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/jenkins-x/jx/pkg/gits"
+	"github.com/jenkins-x/jx/pkg/helm"
+	"github.com/jenkins-x/jx/pkg/jx/cmd"
+	"github.com/jenkins-x/jx/pkg/kube"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestControllerBuildPack(t *testing.T) {
+	t.Parallel()
+	sha := "1111"
+	regenerated := []string{}
+	o := &cmd.ControllerBuildPackOptions{
+		NoWatch: true,
+		FakeBuildPackSHA: func() (string, error) {
+			return sha, nil
+		},
+		FakeRegenerate: func(pipeline string, gitURL string) error {
+			regenerated = append(regenerated, pipeline+"="+gitURL)
+			return nil
+		},
+	}
+	cmd.ConfigureTestOptionsWithResources(&o.CommonOptions, nil, nil, gits.NewGitCLI(), helm.NewHelmCLI("helm", helm.V2, "", true))
+
+	kubeClient, ns, err := o.KubeClientAndDevNamespace()
+	assert.NoError(t, err)
+	now := time.Now()
+	records := []*kube.PipelineAuditRecord{
+		{Pipeline: "app1", Action: kube.PipelineAuditActionGenerate, SourceURL: "https://github.com/myorg/old-app1.git", Timestamp: now.Add(-time.Hour)},
+		{Pipeline: "app1", Action: kube.PipelineAuditActionGenerate, SourceURL: "https://github.com/myorg/app1.git", Timestamp: now},
+		{Pipeline: "app2", Action: kube.PipelineAuditActionGenerate, SourceURL: "https://github.com/myorg/app2.git", Timestamp: now},
+		{Pipeline: "app3", Action: kube.PipelineAuditActionApply, Timestamp: now},
+	}
+	for _, r := range records {
+		assert.NoError(t, kube.SavePipelineAuditRecord(kubeClient, ns, r))
+	}
+
+	err = o.Run()
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"app1=https://github.com/myorg/app1.git", "app2=https://github.com/myorg/app2.git"}, regenerated)
+
+	regenerated = []string{}
+	err = o.Run()
+	assert.NoError(t, err)
+	assert.Empty(t, regenerated, "should not regenerate when the build pack has not changed")
+
+	sha = "2222"
+	err = o.Run()
+	assert.NoError(t, err)
+	assert.Equal(t, 2, len(regenerated))
+}
@@ -0,0 +1,176 @@
+package cmd
+
+import (
+	"io"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/jenkins-x/jx/pkg/apis/jenkins.io/v1"
+	"github.com/jenkins-x/jx/pkg/client/clientset/versioned"
+	"github.com/jenkins-x/jx/pkg/kube"
+	"github.com/jenkins-x/jx/pkg/log"
+	"github.com/jenkins-x/jx/pkg/util"
+	"github.com/spf13/cobra"
+	"gopkg.in/AlecAivazis/survey.v1/terminal"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/fields"
+	"k8s.io/client-go/tools/cache"
+)
+
+// TriggerPipelineFn triggers the given pipeline with the parameters
+type TriggerPipelineFn func(pipeline string, params url.Values) error
+
+// ControllerChainOptions are the flags for the commands
+type ControllerChainOptions struct {
+	ControllerOptions
+
+	Namespace string
+	NoWatch   bool
+
+	// testing
+	FakeTrigger TriggerPipelineFn
+}
+
+// NewCmdControllerChain creates the command
+func NewCmdControllerChain(f Factory, in terminal.FileReader, out terminal.FileWriter, errOut io.Writer) *cobra.Command {
+	options := &ControllerChainOptions{
+		ControllerOptions: ControllerOptions{
+			CommonOptions: CommonOptions{
+				Factory: f,
+				In:      in,
+				Out:     out,
+				Err:     errOut,
+			},
+		},
+	}
+
+	cmd := &cobra.Command{
+		Use:   "chain",
+		Short: "Runs the chain controller which triggers downstream pipelines when a pipeline succeeds",
+		Run: func(cmd *cobra.Command, args []string) {
+			options.Cmd = cmd
+			options.Args = args
+			err := options.Run()
+			CheckErr(err)
+		},
+	}
+
+	options.addCommonFlags(cmd)
+	cmd.Flags().StringVarP(&options.Namespace, "namespace", "n", "", "The namespace to watch or defaults to the current namespace")
+	cmd.Flags().BoolVarP(&options.NoWatch, "no-watch", "", false, "Disable watch so just triggers the downstream pipelines of any succeeded pipelines")
+	return cmd
+}
+
+// Run implements this command
+func (o *ControllerChainOptions) Run() error {
+	err := o.registerPipelineActivityCRD()
+	if err != nil {
+		return err
+	}
+	jxClient, devNs, err := o.JXClientAndDevNamespace()
+	if err != nil {
+		return err
+	}
+	ns := o.Namespace
+	if ns == "" {
+		ns = devNs
+	}
+
+	if o.NoWatch {
+		activities, err := jxClient.JenkinsV1().PipelineActivities(ns).List(metav1.ListOptions{})
+		if err != nil {
+			return err
+		}
+		for i := range activities.Items {
+			o.onActivity(&activities.Items[i], jxClient, ns)
+		}
+		return nil
+	}
+
+	log.Infof("Watching for PipelineActivity resources in namespace %s\n", util.ColorInfo(ns))
+	activity := &v1.PipelineActivity{}
+	listWatch := cache.NewListWatchFromClient(jxClient.JenkinsV1().RESTClient(), "pipelineactivities", ns, fields.Everything())
+	kube.SortListWatchByName(listWatch)
+	_, controller := cache.NewInformer(
+		listWatch,
+		activity,
+		time.Minute*10,
+		cache.ResourceEventHandlerFuncs{
+			AddFunc: func(obj interface{}) {
+				o.onActivityObj(obj, jxClient, ns)
+			},
+			UpdateFunc: func(oldObj, newObj interface{}) {
+				o.onActivityObj(newObj, jxClient, ns)
+			},
+			DeleteFunc: func(obj interface{}) {
+			},
+		},
+	)
+
+	stop := make(chan struct{})
+	go controller.Run(stop)
+
+	// Wait forever
+	select {}
+}
+
+func (o *ControllerChainOptions) onActivityObj(obj interface{}, jxClient versioned.Interface, ns string) {
+	activity, ok := obj.(*v1.PipelineActivity)
+	if !ok {
+		log.Infof("Object is not a PipelineActivity %#v\n", obj)
+		return
+	}
+	o.onActivity(activity, jxClient, ns)
+}
+
+// onActivity triggers the downstream pipelines of a succeeded activity which have not been triggered yet
+func (o *ControllerChainOptions) onActivity(activity *v1.PipelineActivity, jxClient versioned.Interface, ns string) {
+	annotations := activity.Annotations
+	if annotations == nil || annotations[kube.AnnotationTriggers] == "" || annotations[kube.AnnotationTriggered] == "true" {
+		return
+	}
+	if activity.Spec.Status != v1.ActivityStatusTypeSucceeded {
+		return
+	}
+	params := url.Values{}
+	params.Set("UPSTREAM_PIPELINE", activity.Spec.Pipeline)
+	params.Set("UPSTREAM_BUILD", activity.Spec.Build)
+	if activity.Spec.Version != "" {
+		params.Set("UPSTREAM_VERSION", activity.Spec.Version)
+	}
+	for _, pipeline := range strings.Split(annotations[kube.AnnotationTriggers], ",") {
+		pipeline = strings.TrimSpace(pipeline)
+		if pipeline == "" {
+			continue
+		}
+		err := o.triggerPipeline(pipeline, params)
+		if err != nil {
+			log.Warnf("Failed to trigger pipeline %s from %s: %s\n", pipeline, activity.Name, err)
+			continue
+		}
+		log.Infof("Triggered pipeline %s from %s\n", util.ColorInfo(pipeline), util.ColorInfo(activity.Name))
+	}
+
+	copy := activity.DeepCopy()
+	copy.Annotations[kube.AnnotationTriggered] = "true"
+	_, err := jxClient.JenkinsV1().PipelineActivities(ns).Update(copy)
+	if err != nil {
+		log.Warnf("Failed to update PipelineActivity %s: %s\n", activity.Name, err)
+	}
+}
+
+func (o *ControllerChainOptions) triggerPipeline(pipeline string, params url.Values) error {
+	if o.FakeTrigger != nil {
+		return o.FakeTrigger(pipeline, params)
+	}
+	jenkins, err := o.JenkinsClient()
+	if err != nil {
+		return err
+	}
+	job, err := jenkins.GetJobByPath(strings.Split(pipeline, "/")...)
+	if err != nil {
+		return err
+	}
+	return jenkins.Build(job, params)
+}
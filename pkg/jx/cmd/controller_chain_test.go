@@ -0,0 +1,68 @@
+package cmd_test
+
+import (
+	"net/url"
+	"testing"
+
+	"github.com/jenkins-x/jx/pkg/apis/jenkins.io/v1"
+	"github.com/jenkins-x/jx/pkg/gits"
+	"github.com/jenkins-x/jx/pkg/helm"
+	"github.com/jenkins-x/jx/pkg/jx/cmd"
+	"github.com/jenkins-x/jx/pkg/kube"
+	"github.com/stretchr/testify/assert"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+)
+
+func TestControllerChain(t *testing.T) {
+	t.Parallel()
+	newActivity := func(name string, status v1.ActivityStatusType) *v1.PipelineActivity {
+		return &v1.PipelineActivity{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      name,
+				Namespace: "jx",
+				Annotations: map[string]string{
+					kube.AnnotationTriggers: "myorg/integration-tests/master",
+				},
+			},
+			Spec: v1.PipelineActivitySpec{
+				Pipeline: "myorg/myapp/master",
+				Build:    "3",
+				Version:  "1.0.3",
+				Status:   status,
+			},
+		}
+	}
+	succeeded := newActivity("myorg-myapp-master-3", v1.ActivityStatusTypeSucceeded)
+	running := newActivity("myorg-myapp-master-4", v1.ActivityStatusTypeRunning)
+
+	triggered := map[string]url.Values{}
+	o := &cmd.ControllerChainOptions{
+		NoWatch: true,
+		FakeTrigger: func(pipeline string, params url.Values) error {
+			triggered[pipeline] = params
+			return nil
+		},
+	}
+	cmd.ConfigureTestOptionsWithResources(&o.CommonOptions, nil, []runtime.Object{succeeded, running}, gits.NewGitCLI(), helm.NewHelmCLI("helm", helm.V2, "", true))
+
+	err := o.Run()
+	assert.NoError(t, err)
+	if assert.Equal(t, 1, len(triggered)) {
+		params := triggered["myorg/integration-tests/master"]
+		assert.Equal(t, "1.0.3", params.Get("UPSTREAM_VERSION"))
+		assert.Equal(t, "myorg/myapp/master", params.Get("UPSTREAM_PIPELINE"))
+	}
+
+	jxClient, ns, err := o.JXClientAndDevNamespace()
+	assert.NoError(t, err)
+	activity, err := jxClient.JenkinsV1().PipelineActivities(ns).Get(succeeded.Name, metav1.GetOptions{})
+	assert.NoError(t, err)
+	assert.Equal(t, "true", activity.Annotations[kube.AnnotationTriggered])
+
+	// the downstream pipelines should only be triggered once
+	triggered = map[string]url.Values{}
+	err = o.Run()
+	assert.NoError(t, err)
+	assert.Equal(t, 0, len(triggered))
+}
@@ -0,0 +1,231 @@
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	"github.com/jenkins-x/jx/pkg/apis/jenkins.io/v1"
+	"github.com/jenkins-x/jx/pkg/client/clientset/versioned"
+	"github.com/jenkins-x/jx/pkg/gits"
+	"github.com/jenkins-x/jx/pkg/issues"
+	"github.com/jenkins-x/jx/pkg/jx/cmd/templates"
+	"github.com/jenkins-x/jx/pkg/kube"
+	"github.com/jenkins-x/jx/pkg/log"
+	"github.com/jenkins-x/jx/pkg/util"
+	"github.com/spf13/cobra"
+	"gopkg.in/AlecAivazis/survey.v1/terminal"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+var (
+	controllerIssuesLong = templates.LongDesc(`
+		Runs the issues controller which comments the links to the completed pipeline runs on the issues they were run for.
+
+		The builds generated by 'jx step create build' are annotated with the keys of the issues referenced by the message of their head commit or the name of their branch, which 'jx controller build' records on the PipelineActivity of each run.
+		Once a run completes a comment with its status and the links to the run, its logs, the commit, the pull request and the release notes is added to each of its issues on the issue tracker of the project or otherwise the git provider.
+`)
+
+	controllerIssuesExample = templates.Examples(`
+		# Runs the issues controller
+		jx controller issues
+
+		# Comments on the issues of the completed runs then exits
+		jx controller issues --no-watch
+	`)
+)
+
+// ControllerIssuesOptions are the flags for the commands
+type ControllerIssuesOptions struct {
+	ControllerOptions
+
+	Namespace    string
+	NoWatch      bool
+	PollDuration time.Duration
+
+	trackers map[string]issues.IssueProvider
+}
+
+// NewCmdControllerIssues creates the command
+func NewCmdControllerIssues(f Factory, in terminal.FileReader, out terminal.FileWriter, errOut io.Writer) *cobra.Command {
+	options := &ControllerIssuesOptions{
+		ControllerOptions: ControllerOptions{
+			CommonOptions: CommonOptions{
+				Factory: f,
+				In:      in,
+				Out:     out,
+				Err:     errOut,
+			},
+		},
+	}
+
+	cmd := &cobra.Command{
+		Use:     "issues",
+		Short:   "Runs the issues controller which comments the links to the completed pipeline runs on their issues",
+		Long:    controllerIssuesLong,
+		Example: controllerIssuesExample,
+		Run: func(cmd *cobra.Command, args []string) {
+			options.Cmd = cmd
+			options.Args = args
+			err := options.Run()
+			CheckErr(err)
+		},
+	}
+
+	options.addCommonFlags(cmd)
+	cmd.Flags().StringVarP(&options.Namespace, "namespace", "n", "", "The namespace to watch or defaults to the current namespace")
+	cmd.Flags().BoolVarP(&options.NoWatch, "no-watch", "", false, "Disable watch so just comments on the issues of the runs which have completed")
+	cmd.Flags().DurationVarP(&options.PollDuration, "poll-duration", "", time.Second*30, "How often to check the pipeline runs")
+	return cmd
+}
+
+// Run implements this command
+func (o *ControllerIssuesOptions) Run() error {
+	// the controller runs unattended so it cannot prompt for the credentials of the issue trackers
+	o.BatchMode = true
+	jxClient, devNs, err := o.JXClientAndDevNamespace()
+	if err != nil {
+		return err
+	}
+	ns := o.Namespace
+	if ns == "" {
+		ns = devNs
+	}
+	o.trackers = map[string]issues.IssueProvider{}
+	if o.NoWatch {
+		return o.commentOnIssues(jxClient, ns)
+	}
+
+	log.Infof("Commenting on the issues of the pipeline runs in namespace %s every %s\n", util.ColorInfo(ns), util.ColorInfo(o.PollDuration.String()))
+	for {
+		err = o.commentOnIssues(jxClient, ns)
+		if err != nil {
+			log.Warnf("Failed to comment on the issues of the pipeline runs: %s\n", err)
+		}
+		time.Sleep(o.PollDuration)
+	}
+}
+
+// commentOnIssues comments on the issues of the completed activities which have not been commented on yet
+func (o *ControllerIssuesOptions) commentOnIssues(jxClient versioned.Interface, ns string) error {
+	activities, err := jxClient.JenkinsV1().PipelineActivities(ns).List(metav1.ListOptions{})
+	if err != nil {
+		return err
+	}
+	for i := range activities.Items {
+		activity := &activities.Items[i]
+		keys := activity.Annotations[kube.AnnotationIssues]
+		if keys == "" || activity.Annotations[kube.AnnotationIssuesCommented] != "" || !isActivityCompleted(activity) {
+			continue
+		}
+		tracker, err := o.issueTracker(activity)
+		if err != nil {
+			log.Warnf("Failed to create the issue tracker of PipelineActivity %s: %s\n", activity.Name, err)
+			continue
+		}
+		comment := runIssueComment(activity)
+		for _, key := range strings.Split(keys, ",") {
+			err = tracker.CreateIssueComment(key, comment)
+			if err != nil {
+				log.Warnf("Failed to comment on issue %s of PipelineActivity %s: %s\n", key, activity.Name, err)
+				continue
+			}
+			log.Infof("Commented build %s of %s on issue %s\n", util.ColorInfo(activity.Spec.Build), util.ColorInfo(activity.Spec.Pipeline), util.ColorInfo(tracker.IssueURL(key)))
+		}
+		// the issues are only commented on once even if some of the comments failed so that the issues are not spammed
+		activity.Annotations[kube.AnnotationIssuesCommented] = "true"
+		_, err = jxClient.JenkinsV1().PipelineActivities(ns).Update(activity)
+		if err != nil {
+			log.Warnf("Failed to annotate PipelineActivity %s: %s\n", activity.Name, err)
+		}
+	}
+	return nil
+}
+
+// issueTracker returns the issue tracker of the issues of the activity which is the git provider of the repository of
+// the activity unless the build was annotated with a different issue tracker
+func (o *ControllerIssuesOptions) issueTracker(activity *v1.PipelineActivity) (issues.IssueProvider, error) {
+	kind := activity.Annotations[kube.AnnotationIssueTrackerKind]
+	trackerURL := activity.Annotations[kube.AnnotationIssueTrackerURL]
+	key := kind + "=" + trackerURL
+	if kind == "" {
+		key = activity.Spec.GitURL
+	}
+	if tracker, ok := o.trackers[key]; ok {
+		return tracker, nil
+	}
+	var tracker issues.IssueProvider
+	if kind != "" {
+		authConfigSvc, err := o.CreateIssueTrackerAuthConfigService()
+		if err != nil {
+			return nil, err
+		}
+		config := authConfigSvc.Config()
+		server := config.GetOrCreateServer(trackerURL)
+		userAuth, err := config.PickServerUserAuth(server, "user to access the issue tracker", o.BatchMode, "", o.In, o.Out, o.Err)
+		if err != nil {
+			return nil, err
+		}
+		tracker, err = issues.CreateIssueProvider(kind, server, userAuth, "", o.BatchMode, o.Git())
+		if err != nil {
+			return nil, err
+		}
+	} else {
+		if activity.Spec.GitURL == "" {
+			return nil, fmt.Errorf("no git URL is recorded on the activity")
+		}
+		gitInfo, err := gits.ParseGitURL(activity.Spec.GitURL)
+		if err != nil {
+			return nil, err
+		}
+		gitProvider, err := o.gitProviderForURL(activity.Spec.GitURL, "user name to comment on the issues")
+		if err != nil {
+			return nil, err
+		}
+		tracker, err = issues.CreateGitIssueProvider(gitProvider, gitInfo.Organisation, gitInfo.Name)
+		if err != nil {
+			return nil, err
+		}
+	}
+	o.trackers[key] = tracker
+	return tracker, nil
+}
+
+// runIssueComment returns the markdown comment linking an issue to the completed run of the activity
+func runIssueComment(activity *v1.PipelineActivity) string {
+	spec := &activity.Spec
+	status := ":white_check_mark: succeeded"
+	if spec.Status == v1.ActivityStatusTypeFailed {
+		status = ":x: failed"
+	}
+	build := "build #" + spec.Build
+	if spec.BuildURL != "" {
+		build = "[" + build + "](" + spec.BuildURL + ")"
+	}
+	comment := fmt.Sprintf("%s of pipeline **%s** %s", build, spec.Pipeline, status)
+	if spec.LastCommitSHA != "" {
+		commit := spec.LastCommitSHA
+		if len(commit) > 7 {
+			commit = commit[0:7]
+		}
+		if spec.LastCommitURL != "" {
+			commit = "[" + commit + "](" + spec.LastCommitURL + ")"
+		}
+		comment += " for commit " + commit
+	}
+	if url := activity.Annotations[kube.AnnotationPullRequestURL]; url != "" {
+		comment += " of [pull request](" + url + ")"
+	}
+	links := []string{}
+	if spec.BuildLogsURL != "" {
+		links = append(links, "[logs]("+spec.BuildLogsURL+")")
+	}
+	if spec.ReleaseNotesURL != "" {
+		links = append(links, "[release notes]("+spec.ReleaseNotesURL+")")
+	}
+	if len(links) > 0 {
+		comment += "\n\n" + strings.Join(links, " | ")
+	}
+	return comment
+}
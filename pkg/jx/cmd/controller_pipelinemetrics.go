@@ -0,0 +1,96 @@
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+
+	"github.com/jenkins-x/jx/pkg/jx/cmd/templates"
+	"github.com/jenkins-x/jx/pkg/log"
+	"github.com/jenkins-x/jx/pkg/metrics"
+	"github.com/jenkins-x/jx/pkg/util"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/spf13/cobra"
+	"gopkg.in/AlecAivazis/survey.v1/terminal"
+)
+
+var (
+	controllerPipelineMetricsLong = templates.LongDesc(`
+		Runs the pipeline metrics controller which exposes Prometheus metrics about the PipelineActivity resources such as
+		the duration of pipeline runs and stages, the time runs are queued for and the success rate of each repository.
+`)
+
+	controllerPipelineMetricsExample = templates.Examples(`
+		# Exposes the pipeline metrics on port 8080 at /metrics
+		jx controller pipelinemetrics
+
+		# Exposes the pipeline metrics on a different port
+		jx controller pipelinemetrics --port 9090
+	`)
+)
+
+// ControllerPipelineMetricsOptions are the flags for the commands
+type ControllerPipelineMetricsOptions struct {
+	ControllerOptions
+
+	Namespace string
+	Port      int
+	Path      string
+}
+
+// NewCmdControllerPipelineMetrics creates the command
+func NewCmdControllerPipelineMetrics(f Factory, in terminal.FileReader, out terminal.FileWriter, errOut io.Writer) *cobra.Command {
+	options := &ControllerPipelineMetricsOptions{
+		ControllerOptions: ControllerOptions{
+			CommonOptions: CommonOptions{
+				Factory: f,
+				In:      in,
+				Out:     out,
+				Err:     errOut,
+			},
+		},
+	}
+
+	cmd := &cobra.Command{
+		Use:     "pipelinemetrics",
+		Short:   "Runs the pipeline metrics controller which exposes Prometheus metrics about pipeline runs",
+		Long:    controllerPipelineMetricsLong,
+		Example: controllerPipelineMetricsExample,
+		Run: func(cmd *cobra.Command, args []string) {
+			options.Cmd = cmd
+			options.Args = args
+			err := options.Run()
+			CheckErr(err)
+		},
+	}
+
+	options.addCommonFlags(cmd)
+	cmd.Flags().StringVarP(&options.Namespace, "namespace", "n", "", "The namespace of the PipelineActivity resources or defaults to the current namespace")
+	cmd.Flags().IntVarP(&options.Port, "port", "", 8080, "The port the metrics are exposed on")
+	cmd.Flags().StringVarP(&options.Path, "path", "", "/metrics", "The HTTP path the metrics are exposed on")
+	return cmd
+}
+
+// Run implements this command
+func (o *ControllerPipelineMetricsOptions) Run() error {
+	err := o.registerPipelineActivityCRD()
+	if err != nil {
+		return err
+	}
+	jxClient, devNs, err := o.JXClientAndDevNamespace()
+	if err != nil {
+		return err
+	}
+	ns := o.Namespace
+	if ns == "" {
+		ns = devNs
+	}
+	err = prometheus.Register(metrics.NewPipelineCollector(jxClient, ns))
+	if err != nil {
+		return err
+	}
+	http.Handle(o.Path, prometheus.Handler())
+
+	log.Infof("Exposing the metrics of pipelines in namespace %s at %s on port %s\n", util.ColorInfo(ns), util.ColorInfo(o.Path), util.ColorInfo(o.Port))
+	return http.ListenAndServe(fmt.Sprintf(":%d", o.Port), nil)
+}
@@ -0,0 +1,190 @@
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/jenkins-x/jx/pkg/builds"
+	"github.com/jenkins-x/jx/pkg/jx/cmd/templates"
+	"github.com/jenkins-x/jx/pkg/kube"
+	"github.com/jenkins-x/jx/pkg/log"
+	"github.com/jenkins-x/jx/pkg/util"
+	"github.com/spf13/cobra"
+	"gopkg.in/AlecAivazis/survey.v1/terminal"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+)
+
+const (
+	preemptionControllerComponent = "jx-preemption-controller"
+
+	// ReasonPreemptionRetried the reason of the event emitted when a preempted build pod is retried
+	ReasonPreemptionRetried = "PreemptionRetried"
+	// ReasonPreemptionRetriesExceeded the reason of the event emitted when a preempted build pod has no retries left
+	ReasonPreemptionRetriesExceeded = "PreemptionRetriesExceeded"
+)
+
+var (
+	controllerPreemptionLong = templates.LongDesc(`
+		Runs the preemption controller which retries the build pods whose spot or preemptible node was preempted.
+
+		The builds are scheduled on spot nodes by 'jx step create build' when the spot section of the team build defaults is enabled.
+		A preempted build pod is retried from the step it was preempted in: the steps which completed before it are skipped while the steps preparing the workspace run again as the workspace was lost along with the node.
+		A Warning event is emitted for each preempted build pod so that preemptions can be reported on.
+`)
+
+	controllerPreemptionExample = templates.Examples(`
+		# Runs the preemption controller
+		jx controller preemption
+
+		# Retries any build pods which are currently preempted then exits
+		jx controller preemption --no-watch
+	`)
+)
+
+// ControllerPreemptionOptions are the flags for the commands
+type ControllerPreemptionOptions struct {
+	ControllerOptions
+
+	Namespace    string
+	NoWatch      bool
+	PollDuration time.Duration
+}
+
+// NewCmdControllerPreemption creates the command
+func NewCmdControllerPreemption(f Factory, in terminal.FileReader, out terminal.FileWriter, errOut io.Writer) *cobra.Command {
+	options := &ControllerPreemptionOptions{
+		ControllerOptions: ControllerOptions{
+			CommonOptions: CommonOptions{
+				Factory: f,
+				In:      in,
+				Out:     out,
+				Err:     errOut,
+			},
+		},
+	}
+
+	cmd := &cobra.Command{
+		Use:     "preemption",
+		Short:   "Runs the preemption controller which retries the build pods whose spot node was preempted",
+		Long:    controllerPreemptionLong,
+		Example: controllerPreemptionExample,
+		Run: func(cmd *cobra.Command, args []string) {
+			options.Cmd = cmd
+			options.Args = args
+			err := options.Run()
+			CheckErr(err)
+		},
+	}
+
+	options.addCommonFlags(cmd)
+	cmd.Flags().StringVarP(&options.Namespace, "namespace", "n", "", "The namespace to watch or defaults to the current namespace")
+	cmd.Flags().BoolVarP(&options.NoWatch, "no-watch", "", false, "Disable watch so just retries any build pods which are currently preempted")
+	cmd.Flags().DurationVarP(&options.PollDuration, "poll-duration", "", time.Second*30, "How often to check the build pods")
+	return cmd
+}
+
+// Run implements this command
+func (o *ControllerPreemptionOptions) Run() error {
+	kubeClient, devNs, err := o.KubeClientAndDevNamespace()
+	if err != nil {
+		return err
+	}
+	ns := o.Namespace
+	if ns == "" {
+		ns = devNs
+	}
+	if o.NoWatch {
+		return o.retryPreemptedPods(kubeClient, ns)
+	}
+
+	log.Infof("Checking for preempted build pods in namespace %s every %s\n", util.ColorInfo(ns), util.ColorInfo(o.PollDuration.String()))
+	for {
+		err = o.retryPreemptedPods(kubeClient, ns)
+		if err != nil {
+			log.Warnf("Failed to retry the preempted build pods: %s\n", err)
+		}
+		time.Sleep(o.PollDuration)
+	}
+}
+
+// retryPreemptedPods retries the build pods which were preempted and have not been retried yet until they run out of
+// retries
+func (o *ControllerPreemptionOptions) retryPreemptedPods(kubeClient kubernetes.Interface, ns string) error {
+	pods, err := builds.GetBuildPods(kubeClient, ns)
+	if err != nil {
+		return err
+	}
+	for _, pod := range pods {
+		if pod.DeletionTimestamp != nil || !builds.IsPreempted(pod) || pod.Annotations[kube.AnnotationRetriedBy] != "" {
+			continue
+		}
+		maxRetries := builds.MaxPreemptionRetries(pod)
+		if maxRetries == 0 {
+			continue
+		}
+		if builds.PreemptionRetries(pod) >= maxRetries {
+			o.markRetried(kubeClient, ns, pod, "-")
+			o.emitPreemptionEvent(kubeClient, ns, pod, ReasonPreemptionRetriesExceeded,
+				fmt.Sprintf("Build pod %s was preempted and has used all of its %d retries", pod.Name, maxRetries))
+			continue
+		}
+		retry := builds.RetryPod(pod, workspaceStepNames)
+		_, err = kubeClient.CoreV1().Pods(ns).Create(retry)
+		if err != nil {
+			log.Warnf("Failed to retry preempted build pod %s: %s\n", pod.Name, err)
+			continue
+		}
+		o.markRetried(kubeClient, ns, pod, retry.Name)
+		o.emitPreemptionEvent(kubeClient, ns, pod, ReasonPreemptionRetried,
+			fmt.Sprintf("Build pod %s was preempted and is retried from step %s by pod %s", pod.Name, builds.CheckpointStep(pod), retry.Name))
+	}
+	return nil
+}
+
+// markRetried records the pod retrying the preempted pod so that it is only retried once
+func (o *ControllerPreemptionOptions) markRetried(kubeClient kubernetes.Interface, ns string, pod *corev1.Pod, retriedBy string) {
+	if pod.Annotations == nil {
+		pod.Annotations = map[string]string{}
+	}
+	pod.Annotations[kube.AnnotationRetriedBy] = retriedBy
+	_, err := kubeClient.CoreV1().Pods(ns).Update(pod)
+	if err != nil {
+		log.Warnf("Failed to annotate preempted build pod %s: %s\n", pod.Name, err)
+	}
+}
+
+// emitPreemptionEvent emits a Warning event recording what happened to the preempted build pod
+func (o *ControllerPreemptionOptions) emitPreemptionEvent(kubeClient kubernetes.Interface, ns string, pod *corev1.Pod, reason string, message string) {
+	log.Infof("%s\n", message)
+
+	now := metav1.Now()
+	event := &corev1.Event{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      fmt.Sprintf("%s.%x", pod.Name, now.UnixNano()),
+			Namespace: ns,
+		},
+		InvolvedObject: corev1.ObjectReference{
+			APIVersion: "v1",
+			Kind:       "Pod",
+			Name:       pod.Name,
+			Namespace:  ns,
+			UID:        pod.UID,
+		},
+		Reason:         reason,
+		Message:        message,
+		Type:           corev1.EventTypeWarning,
+		Count:          1,
+		FirstTimestamp: now,
+		LastTimestamp:  now,
+		Source: corev1.EventSource{
+			Component: preemptionControllerComponent,
+		},
+	}
+	_, err := kubeClient.CoreV1().Events(ns).Create(event)
+	if err != nil {
+		log.Warnf("Failed to create event for preempted build pod %s: %s\n", pod.Name, err)
+	}
+}
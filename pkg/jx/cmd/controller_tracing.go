@@ -0,0 +1,207 @@
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	"github.com/jenkins-x/jx/pkg/builds"
+	"github.com/jenkins-x/jx/pkg/jx/cmd/templates"
+	"github.com/jenkins-x/jx/pkg/kube"
+	"github.com/jenkins-x/jx/pkg/log"
+	"github.com/jenkins-x/jx/pkg/tracing"
+	"github.com/jenkins-x/jx/pkg/util"
+	"github.com/spf13/cobra"
+	"gopkg.in/AlecAivazis/survey.v1/terminal"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/client-go/kubernetes"
+)
+
+var (
+	controllerTracingLong = templates.LongDesc(`
+		Runs the tracing controller which exports the stages of the completed pipelines as OpenTelemetry spans so that whole pipelines appear as distributed traces in Jaeger or Cloud Trace.
+
+		The builds are traced by 'jx step create build' when the tracing section of the team build defaults is enabled.
+		Each completed build pod is exported as a span of the pipeline with a child span for each of its steps to the OTLP/HTTP endpoint of the team. The spans which the tools run by the steps report with the trace context of their step are children of the span of the step.
+`)
+
+	controllerTracingExample = templates.Examples(`
+		# Runs the tracing controller
+		jx controller tracing
+
+		# Exports the traces of the completed build pods to a Jaeger collector then exits
+		jx controller tracing --endpoint http://jaeger-collector:4318 --no-watch
+	`)
+)
+
+// ControllerTracingOptions are the flags for the commands
+type ControllerTracingOptions struct {
+	ControllerOptions
+
+	Namespace    string
+	Endpoint     string
+	NoWatch      bool
+	PollDuration time.Duration
+}
+
+// NewCmdControllerTracing creates the command
+func NewCmdControllerTracing(f Factory, in terminal.FileReader, out terminal.FileWriter, errOut io.Writer) *cobra.Command {
+	options := &ControllerTracingOptions{
+		ControllerOptions: ControllerOptions{
+			CommonOptions: CommonOptions{
+				Factory: f,
+				In:      in,
+				Out:     out,
+				Err:     errOut,
+			},
+		},
+	}
+
+	cmd := &cobra.Command{
+		Use:     "tracing",
+		Short:   "Runs the tracing controller which exports the stages of the completed pipelines as OpenTelemetry spans",
+		Long:    controllerTracingLong,
+		Example: controllerTracingExample,
+		Run: func(cmd *cobra.Command, args []string) {
+			options.Cmd = cmd
+			options.Args = args
+			err := options.Run()
+			CheckErr(err)
+		},
+	}
+
+	options.addCommonFlags(cmd)
+	cmd.Flags().StringVarP(&options.Namespace, "namespace", "n", "", "The namespace to watch or defaults to the current namespace")
+	cmd.Flags().StringVarP(&options.Endpoint, "endpoint", "", "", "The OTLP/HTTP endpoint the spans are exported to. Defaults to the tracing endpoint of the build defaults of the team")
+	cmd.Flags().BoolVarP(&options.NoWatch, "no-watch", "", false, "Disable watch so just exports the traces of the build pods which have completed")
+	cmd.Flags().DurationVarP(&options.PollDuration, "poll-duration", "", time.Second*30, "How often to check the build pods")
+	return cmd
+}
+
+// Run implements this command
+func (o *ControllerTracingOptions) Run() error {
+	kubeClient, devNs, err := o.KubeClientAndDevNamespace()
+	if err != nil {
+		return err
+	}
+	ns := o.Namespace
+	if ns == "" {
+		ns = devNs
+	}
+	if o.Endpoint == "" {
+		teamSettings, err := o.TeamSettings()
+		if err != nil {
+			return err
+		}
+		o.Endpoint = teamSettings.BuildDefaults.Tracing.Endpoint
+	}
+	if o.Endpoint == "" {
+		return util.MissingOption("endpoint")
+	}
+	if o.NoWatch {
+		return o.exportTraces(kubeClient, ns)
+	}
+
+	log.Infof("Exporting the traces of the build pods in namespace %s to %s every %s\n", util.ColorInfo(ns), util.ColorInfo(o.Endpoint), util.ColorInfo(o.PollDuration.String()))
+	for {
+		err = o.exportTraces(kubeClient, ns)
+		if err != nil {
+			log.Warnf("Failed to export the traces of the build pods: %s\n", err)
+		}
+		time.Sleep(o.PollDuration)
+	}
+}
+
+// exportTraces exports the spans of the traced build pods which have completed and have not been exported yet
+func (o *ControllerTracingOptions) exportTraces(kubeClient kubernetes.Interface, ns string) error {
+	pods, err := builds.GetBuildPods(kubeClient, ns)
+	if err != nil {
+		return err
+	}
+	for _, pod := range pods {
+		traceID := pod.Annotations[kube.AnnotationTraceID]
+		if traceID == "" || pod.Annotations[kube.AnnotationTraceExported] != "" {
+			continue
+		}
+		if pod.Status.Phase != corev1.PodSucceeded && pod.Status.Phase != corev1.PodFailed {
+			continue
+		}
+		err = tracing.Export(o.Endpoint, buildPodServiceName(pod), buildPodSpans(pod, traceID))
+		if err != nil {
+			log.Warnf("Failed to export the trace of build pod %s: %s\n", pod.Name, err)
+			continue
+		}
+		pod.Annotations[kube.AnnotationTraceExported] = "true"
+		_, err = kubeClient.CoreV1().Pods(ns).Update(pod)
+		if err != nil {
+			log.Warnf("Failed to annotate build pod %s: %s\n", pod.Name, err)
+			continue
+		}
+		log.Infof("Exported trace %s of build pod %s\n", util.ColorInfo(traceID), util.ColorInfo(pod.Name))
+	}
+	return nil
+}
+
+// buildPodSpans returns the span of the pipeline run by the build pod and the spans of each of its steps
+func buildPodSpans(pod *corev1.Pod, traceID string) []tracing.Span {
+	rootSpanID := tracing.SpanID(traceID, "")
+	buildName := pod.Labels[builds.LabelBuildName]
+	if buildName == "" {
+		buildName = pod.Name
+	}
+	root := tracing.Span{
+		TraceID: traceID,
+		SpanID:  rootSpanID,
+		Name:    buildName,
+		Start:   pod.CreationTimestamp.Time,
+		End:     pod.CreationTimestamp.Time,
+		Attributes: map[string]string{
+			"pod": pod.Name,
+		},
+		Failed: pod.Status.Phase == corev1.PodFailed,
+	}
+	if pod.Status.StartTime != nil {
+		root.Start = pod.Status.StartTime.Time
+	}
+	spans := []tracing.Span{}
+	for _, status := range pod.Status.InitContainerStatuses {
+		terminated := status.State.Terminated
+		if terminated == nil || !strings.HasPrefix(status.Name, builds.StepContainerPrefix) {
+			continue
+		}
+		step := strings.TrimPrefix(status.Name, builds.StepContainerPrefix)
+		spans = append(spans, tracing.Span{
+			TraceID:      traceID,
+			SpanID:       tracing.SpanID(traceID, step),
+			ParentSpanID: rootSpanID,
+			Name:         step,
+			Start:        terminated.StartedAt.Time,
+			End:          terminated.FinishedAt.Time,
+			Attributes: map[string]string{
+				"image":    status.Image,
+				"exitCode": fmt.Sprintf("%d", terminated.ExitCode),
+			},
+			Failed: terminated.ExitCode != 0,
+		})
+		if terminated.FinishedAt.After(root.End) {
+			root.End = terminated.FinishedAt.Time
+		}
+	}
+	return append([]tracing.Span{root}, spans...)
+}
+
+// buildPodServiceName returns the service name the steps of the build pod report their spans as
+func buildPodServiceName(pod *corev1.Pod) string {
+	for _, c := range pod.Spec.InitContainers {
+		for _, e := range c.Env {
+			if e.Name == tracing.EnvServiceName && e.Value != "" {
+				return e.Value
+			}
+		}
+	}
+	if pod.Labels[builds.LabelBuildName] != "" {
+		return pod.Labels[builds.LabelBuildName]
+	}
+	return pod.Name
+}
@@ -0,0 +1,106 @@
+package cmd_test
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/jenkins-x/jx/pkg/builds"
+	"github.com/jenkins-x/jx/pkg/gits"
+	"github.com/jenkins-x/jx/pkg/helm"
+	"github.com/jenkins-x/jx/pkg/jx/cmd"
+	"github.com/jenkins-x/jx/pkg/kube"
+	"github.com/jenkins-x/jx/pkg/tracing"
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+)
+
+func TestControllerTracing(t *testing.T) {
+	t.Parallel()
+	traceID := "0af7651916cd43dd8448eb211c80319c"
+	spanNames := []string{}
+	parents := map[string]string{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		data, err := ioutil.ReadAll(r.Body)
+		assert.NoError(t, err)
+		body := map[string]interface{}{}
+		err = json.Unmarshal(data, &body)
+		assert.NoError(t, err)
+		resourceSpans := body["resourceSpans"].([]interface{})[0].(map[string]interface{})
+		spans := resourceSpans["scopeSpans"].([]interface{})[0].(map[string]interface{})["spans"].([]interface{})
+		for _, s := range spans {
+			span := s.(map[string]interface{})
+			name := span["name"].(string)
+			spanNames = append(spanNames, name)
+			if parent, ok := span["parentSpanId"]; ok {
+				parents[name] = parent.(string)
+			}
+		}
+	}))
+	defer server.Close()
+
+	start := metav1.NewTime(time.Now().Add(-time.Hour))
+	terminated := func(name string, exitCode int32) corev1.ContainerStatus {
+		return corev1.ContainerStatus{
+			Name: builds.StepContainerPrefix + name,
+			State: corev1.ContainerState{
+				Terminated: &corev1.ContainerStateTerminated{
+					ExitCode:   exitCode,
+					StartedAt:  start,
+					FinishedAt: metav1.NewTime(start.Add(time.Minute)),
+				},
+			},
+		}
+	}
+	buildPod := func(name string, phase corev1.PodPhase, annotations map[string]string) *corev1.Pod {
+		return &corev1.Pod{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:        name,
+				Namespace:   "jx",
+				Labels:      map[string]string{builds.LabelBuildName: name},
+				Annotations: annotations,
+			},
+			Status: corev1.PodStatus{
+				Phase:                 phase,
+				StartTime:             &start,
+				InitContainerStatuses: []corev1.ContainerStatus{terminated("build", 0), terminated("test", 1)},
+			},
+		}
+	}
+	k8sObjects := []runtime.Object{
+		buildPod("traced", corev1.PodFailed, map[string]string{kube.AnnotationTraceID: traceID}),
+		buildPod("running", corev1.PodRunning, map[string]string{kube.AnnotationTraceID: traceID}),
+		buildPod("untraced", corev1.PodSucceeded, map[string]string{}),
+	}
+
+	o := &cmd.ControllerTracingOptions{
+		NoWatch:  true,
+		Endpoint: server.URL,
+	}
+	cmd.ConfigureTestOptionsWithResources(&o.CommonOptions, k8sObjects, nil, gits.NewGitCLI(), helm.NewHelmCLI("helm", helm.V2, "", true))
+
+	err := o.Run()
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"traced", "build", "test"}, spanNames)
+	assert.Equal(t, map[string]string{
+		"build": tracing.SpanID(traceID, ""),
+		"test":  tracing.SpanID(traceID, ""),
+	}, parents)
+
+	kubeClient, ns, err := o.KubeClientAndDevNamespace()
+	assert.NoError(t, err)
+	pod, err := kubeClient.CoreV1().Pods(ns).Get("traced", metav1.GetOptions{})
+	assert.NoError(t, err)
+	assert.Equal(t, "true", pod.Annotations[kube.AnnotationTraceExported])
+
+	// the exported traces are not exported again
+	spanNames = []string{}
+	err = o.Run()
+	assert.NoError(t, err)
+	assert.Empty(t, spanNames)
+}
@@ -84,6 +84,7 @@ func NewCmdCreate(f Factory, in terminal.FileReader, out terminal.FileWriter, er
 	cmd.AddCommand(NewCmdCreateJHipster(f, in, out, errOut))
 	cmd.AddCommand(NewCmdCreateLile(f, in, out, errOut))
 	cmd.AddCommand(NewCmdCreateMicro(f, in, out, errOut))
+	cmd.AddCommand(NewCmdCreatePodTemplate(f, in, out, errOut))
 	cmd.AddCommand(NewCmdCreatePostPreviewJob(f, in, out, errOut))
 	cmd.AddCommand(NewCmdCreateQuickstart(f, in, out, errOut))
 	cmd.AddCommand(NewCmdCreateQuickstartLocation(f, in, out, errOut))
@@ -93,6 +94,7 @@ func NewCmdCreate(f Factory, in terminal.FileReader, out terminal.FileWriter, er
 	cmd.AddCommand(NewCmdCreateToken(f, in, out, errOut))
 	cmd.AddCommand(NewCmdCreateTracker(f, in, out, errOut))
 	cmd.AddCommand(NewCmdCreateUser(f, in, out, errOut))
+	cmd.AddCommand(NewCmdCreateWarmup(f, in, out, errOut))
 	return cmd
 }
 
@@ -6,6 +6,7 @@ import (
 	"io"
 
 	"github.com/jenkins-x/jx/pkg/jx/cmd/templates"
+	"github.com/jenkins-x/jx/pkg/kube"
 	"github.com/jenkins-x/jx/pkg/util"
 	"github.com/spf13/cobra"
 	"gopkg.in/AlecAivazis/survey.v1"
@@ -110,7 +111,7 @@ func (o *CreateDockerAuthOptions) Run() error {
 	if err != nil {
 		return err
 	}
-	secretFromConfig, err := kubeClient.CoreV1().Secrets(currentNs).Get("jenkins-docker-cfg", metav1.GetOptions{})
+	secretFromConfig, err := kubeClient.CoreV1().Secrets(currentNs).Get(kube.SecretJenkinsDockerConfig, metav1.GetOptions{})
 	if err != nil {
 		return nil
 	}
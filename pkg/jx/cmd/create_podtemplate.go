@@ -0,0 +1,107 @@
+package cmd
+
+import (
+	"io"
+	"io/ioutil"
+	"strings"
+
+	"github.com/ghodss/yaml"
+	"github.com/jenkins-x/jx/pkg/jx/cmd/templates"
+	"github.com/jenkins-x/jx/pkg/kube"
+	"github.com/jenkins-x/jx/pkg/log"
+	"github.com/jenkins-x/jx/pkg/util"
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+	"gopkg.in/AlecAivazis/survey.v1/terminal"
+	corev1 "k8s.io/api/core/v1"
+)
+
+var (
+	createPodTemplateLong = templates.LongDesc(`
+		Creates or replaces a pod template for the current team from a YAML file containing a Pod
+`)
+
+	createPodTemplateExample = templates.Examples(`
+		# Import a pod template from a file
+		jx create podtemplate -f maven.yaml
+
+		# Import a pod template using a specific name
+		jx create podtemplate -f pod.yaml --name maven-java11
+	`)
+)
+
+// CreatePodTemplateOptions the options for the create podtemplate command
+type CreatePodTemplateOptions struct {
+	CreateOptions
+
+	Name     string
+	FileName string
+}
+
+// NewCmdCreatePodTemplate creates a command object for the "create podtemplate" command
+func NewCmdCreatePodTemplate(f Factory, in terminal.FileReader, out terminal.FileWriter, errOut io.Writer) *cobra.Command {
+	options := &CreatePodTemplateOptions{
+		CreateOptions: CreateOptions{
+			CommonOptions: CommonOptions{
+				Factory: f,
+				In:      in,
+				Out:     out,
+				Err:     errOut,
+			},
+		},
+	}
+
+	cmd := &cobra.Command{
+		Use:     podTemplate,
+		Short:   "Creates or replaces a pod template for the current team from a YAML file",
+		Aliases: podTemplateAliases,
+		Long:    createPodTemplateLong,
+		Example: createPodTemplateExample,
+		Run: func(cmd *cobra.Command, args []string) {
+			options.Cmd = cmd
+			options.Args = args
+			err := options.Run()
+			CheckErr(err)
+		},
+	}
+	options.addCommonFlags(cmd)
+
+	cmd.Flags().StringVarP(&options.Name, optionName, "n", "", "The name of the pod template. Defaults to the name of the Pod without any 'jenkins-' prefix")
+	cmd.Flags().StringVarP(&options.FileName, "file", "f", "", "The YAML file containing the Pod to import")
+	return cmd
+}
+
+// Run implements the command
+func (o *CreatePodTemplateOptions) Run() error {
+	if o.FileName == "" {
+		return util.MissingOption("file")
+	}
+	data, err := ioutil.ReadFile(o.FileName)
+	if err != nil {
+		return errors.Wrapf(err, "failed to load file %s", o.FileName)
+	}
+	pod := &corev1.Pod{}
+	err = yaml.Unmarshal(data, pod)
+	if err != nil {
+		return errors.Wrapf(err, "failed to parse the Pod YAML in file %s", o.FileName)
+	}
+	name := o.Name
+	if name == "" {
+		name = strings.TrimPrefix(pod.Name, "jenkins-")
+	}
+	if name == "" {
+		return util.MissingOption(optionName)
+	}
+
+	kubeClient, ns, err := o.KubeClientAndDevNamespace()
+	if err != nil {
+		return err
+	}
+	err = kube.SavePodTemplate(kubeClient, ns, name, pod)
+	if err != nil {
+		return err
+	}
+	invalidatePodTemplatesCache(ns)
+	log.Infof("Saved pod template %s\n", util.ColorInfo(name))
+	return nil
+}
@@ -18,6 +18,7 @@ import (
 	"gopkg.in/AlecAivazis/survey.v1/terminal"
 
 	"github.com/jenkins-x/jx/pkg/auth"
+	"github.com/jenkins-x/jx/pkg/config"
 	"github.com/jenkins-x/jx/pkg/jx/cmd/templates"
 	"github.com/jenkins-x/jx/pkg/util"
 )
@@ -47,6 +48,9 @@ var (
 		jx create quickstart
 
 		jx create quickstart -f http
+
+		# Create a quickstart which includes its committed pre-generated pipelines in the .jx/pipelines folder
+		jx create quickstart --tekton --pipelines
 	`)
 )
 
@@ -59,6 +63,7 @@ type CreateQuickstartOptions struct {
 	GitProvider         gits.GitProvider
 	GitHost             string
 	IgnoreTeam          bool
+	Pipelines           bool
 }
 
 // NewCmdCreateQuickstart creates a command object for the "create" command
@@ -99,6 +104,7 @@ func NewCmdCreateQuickstart(f Factory, in terminal.FileReader, out terminal.File
 	cmd.Flags().StringVarP(&options.GitHost, "git-host", "", "", "The Git server host if not using GitHub when pushing created project")
 	cmd.Flags().StringVarP(&options.Filter.Text, "filter", "f", "", "The text filter")
 	cmd.Flags().StringVarP(&options.Filter.ProjectName, "project-name", "p", "", "The project name (for use with -b batch mode)")
+	cmd.Flags().BoolVarP(&options.Pipelines, "pipelines", "", false, fmt.Sprintf("Commits the generated pipelines into the %s folder of the project so they are applied rather than generated on demand", config.DefaultPipelinesDir))
 	return cmd
 }
 
@@ -216,6 +222,18 @@ func (o *CreateQuickstartOptions) Run() error {
 			log.Infof("### NO charts folder %s\n", chartsDir)
 		}
 	}
+	if o.Pipelines {
+		callback := o.PostDraftPackCallback
+		o.PostDraftPackCallback = func() error {
+			if callback != nil {
+				err := callback()
+				if err != nil {
+					return err
+				}
+			}
+			return o.GeneratePipelines()
+		}
+	}
 	log.Infof("Created project at %s\n\n", util.ColorInfo(genDir))
 
 	o.CreateProjectOptions.ImportOptions.GitProvider = o.GitProvider
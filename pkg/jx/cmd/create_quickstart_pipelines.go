@@ -0,0 +1,44 @@
+package cmd
+
+import (
+	"path/filepath"
+
+	"github.com/jenkins-x/jx/pkg/config"
+	"github.com/jenkins-x/jx/pkg/log"
+	"github.com/jenkins-x/jx/pkg/util"
+	"github.com/pkg/errors"
+)
+
+// GeneratePipelines generates the builds of the project into its pipelines directory so they can be committed
+// along with the source code. The pipelinesDir of the jenkins-x.yml file is set so that 'jx step apply build'
+// applies the committed builds rather than generating them
+func (o *ImportOptions) GeneratePipelines() error {
+	projectConfig, err := o.CreateTektonProjectConfig()
+	if err != nil {
+		return err
+	}
+	if projectConfig.PipelinesDir == "" {
+		projectConfig.PipelinesDir = config.DefaultPipelinesDir
+		err = projectConfig.SaveConfig(filepath.Join(o.Dir, config.ProjectConfigFileName))
+		if err != nil {
+			return err
+		}
+	}
+	outDir := filepath.Join(o.Dir, projectConfig.PipelinesDir)
+	so := &StepCreateBuildOptions{
+		StepOptions: StepOptions{
+			CommonOptions: o.CommonOptions,
+		},
+		Dir:       o.Dir,
+		OutputDir: outDir,
+		// committed builds should not be bound to a build number
+		BuildNumber: 0,
+		NoAudit:     true,
+	}
+	err = so.Run()
+	if err != nil {
+		return errors.Wrap(err, "failed to generate the pipelines")
+	}
+	log.Infof("Generated the pipelines into %s\n", util.ColorInfo(outDir))
+	return nil
+}
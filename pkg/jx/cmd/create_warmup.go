@@ -0,0 +1,305 @@
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/ghodss/yaml"
+	"github.com/jenkins-x/jx/pkg/jx/cmd/templates"
+	"github.com/jenkins-x/jx/pkg/kube"
+	"github.com/jenkins-x/jx/pkg/log"
+	"github.com/jenkins-x/jx/pkg/util"
+	"github.com/spf13/cobra"
+	"gopkg.in/AlecAivazis/survey.v1/terminal"
+	appsv1 "k8s.io/api/apps/v1"
+	batchv1 "k8s.io/api/batch/v1"
+	batchv1beta1 "k8s.io/api/batch/v1beta1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+)
+
+const (
+	warmupName       = "jx-builder-warmup"
+	warmupPauseImage = "k8s.gcr.io/pause:3.1"
+)
+
+var (
+	createWarmupLong = templates.LongDesc(`
+		Creates the resources which warm up the builder images and dependency caches used by pipelines to reduce the time it takes builds to start.
+
+		A DaemonSet pulls the builder images of the pod templates onto every node, including any new nodes added by the cluster autoscaler.
+
+		If a cache command is specified a CronJob periodically runs it against a shared PersistentVolumeClaim to keep the dependency cache up to date.
+`)
+
+	createWarmupExample = templates.Examples(`
+		# Pull the builder images of all the pod templates onto every node
+		jx create warmup
+
+		# Pull specific builder images onto every node
+		jx create warmup --image jenkinsxio/builder-maven:0.0.500 --image jenkinsxio/builder-go:0.0.500
+
+		# Also warm up a shared maven repository every 6 hours
+		jx create warmup --pvc maven-cache --cache-path /root/.m2 --cache-image jenkinsxio/builder-maven:0.0.500 --cache-command "mvn dependency:go-offline -f /cache/pom.xml"
+
+		# Generate the YAML without applying it
+		jx create warmup -o warmup
+	`)
+)
+
+// CreateWarmupOptions the options for the create warmup command
+type CreateWarmupOptions struct {
+	CreateOptions
+
+	Images       []string
+	PVC          string
+	CachePath    string
+	CacheImage   string
+	CacheCommand string
+	Schedule     string
+	OutputDir    string
+}
+
+// NewCmdCreateWarmup creates the command
+func NewCmdCreateWarmup(f Factory, in terminal.FileReader, out terminal.FileWriter, errOut io.Writer) *cobra.Command {
+	options := &CreateWarmupOptions{
+		CreateOptions: CreateOptions{
+			CommonOptions: CommonOptions{
+				Factory: f,
+				In:      in,
+				Out:     out,
+				Err:     errOut,
+			},
+		},
+	}
+
+	cmd := &cobra.Command{
+		Use:     "warmup",
+		Short:   "Creates the resources which warm up the builder images and dependency caches of pipelines",
+		Long:    createWarmupLong,
+		Example: createWarmupExample,
+		Run: func(cmd *cobra.Command, args []string) {
+			options.Cmd = cmd
+			options.Args = args
+			err := options.Run()
+			CheckErr(err)
+		},
+	}
+	options.addCommonFlags(cmd)
+
+	cmd.Flags().StringArrayVarP(&options.Images, "image", "i", []string{}, "The builder images to pull onto each node. Defaults to the images of the pod templates")
+	cmd.Flags().StringVarP(&options.PVC, "pvc", "", "", "The name of the PersistentVolumeClaim containing the shared dependency cache")
+	cmd.Flags().StringVarP(&options.CachePath, "cache-path", "", "/cache", "The path the dependency cache is mounted at")
+	cmd.Flags().StringVarP(&options.CacheImage, "cache-image", "", "", "The image used to warm up the dependency cache")
+	cmd.Flags().StringVarP(&options.CacheCommand, "cache-command", "", "", "The shell command which warms up the dependency cache")
+	cmd.Flags().StringVarP(&options.Schedule, "schedule", "", "0 */6 * * *", "The cron schedule used to warm up the dependency cache")
+	cmd.Flags().StringVarP(&options.OutputDir, "output-dir", "o", "", "The directory to write the generated YAML to instead of applying it")
+	return cmd
+}
+
+// Run implements the command
+func (o *CreateWarmupOptions) Run() error {
+	kubeClient, ns, err := o.KubeClientAndDevNamespace()
+	if err != nil {
+		return err
+	}
+	images := o.Images
+	if len(images) == 0 {
+		images, err = builderImages(kubeClient, ns)
+		if err != nil {
+			return err
+		}
+	}
+	if len(images) == 0 {
+		return fmt.Errorf("no builder images found in the pod templates; please specify the --image option")
+	}
+	daemonSet := o.createWarmupDaemonSet(images)
+
+	var cronJob *batchv1beta1.CronJob
+	if o.CacheCommand != "" {
+		if o.PVC == "" {
+			return util.MissingOption("pvc")
+		}
+		if o.CacheImage == "" {
+			return util.MissingOption("cache-image")
+		}
+		cronJob = o.createWarmupCronJob()
+	}
+
+	if o.OutputDir != "" {
+		err = os.MkdirAll(o.OutputDir, DefaultWritePermissions)
+		if err != nil {
+			return err
+		}
+		err = writeYaml(filepath.Join(o.OutputDir, "daemonset.yaml"), daemonSet)
+		if err != nil {
+			return err
+		}
+		if cronJob != nil {
+			err = writeYaml(filepath.Join(o.OutputDir, "cronjob.yaml"), cronJob)
+			if err != nil {
+				return err
+			}
+		}
+		log.Infof("Generated the warm up resources in %s\n", util.ColorInfo(o.OutputDir))
+		return nil
+	}
+
+	daemonSets := kubeClient.AppsV1().DaemonSets(ns)
+	existing, err := daemonSets.Get(warmupName, metav1.GetOptions{})
+	if err == nil {
+		existing.Spec = daemonSet.Spec
+		_, err = daemonSets.Update(existing)
+	} else {
+		_, err = daemonSets.Create(daemonSet)
+	}
+	if err != nil {
+		return err
+	}
+	log.Infof("Pulling builder images %s onto every node via DaemonSet %s\n", util.ColorInfo(strings.Join(images, ", ")), util.ColorInfo(warmupName))
+
+	if cronJob != nil {
+		cronJobs := kubeClient.BatchV1beta1().CronJobs(ns)
+		existing, err := cronJobs.Get(warmupName, metav1.GetOptions{})
+		if err == nil {
+			existing.Spec = cronJob.Spec
+			_, err = cronJobs.Update(existing)
+		} else {
+			_, err = cronJobs.Create(cronJob)
+		}
+		if err != nil {
+			return err
+		}
+		log.Infof("Warming up the dependency cache in PVC %s on schedule %s via CronJob %s\n", util.ColorInfo(o.PVC), util.ColorInfo(o.Schedule), util.ColorInfo(warmupName))
+	}
+	return nil
+}
+
+// builderImages returns the sorted unique images of the containers of the pod templates
+func builderImages(kubeClient kubernetes.Interface, ns string) ([]string, error) {
+	podTemplates, err := kube.LoadPodTemplates(kubeClient, ns)
+	if err != nil {
+		return nil, err
+	}
+	images := []string{}
+	for _, pod := range podTemplates {
+		for _, c := range pod.Spec.Containers {
+			if c.Image != "" && util.StringArrayIndex(images, c.Image) < 0 {
+				images = append(images, c.Image)
+			}
+		}
+	}
+	sort.Strings(images)
+	return images, nil
+}
+
+func (o *CreateWarmupOptions) createWarmupDaemonSet(images []string) *appsv1.DaemonSet {
+	labels := map[string]string{"app": warmupName}
+	initContainers := []corev1.Container{}
+	for i, image := range images {
+		initContainers = append(initContainers, corev1.Container{
+			Name:    fmt.Sprintf("pull-%d", i),
+			Image:   image,
+			Command: []string{"sh", "-c", "exit 0"},
+		})
+	}
+	return &appsv1.DaemonSet{
+		TypeMeta: metav1.TypeMeta{
+			APIVersion: "apps/v1",
+			Kind:       "DaemonSet",
+		},
+		ObjectMeta: metav1.ObjectMeta{
+			Name:   warmupName,
+			Labels: labels,
+		},
+		Spec: appsv1.DaemonSetSpec{
+			Selector: &metav1.LabelSelector{
+				MatchLabels: labels,
+			},
+			Template: corev1.PodTemplateSpec{
+				ObjectMeta: metav1.ObjectMeta{
+					Labels: labels,
+				},
+				Spec: corev1.PodSpec{
+					InitContainers: initContainers,
+					Containers: []corev1.Container{
+						{
+							Name:  "pause",
+							Image: warmupPauseImage,
+							Resources: corev1.ResourceRequirements{
+								Requests: corev1.ResourceList{
+									corev1.ResourceCPU:    resource.MustParse("1m"),
+									corev1.ResourceMemory: resource.MustParse("8Mi"),
+								},
+							},
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+func (o *CreateWarmupOptions) createWarmupCronJob() *batchv1beta1.CronJob {
+	return &batchv1beta1.CronJob{
+		TypeMeta: metav1.TypeMeta{
+			APIVersion: "batch/v1beta1",
+			Kind:       "CronJob",
+		},
+		ObjectMeta: metav1.ObjectMeta{
+			Name:   warmupName,
+			Labels: map[string]string{"app": warmupName},
+		},
+		Spec: batchv1beta1.CronJobSpec{
+			Schedule:          o.Schedule,
+			ConcurrencyPolicy: batchv1beta1.ForbidConcurrent,
+			JobTemplate: batchv1beta1.JobTemplateSpec{
+				Spec: batchv1.JobSpec{
+					Template: corev1.PodTemplateSpec{
+						Spec: corev1.PodSpec{
+							RestartPolicy: corev1.RestartPolicyOnFailure,
+							Containers: []corev1.Container{
+								{
+									Name:    "warmup",
+									Image:   o.CacheImage,
+									Command: []string{"sh", "-c", o.CacheCommand},
+									VolumeMounts: []corev1.VolumeMount{
+										{
+											Name:      "cache",
+											MountPath: o.CachePath,
+										},
+									},
+								},
+							},
+							Volumes: []corev1.Volume{
+								{
+									Name: "cache",
+									VolumeSource: corev1.VolumeSource{
+										PersistentVolumeClaim: &corev1.PersistentVolumeClaimVolumeSource{
+											ClaimName: o.PVC,
+										},
+									},
+								},
+							},
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+func writeYaml(fileName string, obj interface{}) error {
+	data, err := yaml.Marshal(obj)
+	if err != nil {
+		return err
+	}
+	return ioutil.WriteFile(fileName, data, DefaultWritePermissions)
+}
@@ -0,0 +1,79 @@
+package cmd_test
+
+import (
+	"testing"
+
+	"github.com/jenkins-x/jx/pkg/gits"
+	"github.com/jenkins-x/jx/pkg/helm"
+	"github.com/jenkins-x/jx/pkg/jx/cmd"
+	"github.com/jenkins-x/jx/pkg/kube"
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+)
+
+func TestCreateWarmup(t *testing.T) {
+	t.Parallel()
+	cm := &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      kube.ConfigMapJenkinsPodTemplates,
+			Namespace: "jx",
+		},
+		Data: map[string]string{
+			"maven": `
+metadata:
+  name: maven
+spec:
+  containers:
+  - name: maven
+    image: jenkinsxio/builder-maven:0.0.1
+  - name: jnlp
+    image: jenkinsci/jnlp-slave:3.14-1
+`,
+			"go": `
+metadata:
+  name: go
+spec:
+  containers:
+  - name: go
+    image: jenkinsxio/builder-go:0.0.1
+  - name: jnlp
+    image: jenkinsci/jnlp-slave:3.14-1
+`,
+		},
+	}
+
+	o := &cmd.CreateWarmupOptions{
+		PVC:          "maven-cache",
+		CachePath:    "/root/.m2",
+		CacheImage:   "jenkinsxio/builder-maven:0.0.1",
+		CacheCommand: "mvn dependency:go-offline",
+		Schedule:     "0 */6 * * *",
+	}
+	cmd.ConfigureTestOptionsWithResources(&o.CommonOptions, []runtime.Object{cm}, nil, gits.NewGitCLI(), helm.NewHelmCLI("helm", helm.V2, "", true))
+
+	err := o.Run()
+	assert.NoError(t, err)
+
+	kubeClient, ns, err := o.KubeClientAndDevNamespace()
+	assert.NoError(t, err)
+	daemonSet, err := kubeClient.AppsV1().DaemonSets(ns).Get("jx-builder-warmup", metav1.GetOptions{})
+	assert.NoError(t, err)
+	images := []string{}
+	for _, c := range daemonSet.Spec.Template.Spec.InitContainers {
+		images = append(images, c.Image)
+	}
+	assert.Equal(t, []string{"jenkinsci/jnlp-slave:3.14-1", "jenkinsxio/builder-go:0.0.1", "jenkinsxio/builder-maven:0.0.1"}, images)
+
+	cronJob, err := kubeClient.BatchV1beta1().CronJobs(ns).Get("jx-builder-warmup", metav1.GetOptions{})
+	assert.NoError(t, err)
+	assert.Equal(t, "0 */6 * * *", cronJob.Spec.Schedule)
+	podSpec := cronJob.Spec.JobTemplate.Spec.Template.Spec
+	assert.Equal(t, "maven-cache", podSpec.Volumes[0].PersistentVolumeClaim.ClaimName)
+	assert.Equal(t, "/root/.m2", podSpec.Containers[0].VolumeMounts[0].MountPath)
+
+	// running again should update the existing resources
+	err = o.Run()
+	assert.NoError(t, err)
+}
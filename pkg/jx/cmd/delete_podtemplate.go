@@ -0,0 +1,78 @@
+package cmd
+
+import (
+	"fmt"
+	"io"
+
+	"github.com/jenkins-x/jx/pkg/jx/cmd/templates"
+	"github.com/jenkins-x/jx/pkg/kube"
+	"github.com/jenkins-x/jx/pkg/log"
+	"github.com/jenkins-x/jx/pkg/util"
+	"github.com/spf13/cobra"
+	"gopkg.in/AlecAivazis/survey.v1/terminal"
+)
+
+var (
+	deletePodTemplateLong = templates.LongDesc(`
+		Deletes a pod template from the current team
+`)
+
+	deletePodTemplateExample = templates.Examples(`
+		# Delete a pod template
+		jx delete podtemplate maven-java11
+	`)
+)
+
+// DeletePodTemplateOptions the options for the delete podtemplate command
+type DeletePodTemplateOptions struct {
+	DeleteOptions
+}
+
+// NewCmdDeletePodTemplate creates a command object for the "delete podtemplate" command
+func NewCmdDeletePodTemplate(f Factory, in terminal.FileReader, out terminal.FileWriter, errOut io.Writer) *cobra.Command {
+	options := &DeletePodTemplateOptions{
+		DeleteOptions: DeleteOptions{
+			CommonOptions: CommonOptions{
+				Factory: f,
+				In:      in,
+				Out:     out,
+				Err:     errOut,
+			},
+		},
+	}
+
+	cmd := &cobra.Command{
+		Use:     podTemplate + " [name]",
+		Short:   "Deletes a pod template from the current team",
+		Aliases: podTemplateAliases,
+		Long:    deletePodTemplateLong,
+		Example: deletePodTemplateExample,
+		Run: func(cmd *cobra.Command, args []string) {
+			options.Cmd = cmd
+			options.Args = args
+			err := options.Run()
+			CheckErr(err)
+		},
+	}
+	options.addCommonFlags(cmd)
+	return cmd
+}
+
+// Run implements the command
+func (o *DeletePodTemplateOptions) Run() error {
+	if len(o.Args) == 0 {
+		return fmt.Errorf("Missing pod template name argument")
+	}
+	name := o.Args[0]
+	kubeClient, ns, err := o.KubeClientAndDevNamespace()
+	if err != nil {
+		return err
+	}
+	err = kube.DeletePodTemplate(kubeClient, ns, name)
+	if err != nil {
+		return err
+	}
+	invalidatePodTemplatesCache(ns)
+	log.Infof("Deleted pod template %s\n", util.ColorInfo(name))
+	return nil
+}
@@ -36,6 +36,8 @@ func NewCmdDiagnose(f Factory, in terminal.FileReader, out terminal.FileWriter,
 	}
 	cmd.Flags().StringVarP(&options.Namespace, "namespace", "n", "", "The namespace to display the kube resources from. If left out, defaults to the current namespace")
 	options.addCommonFlags(cmd)
+
+	cmd.AddCommand(NewCmdDiagnosePipeline(f, in, out, errOut))
 	return cmd
 }
 
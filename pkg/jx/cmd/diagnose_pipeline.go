@@ -0,0 +1,148 @@
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"sort"
+
+	"github.com/jenkins-x/jx/pkg/builds"
+	"github.com/jenkins-x/jx/pkg/jx/cmd/templates"
+	"github.com/jenkins-x/jx/pkg/log"
+	"github.com/jenkins-x/jx/pkg/util"
+	"github.com/spf13/cobra"
+	"gopkg.in/AlecAivazis/survey.v1/terminal"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+var (
+	diagnosePipelineLong = templates.LongDesc(`
+		Diagnoses a failed pipeline build by analysing the events and container statuses of its build pod.
+
+		The probable causes of the failure are displayed with the most likely first along with how to fix them.
+`)
+
+	diagnosePipelineExample = templates.Examples(`
+		# Pick a failed build to diagnose
+		jx diagnose pipeline
+
+		# Diagnose a specific build
+		jx diagnose pipeline myorg-myapp-master-3
+	`)
+)
+
+// DiagnosePipelineOptions the options for the diagnose pipeline command
+type DiagnosePipelineOptions struct {
+	CommonOptions
+
+	Namespace string
+}
+
+// NewCmdDiagnosePipeline creates the command
+func NewCmdDiagnosePipeline(f Factory, in terminal.FileReader, out terminal.FileWriter, errOut io.Writer) *cobra.Command {
+	options := &DiagnosePipelineOptions{
+		CommonOptions: CommonOptions{
+			Factory: f,
+			In:      in,
+			Out:     out,
+			Err:     errOut,
+		},
+	}
+	cmd := &cobra.Command{
+		Use:     "pipeline [build]",
+		Short:   "Diagnoses the probable causes of a failed pipeline build",
+		Long:    diagnosePipelineLong,
+		Example: diagnosePipelineExample,
+		Aliases: []string{"pipelines", "build"},
+		Run: func(cmd *cobra.Command, args []string) {
+			options.Cmd = cmd
+			options.Args = args
+			err := options.Run()
+			CheckErr(err)
+		},
+	}
+	cmd.Flags().StringVarP(&options.Namespace, "namespace", "n", "", "The namespace of the build pods. If left out, defaults to the development namespace")
+	options.addCommonFlags(cmd)
+	return cmd
+}
+
+// Run implements the command
+func (o *DiagnosePipelineOptions) Run() error {
+	kubeClient, devNs, err := o.KubeClientAndDevNamespace()
+	if err != nil {
+		return err
+	}
+	ns := o.Namespace
+	if ns == "" {
+		ns = devNs
+	}
+	pods, err := builds.GetBuildPods(kubeClient, ns)
+	if err != nil {
+		return err
+	}
+	podMap := map[string]*corev1.Pod{}
+	failedNames := []string{}
+	for _, pod := range pods {
+		name := buildPodName(pod)
+		podMap[name] = pod
+		podMap[pod.Name] = pod
+		if pod.Status.Phase == corev1.PodFailed {
+			failedNames = append(failedNames, name)
+		}
+	}
+	sort.Strings(failedNames)
+
+	name := ""
+	if len(o.Args) > 0 {
+		name = o.Args[0]
+	} else {
+		if len(failedNames) == 0 {
+			log.Infof("There are no failed builds in namespace %s\n", util.ColorInfo(ns))
+			return nil
+		}
+		name, err = util.PickName(failedNames, "Which build do you want to diagnose?: ", o.In, o.Out, o.Err)
+		if err != nil {
+			return err
+		}
+	}
+	pod := podMap[name]
+	if pod == nil {
+		return util.InvalidArg(name, failedNames)
+	}
+
+	eventList, err := kubeClient.CoreV1().Events(ns).List(metav1.ListOptions{
+		FieldSelector: "involvedObject.name=" + pod.Name,
+	})
+	if err != nil {
+		return err
+	}
+	causes := builds.DiagnoseBuildPod(pod, eventList.Items)
+	if len(causes) == 0 {
+		log.Infof("Could not find a probable cause of the failure of build %s. View the logs via: %s\n", util.ColorInfo(name), util.ColorInfo("jx get build log"))
+		return nil
+	}
+	log.Infof("Probable causes of the failure of build %s:\n\n", util.ColorInfo(name))
+	for i, cause := range causes {
+		text := cause.Cause
+		if cause.Container != "" {
+			text = fmt.Sprintf("step %s: %s", util.ColorInfo(cause.Container), text)
+		}
+		log.Infof("%d. %s\n", i+1, text)
+		log.Infof("   to fix: %s\n\n", util.ColorInfo(cause.Fix))
+	}
+	return nil
+}
+
+// buildPodName returns the build name of a build pod or the pod name if it has no build label
+func buildPodName(pod *corev1.Pod) string {
+	if pod.Labels != nil {
+		name := pod.Labels[builds.LabelBuildName]
+		if name == "" {
+			name = pod.Labels[builds.LabelOldBuildName]
+		}
+		if name != "" {
+			return name
+		}
+	}
+	return pod.Name
+}
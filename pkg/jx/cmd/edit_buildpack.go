@@ -1,11 +1,17 @@
 package cmd
 
 import (
+	"fmt"
 	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
 
 	"github.com/jenkins-x/jx/pkg/apis/jenkins.io/v1"
 	"github.com/jenkins-x/jx/pkg/log"
 	"github.com/jenkins-x/jx/pkg/util"
+	"github.com/pkg/errors"
 	"github.com/spf13/cobra"
 	"gopkg.in/AlecAivazis/survey.v1/terminal"
 
@@ -18,9 +24,12 @@ var (
 `)
 
 	editBuildpackExample = templates.Examples(`
-		# Edit the build pack configuration for your team
+		# Edit the build pack configuration for your team, picking from the known build pack catalogs
 		jx edit buildpack
 
+		# Switch to a custom build pack repository and ref
+		jx edit buildpack -u https://github.com/myorg/mypacks.git -r master -b
+
 		For more documentation see: [https://jenkins-x.io/architecture/build-packs/](https://jenkins-x.io/architecture/build-packs/)
 	`)
 )
@@ -31,6 +40,18 @@ type EditBuildpackOptions struct {
 
 	BuildPackURL string
 	BuildPackRef string
+	NoValidate   bool
+}
+
+const (
+	customBuildPackCatalog = "Custom"
+)
+
+// BuildPackCatalogs the known build pack catalogs a team can choose from keyed by their description
+var BuildPackCatalogs = map[string]string{
+	"Kubernetes Workloads: Automated CI+CD with GitOps Promotion": "https://github.com/jenkins-x-buildpacks/jenkins-x-kubernetes.git",
+	"Library Workloads: CI+Release but no CD":                     "https://github.com/jenkins-x-buildpacks/jenkins-x-classic.git",
+	"Draft Packs: the original Jenkins X build packs":             JenkinsBuildPackURL,
 }
 
 // NewCmdEditBuildpack creates a command object for the "create" command
@@ -61,6 +82,7 @@ func NewCmdEditBuildpack(f Factory, in terminal.FileReader, out terminal.FileWri
 	}
 	cmd.Flags().StringVarP(&options.BuildPackURL, "url", "u", "", "The URL for the build pack Git repository")
 	cmd.Flags().StringVarP(&options.BuildPackRef, "ref", "r", "", "The Git reference (branch,tag,sha) in the Git repository touse")
+	cmd.Flags().BoolVarP(&options.NoValidate, "no-validate", "", false, "Disables cloning the build pack repository to validate its structure before saving it")
 	options.addCommonFlags(cmd)
 	return cmd
 }
@@ -76,7 +98,7 @@ func (o *EditBuildpackOptions) Run() error {
 			return err
 		}
 		if buildPackURL == "" {
-			buildPackURL, err = util.PickValue("Build pack git clone URL:", teamSettings.BuildPackURL, true, o.In, o.Out, o.Err)
+			buildPackURL, err = o.pickBuildPackURL(teamSettings.BuildPackURL)
 			if err != nil {
 				return err
 			}
@@ -89,6 +111,28 @@ func (o *EditBuildpackOptions) Run() error {
 		}
 	}
 
+	if (buildPackURL != "" || BuildPackRef != "") && !o.NoValidate {
+		// the repository is validated at the ref it will be used at even if only one of them changes
+		validateURL := buildPackURL
+		validateRef := BuildPackRef
+		if validateURL == "" || validateRef == "" {
+			teamSettings, err := o.TeamSettings()
+			if err != nil {
+				return err
+			}
+			if validateURL == "" {
+				validateURL = teamSettings.BuildPackURL
+			}
+			if validateRef == "" {
+				validateRef = teamSettings.BuildPackRef
+			}
+		}
+		err := o.validateBuildPackRepository(validateURL, validateRef)
+		if err != nil {
+			return err
+		}
+	}
+
 	callback := func(env *v1.Environment) error {
 		teamSettings := &env.Spec.TeamSettings
 		if buildPackURL != "" {
@@ -102,3 +146,70 @@ func (o *EditBuildpackOptions) Run() error {
 	}
 	return o.ModifyDevEnvironment(callback)
 }
+
+// pickBuildPackURL lets the user pick one of the known build pack catalogs or enter a custom git URL
+func (o *EditBuildpackOptions) pickBuildPackURL(currentURL string) (string, error) {
+	names := []string{}
+	defaultName := customBuildPackCatalog
+	for name, u := range BuildPackCatalogs {
+		names = append(names, name)
+		if u == currentURL {
+			defaultName = name
+		}
+	}
+	sort.Strings(names)
+	names = append(names, customBuildPackCatalog)
+
+	name, err := util.PickRequiredNameWithDefault(names, "Pick the build pack catalog for your team:", defaultName, o.In, o.Out, o.Err)
+	if err != nil {
+		return "", err
+	}
+	if name != customBuildPackCatalog {
+		return BuildPackCatalogs[name], nil
+	}
+	return util.PickValue("Build pack git clone URL:", currentURL, true, o.In, o.Out, o.Err)
+}
+
+// validateBuildPackRepository clones the build pack repository and verifies that it contains build packs
+func (o *EditBuildpackOptions) validateBuildPackRepository(gitURL string, ref string) error {
+	dir, err := ioutil.TempDir("", "jx-buildpack-")
+	if err != nil {
+		return err
+	}
+	defer os.RemoveAll(dir)
+
+	log.Infof("Validating the build pack repository %s\n", util.ColorInfo(gitURL))
+	err = o.Git().Clone(gitURL, dir)
+	if err != nil {
+		return errors.Wrapf(err, "failed to clone the build pack repository %s", gitURL)
+	}
+	if ref != "" {
+		err = o.Git().Checkout(dir, ref)
+		if err != nil {
+			return errors.Wrapf(err, "failed to checkout ref %s of the build pack repository %s", ref, gitURL)
+		}
+	}
+	return validateBuildPackDir(dir, gitURL)
+}
+
+// validateBuildPackDir verifies the given directory contains a packs folder with at least one build pack
+func validateBuildPackDir(dir string, gitURL string) error {
+	packsDir := filepath.Join(dir, "packs")
+	exists, err := util.FileExists(packsDir)
+	if err != nil {
+		return err
+	}
+	if !exists {
+		return fmt.Errorf("The build pack repository %s does not contain a packs directory", gitURL)
+	}
+	files, err := ioutil.ReadDir(packsDir)
+	if err != nil {
+		return err
+	}
+	for _, f := range files {
+		if f.IsDir() {
+			return nil
+		}
+	}
+	return fmt.Errorf("The build pack repository %s does not contain any build packs in its packs directory", gitURL)
+}
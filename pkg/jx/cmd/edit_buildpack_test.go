@@ -0,0 +1,105 @@
+package cmd
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/jenkins-x/jx/pkg/apis/jenkins.io/v1"
+	"github.com/jenkins-x/jx/pkg/gits"
+	"github.com/jenkins-x/jx/pkg/helm"
+	"github.com/jenkins-x/jx/pkg/kube"
+	"github.com/jenkins-x/jx/pkg/util"
+	"github.com/stretchr/testify/assert"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+)
+
+func TestValidateBuildPackDir(t *testing.T) {
+	t.Parallel()
+	dir, err := ioutil.TempDir("", "test-validate-buildpack-dir")
+	assert.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	err = validateBuildPackDir(dir, "https://github.com/myorg/mypacks.git")
+	assert.Error(t, err, "a repository without a packs directory is not a build pack repository")
+
+	packsDir := filepath.Join(dir, "packs")
+	err = os.MkdirAll(packsDir, util.DefaultWritePermissions)
+	assert.NoError(t, err)
+	err = ioutil.WriteFile(filepath.Join(packsDir, "README.md"), []byte("build packs\n"), util.DefaultWritePermissions)
+	assert.NoError(t, err)
+	err = validateBuildPackDir(dir, "https://github.com/myorg/mypacks.git")
+	assert.Error(t, err, "a packs directory without any build pack directories has no build packs")
+
+	err = os.MkdirAll(filepath.Join(packsDir, "go"), util.DefaultWritePermissions)
+	assert.NoError(t, err)
+	err = validateBuildPackDir(dir, "https://github.com/myorg/mypacks.git")
+	assert.NoError(t, err)
+}
+
+func TestEditBuildpackValidatesRef(t *testing.T) {
+	t.Parallel()
+	repoDir, err := ioutil.TempDir("", "test-edit-buildpack")
+	assert.NoError(t, err)
+	defer os.RemoveAll(repoDir)
+
+	// the build packs only exist at tag v1 as they are removed by the following commit
+	gitter := gits.NewGitCLI()
+	err = gitter.Init(repoDir)
+	assert.NoError(t, err)
+	for _, args := range [][]string{{"config", "user.name", "test"}, {"config", "user.email", "test@example.com"}} {
+		_, err = (&util.Command{Dir: repoDir, Name: "git", Args: args}).RunWithoutRetry()
+		assert.NoError(t, err)
+	}
+	packDir := filepath.Join(repoDir, "packs", "go")
+	err = os.MkdirAll(packDir, util.DefaultWritePermissions)
+	assert.NoError(t, err)
+	err = ioutil.WriteFile(filepath.Join(packDir, "pipeline.yaml"), []byte("extends: {}\n"), util.DefaultWritePermissions)
+	assert.NoError(t, err)
+	err = gitter.Add(repoDir, "*")
+	assert.NoError(t, err)
+	err = gitter.CommitDir(repoDir, "add the go build pack")
+	assert.NoError(t, err)
+	_, err = (&util.Command{Dir: repoDir, Name: "git", Args: []string{"tag", "v1"}}).RunWithoutRetry()
+	assert.NoError(t, err)
+	_, err = (&util.Command{Dir: repoDir, Name: "git", Args: []string{"rm", "-r", "-q", "packs"}}).RunWithoutRetry()
+	assert.NoError(t, err)
+	err = gitter.CommitDir(repoDir, "remove the build packs")
+	assert.NoError(t, err)
+	repoURL := "file://" + repoDir
+
+	devEnv := kube.NewPermanentEnvironment("dev")
+	devEnv.Spec.Namespace = "jx"
+	devEnv.Spec.Kind = v1.EnvironmentKindTypeDevelopment
+	devEnv.Spec.TeamSettings.BuildPackURL = repoURL
+	devEnv.Spec.TeamSettings.BuildPackRef = "v1"
+
+	edit := func(buildPackURL string, buildPackRef string) (*v1.TeamSettings, error) {
+		o := &EditBuildpackOptions{}
+		ConfigureTestOptionsWithResources(&o.CommonOptions, nil, []runtime.Object{devEnv.DeepCopy()}, gitter, helm.NewHelmCLI("helm", helm.V2, repoDir, true))
+		o.BuildPackURL = buildPackURL
+		o.BuildPackRef = buildPackRef
+		err := o.Run()
+		if err != nil {
+			return nil, err
+		}
+		jxClient, ns, err := o.JXClientAndDevNamespace()
+		assert.NoError(t, err)
+		env, err := jxClient.JenkinsV1().Environments(ns).Get(kube.LabelValueDevEnvironment, metav1.GetOptions{})
+		assert.NoError(t, err)
+		return &env.Spec.TeamSettings, nil
+	}
+
+	teamSettings, err := edit(repoURL, "v1")
+	if assert.NoError(t, err, "the tag should be checked out to validate the build packs") {
+		assert.Equal(t, "v1", teamSettings.BuildPackRef)
+	}
+
+	_, err = edit("", "master")
+	assert.Error(t, err, "changing only the ref to one without build packs should fail the validation")
+
+	_, err = edit(repoURL, "")
+	assert.NoError(t, err, "changing only the URL should validate it at the ref of the team")
+}
@@ -0,0 +1,169 @@
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"os/exec"
+	"reflect"
+	"sort"
+
+	"github.com/ghodss/yaml"
+	"github.com/jenkins-x/jx/pkg/jx/cmd/templates"
+	"github.com/jenkins-x/jx/pkg/kube"
+	"github.com/jenkins-x/jx/pkg/log"
+	"github.com/jenkins-x/jx/pkg/util"
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+	"gopkg.in/AlecAivazis/survey.v1/terminal"
+)
+
+const (
+	defaultEditor = "vi"
+)
+
+var (
+	editPodTemplateLong = templates.LongDesc(`
+		Edits a pod template for the current team using the editor defined by the $EDITOR environment variable.
+
+		The pod template is edited as it is stored so any pod template it extends is not merged into it.
+		The modified pod template is validated before it is saved.
+`)
+
+	editPodTemplateExample = templates.Examples(`
+		# Edit the maven pod template
+		jx edit podtemplate maven
+
+		# Pick the pod template to edit
+		jx edit podtemplate
+	`)
+)
+
+// EditPodTemplateOptions the options for the edit podtemplate command
+type EditPodTemplateOptions struct {
+	EditOptions
+}
+
+// NewCmdEditPodTemplate creates a command object for the "edit podtemplate" command
+func NewCmdEditPodTemplate(f Factory, in terminal.FileReader, out terminal.FileWriter, errOut io.Writer) *cobra.Command {
+	options := &EditPodTemplateOptions{
+		EditOptions: EditOptions{
+			CommonOptions: CommonOptions{
+				Factory: f,
+				In:      in,
+				Out:     out,
+				Err:     errOut,
+			},
+		},
+	}
+
+	cmd := &cobra.Command{
+		Use:     podTemplate + " [name]",
+		Short:   "Edits a pod template for the current team",
+		Aliases: podTemplateAliases,
+		Long:    editPodTemplateLong,
+		Example: editPodTemplateExample,
+		Run: func(cmd *cobra.Command, args []string) {
+			options.Cmd = cmd
+			options.Args = args
+			err := options.Run()
+			CheckErr(err)
+		},
+	}
+	options.addCommonFlags(cmd)
+	return cmd
+}
+
+// Run implements the command
+func (o *EditPodTemplateOptions) Run() error {
+	kubeClient, ns, err := o.KubeClientAndDevNamespace()
+	if err != nil {
+		return err
+	}
+	podTemplates, err := kube.LoadUnresolvedPodTemplates(kubeClient, ns)
+	if err != nil {
+		return err
+	}
+	names := []string{}
+	for name := range podTemplates {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	name := ""
+	if len(o.Args) > 0 {
+		name = o.Args[0]
+	} else {
+		if o.BatchMode {
+			return fmt.Errorf("Missing pod template name argument")
+		}
+		name, err = util.PickName(names, "Pick the pod template to edit:", o.In, o.Out, o.Err)
+		if err != nil {
+			return err
+		}
+	}
+	pod := podTemplates[name]
+	if pod == nil {
+		return util.InvalidArg(name, names)
+	}
+
+	data, err := yaml.Marshal(pod)
+	if err != nil {
+		return err
+	}
+	tmpFile, err := ioutil.TempFile("", "jx-podtemplate-"+name+"-")
+	if err != nil {
+		return err
+	}
+	fileName := tmpFile.Name() + ".yaml"
+	tmpFile.Close()
+	os.Remove(tmpFile.Name())
+	defer os.Remove(fileName)
+
+	err = ioutil.WriteFile(fileName, data, DefaultWritePermissions)
+	if err != nil {
+		return err
+	}
+	err = o.runEditor(fileName)
+	if err != nil {
+		return err
+	}
+	data, err = ioutil.ReadFile(fileName)
+	if err != nil {
+		return err
+	}
+	// the modified pod template is parsed like the stored ones so that any 'extends:' key is kept
+	modified, err := kube.ParsePodTemplate(name, string(data))
+	if err != nil {
+		return errors.Wrapf(err, "failed to parse the modified pod template %s", name)
+	}
+	if reflect.DeepEqual(pod, modified) {
+		log.Infof("No changes made to pod template %s\n", util.ColorInfo(name))
+		return nil
+	}
+	err = kube.SavePodTemplate(kubeClient, ns, name, modified)
+	if err != nil {
+		return err
+	}
+	invalidatePodTemplatesCache(ns)
+	log.Infof("Saved pod template %s\n", util.ColorInfo(name))
+	return nil
+}
+
+// runEditor opens the given file in the users editor
+func (o *EditPodTemplateOptions) runEditor(fileName string) error {
+	editor := os.Getenv("EDITOR")
+	if editor == "" {
+		editor = defaultEditor
+	}
+	e := exec.Command(editor, fileName)
+	e.Stdin = os.Stdin
+	e.Stdout = os.Stdout
+	e.Stderr = os.Stderr
+	err := e.Run()
+	if err != nil {
+		return errors.Wrapf(err, "failed to run the editor %s", editor)
+	}
+	return nil
+}
@@ -0,0 +1,72 @@
+package cmd_test
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/jenkins-x/jx/pkg/gits"
+	"github.com/jenkins-x/jx/pkg/helm"
+	"github.com/jenkins-x/jx/pkg/jx/cmd"
+	"github.com/jenkins-x/jx/pkg/kube"
+	"github.com/jenkins-x/jx/pkg/util"
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+)
+
+func TestEditPodTemplate(t *testing.T) {
+	testDir, err := ioutil.TempDir("", "test-edit-podtemplate")
+	assert.NoError(t, err)
+	defer os.RemoveAll(testDir)
+
+	// the editor makes the pod template extend another one
+	editor := filepath.Join(testDir, "editor.sh")
+	err = ioutil.WriteFile(editor, []byte("#!/bin/sh\necho 'extends: maven' >> \"$1\"\n"), 0755)
+	assert.NoError(t, err)
+	oldEditor := os.Getenv("EDITOR")
+	defer os.Setenv("EDITOR", oldEditor)
+	os.Setenv("EDITOR", editor)
+
+	// the cached pod templates of jx step create build are removed once the pod template is saved
+	jxHome := os.Getenv("JX_HOME")
+	defer os.Setenv("JX_HOME", jxHome)
+	os.Setenv("JX_HOME", testDir)
+	cacheDir := filepath.Join(testDir, "cache", "createbuild")
+	err = os.MkdirAll(cacheDir, util.DefaultWritePermissions)
+	assert.NoError(t, err)
+	cacheFile := filepath.Join(cacheDir, "podtemplates-0123-jx.json")
+	err = ioutil.WriteFile(cacheFile, []byte("{}"), util.DefaultWritePermissions)
+	assert.NoError(t, err)
+
+	k8sObjects := []runtime.Object{
+		&corev1.ConfigMap{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      kube.ConfigMapJenkinsPodTemplates,
+				Namespace: "jx",
+			},
+			Data: map[string]string{
+				"maven":        MavenBuildPackYaml,
+				"maven-custom": MavenBuildPackYaml,
+			},
+		},
+	}
+	o := &cmd.EditPodTemplateOptions{}
+	cmd.ConfigureTestOptionsWithResources(&o.CommonOptions, k8sObjects, nil, gits.NewGitCLI(), helm.NewHelmCLI("helm", helm.V2, "", true))
+	o.Args = []string{"maven-custom"}
+
+	err = o.Run()
+	assert.NoError(t, err)
+
+	kubeClient, ns, err := o.KubeClientAndDevNamespace()
+	assert.NoError(t, err)
+	podTemplates, err := kube.LoadUnresolvedPodTemplates(kubeClient, ns)
+	if assert.NoError(t, err) && assert.NotNil(t, podTemplates["maven-custom"]) {
+		assert.Equal(t, "maven", podTemplates["maven-custom"].Annotations[kube.PodTemplateExtendsAnnotation])
+	}
+	exists, err := util.FileExists(cacheFile)
+	assert.NoError(t, err)
+	assert.False(t, exists, "the cached pod templates should be removed")
+}
@@ -0,0 +1,90 @@
+package cmd
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/ghodss/yaml"
+	"github.com/jenkins-x/jx/pkg/util"
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+)
+
+// ApplyFlagDefaults sets the flags of the command which were not specified on the command line to the values in
+// the YAML files keyed by flag name. Files which do not exist are ignored and later files override earlier files
+func ApplyFlagDefaults(cmd *cobra.Command, fileNames ...string) error {
+	values := map[string]interface{}{}
+	for _, fileName := range fileNames {
+		exists, err := util.FileExists(fileName)
+		if err != nil || !exists {
+			continue
+		}
+		data, err := ioutil.ReadFile(fileName)
+		if err != nil {
+			return errors.Wrapf(err, "failed to read the flag defaults file %s", fileName)
+		}
+		fileValues := map[string]interface{}{}
+		err = yaml.Unmarshal(data, &fileValues)
+		if err != nil {
+			return util.WithExitCode(errors.Wrapf(err, "failed to parse the flag defaults file %s", fileName), util.ExitCodeValidation)
+		}
+		for k, v := range fileValues {
+			values[k] = v
+		}
+	}
+	names := []string{}
+	for name := range values {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		flag := cmd.Flags().Lookup(name)
+		if flag == nil {
+			return util.WithExitCode(fmt.Errorf("Unknown flag %s in the flag defaults of %s", name, cmd.CommandPath()), util.ExitCodeValidation)
+		}
+		if flag.Changed {
+			continue
+		}
+		items, ok := values[name].([]interface{})
+		if !ok {
+			items = []interface{}{values[name]}
+		}
+		for _, item := range items {
+			err := flag.Value.Set(fmt.Sprint(item))
+			if err != nil {
+				return util.InvalidOptionError(name, fmt.Sprint(item), err)
+			}
+		}
+	}
+	return nil
+}
+
+// ApplyFlagEnvVars sets the flags of the command which were not specified on the command line to the value of the
+// environment variable named after the flag such as $JX_BUILD_NUMBER for the build-number flag with the JX prefix
+func ApplyFlagEnvVars(cmd *cobra.Command, prefix string) error {
+	var err error
+	cmd.Flags().VisitAll(func(flag *pflag.Flag) {
+		if err != nil || flag.Changed {
+			return
+		}
+		name := FlagEnvVar(prefix, flag.Name)
+		value, ok := os.LookupEnv(name)
+		if !ok {
+			return
+		}
+		setErr := cmd.Flags().Set(flag.Name, value)
+		if setErr != nil {
+			err = util.InvalidOptionf(flag.Name, value, "Invalid value of $%s: %s", name, setErr)
+		}
+	})
+	return err
+}
+
+// FlagEnvVar returns the name of the environment variable of the flag
+func FlagEnvVar(prefix string, flag string) string {
+	return strings.ToUpper(prefix + "_" + strings.Replace(flag, "-", "_", -1))
+}
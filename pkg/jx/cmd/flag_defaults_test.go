@@ -0,0 +1,77 @@
+package cmd_test
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/jenkins-x/jx/pkg/jx/cmd"
+	"github.com/jenkins-x/jx/pkg/util"
+	"github.com/spf13/cobra"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestApplyFlagDefaults(t *testing.T) {
+	t.Parallel()
+	dir, err := ioutil.TempDir("", "test-apply-flag-defaults")
+	assert.NoError(t, err)
+	defer os.RemoveAll(dir)
+	userFile := filepath.Join(dir, "user.yaml")
+	repoFile := filepath.Join(dir, "repo.yaml")
+	err = ioutil.WriteFile(userFile, []byte("kind: release\nbuild-number: 3\nno-audit: true\n"), util.DefaultWritePermissions)
+	assert.NoError(t, err)
+	err = ioutil.WriteFile(repoFile, []byte("kind: pullRequest\nlabels:\n- a\n- b\n"), util.DefaultWritePermissions)
+	assert.NoError(t, err)
+
+	kind := ""
+	buildNumber := 1
+	noAudit := false
+	labels := []string{}
+	command := &cobra.Command{Use: "test"}
+	command.Flags().StringVarP(&kind, "kind", "k", "", "")
+	command.Flags().IntVarP(&buildNumber, "build-number", "n", 1, "")
+	command.Flags().BoolVarP(&noAudit, "no-audit", "", false, "")
+	command.Flags().StringArrayVarP(&labels, "labels", "", nil, "")
+	err = command.Flags().Parse([]string{"--build-number", "7"})
+	assert.NoError(t, err)
+
+	err = cmd.ApplyFlagDefaults(command, userFile, repoFile, filepath.Join(dir, "missing.yaml"))
+	assert.NoError(t, err)
+	assert.Equal(t, "pullRequest", kind)
+	assert.Equal(t, 7, buildNumber)
+	assert.True(t, noAudit)
+	assert.Equal(t, []string{"a", "b"}, labels)
+
+	err = ioutil.WriteFile(repoFile, []byte("unknown: true\n"), util.DefaultWritePermissions)
+	assert.NoError(t, err)
+	err = cmd.ApplyFlagDefaults(command, repoFile)
+	assert.Error(t, err)
+}
+
+func TestApplyFlagEnvVars(t *testing.T) {
+	kind := ""
+	buildNumber := 1
+	command := &cobra.Command{Use: "test"}
+	command.Flags().StringVarP(&kind, "kind", "k", "", "")
+	command.Flags().IntVarP(&buildNumber, "build-number", "n", 1, "")
+	err := command.Flags().Parse([]string{"--kind", "release"})
+	assert.NoError(t, err)
+
+	assert.Equal(t, "JX_BUILD_NUMBER", cmd.FlagEnvVar("JX", "build-number"))
+	os.Setenv("JX_TEST_BUILD_NUMBER", "5")
+	os.Setenv("JX_TEST_KIND", "pullRequest")
+	defer os.Unsetenv("JX_TEST_BUILD_NUMBER")
+	defer os.Unsetenv("JX_TEST_KIND")
+
+	err = cmd.ApplyFlagEnvVars(command, "JX_TEST")
+	assert.NoError(t, err)
+	assert.Equal(t, "release", kind)
+	assert.Equal(t, 5, buildNumber)
+	assert.True(t, command.Flags().Changed("build-number"))
+
+	os.Setenv("JX_TEST_BUILD_NUMBER", "five")
+	command.Flags().Lookup("build-number").Changed = false
+	err = cmd.ApplyFlagEnvVars(command, "JX_TEST")
+	assert.Error(t, err)
+}
@@ -22,8 +22,10 @@ const (
 
     * activities
 	* helm
+	* namespaces
 	* previews
 	* releases
+	* workspaces
     `
 )
 
@@ -39,8 +41,10 @@ var (
 		jx gc activities
 		jx gc gke
 		jx gc helm
+		jx gc namespaces
 		jx gc previews
 		jx gc releases
+		jx gc workspaces
 
 	`)
 )
@@ -74,7 +78,9 @@ func NewCmdGC(f Factory, in terminal.FileReader, out terminal.FileWriter, errOut
 	cmd.AddCommand(NewCmdGCPreviews(f, in, out, errOut))
 	cmd.AddCommand(NewCmdGCGKE(f, in, out, errOut))
 	cmd.AddCommand(NewCmdGCHelm(f, in, out, errOut))
+	cmd.AddCommand(NewCmdGCNamespaces(f, in, out, errOut))
 	cmd.AddCommand(NewCmdGCReleases(f, in, out, errOut))
+	cmd.AddCommand(NewCmdGCWorkspaces(f, in, out, errOut))
 
 	return cmd
 }
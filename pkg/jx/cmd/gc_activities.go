@@ -7,7 +7,10 @@ import (
 	"strconv"
 	"strings"
 
+	"github.com/jenkins-x/jx/pkg/history"
 	"github.com/jenkins-x/jx/pkg/kube"
+	"github.com/jenkins-x/jx/pkg/util"
+	"github.com/pkg/errors"
 	"github.com/spf13/cobra"
 	"gopkg.in/AlecAivazis/survey.v1/terminal"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
@@ -23,6 +26,7 @@ type GCActivitiesOptions struct {
 	CommonOptions
 
 	RevisionHistoryLimit int
+	HistoryBucketURL     string
 	jclient              gojenkins.JenkinsClient
 }
 
@@ -30,6 +34,8 @@ var (
 	GCActivitiesLong = templates.LongDesc(`
 		Garbage collect the Jenkins X Activity Custom Resource Definitions
 
+		If the team has a history bucket the completed activities are compacted into it before they are deleted so that 'jx get pipeline stats' can report on them.
+
 `)
 
 	GCActivitiesExample = templates.Examples(`
@@ -62,6 +68,7 @@ func NewCmdGCActivities(f Factory, in terminal.FileReader, out terminal.FileWrit
 		},
 	}
 	cmd.Flags().IntVarP(&options.RevisionHistoryLimit, "revision-history-limit", "l", 5, "Minimum number of Activities per application to keep")
+	cmd.Flags().StringVarP(&options.HistoryBucketURL, "history-bucket-url", "", "", "The URL of the bucket such as s3://mybucket or gs://mybucket to compact the completed activities into before deleting them. Defaults to the historyBucketUrl of the build defaults of the team")
 	options.addCommonFlags(cmd)
 	return cmd
 }
@@ -100,6 +107,28 @@ func (o *GCActivitiesOptions) Run() error {
 		return nil
 	}
 
+	if o.HistoryBucketURL == "" {
+		teamSettings, err := o.TeamSettings()
+		if err != nil {
+			return err
+		}
+		o.HistoryBucketURL = teamSettings.BuildDefaults.HistoryBucketURL
+	}
+	if o.HistoryBucketURL != "" {
+		runs := []history.PipelineRun{}
+		for i := range activities.Items {
+			run := history.NewPipelineRun(&activities.Items[i])
+			if run != nil {
+				runs = append(runs, *run)
+			}
+		}
+		err = history.Archive(o.HistoryBucketURL, runs)
+		if err != nil {
+			return errors.Wrapf(err, "failed to compact the activities into the history bucket %s", o.HistoryBucketURL)
+		}
+		log.Infof("Compacted %d completed activities into the history bucket %s\n", len(runs), util.ColorInfo(o.HistoryBucketURL))
+	}
+
 	prowEnabled, err := kube.IsProwEnabled(kubeClient, currentNs)
 	if err != nil {
 		return err
@@ -0,0 +1,96 @@
+package cmd
+
+import (
+	"io"
+	"time"
+
+	"github.com/jenkins-x/jx/pkg/jx/cmd/templates"
+	"github.com/jenkins-x/jx/pkg/kube"
+	"github.com/jenkins-x/jx/pkg/log"
+	"github.com/jenkins-x/jx/pkg/util"
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+	"gopkg.in/AlecAivazis/survey.v1/terminal"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// GCNamespacesOptions contains the command line flags
+type GCNamespacesOptions struct {
+	CommonOptions
+}
+
+var (
+	gcNamespacesLong = templates.LongDesc(`
+		Garbage collect the namespaces created for single builds by 'jx step create build --isolated' which have expired.
+		The namespaces are normally deleted by the last step of the build so this deletes the namespaces of failed builds.
+
+`)
+
+	gcNamespacesExample = templates.Examples(`
+		jx garbage collect namespaces
+		jx gc namespaces
+`)
+)
+
+// NewCmdGCNamespaces creates the command
+func NewCmdGCNamespaces(f Factory, in terminal.FileReader, out terminal.FileWriter, errOut io.Writer) *cobra.Command {
+	options := &GCNamespacesOptions{
+		CommonOptions: CommonOptions{
+			Factory: f,
+			In:      in,
+			Out:     out,
+			Err:     errOut,
+		},
+	}
+
+	cmd := &cobra.Command{
+		Use:     "namespaces",
+		Short:   "garbage collection for the expired namespaces of builds",
+		Long:    gcNamespacesLong,
+		Example: gcNamespacesExample,
+		Run: func(cmd *cobra.Command, args []string) {
+			options.Cmd = cmd
+			options.Args = args
+			err := options.Run()
+			CheckErr(err)
+		},
+	}
+	options.addCommonFlags(cmd)
+	return cmd
+}
+
+// Run implements this command
+func (o *GCNamespacesOptions) Run() error {
+	kubeClient, _, err := o.KubeClient()
+	if err != nil {
+		return err
+	}
+	namespaces, err := kubeClient.CoreV1().Namespaces().List(metav1.ListOptions{
+		LabelSelector: kube.LabelIsolated + "=true",
+	})
+	if err != nil {
+		return errors.Wrap(err, "failed to list the namespaces of builds")
+	}
+	now := time.Now()
+	for _, ns := range namespaces.Items {
+		text := ns.Annotations[kube.AnnotationExpires]
+		if text == "" {
+			continue
+		}
+		expires, err := time.Parse(time.RFC3339, text)
+		if err != nil {
+			log.Warnf("Ignoring namespace %s as its annotation %s is not a valid time: %s\n", ns.Name, kube.AnnotationExpires, text)
+			continue
+		}
+		if now.Before(expires) {
+			continue
+		}
+		err = kubeClient.CoreV1().Namespaces().Delete(ns.Name, &metav1.DeleteOptions{})
+		if err != nil && !apierrors.IsNotFound(err) {
+			return errors.Wrapf(err, "failed to delete namespace %s", ns.Name)
+		}
+		log.Infof("Deleted the expired namespace %s\n", util.ColorInfo(ns.Name))
+	}
+	return nil
+}
@@ -0,0 +1,86 @@
+package cmd
+
+import (
+	"io"
+	"os"
+	"time"
+
+	"github.com/jenkins-x/jx/pkg/jx/cmd/templates"
+	"github.com/jenkins-x/jx/pkg/log"
+	"github.com/jenkins-x/jx/pkg/util"
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+	"gopkg.in/AlecAivazis/survey.v1/terminal"
+)
+
+// GCWorkspacesOptions contains the command line flags
+type GCWorkspacesOptions struct {
+	CommonOptions
+
+	MaxAge time.Duration
+}
+
+var (
+	gcWorkspacesLong = templates.LongDesc(`
+		Garbage collect the temporary workspaces in ~/.jx/workspaces such as the clones of repositories and build packs.
+		The workspaces are normally removed by the commands which create them so this removes the workspaces of commands
+		which were killed or which are older than the maximum age.
+
+`)
+
+	gcWorkspacesExample = templates.Examples(`
+		jx garbage collect workspaces
+		jx gc workspaces
+
+		# remove the workspaces which are more than an hour old
+		jx gc workspaces --max-age 1h
+`)
+)
+
+// NewCmdGCWorkspaces creates the command
+func NewCmdGCWorkspaces(f Factory, in terminal.FileReader, out terminal.FileWriter, errOut io.Writer) *cobra.Command {
+	options := &GCWorkspacesOptions{
+		CommonOptions: CommonOptions{
+			Factory: f,
+			In:      in,
+			Out:     out,
+			Err:     errOut,
+		},
+	}
+
+	cmd := &cobra.Command{
+		Use:     "workspaces",
+		Short:   "garbage collection for the stale temporary workspaces of jx",
+		Long:    gcWorkspacesLong,
+		Example: gcWorkspacesExample,
+		Aliases: []string{"workspace"},
+		Run: func(cmd *cobra.Command, args []string) {
+			options.Cmd = cmd
+			options.Args = args
+			err := options.Run()
+			CheckErr(err)
+		},
+	}
+	options.addCommonFlags(cmd)
+	cmd.Flags().DurationVarP(&options.MaxAge, "max-age", "", 24*time.Hour, "The age after which the workspaces of running commands are removed")
+	return cmd
+}
+
+// Run implements this command
+func (o *GCWorkspacesOptions) Run() error {
+	workspaces, err := util.ListWorkspaces()
+	if err != nil {
+		return errors.Wrap(err, "failed to list the workspaces")
+	}
+	for _, workspace := range workspaces {
+		if !workspace.IsStale(o.MaxAge) {
+			continue
+		}
+		err = os.RemoveAll(workspace.Dir)
+		if err != nil {
+			return errors.Wrapf(err, "failed to remove workspace %s", workspace.Dir)
+		}
+		log.Infof("Removed the stale workspace %s\n", util.ColorInfo(workspace.Dir))
+	}
+	return nil
+}
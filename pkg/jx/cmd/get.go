@@ -83,6 +83,9 @@ func NewCmdGet(f Factory, in terminal.FileReader, out terminal.FileWriter, errOu
 	cmd.AddCommand(NewCmdGetIssues(f, in, out, errOut))
 	cmd.AddCommand(NewCmdGetLimits(f, in, out, errOut))
 	cmd.AddCommand(NewCmdGetPipeline(f, in, out, errOut))
+	cmd.AddCommand(NewCmdGetPipelineAudit(f, in, out, errOut))
+	cmd.AddCommand(NewCmdGetRepos(f, in, out, errOut))
+	cmd.AddCommand(NewCmdGetPodTemplate(f, in, out, errOut))
 	cmd.AddCommand(NewCmdGetPostPreviewJob(f, in, out, errOut))
 	cmd.AddCommand(NewCmdGetPreview(f, in, out, errOut))
 	cmd.AddCommand(NewCmdGetQuickstartLocation(f, in, out, errOut))
@@ -31,17 +31,24 @@ type GetBuildLogsOptions struct {
 	Tail   bool
 	Filter string
 	Build  int
+	Stage  string
+	Step   string
 }
 
 var (
 	get_build_log_long = templates.LongDesc(`
-		Display the Git server URLs.
+		Display a build log.
+
+		For Knative builds the logs of the steps are read from the build pod while it exists whether the build is running or has completed. Once the pod has been garbage collected the logs are read from the bucket they have been archived to by the build controller.
 
 `)
 
 	get_build_log_example = templates.Examples(`
-		# List all registered Git server URLs
-		jx get git
+		# Display the log of the last build of a pipeline
+		jx get build log myorg/myapp/master
+
+		# Display the log of the tests of build 3 of a pipeline
+		jx get build log myorg/myapp/master --build 3 --step test
 	`)
 )
 
@@ -75,6 +82,8 @@ func NewCmdGetBuildLogs(f Factory, in terminal.FileReader, out terminal.FileWrit
 	cmd.Flags().BoolVarP(&options.Tail, "tail", "t", true, "Tails the build log to the current terminal")
 	cmd.Flags().StringVarP(&options.Filter, "filter", "f", "", "Filters all the available jobs by those that contain the given text")
 	cmd.Flags().IntVarP(&options.Build, "build", "b", 0, "The build number to view")
+	cmd.Flags().StringVarP(&options.Stage, "stage", "", "", "Only displays the log of the stage of a Knative build with the given name")
+	cmd.Flags().StringVarP(&options.Step, "step", "", "", "Only displays the log of the step of a Knative build with the given name")
 
 	return cmd
 }
@@ -241,19 +250,17 @@ func (o *GetBuildLogsOptions) getProwBuildLog(kubeClient kubernetes.Interface, j
 			params.DefaultValuesFromEnvVars(lastInitC.Env)
 
 			if params.MatchesPipeline(build) {
-				return o.getPodLog(ns, pod, lastInitC)
+				return o.getBuildPodLogs(kubeClient, ns, pod)
 			}
 		}
 	}
+	if isArchivedBuildLog(build.Spec.BuildLogsURL) {
+		return o.getArchivedBuildLog(build.Spec.BuildLogsURL)
+	}
 	log.Warnf("No pod is available for pipeline %s build %s\n", util.ColorInfo(name), util.ColorInfo("#"+strconv.Itoa(buildNumber)))
 	return nil
 }
 
-func (o *GetBuildLogsOptions) getPodLog(ns string, pod *corev1.Pod, container corev1.Container) error {
-	log.Infof("Getting the pod log for pod %s and init container %s\n", pod.Name, container.Name)
-	return o.tailLogs(ns, pod.Name, container.Name)
-}
-
 type BuildParams struct {
 	GitOwner      string
 	GitRepository string
@@ -0,0 +1,156 @@
+package cmd
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/jenkins-x/jx/pkg/cloud/buckets"
+	"github.com/jenkins-x/jx/pkg/log"
+	"github.com/jenkins-x/jx/pkg/util"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+)
+
+// buildLogStep the log of a step of an archived build log
+type buildLogStep struct {
+	Name string
+	Log  []byte
+}
+
+// buildStepStageName returns the name of the stage of the pipeline activity of the step of a Knative build pod such
+// as 'Git Source' for the init container 'build-step-git-source'
+func buildStepStageName(containerName string) string {
+	name := strings.Replace(strings.TrimPrefix(containerName, "build-step-"), "-", " ", -1)
+	return strings.Title(name)
+}
+
+// matchesBuildLogStep returns true if the step of a Knative build pod matches the --stage and --step filters
+func (o *GetBuildLogsOptions) matchesBuildLogStep(containerName string) bool {
+	if o.Step != "" && o.Step != containerName && o.Step != strings.TrimPrefix(containerName, "build-step-") {
+		return false
+	}
+	return o.Stage == "" || strings.EqualFold(o.Stage, buildStepStageName(containerName))
+}
+
+func (o *GetBuildLogsOptions) noMatchingBuildLogStepError(steps []string) error {
+	return fmt.Errorf("No steps of the build match the stage '%s' and step '%s'. The steps are: %s", o.Stage, o.Step, strings.Join(steps, ", "))
+}
+
+// getBuildPodLogs writes the logs of the steps of the Knative build pod which match the filters. The logs of steps
+// which are still running are tailed if --tail is enabled
+func (o *GetBuildLogsOptions) getBuildPodLogs(kubeClient kubernetes.Interface, ns string, pod *corev1.Pod) error {
+	all := []string{}
+	steps := []string{}
+	for _, c := range pod.Spec.InitContainers {
+		all = append(all, c.Name)
+		if o.matchesBuildLogStep(c.Name) {
+			steps = append(steps, c.Name)
+		}
+	}
+	if len(steps) == 0 {
+		return o.noMatchingBuildLogStepError(all)
+	}
+	for _, step := range steps {
+		state, err := o.waitForBuildStep(kubeClient, ns, pod.Name, step)
+		if err != nil {
+			return err
+		}
+		if !isContainerStarted(state) {
+			log.Infof("Step %s of pod %s has not run\n", util.ColorInfo(step), pod.Name)
+			continue
+		}
+		fmt.Fprintf(o.Out, "%s%s\n", buildLogStepPrefix, step)
+		if isContainerCompleted(state) || !o.Tail {
+			data, err := containerLog(kubeClient, ns, pod.Name, step)
+			if err != nil {
+				return err
+			}
+			o.Out.Write(data)
+			continue
+		}
+		err = o.tailLogs(ns, pod.Name, step)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// waitForBuildStep returns the state of the step of the build pod waiting for the step to start if --tail is enabled
+// and the pod is still running
+func (o *GetBuildLogsOptions) waitForBuildStep(kubeClient kubernetes.Interface, ns string, podName string, step string) (*corev1.ContainerState, error) {
+	for {
+		pod, err := kubeClient.CoreV1().Pods(ns).Get(podName, metav1.GetOptions{})
+		if err != nil {
+			return nil, err
+		}
+		var state *corev1.ContainerState
+		for _, s := range pod.Status.InitContainerStatuses {
+			if s.Name == step {
+				copy := s.State
+				state = &copy
+			}
+		}
+		phase := pod.Status.Phase
+		if isContainerStarted(state) || !o.Tail || phase == corev1.PodSucceeded || phase == corev1.PodFailed {
+			return state, nil
+		}
+		time.Sleep(time.Second)
+	}
+}
+
+// isArchivedBuildLog returns true if the build logs URL of a pipeline activity is a log archived to a bucket
+func isArchivedBuildLog(logsURL string) bool {
+	u, err := url.Parse(logsURL)
+	if err != nil {
+		return false
+	}
+	return u.Scheme == "s3" || u.Scheme == "gs" || u.Scheme == "file"
+}
+
+// getArchivedBuildLog writes the logs of the steps of an archived build log which match the filters
+func (o *GetBuildLogsOptions) getArchivedBuildLog(logsURL string) error {
+	log.Infof("Getting the archived log %s\n", util.ColorInfo(logsURL))
+	data, err := buckets.ReadFile(logsURL)
+	if err != nil {
+		return err
+	}
+	all := []string{}
+	found := false
+	for _, step := range splitArchivedBuildLog(data) {
+		all = append(all, step.Name)
+		if o.matchesBuildLogStep(step.Name) {
+			found = true
+			fmt.Fprintf(o.Out, "%s%s\n", buildLogStepPrefix, step.Name)
+			o.Out.Write(step.Log)
+		}
+	}
+	if !found {
+		return o.noMatchingBuildLogStepError(all)
+	}
+	return nil
+}
+
+// splitArchivedBuildLog splits an archived build log into the logs of its steps
+func splitArchivedBuildLog(data []byte) []buildLogStep {
+	answer := []buildLogStep{}
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	scanner.Buffer(make([]byte, 64*1024), 16*1024*1024)
+	for scanner.Scan() {
+		line := scanner.Text()
+		if strings.HasPrefix(line, buildLogStepPrefix) {
+			answer = append(answer, buildLogStep{Name: strings.TrimPrefix(line, buildLogStepPrefix)})
+			continue
+		}
+		if len(answer) > 0 {
+			step := &answer[len(answer)-1]
+			step.Log = append(append(step.Log, line...), '\n')
+		}
+	}
+	return answer
+}
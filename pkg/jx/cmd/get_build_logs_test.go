@@ -0,0 +1,84 @@
+package cmd_test
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/jenkins-x/jx/pkg/apis/jenkins.io/v1"
+	"github.com/jenkins-x/jx/pkg/gits"
+	"github.com/jenkins-x/jx/pkg/helm"
+	"github.com/jenkins-x/jx/pkg/jx/cmd"
+	"github.com/jenkins-x/jx/pkg/kube"
+	"github.com/stretchr/testify/assert"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+)
+
+func TestGetBuildLogsArchived(t *testing.T) {
+	t.Parallel()
+	testDir, err := ioutil.TempDir("", "test-get-build-logs-archived")
+	assert.NoError(t, err)
+	defer os.RemoveAll(testDir)
+
+	logFile := filepath.Join(testDir, "1.log")
+	archivedLog := `==> step: build-step-git-source
+cloning myorg/myapp
+==> step: build-step-build
+mvn deploy
+BUILD SUCCESS
+==> step: build-step-test
+mvn test
+`
+	err = ioutil.WriteFile(logFile, []byte(archivedLog), 0644)
+	assert.NoError(t, err)
+
+	devEnv := kube.NewPermanentEnvironment("dev")
+	devEnv.Spec.Namespace = "jx"
+	devEnv.Spec.Kind = v1.EnvironmentKindTypeDevelopment
+	devEnv.Spec.WebHookEngine = v1.WebHookEngineProw
+	activity := &v1.PipelineActivity{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "myorg-myapp-master-1",
+			Namespace: "jx",
+		},
+		Spec: v1.PipelineActivitySpec{
+			Pipeline:     "myorg/myapp/master",
+			Build:        "1",
+			Status:       v1.ActivityStatusTypeSucceeded,
+			BuildLogsURL: "file://" + filepath.ToSlash(logFile),
+		},
+	}
+
+	getLog := func(stage string, step string) (string, error) {
+		out, err := ioutil.TempFile(testDir, "out")
+		assert.NoError(t, err)
+		defer out.Close()
+
+		o := &cmd.GetBuildLogsOptions{}
+		cmd.ConfigureTestOptionsWithResources(&o.CommonOptions, nil, []runtime.Object{devEnv, activity}, gits.NewGitCLI(), helm.NewHelmCLI("helm", helm.V2, testDir, true))
+		o.Out = out
+		o.Args = []string{"myorg/myapp/master"}
+		o.Stage = stage
+		o.Step = step
+		err = o.Run()
+		data, _ := ioutil.ReadFile(out.Name())
+		return string(data), err
+	}
+
+	text, err := getLog("", "")
+	assert.NoError(t, err)
+	assert.Equal(t, archivedLog, text)
+
+	text, err = getLog("build", "")
+	assert.NoError(t, err)
+	assert.Equal(t, "==> step: build-step-build\nmvn deploy\nBUILD SUCCESS\n", text)
+
+	text, err = getLog("", "test")
+	assert.NoError(t, err)
+	assert.Equal(t, "==> step: build-step-test\nmvn test\n", text)
+
+	_, err = getLog("", "deploy")
+	assert.Error(t, err)
+}
@@ -60,6 +60,7 @@ func NewCmdGetPipeline(f Factory, in terminal.FileReader, out terminal.FileWrite
 	}
 
 	options.addGetFlags(cmd)
+	cmd.AddCommand(NewCmdGetPipelineStats(f, in, out, errOut))
 	return cmd
 }
 
@@ -0,0 +1,109 @@
+package cmd
+
+import (
+	"io"
+	"strings"
+
+	"github.com/jenkins-x/jx/pkg/jx/cmd/templates"
+	"github.com/jenkins-x/jx/pkg/kube"
+	"github.com/spf13/cobra"
+	"gopkg.in/AlecAivazis/survey.v1/terminal"
+)
+
+// GetPipelineAuditOptions the command line options
+type GetPipelineAuditOptions struct {
+	GetOptions
+
+	Pipeline string
+}
+
+var (
+	getPipelineAuditLong = templates.LongDesc(`
+		Display the pipeline audit log which records who generated or applied the resources of each pipeline, when,
+		and from which source and build pack commits along with a hash of the generated resources.
+`)
+
+	getPipelineAuditExample = templates.Examples(`
+		# List the pipeline audit log
+		jx get pipeline-audit
+
+		# List the pipeline audit log of a single pipeline
+		jx get pipeline-audit -p myapp
+
+		# Output the pipeline audit log as YAML
+		jx get pipeline-audit -o yaml
+	`)
+)
+
+// NewCmdGetPipelineAudit creates the command
+func NewCmdGetPipelineAudit(f Factory, in terminal.FileReader, out terminal.FileWriter, errOut io.Writer) *cobra.Command {
+	options := &GetPipelineAuditOptions{
+		GetOptions: GetOptions{
+			CommonOptions: CommonOptions{
+				Factory: f,
+				In:      in,
+				Out:     out,
+				Err:     errOut,
+			},
+		},
+	}
+
+	cmd := &cobra.Command{
+		Use:     "pipeline-audit",
+		Short:   "Display the pipeline audit log",
+		Long:    getPipelineAuditLong,
+		Example: getPipelineAuditExample,
+		Aliases: []string{"pipeline-audits", "audit"},
+		Run: func(cmd *cobra.Command, args []string) {
+			options.Cmd = cmd
+			options.Args = args
+			err := options.Run()
+			CheckErr(err)
+		},
+	}
+
+	options.addGetFlags(cmd)
+	cmd.Flags().StringVarP(&options.Pipeline, "pipeline", "p", "", "The name of the pipeline to display the audit log of")
+	return cmd
+}
+
+// Run implements this command
+func (o *GetPipelineAuditOptions) Run() error {
+	kubeClient, ns, err := o.KubeClientAndDevNamespace()
+	if err != nil {
+		return err
+	}
+	records, err := kube.LoadPipelineAuditRecords(kubeClient, ns, o.Pipeline)
+	if err != nil {
+		return err
+	}
+	if o.Output != "" {
+		return o.renderResult(records, o.Output)
+	}
+	if len(records) == 0 {
+		return outputEmptyListWarning(o.Out)
+	}
+
+	table := o.CreateTable()
+	table.AddRow("TIME", "PIPELINE", "KIND", "ACTION", "CLUSTER", "USER", "SOURCE", "BUILD PACK", "OUTPUT")
+	for _, r := range records {
+		source := r.SourceURL
+		if r.SourceSHA != "" {
+			source += "@" + shortSHA(r.SourceSHA)
+		}
+		buildPack := r.BuildPack
+		if r.BuildPackSHA != "" {
+			buildPack += "@" + shortSHA(r.BuildPackSHA)
+		}
+		table.AddRow(r.Timestamp.Format("2006-01-02 15:04:05"), r.Pipeline, r.Kind, r.Action, r.Cluster, r.User, strings.TrimSpace(source), buildPack, shortSHA(r.OutputHash))
+	}
+	table.Render()
+	return nil
+}
+
+func shortSHA(sha string) string {
+	if len(sha) > 8 {
+		return sha[:8]
+	}
+	return sha
+}
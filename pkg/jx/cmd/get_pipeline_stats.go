@@ -0,0 +1,165 @@
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/jenkins-x/jx/pkg/history"
+	"github.com/jenkins-x/jx/pkg/jx/cmd/templates"
+	"github.com/jenkins-x/jx/pkg/util"
+	"github.com/spf13/cobra"
+	"gopkg.in/AlecAivazis/survey.v1/terminal"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// GetPipelineStatsOptions the command line options
+type GetPipelineStatsOptions struct {
+	GetOptions
+
+	Since            string
+	HistoryBucketURL string
+}
+
+var (
+	getPipelineStatsLong = templates.LongDesc(`
+		Display the success rate, the median and 95th percentile durations and the flakiest stages of the pipelines of each repository.
+
+		The statistics are calculated from the completed pipeline activities and the history of the pipelines which 'jx gc activities' compacts into the history bucket of the team before it deletes old activities.
+
+`)
+
+	getPipelineStatsExample = templates.Examples(`
+		# Display the statistics of the pipelines of the last 30 days
+		jx get pipeline stats --since 30d
+
+		# Display the statistics of the pipelines of a repository
+		jx get pipeline stats myorg/myapp
+	`)
+)
+
+// NewCmdGetPipelineStats creates the command
+func NewCmdGetPipelineStats(f Factory, in terminal.FileReader, out terminal.FileWriter, errOut io.Writer) *cobra.Command {
+	options := &GetPipelineStatsOptions{
+		GetOptions: GetOptions{
+			CommonOptions: CommonOptions{
+				Factory: f,
+				In:      in,
+				Out:     out,
+				Err:     errOut,
+			},
+		},
+	}
+
+	cmd := &cobra.Command{
+		Use:     "stats [repository...]",
+		Short:   "Display the statistics of the pipelines of each repository",
+		Long:    getPipelineStatsLong,
+		Example: getPipelineStatsExample,
+		Aliases: []string{"stat", "statistics"},
+		Run: func(cmd *cobra.Command, args []string) {
+			options.Cmd = cmd
+			options.Args = args
+			err := options.Run()
+			CheckErr(err)
+		},
+	}
+	options.addGetFlags(cmd)
+	cmd.Flags().StringVarP(&options.Since, "since", "s", "30d", "Only includes the pipelines which started within this duration such as 12h or 30d")
+	cmd.Flags().StringVarP(&options.HistoryBucketURL, "history-bucket-url", "", "", "The URL of the bucket containing the history of the pipelines. Defaults to the historyBucketUrl of the build defaults of the team")
+	return cmd
+}
+
+// Run implements this command
+func (o *GetPipelineStatsOptions) Run() error {
+	since, err := parseSinceDuration(o.Since)
+	if err != nil {
+		return util.InvalidOptionError("since", o.Since, err)
+	}
+	jxClient, ns, err := o.JXClientAndDevNamespace()
+	if err != nil {
+		return err
+	}
+	if o.HistoryBucketURL == "" {
+		teamSettings, err := o.TeamSettings()
+		if err != nil {
+			return err
+		}
+		o.HistoryBucketURL = teamSettings.BuildDefaults.HistoryBucketURL
+	}
+
+	runs := []history.PipelineRun{}
+	if o.HistoryBucketURL != "" {
+		runs, err = history.Load(o.HistoryBucketURL)
+		if err != nil {
+			return err
+		}
+	}
+	activities, err := jxClient.JenkinsV1().PipelineActivities(ns).List(metav1.ListOptions{})
+	if err != nil {
+		return err
+	}
+	liveRuns := []history.PipelineRun{}
+	for i := range activities.Items {
+		run := history.NewPipelineRun(&activities.Items[i])
+		if run != nil {
+			liveRuns = append(liveRuns, *run)
+		}
+	}
+	runs = history.Merge(runs, liveRuns)
+
+	stats := []history.RepositoryStats{}
+	for _, s := range history.Stats(runs, time.Now().Add(-since)) {
+		if o.matchesRepository(s.Repository) {
+			stats = append(stats, s)
+		}
+	}
+	if len(stats) == 0 {
+		return outputEmptyListWarning(o.Out)
+	}
+	if o.Output != "" {
+		return o.renderResult(stats, o.Output)
+	}
+
+	table := o.CreateTable()
+	table.AddRow("REPOSITORY", "RUNS", "SUCCESS", "P50", "P95", "FLAKIEST STAGES")
+	for _, s := range stats {
+		flakiest := []string{}
+		for _, f := range s.FlakiestStages {
+			flakiest = append(flakiest, fmt.Sprintf("%s (%.0f%%)", f.Name, f.Flakiness()))
+		}
+		table.AddRow(s.Repository, strconv.Itoa(s.Runs), fmt.Sprintf("%.1f%%", s.SuccessRate()), formatStatsDuration(s.P50), formatStatsDuration(s.P95), strings.Join(flakiest, ", "))
+	}
+	table.Render()
+	return nil
+}
+
+func (o *GetPipelineStatsOptions) matchesRepository(repository string) bool {
+	if len(o.Args) == 0 {
+		return true
+	}
+	for _, arg := range o.Args {
+		if strings.Contains(repository, arg) {
+			return true
+		}
+	}
+	return false
+}
+
+func formatStatsDuration(d time.Duration) string {
+	return d.Round(time.Second).String()
+}
+
+// parseSinceDuration parses a duration such as '12h' or '30d' which may be in days
+func parseSinceDuration(text string) (time.Duration, error) {
+	if strings.HasSuffix(text, "d") {
+		days, err := strconv.Atoi(strings.TrimSuffix(text, "d"))
+		if err != nil || days < 0 {
+			return 0, fmt.Errorf("invalid number of days %s", text)
+		}
+		return time.Duration(days) * 24 * time.Hour, nil
+	}
+	return time.ParseDuration(text)
+}
@@ -0,0 +1,183 @@
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+
+	"github.com/jenkins-x/jx/pkg/jx/cmd/templates"
+	"github.com/jenkins-x/jx/pkg/kube"
+	"github.com/spf13/cobra"
+	"gopkg.in/AlecAivazis/survey.v1/terminal"
+	corev1 "k8s.io/api/core/v1"
+)
+
+// GetPodTemplateOptions containers the CLI options
+type GetPodTemplateOptions struct {
+	GetOptions
+}
+
+const (
+	podTemplate = "podtemplate"
+)
+
+var (
+	podTemplateAliases = []string{
+		"podtemplates", "pod template", "pod templates",
+	}
+
+	getPodTemplateLong = templates.LongDesc(`
+		Display the pod templates used to run the containers of pipelines for the current team.
+
+		If a pod template name is specified then the containers, images and environment variables of that pod template are displayed.
+`)
+
+	getPodTemplateExample = templates.Examples(`
+		# List the pod templates for the current team
+		jx get podtemplates
+
+		# View the containers, images and environment variables of a pod template
+		jx get podtemplate maven
+
+		# Export a pod template to a YAML file
+		jx get podtemplate maven -o yaml > maven.yaml
+	`)
+)
+
+// NewCmdGetPodTemplate creates the new command for: jx get podtemplate
+func NewCmdGetPodTemplate(f Factory, in terminal.FileReader, out terminal.FileWriter, errOut io.Writer) *cobra.Command {
+	options := &GetPodTemplateOptions{
+		GetOptions: GetOptions{
+			CommonOptions: CommonOptions{
+				Factory: f,
+				In:      in,
+				Out:     out,
+				Err:     errOut,
+			},
+		},
+	}
+	cmd := &cobra.Command{
+		Use:     podTemplate + " [name]",
+		Short:   "Display the pod templates used to run the containers of pipelines for the current team",
+		Aliases: podTemplateAliases,
+		Long:    getPodTemplateLong,
+		Example: getPodTemplateExample,
+		Run: func(cmd *cobra.Command, args []string) {
+			options.Cmd = cmd
+			options.Args = args
+			err := options.Run()
+			CheckErr(err)
+		},
+	}
+
+	options.addGetFlags(cmd)
+	return cmd
+}
+
+// Run implements this command
+func (o *GetPodTemplateOptions) Run() error {
+	kubeClient, ns, err := o.KubeClientAndDevNamespace()
+	if err != nil {
+		return err
+	}
+	if len(o.Args) > 0 {
+		name := o.Args[0]
+		pod, err := kube.LoadPodTemplate(kubeClient, ns, name)
+		if err != nil {
+			return err
+		}
+		if o.Output != "" {
+			return o.renderResult(pod, o.Output)
+		}
+		return o.renderPodTemplate(name, pod)
+	}
+
+	podTemplates, err := kube.LoadPodTemplates(kubeClient, ns)
+	if err != nil {
+		return err
+	}
+	if o.Output != "" {
+		return o.renderResult(podTemplates, o.Output)
+	}
+	if len(podTemplates) == 0 {
+		return outputEmptyListWarning(o.Out)
+	}
+	names := []string{}
+	for name := range podTemplates {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	table := o.CreateTable()
+	table.AddRow("NAME", "CONTAINERS", "IMAGES")
+	for _, name := range names {
+		containerNames := []string{}
+		images := []string{}
+		for _, c := range podTemplates[name].Spec.Containers {
+			containerNames = append(containerNames, c.Name)
+			images = append(images, c.Image)
+		}
+		table.AddRow(name, strings.Join(containerNames, ", "), strings.Join(images, ", "))
+	}
+	table.Render()
+	return nil
+}
+
+func (o *GetPodTemplateOptions) renderPodTemplate(name string, pod *corev1.Pod) error {
+	table := o.CreateTable()
+	table.AddRow("CONTAINER", "IMAGE", "ENV", "VALUE")
+	for _, c := range pod.Spec.Containers {
+		table.AddRow(c.Name, c.Image, "", "")
+		for _, env := range c.Env {
+			table.AddRow("", "", env.Name, envVarValueText(env))
+		}
+	}
+	table.Render()
+
+	if len(pod.Spec.Volumes) > 0 {
+		fmt.Fprintln(o.Out)
+		table = o.CreateTable()
+		table.AddRow("VOLUME", "SOURCE")
+		for _, v := range pod.Spec.Volumes {
+			table.AddRow(v.Name, volumeSourceText(v))
+		}
+		table.Render()
+	}
+	return nil
+}
+
+// envVarValueText returns a textual description of the value of an environment variable
+func envVarValueText(env corev1.EnvVar) string {
+	from := env.ValueFrom
+	if from != nil {
+		if from.ConfigMapKeyRef != nil {
+			return fmt.Sprintf("configmap %s key %s", from.ConfigMapKeyRef.Name, from.ConfigMapKeyRef.Key)
+		}
+		if from.SecretKeyRef != nil {
+			return fmt.Sprintf("secret %s key %s", from.SecretKeyRef.Name, from.SecretKeyRef.Key)
+		}
+		if from.FieldRef != nil {
+			return fmt.Sprintf("field %s", from.FieldRef.FieldPath)
+		}
+	}
+	return env.Value
+}
+
+// volumeSourceText returns a textual description of the source of a volume
+func volumeSourceText(v corev1.Volume) string {
+	switch {
+	case v.Secret != nil:
+		return "secret " + v.Secret.SecretName
+	case v.ConfigMap != nil:
+		return "configmap " + v.ConfigMap.Name
+	case v.HostPath != nil:
+		return "hostPath " + v.HostPath.Path
+	case v.EmptyDir != nil:
+		return "emptyDir"
+	case v.PersistentVolumeClaim != nil:
+		return "pvc " + v.PersistentVolumeClaim.ClaimName
+	default:
+		return ""
+	}
+}
@@ -0,0 +1,93 @@
+package cmd
+
+import (
+	"io"
+	"sort"
+	"strings"
+
+	"github.com/jenkins-x/jx/pkg/jx/cmd/templates"
+	"github.com/spf13/cobra"
+	"gopkg.in/AlecAivazis/survey.v1/terminal"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// GetReposOptions the command line options
+type GetReposOptions struct {
+	GetOptions
+}
+
+var (
+	getReposLong = templates.LongDesc(`
+		Display the inventory of CI enabled git repositories registered as SourceRepository resources.
+`)
+
+	getReposExample = templates.Examples(`
+		# List the CI enabled git repositories
+		jx get repos
+
+		# Output the CI enabled git repositories as YAML
+		jx get repos -o yaml
+	`)
+)
+
+// NewCmdGetRepos creates the command
+func NewCmdGetRepos(f Factory, in terminal.FileReader, out terminal.FileWriter, errOut io.Writer) *cobra.Command {
+	options := &GetReposOptions{
+		GetOptions: GetOptions{
+			CommonOptions: CommonOptions{
+				Factory: f,
+				In:      in,
+				Out:     out,
+				Err:     errOut,
+			},
+		},
+	}
+
+	cmd := &cobra.Command{
+		Use:     "repos",
+		Short:   "Display the CI enabled git repositories",
+		Long:    getReposLong,
+		Example: getReposExample,
+		Aliases: []string{"repo", "sourcerepositories", "sourcerepository"},
+		Run: func(cmd *cobra.Command, args []string) {
+			options.Cmd = cmd
+			options.Args = args
+			err := options.Run()
+			CheckErr(err)
+		},
+	}
+
+	options.addGetFlags(cmd)
+	return cmd
+}
+
+// Run implements this command
+func (o *GetReposOptions) Run() error {
+	jxClient, ns, err := o.JXClientAndDevNamespace()
+	if err != nil {
+		return err
+	}
+	list, err := jxClient.JenkinsV1().SourceRepositories(ns).List(metav1.ListOptions{})
+	if err != nil {
+		return err
+	}
+	repos := list.Items
+	sort.Slice(repos, func(i, j int) bool {
+		return repos[i].Name < repos[j].Name
+	})
+	if o.Output != "" {
+		return o.renderResult(repos, o.Output)
+	}
+	if len(repos) == 0 {
+		return outputEmptyListWarning(o.Out)
+	}
+
+	table := o.CreateTable()
+	table.AddRow("ORG", "REPO", "PROVIDER", "BUILD PACK", "KINDS", "URL")
+	for _, r := range repos {
+		s := r.Spec
+		table.AddRow(s.Org, s.Repo, s.Provider, s.BuildPack, strings.Join(s.PipelineKinds, ", "), s.URL)
+	}
+	table.Render()
+	return nil
+}
@@ -7,6 +7,8 @@ import (
 	"strings"
 
 	"github.com/golang/glog"
+	"github.com/jenkins-x/jx/pkg/util"
+	"github.com/pkg/errors"
 	"github.com/spf13/cobra"
 )
 
@@ -128,7 +130,11 @@ func checkErr(prefix string, err error, handleErr func(string, int)) {
 					msg = fmt.Sprintf("error: %s", msg)
 				}
 			}
-			handleErr(msg, DefaultErrorExitCode)
+			code := util.ExitCode(err)
+			if _, ok := errors.Cause(err).(*url.Error); ok && code == DefaultErrorExitCode {
+				code = util.ExitCodeUnreachable
+			}
+			handleErr(msg, code)
 		}
 	}
 }
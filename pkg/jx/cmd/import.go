@@ -92,6 +92,7 @@ type ImportOptions struct {
 	ListDraftPacks          bool
 	DraftPack               string
 	DockerRegistryOrg       string
+	Tekton                  bool
 
 	DisableDotGitSearch   bool
 	InitialisedGit        bool
@@ -137,6 +138,12 @@ var (
 
         # Import all repositories from a GitHub organisation which contain the text foo
 		jx import --github --org myname --all --filter foo 
+
+		# Import the current folder generating its builds, Prow configuration and webhook instead of a Jenkins job
+		jx import --tekton
+
+		# Show what importing the current folder with generated builds would create
+		jx import --tekton --dry-run
 		`)
 )
 
@@ -190,7 +197,9 @@ func (options *ImportOptions) addImportFlags(cmd *cobra.Command, createProject b
 	cmd.Flags().StringVarP(&options.BranchPattern, "branches", "", "", "The branch pattern for branches to trigger CI/CD pipelines on")
 	cmd.Flags().BoolVarP(&options.ListDraftPacks, "list-packs", "", false, "list available draft packs")
 	cmd.Flags().StringVarP(&options.DraftPack, "pack", "", "", "The name of the pack to use")
+	addValueCompletion(cmd, "pack", completionValuesPacks)
 	cmd.Flags().StringVarP(&options.DockerRegistryOrg, "docker-registry-org", "", "", "The name of the docker registry organisation to use. If not specified then the Git provider organisation will be used")
+	cmd.Flags().BoolVarP(&options.Tekton, "tekton", "", false, "Generates the jenkins-x.yml defaults, builds, Prow configuration and webhook of the project instead of creating a Jenkins job")
 	cmd.Flags().StringVarP(&options.ExternalJenkinsBaseURL, "external-jenkins-url", "", "", "The jenkins url that an external git provider needs to use")
 
 	options.addCommonFlags(cmd)
@@ -241,7 +250,7 @@ func (options *ImportOptions) Run() error {
 			return err
 		}
 
-		if !isProw {
+		if !isProw && !options.Tekton {
 			options.Jenkins, err = options.JenkinsClient()
 			if err != nil {
 				return err
@@ -390,6 +399,17 @@ func (options *ImportOptions) Run() error {
 		return err
 	}
 
+	if options.Tekton {
+		_, err = options.CreateTektonProjectConfig()
+		if err != nil {
+			return err
+		}
+		err = options.commitTektonProjectConfig()
+		if err != nil {
+			return err
+		}
+	}
+
 	if options.RepoURL == "" {
 		if !options.DryRun {
 			err = options.CreateNewRemoteRepository()
@@ -407,10 +427,17 @@ func (options *ImportOptions) Run() error {
 	}
 
 	if options.DryRun {
+		if options.Tekton {
+			return options.dryRunTekton()
+		}
 		log.Infoln("dry-run so skipping import to Jenkins X")
 		return nil
 	}
 
+	if options.Tekton {
+		return options.doImportTekton()
+	}
+
 	if !isProw {
 		err = options.checkChartmuseumCredentialExists()
 		if err != nil {
@@ -1066,7 +1093,7 @@ func (options *ImportOptions) ensureDockerRepositoryExists() error {
 	if cm.Data != nil {
 		dockerRegistry := cm.Data["docker.registry"]
 		if dockerRegistry != "" {
-			if strings.HasSuffix(dockerRegistry, ".amazonaws.com") && strings.Index(dockerRegistry, ".ecr.") > 0 {
+			if isECRRegistry(dockerRegistry) {
 				return amazon.LazyCreateRegistry(orgName, appName)
 			}
 		}
@@ -0,0 +1,215 @@
+package cmd
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+
+	"github.com/jenkins-x/jx/pkg/config"
+	"github.com/jenkins-x/jx/pkg/gits"
+	"github.com/jenkins-x/jx/pkg/kube"
+	"github.com/jenkins-x/jx/pkg/log"
+	"github.com/jenkins-x/jx/pkg/prow"
+	"github.com/jenkins-x/jx/pkg/util"
+	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
+)
+
+// defaultTektonBuilds returns the builds added to the jenkins-x.yml of an imported project which has none
+func defaultTektonBuilds() []*config.BranchBuild {
+	return []*config.BranchBuild{
+		{
+			Kind: config.BuildKindPullRequest,
+			Build: config.Build{
+				Steps: []corev1.Container{
+					{
+						Name: "build",
+						Args: []string{"skaffold", "build", "-f", "skaffold.yaml"},
+					},
+				},
+			},
+		},
+		{
+			Kind: config.BuildKindRelease,
+			Build: config.Build{
+				Steps: []corev1.Container{
+					{
+						Name: "build",
+						Args: []string{"skaffold", "build", "-f", "skaffold.yaml"},
+					},
+					{
+						Name: "release-chart",
+						Args: []string{"jx", "step", "helm", "release"},
+					},
+				},
+			},
+		},
+	}
+}
+
+// CreateTektonProjectConfig defaults the build pack and builds of the jenkins-x.yml file of the project so that
+// its builds can be generated. Does nothing if the file already defines them
+func (options *ImportOptions) CreateTektonProjectConfig() (*config.ProjectConfig, error) {
+	projectConfig, fileName, err := config.LoadProjectConfig(options.Dir)
+	if err != nil {
+		return projectConfig, err
+	}
+	modified := false
+	if projectConfig.BuildPack == "" && options.DraftPack != "" {
+		projectConfig.BuildPack = options.DraftPack
+		modified = true
+	}
+	if len(projectConfig.Builds) == 0 {
+		projectConfig.Builds = defaultTektonBuilds()
+		modified = true
+	}
+	if !modified {
+		return projectConfig, nil
+	}
+	err = projectConfig.SaveConfig(fileName)
+	if err != nil {
+		return projectConfig, errors.Wrapf(err, "failed to save %s", fileName)
+	}
+	log.Infof("Defaulted the builds in %s\n", util.ColorInfo(fileName))
+	return projectConfig, nil
+}
+
+// commitTektonProjectConfig commits the defaulted jenkins-x.yml file so the builds can be generated from the
+// cloned repository
+func (options *ImportOptions) commitTektonProjectConfig() error {
+	err := options.Git().Add(options.Dir, config.ProjectConfigFileName)
+	if err != nil {
+		return err
+	}
+	return options.Git().CommitIfChanges(options.Dir, "Add "+config.ProjectConfigFileName)
+}
+
+// generateTektonBuilds generates the builds of the project into the given directory returning the generated files
+func (options *ImportOptions) generateTektonBuilds(outDir string, registerRepo bool) ([]string, error) {
+	so := &StepCreateBuildOptions{
+		StepOptions: StepOptions{
+			CommonOptions: options.CommonOptions,
+		},
+		Dir:          options.Dir,
+		OutputDir:    outDir,
+		NoAudit:      options.DryRun,
+		RegisterRepo: registerRepo,
+	}
+	err := so.Run()
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to generate the builds")
+	}
+	return filepath.Glob(filepath.Join(outDir, "build-*.yml"))
+}
+
+// dryRunTekton logs the resources that importing the project using generated builds would create
+func (options *ImportOptions) dryRunTekton() error {
+	gitInfo, err := gits.ParseGitURL(options.RepoURL)
+	if err != nil {
+		return err
+	}
+	repo := gitInfo.Organisation + "/" + gitInfo.Name
+	log.Infof("dry-run so the import would:\n")
+	log.Infof("* create a webhook on %s\n", util.ColorInfo(options.RepoURL))
+	log.Infof("* add %s to the Prow configuration\n", util.ColorInfo(repo))
+	log.Infof("* create the SourceRepository %s\n", util.ColorInfo(kube.ToValidName(gitInfo.Organisation+"-"+gitInfo.Name)))
+
+	tmpDir, err := ioutil.TempDir("", "jx-import-builds-")
+	if err != nil {
+		return err
+	}
+	defer os.RemoveAll(tmpDir)
+	fileNames, err := options.generateTektonBuilds(tmpDir, false)
+	if err != nil {
+		log.Warnf("Could not generate the builds: %s\n", err)
+		return nil
+	}
+	for _, fileName := range fileNames {
+		data, err := ioutil.ReadFile(fileName)
+		if err != nil {
+			return err
+		}
+		log.Infof("* apply the build %s:\n\n%s\n", util.ColorInfo(filepath.Base(fileName)), string(data))
+	}
+	return nil
+}
+
+// doImportTekton imports the project creating its webhook, Prow configuration and SourceRepository then applies
+// its generated builds rather than creating a Jenkins job
+func (options *ImportOptions) doImportTekton() error {
+	gitURL := options.RepoURL
+	gitProvider := options.GitProvider
+	if gitProvider == nil {
+		p, err := options.gitProviderForURL(gitURL, "user name to register webhook")
+		if err != nil {
+			return err
+		}
+		gitProvider = p
+	}
+	err := options.ensureDockerRepositoryExists()
+	if err != nil {
+		return err
+	}
+	err = options.createWebhookProw(gitURL, gitProvider)
+	if err != nil {
+		return err
+	}
+	gitInfo, err := gits.ParseGitURL(gitURL)
+	if err != nil {
+		return err
+	}
+	err = prow.AddApplication(options.KubeClientCached, []string{gitInfo.Organisation + "/" + gitInfo.Name}, options.currentNamespace, options.DraftPack)
+	if err != nil {
+		return err
+	}
+
+	buildDir, generated, err := options.tektonBuildDir()
+	if generated {
+		defer os.RemoveAll(buildDir)
+	}
+	if err != nil {
+		return err
+	}
+	ao := &StepApplyBuildOptions{
+		StepOptions: StepOptions{
+			CommonOptions: options.CommonOptions,
+		},
+		Dir: buildDir,
+	}
+	err = ao.Run()
+	if err != nil {
+		return errors.Wrap(err, "failed to apply the builds")
+	}
+	options.logImportedProject(false, gitInfo)
+	return nil
+}
+
+// tektonBuildDir returns the directory of the committed pre-generated builds of the project if it has any, otherwise
+// generates the builds into a temporary directory and returns true. The SourceRepository of the project is
+// registered either way
+func (options *ImportOptions) tektonBuildDir() (string, bool, error) {
+	projectConfig, _, err := config.LoadProjectConfig(options.Dir)
+	if err != nil {
+		return "", false, err
+	}
+	if projectConfig.PipelinesDir == "" {
+		tmpDir, err := ioutil.TempDir("", "jx-import-builds-")
+		if err != nil {
+			return "", false, err
+		}
+		_, err = options.generateTektonBuilds(tmpDir, true)
+		return tmpDir, true, err
+	}
+	kinds := []string{}
+	for _, b := range projectConfig.Builds {
+		kinds = append(kinds, b.Kind)
+	}
+	so := &StepCreateBuildOptions{
+		StepOptions: StepOptions{
+			CommonOptions: options.CommonOptions,
+		},
+		Dir: options.Dir,
+	}
+	err = so.registerSourceRepository(projectConfig, kinds)
+	return filepath.Join(options.Dir, projectConfig.PipelinesDir), false, err
+}
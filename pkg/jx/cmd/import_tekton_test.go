@@ -0,0 +1,50 @@
+package cmd_test
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/jenkins-x/jx/pkg/config"
+	"github.com/jenkins-x/jx/pkg/jx/cmd"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCreateTektonProjectConfig(t *testing.T) {
+	t.Parallel()
+	dir, err := ioutil.TempDir("", "test-import-tekton")
+	assert.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	o := &cmd.ImportOptions{
+		Dir:       dir,
+		DraftPack: "go",
+	}
+	pc, err := o.CreateTektonProjectConfig()
+	assert.NoError(t, err)
+	assert.Equal(t, "go", pc.BuildPack)
+	kinds := []string{}
+	for _, b := range pc.Builds {
+		kinds = append(kinds, b.Kind)
+	}
+	assert.Equal(t, []string{config.BuildKindPullRequest, config.BuildKindRelease}, kinds)
+
+	loaded, _, err := config.LoadProjectConfig(dir)
+	assert.NoError(t, err)
+	assert.Equal(t, "go", loaded.BuildPack)
+	if assert.Equal(t, 2, len(loaded.Builds)) {
+		assert.Equal(t, "release-chart", loaded.Builds[1].Build.Steps[1].Name)
+	}
+
+	// an existing configuration is left alone
+	existing := &config.ProjectConfig{
+		BuildPack: "maven",
+		Builds:    []*config.BranchBuild{{Kind: config.BuildKindRelease}},
+	}
+	err = existing.SaveConfig(filepath.Join(dir, config.ProjectConfigFileName))
+	assert.NoError(t, err)
+	pc, err = o.CreateTektonProjectConfig()
+	assert.NoError(t, err)
+	assert.Equal(t, existing, pc)
+}
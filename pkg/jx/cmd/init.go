@@ -415,20 +415,16 @@ func (o *InitOptions) initBuildPacks() (string, error) {
 	packURL := settings.BuildPackURL
 	packRef := settings.BuildPackRef
 
-	u, err := url.Parse(strings.TrimSuffix(packURL, ".git"))
-	if err != nil {
-		return "", fmt.Errorf("Failed to parse build pack URL: %s: %s", packURL, err)
-	}
-
-	draftDir, err := util.DraftDir()
+	dir, err := buildPackDir(packURL)
 	if err != nil {
 		return "", err
 	}
-	dir := filepath.Join(draftDir, "packs", u.Host, u.Path)
 	if err := os.MkdirAll(dir, 0755); err != nil {
 		return "", fmt.Errorf("Could not create %s: %s", dir, err)
 	}
 
+	progress := log.StartProgress(os.Stderr, o.BatchMode, "Cloning the build packs %s", packURL)
+	defer progress.Stop()
 	err = o.Git().CloneOrPull(packURL, dir)
 	if err != nil {
 		return "", err
@@ -439,6 +435,19 @@ func (o *InitOptions) initBuildPacks() (string, error) {
 	return filepath.Join(dir, "packs"), err
 }
 
+// buildPackDir returns the directory the build pack repository is cloned into
+func buildPackDir(packURL string) (string, error) {
+	u, err := url.Parse(strings.TrimSuffix(packURL, ".git"))
+	if err != nil {
+		return "", fmt.Errorf("Failed to parse build pack URL: %s: %s", packURL, err)
+	}
+	draftDir, err := util.DraftDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(draftDir, "packs", u.Host, u.Path), nil
+}
+
 func (o *InitOptions) initIngress() error {
 	surveyOpts := survey.WithStdio(o.In, o.Out, o.Err)
 	client, _, err := o.KubeClient()
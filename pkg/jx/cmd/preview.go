@@ -726,13 +726,19 @@ func (o *PreviewOptions) defaultValues(ns string, warnMissingName bool) error {
 
 	o.PullRequestName = strings.TrimPrefix(o.PullRequest, "PR-")
 
+	// the feature builds name the preview environment after the feature of their branch
+	featureName := ""
+	if !strings.HasPrefix(o.PullRequest, "PR-") {
+		featureName = os.Getenv(config.EnvFeatureName)
+	}
+
 	if o.SourceURL != "" {
 		o.GitInfo, err = gits.ParseGitURL(o.SourceURL)
 		if err != nil {
 			log.Warnf("Could not parse the git URL %s due to %s\n", o.SourceURL, err)
 		} else {
 			o.SourceURL = o.GitInfo.HttpCloneURL()
-			if o.PullRequestURL == "" {
+			if o.PullRequestURL == "" && featureName == "" {
 				if o.PullRequest == "" {
 					if warnMissingName {
 						log.Warnf("No Pull Request name or URL specified nor could one be found via $BRANCH_NAME\n")
@@ -741,6 +747,14 @@ func (o *PreviewOptions) defaultValues(ns string, warnMissingName bool) error {
 					o.PullRequestURL = o.GitInfo.PullRequestURL(o.PullRequestName)
 				}
 			}
+			if featureName != "" {
+				if o.Name == "" {
+					o.Name = o.GitInfo.Organisation + "-" + o.GitInfo.Name + "-" + featureName
+				}
+				if o.Label == "" {
+					o.Label = o.GitInfo.Organisation + "/" + o.GitInfo.Name + " " + featureName
+				}
+			}
 			if o.Name == "" && o.PullRequestName != "" {
 				o.Name = o.GitInfo.Organisation + "-" + o.GitInfo.Name + "-pr-" + o.PullRequestName
 			}
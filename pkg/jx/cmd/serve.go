@@ -0,0 +1,60 @@
+package cmd
+
+import (
+	"io"
+
+	"github.com/spf13/cobra"
+	"gopkg.in/AlecAivazis/survey.v1/terminal"
+
+	"github.com/jenkins-x/jx/pkg/jx/cmd/templates"
+)
+
+// ServeOptions contains the CLI options
+type ServeOptions struct {
+	CommonOptions
+}
+
+var (
+	serveLong = templates.LongDesc(`
+		Runs a service which exposes Jenkins X commands over HTTP
+
+`)
+
+	serveExample = templates.Examples(`
+		# generates the builds of repositories over HTTP
+		jx serve taskgen
+	`)
+)
+
+// NewCmdServe creates the serve command
+func NewCmdServe(f Factory, in terminal.FileReader, out terminal.FileWriter, errOut io.Writer) *cobra.Command {
+	options := &ServeOptions{
+		CommonOptions{
+			Factory: f,
+			In:      in,
+			Out:     out,
+			Err:     errOut,
+		},
+	}
+
+	cmd := &cobra.Command{
+		Use:     "serve [flags]",
+		Short:   "Runs a service which exposes Jenkins X commands over HTTP",
+		Long:    serveLong,
+		Example: serveExample,
+		Run: func(cmd *cobra.Command, args []string) {
+			options.Cmd = cmd
+			options.Args = args
+			err := options.Run()
+			CheckErr(err)
+		},
+	}
+
+	cmd.AddCommand(NewCmdServeTaskgen(f, in, out, errOut))
+	return cmd
+}
+
+// Run implements this command
+func (o *ServeOptions) Run() error {
+	return o.Cmd.Help()
+}
@@ -0,0 +1,279 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/jenkins-x/jx/pkg/jx/cmd/templates"
+	"github.com/jenkins-x/jx/pkg/log"
+	"github.com/jenkins-x/jx/pkg/taskgen"
+	"github.com/jenkins-x/jx/pkg/util"
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+	"google.golang.org/grpc"
+	"gopkg.in/AlecAivazis/survey.v1/terminal"
+	corev1 "k8s.io/api/core/v1"
+)
+
+var (
+	serveTaskgenLong = templates.LongDesc(`
+		Runs a service which generates the builds of git repositories over HTTP so that platforms can generate pipelines centrally without running the CLI.
+
+		The builds of a repository are generated by POSTing a JSON request such as {"url": "https://github.com/myorg/myapp.git", "ref": "master", "kind": "release"} to the path of the service. The response contains the generated resources as YAML documents.
+
+		With --grpc-port the Taskgen gRPC service of the github.com/jenkins-x/jx/pkg/taskgen package is also served so that controllers can generate builds programmatically while the progress of the generation is streamed to them.
+
+		The build packs and pod templates are cached between requests for the --cache-duration.
+
+		The requests are not authenticated so the services listen on localhost unless --address is given, such as an empty address to listen on all interfaces behind a proxy which authenticates the requests. Only the https, ssh and git URLs of repositories are accepted so that requests cannot clone the files of the server.
+`)
+
+	serveTaskgenExample = templates.Examples(`
+		# Serves the generation of builds on port 8080
+		jx serve taskgen
+
+		# Serves the generation of builds on all interfaces behind an authenticating proxy
+		jx serve taskgen --address ""
+
+		# Then generate the release build of a repository
+		curl -X POST -d '{"url": "https://github.com/myorg/myapp.git", "kind": "release"}' http://taskgen.jx.example.com/generate
+	`)
+)
+
+// taskgenURLSchemes the schemes of the URLs of the repositories whose builds are generated
+var taskgenURLSchemes = []string{"https", "ssh", "git"}
+
+// scpLikeGitURL matches the scp-like syntax of ssh git URLs such as git@github.com:myorg/myapp.git
+var scpLikeGitURL = regexp.MustCompile(`^[\w.-]+@[\w.-]+:`)
+
+// ServeTaskgenOptions are the flags for the commands
+type ServeTaskgenOptions struct {
+	ServeOptions
+
+	Address       string
+	Port          int
+	GRPCPort      int
+	Path          string
+	CacheDuration time.Duration
+
+	lock         sync.Mutex
+	cacheExpires time.Time
+	packsDir     string
+	podTemplates map[string]*corev1.Pod
+}
+
+// NewCmdServeTaskgen creates the command
+func NewCmdServeTaskgen(f Factory, in terminal.FileReader, out terminal.FileWriter, errOut io.Writer) *cobra.Command {
+	options := &ServeTaskgenOptions{
+		ServeOptions: ServeOptions{
+			CommonOptions: CommonOptions{
+				Factory: f,
+				In:      in,
+				Out:     out,
+				Err:     errOut,
+			},
+		},
+	}
+
+	cmd := &cobra.Command{
+		Use:     "taskgen",
+		Short:   "Runs a service which generates the builds of git repositories over HTTP",
+		Long:    serveTaskgenLong,
+		Example: serveTaskgenExample,
+		Run: func(cmd *cobra.Command, args []string) {
+			options.Cmd = cmd
+			options.Args = args
+			err := options.Run()
+			CheckErr(err)
+		},
+	}
+
+	options.addCommonFlags(cmd)
+	cmd.Flags().StringVarP(&options.Address, "address", "", "127.0.0.1", "The address the services listen on. Defaults to localhost as the requests are not authenticated")
+	cmd.Flags().IntVarP(&options.Port, "port", "", 8080, "The port the service listens on")
+	cmd.Flags().IntVarP(&options.GRPCPort, "grpc-port", "", 0, "The port the gRPC service listens on. The gRPC service is disabled if it is 0")
+	cmd.Flags().StringVarP(&options.Path, "path", "", "/generate", "The HTTP path the requests are POSTed to")
+	cmd.Flags().DurationVarP(&options.CacheDuration, "cache-duration", "", 10*time.Minute, "How long the build packs and pod templates are cached for")
+	return cmd
+}
+
+// Run implements this command
+func (o *ServeTaskgenOptions) Run() error {
+	o.BatchMode = true
+	if o.GRPCPort > 0 {
+		listener, err := net.Listen("tcp", net.JoinHostPort(o.Address, strconv.Itoa(o.GRPCPort)))
+		if err != nil {
+			return err
+		}
+		server := grpc.NewServer()
+		taskgen.RegisterTaskgenServer(server, o)
+		go func() {
+			err := server.Serve(listener)
+			if err != nil {
+				log.Errorf("The gRPC service stopped: %s\n", err)
+			}
+		}()
+		log.Infof("Serving the gRPC generation of builds on port %s\n", util.ColorInfo(o.GRPCPort))
+	}
+	mux := http.NewServeMux()
+	mux.Handle(o.Path, o)
+	log.Infof("Serving the generation of builds at %s on port %s\n", util.ColorInfo(o.Path), util.ColorInfo(o.Port))
+	return http.ListenAndServe(net.JoinHostPort(o.Address, strconv.Itoa(o.Port)), mux)
+}
+
+// ServeHTTP generates the builds of the repository of the request
+func (o *ServeTaskgenOptions) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	request := &taskgen.GenerateRequest{}
+	err := json.NewDecoder(r.Body).Decode(request)
+	if err != nil {
+		http.Error(w, fmt.Sprintf("invalid request: %s", err), http.StatusBadRequest)
+		return
+	}
+	err = validateTaskgenRequest(request)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	written := false
+	err = o.generate(request, func(string) {}, func(outDir string) error {
+		w.Header().Set("Content-Type", "application/x-yaml")
+		written = true
+		return writeGeneratedDocuments(outDir, w)
+	})
+	if err != nil {
+		log.Warnf("Failed to generate the builds of %s: %s\n", request.Url, err)
+		if !written {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+		}
+	}
+}
+
+// Generate implements the Taskgen gRPC service streaming the progress of the generation before the resources
+func (o *ServeTaskgenOptions) Generate(request *taskgen.GenerateRequest, stream taskgen.Taskgen_GenerateServer) error {
+	err := validateTaskgenRequest(request)
+	if err != nil {
+		return err
+	}
+	err = o.generate(request, func(progress string) {
+		err := stream.Send(&taskgen.GenerateResponse{Progress: progress})
+		if err != nil {
+			log.Warnf("Failed to send the progress of the generation of %s: %s\n", request.Url, err)
+		}
+	}, func(outDir string) error {
+		return writeGeneratedDocuments(outDir, &taskgenResourcesWriter{stream: stream})
+	})
+	if err != nil {
+		log.Warnf("Failed to generate the builds of %s: %s\n", request.Url, err)
+	}
+	return err
+}
+
+// validateTaskgenRequest returns an error unless the URL of the repository of the request is an https, ssh or git URL
+// so that requests cannot clone local paths of the server or pass options to git with values starting with '-'
+func validateTaskgenRequest(request *taskgen.GenerateRequest) error {
+	if request.Url == "" {
+		return fmt.Errorf("missing the url of the repository")
+	}
+	if strings.HasPrefix(request.Url, "-") {
+		return fmt.Errorf("invalid url %s", request.Url)
+	}
+	if strings.HasPrefix(request.Ref, "-") {
+		return fmt.Errorf("invalid ref %s", request.Ref)
+	}
+	if scpLikeGitURL.MatchString(request.Url) {
+		return nil
+	}
+	u, err := url.Parse(request.Url)
+	if err != nil || u.Host == "" || util.StringArrayIndex(taskgenURLSchemes, u.Scheme) < 0 {
+		return fmt.Errorf("invalid url %s, the url of the repository must be an %s URL", request.Url, strings.Join(taskgenURLSchemes, ", "))
+	}
+	return nil
+}
+
+// taskgenResourcesWriter sends the YAML documents of the generated resources written to it in chunks on the stream
+type taskgenResourcesWriter struct {
+	stream taskgen.Taskgen_GenerateServer
+}
+
+// Write implements io.Writer
+func (w *taskgenResourcesWriter) Write(p []byte) (int, error) {
+	// the stream may keep the message after Send returns so the chunk is copied
+	err := w.stream.Send(&taskgen.GenerateResponse{Resources: append([]byte{}, p...)})
+	if err != nil {
+		return 0, err
+	}
+	return len(p), nil
+}
+
+// generate clones the repository of the request and passes the directory of its generated builds to the write
+// function before the directory is removed. Builds are generated one at a time as the generation changes the caches and the state of the process
+func (o *ServeTaskgenOptions) generate(request *taskgen.GenerateRequest, progress func(string), write func(outDir string) error) error {
+	progress("Waiting for the generation of the previous request")
+	o.lock.Lock()
+	defer o.lock.Unlock()
+
+	dir, err := util.CreateWorkspace("jx-taskgen-")
+	if err != nil {
+		return err
+	}
+	defer os.RemoveAll(dir)
+	sourceDir := filepath.Join(dir, "source")
+	outDir := filepath.Join(dir, "output")
+	err = os.MkdirAll(sourceDir, DefaultWritePermissions)
+	if err != nil {
+		return err
+	}
+	progress(fmt.Sprintf("Cloning %s", request.Url))
+	err = o.Git().Clone(request.Url, sourceDir)
+	if err != nil {
+		return errors.Wrapf(err, "failed to clone %s", request.Url)
+	}
+	if request.Ref != "" {
+		progress(fmt.Sprintf("Checking out %s", request.Ref))
+		err = o.Git().Checkout(sourceDir, request.Ref)
+		if err != nil {
+			return errors.Wrapf(err, "failed to checkout %s of %s", request.Ref, request.Url)
+		}
+	}
+
+	if time.Now().After(o.cacheExpires) {
+		o.packsDir = ""
+		o.podTemplates = map[string]*corev1.Pod{}
+		o.cacheExpires = time.Now().Add(o.CacheDuration)
+	}
+	createBuild := &StepCreateBuildOptions{
+		StepOptions: StepOptions{
+			CommonOptions: o.CommonOptions,
+		},
+		Dir:          sourceDir,
+		OutputDir:    outDir,
+		Branch:       request.Ref,
+		BranchKind:   request.Kind,
+		StrictConfig: true,
+		PacksDir:     o.packsDir,
+		podTemplates: o.podTemplates,
+	}
+	progress("Generating the builds")
+	err = createBuild.Run()
+	if err != nil {
+		return err
+	}
+	o.packsDir = createBuild.PacksDir
+	o.podTemplates = createBuild.podTemplates
+	return write(outDir)
+}
@@ -0,0 +1,140 @@
+package cmd_test
+
+import (
+	"io/ioutil"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/ghodss/yaml"
+	"github.com/jenkins-x/jx/pkg/config"
+	"github.com/jenkins-x/jx/pkg/gits"
+	"github.com/jenkins-x/jx/pkg/helm"
+	"github.com/jenkins-x/jx/pkg/jx/cmd"
+	"github.com/jenkins-x/jx/pkg/taskgen"
+	"github.com/jenkins-x/jx/pkg/util"
+	"github.com/stretchr/testify/assert"
+	"golang.org/x/net/context"
+	"google.golang.org/grpc"
+)
+
+// localCloneGitter clones the local repositories of the URLs as the service only clones remote URLs
+type localCloneGitter struct {
+	*gits.GitCLI
+	repos map[string]string
+}
+
+// Clone clones the local repository of the URL
+func (g *localCloneGitter) Clone(url string, dir string) error {
+	return g.GitCLI.Clone(g.repos[url], dir)
+}
+
+func TestServeTaskgen(t *testing.T) {
+	t.Parallel()
+	testDir, err := ioutil.TempDir("", "test-serve-taskgen")
+	assert.NoError(t, err)
+	defer os.RemoveAll(testDir)
+	repoDir := filepath.Join(testDir, "myapp")
+	err = os.MkdirAll(repoDir, util.DefaultWritePermissions)
+	assert.NoError(t, err)
+
+	projectYaml := `builds:
+  - kind: release
+    build:
+      steps:
+        - name: release
+          image: golang:1.11
+          args:
+          - make
+          - release
+  - kind: pullRequest
+    build:
+      steps:
+        - name: build
+          image: golang:1.11
+          args:
+          - make
+`
+	err = ioutil.WriteFile(filepath.Join(repoDir, config.ProjectConfigFileName), []byte(projectYaml), util.DefaultWritePermissions)
+	assert.NoError(t, err)
+	gitter := gits.NewGitCLI()
+	err = gitter.Init(repoDir)
+	assert.NoError(t, err)
+	for _, args := range [][]string{{"config", "user.name", "test"}, {"config", "user.email", "test@example.com"}} {
+		_, err = (&util.Command{Dir: repoDir, Name: "git", Args: args}).RunWithoutRetry()
+		assert.NoError(t, err)
+	}
+	err = gitter.Add(repoDir, "*")
+	assert.NoError(t, err)
+	err = gitter.CommitDir(repoDir, "initial import")
+	assert.NoError(t, err)
+
+	repoURL := "https://github.com/myorg/myapp.git"
+	o := &cmd.ServeTaskgenOptions{}
+	cmd.ConfigureTestOptionsWithResources(&o.CommonOptions, nil, nil, &localCloneGitter{GitCLI: gitter, repos: map[string]string{repoURL: repoDir}}, helm.NewHelmCLI("helm", helm.V2, testDir, true))
+
+	w := httptest.NewRecorder()
+	o.ServeHTTP(w, httptest.NewRequest(http.MethodPost, "/generate", strings.NewReader(`{"url": "`+repoURL+`", "kind": "release"}`)))
+	assert.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	assert.Equal(t, "application/x-yaml", w.Header().Get("Content-Type"))
+	build := &cmd.Build{}
+	err = yaml.Unmarshal(w.Body.Bytes(), build)
+	assert.NoError(t, err)
+	if assert.Len(t, build.Spec.Steps, 1) {
+		assert.Equal(t, "release", build.Spec.Steps[0].Name)
+	}
+
+	w = httptest.NewRecorder()
+	o.ServeHTTP(w, httptest.NewRequest(http.MethodPost, "/generate", strings.NewReader(`{"url": "`+repoURL+`"}`)))
+	assert.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	assert.Len(t, strings.Split(w.Body.String(), "---\n"), 2, "all kinds of builds should be generated")
+
+	w = httptest.NewRecorder()
+	o.ServeHTTP(w, httptest.NewRequest(http.MethodPost, "/generate", strings.NewReader(`{}`)))
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+
+	for _, request := range []string{
+		`{"url": "` + repoDir + `"}`,
+		`{"url": "file://` + repoDir + `"}`,
+		`{"url": "http://github.com/myorg/myapp.git"}`,
+		`{"url": "--upload-pack=touch /tmp/pwned"}`,
+		`{"url": "` + repoURL + `", "ref": "--orphan=x"}`,
+	} {
+		w = httptest.NewRecorder()
+		o.ServeHTTP(w, httptest.NewRequest(http.MethodPost, "/generate", strings.NewReader(request)))
+		assert.Equal(t, http.StatusBadRequest, w.Code, "request %s", request)
+	}
+
+	w = httptest.NewRecorder()
+	o.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/generate", nil))
+	assert.Equal(t, http.StatusMethodNotAllowed, w.Code)
+
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	assert.NoError(t, err)
+	server := grpc.NewServer()
+	taskgen.RegisterTaskgenServer(server, o)
+	go server.Serve(listener)
+	defer server.Stop()
+	conn, err := grpc.Dial(listener.Addr().String(), grpc.WithInsecure())
+	assert.NoError(t, err)
+	defer conn.Close()
+	progress := []string{}
+	data, err := taskgen.Generate(context.Background(), taskgen.NewTaskgenClient(conn), &taskgen.GenerateRequest{Url: repoURL, Kind: "pullRequest"}, func(message string) {
+		progress = append(progress, message)
+	})
+	assert.NoError(t, err)
+	assert.Contains(t, progress, "Generating the builds")
+	build = &cmd.Build{}
+	err = yaml.Unmarshal(data, build)
+	assert.NoError(t, err)
+	if assert.Len(t, build.Spec.Steps, 1) {
+		assert.Equal(t, "build", build.Spec.Steps[0].Name)
+	}
+
+	_, err = taskgen.Generate(context.Background(), taskgen.NewTaskgenClient(conn), &taskgen.GenerateRequest{Url: "file://" + repoDir}, nil)
+	assert.Error(t, err, "local repositories should not be cloned")
+}
@@ -41,13 +41,26 @@ func NewCmdStep(f Factory, in terminal.FileReader, out terminal.FileWriter, errO
 		},
 	}
 
+	cmd.AddCommand(NewCmdStepApply(f, in, out, errOut))
+	cmd.AddCommand(NewCmdStepApproval(f, in, out, errOut))
 	cmd.AddCommand(NewCmdStepBlog(f, in, out, errOut))
+	cmd.AddCommand(NewCmdStepBlueGreen(f, in, out, errOut))
+	cmd.AddCommand(NewCmdStepCanary(f, in, out, errOut))
+	cmd.AddCommand(NewCmdStepChain(f, in, out, errOut))
 	cmd.AddCommand(NewCmdStepChangelog(f, in, out, errOut))
+	cmd.AddCommand(NewCmdStepChatOps(f, in, out, errOut))
 	cmd.AddCommand(NewCmdCreateBuild(f, in, out, errOut))
 	cmd.AddCommand(NewCmdStepGit(f, in, out, errOut))
 	cmd.AddCommand(NewCmdStepGpgCredentials(f, in, out, errOut))
 	cmd.AddCommand(NewCmdStepHelm(f, in, out, errOut))
 	cmd.AddCommand(NewCmdStepLinkServices(f, in, out, errOut))
+	cmd.AddCommand(NewCmdStepMigrate(f, in, out, errOut))
+	cmd.AddCommand(NewCmdStepIsolate(f, in, out, errOut))
+	cmd.AddCommand(NewCmdStepJira(f, in, out, errOut))
+	cmd.AddCommand(NewCmdStepVault(f, in, out, errOut))
+	cmd.AddCommand(NewCmdStepFlakyTests(f, in, out, errOut))
+	cmd.AddCommand(NewCmdStepDependencyScan(f, in, out, errOut))
+	cmd.AddCommand(NewCmdStepExport(f, in, out, errOut))
 	cmd.AddCommand(NewCmdStepNexus(f, in, out, errOut))
 	cmd.AddCommand(NewCmdStepNextVersion(f, in, out, errOut))
 	cmd.AddCommand(NewCmdStepNextBuildNumber(f, in, out, errOut))
@@ -56,12 +69,18 @@ func NewCmdStep(f Factory, in terminal.FileReader, out terminal.FileWriter, errO
 	cmd.AddCommand(NewCmdStepPost(f, in, out, errOut))
 	cmd.AddCommand(NewCmdStepReport(f, in, out, errOut))
 	cmd.AddCommand(NewCmdStepRelease(f, in, out, errOut))
+	cmd.AddCommand(NewCmdStepSchema(f, in, out, errOut))
+	cmd.AddCommand(NewCmdStepSonar(f, in, out, errOut))
 	cmd.AddCommand(NewCmdStepSplitMonorepo(f, in, out, errOut))
+	cmd.AddCommand(NewCmdStepSync(f, in, out, errOut))
 	cmd.AddCommand(NewCmdStepTag(f, in, out, errOut))
+	cmd.AddCommand(NewCmdStepTrace(f, in, out, errOut))
+	cmd.AddCommand(NewCmdStepUpdate(f, in, out, errOut))
 	cmd.AddCommand(NewCmdStepValidate(f, in, out, errOut))
 	cmd.AddCommand(NewCmdStepVerify(f, in, out, errOut))
 	cmd.AddCommand(NewCmdStepWaitForArtifact(f, in, out, errOut))
 	cmd.AddCommand(NewCmdStepCollect(f, in, out, errOut))
+	cmd.AddCommand(NewCmdStepConvert(f, in, out, errOut))
 
 	return cmd
 }
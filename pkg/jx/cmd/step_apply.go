@@ -0,0 +1,45 @@
+package cmd
+
+import (
+	"io"
+
+	"github.com/spf13/cobra"
+	"gopkg.in/AlecAivazis/survey.v1/terminal"
+)
+
+// StepApplyOptions contains the command line flags
+type StepApplyOptions struct {
+	StepOptions
+}
+
+// NewCmdStepApply Creates a new Command object
+func NewCmdStepApply(f Factory, in terminal.FileReader, out terminal.FileWriter, errOut io.Writer) *cobra.Command {
+	options := &StepApplyOptions{
+		StepOptions: StepOptions{
+			CommonOptions: CommonOptions{
+				Factory: f,
+				In:      in,
+				Out:     out,
+				Err:     errOut,
+			},
+		},
+	}
+
+	cmd := &cobra.Command{
+		Use:   "apply",
+		Short: "apply [command]",
+		Run: func(cmd *cobra.Command, args []string) {
+			options.Cmd = cmd
+			options.Args = args
+			err := options.Run()
+			CheckErr(err)
+		},
+	}
+	cmd.AddCommand(NewCmdStepApplyBuild(f, in, out, errOut))
+	return cmd
+}
+
+// Run implements this command
+func (o *StepApplyOptions) Run() error {
+	return o.Cmd.Help()
+}
@@ -0,0 +1,254 @@
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"io/ioutil"
+	"path/filepath"
+	"sort"
+
+	"github.com/ghodss/yaml"
+	"github.com/jenkins-x/jx/pkg/config"
+	"github.com/jenkins-x/jx/pkg/jx/cmd/templates"
+	"github.com/jenkins-x/jx/pkg/kube"
+	"github.com/jenkins-x/jx/pkg/log"
+	"github.com/jenkins-x/jx/pkg/util"
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+	"gopkg.in/AlecAivazis/survey.v1/terminal"
+)
+
+// ApplyFn applies the resources in the given file to the cluster of the kubeconfig context
+type ApplyFn func(context string, fileName string) error
+
+// StepApplyBuildOptions contains the command line flags
+type StepApplyBuildOptions struct {
+	StepOptions
+
+	Dir         string
+	Contexts    []string
+	Clusters    []string
+	AllContexts bool
+	NoAudit     bool
+
+	// testing
+	FakeApply ApplyFn
+}
+
+// applyResult the result of applying a build file to the cluster of a kubeconfig context
+type applyResult struct {
+	Context  string
+	FileName string
+	Err      error
+}
+
+var (
+	stepApplyBuildLong = templates.LongDesc(`
+		Applies the builds generated by 'jx step create build' to one or more clusters such as regional build farms.
+
+		The clusters are chosen via kubeconfig contexts, kubeconfig clusters or all of the contexts in the kubeconfig file.
+		A report of the result of each cluster is displayed and the command fails if any cluster failed.
+`)
+
+	stepApplyBuildExample = templates.Examples(`
+		# apply the generated builds to the current cluster
+		jx step apply build build-release.yml
+
+		# apply the builds generated into a directory to two clusters
+		jx step apply build --dir out --context us-east --context eu-west
+
+		# apply the committed pre-generated builds in the pipelinesDir of the jenkins-x.yml file
+		jx step apply build
+
+		# apply the generated builds to every cluster in the kubeconfig file
+		jx step apply build --all-contexts build-release.yml
+	`)
+)
+
+// NewCmdStepApplyBuild creates the command
+func NewCmdStepApplyBuild(f Factory, in terminal.FileReader, out terminal.FileWriter, errOut io.Writer) *cobra.Command {
+	options := &StepApplyBuildOptions{
+		StepOptions: StepOptions{
+			CommonOptions: CommonOptions{
+				Factory: f,
+				In:      in,
+				Out:     out,
+				Err:     errOut,
+			},
+		},
+	}
+
+	cmd := &cobra.Command{
+		Use:     "build [files]",
+		Short:   "Applies generated builds to one or more clusters",
+		Long:    stepApplyBuildLong,
+		Example: stepApplyBuildExample,
+		Run: func(cmd *cobra.Command, args []string) {
+			options.Cmd = cmd
+			options.Args = args
+			err := options.Run()
+			CheckErr(err)
+		},
+	}
+	options.addCommonFlags(cmd)
+
+	cmd.Flags().StringVarP(&options.Dir, "dir", "d", "", "The directory containing the build files generated by 'jx step create build --output-dir'. Defaults to the pipelinesDir of the jenkins-x.yml file")
+	cmd.Flags().StringArrayVarP(&options.Contexts, "context", "c", []string{}, "The kubeconfig context of a cluster to apply the builds to")
+	cmd.Flags().StringArrayVarP(&options.Clusters, "cluster", "", []string{}, "The kubeconfig cluster to apply the builds to")
+	cmd.Flags().BoolVarP(&options.AllContexts, "all-contexts", "", false, "Applies the builds to the clusters of all of the kubeconfig contexts")
+	cmd.Flags().BoolVarP(&options.NoAudit, "no-audit", "", false, "Disables recording the applied builds in the pipeline audit log")
+	return cmd
+}
+
+// Run implements this command
+func (o *StepApplyBuildOptions) Run() error {
+	fileNames := append([]string{}, o.Args...)
+	dir := o.Dir
+	if dir == "" && len(fileNames) == 0 {
+		pc, _, err := config.LoadProjectConfig("")
+		if err != nil {
+			return err
+		}
+		dir = pc.PipelinesDir
+	}
+	if dir != "" {
+		matches, err := filepath.Glob(filepath.Join(dir, "build-*.yml"))
+		if err != nil {
+			return err
+		}
+		fileNames = append(fileNames, matches...)
+	}
+	if len(fileNames) == 0 {
+		return fmt.Errorf("Missing argument: the build files to apply or the --dir option")
+	}
+	contexts, err := o.resolveContexts()
+	if err != nil {
+		return err
+	}
+
+	results := []applyResult{}
+	failed := 0
+	for _, context := range contexts {
+		for _, fileName := range fileNames {
+			err := o.apply(context, fileName)
+			if err != nil {
+				failed++
+			} else if !o.NoAudit {
+				o.recordApply(context, fileName)
+			}
+			results = append(results, applyResult{Context: context, FileName: fileName, Err: err})
+		}
+	}
+
+	table := o.CreateTable()
+	table.AddRow("CONTEXT", "FILE", "STATUS", "MESSAGE")
+	for _, r := range results {
+		context := r.Context
+		if context == "" {
+			context = "(current)"
+		}
+		if r.Err != nil {
+			table.AddRow(context, r.FileName, util.ColorError("Failed"), r.Err.Error())
+		} else {
+			table.AddRow(context, r.FileName, util.ColorInfo("Applied"), "")
+		}
+	}
+	table.Render()
+
+	if failed > 0 {
+		return fmt.Errorf("failed to apply %d of %d builds", failed, len(results))
+	}
+	return nil
+}
+
+// resolveContexts returns the kubeconfig contexts to apply the builds to. An empty context is the current context
+func (o *StepApplyBuildOptions) resolveContexts() ([]string, error) {
+	if len(o.Clusters) == 0 && !o.AllContexts {
+		if len(o.Contexts) == 0 {
+			return []string{""}, nil
+		}
+		return o.Contexts, nil
+	}
+	config, _, err := kube.LoadConfig()
+	if err != nil {
+		return nil, err
+	}
+	answer := append([]string{}, o.Contexts...)
+	names := []string{}
+	for name := range config.Contexts {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, cluster := range o.Clusters {
+		found := false
+		for _, name := range names {
+			if config.Contexts[name].Cluster == cluster {
+				found = true
+				if util.StringArrayIndex(answer, name) < 0 {
+					answer = append(answer, name)
+				}
+			}
+		}
+		if !found {
+			return nil, fmt.Errorf("No kubeconfig context found for cluster %s", cluster)
+		}
+	}
+	if o.AllContexts {
+		for _, name := range names {
+			if util.StringArrayIndex(answer, name) < 0 {
+				answer = append(answer, name)
+			}
+		}
+	}
+	return answer, nil
+}
+
+func (o *StepApplyBuildOptions) apply(context string, fileName string) error {
+	if o.FakeApply != nil {
+		return o.FakeApply(context, fileName)
+	}
+	args := []string{"apply", "-f", fileName}
+	if context != "" {
+		args = append([]string{"--context", context}, args...)
+	}
+	_, err := o.getCommandOutput("", "kubectl", args...)
+	return err
+}
+
+// recordApply records the applied build in the pipeline audit log. Failures are only logged
+func (o *StepApplyBuildOptions) recordApply(context string, fileName string) {
+	err := o.saveApplyAuditRecord(context, fileName)
+	if err != nil {
+		log.Warnf("Failed to record applying %s in the pipeline audit log: %s\n", fileName, err)
+	}
+}
+
+func (o *StepApplyBuildOptions) saveApplyAuditRecord(context string, fileName string) error {
+	data, err := ioutil.ReadFile(fileName)
+	if err != nil {
+		return err
+	}
+	build := &Build{}
+	err = yaml.Unmarshal(data, build)
+	if err != nil {
+		return errors.Wrapf(err, "failed to parse build %s", fileName)
+	}
+	record := &kube.PipelineAuditRecord{
+		Pipeline:   build.Name,
+		Action:     kube.PipelineAuditActionApply,
+		User:       currentUsername(),
+		OutputHash: kube.HashPipelineOutput(data),
+	}
+	if build.Labels != nil && build.Labels[kube.LabelPipeline] != "" {
+		record.Pipeline = build.Labels[kube.LabelPipeline]
+	}
+	if build.Annotations != nil {
+		record.BuildPackURL = build.Annotations[kube.AnnotationBuildPackRef]
+	}
+	record.Cluster = context
+	kubeClient, ns, err := o.KubeClientAndDevNamespace()
+	if err != nil {
+		return err
+	}
+	return kube.SavePipelineAuditRecord(kubeClient, ns, record)
+}
@@ -0,0 +1,85 @@
+package cmd_test
+
+import (
+	"fmt"
+	"io/ioutil"
+	"path/filepath"
+	"testing"
+
+	"github.com/jenkins-x/jx/pkg/gits"
+	"github.com/jenkins-x/jx/pkg/helm"
+	"github.com/jenkins-x/jx/pkg/jx/cmd"
+	"github.com/jenkins-x/jx/pkg/kube"
+	"github.com/jenkins-x/jx/pkg/util"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestStepApplyBuild(t *testing.T) {
+	t.Parallel()
+	testDir, err := ioutil.TempDir("", "test-step-apply-build")
+	assert.NoError(t, err)
+
+	buildYaml := `apiVersion: build.knative.dev/v1alpha1
+kind: Build
+metadata:
+  name: myapp1
+  labels:
+    jenkins.io/pipeline: myapp
+spec:
+  steps:
+  - name: build
+    image: golang:1.11
+`
+	for _, name := range []string{"build-release.yml", "build-pullRequest.yml"} {
+		err = ioutil.WriteFile(filepath.Join(testDir, name), []byte(buildYaml), util.DefaultWritePermissions)
+		assert.NoError(t, err)
+	}
+
+	applied := []string{}
+	o := &cmd.StepApplyBuildOptions{
+		Dir:      testDir,
+		Contexts: []string{"us-east", "eu-west"},
+		FakeApply: func(context string, fileName string) error {
+			applied = append(applied, context+":"+filepath.Base(fileName))
+			return nil
+		},
+	}
+	cmd.ConfigureTestOptionsWithResources(&o.CommonOptions, nil, nil, gits.NewGitCLI(), helm.NewHelmCLI("helm", helm.V2, testDir, true))
+	out, err := ioutil.TempFile(testDir, "output")
+	assert.NoError(t, err)
+	defer out.Close()
+	o.Out = out
+
+	err = o.Run()
+	assert.NoError(t, err)
+	assert.Equal(t, []string{
+		"us-east:build-pullRequest.yml",
+		"us-east:build-release.yml",
+		"eu-west:build-pullRequest.yml",
+		"eu-west:build-release.yml",
+	}, applied)
+
+	kubeClient, ns, err := o.KubeClientAndDevNamespace()
+	assert.NoError(t, err)
+	records, err := kube.LoadPipelineAuditRecords(kubeClient, ns, "myapp")
+	assert.NoError(t, err)
+	if assert.Equal(t, 4, len(records)) {
+		for _, r := range records {
+			assert.Equal(t, kube.PipelineAuditActionApply, r.Action)
+		}
+	}
+
+	// a failure in one cluster should not stop the other clusters being applied to
+	applied = []string{}
+	o.NoAudit = true
+	o.FakeApply = func(context string, fileName string) error {
+		if context == "us-east" {
+			return fmt.Errorf("cluster unreachable")
+		}
+		applied = append(applied, context+":"+filepath.Base(fileName))
+		return nil
+	}
+	err = o.Run()
+	assert.Error(t, err)
+	assert.Equal(t, []string{"eu-west:build-pullRequest.yml", "eu-west:build-release.yml"}, applied)
+}
@@ -0,0 +1,162 @@
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/jenkins-x/jx/pkg/apis/jenkins.io/v1"
+	"github.com/jenkins-x/jx/pkg/jx/cmd/templates"
+	"github.com/jenkins-x/jx/pkg/kube"
+	"github.com/jenkins-x/jx/pkg/log"
+	"github.com/jenkins-x/jx/pkg/util"
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+	"gopkg.in/AlecAivazis/survey.v1/terminal"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+var (
+	stepApprovalLong = templates.LongDesc(`
+		Waits for a manual approval gate of the current pipeline to be approved via 'jx approve'.
+
+		The step fails if the approval gate is rejected or is not approved before the timeout.
+`)
+
+	stepApprovalExample = templates.Examples(`
+		# Wait for the 'production' approval gate to be approved
+		jx step approval --name production
+
+		# Wait for one of the given users to approve
+		jx step approval --name production --approvers alice,bob --timeout 2h
+	`)
+)
+
+// StepApprovalOptions contains the command line flags
+type StepApprovalOptions struct {
+	StepOptions
+
+	Name         string
+	Pipeline     string
+	Build        string
+	Approvers    []string
+	Timeout      string
+	PollDuration time.Duration
+}
+
+// NewCmdStepApproval creates the command
+func NewCmdStepApproval(f Factory, in terminal.FileReader, out terminal.FileWriter, errOut io.Writer) *cobra.Command {
+	options := &StepApprovalOptions{
+		StepOptions: StepOptions{
+			CommonOptions: CommonOptions{
+				Factory: f,
+				In:      in,
+				Out:     out,
+				Err:     errOut,
+			},
+		},
+	}
+
+	cmd := &cobra.Command{
+		Use:     "approval",
+		Short:   "Waits for a manual approval gate of the current pipeline to be approved",
+		Long:    stepApprovalLong,
+		Example: stepApprovalExample,
+		Run: func(cmd *cobra.Command, args []string) {
+			options.Cmd = cmd
+			options.Args = args
+			err := options.Run()
+			CheckErr(err)
+		},
+	}
+
+	cmd.Flags().StringVarP(&options.Name, "name", "n", "", "The name of the approval gate")
+	cmd.Flags().StringVarP(&options.Pipeline, "pipeline", "p", "", "The pipeline name. Defaults to the $JOB_NAME of the current pipeline")
+	cmd.Flags().StringVarP(&options.Build, "build", "b", "", "The build number. Defaults to the $BUILD_NUMBER of the current pipeline")
+	cmd.Flags().StringSliceVarP(&options.Approvers, "approvers", "a", []string{}, "The users who can approve. If not specified anyone can approve")
+	cmd.Flags().StringVarP(&options.Timeout, "timeout", "t", "24h", "The duration to wait for approval before failing")
+	cmd.Flags().DurationVarP(&options.PollDuration, "poll", "", 10*time.Second, "The duration between checks of the approval gate")
+	return cmd
+}
+
+// Run implements this command
+func (o *StepApprovalOptions) Run() error {
+	if o.Name == "" {
+		return util.MissingOption("name")
+	}
+	timeout, err := time.ParseDuration(o.Timeout)
+	if err != nil {
+		return util.InvalidOptionError("timeout", o.Timeout, err)
+	}
+	pipeline := o.Pipeline
+	if pipeline == "" {
+		pipeline = o.getJobName()
+	}
+	build := o.Build
+	if build == "" {
+		build = o.getBuildNumber()
+	}
+	if pipeline == "" || build == "" {
+		return fmt.Errorf("could not detect the pipeline and build number; please specify the --pipeline and --build options")
+	}
+
+	apisClient, err := o.CreateApiExtensionsClient()
+	if err != nil {
+		return err
+	}
+	err = kube.RegisterPipelineActivityCRD(apisClient)
+	if err != nil {
+		return err
+	}
+	jxClient, ns, err := o.JXClientAndDevNamespace()
+	if err != nil {
+		return err
+	}
+	activities := jxClient.JenkinsV1().PipelineActivities(ns)
+	name := kube.ToValidName(pipeline + "-" + build)
+	activity, err := activities.Get(name, metav1.GetOptions{})
+	if err != nil {
+		return errors.Wrapf(err, "failed to find the PipelineActivity %s in namespace %s", name, ns)
+	}
+
+	_, approval, _ := kube.GetOrCreateApproval(activity, o.Name)
+	if approval.Status != v1.ActivityStatusTypeSucceeded && approval.Status != v1.ActivityStatusTypeFailed {
+		approval.Status = v1.ActivityStatusTypeWaitingForApproval
+		approval.Approvers = o.Approvers
+		if approval.StartedTimestamp == nil {
+			approval.StartedTimestamp = &metav1.Time{Time: time.Now()}
+		}
+		activity, err = activities.Update(activity)
+		if err != nil {
+			return errors.Wrapf(err, "failed to update the PipelineActivity %s", name)
+		}
+	}
+	log.Infof("Waiting for approval %s of pipeline %s build %s. Approve it via: %s\n", util.ColorInfo(o.Name), util.ColorInfo(pipeline), util.ColorInfo(build),
+		util.ColorInfo(fmt.Sprintf("jx approve %s --name %s", name, o.Name)))
+
+	end := time.Now().Add(timeout)
+	for {
+		_, approval, _ = kube.GetOrCreateApproval(activity, o.Name)
+		switch approval.Status {
+		case v1.ActivityStatusTypeSucceeded:
+			log.Infof("Approval %s was approved by %s\n", util.ColorInfo(o.Name), util.ColorInfo(approval.ApprovedBy))
+			return nil
+		case v1.ActivityStatusTypeFailed:
+			return fmt.Errorf("approval %s was rejected by %s", o.Name, approval.ApprovedBy)
+		}
+		if time.Now().After(end) {
+			approval.Status = v1.ActivityStatusTypeFailed
+			approval.CompletedTimestamp = &metav1.Time{Time: time.Now()}
+			_, err = activities.Update(activity)
+			if err != nil {
+				log.Warnf("Failed to update the PipelineActivity %s: %s\n", name, err)
+			}
+			return fmt.Errorf("approval %s was not approved within %s", o.Name, o.Timeout)
+		}
+		time.Sleep(o.PollDuration)
+		activity, err = activities.Get(name, metav1.GetOptions{})
+		if err != nil {
+			return errors.Wrapf(err, "failed to find the PipelineActivity %s in namespace %s", name, ns)
+		}
+	}
+}
@@ -0,0 +1,230 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+
+	"github.com/jenkins-x/jx/pkg/jx/cmd/templates"
+	"github.com/jenkins-x/jx/pkg/log"
+	"github.com/jenkins-x/jx/pkg/util"
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+	"gopkg.in/AlecAivazis/survey.v1/terminal"
+)
+
+const (
+	trackBlue  = "blue"
+	trackGreen = "green"
+
+	// labelTrack the label on the pods of a blue green deployment which the service selects
+	labelTrack = "track"
+)
+
+var (
+	stepBlueGreenActions = []string{"idle", "verify", "switch", "retire"}
+
+	stepBlueGreenLong = templates.LongDesc(`
+		Performs an action of a blue green deployment of an application.
+
+		The service of the application selects the pods of the live track via the 'track' label. A new version is deployed as the idle track which is verified before the service is switched to it and the previous track is retired.
+
+		The actions are:
+
+		* idle   - displays the idle track which the new version is deployed to
+		* verify - waits for the idle track to become available and runs the verify command, retiring the idle track if it fails
+		* switch - switches the service to the idle track
+		* retire - scales down the track which is no longer live
+`)
+
+	stepBlueGreenExample = templates.Examples(`
+		# deploy the new version to the idle track
+		helm upgrade --install myapp-$(jx step bluegreen --app myapp idle) charts/myapp --set track=$(jx step bluegreen --app myapp idle)
+
+		# verify the idle track then switch the service to it and retire the previous track
+		jx step bluegreen --app myapp --verify "curl -f http://myapp-$JX_TRACK/health" verify
+		jx step bluegreen --app myapp switch
+		jx step bluegreen --app myapp retire
+	`)
+)
+
+// StepBlueGreenOptions contains the command line flags
+type StepBlueGreenOptions struct {
+	StepOptions
+
+	App       string
+	Namespace string
+	Timeout   string
+	Verify    string
+
+	// testing
+	FakeKubectl KubectlFn
+}
+
+// NewCmdStepBlueGreen creates the command
+func NewCmdStepBlueGreen(f Factory, in terminal.FileReader, out terminal.FileWriter, errOut io.Writer) *cobra.Command {
+	options := &StepBlueGreenOptions{
+		StepOptions: StepOptions{
+			CommonOptions: CommonOptions{
+				Factory: f,
+				In:      in,
+				Out:     out,
+				Err:     errOut,
+			},
+		},
+	}
+
+	cmd := &cobra.Command{
+		Use:     "bluegreen [" + strings.Join(stepBlueGreenActions, "|") + "]",
+		Short:   "Performs an action of a blue green deployment of an application",
+		Long:    stepBlueGreenLong,
+		Example: stepBlueGreenExample,
+		Run: func(cmd *cobra.Command, args []string) {
+			options.Cmd = cmd
+			options.Args = args
+			err := options.Run()
+			CheckErr(err)
+		},
+	}
+	options.addCommonFlags(cmd)
+
+	cmd.Flags().StringVarP(&options.App, "app", "a", "", "The name of the service of the application")
+	cmd.Flags().StringVarP(&options.Namespace, "namespace", "n", "", "The namespace of the application. Defaults to the current namespace")
+	cmd.Flags().StringVarP(&options.Timeout, "timeout", "t", "5m", "The duration to wait for the idle track to become available")
+	cmd.Flags().StringVarP(&options.Verify, "verify", "", "", "The shell command which verifies the idle track whose name is available as $JX_TRACK")
+	return cmd
+}
+
+// Run implements this command
+func (o *StepBlueGreenOptions) Run() error {
+	if o.App == "" {
+		return util.MissingOption("app")
+	}
+	if len(o.Args) != 1 || util.StringArrayIndex(stepBlueGreenActions, o.Args[0]) < 0 {
+		return fmt.Errorf("Missing argument: the action which is one of: %s", strings.Join(stepBlueGreenActions, ", "))
+	}
+	ns := o.Namespace
+	if ns == "" {
+		var err error
+		_, ns, err = o.KubeClient()
+		if err != nil {
+			return err
+		}
+	}
+	selector, err := o.serviceSelector(ns)
+	if err != nil {
+		return err
+	}
+	live := selector[labelTrack]
+	if live == "" {
+		live = trackBlue
+	}
+	idle := otherTrack(live)
+
+	switch o.Args[0] {
+	case "idle":
+		_, err = fmt.Fprintln(o.Out, idle)
+		return err
+	case "verify":
+		return o.verify(ns, selector, idle)
+	case "switch":
+		_, err = o.kubectl("patch", "service", o.App, "--namespace", ns, "--type", "merge", "-p",
+			fmt.Sprintf(`{"spec":{"selector":{"%s":"%s"}}}`, labelTrack, idle))
+		if err != nil {
+			return errors.Wrapf(err, "failed to switch the service %s to track %s", o.App, idle)
+		}
+		log.Infof("Switched the service %s to track %s\n", util.ColorInfo(o.App), util.ColorInfo(idle))
+		return nil
+	default:
+		// the live track is the new version once the service has been switched
+		return o.scaleDown(ns, selector, idle)
+	}
+}
+
+// verify waits for the idle track to become available and runs the verify command. The idle track is scaled down
+// if the verification fails so the live track keeps serving
+func (o *StepBlueGreenOptions) verify(ns string, selector map[string]string, track string) error {
+	_, err := o.kubectl("wait", "deployment", "--namespace", ns, "-l", trackSelector(selector, track),
+		"--for=condition=available", "--timeout="+o.Timeout)
+	if err == nil && o.Verify != "" {
+		cmd := util.Command{
+			Name: "/bin/sh",
+			Args: []string{"-c", o.Verify},
+			Env:  map[string]string{"JX_TRACK": track},
+			Out:  o.Out,
+			Err:  o.Err,
+		}
+		_, err = cmd.RunWithoutRetry()
+	}
+	if err == nil {
+		log.Infof("Verified track %s of %s\n", util.ColorInfo(track), util.ColorInfo(o.App))
+		return nil
+	}
+	rollbackErr := o.scaleDown(ns, selector, track)
+	if rollbackErr != nil {
+		log.Warnf("Failed to retire track %s: %s\n", track, rollbackErr)
+	}
+	return errors.Wrapf(err, "failed to verify track %s of %s so it was retired", track, o.App)
+}
+
+// scaleDown scales down the deployments of the track
+func (o *StepBlueGreenOptions) scaleDown(ns string, selector map[string]string, track string) error {
+	text, err := o.kubectl("scale", "deployment", "--namespace", ns, "-l", trackSelector(selector, track), "--replicas=0")
+	if err != nil {
+		if strings.Contains(text+err.Error(), "no objects passed to scale") {
+			return nil
+		}
+		return errors.Wrapf(err, "failed to retire track %s of %s", track, o.App)
+	}
+	log.Infof("Retired track %s of %s\n", util.ColorInfo(track), util.ColorInfo(o.App))
+	return nil
+}
+
+// serviceSelector returns the selector of the service of the application
+func (o *StepBlueGreenOptions) serviceSelector(ns string) (map[string]string, error) {
+	text, err := o.kubectl("get", "service", o.App, "--namespace", ns, "-o", "json")
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to find the service %s in namespace %s", o.App, ns)
+	}
+	service := struct {
+		Spec struct {
+			Selector map[string]string `json:"selector"`
+		} `json:"spec"`
+	}{}
+	err = json.Unmarshal([]byte(text), &service)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to parse the service %s", o.App)
+	}
+	if service.Spec.Selector == nil {
+		return map[string]string{}, nil
+	}
+	return service.Spec.Selector, nil
+}
+
+func (o *StepBlueGreenOptions) kubectl(args ...string) (string, error) {
+	if o.FakeKubectl != nil {
+		return o.FakeKubectl(args...)
+	}
+	return o.getCommandOutput("", "kubectl", args...)
+}
+
+// trackSelector returns the label selector of the pods of the track
+func trackSelector(selector map[string]string, track string) string {
+	labels := []string{}
+	for k, v := range selector {
+		if k != labelTrack {
+			labels = append(labels, k+"="+v)
+		}
+	}
+	sort.Strings(labels)
+	return strings.Join(append(labels, labelTrack+"="+track), ",")
+}
+
+func otherTrack(track string) string {
+	if track == trackBlue {
+		return trackGreen
+	}
+	return trackBlue
+}
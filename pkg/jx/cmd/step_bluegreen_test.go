@@ -0,0 +1,73 @@
+package cmd_test
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/jenkins-x/jx/pkg/jx/cmd"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestStepBlueGreen(t *testing.T) {
+	t.Parallel()
+	commands := []string{}
+	serviceJSON := `{"spec":{"selector":{"app":"myapp","track":"blue"}}}`
+	waitErr := error(nil)
+	out, err := ioutil.TempFile("", "test-step-bluegreen")
+	assert.NoError(t, err)
+	defer os.Remove(out.Name())
+	o := &cmd.StepBlueGreenOptions{
+		App:       "myapp",
+		Namespace: "jx-staging",
+		Timeout:   "1m",
+		FakeKubectl: func(args ...string) (string, error) {
+			if args[0] == "get" {
+				return serviceJSON, nil
+			}
+			commands = append(commands, strings.Join(args, " "))
+			if args[0] == "wait" {
+				return "", waitErr
+			}
+			return "", nil
+		},
+	}
+	o.Out = out
+
+	o.Args = []string{"idle"}
+	err = o.Run()
+	assert.NoError(t, err)
+	data, err := ioutil.ReadFile(out.Name())
+	assert.NoError(t, err)
+	assert.Equal(t, "green\n", string(data))
+
+	o.Args = []string{"verify"}
+	o.Verify = `test "$JX_TRACK" = green`
+	err = o.Run()
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"wait deployment --namespace jx-staging -l app=myapp,track=green --for=condition=available --timeout=1m"}, commands)
+
+	commands = []string{}
+	o.Args = []string{"switch"}
+	err = o.Run()
+	assert.NoError(t, err)
+	assert.Equal(t, []string{`patch service myapp --namespace jx-staging --type merge -p {"spec":{"selector":{"track":"green"}}}`}, commands)
+
+	commands = []string{}
+	serviceJSON = `{"spec":{"selector":{"app":"myapp","track":"green"}}}`
+	o.Args = []string{"retire"}
+	err = o.Run()
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"scale deployment --namespace jx-staging -l app=myapp,track=blue --replicas=0"}, commands)
+
+	// a failed verification retires the idle track
+	commands = []string{}
+	waitErr = fmt.Errorf("timed out")
+	o.Args = []string{"verify"}
+	err = o.Run()
+	assert.Error(t, err)
+	assert.Equal(t, 2, len(commands))
+	assert.Equal(t, "scale deployment --namespace jx-staging -l app=myapp,track=blue --replicas=0", commands[1])
+}
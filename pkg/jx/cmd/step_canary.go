@@ -0,0 +1,231 @@
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/ghodss/yaml"
+	"github.com/jenkins-x/jx/pkg/jx/cmd/templates"
+	"github.com/jenkins-x/jx/pkg/log"
+	"github.com/jenkins-x/jx/pkg/util"
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+	"gopkg.in/AlecAivazis/survey.v1/terminal"
+)
+
+const (
+	canaryPhaseInitialized = "Initialized"
+	canaryPhaseSucceeded   = "Succeeded"
+	canaryPhaseFailed      = "Failed"
+)
+
+var (
+	stepCanaryLong = templates.LongDesc(`
+		Creates or updates the Flagger canary of an application deployed by 'helm upgrade' and waits for the analysis of the new version.
+
+		Flagger progressively shifts traffic to the new version while its metrics are healthy and rolls it back otherwise. The step fails if the new version is rolled back or the analysis does not complete before the timeout.
+`)
+
+	stepCanaryExample = templates.Examples(`
+		# Wait for the canary analysis of the new version of myapp
+		jx step canary --app myapp --namespace jx-staging
+
+		# Shift traffic in steps of 5% up to 30% analysing the metrics every 30 seconds
+		jx step canary --app myapp --step-weight 5 --max-weight 30 --interval 30s
+	`)
+)
+
+// KubectlFn runs kubectl with the given arguments returning its output
+type KubectlFn func(args ...string) (string, error)
+
+// StepCanaryOptions contains the command line flags
+type StepCanaryOptions struct {
+	StepOptions
+
+	App          string
+	Namespace    string
+	Port         int
+	Interval     string
+	Threshold    int
+	MaxWeight    int
+	StepWeight   int
+	Timeout      string
+	PollDuration time.Duration
+
+	// testing
+	FakeKubectl KubectlFn
+}
+
+// NewCmdStepCanary creates the command
+func NewCmdStepCanary(f Factory, in terminal.FileReader, out terminal.FileWriter, errOut io.Writer) *cobra.Command {
+	options := &StepCanaryOptions{
+		StepOptions: StepOptions{
+			CommonOptions: CommonOptions{
+				Factory: f,
+				In:      in,
+				Out:     out,
+				Err:     errOut,
+			},
+		},
+	}
+
+	cmd := &cobra.Command{
+		Use:     "canary",
+		Short:   "Creates the Flagger canary of an application and waits for the analysis of the new version",
+		Long:    stepCanaryLong,
+		Example: stepCanaryExample,
+		Run: func(cmd *cobra.Command, args []string) {
+			options.Cmd = cmd
+			options.Args = args
+			err := options.Run()
+			CheckErr(err)
+		},
+	}
+	options.addCommonFlags(cmd)
+
+	cmd.Flags().StringVarP(&options.App, "app", "a", "", "The name of the deployment of the application")
+	cmd.Flags().StringVarP(&options.Namespace, "namespace", "n", "", "The namespace of the application. Defaults to the current namespace")
+	cmd.Flags().IntVarP(&options.Port, "port", "", 8080, "The port the service of the application listens on")
+	cmd.Flags().StringVarP(&options.Interval, "interval", "", "1m", "How often the metrics of the canary are analysed")
+	cmd.Flags().IntVarP(&options.Threshold, "threshold", "", 5, "The number of failed metric checks before the canary is rolled back")
+	cmd.Flags().IntVarP(&options.MaxWeight, "max-weight", "", 50, "The maximum percentage of traffic routed to the canary")
+	cmd.Flags().IntVarP(&options.StepWeight, "step-weight", "", 10, "The percentage of traffic added to the canary after each successful analysis")
+	cmd.Flags().StringVarP(&options.Timeout, "timeout", "t", "30m", "The duration to wait for the analysis to complete before failing")
+	cmd.Flags().DurationVarP(&options.PollDuration, "poll", "", 10*time.Second, "The duration between checks of the canary status")
+	return cmd
+}
+
+// Run implements this command
+func (o *StepCanaryOptions) Run() error {
+	if o.App == "" {
+		return util.MissingOption("app")
+	}
+	timeout, err := time.ParseDuration(o.Timeout)
+	if err != nil {
+		return util.InvalidOptionError("timeout", o.Timeout, err)
+	}
+	ns := o.Namespace
+	if ns == "" {
+		_, ns, err = o.KubeClient()
+		if err != nil {
+			return err
+		}
+	}
+
+	start := time.Now()
+	err = o.applyCanary(ns)
+	if err != nil {
+		return err
+	}
+	log.Infof("Waiting for the canary analysis of %s in namespace %s\n", util.ColorInfo(o.App), util.ColorInfo(ns))
+
+	end := start.Add(timeout)
+	for {
+		phase, transition, err := o.canaryStatus(ns)
+		if err != nil {
+			log.Warnf("Failed to get the status of canary %s: %s\n", o.App, err)
+		} else if !transition.Before(start.Truncate(time.Second)) {
+			switch phase {
+			case canaryPhaseInitialized, canaryPhaseSucceeded:
+				log.Infof("The canary analysis of %s succeeded\n", util.ColorInfo(o.App))
+				return nil
+			case canaryPhaseFailed:
+				return fmt.Errorf("the canary analysis of %s failed so the new version was rolled back", o.App)
+			}
+		}
+		if time.Now().After(end) {
+			return fmt.Errorf("the canary analysis of %s did not complete within %s", o.App, o.Timeout)
+		}
+		time.Sleep(o.PollDuration)
+	}
+}
+
+// applyCanary creates or updates the Flagger canary of the application
+func (o *StepCanaryOptions) applyCanary(ns string) error {
+	data, err := yaml.Marshal(o.canary(ns))
+	if err != nil {
+		return err
+	}
+	tmpFile, err := ioutil.TempFile("", "jx-canary-")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmpFile.Name())
+	_, err = tmpFile.Write(data)
+	tmpFile.Close()
+	if err != nil {
+		return err
+	}
+	_, err = o.kubectl("apply", "-f", tmpFile.Name())
+	if err != nil {
+		return errors.Wrapf(err, "failed to apply the canary %s", o.App)
+	}
+	return nil
+}
+
+// canary returns the Flagger canary resource of the application
+func (o *StepCanaryOptions) canary(ns string) map[string]interface{} {
+	metric := func(name string, threshold int) map[string]interface{} {
+		return map[string]interface{}{
+			"name":      name,
+			"threshold": threshold,
+			"interval":  o.Interval,
+		}
+	}
+	return map[string]interface{}{
+		"apiVersion": "flagger.app/v1alpha3",
+		"kind":       "Canary",
+		"metadata": map[string]interface{}{
+			"name":      o.App,
+			"namespace": ns,
+		},
+		"spec": map[string]interface{}{
+			"targetRef": map[string]interface{}{
+				"apiVersion": "apps/v1",
+				"kind":       "Deployment",
+				"name":       o.App,
+			},
+			"service": map[string]interface{}{
+				"port": o.Port,
+			},
+			"canaryAnalysis": map[string]interface{}{
+				"interval":   o.Interval,
+				"threshold":  o.Threshold,
+				"maxWeight":  o.MaxWeight,
+				"stepWeight": o.StepWeight,
+				"metrics": []interface{}{
+					metric("request-success-rate", 99),
+					metric("request-duration", 500),
+				},
+			},
+		},
+	}
+}
+
+// canaryStatus returns the phase of the canary and when it last changed
+func (o *StepCanaryOptions) canaryStatus(ns string) (string, time.Time, error) {
+	text, err := o.kubectl("get", "canary", o.App, "--namespace", ns, "-o", "jsonpath={.status.phase},{.status.lastTransitionTime}")
+	if err != nil {
+		return "", time.Time{}, err
+	}
+	parts := strings.SplitN(strings.TrimSpace(text), ",", 2)
+	if len(parts) < 2 || parts[1] == "" {
+		return parts[0], time.Time{}, nil
+	}
+	transition, err := time.Parse(time.RFC3339, parts[1])
+	if err != nil {
+		return parts[0], transition, errors.Wrapf(err, "failed to parse the last transition time %s", parts[1])
+	}
+	return parts[0], transition, nil
+}
+
+func (o *StepCanaryOptions) kubectl(args ...string) (string, error) {
+	if o.FakeKubectl != nil {
+		return o.FakeKubectl(args...)
+	}
+	return o.getCommandOutput("", "kubectl", args...)
+}
@@ -0,0 +1,49 @@
+package cmd_test
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/jenkins-x/jx/pkg/jx/cmd"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestStepCanary(t *testing.T) {
+	t.Parallel()
+	statuses := []string{}
+	applied := []string{}
+	o := &cmd.StepCanaryOptions{
+		App:          "myapp",
+		Namespace:    "jx-staging",
+		Timeout:      "1m",
+		PollDuration: time.Millisecond,
+		FakeKubectl: func(args ...string) (string, error) {
+			if args[0] == "apply" {
+				applied = append(applied, args[2])
+				return "", nil
+			}
+			if len(statuses) == 0 {
+				return "", fmt.Errorf("no more statuses")
+			}
+			status := statuses[0]
+			statuses = statuses[1:]
+			return status, nil
+		},
+	}
+	future := time.Now().Add(time.Hour).UTC().Format(time.RFC3339)
+	past := time.Now().Add(-time.Hour).UTC().Format(time.RFC3339)
+
+	statuses = []string{"Succeeded," + past, "Progressing," + future, "Succeeded," + future}
+	err := o.Run()
+	assert.NoError(t, err)
+	assert.Equal(t, 1, len(applied))
+	assert.Empty(t, statuses, "should ignore the status of the previous analysis")
+
+	statuses = []string{"Progressing," + future, "Failed," + future}
+	err = o.Run()
+	if assert.Error(t, err) {
+		assert.True(t, strings.Contains(err.Error(), "rolled back"), err.Error())
+	}
+}
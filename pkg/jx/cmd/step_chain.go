@@ -0,0 +1,114 @@
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/jenkins-x/jx/pkg/jx/cmd/templates"
+	"github.com/jenkins-x/jx/pkg/kube"
+	"github.com/jenkins-x/jx/pkg/log"
+	"github.com/jenkins-x/jx/pkg/util"
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+	"gopkg.in/AlecAivazis/survey.v1/terminal"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+var (
+	stepChainLong = templates.LongDesc(`
+		Chains downstream pipelines to the current pipeline so that they are triggered with the version of the current pipeline when it succeeds.
+
+		The downstream pipelines are triggered by the 'jx controller chain' controller.
+`)
+
+	stepChainExample = templates.Examples(`
+		# Trigger the integration tests when the current pipeline succeeds
+		jx step chain --pipeline myorg/integration-tests/master
+	`)
+)
+
+// StepChainOptions contains the command line flags
+type StepChainOptions struct {
+	StepOptions
+
+	Pipelines []string
+}
+
+// NewCmdStepChain creates the command
+func NewCmdStepChain(f Factory, in terminal.FileReader, out terminal.FileWriter, errOut io.Writer) *cobra.Command {
+	options := &StepChainOptions{
+		StepOptions: StepOptions{
+			CommonOptions: CommonOptions{
+				Factory: f,
+				In:      in,
+				Out:     out,
+				Err:     errOut,
+			},
+		},
+	}
+
+	cmd := &cobra.Command{
+		Use:     "chain",
+		Short:   "Chains downstream pipelines to be triggered when the current pipeline succeeds",
+		Long:    stepChainLong,
+		Example: stepChainExample,
+		Run: func(cmd *cobra.Command, args []string) {
+			options.Cmd = cmd
+			options.Args = args
+			err := options.Run()
+			CheckErr(err)
+		},
+	}
+	cmd.Flags().StringSliceVarP(&options.Pipelines, "pipeline", "p", []string{}, "The downstream pipelines such as 'myorg/myrepo/master' to trigger")
+	return cmd
+}
+
+// Run implements this command
+func (o *StepChainOptions) Run() error {
+	if len(o.Pipelines) == 0 {
+		return util.MissingOption("pipeline")
+	}
+	pipeline := o.getJobName()
+	build := o.getBuildNumber()
+	if pipeline == "" || build == "" {
+		return fmt.Errorf("JOB_NAME or BUILD_NUMBER environment variables not set")
+	}
+	apisClient, err := o.CreateApiExtensionsClient()
+	if err != nil {
+		return err
+	}
+	err = kube.RegisterPipelineActivityCRD(apisClient)
+	if err != nil {
+		return err
+	}
+	jxClient, ns, err := o.JXClientAndDevNamespace()
+	if err != nil {
+		return err
+	}
+	activities := jxClient.JenkinsV1().PipelineActivities(ns)
+	name := kube.ToValidName(pipeline + "-" + build)
+	activity, err := activities.Get(name, metav1.GetOptions{})
+	if err != nil {
+		return errors.Wrapf(err, "failed to find the PipelineActivity %s in namespace %s", name, ns)
+	}
+	if activity.Annotations == nil {
+		activity.Annotations = map[string]string{}
+	}
+	triggers := []string{}
+	if activity.Annotations[kube.AnnotationTriggers] != "" {
+		triggers = strings.Split(activity.Annotations[kube.AnnotationTriggers], ",")
+	}
+	for _, p := range o.Pipelines {
+		if util.StringArrayIndex(triggers, p) < 0 {
+			triggers = append(triggers, p)
+		}
+	}
+	activity.Annotations[kube.AnnotationTriggers] = strings.Join(triggers, ",")
+	_, err = activities.Update(activity)
+	if err != nil {
+		return err
+	}
+	log.Infof("Pipelines %s will be triggered when %s succeeds\n", util.ColorInfo(strings.Join(o.Pipelines, ", ")), util.ColorInfo(name))
+	return nil
+}
@@ -0,0 +1,150 @@
+package cmd
+
+import (
+	"io"
+
+	"github.com/jenkins-x/jx/pkg/config"
+	"github.com/jenkins-x/jx/pkg/jx/cmd/templates"
+	"github.com/jenkins-x/jx/pkg/kube"
+	"github.com/jenkins-x/jx/pkg/log"
+	"github.com/jenkins-x/jx/pkg/prow"
+	"github.com/jenkins-x/jx/pkg/util"
+	"github.com/spf13/cobra"
+	"gopkg.in/AlecAivazis/survey.v1/terminal"
+)
+
+var (
+	stepChatOpsLong = templates.LongDesc(`
+		Maps the Prow style chat commands in a pull request comment to the builds of the project.
+
+		* '/test all', '/test this' or '/test <context>' creates the builds whose context matches
+		* '/retest' creates all of the builds again
+		* '/approve' approves the approval gates of the current pipeline which are waiting for approval
+
+		The context of a build defaults to '` + prow.ServerlessJenins + `' for pull request builds or the kind of the build. It can be changed via the 'context' of a build in the ` + config.ProjectConfigFileName + ` file.
+`)
+
+	stepChatOpsExample = templates.Examples(`
+		# Create the builds matching the comment
+		jx step chatops --comment "/test all" -o builds
+
+		# Approve the waiting approval gates of the current pipeline
+		jx step chatops --comment "/approve" --user alice
+	`)
+)
+
+// StepChatOpsOptions contains the command line flags
+type StepChatOpsOptions struct {
+	StepOptions
+
+	Comment     string
+	User        string
+	Dir         string
+	OutputDir   string
+	BuildNumber int
+}
+
+// NewCmdStepChatOps creates the command
+func NewCmdStepChatOps(f Factory, in terminal.FileReader, out terminal.FileWriter, errOut io.Writer) *cobra.Command {
+	options := &StepChatOpsOptions{
+		StepOptions: StepOptions{
+			CommonOptions: CommonOptions{
+				Factory: f,
+				In:      in,
+				Out:     out,
+				Err:     errOut,
+			},
+		},
+	}
+
+	cmd := &cobra.Command{
+		Use:     "chatops",
+		Short:   "Maps the chat commands in a pull request comment to the builds of the project",
+		Long:    stepChatOpsLong,
+		Example: stepChatOpsExample,
+		Run: func(cmd *cobra.Command, args []string) {
+			options.Cmd = cmd
+			options.Args = args
+			err := options.Run()
+			CheckErr(err)
+		},
+	}
+	options.addCommonFlags(cmd)
+
+	cmd.Flags().StringVarP(&options.Comment, "comment", "c", "", "The text of the pull request comment")
+	cmd.Flags().StringVarP(&options.User, "user", "u", "", "The user who made the comment")
+	cmd.Flags().StringVarP(&options.Dir, "dir", "d", "", "The directory to query to find the projects .git directory")
+	cmd.Flags().StringVarP(&options.OutputDir, "output-dir", "o", "", "The directory where the generated build yaml files will be output to")
+	cmd.Flags().IntVarP(&options.BuildNumber, "build-number", "n", 1, "Which build number to use. <= 0 are ignored")
+	return cmd
+}
+
+// Run implements this command
+func (o *StepChatOpsOptions) Run() error {
+	if o.Comment == "" {
+		return util.MissingOption("comment")
+	}
+	commands := prow.ParseChatOpsCommands(o.Comment)
+	if len(commands) == 0 {
+		log.Infof("No chat commands found in the comment\n")
+		return nil
+	}
+	pc, _, err := config.LoadProjectConfig(o.Dir)
+	if err != nil {
+		return err
+	}
+	kinds := []string{}
+	for _, command := range commands {
+		if command.Name == prow.ChatOpsApprove {
+			err = o.approve()
+			if err != nil {
+				return err
+			}
+			continue
+		}
+		for _, branchBuild := range pc.Builds {
+			if branchBuild == nil || util.StringArrayIndex(kinds, branchBuild.Kind) >= 0 {
+				continue
+			}
+			context := branchBuild.Context
+			if context == "" {
+				context = prow.DefaultContext(branchBuild.Kind)
+			}
+			if command.MatchesContext(context) {
+				kinds = append(kinds, branchBuild.Kind)
+			}
+		}
+	}
+	for _, kind := range kinds {
+		log.Infof("Creating the %s build\n", util.ColorInfo(kind))
+		build := &StepCreateBuildOptions{
+			StepOptions:      o.StepOptions,
+			Dir:              o.Dir,
+			OutputDir:        o.OutputDir,
+			OutputFilePrefix: "build-",
+			BranchKind:       kind,
+			BuildNumber:      o.BuildNumber,
+		}
+		err = build.Run()
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// approve approves the approval gates of the current pipeline which are waiting for approval
+func (o *StepChatOpsOptions) approve() error {
+	pipeline := o.getJobName()
+	build := o.getBuildNumber()
+	if pipeline == "" || build == "" {
+		log.Warnf("Cannot approve as the current pipeline could not be detected from $JOB_NAME and $BUILD_NUMBER\n")
+		return nil
+	}
+	approve := &ApproveOptions{
+		CommonOptions: o.CommonOptions,
+		User:          o.User,
+	}
+	approve.Args = []string{kube.ToValidName(pipeline + "-" + build)}
+	return approve.Run()
+}
@@ -0,0 +1,48 @@
+package cmd
+
+import (
+	"io"
+
+	"github.com/spf13/cobra"
+	"gopkg.in/AlecAivazis/survey.v1/terminal"
+)
+
+// StepConvertOptions contains the command line flags
+type StepConvertOptions struct {
+	StepOptions
+}
+
+// NewCmdStepConvert creates the command
+func NewCmdStepConvert(f Factory, in terminal.FileReader, out terminal.FileWriter, errOut io.Writer) *cobra.Command {
+	options := &StepConvertOptions{
+		StepOptions: StepOptions{
+			CommonOptions: CommonOptions{
+				Factory: f,
+				In:      in,
+				Out:     out,
+				Err:     errOut,
+			},
+		},
+	}
+
+	cmd := &cobra.Command{
+		Use:   "convert",
+		Short: "Converts legacy configuration into its Jenkins X equivalent",
+		Run: func(cmd *cobra.Command, args []string) {
+			options.Cmd = cmd
+			options.Args = args
+			err := options.Run()
+			CheckErr(err)
+		},
+	}
+
+	cmd.AddCommand(NewCmdStepConvertPack(f, in, out, errOut))
+	options.addCommonFlags(cmd)
+
+	return cmd
+}
+
+// Run implements this command
+func (o *StepConvertOptions) Run() error {
+	return o.Cmd.Help()
+}
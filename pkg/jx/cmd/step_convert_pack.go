@@ -0,0 +1,143 @@
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"path/filepath"
+
+	"github.com/jenkins-x/jx/pkg/config"
+	"github.com/jenkins-x/jx/pkg/draft"
+	"github.com/jenkins-x/jx/pkg/jx/cmd/templates"
+	"github.com/jenkins-x/jx/pkg/log"
+	"github.com/jenkins-x/jx/pkg/util"
+	"github.com/spf13/cobra"
+	"gopkg.in/AlecAivazis/survey.v1/terminal"
+	"gopkg.in/yaml.v2"
+)
+
+var (
+	stepConvertPackLong = templates.LongDesc(`
+		Converts the Jenkinsfile of a legacy draft build pack into the equivalent builds of a jenkins-x.yml file.
+
+		Stages for the 'PR-*' branches become steps of the pullRequest build, stages for the 'master' branch steps of the release build and stages without a when clause steps of both. Anything which cannot be converted such as environment variables bound to credentials is reported as a warning.
+
+		Projects with a build pack but no builds are converted transparently by 'jx step create build' so converting a pack is only required to customise its builds.
+`)
+
+	stepConvertPackExample = templates.Examples(`
+		# prints the builds of the build pack of the current project
+		jx step convert pack
+
+		# writes the builds of the maven build pack of the team into a file
+		jx step convert pack maven -o jenkins-x.yml
+
+		# converts a pack in a local directory
+		jx step convert pack ./packs/go
+	`)
+)
+
+// StepConvertPackOptions contains the command line flags
+type StepConvertPackOptions struct {
+	StepConvertOptions
+
+	Dir        string
+	PacksDir   string
+	OutputFile string
+}
+
+// NewCmdStepConvertPack creates the command
+func NewCmdStepConvertPack(f Factory, in terminal.FileReader, out terminal.FileWriter, errOut io.Writer) *cobra.Command {
+	options := &StepConvertPackOptions{
+		StepConvertOptions: StepConvertOptions{
+			StepOptions: StepOptions{
+				CommonOptions: CommonOptions{
+					Factory: f,
+					In:      in,
+					Out:     out,
+					Err:     errOut,
+				},
+			},
+		},
+	}
+
+	cmd := &cobra.Command{
+		Use:     "pack [name or directory]",
+		Short:   "Converts the Jenkinsfile of a legacy draft build pack into the builds of a jenkins-x.yml file",
+		Long:    stepConvertPackLong,
+		Example: stepConvertPackExample,
+		Run: func(cmd *cobra.Command, args []string) {
+			options.Cmd = cmd
+			options.Args = args
+			err := options.Run()
+			CheckErr(err)
+		},
+	}
+	options.addCommonFlags(cmd)
+
+	cmd.Flags().StringVarP(&options.Dir, "dir", "d", ".", "The directory of the project whose build pack is converted if no pack is specified")
+	cmd.Flags().StringVarP(&options.PacksDir, "packs-dir", "", "", "The directory containing the build packs. Defaults to the build packs of the team")
+	cmd.Flags().StringVarP(&options.OutputFile, optionOutputFile, "o", "", "The file to write the builds into. Defaults to printing them")
+	return cmd
+}
+
+// Run implements this command
+func (o *StepConvertPackOptions) Run() error {
+	if len(o.Args) > 1 {
+		return fmt.Errorf("expected a single build pack but got %v", o.Args)
+	}
+	packDir, err := o.packDir()
+	if err != nil {
+		return err
+	}
+	pc, warnings, err := draft.ConvertPack(packDir)
+	if err != nil {
+		return err
+	}
+	for _, warning := range warnings {
+		log.Warnf("%s\n", warning)
+	}
+	if o.OutputFile != "" {
+		err = pc.SaveConfig(o.OutputFile)
+		if err != nil {
+			return err
+		}
+		log.Infof("Wrote the builds of %s to %s\n", util.ColorInfo(packDir), util.ColorInfo(o.OutputFile))
+		return nil
+	}
+	data, err := yaml.Marshal(pc)
+	if err != nil {
+		return err
+	}
+	_, err = o.Out.Write(data)
+	return err
+}
+
+// packDir returns the directory of the pack of the argument which is either a directory or the name of a build pack
+// of the team, defaulting to the build pack of the project
+func (o *StepConvertPackOptions) packDir() (string, error) {
+	pack := ""
+	if len(o.Args) > 0 {
+		pack = o.Args[0]
+		if fileExists(filepath.Join(pack, draft.JenkinsfileName)) {
+			return pack, nil
+		}
+	} else {
+		pc, _, err := config.LoadProjectConfig(o.Dir)
+		if err != nil {
+			return "", err
+		}
+		pack = pc.BuildPack
+		if pack == "" {
+			return "", fmt.Errorf("the project in %s has no build pack; please specify the build pack to convert", o.Dir)
+		}
+	}
+	packsDir := o.PacksDir
+	if packsDir == "" {
+		var err error
+		packsDir, err = loadBuildPacks(o.CommonOptions)
+		if err != nil {
+			return "", err
+		}
+	}
+	return filepath.Join(packsDir, pack), nil
+}
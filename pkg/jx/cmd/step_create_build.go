@@ -1,29 +1,107 @@
 package cmd
 
 import (
+	"context"
 	"fmt"
 	"io"
 	"io/ioutil"
 	"os"
 	"path/filepath"
 	"strconv"
+	"strings"
+	"sync"
+	"time"
 
+	"github.com/fatih/color"
 	"github.com/ghodss/yaml"
+	"github.com/jenkins-x/jx/pkg/apis/jenkins.io/v1"
 	"github.com/jenkins-x/jx/pkg/kube"
 	"github.com/pkg/errors"
 	"gopkg.in/AlecAivazis/survey.v1/terminal"
 
 	"github.com/jenkins-x/jx/pkg/config"
+	"github.com/jenkins-x/jx/pkg/history"
+	"github.com/jenkins-x/jx/pkg/issues"
 	"github.com/jenkins-x/jx/pkg/jx/cmd/templates"
 	"github.com/jenkins-x/jx/pkg/log"
+	"github.com/jenkins-x/jx/pkg/util"
 	"github.com/spf13/cobra"
 	corev1 "k8s.io/api/core/v1"
+	networkingv1 "k8s.io/api/networking/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
 
 var (
 	createBuildLong = templates.LongDesc(`
 		Creates a Knative build resource for a project
+
+		The default values of the flags can be specified by name in ~/.jx/createbuild.yaml and in .jx/createbuild.yaml in the repository, which overrides the values of the user. Each flag can also be specified by an environment variable named after the flag such as $JX_BUILD_NUMBER for --build-number, which overrides the files. Flags specified on the command line override all of them.
+
+		No builds are created if the message of the head commit contains [skip ci] or [ci skip]. Other directives such as [skip tests] skip the steps mapped to them by the commitDirectives of jenkins-x.yml.
+
+		If the identity of the team build defaults binds the builds to a GKE Workload Identity, an EKS IAM role or an Azure managed identity, the builds run as the jx-build ServiceAccount which is written next to each build with the annotations of the identity so that the builds can push to cloud registries without long lived keys.
+
+		If the build defaults of the team configure a SonarQube server, the code of the projects is analysed before the first step which builds an image or chart using the Maven plugin for Maven projects or otherwise the SonarQube scanner with the properties of the language of the project. The quality gate of the analysis of a pull request is commented on the pull request by 'jx step sonar decorate'.
+
+		If the dependencyScan of jenkins-x.yml or of the team build defaults is enabled, the dependencies of the project are scanned for known vulnerabilities and license issues by OWASP dependency-check or snyk before the first step which builds an image or chart. The report is uploaded to the reportBucketUrl and the build fails if a vulnerability is at least as severe as the threshold. A project disables the dependency scan of the team with 'disabled: true'.
+
+		With --merge-steps or the mergeSteps of jenkins-x.yml, the consecutive steps which share their image, working directory, environment and volumes are merged into one step running their commands in turn with 'sh -e -c' so that a container is not started for each of them. Steps are kept apart by listing their names in the exclude of mergeSteps.
+
+		Besides the built in kinds of build the pipelineKinds of jenkins-x.yml or the pipeline-kinds.yml file of the build pack define custom kinds such as 'nightly', 'perf' or 'security'. A custom kind may have aliases, is only generated for the branches matching its branches expression and behaves like the kind it extends, using the build of that kind when the project has no build of its own. The built in kinds can also be referred to by aliases such as 'pr' for 'pullRequest'.
+
+		The feature builds derive the name of the feature from the branch naming convention such as 'feature/add-login' or the branchPrefixes of the feature section of jenkins-x.yml and pass it to the steps as $JX_FEATURE_NAME along with the branch in $JX_FEATURE_BRANCH. The steps of the feature section are added to the end of the feature builds so that each feature can be deployed to its own preview environment.
+
+		With --from-step a failed build is retried from the given step by skipping the steps before it, apart from the steps which prepare the workspace such as the step generating the Dockerfile. The retried build runs in a new workspace: the files, images and other outputs the skipped steps produced in the workspace of the failed build are not restored, so the steps from the given step onwards must not depend on them.
+
+		When the command is not in batch mode and runs in a terminal it prompts for the inputs which are missing such as where to write the builds, whether to apply them to the current cluster, the build pack of a project without builds and the kind of build to create. In batch mode it never prompts: the builds of all kinds are written to the standard output, the git commands fail rather than prompt for credentials and a project without builds or a build pack fails.
+
+		When the builds of jenkins-x.yml override the builds of the build pack the steps they override, add and remove are highlighted like a diff with ~, + and - in yellow, green and red. Use --no-color to disable the colors.
+
+		The pipelineSteps of the Extensions installed in the team such as Apps are added before or after the steps of each build so that pipeline capabilities such as code scanning can be installed for every project. Projects replace the step of an extension with a step of the same name.
+
+		Each executable on the PATH whose name starts with jx-taskgen- and each OCI image in the plugins of the team build defaults is run as a plugin in turn. A plugin receives the generated build as JSON on its standard input along with the kind of the build in $JX_TASKGEN_KIND and writes the mutated build as JSON or YAML to its standard output, or nothing to leave the build unchanged, so that company policies can be injected into the builds. Plugins are disabled with --no-plugins.
+
+		With --versions-dir, or $JX_VERSIONS_DIR, a warning is logged for each builder image of the pod templates of the builds which is behind the version stream checked out in that directory. 'jx upgrade builders' upgrades them.
+
+		The project configuration and the team settings are loaded concurrently followed by the pod template and the build packs of the project. With --verbose the time each of them and the generation of each build took is logged.
+
+		With --cache-ttl the team settings and pod templates are cached on disk for the given duration. Modifying the team settings or the pod templates with jx removes them from the cache.
+
+		With --bundle the build packs and pod templates are read from a pipeline bundle exported by 'jx step export pipelinebundle' rather than cloned from the internet and loaded from the cluster so that builds can be generated in clusters without internet access.
+
+		The git repositories such as the build packs are cloned through the gitProxy of the team build defaults when it is configured, authenticating with the proxy using Kerberos (negotiate), NTLM, basic or digest authentication. The username and password are read from the Secret named by its credentialsSecret while Kerberos uses the ticket of the user running the command when no Secret is configured.
+
+		With --strict-tls the command fails unless the Kubernetes API server, the git servers and the docker registry it connects to are accessed with TLS 1.2 or later and verified certificates and none of the generated steps disables TLS verification such as with --insecure-registry or --skip-tls-verify. The error names the offending endpoint or step.
+
+		The builds of a repository registered as a SourceRepository, or of the git repository of an Environment, are owned by that resource so that deleting it deletes its builds too. The builds then run in the development namespace unless --namespace or --env picks another namespace, in which case they have no owner as owners cannot be in another namespace.
+
+		The build pods run with the priorityClassName of the team build defaults so that builds on shared clusters are preempted before production workloads. When a ResourceQuota of the namespace of the builds limits cpu or memory, the steps which do not specify the requests or limits it requires, and which no LimitRange defaults, get them from the quotaResources of the team build defaults, falling back to the default resources, so that their pods are not rejected.
+
+		When the spot section of the team build defaults is enabled the build pods are scheduled on spot or preemptible nodes with its nodeSelector and tolerations. 'jx controller preemption' retries a preempted build pod up to maxRetries times from the step it was preempted in. Use --no-spot for pipelines such as releases which must not be preempted.
+
+		The autoscaling section of the team build defaults tunes the build pods for autoscaled build node pools: safeToEvict sets the cluster-autoscaler.kubernetes.io/safe-to-evict annotation so that build pods do not block scale downs, preferredNodeLabels prefers the nodes of the build node pool, spreadTopologyKey spreads the build pods across the nodes or zones and packBuilds packs them onto the nodes already running builds so that the other nodes empty out.
+
+		With --estimate the builds are not written. A table of the estimated duration and cost of each build is printed instead. The duration is the median of the successful runs of the pipeline of the branch, or of the other pipelines of the repository, from the history bucket of the team and the pipeline activities. Builds without history are estimated from their steps. The cost is that of the most CPU and memory any step requests for that long, priced with --cpu-cost and --memory-cost.
+
+		When the tracing section of the team build defaults is enabled each step gets the W3C trace context of its span in $TRACEPARENT along with $OTEL_SERVICE_NAME and the $OTEL_EXPORTER_OTLP_ENDPOINT of the team so that the tools the steps run report their spans as children of the step. 'jx controller tracing' exports the spans of the pipeline and its steps when the build completes. With wrapSteps the command of each step is run by 'jx step trace', which exports a span of the command.
+
+		The generated builds are annotated with the SHA and the URL of the commit, the URL of the pull request and the keys of the issues referenced by the message of the head commit or the name of the branch. The keys are in the format of the issueTracker of jenkins-x.yml or otherwise of the git provider. 'jx controller build' records the links on the PipelineActivity of each run and 'jx controller issues' comments the links to the completed runs on their issues.
+
+		If the issueTracker of jenkins-x.yml is JIRA and has a release section, a final jira-release step is added to the release builds which runs 'jx step jira release' to transition the issues referenced by the commits of the release to Released or Done and add the version to their fix versions.
+
+		With --repo the builds of one or more git repositories are generated in one run, cloning each repository at the ref after its '#'. The repositories can be hosted on different git providers such as GitHub, GitLab and BitBucket: each repository is cloned with the credentials of its git server in the git auth config and the API client of its git provider is created from the kind of its git server. The builds of each repository are written to the <host>/<owner>/<repository> directory of the output directory, which is required for more than one repository. The builds of the other repositories are still generated if one of them fails.
+
+		The command exits with a distinct exit code for each class of failure so that scripts can branch on it:
+
+		* 1 - any other failure
+		* 2 - a required option is missing or an option is invalid
+		* 3 - the build pack or pod template could not be found
+		* 4 - the cluster could not be reached
+		* 5 - the project configuration is not valid
+		* 6 - the builds could not be generated
+		* 130 - the command was interrupted by SIGINT or SIGTERM
+
+		When --timeout expires the command fails with exit code 4. The build packs being cloned when the command times out or is interrupted are removed so that the next invocation clones them again.
 `)
 
 	createBuildExample = templates.Examples(`
@@ -33,18 +111,135 @@ var (
 		# create a Knative build
 		jx step create build -o mybuild.yaml
 
+		# verify the cluster can run the builds before creating them
+		jx step create build --preflight
+
+		# create a Knative build which retries a failed build from the 'deploy' step skipping the previous steps
+		jx step create build --from-step deploy
+
+		# write the generated builds into the repository and create a Pull Request to review the changes
+		jx step create build --pr
+
+		# create the builds along with a JSON report describing where each step came from and how it was changed
+		jx step create build --output-dir out --explain
+
+		# create the builds of a pull request with the label 'skip-e2e' which skips the steps configured in jenkins-x.yml
+		jx step create build --branch PR-12 --pr-labels skip-e2e
+
+		# create the builds of a pull request which each run in their own namespace
+		jx step create build --branch PR-12 --isolated
+
+		# create the hardened builds of a pull request from a fork
+		jx step create build --branch PR-12 --trust-level untrusted --output-dir out
+
+		# create the builds along with NetworkPolicies which limit the build pods to the endpoints they require
+		jx step create build --output-dir out --network-policy
+
+		# create the build of a Terraform repository which plans the changes of pull request 12
+		jx step create build --kind infra --branch PR-12
+
+		# create the builds of a Go project which build its image with ko rather than a Dockerfile
+		jx step create build --image-builder ko
+
+		# create the builds from the build packs and pod templates of a pipeline bundle in a cluster without internet access
+		jx step create build --bundle pipelinebundle.tar.gz
+
+		# run the steps of the test lifecycle in the container of the maven-java11 pod template
+		jx step create build --container test=maven-java11
+
+		# create a partial build which only runs the steps of the test lifecycle to debug it
+		jx step create build --only-lifecycle test
+
 			`)
 )
 
+const (
+	defaultBuildEngineVersion = "v1alpha1"
+
+	// createBuildDefaultsFileName the file in the .jx directory of the repository or the user containing the
+	// default values of the flags
+	createBuildDefaultsFileName = "createbuild.yaml"
+)
+
 // StepCreateBuildOptions contains the command line flags
 type StepCreateBuildOptions struct {
 	StepOptions
 
-	Dir              string
-	OutputDir        string
-	OutputFilePrefix string
-	BranchKind       string
-	BuildNumber      int
+	Dir               string
+	OutputDir         string
+	OutputFilePrefix  string
+	BranchKind        string
+	BuildNumber       int
+	Preflight         bool
+	FromStep          string
+	StartStep         string
+	EndStep           string
+	OnlyLifecycles    []string
+	Branch            string
+	WorkflowPreset    string
+	NoAudit           bool
+	SignKey           string
+	Namespace         string
+	Environment       string
+	RegisterRepo      bool
+	Commit            bool
+	Push              bool
+	PullRequest       bool
+	Explain           bool
+	PullRequestLabels []string
+	Isolated          bool
+	IsolatedTTL       time.Duration
+	TrustLevel        string
+	NetworkPolicy     bool
+	ImageBuilder      string
+	PacksDir          string
+	VersionsDir       string
+
+	NoGenerateDockerfile bool
+	CommitDockerfile     bool
+	StrictConfig         bool
+	YAMLAnchors          bool
+	ConfigFile           string
+	NoPlugins            bool
+	MergeSteps           bool
+	CacheTTL             time.Duration
+	CacheDir             string
+	Timeout              time.Duration
+	Bundle               string
+	StrictTLS            bool
+	Containers           []string
+	NoColor              bool
+	NoSpot               bool
+	Estimate             bool
+	Repos                []string
+	CPUCost              float64
+	MemoryCost           float64
+
+	buildDefaults   *v1.BuildDefaults
+	quota           *quotaRequirements
+	buildPackURL    string
+	targetNamespace string
+	explanation     *BuildExplanation
+	labelConfigs    []*config.PullRequestLabelConfig
+	directives      []*config.CommitDirectiveConfig
+	links           *buildLinks
+	dockerRegistry  string
+	extensionSteps  []extensionStep
+
+	generatedDockerfile bool
+	documents           *documentWriter
+	podTemplates        map[string]*corev1.Pod
+	bundlePodTemplates  map[string]string
+	containerOverrides  map[string]string
+	applyBuilds         bool
+	ctx                 context.Context
+	cleanUpLock         sync.Mutex
+	cleanUps            map[int]func()
+	nextCleanUp         int
+
+	// createBuildCmd is the step create build command whose flags the defaults are applied to. The commands which
+	// generate builds with their own options have a different Cmd whose flags are left alone
+	createBuildCmd *cobra.Command
 }
 
 // NewCmdCreateBuild Creates a new Command object
@@ -67,6 +262,7 @@ func NewCmdCreateBuild(f Factory, in terminal.FileReader, out terminal.FileWrite
 		Example: createBuildExample,
 		Run: func(cmd *cobra.Command, args []string) {
 			options.Cmd = cmd
+			options.createBuildCmd = cmd
 			options.Args = args
 			err := options.Run()
 			CheckErr(err)
@@ -75,30 +271,256 @@ func NewCmdCreateBuild(f Factory, in terminal.FileReader, out terminal.FileWrite
 	options.addCommonFlags(cmd)
 
 	cmd.Flags().StringVarP(&options.Dir, "dir", "d", "", "The directory to query to find the projects .git directory")
-	cmd.Flags().StringVarP(&options.BranchKind, "kind", "k", "", "The kind of build such as 'release' or 'pullRequest' otherwise all of the builds are created")
+	cmd.Flags().StringVarP(&options.BranchKind, "kind", "k", "", "The kind of build such as 'release', 'pullRequest', 'infra' or a custom pipeline kind otherwise all of the builds are created")
+	addValueCompletion(cmd, "kind", completionValuesKinds)
 	cmd.Flags().IntVarP(&options.BuildNumber, "build-number", "n", 1, "Which build number to use. <= 0 are ignored")
 	cmd.Flags().StringVarP(&options.OutputDir, "output-dir", "o", "", "The directory where the generated build yaml files will be output to")
 	cmd.Flags().StringVarP(&options.OutputFilePrefix, "output-prefix", "p", "build-", "The file name prefix used in the generated build files if output-dir is enabled")
+	cmd.Flags().StringVarP(&options.Branch, "branch", "b", "", "The branch or tag being built which is used to find any branch specific builds. Defaults to $BRANCH_NAME or the current git branch")
+	cmd.Flags().StringVarP(&options.WorkflowPreset, "workflow", "w", "", fmt.Sprintf("The workflow preset which decides the kinds of build created for the branch and how they are versioned. Defaults to the workflowPreset in %s. Possible values: %s", config.ProjectConfigFileName, strings.Join(config.WorkflowPresetNames, ", ")))
+	cmd.Flags().StringVarP(&options.FromStep, "from-step", "", "", "The name of the step to start the build from; any previous steps are skipped. Used to retry a failed build from the step that failed. The workspace and outputs of the skipped steps are not restored so the step must not depend on them")
+	cmd.Flags().StringVarP(&options.StartStep, "start-step", "", "", "The name of the first step of the build; any previous steps are skipped. Used with --end-step to debug a single failing stage")
+	cmd.Flags().StringVarP(&options.EndStep, "end-step", "", "", "The name of the last step of the build; any later steps are skipped")
+	cmd.Flags().StringArrayVarP(&options.OnlyLifecycles, "only-lifecycle", "", nil, "Only generates the steps of the lifecycle such as 'test' whose steps are named after it such as 'test' or 'test-1'. Can be repeated for each lifecycle")
+	cmd.Flags().StringVarP(&options.Namespace, "namespace", "", "", "The namespace the builds run in. Defaults to the namespace of the environment or the current namespace")
+	cmd.Flags().StringVarP(&options.Environment, "env", "e", "", "The name of the environment such as 'staging' or 'production' whose namespace the builds run in")
+	cmd.Flags().StringVarP(&options.SignKey, "sign-key", "", "", fmt.Sprintf("The file containing the ASCII armored PGP private key used to sign the builds. The passphrase of the key is read from $%s", EnvSigningPassphrase))
+	cmd.Flags().StringArrayVarP(&options.PullRequestLabels, "pr-labels", "", nil, fmt.Sprintf("The labels of the pull request being built which skip steps or add parameters as configured by the pullRequestLabels of %s. Defaults to the labels of the pull request on the git provider", config.ProjectConfigFileName))
+	cmd.Flags().BoolVarP(&options.Explain, "explain", "", false, "Writes a JSON report next to each generated build describing where each step came from and how it was changed")
+	cmd.Flags().BoolVarP(&options.Isolated, "isolated", "", false, "Runs each build in a new namespace created by the first step and deleted by the last step so that builds of untrusted pull requests cannot interfere with each other")
+	cmd.Flags().DurationVarP(&options.IsolatedTTL, "isolated-ttl", "", defaultIsolatedTTL, "The duration after which 'jx gc namespaces' deletes the namespace of an isolated build which failed")
+	cmd.Flags().StringVarP(&options.TrustLevel, "trust-level", "", trustLevelTrusted, fmt.Sprintf("The trust level of the builds. Builds of untrusted pull requests such as pull requests from forks have no secrets or privileges, run as the restricted service account of the team and have a NetworkPolicy limiting their egress. Possible values: %s", strings.Join(trustLevels, ", ")))
+	cmd.Flags().BoolVarP(&options.NetworkPolicy, "network-policy", "", false, "Writes a NetworkPolicy next to each generated build which limits the build pods to DNS, the Kubernetes API, the git server, the docker registry, the artifact repository and the egressEndpoints of the team build defaults")
+	cmd.Flags().StringVarP(&options.ImageBuilder, "image-builder", "", "", fmt.Sprintf("The tool which builds the image of the application instead of the Dockerfile. Defaults to the imageBuilder of %s or the build pack. Possible values: %s", config.ProjectConfigFileName, strings.Join(config.ImageBuilders, ", ")))
+	cmd.Flags().StringVarP(&options.PacksDir, "packs-dir", "", "", "The directory containing the build packs. Defaults to the build packs of the team")
+	cmd.Flags().StringVarP(&options.VersionsDir, "versions-dir", "", "", "A local checkout of the version stream which the builder images of the pod templates are checked against, warning about the images which are behind it")
+	cmd.Flags().StringVarP(&options.ConfigFile, "config", "", "", fmt.Sprintf("The %s file to generate the builds from or - to read it from the standard input. Defaults to the file in the directory", config.ProjectConfigFileName))
+	cmd.Flags().BoolVarP(&options.NoGenerateDockerfile, "no-generate-dockerfile", "", false, "Disables generating the Dockerfile of the build pack when the project has none")
+	cmd.Flags().BoolVarP(&options.CommitDockerfile, "commit-dockerfile", "", false, "Writes the generated Dockerfile of the build pack into the project, committing it with --commit, --push or --pr, rather than generating it in a step of the build")
+	cmd.Flags().BoolVarP(&options.StrictConfig, "strict-config", "", true, "Fails if the jenkins-x.yml file contains keys which are not configuration fields such as misspelled keys rather than ignoring them")
+	cmd.Flags().BoolVarP(&options.YAMLAnchors, "yaml-anchors", "", false, "Emits the values repeated across the steps of the generated builds such as their env once using YAML anchors and aliases")
+	cmd.Flags().BoolVarP(&options.RegisterRepo, "register-repo", "", false, "Creates or updates the SourceRepository resource of the git repository so it is included in the inventory of CI enabled repositories")
+	cmd.Flags().BoolVarP(&options.Commit, "commit", "", false, fmt.Sprintf("Writes the generated builds into the repository, defaulting to the pipelinesDir of %s or %s, and commits them", config.ProjectConfigFileName, config.DefaultPipelinesDir))
+	cmd.Flags().BoolVarP(&options.Push, "push", "", false, "Commits the generated builds and pushes the commit")
+	cmd.Flags().BoolVarP(&options.PullRequest, "pr", "", false, "Commits the generated builds on a new branch, pushes it and creates a Pull Request")
+	cmd.Flags().BoolVarP(&options.NoAudit, "no-audit", "", false, "Disables recording the generated builds in the pipeline audit log")
+	cmd.Flags().BoolVarP(&options.NoPlugins, "no-plugins", "", false, "Disables running the jx-taskgen- plugins on the PATH and the plugins of the team on the generated builds")
+	cmd.Flags().BoolVarP(&options.MergeSteps, "merge-steps", "", false, fmt.Sprintf("Merges the consecutive steps which run in the same container into one step to save the startup of a container per step. Defaults to the mergeSteps of %s", config.ProjectConfigFileName))
+	cmd.Flags().DurationVarP(&options.CacheTTL, "cache-ttl", "", 0, "How long the team settings and pod templates loaded from the cluster are cached on disk so that repeated invocations in the same job do not load them again. Set $JX_CACHE_TTL to enable it for a whole job. Disabled by default")
+	cmd.Flags().StringVarP(&options.CacheDir, "cache-dir", "", "", "The directory of the cached team settings and pod templates. Defaults to ~/.jx/cache/createbuild")
+	cmd.Flags().DurationVarP(&options.Timeout, "timeout", "", 0, "The maximum time to generate the builds after which the command fails even if a call to the cluster or git server hangs. Defaults to no timeout")
+	cmd.Flags().StringVarP(&options.Bundle, "bundle", "", "", "The pipeline bundle file or directory exported by 'jx step export pipelinebundle' whose build packs and pod templates are used rather than the build packs of the team and the pod templates of the cluster")
+	cmd.Flags().BoolVarP(&options.StrictTLS, "strict-tls", "", false, "Fails unless the cluster, git servers and docker registry are accessed with TLS 1.2 or later and verified certificates and the generated steps do not disable TLS verification. Set $JX_STRICT_TLS to enable it for a whole job")
+	cmd.Flags().StringArrayVarP(&options.Repos, "repo", "", nil, "Clones the git repository such as 'https://gitlab.com/myorg/myapp.git' or 'https://github.com/myorg/myapp.git#develop' at the optional ref and generates its builds instead of the builds of the current directory. Can be repeated for repositories on different git providers in which case --output-dir is required")
+	cmd.Flags().StringArrayVarP(&options.Containers, "container", "", nil, "Overrides the container of the steps of a lifecycle with the first container of a pod template such as 'test=maven-java11'. The steps of a lifecycle are named after it such as 'test' or 'test-1'. Can be repeated for each lifecycle")
+	cmd.Flags().BoolVarP(&options.NoColor, "no-color", "", false, "Disables the colors of the console output such as the highlighting of the steps of the build pack which the builds of jenkins-x.yml override")
+	cmd.Flags().BoolVarP(&options.NoSpot, "no-spot", "", false, "Schedules the build pods on the regular nodes even when the spot nodes of the team build defaults are enabled such as for release pipelines which must not be preempted")
+	cmd.Flags().BoolVarP(&options.Estimate, "estimate", "", false, "Prints the estimated duration and cost of the builds from the history of the pipelines, or the heuristics of their steps, instead of writing them")
+	cmd.Flags().Float64VarP(&options.CPUCost, "cpu-cost", "", defaultCPUCost, "The cost in dollars of a CPU core for an hour used by --estimate")
+	cmd.Flags().Float64VarP(&options.MemoryCost, "memory-cost", "", defaultMemoryCost, "The cost in dollars of a GiB of memory for an hour used by --estimate")
+	cmd.Flags().BoolVarP(&options.Preflight, "preflight", "", false, "Verifies the cluster has the CRDs, service accounts, secrets and storage class required by the builds before creating them")
 	return cmd
 }
 
 // Run implements this command
 func (o *StepCreateBuildOptions) Run() error {
-	pc, _, err := config.LoadProjectConfig(o.Dir)
+	err := o.applyFlagDefaults()
 	if err != nil {
 		return err
 	}
+	if o.NoColor {
+		color.NoColor = true
+	}
+	if len(o.Repos) > 0 {
+		return o.runWithContext(o.createRepositoryBuilds)
+	}
+	return o.runWithContext(o.createBuilds)
+}
 
-	// TODO load the build pack jenkins-x to add any default build kinds?
+// createBuilds generates the builds of the project
+func (o *StepCreateBuildOptions) createBuilds() error {
+	err := o.runOutputWizard()
+	if err != nil {
+		return err
+	}
+	if o.OutputDir == "" {
+		// the generated resources are written to the standard output so that they can be piped into kubectl
+		defer log.SetOutput(os.Stderr)()
+	}
+	if o.TrustLevel != "" && util.StringArrayIndex(trustLevels, o.TrustLevel) < 0 {
+		return util.InvalidOption("trust-level", o.TrustLevel, trustLevels)
+	}
+	o.containerOverrides, err = parseContainerOverrides(o.Containers)
+	if err != nil {
+		return err
+	}
+	if o.Bundle != "" {
+		removeBundle, err := o.loadPipelineBundle()
+		if err != nil {
+			return util.WithExitCode(err, util.ExitCodeValidation)
+		}
+		defer removeBundle()
+	}
+	pc, teamSettings, err := o.loadProjectAndTeam()
+	if err != nil {
+		return err
+	}
+	o.buildDefaults = &teamSettings.BuildDefaults
+	o.buildPackURL = teamSettings.BuildPackURL
+	err = o.configureGitProxy(&teamSettings.BuildDefaults.GitProxy)
+	if err != nil {
+		return err
+	}
+	o.disableGitPrompts()
+	o.extensionSteps, err = o.loadExtensionSteps()
+	if err != nil {
+		return util.WithExitCode(errors.Wrap(err, "failed to load the pipeline steps of the extensions"), util.ExitCodeUnreachable)
+	}
+	o.targetNamespace, err = o.resolveTargetNamespace()
+	if err != nil {
+		return err
+	}
+	buildOwner, ownerNs := o.resolveBuildOwner()
+	if buildOwner != nil && o.targetNamespace == "" {
+		// the builds run in the namespace of their owner as owners cannot be in another namespace
+		o.targetNamespace = ownerNs
+	}
+	o.quota = o.loadQuotaRequirements()
+	o.dockerRegistry, err = o.dockerRegistryHost()
+	if err != nil {
+		return util.WithExitCode(err, util.ExitCodeUnreachable)
+	}
+	if o.StrictTLS {
+		err = o.verifyStrictTLSEndpoints(pc)
+		if err != nil {
+			return err
+		}
+	}
+	var egress []networkingv1.NetworkPolicyEgressRule
+	if o.NetworkPolicy {
+		egress, err = o.networkPolicyEgress(teamSettings)
+		if err != nil {
+			return util.WithExitCode(err, util.ExitCodeUnreachable)
+		}
+	}
 
-	for _, branchBuild := range pc.Builds {
-		if o.BranchKind != "" && branchBuild.Kind != o.BranchKind {
-			continue
+	if o.Preflight {
+		podTemplate, err := o.loadPodTemplate(pc.BuildPack)
+		if err != nil {
+			return err
 		}
-		build, err := o.generateBuild(pc, branchBuild)
+		err = o.runPreflightChecks(podTemplate)
 		if err != nil {
 			return err
 		}
+	}
+
+	err = o.runProjectWizard(pc)
+	if err != nil {
+		return err
+	}
+	if pc.BuildPack == "" && len(pc.Builds) == 0 {
+		return util.WithExitCode(fmt.Errorf("No builds or build pack are defined in %s. Add the builds or the buildPack of the project to it", config.ProjectConfigFileName), util.ExitCodeValidation)
+	}
+	o.prefetchBuildPack(pc)
+	o.reportPackOverrides(pc)
+	err = o.applyPackBuilds(pc)
+	if err != nil {
+		return util.WithExitCode(err, util.ExitCodeGeneration)
+	}
+	err = o.applyPackPipelineKinds(pc)
+	if err != nil {
+		return util.WithExitCode(err, util.ExitCodeValidation)
+	}
+
+	o.defaultCommitOutputDir(pc)
+	branch := o.currentBranch()
+	message := o.headCommitMessage()
+	if config.SkipsCI(message) {
+		log.Infof("Not creating any builds as the head commit contains a %s directive\n", util.ColorInfo("[skip ci]"))
+		return nil
+	}
+	o.directives = pc.CommitDirectivesFor(message)
+	for _, d := range o.directives {
+		log.Infof("Applying commit directive %s\n", util.ColorInfo(d.Directive))
+	}
+	o.links = o.resolveBuildLinks(pc, branch, message)
+	branchBuilds, err := pc.BuildsForBranch(branch)
+	if err != nil {
+		return err
+	}
+	branchBuilds, err = applyPipelineKinds(branchBuilds, pc, branch)
+	if err != nil {
+		return err
+	}
+	workflowPreset := o.WorkflowPreset
+	if workflowPreset == "" {
+		workflowPreset = pc.WorkflowPreset
+	}
+	if workflowPreset != "" {
+		branchBuilds, err = applyWorkflowPreset(branchBuilds, pc, workflowPreset, branch)
+		if err != nil {
+			return err
+		}
+	}
+	branchBuilds = applyFeatureBranch(branchBuilds, pc, branch)
+	branchBuilds, err = applyInfraBuilds(branchBuilds, pc, branch)
+	if err != nil {
+		return err
+	}
+	branchBuilds, err = applyBazelBuilds(branchBuilds, pc, &o.buildDefaults.Bazel)
+	if err != nil {
+		return err
+	}
+	labels, err := o.resolvePullRequestLabels(pc, branch)
+	if err != nil {
+		return util.WithExitCode(err, util.ExitCodeUnreachable)
+	}
+	o.labelConfigs = pc.PullRequestLabelsFor(labels)
+	for _, l := range o.labelConfigs {
+		log.Infof("Applying pull request label %s\n", util.ColorInfo(l.Label))
+	}
+	var runs []history.PipelineRun
+	estimates := []*BuildEstimate{}
+	if o.Estimate {
+		runs = o.loadRunHistory(branch)
+	}
+	kinds := []string{}
+	branchKind := pc.CanonicalKind(o.BranchKind)
+	for _, branchBuild := range branchBuilds {
+		if branchKind != "" && branchBuild.Kind != branchKind {
+			continue
+		}
+		kinds = append(kinds, branchBuild.Kind)
+		if o.Explain {
+			o.explanation = newBuildExplanation(pc, branchBuild, branch)
+		}
+		var build *Build
+		err = o.timed("generating the "+branchBuild.Kind+" build", func() error {
+			build, err = o.generateBuild(pc, branchBuild)
+			return err
+		})()
+		if err != nil {
+			return util.WithExitCode(err, util.ExitCodeGeneration)
+		}
+		if buildOwner != nil {
+			build.OwnerReferences = append(build.OwnerReferences, *buildOwner)
+		}
+		applyBuildLinks(build, o.links)
+		build, err = o.applyTaskgenPlugins(build, branchBuild.Kind)
+		if err != nil {
+			return util.WithExitCode(err, util.ExitCodeGeneration)
+		}
+		if o.Estimate {
+			estimates = append(estimates, o.estimateBuild(build, branchBuild.Kind, runs))
+			continue
+		}
+		if o.SignKey != "" {
+			err = o.signBuild(build, pc)
+			if err != nil {
+				return err
+			}
+		}
 		data, err := yaml.Marshal(build)
 		if err != nil {
 			return err
@@ -106,6 +528,15 @@ func (o *StepCreateBuildOptions) Run() error {
 		if data == nil {
 			return fmt.Errorf("Could not marshal build to yaml")
 		}
+		if o.YAMLAnchors {
+			data, err = anchorStepValues(data)
+			if err != nil {
+				return errors.Wrap(err, "failed to anchor the repeated values of the steps")
+			}
+		}
+		if !o.NoAudit {
+			o.recordPipelineAudit(pc, branchBuild, data)
+		}
 
 		outDir := o.OutputDir
 		if outDir != "" {
@@ -119,12 +550,228 @@ func (o *StepCreateBuildOptions) Run() error {
 				return err
 			}
 		} else {
-			log.Info(string(data))
+			err = o.writeDocument(data)
+			if err != nil {
+				return err
+			}
+		}
+		if o.NetworkPolicy {
+			err = o.writeGeneratedResource(newNetworkPolicy(build, egress), outDir, "networkpolicy-"+branchBuild.Kind+".yml")
+		} else if o.TrustLevel == trustLevelUntrusted {
+			err = o.writeGeneratedResource(untrustedNetworkPolicy(build), outDir, "networkpolicy-"+branchBuild.Kind+".yml")
+		}
+		if err != nil {
+			return err
+		}
+		if o.TrustLevel != trustLevelUntrusted && hasBuildIdentity(&o.buildDefaults.Identity) {
+			err = o.writeGeneratedResource(buildIdentityServiceAccount(&o.buildDefaults.Identity, build.Namespace), outDir, "serviceaccount-"+branchBuild.Kind+".yml")
+			if err != nil {
+				return err
+			}
+		}
+		if o.explanation != nil {
+			err = o.writeExplanation(o.explanation, outDir)
+			if err != nil {
+				return err
+			}
 		}
 	}
+	if o.Estimate {
+		o.printEstimates(estimates)
+		return nil
+	}
+	if o.RegisterRepo {
+		err = o.registerSourceRepository(pc, kinds)
+		if err != nil {
+			return err
+		}
+	}
+	if o.commitMode() {
+		err = o.commitBuilds()
+	} else if o.applyBuilds {
+		err = o.applyGeneratedBuilds()
+	}
 	return err
 }
 
+// loadProjectConfig loads the project configuration from the --config file, the standard input if it is - or the
+// directory of the project
+func (o *StepCreateBuildOptions) loadProjectConfig() (*config.ProjectConfig, error) {
+	if o.ConfigFile == "" {
+		loadProjectConfig := config.LoadProjectConfig
+		if o.StrictConfig {
+			loadProjectConfig = config.LoadProjectConfigStrict
+		}
+		pc, _, err := loadProjectConfig(o.Dir)
+		return pc, err
+	}
+	var data []byte
+	var err error
+	if o.ConfigFile == "-" {
+		var in io.Reader = o.In
+		if o.In == nil {
+			in = os.Stdin
+		}
+		data, err = ioutil.ReadAll(in)
+	} else {
+		data, err = ioutil.ReadFile(o.ConfigFile)
+	}
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to read the project configuration %s", o.ConfigFile)
+	}
+	pc, err := config.ParseProjectConfig(data, o.StrictConfig)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to parse the project configuration %s", o.ConfigFile)
+	}
+	return pc, nil
+}
+
+// writeDocument writes a generated resource to the standard output separating it from the resources written before
+// it so that the output can be piped into 'kubectl apply -f -'
+func (o *StepCreateBuildOptions) writeDocument(data []byte) error {
+	if o.documents == nil {
+		out := o.Out
+		if out == nil {
+			out = os.Stdout
+		}
+		o.documents = &documentWriter{out: out}
+	}
+	return o.documents.writeDocument(data)
+}
+
+// applyFlagDefaults sets the flags not specified on the command line to their environment variables or the defaults of
+// the user and the repository. Nothing is applied when the builds are generated by another command
+func (o *StepCreateBuildOptions) applyFlagDefaults() error {
+	if o.createBuildCmd == nil || o.createBuildCmd != o.Cmd {
+		return nil
+	}
+	err := ApplyFlagEnvVars(o.Cmd, "JX")
+	if err != nil {
+		return err
+	}
+	configDir, err := util.ConfigDir()
+	if err != nil {
+		return err
+	}
+	dir := o.Dir
+	if dir == "" {
+		dir = "."
+	}
+	return ApplyFlagDefaults(o.Cmd, filepath.Join(configDir, createBuildDefaultsFileName), filepath.Join(dir, ".jx", createBuildDefaultsFileName))
+}
+
+// flagChanged returns true if the flag of the step create build command was specified
+func (o *StepCreateBuildOptions) flagChanged(name string) bool {
+	return o.createBuildCmd != nil && o.createBuildCmd == o.Cmd && o.Cmd.Flags().Changed(name)
+}
+
+// registerSourceRepository creates or updates the SourceRepository of the git repository recording the kinds of
+// pipelines generated for it. The SourceRepository CRD is not registered as the generator never registers CRDs
+func (o *StepCreateBuildOptions) registerSourceRepository(projectConfig *config.ProjectConfig, kinds []string) error {
+	dir := o.Dir
+	if dir == "" {
+		dir = "."
+	}
+	gitInfo, err := o.FindGitInfo(dir)
+	if err != nil {
+		return errors.Wrap(err, "failed to find the git repository to register")
+	}
+	jxClient, ns, err := o.JXClientAndDevNamespace()
+	if err != nil {
+		return err
+	}
+	repo, err := kube.GetOrCreateSourceRepository(jxClient, ns, kube.NewSourceRepository(gitInfo, projectConfig.BuildPack, kinds))
+	if err != nil {
+		return err
+	}
+	log.Infof("Registered SourceRepository %s\n", util.ColorInfo(repo.Name))
+	return nil
+}
+
+// applyWorkflowPreset returns the builds of the kinds the workflow preset generates for the branch along with the
+// environment variables which control how they are versioned. Feature builds default to the steps of the release build.
+// The builds of the custom pipeline kinds of the project are kept as they are
+func applyWorkflowPreset(branchBuilds []*config.BranchBuild, projectConfig *config.ProjectConfig, name string, branch string) ([]*config.BranchBuild, error) {
+	preset, err := config.GetWorkflowPreset(name)
+	if err != nil {
+		return branchBuilds, err
+	}
+	kinds, err := preset.KindsForBranch(branch)
+	if err != nil {
+		return branchBuilds, err
+	}
+	answer := []*config.BranchBuild{}
+	for _, k := range kinds {
+		var found *config.BranchBuild
+		for _, b := range branchBuilds {
+			if b.Kind == k.Kind {
+				found = b
+				break
+			}
+			if k.Kind == config.BuildKindFeature && b.Kind == config.BuildKindRelease && found == nil {
+				found = b
+			}
+		}
+		if found == nil {
+			continue
+		}
+		b := *found
+		b.Kind = k.Kind
+		b.Env = append(append([]corev1.EnvVar{}, found.Env...), k.EnvVars()...)
+		answer = append(answer, &b)
+	}
+	for _, b := range branchBuilds {
+		if projectConfig.PipelineKind(b.Kind) != nil {
+			answer = append(answer, b)
+		}
+	}
+	return answer, nil
+}
+
+// resolveTargetNamespace returns the namespace the builds run in from the namespace or environment options. Returns
+// an empty string if neither are specified so the builds run in the namespace they are applied to
+func (o *StepCreateBuildOptions) resolveTargetNamespace() (string, error) {
+	if o.Namespace != "" {
+		if o.Environment != "" {
+			return "", fmt.Errorf("Cannot specify both the namespace and env options")
+		}
+		return o.Namespace, nil
+	}
+	if o.Environment == "" {
+		return "", nil
+	}
+	jxClient, devNs, err := o.JXClientAndDevNamespace()
+	if err != nil {
+		return "", err
+	}
+	env, err := kube.GetEnvironment(jxClient, devNs, o.Environment)
+	if err != nil {
+		names, _ := kube.GetEnvironmentNames(jxClient, devNs)
+		return "", util.InvalidOption("env", o.Environment, names)
+	}
+	if env.Spec.Namespace == "" {
+		return "", fmt.Errorf("The environment %s has no namespace", o.Environment)
+	}
+	return env.Spec.Namespace, nil
+}
+
+// currentBranch returns the branch or tag being built
+func (o *StepCreateBuildOptions) currentBranch() string {
+	branch := o.Branch
+	if branch == "" {
+		branch = os.Getenv("BRANCH_NAME")
+	}
+	if branch == "" {
+		dir := o.Dir
+		if dir == "" {
+			dir = "."
+		}
+		// ignore errors as the project may not be in a git repository
+		branch, _ = o.Git().Branch(dir)
+	}
+	return branch
+}
+
 func (o *StepCreateBuildOptions) generateBuild(projectConfig *config.ProjectConfig, build *config.BranchBuild) (*Build, error) {
 	dir := o.Dir
 	var err error
@@ -141,13 +788,22 @@ func (o *StepCreateBuildOptions) generateBuild(projectConfig *config.ProjectConf
 		buildName = buildName + strconv.Itoa(buildNumber)
 	}
 	steps := []corev1.Container{}
+	defaults := o.buildDefaults
+	if defaults == nil {
+		defaults = &v1.BuildDefaults{}
+	}
+	engineVersion := defaults.EngineVersion
+	if engineVersion == "" {
+		engineVersion = defaultBuildEngineVersion
+	}
 	answer := &Build{
 		TypeMeta: metav1.TypeMeta{
-			APIVersion: "build.knative.dev/v1alpha1",
+			APIVersion: "build.knative.dev/" + engineVersion,
 			Kind:       "Build",
 		},
 		ObjectMeta: metav1.ObjectMeta{
-			Name: kube.ToValidName(buildName),
+			Name:      kube.ToValidName(buildName),
+			Namespace: o.targetNamespace,
 		},
 		Spec: BuildSpec{
 			Steps: steps,
@@ -161,15 +817,34 @@ func (o *StepCreateBuildOptions) generateBuild(projectConfig *config.ProjectConf
 	if err != nil {
 		return answer, err
 	}
+	explain := o.explanation
+	toolVersions, err := config.DetectToolVersions(dir)
+	if err != nil {
+		return answer, err
+	}
+	toolImage, tool := resolveToolImage(defaults.ToolImages, toolVersions)
 	for _, step := range build.Build.Steps {
 		step2 := step
-		if step2.Image == "" {
+		explain.explainSteps([]corev1.Container{step2}, explain.buildSource())
+		if step2.Image != "" {
+			explain.explainImage(step2.Name, "the step")
+		}
+		if step2.Image == "" && defaultImage != "" {
 			step2.Image = defaultImage
+			explain.explainImage(step2.Name, "the previous step")
+		}
+		if step2.Image == "" && toolImage != "" {
+			step2.Image = toolImage
+			explain.explainImage(step2.Name, "the tool images of the build defaults for %s %s", tool, toolVersions[tool])
+		}
+		if step2.Image == "" && projectConfig.BuildPack == "" {
+			step2.Image = defaults.DefaultImage
+			explain.explainImage(step2.Name, "the default image of the build defaults")
 		}
 		if step2.Image == "" {
 			buildPack := projectConfig.BuildPack
 			if buildPack == "" {
-				return answer, fmt.Errorf("No build pack defined in the configuration file: %s", config.ProjectConfigFileName)
+				return answer, util.WithExitCode(fmt.Errorf("No build pack defined in the configuration file: %s", config.ProjectConfigFileName), util.ExitCodeValidation)
 			}
 			containers := podTemplate.Spec.Containers
 			if len(containers) > 0 {
@@ -178,47 +853,274 @@ func (o *StepCreateBuildOptions) generateBuild(projectConfig *config.ProjectConf
 			if step2.Image == "" {
 				return answer, fmt.Errorf("No container image defined in the pod template for build pack %s", buildPack)
 			}
+			explain.explainImage(step2.Name, "the pod template of build pack %s", buildPack)
 		}
 		if step2.Image != "" {
 			defaultImage = step2.Image
 		}
+		stepPodTemplate, err := o.overrideContainer(&step2, podTemplate, explain)
+		if err != nil {
+			return answer, err
+		}
 
-		err = o.addCommonSettings(&step2, projectConfig, build, podTemplate)
+		envCount, mountCount := len(step2.Env), len(step2.VolumeMounts)
+		err = o.addCommonSettings(&step2, projectConfig, build, stepPodTemplate)
 		if err != nil {
 			return answer, err
 		}
+		if len(step2.Env) != envCount || len(step2.VolumeMounts) != mountCount {
+			explain.explainMutation(step2.Name, "added the environment variables and volume mounts of the build and pod template")
+		}
+		image := step2.Image
+		applyBuildDefaults(&step2, defaults)
+		if step2.Image != image {
+			explain.explainMutation(step2.Name, "replaced image %s with mirror %s", image, step2.Image)
+		}
+		helmCommandLine := append(append([]string{}, step2.Command...), step2.Args...)
+		if isHelmDeploy(helmCommandLine) {
+			migrations := migrationSteps(&step2, build.Migrations, helmCommandLine, projectName)
+			explain.explainSteps(migrations, explain.buildSource()+".migrations")
+			steps = append(steps, migrations...)
+		}
+		deploySteps, err := deploymentStrategySteps(&step2, projectConfig, projectName)
+		if err != nil {
+			return answer, err
+		}
+		if len(deploySteps) > 0 {
+			explain.explainSteps(deploySteps, fmt.Sprintf("the %s deployment strategy", projectConfig.DeploymentStrategy))
+			explain.explainMutation(step2.Name, "deployed via the %s deployment strategy", projectConfig.DeploymentStrategy)
+		}
+		if !projectConfig.DisableChartValues {
+			args := strings.Join(step2.Args, " ")
+			injectChartValues(&step2, projectName)
+			if strings.Join(step2.Args, " ") != args {
+				explain.explainMutation(step2.Name, "added the image and version chart values")
+			}
+		}
 
 		steps = append(steps, step2)
+		steps = append(steps, deploySteps...)
+
+		if len(build.PostDeploy) > 0 && isHelmDeploy(helmCommandLine) {
+			postDeploySteps := []corev1.Container{}
+			for _, postDeployStep := range build.PostDeploy {
+				postStep := postDeployStep
+				if postStep.Image == "" {
+					postStep.Image = step2.Image
+				}
+				err = o.addCommonSettings(&postStep, projectConfig, build, podTemplate)
+				if err != nil {
+					return answer, err
+				}
+				applyBuildDefaults(&postStep, defaults)
+				postDeploySteps = append(postDeploySteps, postStep)
+			}
+			err = rollbackOnFailure(postDeploySteps, helmCommandLine, projectConfig.DeploymentStrategy)
+			if err != nil {
+				return answer, err
+			}
+			explain.explainSteps(postDeploySteps, explain.buildSource()+".postDeploy")
+			for _, postStep := range postDeploySteps {
+				explain.explainMutation(postStep.Name, "rolls back the release deployed by step %s if it fails", step2.Name)
+			}
+			steps = append(steps, postDeploySteps...)
+		}
+	}
+	imageBuilder, err := resolveImageBuilder(o.ImageBuilder, projectConfig, podTemplate)
+	if err != nil {
+		return answer, err
+	}
+	steps, err = o.applyPackDockerfile(steps, imageBuilder, defaults.BuildpacksBuilder, projectConfig.BuildPack, dir, explain)
+	if err != nil {
+		return answer, err
+	}
+	steps, err = applyImageBuilder(steps, imageBuilder, dir, defaults.BuildpacksBuilder, o.dockerRegistry, projectName, explain)
+	if err != nil {
+		return answer, err
+	}
+	steps = applySonarSteps(steps, &defaults.SonarQube, projectConfig, dir, projectName, o.currentBranch(), explain)
+	steps, err = applyDependencyScan(steps, &defaults.DependencyScan, projectConfig, explain)
+	if err != nil {
+		return answer, err
+	}
+	if projectConfig.Library != nil {
+		library := *projectConfig.Library
+		if len(projectConfig.Dependents) > 0 {
+			// the dependents are updated by the update-dependents step instead
+			library.DisableVersionPR = true
+		}
+		previous := append([]corev1.Container{}, steps...)
+		steps, err = applyLibraryPack(steps, &library, projectConfig.BaseKind(build.Kind))
+		if err != nil {
+			return answer, err
+		}
+		explain.explainNewSteps(previous, steps, "the library pack")
+	}
+	if len(projectConfig.Dependents) > 0 && projectConfig.BaseKind(build.Kind) == config.BuildKindRelease && len(steps) > 0 {
+		last := steps[len(steps)-1]
+		steps = append(steps, createStepFrom(&last, "update-dependents", []string{"jx", "step", "update", "dependents"}))
+		explain.explainNewSteps(steps[:len(steps)-1], steps, config.ProjectConfigFileName+" dependents")
+	}
+	if tracker := projectConfig.IssueTracker; tracker != nil && tracker.Kind == issues.Jira && tracker.Release != nil && projectConfig.BaseKind(build.Kind) == config.BuildKindRelease && len(steps) > 0 {
+		last := steps[len(steps)-1]
+		steps = append(steps, createStepFrom(&last, jiraReleaseStepName, []string{"jx", "step", "jira", "release"}))
+		explain.explainNewSteps(steps[:len(steps)-1], steps, config.ProjectConfigFileName+" issueTracker release")
+	}
+	if len(projectConfig.Triggers) > 0 && projectConfig.BaseKind(build.Kind) == config.BuildKindRelease && len(steps) > 0 {
+		last := steps[len(steps)-1]
+		steps = append(steps, createStepFrom(&last, "chain", []string{"jx", "step", "chain", "--pipeline", strings.Join(projectConfig.Triggers, ",")}))
+		explain.explainNewSteps(steps[:len(steps)-1], steps, config.ProjectConfigFileName+" triggers")
+	}
+	steps, err = o.applyExtensionSteps(steps, projectConfig, build, podTemplate, defaults, explain)
+	if err != nil {
+		return answer, err
+	}
+	previous := append([]corev1.Container{}, steps...)
+	steps, err = addApprovalSteps(steps, build.Approvals)
+	if err != nil {
+		return answer, err
+	}
+	explain.explainNewSteps(previous, steps, explain.buildSource()+".approvals")
+	steps = applyPullRequestLabels(steps, o.labelConfigs, explain)
+	steps = applyCommitDirectives(steps, o.directives)
+	steps, err = applyFlakyTests(steps, projectConfig.FlakyTests, explain)
+	if err != nil {
+		return answer, err
+	}
+	steps, err = o.filterSteps(steps, build.Approvals)
+	if err != nil {
+		return answer, err
+	}
+	if o.Isolated {
+		previous := steps
+		namespace := isolatedNamespace(projectName, o.currentBranch(), build.Kind, buildNumber)
+		steps = isolateSteps(steps, namespace, o.IsolatedTTL)
+		explain.explainNewSteps(previous, steps, "--isolated")
+	}
+	previous = steps
+	steps, vaultVolume, err := resolveVaultReferences(steps, defaults.VaultAddress, explain)
+	if err != nil {
+		return answer, err
+	}
+	if vaultVolume != nil {
+		answer.Spec.Volumes = append(answer.Spec.Volumes, *vaultVolume)
+		explain.explainNewSteps(previous, steps, "the Vault references of the environment variables")
+	}
+	if o.TrustLevel != trustLevelUntrusted {
+		previous = steps
+		steps = ensureECRRepository(steps, o.dockerRegistry, projectName)
+		var loginVolume *corev1.Volume
+		steps, loginVolume = addRegistryLogin(steps, o.dockerRegistry)
+		if loginVolume != nil {
+			answer.Spec.Volumes = append(answer.Spec.Volumes, *loginVolume)
+			explain.explainNewSteps(previous, steps, "the "+o.dockerRegistry+" registry")
+		}
+	}
+	steps, err = applyStepMutators(steps, &StepMutatorContext{
+		ProjectName:   projectName,
+		Kind:          build.Kind,
+		ProjectConfig: projectConfig,
+		BuildDefaults: defaults,
+	}, explain)
+	if err != nil {
+		return answer, err
+	}
+	if o.MergeSteps || (projectConfig.MergeSteps != nil && projectConfig.MergeSteps.Enabled) {
+		steps = mergeSteps(steps, projectConfig.MergeSteps, explain)
+	}
+	if o.StrictTLS {
+		err = verifyStrictTLSSteps(steps)
+		if err != nil {
+			return answer, err
+		}
 	}
+	applyQuotaDefaults(steps, o.quota, defaults, explain)
 	answer.Spec.Steps = steps
-	return answer, nil
+	applyPriorityClass(answer, defaults)
+	if !o.NoSpot {
+		applySpot(answer, &defaults.Spot, explain)
+	}
+	applyAutoscaling(answer, &defaults.Autoscaling)
+	err = applyTracing(answer, &defaults.Tracing, projectName, explain)
+	if err != nil {
+		return answer, err
+	}
+	if o.TrustLevel == trustLevelUntrusted {
+		secretVolumes := secretVolumeNames(build.Build.Volumes)
+		if podTemplate != nil {
+			secretVolumes = append(secretVolumes, secretVolumeNames(podTemplate.Spec.Volumes)...)
+		}
+		hardenUntrustedBuild(answer, secretVolumes, defaults.UntrustedServiceAccount, explain)
+	} else if hasBuildIdentity(&defaults.Identity) {
+		answer.Spec.ServiceAccountName = buildServiceAccount
+	}
+	explain.orderSteps(steps)
+	err = applyBudget(answer, projectConfig.BudgetForBuild(build), projectName)
+	return answer, err
+}
+
+// applyBudget encodes the budget into the timeout of the build and the annotations which 'jx controller budget'
+// uses to cancel build pods which exceed the budget
+func applyBudget(build *Build, budget *config.BudgetConfig, pipeline string) error {
+	if budget == nil {
+		return nil
+	}
+	err := budget.Validate()
+	if err != nil {
+		return err
+	}
+	if budget.MaxDuration == "" && budget.MaxParallelPods == 0 {
+		return nil
+	}
+	if build.Labels == nil {
+		build.Labels = map[string]string{}
+	}
+	if build.Annotations == nil {
+		build.Annotations = map[string]string{}
+	}
+	build.Labels[kube.LabelPipeline] = kube.ToValidName(pipeline)
+	if budget.MaxDuration != "" {
+		build.Spec.Timeout = budget.MaxDuration
+		build.Annotations[kube.AnnotationMaxDuration] = budget.MaxDuration
+	}
+	if budget.MaxParallelPods > 0 {
+		build.Annotations[kube.AnnotationMaxParallelPods] = strconv.Itoa(budget.MaxParallelPods)
+	}
+	return nil
 }
 
 func (o *StepCreateBuildOptions) loadPodTemplate(buildPack string) (*corev1.Pod, error) {
 	if buildPack == "" {
 		return nil, nil
 	}
-	answer := &corev1.Pod{}
-
-	kubeClient, ns, err := o.KubeClientAndDevNamespace()
-	if err != nil {
-		return answer, err
+	if podTemplate, ok := o.podTemplates[buildPack]; ok {
+		return podTemplate, nil
 	}
-	configMapName := kube.ConfigMapJenkinsPodTemplates
-	cm, err := kubeClient.CoreV1().ConfigMaps(ns).Get(configMapName, metav1.GetOptions{})
-	if err != nil {
-		return answer, errors.Wrapf(err, "failed to find ConfigMap %s in namespace %s", configMapName, ns)
+	data := o.bundlePodTemplates
+	if data == nil {
+		kubeClient, ns, err := o.KubeClientAndDevNamespace()
+		if err != nil {
+			return &corev1.Pod{}, util.WithExitCode(err, util.ExitCodeUnreachable)
+		}
+		progress := log.StartProgress(os.Stderr, o.BatchMode, "Loading the pod template %s", buildPack)
+		defer progress.Stop()
+		data, err = o.loadPodTemplatesData(kubeClient, ns)
+		if err != nil {
+			return nil, util.WithExitCode(err, util.ExitCodeNotFound)
+		}
 	}
-
-	podTemplateYaml := ""
-	if cm.Data != nil {
-		podTemplateYaml = cm.Data[buildPack]
+	// only the pod template of the build pack and the ones it extends are parsed so that an invalid pod template
+	// of another build pack does not break the build
+	podTemplate, err := kube.ParsePodTemplateNamed(data, buildPack)
+	if err != nil {
+		return podTemplate, util.WithExitCode(err, util.ExitCodeNotFound)
 	}
-	if podTemplateYaml == "" {
-		return answer, fmt.Errorf("No pod template is defiend in ConfigMap %s for build pack %s", configMapName, buildPack)
+	if o.podTemplates == nil {
+		o.podTemplates = map[string]*corev1.Pod{}
 	}
-	err = yaml.Unmarshal([]byte(podTemplateYaml), answer)
-	return answer, err
+	o.podTemplates[buildPack] = podTemplate
+	o.warnOutdatedBuilders(buildPack, podTemplate)
+	return podTemplate, nil
 }
 
 func (o *StepCreateBuildOptions) addCommonSettings(container *corev1.Container, projectConfig *config.ProjectConfig, branchBuild *config.BranchBuild, podTemplate *corev1.Pod) error {
@@ -234,8 +1136,11 @@ func (o *StepCreateBuildOptions) addCommonSettings(container *corev1.Container,
 			c := containers[0]
 			if !branchBuild.ExcludePodTemplateEnv {
 				for _, env := range c.Env {
+					if o.buildDefaults != nil && len(o.buildDefaults.EnvAllowList) > 0 && util.StringArrayIndex(o.buildDefaults.EnvAllowList, env.Name) < 0 {
+						continue
+					}
 					if kube.GetEnvVar(container, env.Name) == nil {
-						container.Env = append(c.Env, env)
+						container.Env = append(container.Env, env)
 					}
 				}
 			}
@@ -258,6 +1163,70 @@ func (o *StepCreateBuildOptions) addCommonSettings(container *corev1.Container,
 	return nil
 }
 
+// addApprovalSteps inserts a step before each step requiring approval which waits until the approval gate is approved
+func addApprovalSteps(steps []corev1.Container, approvals []*config.ApprovalConfig) ([]corev1.Container, error) {
+	for _, approval := range approvals {
+		if approval == nil {
+			continue
+		}
+		if approval.Name == "" {
+			return steps, fmt.Errorf("No name defined for the approval before step %s", approval.Before)
+		}
+		idx := -1
+		for i, step := range steps {
+			if step.Name == approval.Before {
+				idx = i
+				break
+			}
+		}
+		if idx < 0 {
+			return steps, fmt.Errorf("The approval %s is before step %s which does not exist", approval.Name, approval.Before)
+		}
+		image := approval.Image
+		if image == "" {
+			image = steps[idx].Image
+		}
+		args := []string{"step", "approval", "--name", approval.Name}
+		if len(approval.Approvers) > 0 {
+			args = append(args, "--approvers", strings.Join(approval.Approvers, ","))
+		}
+		if approval.Timeout != "" {
+			args = append(args, "--timeout", approval.Timeout)
+		}
+		step := corev1.Container{
+			Name:    "approval-" + approval.Name,
+			Image:   image,
+			Command: []string{"jx"},
+			Args:    args,
+			Env:     steps[idx].Env,
+		}
+		steps = append(steps[:idx], append([]corev1.Container{step}, steps[idx:]...)...)
+	}
+	return steps, nil
+}
+
+// applyBuildDefaults applies the team build defaults such as image mirrors and default resources to the given step
+func applyBuildDefaults(container *corev1.Container, defaults *v1.BuildDefaults) {
+	container.Image = mirrorImage(container.Image, defaults.ImageMirrors)
+	if defaults.Resources != nil && len(container.Resources.Requests) == 0 && len(container.Resources.Limits) == 0 {
+		container.Resources = *defaults.Resources.DeepCopy()
+	}
+}
+
+// mirrorImage replaces the longest prefix of the image which has a mirror with that mirror
+func mirrorImage(image string, mirrors map[string]string) string {
+	match := ""
+	for prefix := range mirrors {
+		if prefix != "" && len(prefix) > len(match) && strings.HasPrefix(image, prefix) {
+			match = prefix
+		}
+	}
+	if match == "" {
+		return image
+	}
+	return mirrors[match] + strings.TrimPrefix(image, match)
+}
+
 // TODO replace with the actual Knative build vendored ASAP!
 // +genclient
 // +genclient:noStatus
@@ -297,6 +1266,9 @@ type BuildSpec struct {
 	// The name of the service account as which to run this build.
 	ServiceAccountName string `json:"serviceAccountName,omitempty"`
 
+	// Timeout the maximum duration of the build such as '30m'
+	Timeout string `json:"timeout,omitempty"`
+
 	// Template, if specified, references a BuildTemplate resource to use to
 	// populate fields in the build, and optional Arguments to pass to the
 	// template.
@@ -307,6 +1279,18 @@ type BuildSpec struct {
 	// More info: https://kubernetes.io/docs/concepts/configuration/assign-pod-node/
 	// +optional
 	NodeSelector map[string]string `json:"nodeSelector,omitempty"`
+
+	// PriorityClassName the PriorityClass of the pod of the build
+	// +optional
+	PriorityClassName string `json:"priorityClassName,omitempty"`
+
+	// Tolerations the tolerations of the pod of the build such as of the taints of spot nodes
+	// +optional
+	Tolerations []corev1.Toleration `json:"tolerations,omitempty"`
+
+	// Affinity the scheduling constraints of the pod of the build
+	// +optional
+	Affinity *corev1.Affinity `json:"affinity,omitempty"`
 }
 
 // TemplateInstantiationSpec specifies how a BuildTemplate is instantiated into
@@ -0,0 +1,211 @@
+package cmd
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+
+	"gopkg.in/yaml.v2"
+)
+
+// yamlAnchor a value which is emitted with an anchor so that later identical values refer to it with an alias
+type yamlAnchor struct {
+	name  string
+	value interface{}
+}
+
+// yamlAlias a value which is emitted as an alias of an earlier anchored value
+type yamlAlias struct {
+	name string
+}
+
+// anchorStepValues rewrites the YAML of a build so that the values of step fields such as env and volumeMounts
+// which are repeated across steps are emitted once with an anchor and referenced by an alias from the other steps.
+// The YAML loads into the same build but is much smaller and shows the differences between steps in reviews
+func anchorStepValues(data []byte) ([]byte, error) {
+	doc := yaml.MapSlice{}
+	err := yaml.Unmarshal(data, &doc)
+	if err != nil {
+		return nil, err
+	}
+	spec, ok := mapSliceValue(doc, "spec").(yaml.MapSlice)
+	if !ok {
+		return data, nil
+	}
+	steps, ok := mapSliceValue(spec, "steps").([]interface{})
+	if !ok {
+		return data, nil
+	}
+
+	// count the steps using each value of each field
+	counts := map[string]int{}
+	keys := make([][]string, len(steps))
+	for i, s := range steps {
+		step, ok := s.(yaml.MapSlice)
+		if !ok {
+			continue
+		}
+		keys[i] = make([]string, len(step))
+		for j, item := range step {
+			if !isYAMLCollection(item.Value) {
+				continue
+			}
+			text, err := yaml.Marshal(item.Value)
+			if err != nil {
+				return nil, err
+			}
+			keys[i][j] = fmt.Sprintf("%v\n%s", item.Key, text)
+			counts[keys[i][j]]++
+		}
+	}
+
+	anchors := map[string]string{}
+	for i, s := range steps {
+		step, ok := s.(yaml.MapSlice)
+		if !ok {
+			continue
+		}
+		for j, item := range step {
+			key := keys[i][j]
+			if key == "" || counts[key] < 2 {
+				continue
+			}
+			if name, ok := anchors[key]; ok {
+				step[j].Value = yamlAlias{name: name}
+				continue
+			}
+			name := fmt.Sprintf("%v-%d", item.Key, len(anchors)+1)
+			anchors[key] = name
+			step[j].Value = yamlAnchor{name: name, value: item.Value}
+		}
+	}
+	if len(anchors) == 0 {
+		return data, nil
+	}
+	var buf bytes.Buffer
+	err = writeYAMLMap(&buf, doc, 0, false)
+	if err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// mapSliceValue returns the value of the key of the map or nil if there is none
+func mapSliceValue(m yaml.MapSlice, key string) interface{} {
+	for _, item := range m {
+		if item.Key == key {
+			return item.Value
+		}
+	}
+	return nil
+}
+
+func isYAMLCollection(value interface{}) bool {
+	switch v := value.(type) {
+	case yaml.MapSlice:
+		return len(v) > 0
+	case []interface{}:
+		return len(v) > 0
+	default:
+		return false
+	}
+}
+
+// writeYAMLMap writes the entries of a map at the indent. If inline the first entry continues the current line such
+// as after the dash of a sequence item
+func writeYAMLMap(buf *bytes.Buffer, m yaml.MapSlice, indent int, inline bool) error {
+	for i, item := range m {
+		if i > 0 || !inline {
+			buf.WriteString(strings.Repeat(" ", indent))
+		}
+		key, err := yamlScalar(item.Key, indent)
+		if err != nil {
+			return err
+		}
+		buf.WriteString(key + ":")
+		err = writeYAMLValue(buf, item.Value, indent)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// writeYAMLValue writes the value of a key of a map at the indent of the key
+func writeYAMLValue(buf *bytes.Buffer, value interface{}, indent int) error {
+	switch v := value.(type) {
+	case yamlAlias:
+		buf.WriteString(" *" + v.name + "\n")
+		return nil
+	case yamlAnchor:
+		buf.WriteString(" &" + v.name)
+		return writeYAMLValue(buf, v.value, indent)
+	case yaml.MapSlice:
+		if len(v) == 0 {
+			buf.WriteString(" {}\n")
+			return nil
+		}
+		buf.WriteString("\n")
+		return writeYAMLMap(buf, v, indent+2, false)
+	case []interface{}:
+		if len(v) == 0 {
+			buf.WriteString(" []\n")
+			return nil
+		}
+		buf.WriteString("\n")
+		return writeYAMLSequence(buf, v, indent)
+	default:
+		text, err := yamlScalar(v, indent+2)
+		if err != nil {
+			return err
+		}
+		buf.WriteString(" " + text + "\n")
+		return nil
+	}
+}
+
+// writeYAMLSequence writes the items of a sequence at the indent
+func writeYAMLSequence(buf *bytes.Buffer, items []interface{}, indent int) error {
+	for _, item := range items {
+		buf.WriteString(strings.Repeat(" ", indent) + "-")
+		switch v := item.(type) {
+		case yaml.MapSlice:
+			if len(v) == 0 {
+				buf.WriteString(" {}\n")
+				continue
+			}
+			buf.WriteString(" ")
+			err := writeYAMLMap(buf, v, indent+2, true)
+			if err != nil {
+				return err
+			}
+		case []interface{}:
+			if len(v) == 0 {
+				buf.WriteString(" []\n")
+				continue
+			}
+			buf.WriteString("\n")
+			err := writeYAMLSequence(buf, v, indent+2)
+			if err != nil {
+				return err
+			}
+		default:
+			text, err := yamlScalar(v, indent+2)
+			if err != nil {
+				return err
+			}
+			buf.WriteString(" " + text + "\n")
+		}
+	}
+	return nil
+}
+
+// yamlScalar returns the YAML of a scalar indenting the continuation lines of multi-line scalars
+func yamlScalar(value interface{}, indent int) (string, error) {
+	data, err := yaml.Marshal(value)
+	if err != nil {
+		return "", err
+	}
+	text := strings.TrimSuffix(string(data), "\n")
+	return strings.Replace(text, "\n", "\n"+strings.Repeat(" ", indent), -1), nil
+}
@@ -0,0 +1,84 @@
+package cmd
+
+import (
+	"net/url"
+	"os"
+	"os/user"
+	"path/filepath"
+	"strings"
+
+	"github.com/jenkins-x/jx/pkg/config"
+	"github.com/jenkins-x/jx/pkg/kube"
+	"github.com/jenkins-x/jx/pkg/log"
+	"github.com/jenkins-x/jx/pkg/util"
+)
+
+// recordPipelineAudit records the generated build in the pipeline audit log. Failures are only logged so that
+// builds can still be generated when the audit log is unavailable
+func (o *StepCreateBuildOptions) recordPipelineAudit(projectConfig *config.ProjectConfig, branchBuild *config.BranchBuild, data []byte) {
+	dir := o.Dir
+	if dir == "" {
+		dir, _ = os.Getwd()
+	}
+	_, pipeline := filepath.Split(dir)
+	record := &kube.PipelineAuditRecord{
+		Pipeline:     pipeline,
+		Kind:         branchBuild.Kind,
+		Action:       kube.PipelineAuditActionGenerate,
+		BuildPack:    projectConfig.BuildPack,
+		BuildPackURL: projectConfig.BuildPackGitURL,
+		OutputHash:   kube.HashPipelineOutput(data),
+		User:         currentUsername(),
+	}
+	gitInfo, err := o.FindGitInfo(dir)
+	if err == nil && gitInfo != nil {
+		record.SourceURL = gitInfo.URL
+	}
+	record.SourceSHA, _ = o.Git().GetLatestCommitSha(dir)
+
+	if record.BuildPackURL == "" {
+		record.BuildPackURL = o.buildPackURL
+	}
+	buildPackDir := buildPackCloneDir(record.BuildPackURL)
+	if buildPackDir != "" {
+		record.BuildPackSHA, _ = o.Git().GetLatestCommitSha(buildPackDir)
+	}
+
+	kubeClient, ns, err := o.KubeClientAndDevNamespace()
+	if err == nil {
+		err = kube.SavePipelineAuditRecord(kubeClient, ns, record)
+	}
+	if err != nil {
+		log.Warnf("Failed to record the generated %s build of %s in the pipeline audit log: %s\n", branchBuild.Kind, pipeline, err)
+	}
+}
+
+// currentUsername returns the name of the current user or an empty string if it cannot be found
+func currentUsername() string {
+	u, err := user.Current()
+	if err != nil {
+		return ""
+	}
+	return u.Username
+}
+
+// buildPackCloneDir returns the local clone of the build pack git repository created by 'jx init' if it exists
+func buildPackCloneDir(gitURL string) string {
+	if gitURL == "" {
+		return ""
+	}
+	u, err := url.Parse(strings.TrimSuffix(gitURL, ".git"))
+	if err != nil {
+		return ""
+	}
+	draftDir, err := util.DraftDir()
+	if err != nil {
+		return ""
+	}
+	dir := filepath.Join(draftDir, "packs", u.Host, u.Path)
+	exists, err := util.FileExists(filepath.Join(dir, ".git"))
+	if err != nil || !exists {
+		return ""
+	}
+	return dir
+}
@@ -0,0 +1,97 @@
+package cmd
+
+import (
+	"sort"
+	"strconv"
+
+	"github.com/jenkins-x/jx/pkg/apis/jenkins.io/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+const (
+	// annotationSafeToEvict the annotation which tells the cluster autoscaler whether it may evict a pod to remove its node
+	annotationSafeToEvict = "cluster-autoscaler.kubernetes.io/safe-to-evict"
+
+	// hostnameTopologyKey the node label which the build pods are packed onto the same nodes with
+	hostnameTopologyKey = "kubernetes.io/hostname"
+
+	autoscalingAffinityWeight = 100
+)
+
+// applyAutoscaling adds the annotations and affinities of the team build defaults which help the cluster autoscaler
+// scale the build node pools up and down to the build
+func applyAutoscaling(build *Build, autoscaling *v1.BuildAutoscaling) {
+	if autoscaling.SafeToEvict != nil {
+		if build.Annotations == nil {
+			build.Annotations = map[string]string{}
+		}
+		build.Annotations[annotationSafeToEvict] = strconv.FormatBool(*autoscaling.SafeToEvict)
+	}
+	if len(autoscaling.PreferredNodeLabels) > 0 {
+		keys := []string{}
+		for k := range autoscaling.PreferredNodeLabels {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+		requirements := []corev1.NodeSelectorRequirement{}
+		for _, k := range keys {
+			requirements = append(requirements, corev1.NodeSelectorRequirement{
+				Key:      k,
+				Operator: corev1.NodeSelectorOpIn,
+				Values:   []string{autoscaling.PreferredNodeLabels[k]},
+			})
+		}
+		affinity := buildAffinity(build)
+		if affinity.NodeAffinity == nil {
+			affinity.NodeAffinity = &corev1.NodeAffinity{}
+		}
+		affinity.NodeAffinity.PreferredDuringSchedulingIgnoredDuringExecution = append(affinity.NodeAffinity.PreferredDuringSchedulingIgnoredDuringExecution, corev1.PreferredSchedulingTerm{
+			Weight: autoscalingAffinityWeight,
+			Preference: corev1.NodeSelectorTerm{
+				MatchExpressions: requirements,
+			},
+		})
+	}
+	if autoscaling.SpreadTopologyKey != "" {
+		affinity := buildAffinity(build)
+		if affinity.PodAntiAffinity == nil {
+			affinity.PodAntiAffinity = &corev1.PodAntiAffinity{}
+		}
+		affinity.PodAntiAffinity.PreferredDuringSchedulingIgnoredDuringExecution = append(affinity.PodAntiAffinity.PreferredDuringSchedulingIgnoredDuringExecution, buildPodAffinityTerm(autoscaling.SpreadTopologyKey))
+	}
+	if autoscaling.PackBuilds {
+		affinity := buildAffinity(build)
+		if affinity.PodAffinity == nil {
+			affinity.PodAffinity = &corev1.PodAffinity{}
+		}
+		affinity.PodAffinity.PreferredDuringSchedulingIgnoredDuringExecution = append(affinity.PodAffinity.PreferredDuringSchedulingIgnoredDuringExecution, buildPodAffinityTerm(hostnameTopologyKey))
+	}
+}
+
+// buildAffinity returns the affinity of the build creating it if it has none
+func buildAffinity(build *Build) *corev1.Affinity {
+	if build.Spec.Affinity == nil {
+		build.Spec.Affinity = &corev1.Affinity{}
+	}
+	return build.Spec.Affinity
+}
+
+// buildPodAffinityTerm returns the preferred term matching the build pods on the nodes with the same value of the
+// topology key
+func buildPodAffinityTerm(topologyKey string) corev1.WeightedPodAffinityTerm {
+	return corev1.WeightedPodAffinityTerm{
+		Weight: autoscalingAffinityWeight,
+		PodAffinityTerm: corev1.PodAffinityTerm{
+			LabelSelector: &metav1.LabelSelector{
+				MatchExpressions: []metav1.LabelSelectorRequirement{
+					{
+						Key:      labelKnativeBuildName,
+						Operator: metav1.LabelSelectorOpExists,
+					},
+				},
+			},
+			TopologyKey: topologyKey,
+		},
+	}
+}
@@ -0,0 +1,85 @@
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/jenkins-x/jx/pkg/apis/jenkins.io/v1"
+	"github.com/jenkins-x/jx/pkg/config"
+	corev1 "k8s.io/api/core/v1"
+)
+
+// applyBazelBuilds generates the Bazel steps of the release and pull request builds of a Bazel workspace which do not
+// define their own steps. Every build builds and tests the targets of the build and test lifecycles and release
+// builds then run the targets of the release lifecycle
+func applyBazelBuilds(branchBuilds []*config.BranchBuild, projectConfig *config.ProjectConfig, remote *v1.BazelRemote) ([]*config.BranchBuild, error) {
+	bazel := projectConfig.Bazel
+	if bazel == nil {
+		return branchBuilds, nil
+	}
+	answer := []*config.BranchBuild{}
+	for _, b := range branchBuilds {
+		if projectConfig.BaseKind(b.Kind) == config.BuildKindInfra || len(b.Build.Steps) > 0 {
+			answer = append(answer, b)
+			continue
+		}
+		if bazel.Image == "" && projectConfig.BuildPack == "" {
+			return branchBuilds, fmt.Errorf("The builds of a Bazel workspace require either the %s build pack or a bazel image in %s", config.BazelBuildPack, config.ProjectConfigFileName)
+		}
+		bazelBuild := *b
+		bazelBuild.Build.Steps = bazelSteps(bazel, remote, projectConfig.BaseKind(b.Kind) == config.BuildKindRelease)
+		answer = append(answer, &bazelBuild)
+	}
+	return answer, nil
+}
+
+// bazelSteps returns the steps of the lifecycles of a Bazel workspace
+func bazelSteps(bazel *config.BazelConfig, remote *v1.BazelRemote, release bool) []corev1.Container {
+	flags := append(bazelRemoteFlags(remote), bazel.Flags...)
+	step := func(name string, command string, targets ...string) corev1.Container {
+		args := append(append([]string{"bazel", command}, flags...), targets...)
+		return corev1.Container{
+			Name:  name,
+			Image: bazel.Image,
+			Args:  args,
+		}
+	}
+	targetsOrDefault := func(targets []string) []string {
+		if len(targets) == 0 {
+			return []string{config.DefaultBazelTargets}
+		}
+		return targets
+	}
+	steps := []corev1.Container{
+		step("bazel-build", "build", targetsOrDefault(bazel.Build)...),
+		step("bazel-test", "test", targetsOrDefault(bazel.Test)...),
+	}
+	if !release {
+		return steps
+	}
+	// bazel run only runs a single target at a time
+	for i, target := range bazel.Release {
+		steps = append(steps, step(fmt.Sprintf("bazel-release-%d", i+1), "run", target))
+	}
+	return steps
+}
+
+// bazelRemoteFlags returns the flags of the bazel commands which use the remote cache and executor of the team
+func bazelRemoteFlags(remote *v1.BazelRemote) []string {
+	flags := []string{}
+	if remote == nil {
+		return flags
+	}
+	if remote.RemoteCache != "" {
+		flags = append(flags, "--remote_cache="+remote.RemoteCache)
+	}
+	if remote.RemoteExecutor != "" {
+		flags = append(flags, "--remote_executor="+remote.RemoteExecutor)
+	}
+	if remote.InstanceName != "" {
+		flags = append(flags, "--remote_instance_name="+remote.InstanceName)
+	}
+	if remote.GoogleCredentials && (remote.RemoteCache != "" || remote.RemoteExecutor != "") {
+		flags = append(flags, "--google_default_credentials")
+	}
+	return flags
+}
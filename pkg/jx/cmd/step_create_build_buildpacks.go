@@ -0,0 +1,36 @@
+package cmd
+
+import (
+	"fmt"
+
+	corev1 "k8s.io/api/core/v1"
+)
+
+const (
+	// buildpacksCreator the lifecycle binary of the Cloud Native Buildpacks builder images which detects, builds and
+	// exports the image of the application in a single container
+	buildpacksCreator = "/cnb/lifecycle/creator"
+	// buildpacksStepName the name of the step which builds the image with Cloud Native Buildpacks
+	buildpacksStepName = "buildpacks"
+)
+
+// applyBuildpacks replaces the steps which build the image of a project without a Dockerfile with a step which
+// builds and pushes the image with the Cloud Native Buildpacks builder image of the team so that projects build
+// without maintaining a Dockerfile
+func applyBuildpacks(steps []corev1.Container, dir string, builder string, registry string, appName string, explain *BuildExplanation) []corev1.Container {
+	if builder == "" || hasDockerfile(dir) {
+		return steps
+	}
+	return replaceImageBuildSteps(steps, buildpacksStep(builder, registry, appName), "the buildpacks builder of the build defaults as there is no Dockerfile", explain)
+}
+
+// buildpacksStep returns the step which builds and pushes the image with the given builder image
+func buildpacksStep(builder string, registry string, appName string) corev1.Container {
+	script := fmt.Sprintf(`%s -app="$PWD" "%s:${VERSION}"`, buildpacksCreator, imageRepository(registry, appName))
+	return corev1.Container{
+		Name:    buildpacksStepName,
+		Image:   builder,
+		Command: []string{"/bin/sh"},
+		Args:    []string{"-c", script},
+	}
+}
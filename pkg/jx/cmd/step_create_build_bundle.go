@@ -0,0 +1,62 @@
+package cmd
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+
+	"github.com/ghodss/yaml"
+	"github.com/jenkins-x/jx/pkg/log"
+	"github.com/jenkins-x/jx/pkg/util"
+	"github.com/pkg/errors"
+)
+
+const (
+	// pipelineBundleBuildPacksDir the directory of a pipeline bundle containing the build packs
+	pipelineBundleBuildPacksDir = "buildpacks"
+	// pipelineBundlePodTemplatesFile the file of a pipeline bundle containing the data of the pod templates ConfigMap
+	pipelineBundlePodTemplatesFile = "podtemplates.yml"
+	// pipelineBundleImagesFile the file of a pipeline bundle listing the images which have to be mirrored
+	pipelineBundleImagesFile = "images.txt"
+)
+
+// loadPipelineBundle uses the build packs and pod templates of the --bundle rather than the build packs of the team and
+// the pod templates of the cluster. A bundle file is extracted into a workspace which is removed by the returned
+// function once the builds are generated
+func (o *StepCreateBuildOptions) loadPipelineBundle() (func(), error) {
+	remove := func() {}
+	dir := o.Bundle
+	info, err := os.Stat(dir)
+	if err != nil {
+		return remove, errors.Wrapf(err, "failed to find the pipeline bundle %s", o.Bundle)
+	}
+	if !info.IsDir() {
+		dir, err = util.CreateWorkspace("jx-pipelinebundle-")
+		if err != nil {
+			return remove, err
+		}
+		remove = func() {
+			os.RemoveAll(dir)
+		}
+		err = util.UnTargzDir(o.Bundle, dir)
+		if err != nil {
+			remove()
+			return func() {}, errors.Wrapf(err, "failed to extract the pipeline bundle %s", o.Bundle)
+		}
+	}
+	data, err := ioutil.ReadFile(filepath.Join(dir, pipelineBundlePodTemplatesFile))
+	if err == nil {
+		podTemplates := map[string]string{}
+		err = yaml.Unmarshal(data, &podTemplates)
+		o.bundlePodTemplates = podTemplates
+	}
+	if err != nil {
+		remove()
+		return func() {}, errors.Wrapf(err, "failed to load the pod templates of the pipeline bundle %s", o.Bundle)
+	}
+	if o.PacksDir == "" {
+		o.PacksDir = filepath.Join(dir, pipelineBundleBuildPacksDir)
+	}
+	log.Infof("Using the build packs and %d pod templates of the pipeline bundle %s\n", len(o.bundlePodTemplates), util.ColorInfo(o.Bundle))
+	return remove, nil
+}
@@ -0,0 +1,203 @@
+package cmd
+
+import (
+	"crypto/sha256"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/jenkins-x/jx/pkg/apis/jenkins.io/v1"
+	"github.com/jenkins-x/jx/pkg/kube"
+	"github.com/jenkins-x/jx/pkg/log"
+	"github.com/jenkins-x/jx/pkg/util"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+)
+
+const (
+	// clusterCacheDirName the directory of the cache of jx which the cluster resources are cached in
+	clusterCacheDirName = "createbuild"
+
+	teamSettingsCacheKind = "teamsettings"
+	podTemplatesCacheKind = "podtemplates"
+)
+
+// clusterCacheEntry a cluster resource cached on disk
+type clusterCacheEntry struct {
+	// Fetched when the resource was last fetched from the cluster
+	Fetched time.Time `json:"fetched"`
+	// Data the JSON of the cached data of the resource
+	Data json.RawMessage `json:"data"`
+}
+
+// loadTeamSettings returns the team settings from the cache when it is enabled and fresh. The team settings are only
+// read so that the builds can be generated without the permissions to register CRDs or create the Development
+// environment. The default team settings are used if there is no cluster or Development environment
+func (o *StepCreateBuildOptions) loadTeamSettings() (*v1.TeamSettings, error) {
+	teamSettings := &v1.TeamSettings{}
+	jxClient, ns, err := o.JXClientAndDevNamespace()
+	if err != nil {
+		if o.Verbose {
+			log.Warnf("Using the default team settings as there is no cluster: %s\n", err)
+		}
+		defaultTeamSettings(teamSettings)
+		return teamSettings, nil
+	}
+	fetch := func() (interface{}, error) {
+		env, err := jxClient.JenkinsV1().Environments(ns).Get(kube.LabelValueDevEnvironment, metav1.GetOptions{})
+		if err != nil {
+			if apierrors.IsNotFound(err) {
+				if o.Verbose {
+					log.Warnf("Using the default team settings as there is no Development environment in namespace %s\n", ns)
+				}
+				return &v1.TeamSettings{}, nil
+			}
+			return nil, err
+		}
+		return &env.Spec.TeamSettings, nil
+	}
+	if o.CacheTTL <= 0 {
+		value, err := fetch()
+		if err != nil {
+			return nil, err
+		}
+		teamSettings = value.(*v1.TeamSettings)
+	} else {
+		err = o.cachedClusterResource(teamSettingsCacheKind, ns, teamSettings, fetch)
+		if err != nil {
+			return nil, err
+		}
+	}
+	defaultTeamSettings(teamSettings)
+	return teamSettings, nil
+}
+
+// loadPodTemplatesData returns the unparsed pod templates of the team keyed by name from the cache when it is enabled
+// and fresh so that only the pod templates which are used need to be parsed
+func (o *StepCreateBuildOptions) loadPodTemplatesData(kubeClient kubernetes.Interface, ns string) (map[string]string, error) {
+	fetch := func() (interface{}, error) {
+		cm, err := kubeClient.CoreV1().ConfigMaps(ns).Get(kube.ConfigMapJenkinsPodTemplates, metav1.GetOptions{})
+		if err != nil {
+			return nil, fmt.Errorf("failed to find ConfigMap %s in namespace %s: %s", kube.ConfigMapJenkinsPodTemplates, ns, err)
+		}
+		return cm.Data, nil
+	}
+	if o.CacheTTL <= 0 {
+		value, err := fetch()
+		if err != nil {
+			return nil, err
+		}
+		return value.(map[string]string), nil
+	}
+	data := map[string]string{}
+	err := o.cachedClusterResource(podTemplatesCacheKind, ns, &data, fetch)
+	if err != nil {
+		return nil, err
+	}
+	return data, nil
+}
+
+// cachedClusterResource unmarshals the cached data of the resource into the result if it was fetched within the
+// --cache-ttl, otherwise it fetches the resource and caches it
+func (o *StepCreateBuildOptions) cachedClusterResource(kind string, ns string, result interface{}, fetch func() (interface{}, error)) error {
+	fileName, err := o.clusterCacheFile(kind, ns)
+	if err != nil {
+		return err
+	}
+	entry := &clusterCacheEntry{}
+	data, err := ioutil.ReadFile(fileName)
+	if err == nil && json.Unmarshal(data, entry) == nil {
+		if time.Since(entry.Fetched) < o.CacheTTL && json.Unmarshal(entry.Data, result) == nil {
+			if o.Verbose {
+				log.Infof("Using the %s of namespace %s cached %s ago\n", kind, ns, time.Since(entry.Fetched).Round(time.Second))
+			}
+			return nil
+		}
+	}
+	value, err := fetch()
+	if err != nil {
+		return err
+	}
+	entry.Fetched = time.Now()
+	entry.Data, err = json.Marshal(value)
+	if err != nil {
+		return err
+	}
+	err = json.Unmarshal(entry.Data, result)
+	if err != nil {
+		return err
+	}
+	data, err = json.Marshal(entry)
+	if err != nil {
+		return err
+	}
+	return ioutil.WriteFile(fileName, data, DefaultWritePermissions)
+}
+
+// clusterCacheFile returns the file which caches the resource of the namespace of the current cluster
+func (o *StepCreateBuildOptions) clusterCacheFile(kind string, ns string) (string, error) {
+	dir := o.CacheDir
+	if dir == "" {
+		var err error
+		dir, err = clusterCacheDir()
+		if err != nil {
+			return "", err
+		}
+	}
+	err := os.MkdirAll(dir, util.DefaultWritePermissions)
+	if err != nil {
+		return "", err
+	}
+	server := ""
+	if o.Factory != nil {
+		config, err := o.Factory.CreateKubeConfig()
+		if err == nil && config != nil {
+			server = config.Host
+		}
+	}
+	return filepath.Join(dir, clusterCacheFileName(kind, server, ns)), nil
+}
+
+// clusterCacheDir returns the default directory of the cached cluster resources
+func clusterCacheDir() (string, error) {
+	cacheDir, err := util.CacheDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(cacheDir, clusterCacheDirName), nil
+}
+
+func clusterCacheFileName(kind string, server string, ns string) string {
+	return fmt.Sprintf("%s-%x-%s.json", kind, sha256.Sum256([]byte(server)), ns)
+}
+
+// invalidateTeamSettingsCache removes the cached team settings of the namespace of all clusters so that the builds
+// generated after the team settings are modified use them
+func invalidateTeamSettingsCache(ns string) {
+	invalidateClusterCache(teamSettingsCacheKind, ns)
+}
+
+// invalidatePodTemplatesCache removes the cached pod templates of the namespace of all clusters so that the builds
+// generated after the pod templates are modified use them
+func invalidatePodTemplatesCache(ns string) {
+	invalidateClusterCache(podTemplatesCacheKind, ns)
+}
+
+// invalidateClusterCache removes the cached resources of the kind of the namespace of all clusters
+func invalidateClusterCache(kind string, ns string) {
+	dir, err := clusterCacheDir()
+	if err != nil {
+		return
+	}
+	files, err := filepath.Glob(filepath.Join(dir, kind+"-*-"+ns+".json"))
+	if err != nil {
+		return
+	}
+	for _, f := range files {
+		os.Remove(f)
+	}
+}
@@ -0,0 +1,89 @@
+package cmd
+
+import (
+	"path/filepath"
+	"strings"
+
+	"github.com/jenkins-x/jx/pkg/util"
+	corev1 "k8s.io/api/core/v1"
+)
+
+// chartValues returns the values which make a deployed chart use the image and version built earlier in the same
+// build. The version is read from the VERSION file written by 'jx step next-version'
+func chartValues(appName string) map[string]string {
+	return map[string]string{
+		"image.repository": "${DOCKER_REGISTRY}/${DOCKER_REGISTRY_ORG:-$ORG}/" + appName,
+		"image.tag":        "$(cat VERSION)",
+		"version":          "$(cat VERSION)",
+	}
+}
+
+// isHelmDeploy returns true if the command line runs 'helm upgrade' or 'helm install'
+func isHelmDeploy(commandLine []string) bool {
+	if len(commandLine) < 2 || filepath.Base(commandLine[0]) != "helm" {
+		return false
+	}
+	for _, arg := range commandLine[1:] {
+		if strings.HasPrefix(arg, "-") {
+			continue
+		}
+		return arg == "upgrade" || arg == "install"
+	}
+	return false
+}
+
+// isHelmDeployStep returns true if the step runs 'helm upgrade' or 'helm install'
+func isHelmDeployStep(step *corev1.Container) bool {
+	return isHelmDeploy(append(append([]string{}, step.Command...), step.Args...))
+}
+
+// injectChartValues adds the image and version values to a step which deploys a chart via helm unless the step
+// already sets them. The step is run via a shell so the values are resolved when the step runs
+func injectChartValues(step *corev1.Container, appName string) {
+	commandLine := append(append([]string{}, step.Command...), step.Args...)
+	if !isHelmDeploy(commandLine) {
+		return
+	}
+	valueArgs := chartValueArgs(commandLine, appName)
+	if len(valueArgs) == 0 {
+		return
+	}
+	step.Command = []string{"/bin/sh", "-c"}
+	step.Args = []string{shellScript(commandLine) + " " + strings.Join(valueArgs, " ")}
+}
+
+// chartValueArgs returns the shell arguments which set the chart values not already set by the command line
+func chartValueArgs(commandLine []string, appName string) []string {
+	values := chartValues(appName)
+	keys := util.SortedMapKeys(values)
+	for _, arg := range commandLine {
+		for i, key := range keys {
+			if strings.HasPrefix(arg, key+"=") {
+				keys = append(keys[:i], keys[i+1:]...)
+				break
+			}
+		}
+	}
+	answer := []string{}
+	for _, key := range keys {
+		answer = append(answer, "--set", "\""+key+"="+values[key]+"\"")
+	}
+	return answer
+}
+
+// shellScript returns the shell script which runs the command line
+func shellScript(commandLine []string) string {
+	script := []string{}
+	for _, arg := range commandLine {
+		script = append(script, shellQuote(arg))
+	}
+	return strings.Join(script, " ")
+}
+
+// shellQuote quotes the argument so the shell passes it to the command unchanged
+func shellQuote(arg string) string {
+	if arg != "" && !strings.ContainsAny(arg, " \t\n'\"$`\\*?;&|<>(){}[]#~") {
+		return arg
+	}
+	return "'" + strings.Replace(arg, "'", `'\''`, -1) + "'"
+}
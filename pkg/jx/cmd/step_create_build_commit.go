@@ -0,0 +1,134 @@
+package cmd
+
+import (
+	"fmt"
+	"path/filepath"
+	"time"
+
+	"github.com/jenkins-x/jx/pkg/config"
+	"github.com/jenkins-x/jx/pkg/gits"
+	"github.com/jenkins-x/jx/pkg/log"
+	"github.com/jenkins-x/jx/pkg/util"
+	"github.com/pkg/errors"
+)
+
+const commitPipelinesMessage = "chore: regenerate pipelines"
+
+// commitMode returns true if the generated builds are to be committed into the repository
+func (o *StepCreateBuildOptions) commitMode() bool {
+	return o.Commit || o.Push || o.PullRequest
+}
+
+// defaultCommitOutputDir defaults the output directory to the pipelines directory of the repository when the
+// generated builds are committed
+func (o *StepCreateBuildOptions) defaultCommitOutputDir(projectConfig *config.ProjectConfig) {
+	if !o.commitMode() {
+		return
+	}
+	if o.OutputDir == "" {
+		pipelinesDir := projectConfig.PipelinesDir
+		if pipelinesDir == "" {
+			pipelinesDir = config.DefaultPipelinesDir
+		}
+		o.OutputDir = filepath.Join(o.Dir, pipelinesDir)
+	}
+	if !o.flagChanged("build-number") {
+		// committed builds should not be bound to a build number
+		o.BuildNumber = 0
+	}
+}
+
+// commitBuilds commits the generated builds on a new branch if a pull request is to be created otherwise on the
+// current branch then optionally pushes the branch and creates the pull request
+func (o *StepCreateBuildOptions) commitBuilds() error {
+	dir := o.Dir
+	if dir == "" {
+		dir = "."
+	}
+	gitter := o.Git()
+	base, err := gitter.Branch(dir)
+	if err != nil {
+		return errors.Wrap(err, "failed to find the current branch")
+	}
+	branchName := base
+	if o.PullRequest {
+		branchName = gitter.ConvertToValidBranchName(fmt.Sprintf("jx-pipelines-%d", time.Now().Unix()))
+		err = gitter.CreateBranch(dir, branchName)
+		if err != nil {
+			return err
+		}
+		err = gitter.Checkout(dir, branchName)
+		if err != nil {
+			return err
+		}
+	}
+	outDir, err := filepath.Rel(dir, o.OutputDir)
+	if err != nil {
+		outDir = o.OutputDir
+	}
+	err = gitter.Add(dir, outDir)
+	if err != nil {
+		return err
+	}
+	if o.generatedDockerfile {
+		err = gitter.Add(dir, "Dockerfile")
+		if err != nil {
+			return err
+		}
+	}
+	changed, err := gitter.HasChanges(dir)
+	if err != nil {
+		return err
+	}
+	if !changed {
+		log.Infof("The committed pipelines in %s are up to date\n", util.ColorInfo(outDir))
+		if o.PullRequest {
+			return gitter.Checkout(dir, base)
+		}
+		return nil
+	}
+	err = gitter.CommitDir(dir, commitPipelinesMessage)
+	if err != nil {
+		return err
+	}
+	log.Infof("Committed the pipelines in %s on branch %s\n", util.ColorInfo(outDir), util.ColorInfo(branchName))
+	if !o.Push && !o.PullRequest {
+		return nil
+	}
+	err = gitter.Push(dir)
+	if err != nil {
+		return err
+	}
+	if !o.PullRequest {
+		return nil
+	}
+
+	gitInfo, err := o.FindGitInfo(dir)
+	if err != nil {
+		return err
+	}
+	authConfigSvc, err := o.CreateGitAuthConfigService()
+	if err != nil {
+		return err
+	}
+	gitKind, err := o.GitServerKind(gitInfo)
+	if err != nil {
+		return err
+	}
+	provider, err := gitInfo.PickOrCreateProvider(authConfigSvc, "user name to submit the Pull Request", o.BatchMode, gitKind, gitter, o.In, o.Out, o.Err)
+	if err != nil {
+		return err
+	}
+	pr, err := provider.CreatePullRequest(&gits.GitPullRequestArguments{
+		GitRepositoryInfo: gitInfo,
+		Title:             commitPipelinesMessage,
+		Body:              fmt.Sprintf("Regenerates the pipelines in %s", outDir),
+		Base:              base,
+		Head:              branchName,
+	})
+	if err != nil {
+		return err
+	}
+	log.Infof("Created Pull Request: %s\n", util.ColorInfo(pr.URL))
+	return nil
+}
@@ -0,0 +1,65 @@
+package cmd
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/jenkins-x/jx/pkg/util"
+	corev1 "k8s.io/api/core/v1"
+)
+
+// parseContainerOverrides parses the lifecycle=podTemplate values of --container into the pod template of each
+// lifecycle
+func parseContainerOverrides(values []string) (map[string]string, error) {
+	answer := map[string]string{}
+	for _, value := range values {
+		paths := strings.SplitN(value, "=", 2)
+		lifecycle := strings.TrimSpace(paths[0])
+		if len(paths) != 2 || lifecycle == "" || strings.TrimSpace(paths[1]) == "" {
+			return answer, util.InvalidOptionf("container", value, "Expected a lifecycle and pod template such as 'test=maven-java11'")
+		}
+		if _, ok := answer[lifecycle]; ok {
+			return answer, util.InvalidOptionf("container", value, "The container of the %s lifecycle is already overridden", lifecycle)
+		}
+		answer[lifecycle] = strings.TrimSpace(paths[1])
+	}
+	return answer, nil
+}
+
+// lifecycleContainer returns the lifecycle of the step whose container is overridden with --container along with
+// the name of its pod template. A step belongs to the lifecycle it is named after such as 'test' or 'test-1' and the
+// longest matching lifecycle wins
+func (o *StepCreateBuildOptions) lifecycleContainer(stepName string) (string, string) {
+	lifecycle := ""
+	for l := range o.containerOverrides {
+		if stepInLifecycle(stepName, l) && len(l) > len(lifecycle) {
+			lifecycle = l
+		}
+	}
+	if lifecycle == "" {
+		return "", ""
+	}
+	return lifecycle, o.containerOverrides[lifecycle]
+}
+
+// overrideContainer replaces the image of the step with the first container of the pod template overriding the
+// container of its lifecycle. Returns the pod template whose environment variables and volumes the step uses
+func (o *StepCreateBuildOptions) overrideContainer(step *corev1.Container, podTemplate *corev1.Pod, explain *BuildExplanation) (*corev1.Pod, error) {
+	lifecycle, name := o.lifecycleContainer(step.Name)
+	if name == "" {
+		return podTemplate, nil
+	}
+	override, err := o.loadPodTemplate(name)
+	if err != nil {
+		return podTemplate, err
+	}
+	containers := override.Spec.Containers
+	if len(containers) == 0 || containers[0].Image == "" {
+		return podTemplate, util.WithExitCode(fmt.Errorf("No container image defined in the pod template %s of the %s lifecycle", name, lifecycle), util.ExitCodeValidation)
+	}
+	if step.Image != containers[0].Image {
+		step.Image = containers[0].Image
+		explain.explainImage(step.Name, "the pod template %s of the %s lifecycle given by --container", name, lifecycle)
+	}
+	return override, nil
+}
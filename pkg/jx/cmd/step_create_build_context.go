@@ -0,0 +1,93 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/signal"
+	"syscall"
+
+	"github.com/jenkins-x/jx/pkg/gits"
+	"github.com/jenkins-x/jx/pkg/util"
+	"github.com/pkg/errors"
+)
+
+// runWithContext runs the function until it completes, the --timeout expires or the command is interrupted by SIGINT
+// or SIGTERM. The git commands in progress are killed when the command times out or is interrupted. The calls of the
+// kube client cannot be cancelled so the command returns without waiting for them after running the clean ups of the
+// operations in progress such as removing the build packs being cloned. Signals are only handled when the command is
+// run from the command line
+func (o *StepCreateBuildOptions) runWithContext(fn func() error) error {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	if o.Timeout > 0 {
+		var cancelTimeout context.CancelFunc
+		ctx, cancelTimeout = context.WithTimeout(ctx, o.Timeout)
+		defer cancelTimeout()
+	}
+	o.ctx = ctx
+	if gitCLI, ok := o.Git().(*gits.GitCLI); ok {
+		gitClient := o.GitClient
+		o.GitClient = gitCLI.WithContext(ctx)
+		defer func() {
+			o.GitClient = gitClient
+		}()
+	}
+
+	interrupted := make(chan os.Signal, 1)
+	if o.Cmd != nil {
+		signal.Notify(interrupted, os.Interrupt, syscall.SIGTERM)
+		defer signal.Stop(interrupted)
+	}
+	result := make(chan error, 1)
+	go func() {
+		result <- fn()
+	}()
+	select {
+	case err := <-result:
+		return err
+	case sig := <-interrupted:
+		cancel()
+		o.cleanUp()
+		return util.WithExitCode(fmt.Errorf("interrupted by %s while generating the builds", sig), util.ExitCodeInterrupted)
+	case <-ctx.Done():
+		o.cleanUp()
+		return util.WithExitCode(errors.Errorf("timed out after %s generating the builds", o.Timeout), util.ExitCodeUnreachable)
+	}
+}
+
+// context returns the context of the command which is cancelled when the command times out or is interrupted
+func (o *StepCreateBuildOptions) context() context.Context {
+	if o.ctx == nil {
+		return context.Background()
+	}
+	return o.ctx
+}
+
+// addCleanUp registers a function which undoes an operation in progress if the command times out or is interrupted
+// returning the function which unregisters it when the operation completes
+func (o *StepCreateBuildOptions) addCleanUp(fn func()) func() {
+	o.cleanUpLock.Lock()
+	defer o.cleanUpLock.Unlock()
+	if o.cleanUps == nil {
+		o.cleanUps = map[int]func(){}
+	}
+	id := o.nextCleanUp
+	o.nextCleanUp++
+	o.cleanUps[id] = fn
+	return func() {
+		o.cleanUpLock.Lock()
+		defer o.cleanUpLock.Unlock()
+		delete(o.cleanUps, id)
+	}
+}
+
+// cleanUp runs the registered clean ups
+func (o *StepCreateBuildOptions) cleanUp() {
+	o.cleanUpLock.Lock()
+	defer o.cleanUpLock.Unlock()
+	for id, fn := range o.cleanUps {
+		fn()
+		delete(o.cleanUps, id)
+	}
+}
@@ -0,0 +1,131 @@
+package cmd
+
+import (
+	"github.com/jenkins-x/jx/pkg/apis/jenkins.io/v1"
+	"github.com/jenkins-x/jx/pkg/config"
+	corev1 "k8s.io/api/core/v1"
+)
+
+const (
+	dependencyScanStepName       = "dependency-scan"
+	dependencyScanReportStepName = "dependency-scan-report"
+	dependencyScanDir            = "dependency-scan"
+	dependencyScanTokenKey       = "token"
+	owaspDependencyCheckImage    = "owasp/dependency-check"
+	snykImage                    = "snyk/snyk-cli"
+	snykTokenEnv                 = "SNYK_TOKEN"
+)
+
+// dependencyScanReports the JSON reports written by the dependency scanning tools
+var dependencyScanReports = map[string]string{
+	config.DependencyScanOWASP: dependencyScanDir + "/dependency-check-report.json",
+	config.DependencyScanSnyk:  dependencyScanDir + "/snyk.json",
+}
+
+// resolveDependencyScan returns the dependency scan of the project overriding the dependency scan of the team or nil if
+// the dependencies of the project are not scanned
+func resolveDependencyScan(teamScan *v1.DependencyScan, projectScan *config.DependencyScanConfig) (*config.DependencyScanConfig, error) {
+	if projectScan == nil && !teamScan.Enabled {
+		return nil, nil
+	}
+	answer := &config.DependencyScanConfig{}
+	if teamScan.Enabled {
+		answer.Tool = teamScan.Tool
+		answer.Threshold = teamScan.Threshold
+		answer.Image = teamScan.Image
+		answer.ReportBucketURL = teamScan.ReportBucketURL
+		answer.TokenSecret = teamScan.TokenSecret
+	}
+	if projectScan != nil {
+		if projectScan.Disabled {
+			return nil, nil
+		}
+		if projectScan.Tool != "" && projectScan.Tool != answer.Tool {
+			// the image of the team is the image of another tool
+			answer.Tool = projectScan.Tool
+			answer.Image = ""
+		}
+		if projectScan.Threshold != "" {
+			answer.Threshold = projectScan.Threshold
+		}
+		if projectScan.Image != "" {
+			answer.Image = projectScan.Image
+		}
+		if projectScan.ReportBucketURL != "" {
+			answer.ReportBucketURL = projectScan.ReportBucketURL
+		}
+		if projectScan.TokenSecret != "" {
+			answer.TokenSecret = projectScan.TokenSecret
+		}
+	}
+	if answer.Tool == "" {
+		answer.Tool = config.DependencyScanOWASP
+	}
+	if answer.Threshold == "" {
+		answer.Threshold = config.SeverityHigh
+	}
+	return answer, answer.Validate()
+}
+
+// applyDependencyScan adds the scanning of the dependencies of the project before the first step which builds an
+// image or chart followed by a step which uploads the report and fails the build if a vulnerability reaches the
+// threshold
+func applyDependencyScan(steps []corev1.Container, teamScan *v1.DependencyScan, projectConfig *config.ProjectConfig, explain *BuildExplanation) ([]corev1.Container, error) {
+	scan, err := resolveDependencyScan(teamScan, projectConfig.DependencyScan)
+	if err != nil || scan == nil || len(steps) == 0 {
+		return steps, err
+	}
+	idx := len(steps)
+	for i := range steps {
+		if isImageOrChartStep(&steps[i]) {
+			idx = i
+			break
+		}
+	}
+	template := steps[0]
+	if idx > 0 {
+		template = steps[idx-1]
+	}
+	report := dependencyScanReports[scan.Tool]
+
+	var scanStep corev1.Container
+	switch scan.Tool {
+	case config.DependencyScanSnyk:
+		// snyk exits non zero when it finds vulnerabilities so the report step applies the threshold instead
+		scanStep = createStepFrom(&template, dependencyScanStepName, []string{"/bin/sh", "-c", "mkdir -p " + dependencyScanDir + " && snyk test --all-projects --json-file-output=" + report + "; test -f " + report})
+		scanStep.Image = snykImage
+		if scan.TokenSecret != "" {
+			scanStep.Env = append(append([]corev1.EnvVar{}, scanStep.Env...), corev1.EnvVar{
+				Name: snykTokenEnv,
+				ValueFrom: &corev1.EnvVarSource{
+					SecretKeyRef: &corev1.SecretKeySelector{
+						LocalObjectReference: corev1.LocalObjectReference{
+							Name: scan.TokenSecret,
+						},
+						Key: dependencyScanTokenKey,
+					},
+				},
+			})
+		}
+	default:
+		scanStep = createStepFrom(&template, dependencyScanStepName, []string{"/usr/share/dependency-check/bin/dependency-check.sh", "--project", "dependencies", "--scan", ".", "--format", "JSON", "--format", "HTML", "--out", dependencyScanDir})
+		scanStep.Image = owaspDependencyCheckImage
+	}
+	if scan.Image != "" {
+		scanStep.Image = scan.Image
+	}
+
+	command := []string{"jx", "step", "dependency-scan", "--tool", scan.Tool, "--report", report, "--threshold", scan.Threshold}
+	if scan.ReportBucketURL != "" {
+		command = append(command, "--report-bucket-url", scan.ReportBucketURL)
+	}
+	reportStep := createStepFrom(&template, dependencyScanReportStepName, command)
+
+	scanSteps := []corev1.Container{scanStep, reportStep}
+	source := "the dependency scan of the build defaults"
+	if projectConfig.DependencyScan != nil {
+		source = config.ProjectConfigFileName + " dependencyScan"
+	}
+	explain.explainSteps(scanSteps, source)
+	return append(append(append([]corev1.Container{}, steps[:idx]...), scanSteps...), steps[idx:]...), nil
+}
@@ -0,0 +1,200 @@
+package cmd
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/jenkins-x/jx/pkg/config"
+	corev1 "k8s.io/api/core/v1"
+)
+
+// helmValueFlags the helm flags which are followed by a value
+var helmValueFlags = []string{"--namespace", "--values", "-f", "--set", "--set-string", "--set-file", "--version", "--timeout",
+	"--name", "-n", "--repo", "--kube-context", "--tiller-namespace", "--description"}
+
+// deploymentStrategySteps returns the steps which follow a step deploying a chart via helm to deploy the new version
+// using the deployment strategy of the project. Returns no steps for other steps or if there is no strategy. The
+// deploy step is modified if the strategy changes how the chart is deployed
+func deploymentStrategySteps(step *corev1.Container, projectConfig *config.ProjectConfig, appName string) ([]corev1.Container, error) {
+	strategy := projectConfig.DeploymentStrategy
+	err := config.ValidateDeploymentStrategy(strategy)
+	if err != nil {
+		return nil, err
+	}
+	if strategy == "" || !isHelmDeployStep(step) {
+		return nil, nil
+	}
+	switch strategy {
+	case config.DeploymentStrategyBlueGreen:
+		return blueGreenSteps(step, projectConfig, appName)
+	default:
+		return canarySteps(step, projectConfig, appName), nil
+	}
+}
+
+// canarySteps returns the step which creates the Flagger canary and waits for its analysis
+func canarySteps(step *corev1.Container, projectConfig *config.ProjectConfig, appName string) []corev1.Container {
+	commandLine := append(append([]string{}, step.Command...), step.Args...)
+	args := []string{"jx", "step", "canary", "--app", appName}
+	ns := helmNamespace(commandLine)
+	if ns != "" {
+		args = append(args, "--namespace", ns)
+	}
+	canary := projectConfig.Canary
+	if canary != nil {
+		if canary.Port > 0 {
+			args = append(args, "--port", strconv.Itoa(canary.Port))
+		}
+		if canary.Interval != "" {
+			args = append(args, "--interval", canary.Interval)
+		}
+		if canary.Threshold > 0 {
+			args = append(args, "--threshold", strconv.Itoa(canary.Threshold))
+		}
+		if canary.MaxWeight > 0 {
+			args = append(args, "--max-weight", strconv.Itoa(canary.MaxWeight))
+		}
+		if canary.StepWeight > 0 {
+			args = append(args, "--step-weight", strconv.Itoa(canary.StepWeight))
+		}
+		if canary.Timeout != "" {
+			args = append(args, "--timeout", canary.Timeout)
+		}
+	}
+	return []corev1.Container{createStepFrom(step, "canary", args)}
+}
+
+// blueGreenSteps changes the deploy step to deploy the chart as a release of the idle track then returns the steps
+// which verify the idle track, switch the service to it and retire the previous track
+func blueGreenSteps(step *corev1.Container, projectConfig *config.ProjectConfig, appName string) ([]corev1.Container, error) {
+	commandLine := append(append([]string{}, step.Command...), step.Args...)
+	idx := helmReleaseIndex(commandLine)
+	if idx < 0 {
+		return nil, fmt.Errorf("The step %s must specify the helm release name to use the %s deployment strategy", step.Name, config.DeploymentStrategyBlueGreen)
+	}
+	bluegreen := []string{"jx", "step", "bluegreen", "--app", appName}
+	ns := helmNamespace(commandLine)
+	if ns != "" {
+		bluegreen = append(bluegreen, "--namespace", ns)
+	}
+
+	script := []string{
+		"export JX_TRACK=$(" + strings.Join(append(bluegreen, "idle"), " ") + ") &&",
+		shellScript(commandLine[:idx]),
+		"\"" + commandLine[idx] + "-${JX_TRACK}\"",
+		shellScript(commandLine[idx+1:]),
+		"--set \"track=${JX_TRACK}\"",
+	}
+	if !projectConfig.DisableChartValues {
+		script = append(script, chartValueArgs(commandLine, appName)...)
+	}
+	step.Command = []string{"/bin/sh", "-c"}
+	step.Args = []string{strings.Join(script, " ")}
+
+	verify := append([]string{}, bluegreen...)
+	blueGreen := projectConfig.BlueGreen
+	if blueGreen != nil {
+		if blueGreen.Timeout != "" {
+			verify = append(verify, "--timeout", blueGreen.Timeout)
+		}
+		if blueGreen.Verify != "" {
+			verify = append(verify, "--verify", blueGreen.Verify)
+		}
+	}
+	return []corev1.Container{
+		createStepFrom(step, "verify", append(verify, "verify")),
+		createStepFrom(step, "switch", append(append([]string{}, bluegreen...), "switch")),
+		createStepFrom(step, "retire", append(append([]string{}, bluegreen...), "retire")),
+	}, nil
+}
+
+// migrationSteps returns the steps which run the database migrations as jobs in the namespace the helm command line
+// deploys to before the chart is deployed. Migrations without an image use the image built for the application
+func migrationSteps(deployStep *corev1.Container, migrations []corev1.Container, helmCommandLine []string, appName string) []corev1.Container {
+	answer := []corev1.Container{}
+	for _, migration := range migrations {
+		args := []string{"jx", "step", "migrate", "--app", appName, "--name", migration.Name}
+		ns := helmNamespace(helmCommandLine)
+		if ns != "" {
+			args = append(args, "--namespace", ns)
+		}
+		image := migration.Image
+		if image == "" {
+			values := chartValues(appName)
+			image = values["image.repository"] + ":" + values["image.tag"]
+		}
+		commandLine := append(append([]string{}, migration.Command...), migration.Args...)
+		script := shellScript(args) + " --image \"" + image + "\" -- " + shellScript(commandLine)
+		answer = append(answer, createStepFrom(deployStep, "migrate-"+migration.Name, []string{"/bin/sh", "-c", script}))
+	}
+	return answer
+}
+
+// rollbackOnFailure changes the post deploy steps to roll back the release deployed by the helm command line and fail
+// the build if they fail
+func rollbackOnFailure(postDeploySteps []corev1.Container, helmCommandLine []string, strategy string) error {
+	if strategy == config.DeploymentStrategyBlueGreen {
+		return fmt.Errorf("Post deploy steps are not supported by the %s deployment strategy which verifies the new version via its verify command", strategy)
+	}
+	idx := helmReleaseIndex(helmCommandLine)
+	if idx < 0 {
+		return fmt.Errorf("The helm command %s must specify the release name to roll back if the post deploy steps fail", strings.Join(helmCommandLine, " "))
+	}
+	rollback := shellScript([]string{"jx", "step", "helm", "rollback", "--release", helmCommandLine[idx]})
+	for i := range postDeploySteps {
+		step := &postDeploySteps[i]
+		commandLine := append(append([]string{}, step.Command...), step.Args...)
+		if len(commandLine) == 0 {
+			return fmt.Errorf("The post deploy step %s has no command", step.Name)
+		}
+		step.Command = []string{"/bin/sh", "-c"}
+		step.Args = []string{shellScript(commandLine) + " || { " + rollback + "; exit 1; }"}
+	}
+	return nil
+}
+
+// helmNamespace returns the namespace argument of the helm command line if there is one
+func helmNamespace(commandLine []string) string {
+	for i, arg := range commandLine {
+		if arg == "--namespace" && i+1 < len(commandLine) {
+			return commandLine[i+1]
+		}
+		if strings.HasPrefix(arg, "--namespace=") {
+			return strings.TrimPrefix(arg, "--namespace=")
+		}
+	}
+	return ""
+}
+
+// helmReleaseIndex returns the index of the release name in the helm command line or -1 if there is none. The
+// release is the first argument of 'helm upgrade' or the value of the name flag of 'helm install'
+func helmReleaseIndex(commandLine []string) int {
+	positional := []int{}
+	for i := 1; i < len(commandLine); i++ {
+		arg := commandLine[i]
+		if arg == "--name" || arg == "-n" {
+			if i+1 < len(commandLine) {
+				return i + 1
+			}
+			return -1
+		}
+		if strings.HasPrefix(arg, "-") {
+			if !strings.Contains(arg, "=") {
+				for _, flag := range helmValueFlags {
+					if arg == flag {
+						i++
+						break
+					}
+				}
+			}
+			continue
+		}
+		positional = append(positional, i)
+	}
+	// the first positional argument is the helm command
+	if len(positional) > 1 && commandLine[positional[0]] == "upgrade" {
+		return positional[1]
+	}
+	return -1
+}
@@ -0,0 +1,41 @@
+package cmd
+
+import (
+	"github.com/jenkins-x/jx/pkg/config"
+	"github.com/jenkins-x/jx/pkg/util"
+	corev1 "k8s.io/api/core/v1"
+)
+
+// headCommitMessage returns the message of the head commit being built or an empty string if it cannot be found
+func (o *StepCreateBuildOptions) headCommitMessage() string {
+	dir := o.Dir
+	if dir == "" {
+		dir = "."
+	}
+	// ignore errors as the project may not be in a git repository
+	message, _ := o.Git().GetLatestCommitMessage(dir)
+	return message
+}
+
+// applyCommitDirectives removes the steps skipped by the directives in the message of the head commit
+func applyCommitDirectives(steps []corev1.Container, directives []*config.CommitDirectiveConfig) []corev1.Container {
+	for _, directive := range directives {
+		steps = skipSteps(steps, directive.Skip)
+	}
+	return steps
+}
+
+// skipSteps returns the steps without the steps of the given names
+func skipSteps(steps []corev1.Container, names []string) []corev1.Container {
+	if len(names) == 0 {
+		return steps
+	}
+	answer := []corev1.Container{}
+	for _, step := range steps {
+		if step.Name != "" && util.StringArrayIndex(names, step.Name) >= 0 {
+			continue
+		}
+		answer = append(answer, step)
+	}
+	return answer
+}
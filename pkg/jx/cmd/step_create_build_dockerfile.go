@@ -0,0 +1,136 @@
+package cmd
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/jenkins-x/jx/pkg/config"
+	"github.com/jenkins-x/jx/pkg/log"
+	"github.com/jenkins-x/jx/pkg/util"
+	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
+)
+
+const (
+	// generateDockerfileStepName the name of the step which writes the Dockerfile of the build pack into the workspace
+	generateDockerfileStepName = "generate-dockerfile"
+	// dockerfileHeredocMarker the marker of the heredoc which the generated step writes the Dockerfile with
+	dockerfileHeredocMarker = "JX_DOCKERFILE_EOF"
+	// cloneStopTimeout how long the clean up of the build packs waits for their clone to be killed
+	cloneStopTimeout = 10 * time.Second
+)
+
+// buildPacksDir returns the directory of the build packs of the team cloning them if no --packs-dir is specified
+func (o *StepCreateBuildOptions) buildPacksDir() (string, error) {
+	if o.PacksDir == "" {
+		if o.buildPackURL != "" {
+			// a partial clone of the build packs would fail the pulls of the next invocations. The clone is removed
+			// once git has been killed so that git does not recreate the directory
+			cloneDir, err := buildPackDir(o.buildPackURL)
+			if err == nil && !fileExists(cloneDir) {
+				stopped := make(chan struct{})
+				done := o.addCleanUp(func() {
+					// the team settings loaded before the clone cannot be cancelled so the wait is bounded
+					select {
+					case <-stopped:
+					case <-time.After(cloneStopTimeout):
+					}
+					os.RemoveAll(cloneDir)
+				})
+				defer done()
+				defer close(stopped)
+			}
+		}
+		packsDir, err := loadBuildPacks(o.CommonOptions)
+		if err != nil {
+			return "", err
+		}
+		o.PacksDir = packsDir
+	}
+	return o.PacksDir, nil
+}
+
+// loadBuildPacks clones or pulls the build packs of the team returning their directory
+func loadBuildPacks(commonOptions CommonOptions) (string, error) {
+	initOpts := InitOptions{
+		CommonOptions: commonOptions,
+	}
+	packsDir, err := initOpts.initBuildPacks()
+	if err != nil {
+		return "", errors.Wrap(err, "failed to load the build packs")
+	}
+	return packsDir, nil
+}
+
+// packDockerfile returns the Dockerfile template of the build pack or an empty string if the pack has none
+func (o *StepCreateBuildOptions) packDockerfile(buildPack string) (string, error) {
+	packsDir, err := o.buildPacksDir()
+	if err != nil {
+		return "", err
+	}
+	data, err := ioutil.ReadFile(filepath.Join(packsDir, buildPack, "Dockerfile"))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return "", nil
+		}
+		return "", err
+	}
+	return string(data), nil
+}
+
+// applyPackDockerfile provides the Dockerfile of the build pack to the steps which build the image from a Dockerfile
+// when the project has none. The Dockerfile is either written into the project so that it is committed with the
+// generated builds or written into the workspace by a step before the first image build step
+func (o *StepCreateBuildOptions) applyPackDockerfile(steps []corev1.Container, imageBuilder string, buildpacksBuilder string, buildPack string, dir string, explain *BuildExplanation) ([]corev1.Container, error) {
+	if o.NoGenerateDockerfile || buildPack == "" || hasDockerfile(dir) {
+		return steps, nil
+	}
+	if imageBuilder != config.ImageBuilderDocker && (imageBuilder != "" || buildpacksBuilder != "") {
+		// the image is built without a Dockerfile
+		return steps, nil
+	}
+	index := -1
+	for i := range steps {
+		if isDockerImageBuildStep(&steps[i]) {
+			index = i
+			break
+		}
+	}
+	if index < 0 {
+		return steps, nil
+	}
+	dockerfile, err := o.packDockerfile(buildPack)
+	if err != nil {
+		return steps, err
+	}
+	if dockerfile == "" {
+		log.Warnf("There is no Dockerfile in %s and build pack %s has no Dockerfile template\n", dir, buildPack)
+		return steps, nil
+	}
+	if o.CommitDockerfile {
+		err = ioutil.WriteFile(filepath.Join(dir, "Dockerfile"), []byte(dockerfile), DefaultWritePermissions)
+		if err != nil {
+			return steps, errors.Wrap(err, "failed to write the Dockerfile of the build pack")
+		}
+		o.generatedDockerfile = true
+		log.Infof("Generated the Dockerfile of build pack %s in %s\n", util.ColorInfo(buildPack), util.ColorInfo(dir))
+		if !o.commitMode() {
+			log.Warnf("Commit the generated Dockerfile so that the builds use it\n")
+		}
+		return steps, nil
+	}
+	if !strings.HasSuffix(dockerfile, "\n") {
+		dockerfile += "\n"
+	}
+	script := fmt.Sprintf("cat > Dockerfile <<'%s'\n%s%s", dockerfileHeredocMarker, dockerfile, dockerfileHeredocMarker)
+	generate := createStepFrom(&steps[index], generateDockerfileStepName, []string{"/bin/sh", "-c", script})
+	answer := append([]corev1.Container{}, steps[0:index]...)
+	answer = append(answer, generate)
+	answer = append(answer, steps[index:]...)
+	explain.explainSteps([]corev1.Container{generate}, fmt.Sprintf("the Dockerfile of build pack %s as the project has none", buildPack))
+	return answer, nil
+}
@@ -0,0 +1,119 @@
+package cmd
+
+import (
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/ghodss/yaml"
+)
+
+// documentWriter writes the generated resources one YAML document at a time separating each document from the
+// documents written before it so that the output can be piped into 'kubectl apply -f -' without holding all of the
+// documents in memory
+type documentWriter struct {
+	out       io.Writer
+	documents int
+	// lastByte the last byte written so that every document ends with a new line
+	lastByte byte
+}
+
+// Write implements io.Writer for the content of the current document
+func (w *documentWriter) Write(p []byte) (int, error) {
+	n, err := w.out.Write(p)
+	if n > 0 {
+		w.lastByte = p[n-1]
+	}
+	return n, err
+}
+
+// startDocument separates the next document from the document written before it
+func (w *documentWriter) startDocument() error {
+	if w.documents > 0 {
+		_, err := w.Write([]byte("---\n"))
+		if err != nil {
+			return err
+		}
+	}
+	w.documents++
+	return nil
+}
+
+// endDocuments ends the last document with a new line
+func (w *documentWriter) endDocuments() error {
+	if w.documents == 0 || w.lastByte == '\n' {
+		return nil
+	}
+	_, err := w.Write([]byte("\n"))
+	return err
+}
+
+// writeDocument writes the YAML of a document
+func (w *documentWriter) writeDocument(data []byte) error {
+	err := w.startDocument()
+	if err != nil {
+		return err
+	}
+	_, err = w.Write(data)
+	if err != nil {
+		return err
+	}
+	return w.endDocuments()
+}
+
+// writeResource marshals the resource into a YAML document and writes it
+func (w *documentWriter) writeResource(resource interface{}) error {
+	data, err := yaml.Marshal(resource)
+	if err != nil {
+		return err
+	}
+	return w.writeDocument(data)
+}
+
+// copyDocument streams the YAML file as a document
+func (w *documentWriter) copyDocument(fileName string) error {
+	f, err := os.Open(fileName)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	err = w.startDocument()
+	if err != nil {
+		return err
+	}
+	_, err = io.Copy(w, f)
+	if err != nil {
+		return err
+	}
+	return w.endDocuments()
+}
+
+// writeGeneratedDocuments streams the YAML files generated in the directory as separated YAML documents
+func writeGeneratedDocuments(dir string, out io.Writer) error {
+	files, err := ioutil.ReadDir(dir)
+	if err != nil {
+		if os.IsNotExist(err) {
+			// no builds were generated
+			return nil
+		}
+		return err
+	}
+	names := []string{}
+	for _, f := range files {
+		if !f.IsDir() && strings.HasSuffix(f.Name(), ".yml") {
+			names = append(names, f.Name())
+		}
+	}
+	sort.Strings(names)
+	w := &documentWriter{out: out}
+	for _, name := range names {
+		err = w.copyDocument(filepath.Join(dir, name))
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
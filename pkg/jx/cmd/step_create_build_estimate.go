@@ -0,0 +1,152 @@
+package cmd
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/jenkins-x/jx/pkg/history"
+	"github.com/jenkins-x/jx/pkg/log"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+const (
+	// defaultCPUCost the default cost of a CPU core for an hour in dollars
+	defaultCPUCost = 0.031611
+	// defaultMemoryCost the default cost of a GiB of memory for an hour in dollars
+	defaultMemoryCost = 0.004237
+
+	// stepEstimate the estimated duration of a step without any history
+	stepEstimate = time.Minute
+	// imageBuildStepEstimate the estimated duration of a step building an image without any history
+	imageBuildStepEstimate = 3 * time.Minute
+
+	estimateSourceHeuristics = "heuristics"
+
+	gibibyte = 1024 * 1024 * 1024
+)
+
+// BuildEstimate the estimated duration and cost of a generated build
+type BuildEstimate struct {
+	Kind     string
+	Steps    int
+	Duration time.Duration
+	// Source where the duration was estimated from such as the history of the runs or the heuristics
+	Source string
+	CPU    resource.Quantity
+	Memory resource.Quantity
+	Cost   float64
+}
+
+// estimateBuild estimates how long the build takes from the history of the runs of the pipeline of the branch,
+// falling back to the heuristics of its steps, along with the cost of the resources its pod requests for that long.
+// The steps run one after another so the pod requests the most any of its steps requests
+func (o *StepCreateBuildOptions) estimateBuild(build *Build, kind string, runs []history.PipelineRun) *BuildEstimate {
+	answer := &BuildEstimate{
+		Kind:   kind,
+		Steps:  len(build.Spec.Steps),
+		Source: estimateSourceHeuristics,
+	}
+	if len(runs) > 0 {
+		durations := []time.Duration{}
+		for _, run := range runs {
+			durations = append(durations, run.Duration())
+		}
+		sort.Slice(durations, func(i, j int) bool {
+			return durations[i] < durations[j]
+		})
+		answer.Duration = durations[(len(durations)-1)/2]
+		answer.Source = fmt.Sprintf("%d runs", len(runs))
+	} else {
+		for i := range build.Spec.Steps {
+			if isDockerImageBuildStep(&build.Spec.Steps[i]) {
+				answer.Duration += imageBuildStepEstimate
+			} else {
+				answer.Duration += stepEstimate
+			}
+		}
+	}
+	for _, step := range build.Spec.Steps {
+		if q, ok := step.Resources.Requests[corev1.ResourceCPU]; ok && q.Cmp(answer.CPU) > 0 {
+			answer.CPU = q
+		}
+		if q, ok := step.Resources.Requests[corev1.ResourceMemory]; ok && q.Cmp(answer.Memory) > 0 {
+			answer.Memory = q
+		}
+	}
+	hours := answer.Duration.Hours()
+	cores := float64(answer.CPU.MilliValue()) / 1000
+	gib := float64(answer.Memory.Value()) / gibibyte
+	answer.Cost = hours * (cores*o.CPUCost + gib*o.MemoryCost)
+	return answer
+}
+
+// loadRunHistory returns the successful runs of the pipeline of the branch from the history bucket of the team and
+// the pipeline activities falling back to the runs of all the pipelines of the repository. Returns no runs if the
+// history cannot be loaded so that the builds are estimated from the heuristics
+func (o *StepCreateBuildOptions) loadRunHistory(branch string) []history.PipelineRun {
+	dir := o.Dir
+	if dir == "" {
+		dir = "."
+	}
+	gitInfo, err := o.FindGitInfo(dir)
+	if err != nil {
+		return nil
+	}
+	repository := gitInfo.Organisation + "/" + gitInfo.Name
+	runs := []history.PipelineRun{}
+	if o.buildDefaults != nil && o.buildDefaults.HistoryBucketURL != "" {
+		runs, err = history.Load(o.buildDefaults.HistoryBucketURL)
+		if err != nil && o.Verbose {
+			log.Warnf("Failed to load the history of the pipelines: %s\n", err)
+		}
+	}
+	jxClient, ns, err := o.JXClientAndDevNamespace()
+	if err == nil {
+		activities, err := jxClient.JenkinsV1().PipelineActivities(ns).List(metav1.ListOptions{})
+		if err == nil {
+			liveRuns := []history.PipelineRun{}
+			for i := range activities.Items {
+				run := history.NewPipelineRun(&activities.Items[i])
+				if run != nil {
+					liveRuns = append(liveRuns, *run)
+				}
+			}
+			runs = history.Merge(runs, liveRuns)
+		}
+	}
+	repositoryRuns := []history.PipelineRun{}
+	branchRuns := []history.PipelineRun{}
+	for _, run := range runs {
+		if run.Repository() != repository || !run.Succeeded() {
+			continue
+		}
+		repositoryRuns = append(repositoryRuns, run)
+		if strings.EqualFold(run.Pipeline, repository+"/"+branch) {
+			branchRuns = append(branchRuns, run)
+		}
+	}
+	if len(branchRuns) > 0 {
+		return branchRuns
+	}
+	return repositoryRuns
+}
+
+// printEstimates prints the table of the estimates of the builds
+func (o *StepCreateBuildOptions) printEstimates(estimates []*BuildEstimate) {
+	table := o.CreateTable()
+	table.AddRow("KIND", "STEPS", "DURATION", "ESTIMATED FROM", "CPU", "MEMORY", "COST")
+	total := 0.0
+	for _, e := range estimates {
+		table.AddRow(e.Kind, strconv.Itoa(e.Steps), formatStatsDuration(e.Duration), e.Source, e.CPU.String(), e.Memory.String(), fmt.Sprintf("$%.4f", e.Cost))
+		total += e.Cost
+	}
+	table.Render()
+	if len(estimates) > 1 {
+		log.Infof("The builds are estimated to cost %s in total\n", fmt.Sprintf("$%.4f", total))
+	}
+}
@@ -0,0 +1,178 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"path/filepath"
+
+	"github.com/jenkins-x/jx/pkg/config"
+	"github.com/jenkins-x/jx/pkg/kube"
+	"github.com/jenkins-x/jx/pkg/log"
+	corev1 "k8s.io/api/core/v1"
+)
+
+// BuildExplanation describes how each step of a generated build was derived
+type BuildExplanation struct {
+	Kind        string             `json:"kind"`
+	Source      string             `json:"source"`
+	BuildPack   string             `json:"buildPack,omitempty"`
+	PodTemplate string             `json:"podTemplate,omitempty"`
+	Steps       []*StepExplanation `json:"steps"`
+}
+
+// StepExplanation describes where a generated step came from and how it was changed
+type StepExplanation struct {
+	Name      string   `json:"name"`
+	Source    string   `json:"source"`
+	Image     string   `json:"image,omitempty"`
+	Mutations []string `json:"mutations,omitempty"`
+}
+
+// newBuildExplanation creates the explanation of the build of the branch build
+func newBuildExplanation(projectConfig *config.ProjectConfig, build *config.BranchBuild, branch string) *BuildExplanation {
+	answer := &BuildExplanation{
+		Kind:      build.Kind,
+		Source:    branchBuildSource(projectConfig, build, branch),
+		BuildPack: projectConfig.BuildPack,
+	}
+	if projectConfig.BuildPack != "" {
+		answer.PodTemplate = kube.ConfigMapJenkinsPodTemplates + "/" + projectConfig.BuildPack
+	}
+	return answer
+}
+
+// branchBuildSource returns where in the project configuration the steps of the branch build are defined
+func branchBuildSource(projectConfig *config.ProjectConfig, build *config.BranchBuild, branch string) string {
+	if projectConfig.BaseKind(build.Kind) == config.BuildKindInfra {
+		for _, b := range projectConfig.Builds {
+			if b != nil && b.Kind == build.Kind && len(b.Build.Steps) == 0 {
+				return fmt.Sprintf("the Terraform steps of the %s build kind", config.BuildKindInfra)
+			}
+		}
+	}
+	overrides, err := projectConfig.BuildsForBranch(branch)
+	if err == nil {
+		for _, bc := range projectConfig.Branches {
+			if bc == nil {
+				continue
+			}
+			for _, b := range bc.Builds {
+				if b != nil && b.Kind == build.Kind && containsBranchBuild(overrides, b) {
+					return fmt.Sprintf("%s branches[%s].builds[%s]", config.ProjectConfigFileName, bc.Name, b.Kind)
+				}
+			}
+		}
+	}
+	kind := build.Kind
+	for i := 0; i <= len(projectConfig.PipelineKinds) && !hasBuildOfKind(projectConfig, kind); i++ {
+		// the builds of custom kinds default to the build of the kind they extend
+		k := projectConfig.PipelineKind(kind)
+		if k == nil || k.Extends == "" {
+			break
+		}
+		kind = projectConfig.CanonicalKind(k.Extends)
+	}
+	if kind == config.BuildKindFeature && !hasBuildOfKind(projectConfig, kind) {
+		kind = config.BuildKindRelease
+	}
+	return fmt.Sprintf("%s builds[%s]", config.ProjectConfigFileName, kind)
+}
+
+func containsBranchBuild(builds []*config.BranchBuild, build *config.BranchBuild) bool {
+	for _, b := range builds {
+		if b == build {
+			return true
+		}
+	}
+	return false
+}
+
+// buildSource returns where the steps of the build are defined
+func (e *BuildExplanation) buildSource() string {
+	if e == nil {
+		return ""
+	}
+	return e.Source
+}
+
+// step returns the explanation of the named step creating it from the source if it does not exist
+func (e *BuildExplanation) step(name string, source string) *StepExplanation {
+	for _, s := range e.Steps {
+		if s.Name == name {
+			return s
+		}
+	}
+	s := &StepExplanation{
+		Name:   name,
+		Source: source,
+	}
+	e.Steps = append(e.Steps, s)
+	return s
+}
+
+// explainImage records where the image of the step came from
+func (e *BuildExplanation) explainImage(name string, format string, args ...interface{}) {
+	if e != nil {
+		e.step(name, e.Source).Image = fmt.Sprintf(format, args...)
+	}
+}
+
+// explainMutation records a change made to the step
+func (e *BuildExplanation) explainMutation(name string, format string, args ...interface{}) {
+	if e != nil {
+		s := e.step(name, e.Source)
+		s.Mutations = append(s.Mutations, fmt.Sprintf(format, args...))
+	}
+}
+
+// explainSteps records the source of the steps
+func (e *BuildExplanation) explainSteps(steps []corev1.Container, source string) {
+	if e != nil {
+		for _, s := range steps {
+			e.step(s.Name, source)
+		}
+	}
+}
+
+// explainNewSteps records the source of the steps which are not in the previous steps
+func (e *BuildExplanation) explainNewSteps(previous []corev1.Container, steps []corev1.Container, source string) {
+	if e == nil {
+		return
+	}
+	names := map[string]bool{}
+	for _, s := range previous {
+		names[s.Name] = true
+	}
+	for _, s := range steps {
+		if !names[s.Name] {
+			e.step(s.Name, source)
+		}
+	}
+}
+
+// orderSteps orders the explanations of the steps as the generated steps removing any steps which were skipped
+func (e *BuildExplanation) orderSteps(steps []corev1.Container) {
+	if e == nil {
+		return
+	}
+	answer := []*StepExplanation{}
+	for _, s := range steps {
+		answer = append(answer, e.step(s.Name, e.Source))
+	}
+	e.Steps = answer
+}
+
+// writeExplanation writes the explanation as a JSON report next to the generated build or logs it if there is no
+// output directory
+func (o *StepCreateBuildOptions) writeExplanation(explanation *BuildExplanation, outDir string) error {
+	data, err := json.MarshalIndent(explanation, "", "  ")
+	if err != nil {
+		return err
+	}
+	if outDir == "" {
+		log.Info(string(data) + "\n")
+		return nil
+	}
+	return ioutil.WriteFile(filepath.Join(outDir, "explain-"+explanation.Kind+".json"), data, DefaultWritePermissions)
+}
@@ -0,0 +1,108 @@
+package cmd
+
+import (
+	"sort"
+
+	"github.com/jenkins-x/jx/pkg/apis/jenkins.io/v1"
+	"github.com/jenkins-x/jx/pkg/config"
+	"github.com/jenkins-x/jx/pkg/kube"
+	"github.com/jenkins-x/jx/pkg/log"
+	"github.com/jenkins-x/jx/pkg/util"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// extensionStep a pipeline step contributed by an installed extension
+type extensionStep struct {
+	extension string
+	v1.ExtensionPipelineStep
+}
+
+// loadExtensionSteps loads the pipeline steps contributed by the extensions installed in the team ordered by the
+// name of the extension
+func (o *StepCreateBuildOptions) loadExtensionSteps() ([]extensionStep, error) {
+	jxClient, ns, err := o.JXClientAndDevNamespace()
+	if err != nil {
+		return nil, err
+	}
+	extensions, err := jxClient.JenkinsV1().Extensions(ns).List(metav1.ListOptions{})
+	if err != nil {
+		if apierrors.IsNotFound(err) {
+			// no extensions are installed if the CRD is not registered
+			return nil, nil
+		}
+		return nil, err
+	}
+	items := extensions.Items
+	sort.Slice(items, func(i, j int) bool {
+		return items[i].Name < items[j].Name
+	})
+	answer := []extensionStep{}
+	for _, extension := range items {
+		name := extension.Spec.Name
+		if name == "" {
+			name = extension.Name
+		}
+		for _, step := range extension.Spec.PipelineSteps {
+			answer = append(answer, extensionStep{extension: name, ExtensionPipelineStep: step})
+		}
+	}
+	return answer, nil
+}
+
+// applyExtensionSteps adds the steps contributed by extensions for the kind of build before or after the steps of
+// the build. Steps of the same name as a step of the build are not added so that projects can replace them
+func (o *StepCreateBuildOptions) applyExtensionSteps(steps []corev1.Container, projectConfig *config.ProjectConfig, branchBuild *config.BranchBuild, podTemplate *corev1.Pod, defaults *v1.BuildDefaults, explain *BuildExplanation) ([]corev1.Container, error) {
+	if len(steps) == 0 {
+		return steps, nil
+	}
+	pre := []corev1.Container{}
+	post := []corev1.Container{}
+	for _, e := range o.extensionSteps {
+		if len(e.Kinds) > 0 && util.StringArrayIndex(e.Kinds, branchBuild.Kind) < 0 {
+			continue
+		}
+		step := *e.Step.DeepCopy()
+		if step.Name == "" {
+			step.Name = e.extension
+		}
+		step.Name = kube.ToValidName(step.Name)
+		if containsStep(steps, step.Name) || containsStep(pre, step.Name) || containsStep(post, step.Name) {
+			log.Warnf("Not adding the step %s of extension %s as the build already has a step of that name\n", step.Name, e.extension)
+			continue
+		}
+		adjacent := steps[len(steps)-1]
+		if e.When == v1.ExtensionWhenPre {
+			adjacent = steps[0]
+		}
+		if step.Image == "" {
+			step.Image = adjacent.Image
+		}
+		if step.WorkingDir == "" {
+			step.WorkingDir = adjacent.WorkingDir
+		}
+		err := o.addCommonSettings(&step, projectConfig, branchBuild, podTemplate)
+		if err != nil {
+			return steps, err
+		}
+		applyBuildDefaults(&step, defaults)
+		explain.explainSteps([]corev1.Container{step}, "the "+e.extension+" extension")
+		if e.When == v1.ExtensionWhenPre {
+			pre = append(pre, step)
+		} else {
+			post = append(post, step)
+		}
+	}
+	return append(append(pre, steps...), post...), nil
+}
+
+// containsStep returns true if there is a step of the given name
+func containsStep(steps []corev1.Container, name string) bool {
+	for _, step := range steps {
+		if step.Name == name {
+			return true
+		}
+	}
+	return false
+}
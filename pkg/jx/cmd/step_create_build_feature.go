@@ -0,0 +1,36 @@
+package cmd
+
+import (
+	"github.com/jenkins-x/jx/pkg/config"
+	"github.com/jenkins-x/jx/pkg/log"
+	"github.com/jenkins-x/jx/pkg/util"
+	corev1 "k8s.io/api/core/v1"
+)
+
+// applyFeatureBranch adds the environment variables of the feature of the branch such as $JX_FEATURE_NAME to the
+// feature builds along with the feature steps of the project which deploy each feature
+func applyFeatureBranch(branchBuilds []*config.BranchBuild, pc *config.ProjectConfig, branch string) []*config.BranchBuild {
+	if branch == "" {
+		return branchBuilds
+	}
+	feature := pc.FeatureBranchFor(branch)
+	if feature.Name == "" {
+		return branchBuilds
+	}
+	answer := []*config.BranchBuild{}
+	for _, b := range branchBuilds {
+		if pc.BaseKind(b.Kind) != config.BuildKindFeature {
+			answer = append(answer, b)
+			continue
+		}
+		log.Infof("Building feature %s of branch %s\n", util.ColorInfo(feature.Name), util.ColorInfo(branch))
+		fb := *b
+		fb.Env = setEnvVar(fb.Env, corev1.EnvVar{Name: config.EnvFeatureName, Value: feature.Name})
+		fb.Env = setEnvVar(fb.Env, corev1.EnvVar{Name: config.EnvFeatureBranch, Value: feature.Branch})
+		if pc.Feature != nil && len(pc.Feature.Steps) > 0 {
+			fb.Build.Steps = append(append([]corev1.Container{}, b.Build.Steps...), pc.Feature.Steps...)
+		}
+		answer = append(answer, &fb)
+	}
+	return answer
+}
@@ -0,0 +1,123 @@
+package cmd
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/jenkins-x/jx/pkg/config"
+	"github.com/jenkins-x/jx/pkg/log"
+	"github.com/jenkins-x/jx/pkg/util"
+	corev1 "k8s.io/api/core/v1"
+)
+
+// workspaceStepNames the steps which prepare the workspace for the steps after them so that they are kept when any
+// step after them is generated
+var workspaceStepNames = []string{generateDockerfileStepName}
+
+// startStep returns the name of the step to start the build from along with the flag it was given with
+func (o *StepCreateBuildOptions) startStep() (string, string, error) {
+	if o.StartStep != "" && o.FromStep != "" {
+		return "", "", fmt.Errorf("Cannot specify both the start-step and from-step options")
+	}
+	if o.FromStep != "" {
+		return o.FromStep, "from-step", nil
+	}
+	return o.StartStep, "start-step", nil
+}
+
+// filterSteps returns the steps between --start-step and --end-step which belong to the lifecycles of
+// --only-lifecycle so that a single failing stage can be debugged. The steps which prepare the workspace for the
+// steps which are kept such as the step generating the Dockerfile and the approvals of the steps which are kept are
+// kept too so that the partial build runs as it would as part of the whole build
+func (o *StepCreateBuildOptions) filterSteps(steps []corev1.Container, approvals []*config.ApprovalConfig) ([]corev1.Container, error) {
+	start, startFlag, err := o.startStep()
+	if err != nil {
+		return steps, err
+	}
+	if start == "" && o.EndStep == "" && len(o.OnlyLifecycles) == 0 {
+		return steps, nil
+	}
+	names := []string{}
+	for _, step := range steps {
+		if step.Name != "" {
+			names = append(names, step.Name)
+		}
+	}
+	first, last := 0, len(steps)-1
+	if start != "" {
+		first = stepIndex(steps, start)
+		if first < 0 {
+			return steps, util.InvalidOption(startFlag, start, names)
+		}
+	}
+	if o.EndStep != "" {
+		last = stepIndex(steps, o.EndStep)
+		if last < 0 {
+			return steps, util.InvalidOption("end-step", o.EndStep, names)
+		}
+	}
+	if last < first {
+		return steps, util.InvalidOptionf("end-step", o.EndStep, "The step is before the start step %s", start)
+	}
+	keep := make([]bool, len(steps))
+	matched := map[string]bool{}
+	for i := first; i <= last; i++ {
+		keep[i] = len(o.OnlyLifecycles) == 0
+		for _, lifecycle := range o.OnlyLifecycles {
+			if stepInLifecycle(steps[i].Name, lifecycle) {
+				keep[i] = true
+				matched[lifecycle] = true
+			}
+		}
+	}
+	for _, lifecycle := range o.OnlyLifecycles {
+		if !matched[lifecycle] {
+			return steps, util.InvalidOptionf("only-lifecycle", lifecycle, "No steps belong to the lifecycle. The steps are: %s", strings.Join(names, ", "))
+		}
+	}
+	for _, approval := range approvals {
+		if approval == nil {
+			continue
+		}
+		gate := stepIndex(steps, "approval-"+approval.Name)
+		before := stepIndex(steps, approval.Before)
+		if gate >= 0 && before >= 0 {
+			keep[gate] = keep[before]
+		}
+	}
+	kept := false
+	for i := len(steps) - 1; i >= 0; i-- {
+		if !keep[i] && kept && util.StringArrayIndex(workspaceStepNames, steps[i].Name) >= 0 {
+			keep[i] = true
+		}
+		kept = kept || keep[i]
+	}
+	answer := []corev1.Container{}
+	skipped := []string{}
+	for i, step := range steps {
+		if keep[i] {
+			answer = append(answer, step)
+		} else {
+			skipped = append(skipped, step.Name)
+		}
+	}
+	if len(skipped) > 0 {
+		log.Infof("Skipping the steps %s\n", util.ColorInfo(strings.Join(skipped, ", ")))
+	}
+	return answer, nil
+}
+
+// stepInLifecycle returns true if the step is named after the lifecycle such as 'test' or 'test-1'
+func stepInLifecycle(stepName string, lifecycle string) bool {
+	return stepName == lifecycle || strings.HasPrefix(stepName, lifecycle+"-")
+}
+
+// stepIndex returns the index of the step of the given name or -1 if there is no such step
+func stepIndex(steps []corev1.Container, name string) int {
+	for i, step := range steps {
+		if step.Name == name {
+			return i
+		}
+	}
+	return -1
+}
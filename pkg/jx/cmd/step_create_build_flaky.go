@@ -0,0 +1,56 @@
+package cmd
+
+import (
+	"fmt"
+	"strconv"
+
+	"github.com/jenkins-x/jx/pkg/config"
+	"github.com/jenkins-x/jx/pkg/util"
+	corev1 "k8s.io/api/core/v1"
+)
+
+// flakyTestsExitCodeDir the directory of the workspace shared by the steps which the test steps write their exit codes to
+const flakyTestsExitCodeDir = "/workspace"
+
+// applyFlakyTests makes the test steps of the flaky tests configuration record their exit code rather than fail and
+// adds a step after each of them which records the outcomes of the tests, reruns the failed tests if they are all flaky
+// and otherwise fails with the outcome of the tests
+func applyFlakyTests(steps []corev1.Container, flakyTests *config.FlakyTestsConfig, explain *BuildExplanation) ([]corev1.Container, error) {
+	if flakyTests == nil || len(flakyTests.Steps) == 0 {
+		return steps, nil
+	}
+	if len(flakyTests.Reports) == 0 {
+		return steps, fmt.Errorf("The flakyTests of %s must specify the reports of the tests", config.ProjectConfigFileName)
+	}
+	minFlips := flakyTests.MinFlips
+	if minFlips <= 0 {
+		minFlips = config.DefaultFlakyTestMinFlips
+	}
+	answer := []corev1.Container{}
+	for _, step := range steps {
+		if step.Name == "" || util.StringArrayIndex(flakyTests.Steps, step.Name) < 0 {
+			answer = append(answer, step)
+			continue
+		}
+		commandLine := append(append([]string{}, step.Command...), step.Args...)
+		if len(commandLine) == 0 {
+			return steps, fmt.Errorf("The test step %s must specify its command to detect flaky tests", step.Name)
+		}
+		exitCodeFile := fmt.Sprintf("%s/.jx-test-exit-code-%s", flakyTestsExitCodeDir, step.Name)
+		step.Command = []string{"/bin/sh"}
+		step.Args = append([]string{"-c", fmt.Sprintf(`"$@"; echo $? > %s`, exitCodeFile), "sh"}, commandLine...)
+		explain.explainMutation(step.Name, "records the exit code of the tests for the detection of flaky tests")
+
+		command := []string{"jx", "step", "flaky-tests", "--exit-code-file", exitCodeFile, "--min-flips", strconv.Itoa(minFlips)}
+		for _, report := range flakyTests.Reports {
+			command = append(command, "--reports", report)
+		}
+		if flakyTests.RetryCommand != "" {
+			command = append(command, "--retry-command", flakyTests.RetryCommand)
+		}
+		flaky := createStepFrom(&step, "flaky-"+step.Name, command)
+		answer = append(answer, step, flaky)
+		explain.explainSteps([]corev1.Container{flaky}, config.ProjectConfigFileName+" flakyTests")
+	}
+	return answer, nil
+}
@@ -0,0 +1,55 @@
+package cmd
+
+import (
+	"github.com/jenkins-x/jx/pkg/apis/jenkins.io/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+const (
+	// buildServiceAccount the service account generated for the builds when the team binds them to a cloud identity
+	buildServiceAccount = "jx-build"
+
+	// annotationGKEWorkloadIdentity binds a service account to a Google service account on GKE
+	annotationGKEWorkloadIdentity = "iam.gke.io/gcp-service-account"
+	// annotationEKSRoleARN binds a service account to an AWS IAM role on EKS
+	annotationEKSRoleARN = "eks.amazonaws.com/role-arn"
+	// annotationAzureClientID binds a service account to an Azure managed identity
+	annotationAzureClientID = "azure.workload.identity/client-id"
+	// labelAzureWorkloadIdentity enables Azure Workload Identity for a service account
+	labelAzureWorkloadIdentity = "azure.workload.identity/use"
+)
+
+// hasBuildIdentity returns true if the builds are bound to any cloud identity
+func hasBuildIdentity(identity *v1.BuildIdentity) bool {
+	return identity.GCPServiceAccount != "" || identity.AWSRoleARN != "" || identity.AzureClientID != ""
+}
+
+// buildIdentityServiceAccount returns the service account of the builds in the given namespace annotated with the
+// cloud identities of the team so that the builds can push to cloud registries without long lived keys
+func buildIdentityServiceAccount(identity *v1.BuildIdentity, namespace string) *corev1.ServiceAccount {
+	annotations := map[string]string{}
+	labels := map[string]string{}
+	if identity.GCPServiceAccount != "" {
+		annotations[annotationGKEWorkloadIdentity] = identity.GCPServiceAccount
+	}
+	if identity.AWSRoleARN != "" {
+		annotations[annotationEKSRoleARN] = identity.AWSRoleARN
+	}
+	if identity.AzureClientID != "" {
+		annotations[annotationAzureClientID] = identity.AzureClientID
+		labels[labelAzureWorkloadIdentity] = "true"
+	}
+	return &corev1.ServiceAccount{
+		TypeMeta: metav1.TypeMeta{
+			APIVersion: "v1",
+			Kind:       "ServiceAccount",
+		},
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        buildServiceAccount,
+			Namespace:   namespace,
+			Labels:      labels,
+			Annotations: annotations,
+		},
+	}
+}
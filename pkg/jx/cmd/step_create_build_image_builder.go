@@ -0,0 +1,148 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/jenkins-x/jx/pkg/config"
+	"github.com/jenkins-x/jx/pkg/util"
+	corev1 "k8s.io/api/core/v1"
+)
+
+const (
+	// imageBuilderAnnotation the annotation of the pod template of a build pack which selects the tool which builds the
+	// images of the projects of the build pack such as 'jib' or 'ko'
+	imageBuilderAnnotation = "jenkins-x.io/image-builder"
+
+	// jibMavenBuild the Maven goal which builds and pushes the image with Jib without declaring the plugin in the pom
+	jibMavenBuild = "mvn compile com.google.cloud.tools:jib-maven-plugin:build"
+
+	jibStepName = "jib"
+	koStepName  = "ko"
+)
+
+// dockerImageBuildCommands the command prefixes of steps which build the image of the application from its Dockerfile
+var dockerImageBuildCommands = []string{
+	"skaffold build",
+	"docker build",
+}
+
+// hasDockerfile returns true if the project directory contains a Dockerfile
+func hasDockerfile(dir string) bool {
+	return fileExists(filepath.Join(dir, "Dockerfile"))
+}
+
+func fileExists(path string) bool {
+	_, err := os.Stat(path)
+	return err == nil
+}
+
+// isDockerImageBuildStep returns true if the step builds the image of the application from its Dockerfile
+func isDockerImageBuildStep(step *corev1.Container) bool {
+	return hasCommandPrefix(step, dockerImageBuildCommands)
+}
+
+// imageRepository returns the repository of the image of the application in the registry
+func imageRepository(registry string, appName string) string {
+	if registry == "" {
+		registry = "$DOCKER_REGISTRY"
+	}
+	return fmt.Sprintf(`%s/${DOCKER_REGISTRY_ORG:-$ORG}/%s`, registry, appName)
+}
+
+// resolveImageBuilder returns the image builder from the flag, the project configuration or the annotation of the pod
+// template of the build pack or an empty string if none is selected
+func resolveImageBuilder(flag string, projectConfig *config.ProjectConfig, podTemplate *corev1.Pod) (string, error) {
+	imageBuilder := flag
+	if imageBuilder == "" {
+		imageBuilder = projectConfig.ImageBuilder
+	}
+	if imageBuilder == "" && podTemplate != nil {
+		imageBuilder = podTemplate.Annotations[imageBuilderAnnotation]
+	}
+	if imageBuilder != "" && util.StringArrayIndex(config.ImageBuilders, imageBuilder) < 0 {
+		return "", util.InvalidOption("image-builder", imageBuilder, config.ImageBuilders)
+	}
+	return imageBuilder, nil
+}
+
+// applyImageBuilder replaces the steps which build the image of the application from its Dockerfile with a step
+// running the image builder. If no image builder is selected projects without a Dockerfile are built with the
+// buildpacks builder of the team if there is one
+func applyImageBuilder(steps []corev1.Container, imageBuilder string, dir string, builder string, registry string, appName string, explain *BuildExplanation) ([]corev1.Container, error) {
+	source := fmt.Sprintf("the %s image builder", imageBuilder)
+	switch imageBuilder {
+	case "":
+		return applyBuildpacks(steps, dir, builder, registry, appName, explain), nil
+	case config.ImageBuilderDocker:
+		return steps, nil
+	case config.ImageBuilderBuildpacks:
+		if builder == "" {
+			return steps, fmt.Errorf("The %s image builder requires the buildpacksBuilder of the build defaults of the team", imageBuilder)
+		}
+		return replaceImageBuildSteps(steps, buildpacksStep(builder, registry, appName), source, explain), nil
+	case config.ImageBuilderJib:
+		return replaceImageBuildSteps(steps, jibStep(dir, registry, appName), source, explain), nil
+	case config.ImageBuilderKo:
+		return replaceImageBuildSteps(steps, koStep(registry, appName), source, explain), nil
+	default:
+		return steps, util.InvalidOption("image-builder", imageBuilder, config.ImageBuilders)
+	}
+}
+
+// jibStep returns the step which builds and pushes the image with the Jib plugin of Gradle if the project has a
+// Gradle build or otherwise of Maven
+func jibStep(dir string, registry string, appName string) corev1.Container {
+	image := imageRepository(registry, appName) + ":${VERSION}"
+	script := fmt.Sprintf(`%s -Dimage="%s"`, jibMavenBuild, image)
+	if fileExists(filepath.Join(dir, "build.gradle")) || fileExists(filepath.Join(dir, "build.gradle.kts")) {
+		gradle := "gradle"
+		if fileExists(filepath.Join(dir, "gradlew")) {
+			gradle = "./gradlew"
+		}
+		script = fmt.Sprintf(`%s jib --image="%s"`, gradle, image)
+	}
+	return corev1.Container{
+		Name:    jibStepName,
+		Command: []string{"/bin/sh"},
+		Args:    []string{"-c", script},
+	}
+}
+
+// koStep returns the step which builds and pushes the image of the Go main package in the project directory with ko
+func koStep(registry string, appName string) corev1.Container {
+	script := fmt.Sprintf(`KO_DOCKER_REPO="%s" ko build --bare --tags "${VERSION}" .`, imageRepository(registry, appName))
+	return corev1.Container{
+		Name:    koStepName,
+		Command: []string{"/bin/sh"},
+		Args:    []string{"-c", script},
+	}
+}
+
+// replaceImageBuildSteps replaces the first step which builds the image of the application from its Dockerfile with
+// the given step and removes any others. The replacement inherits the image, environment and volumes of the replaced
+// step unless it specifies its own image
+func replaceImageBuildSteps(steps []corev1.Container, replacement corev1.Container, source string, explain *BuildExplanation) []corev1.Container {
+	answer := []corev1.Container{}
+	replaced := false
+	for _, step := range steps {
+		if !isDockerImageBuildStep(&step) {
+			answer = append(answer, step)
+			continue
+		}
+		if replaced {
+			explain.explainMutation(step.Name, "removed as the image is built by the %s step", replacement.Name)
+			continue
+		}
+		newStep := createStepFrom(&step, replacement.Name, append(append([]string{}, replacement.Command...), replacement.Args...))
+		if replacement.Image != "" {
+			newStep.Image = replacement.Image
+		}
+		answer = append(answer, newStep)
+		replaced = true
+		explain.explainSteps([]corev1.Container{newStep}, source)
+		explain.explainMutation(step.Name, "replaced by the %s step of %s", replacement.Name, source)
+	}
+	return answer
+}
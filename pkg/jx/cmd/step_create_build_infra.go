@@ -0,0 +1,74 @@
+package cmd
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/jenkins-x/jx/pkg/config"
+	corev1 "k8s.io/api/core/v1"
+)
+
+const (
+	terraformPlanFile    = "tfplan"
+	terraformCommentFile = "tfplan.md"
+)
+
+// applyInfraBuilds generates the Terraform steps of the builds of the infra kind which do not define their own steps.
+// Pull request branches plan the changes and comment the plan on the pull request while other branches apply them
+func applyInfraBuilds(branchBuilds []*config.BranchBuild, projectConfig *config.ProjectConfig, branch string) ([]*config.BranchBuild, error) {
+	answer := []*config.BranchBuild{}
+	for _, b := range branchBuilds {
+		if projectConfig.BaseKind(b.Kind) != config.BuildKindInfra || len(b.Build.Steps) > 0 {
+			answer = append(answer, b)
+			continue
+		}
+		terraform := projectConfig.Terraform
+		if terraform == nil {
+			terraform = &config.TerraformConfig{}
+		}
+		if terraform.Image == "" && projectConfig.BuildPack == "" {
+			return branchBuilds, fmt.Errorf("The %s builds require either the %s build pack or a terraform image in %s", config.BuildKindInfra, config.TerraformBuildPack, config.ProjectConfigFileName)
+		}
+		infra := *b
+		infra.Build.Steps = terraformSteps(terraform, strings.HasPrefix(branch, "PR-"))
+		answer = append(answer, &infra)
+	}
+	return answer, nil
+}
+
+// terraformSteps returns the steps which plan a pull request and comment the plan on it or apply the configuration
+func terraformSteps(terraform *config.TerraformConfig, pullRequest bool) []corev1.Container {
+	lockTimeout := terraform.LockTimeout
+	if lockTimeout == "" {
+		lockTimeout = config.DefaultTerraformLockTimeout
+	}
+	withDir := func(args ...string) []string {
+		if terraform.Dir != "" {
+			args = append(args, terraform.Dir)
+		}
+		return args
+	}
+	step := func(name string, args ...string) corev1.Container {
+		return corev1.Container{
+			Name:  name,
+			Image: terraform.Image,
+			Args:  args,
+		}
+	}
+	steps := []corev1.Container{
+		step("terraform-init", withDir("terraform", "init", "-input=false")...),
+	}
+	if !pullRequest {
+		return append(steps, step("terraform-apply", withDir("terraform", "apply", "-input=false", "-lock=true",
+			"-lock-timeout="+lockTimeout, "-auto-approve")...))
+	}
+	plan := withDir("terraform", "plan", "-input=false", "-lock=true", "-lock-timeout="+lockTimeout, "-no-color",
+		"-out="+terraformPlanFile)
+	planScript := shellScript(plan) + " > tfplan.txt || { cat tfplan.txt; exit 1; }; cat tfplan.txt; " +
+		"{ echo '#### terraform plan'; echo '```'; cat tfplan.txt; echo '```'; } > " + terraformCommentFile
+	commentScript := `jx step pr comment --owner "$REPO_OWNER" --repository "$REPO_NAME" --pull-request "$PULL_NUMBER" --file ` +
+		terraformCommentFile
+	return append(steps,
+		step("terraform-plan", "/bin/sh", "-c", planScript),
+		step("terraform-comment", "/bin/sh", "-c", commentScript))
+}
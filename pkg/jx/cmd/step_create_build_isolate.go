@@ -0,0 +1,58 @@
+package cmd
+
+import (
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/jenkins-x/jx/pkg/kube"
+	corev1 "k8s.io/api/core/v1"
+)
+
+const (
+	// isolatedNamespaceEnvVar the environment variable of the steps containing the namespace created for the build
+	isolatedNamespaceEnvVar = "ISOLATED_NAMESPACE"
+
+	// maxNamespaceLength the maximum length of the name of a namespace
+	maxNamespaceLength = 63
+
+	// defaultIsolatedTTL how long the namespace of a build is kept by default if the build fails before deleting it
+	defaultIsolatedTTL = 2 * time.Hour
+)
+
+// isolatedNamespace returns the name of the namespace created for a single build of a branch
+func isolatedNamespace(projectName string, branch string, kind string, buildNumber int) string {
+	suffix := "-" + strconv.Itoa(buildNumber)
+	name := kube.ToValidName("build-" + projectName + "-" + branch + "-" + kind)
+	if len(name)+len(suffix) > maxNamespaceLength {
+		name = strings.TrimSuffix(name[0:maxNamespaceLength-len(suffix)], "-")
+	}
+	return name + suffix
+}
+
+// isolateSteps adds a step which creates the namespace of the build before the steps and a step which deletes it after
+// them. The namespace is passed to the steps in $ISOLATED_NAMESPACE. If the build fails before deleting the namespace
+// it is deleted by 'jx gc namespaces' once it expires
+func isolateSteps(steps []corev1.Container, namespace string, ttl time.Duration) []corev1.Container {
+	if len(steps) == 0 {
+		return steps
+	}
+	if ttl <= 0 {
+		ttl = defaultIsolatedTTL
+	}
+	env := corev1.EnvVar{
+		Name:  isolatedNamespaceEnvVar,
+		Value: namespace,
+	}
+	answer := []corev1.Container{}
+	for _, step := range steps {
+		step.Env = setEnvVar(step.Env, env)
+		answer = append(answer, step)
+	}
+	first := answer[0]
+	last := answer[len(answer)-1]
+	create := createStepFrom(&first, "create-namespace", []string{"jx", "step", "isolate", "--namespace", namespace, "--ttl", ttl.String()})
+	remove := createStepFrom(&last, "delete-namespace", []string{"jx", "step", "isolate", "--namespace", namespace, "--delete"})
+	answer = append([]corev1.Container{create}, answer...)
+	return append(answer, remove)
+}
@@ -0,0 +1,91 @@
+package cmd
+
+import (
+	"path/filepath"
+
+	"github.com/jenkins-x/jx/pkg/config"
+	"github.com/jenkins-x/jx/pkg/util"
+)
+
+// applyPackPipelineKinds adds the custom pipeline kinds defined in the pipeline-kinds.yml file of the build pack of
+// the project which the project does not define itself and validates the custom pipeline kinds. The build packs are
+// only read when the builds need them so that the build packs are not cloned just for their kinds
+func (o *StepCreateBuildOptions) applyPackPipelineKinds(pc *config.ProjectConfig) error {
+	if pc.BuildPack != "" && (o.PacksDir != "" || o.needsBuildPacks(pc)) {
+		packsDir, err := o.buildPacksDir()
+		if err != nil {
+			return err
+		}
+		kinds, err := config.LoadPipelineKinds(filepath.Join(packsDir, pc.BuildPack))
+		if err != nil {
+			return err
+		}
+		pc.AddPipelineKinds(kinds)
+	}
+	return pc.ValidatePipelineKinds()
+}
+
+// applyPipelineKinds resolves the aliases of the kinds of the builds, adds the builds of the custom pipeline kinds
+// which extend the kind of a build when the project has no build of their own and removes the builds of the custom
+// kinds which are not generated for the branch
+func applyPipelineKinds(branchBuilds []*config.BranchBuild, pc *config.ProjectConfig, branch string) ([]*config.BranchBuild, error) {
+	answer := []*config.BranchBuild{}
+	for _, b := range branchBuilds {
+		kind := pc.CanonicalKind(b.Kind)
+		if kind != b.Kind {
+			resolved := *b
+			resolved.Kind = kind
+			b = &resolved
+		}
+		answer = append(answer, b)
+	}
+	for _, k := range pc.PipelineKinds {
+		if k.Extends == "" || findBranchBuild(answer, k.Name) != nil {
+			continue
+		}
+		// the kind may extend another custom kind without a build of its own
+		extends := pc.CanonicalKind(k.Extends)
+		found := findBranchBuild(answer, extends)
+		for found == nil && pc.PipelineKind(extends) != nil && pc.PipelineKind(extends).Extends != "" {
+			extends = pc.CanonicalKind(pc.PipelineKind(extends).Extends)
+			found = findBranchBuild(answer, extends)
+		}
+		if found == nil {
+			continue
+		}
+		b := *found
+		b.Kind = k.Name
+		answer = append(answer, &b)
+	}
+	matching := []*config.BranchBuild{}
+	for _, b := range answer {
+		matches, err := pc.PipelineKindMatchesBranch(b.Kind, branch)
+		if err != nil {
+			return branchBuilds, util.WithExitCode(err, util.ExitCodeValidation)
+		}
+		if matches {
+			matching = append(matching, b)
+		}
+	}
+	return matching, nil
+}
+
+// findBranchBuild returns the build of the kind or nil if there is none
+func findBranchBuild(branchBuilds []*config.BranchBuild, kind string) *config.BranchBuild {
+	for _, b := range branchBuilds {
+		if b.Kind == kind {
+			return b
+		}
+	}
+	return nil
+}
+
+// hasBuildOfKind returns true if the project configuration has a build of the kind
+func hasBuildOfKind(projectConfig *config.ProjectConfig, kind string) bool {
+	for _, b := range projectConfig.Builds {
+		if b != nil && projectConfig.CanonicalKind(b.Kind) == kind {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,83 @@
+package cmd
+
+import (
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/jenkins-x/jx/pkg/config"
+	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
+)
+
+// pullRequestNumber returns the number of the pull request being built from $PULL_NUMBER or a branch such as 'PR-12'
+// or an empty string if the branch is not a pull request
+func pullRequestNumber(branch string) string {
+	number := os.Getenv("PULL_NUMBER")
+	if number == "" && strings.HasPrefix(branch, "PR-") {
+		number = strings.TrimPrefix(branch, "PR-")
+	}
+	return number
+}
+
+// resolvePullRequestLabels returns the labels of the pull request being built from the --pr-labels flag or otherwise
+// the git provider. Labels are only resolved if the project configures any pull request labels
+func (o *StepCreateBuildOptions) resolvePullRequestLabels(projectConfig *config.ProjectConfig, branch string) ([]string, error) {
+	if len(projectConfig.PullRequestLabels) == 0 {
+		return nil, nil
+	}
+	if len(o.PullRequestLabels) > 0 || o.flagChanged("pr-labels") {
+		return o.PullRequestLabels, nil
+	}
+	number := pullRequestNumber(branch)
+	if number == "" {
+		return nil, nil
+	}
+	n, err := strconv.Atoi(number)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to parse the pull request number %s", number)
+	}
+	dir := o.Dir
+	if dir == "" {
+		dir = "."
+	}
+	gitInfo, provider, _, err := o.createGitProvider(dir)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to create the git provider to find the labels of pull request %d", n)
+	}
+	pr, err := provider.GetPullRequest(gitInfo.Organisation, gitInfo, n)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to find the labels of pull request %d", n)
+	}
+	return pr.Labels, nil
+}
+
+// applyPullRequestLabels removes the steps skipped by the labels of the pull request and adds the environment
+// variables of the labels to the remaining steps
+func applyPullRequestLabels(steps []corev1.Container, labels []*config.PullRequestLabelConfig, explain *BuildExplanation) []corev1.Container {
+	for _, label := range labels {
+		steps = skipSteps(steps, label.Skip)
+		if len(label.Env) == 0 {
+			continue
+		}
+		for i := range steps {
+			for _, env := range label.Env {
+				steps[i].Env = setEnvVar(steps[i].Env, env)
+			}
+			explain.explainMutation(steps[i].Name, "added the parameters of pull request label %s", label.Label)
+		}
+	}
+	return steps
+}
+
+// setEnvVar replaces the environment variable of the same name or appends it
+func setEnvVar(envVars []corev1.EnvVar, envVar corev1.EnvVar) []corev1.EnvVar {
+	answer := append([]corev1.EnvVar{}, envVars...)
+	for i, e := range answer {
+		if e.Name == envVar.Name {
+			answer[i] = envVar
+			return answer
+		}
+	}
+	return append(answer, envVar)
+}
@@ -0,0 +1,110 @@
+package cmd
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/jenkins-x/jx/pkg/config"
+	corev1 "k8s.io/api/core/v1"
+)
+
+const (
+	libraryKindMaven = "maven"
+	libraryKindNPM   = "npm"
+	libraryKindGo    = "go"
+)
+
+// imageOrChartCommands the command prefixes of steps which build container images, charts or deploy applications
+var imageOrChartCommands = []string{
+	"skaffold",
+	"docker build",
+	"docker push",
+	buildpacksCreator,
+	jibMavenBuild,
+	"gradle jib",
+	"./gradlew jib",
+	"ko build",
+	"bazel run",
+	"helm",
+	"jx step helm",
+	"jx step post build",
+	"jx preview",
+	"jx promote",
+}
+
+// envAssignmentsPrefix matches the environment variable assignments such as 'FOO=bar ' at the start of a shell command
+var envAssignmentsPrefix = regexp.MustCompile(`^([A-Za-z_][A-Za-z0-9_]*=("[^"]*"|'[^']*'|\S*)\s+)+`)
+
+// defaultLibraryPublishCommands the default commands used to publish a release of each kind of library
+var defaultLibraryPublishCommands = map[string][]string{
+	libraryKindMaven: {"mvn", "deploy"},
+	libraryKindNPM:   {"npm", "publish"},
+	// go modules are published by tagging the git repository
+	libraryKindGo: nil,
+}
+
+// applyLibraryPack removes the steps which build images or charts from the steps of a library and adds the steps
+// which publish the artifacts and create pull requests to update the version of the library in downstream projects
+func applyLibraryPack(steps []corev1.Container, library *config.LibraryConfig, kind string) ([]corev1.Container, error) {
+	answer := []corev1.Container{}
+	for _, step := range steps {
+		if !isImageOrChartStep(&step) {
+			answer = append(answer, step)
+		}
+	}
+	if kind != config.BuildKindRelease || len(answer) == 0 {
+		return answer, nil
+	}
+	publish := library.Publish
+	if len(publish) == 0 {
+		var ok bool
+		publish, ok = defaultLibraryPublishCommands[library.Kind]
+		if !ok {
+			return answer, fmt.Errorf("No publish command defined for the library of kind %s. Please specify the library publish command in %s", library.Kind, config.ProjectConfigFileName)
+		}
+	}
+	last := answer[len(answer)-1]
+	if len(publish) > 0 {
+		answer = append(answer, createStepFrom(&last, "publish", publish))
+	}
+	if !library.DisableVersionPR {
+		answer = append(answer, createStepFrom(&last, "version-pr", []string{"updatebot", "push"}))
+	}
+	return answer, nil
+}
+
+// createStepFrom creates a step running the given command with the image, environment and volumes of the template step
+func createStepFrom(template *corev1.Container, name string, command []string) corev1.Container {
+	return corev1.Container{
+		Name:         name,
+		Image:        template.Image,
+		Command:      command[0:1],
+		Args:         command[1:],
+		Env:          template.Env,
+		VolumeMounts: template.VolumeMounts,
+		WorkingDir:   template.WorkingDir,
+	}
+}
+
+// isImageOrChartStep returns true if the command line of the step or a shell script argument starts with one of
+// the imageOrChartCommands
+func isImageOrChartStep(step *corev1.Container) bool {
+	return hasCommandPrefix(step, imageOrChartCommands)
+}
+
+// hasCommandPrefix returns true if the command line of the step or a shell script argument starts with one of the
+// given command prefixes ignoring any environment variable assignments before the command
+func hasCommandPrefix(step *corev1.Container, prefixes []string) bool {
+	commandLine := strings.Join(append(append([]string{}, step.Command...), step.Args...), " ")
+	commandLines := append([]string{commandLine}, step.Args...)
+	for _, line := range commandLines {
+		line = envAssignmentsPrefix.ReplaceAllString(strings.TrimSpace(line), "")
+		for _, prefix := range prefixes {
+			if line == prefix || strings.HasPrefix(line, prefix+" ") {
+				return true
+			}
+		}
+	}
+	return false
+}
@@ -0,0 +1,111 @@
+package cmd
+
+import (
+	"os"
+	"regexp"
+	"strings"
+
+	"github.com/jenkins-x/jx/pkg/config"
+	"github.com/jenkins-x/jx/pkg/issues"
+	"github.com/jenkins-x/jx/pkg/kube"
+	"github.com/jenkins-x/jx/pkg/util"
+)
+
+// buildLinks the links of the generated builds to the commit, the pull request and the issues they are run for
+type buildLinks struct {
+	CommitSHA        string
+	CommitURL        string
+	PullRequestURL   string
+	Issues           []string
+	IssueTrackerKind string
+	IssueTrackerURL  string
+}
+
+// resolveBuildLinks returns the links of the builds of the branch to the head commit, the pull request being built and
+// the issues referenced by the message of the head commit or the name of the branch. The issue keys are in the format
+// of the issue tracker of the project or otherwise of the git provider
+func (o *StepCreateBuildOptions) resolveBuildLinks(projectConfig *config.ProjectConfig, branch string, message string) *buildLinks {
+	dir := o.Dir
+	if dir == "" {
+		dir = "."
+	}
+	answer := &buildLinks{}
+	answer.CommitSHA = os.Getenv("PULL_PULL_SHA")
+	if answer.CommitSHA == "" {
+		answer.CommitSHA = os.Getenv("PULL_BASE_SHA")
+	}
+	if answer.CommitSHA == "" {
+		// ignore errors as the project may not be in a git repository
+		sha, err := o.Git().GetLatestCommitSha(dir)
+		if err == nil {
+			answer.CommitSHA = sha
+		}
+	}
+	gitInfo, err := o.FindGitInfo(dir)
+	if err == nil && gitInfo != nil {
+		if answer.CommitSHA != "" {
+			answer.CommitURL = util.UrlJoin(gitInfo.HttpsURL(), "commit", answer.CommitSHA)
+		}
+		number := pullRequestNumber(branch)
+		if number != "" {
+			answer.PullRequestURL = gitInfo.PullRequestURL(number)
+		}
+	}
+	regex := GitHubIssueRegex
+	tracker := projectConfig.IssueTracker
+	if tracker != nil && tracker.Kind != "" && tracker.Kind != issues.Git {
+		answer.IssueTrackerKind = tracker.Kind
+		answer.IssueTrackerURL = tracker.URL
+		if tracker.Kind == issues.Jira {
+			regex = JIRAIssueRegex
+		}
+	}
+	texts := []string{message}
+	if !strings.HasPrefix(branch, "PR-") {
+		// the names of the pull request branches such as 'PR-123' look like JIRA keys
+		texts = append(texts, branch)
+	}
+	answer.Issues = issueKeys(regex, texts...)
+	return answer
+}
+
+// issueKeys returns the keys of the issues the texts reference in the order they are first referenced
+func issueKeys(regex *regexp.Regexp, texts ...string) []string {
+	answer := []string{}
+	for _, text := range texts {
+		for _, match := range regex.FindAllString(text, -1) {
+			key := strings.TrimPrefix(match, "#")
+			if util.StringArrayIndex(answer, key) < 0 {
+				answer = append(answer, key)
+			}
+		}
+	}
+	return answer
+}
+
+// applyBuildLinks annotates the build with its links so that the links can be shown along with the runs and
+// 'jx controller issues' can comment the links to the runs on the issues
+func applyBuildLinks(build *Build, links *buildLinks) {
+	if links == nil {
+		return
+	}
+	annotations := map[string]string{
+		kube.AnnotationCommitSHA:      links.CommitSHA,
+		kube.AnnotationCommitURL:      links.CommitURL,
+		kube.AnnotationPullRequestURL: links.PullRequestURL,
+		kube.AnnotationIssues:         strings.Join(links.Issues, ","),
+	}
+	if len(links.Issues) > 0 {
+		annotations[kube.AnnotationIssueTrackerKind] = links.IssueTrackerKind
+		annotations[kube.AnnotationIssueTrackerURL] = links.IssueTrackerURL
+	}
+	for k, v := range annotations {
+		if v == "" {
+			continue
+		}
+		if build.Annotations == nil {
+			build.Annotations = map[string]string{}
+		}
+		build.Annotations[k] = v
+	}
+}
@@ -0,0 +1,89 @@
+package cmd
+
+import (
+	"reflect"
+	"strings"
+
+	"github.com/jenkins-x/jx/pkg/config"
+	"github.com/jenkins-x/jx/pkg/util"
+	corev1 "k8s.io/api/core/v1"
+)
+
+// mergeableShells the shells whose -c scripts are merged into the script of the merged step as they are
+var mergeableShells = []string{"sh", "/bin/sh"}
+
+// mergeSteps merges each run of consecutive steps which share their image, working directory, environment, volumes
+// and every other field of their container into one step running their commands in turn with 'sh -e -c' so that
+// a container is not started for each of them. A command repeated by consecutive steps is only run once
+func mergeSteps(steps []corev1.Container, mergeConfig *config.MergeStepsConfig, explain *BuildExplanation) []corev1.Container {
+	exclude := []string{}
+	if mergeConfig != nil {
+		exclude = mergeConfig.Exclude
+	}
+	answer := []corev1.Container{}
+	for i := 0; i < len(steps); {
+		j := i + 1
+		if mergeableStep(&steps[i], exclude) {
+			for j < len(steps) && mergeableStep(&steps[j], exclude) && sameContainer(&steps[i], &steps[j]) {
+				j++
+			}
+		}
+		if j-i == 1 {
+			answer = append(answer, steps[i])
+			i = j
+			continue
+		}
+		names := []string{}
+		scripts := []string{}
+		for _, step := range steps[i:j] {
+			names = append(names, step.Name)
+			script := stepScript(&step)
+			if len(scripts) > 0 && scripts[len(scripts)-1] == script {
+				continue
+			}
+			scripts = append(scripts, script)
+		}
+		merged := steps[i]
+		merged.Command = []string{"/bin/sh"}
+		merged.Args = []string{"-e", "-c", strings.Join(scripts, " && ")}
+		answer = append(answer, merged)
+		explain.explainMutation(merged.Name, "runs the commands of the merged steps %s in one container", strings.Join(names, ", "))
+		i = j
+	}
+	return answer
+}
+
+// mergeableStep returns true if the step has a command line which can be run by a shell script and is not excluded
+func mergeableStep(step *corev1.Container, exclude []string) bool {
+	if util.StringArrayIndex(exclude, step.Name) >= 0 {
+		return false
+	}
+	commandLine := append(append([]string{}, step.Command...), step.Args...)
+	if len(commandLine) == 0 {
+		return false
+	}
+	if util.StringArrayIndex(mergeableShells, commandLine[0]) >= 0 && len(commandLine) > 1 && commandLine[1] == "-c" {
+		// scripts which are passed positional parameters are not merged
+		return len(commandLine) == 3
+	}
+	return true
+}
+
+// sameContainer returns true if the steps only differ in their names and command lines
+func sameContainer(step1 *corev1.Container, step2 *corev1.Container) bool {
+	c1 := *step1
+	c2 := *step2
+	c1.Name, c1.Command, c1.Args = "", nil, nil
+	c2.Name, c2.Command, c2.Args = "", nil, nil
+	return reflect.DeepEqual(c1, c2)
+}
+
+// stepScript returns the shell script which runs the command line of the step. Scripts are run in a subshell so that
+// changes of directory and exits only apply to the script as when it ran in its own container
+func stepScript(step *corev1.Container) string {
+	commandLine := append(append([]string{}, step.Command...), step.Args...)
+	if util.StringArrayIndex(mergeableShells, commandLine[0]) >= 0 && len(commandLine) == 3 && commandLine[1] == "-c" {
+		return "(" + commandLine[2] + "\n)"
+	}
+	return shellScript(commandLine)
+}
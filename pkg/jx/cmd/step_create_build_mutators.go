@@ -0,0 +1,232 @@
+package cmd
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/jenkins-x/jx/pkg/apis/jenkins.io/v1"
+	"github.com/jenkins-x/jx/pkg/config"
+	"github.com/jenkins-x/jx/pkg/kube"
+	corev1 "k8s.io/api/core/v1"
+)
+
+const (
+	// StepMutatorImageMirrors the name of the step mutator which replaces the images of all the steps with the image
+	// mirrors of the team including the steps added by the generation
+	StepMutatorImageMirrors = "image-mirrors"
+	// StepMutatorEnvInjection the name of the step mutator which adds the step environment variables of the team to
+	// every step
+	StepMutatorEnvInjection = "env-injection"
+	// StepMutatorSecurityHardening the name of the step mutator which disables the privileges of the steps which do
+	// not request them
+	StepMutatorSecurityHardening = "security-hardening"
+)
+
+// StepMutatorContext the build which the steps being mutated are generated for
+type StepMutatorContext struct {
+	// ProjectName the name of the project of the build
+	ProjectName string
+	// Kind the kind of build such as release or pullRequest
+	Kind string
+	// ProjectConfig the configuration of the project
+	ProjectConfig *config.ProjectConfig
+	// BuildDefaults the build defaults of the team
+	BuildDefaults *v1.BuildDefaults
+}
+
+// StepMutator customizes the steps generated by 'jx step create build' so that distributions of Jenkins X can
+// change the generated builds without forking the generation
+type StepMutator interface {
+	// Name returns the name which teams enable or disable the step mutator with
+	Name() string
+
+	// Mutate returns the mutated steps of the build
+	Mutate(ctx *StepMutatorContext, steps []corev1.Container) ([]corev1.Container, error)
+}
+
+type registeredStepMutator struct {
+	mutator          StepMutator
+	order            int
+	enabledByDefault bool
+}
+
+var (
+	stepMutatorsLock sync.Mutex
+	stepMutators     = []registeredStepMutator{}
+)
+
+func init() {
+	RegisterStepMutator(&imageMirrorsStepMutator{}, 100, false)
+	RegisterStepMutator(&envInjectionStepMutator{}, 200, false)
+	RegisterStepMutator(&securityHardeningStepMutator{}, 300, false)
+}
+
+// RegisterStepMutator registers a step mutator which is applied to the generated steps in ascending order, then
+// by name. Step mutators which are not enabled by default are applied when they are in the step mutators of the
+// build defaults of the team
+func RegisterStepMutator(mutator StepMutator, order int, enabledByDefault bool) {
+	stepMutatorsLock.Lock()
+	defer stepMutatorsLock.Unlock()
+	registered := registeredStepMutator{
+		mutator:          mutator,
+		order:            order,
+		enabledByDefault: enabledByDefault,
+	}
+	for i, m := range stepMutators {
+		if m.mutator.Name() == mutator.Name() {
+			stepMutators[i] = registered
+			return
+		}
+	}
+	stepMutators = append(stepMutators, registered)
+	sort.SliceStable(stepMutators, func(i, j int) bool {
+		if stepMutators[i].order != stepMutators[j].order {
+			return stepMutators[i].order < stepMutators[j].order
+		}
+		return stepMutators[i].mutator.Name() < stepMutators[j].mutator.Name()
+	})
+}
+
+// StepMutatorNames returns the names of the registered step mutators in the order they are applied
+func StepMutatorNames() []string {
+	stepMutatorsLock.Lock()
+	defer stepMutatorsLock.Unlock()
+	answer := []string{}
+	for _, m := range stepMutators {
+		answer = append(answer, m.mutator.Name())
+	}
+	return answer
+}
+
+// enabledStepMutators returns the step mutators which are enabled by default or by the names of the team in the
+// order they are applied. Names prefixed with '-' disable the step mutators which are enabled by default
+func enabledStepMutators(names []string) ([]StepMutator, error) {
+	stepMutatorsLock.Lock()
+	defer stepMutatorsLock.Unlock()
+	enabled := map[string]bool{}
+	known := []string{}
+	for _, m := range stepMutators {
+		enabled[m.mutator.Name()] = m.enabledByDefault
+		known = append(known, m.mutator.Name())
+	}
+	for _, name := range names {
+		value := !strings.HasPrefix(name, "-")
+		name = strings.TrimPrefix(name, "-")
+		if _, ok := enabled[name]; !ok {
+			return nil, fmt.Errorf("unknown step mutator %s in the build defaults of the team. Known step mutators: %s", name, strings.Join(known, ", "))
+		}
+		enabled[name] = value
+	}
+	answer := []StepMutator{}
+	for _, m := range stepMutators {
+		if enabled[m.mutator.Name()] {
+			answer = append(answer, m.mutator)
+		}
+	}
+	return answer, nil
+}
+
+// applyStepMutators applies the enabled step mutators to the steps recording the steps they add or change
+func applyStepMutators(steps []corev1.Container, ctx *StepMutatorContext, explain *BuildExplanation) ([]corev1.Container, error) {
+	mutators, err := enabledStepMutators(ctx.BuildDefaults.StepMutators)
+	if err != nil {
+		return steps, err
+	}
+	for _, mutator := range mutators {
+		previous := make([]corev1.Container, len(steps))
+		for i := range steps {
+			steps[i].DeepCopyInto(&previous[i])
+		}
+		steps, err = mutator.Mutate(ctx, steps)
+		if err != nil {
+			return steps, fmt.Errorf("step mutator %s failed: %s", mutator.Name(), err)
+		}
+		source := "the " + mutator.Name() + " step mutator"
+		explain.explainNewSteps(previous, steps, source)
+		for _, step := range steps {
+			for _, p := range previous {
+				if p.Name == step.Name && !reflect.DeepEqual(p, step) {
+					explain.explainMutation(step.Name, "changed by %s", source)
+				}
+			}
+		}
+	}
+	return steps, nil
+}
+
+// imageMirrorsStepMutator replaces the images of all the steps with the image mirrors of the team whereas the
+// build defaults only replace the images of the steps of the project
+type imageMirrorsStepMutator struct {
+}
+
+func (m *imageMirrorsStepMutator) Name() string {
+	return StepMutatorImageMirrors
+}
+
+func (m *imageMirrorsStepMutator) Mutate(ctx *StepMutatorContext, steps []corev1.Container) ([]corev1.Container, error) {
+	for i := range steps {
+		if !imageMirrored(steps[i].Image, ctx.BuildDefaults.ImageMirrors) {
+			steps[i].Image = mirrorImage(steps[i].Image, ctx.BuildDefaults.ImageMirrors)
+		}
+	}
+	return steps, nil
+}
+
+// imageMirrored returns true if the image already uses one of the mirrors
+func imageMirrored(image string, mirrors map[string]string) bool {
+	for _, mirror := range mirrors {
+		if mirror != "" && strings.HasPrefix(image, mirror) {
+			return true
+		}
+	}
+	return false
+}
+
+// envInjectionStepMutator adds the step environment variables of the team to every step
+type envInjectionStepMutator struct {
+}
+
+func (m *envInjectionStepMutator) Name() string {
+	return StepMutatorEnvInjection
+}
+
+func (m *envInjectionStepMutator) Mutate(ctx *StepMutatorContext, steps []corev1.Container) ([]corev1.Container, error) {
+	for i := range steps {
+		for _, env := range ctx.BuildDefaults.StepEnv {
+			if kube.GetEnvVar(&steps[i], env.Name) == nil {
+				steps[i].Env = append(steps[i].Env, env)
+			}
+		}
+	}
+	return steps, nil
+}
+
+// securityHardeningStepMutator disables privileged containers and privilege escalation for the steps which do not
+// explicitly request them
+type securityHardeningStepMutator struct {
+}
+
+func (m *securityHardeningStepMutator) Name() string {
+	return StepMutatorSecurityHardening
+}
+
+func (m *securityHardeningStepMutator) Mutate(ctx *StepMutatorContext, steps []corev1.Container) ([]corev1.Container, error) {
+	for i := range steps {
+		step := &steps[i]
+		if step.SecurityContext == nil {
+			step.SecurityContext = &corev1.SecurityContext{}
+		}
+		if step.SecurityContext.Privileged == nil {
+			privileged := false
+			step.SecurityContext.Privileged = &privileged
+		}
+		if step.SecurityContext.AllowPrivilegeEscalation == nil {
+			allowPrivilegeEscalation := false
+			step.SecurityContext.AllowPrivilegeEscalation = &allowPrivilegeEscalation
+		}
+	}
+	return steps, nil
+}
@@ -0,0 +1,226 @@
+package cmd
+
+import (
+	"io/ioutil"
+	"net"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/ghodss/yaml"
+	"github.com/jenkins-x/jx/pkg/apis/jenkins.io/v1"
+	"github.com/jenkins-x/jx/pkg/gits"
+	"github.com/jenkins-x/jx/pkg/log"
+	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
+	networkingv1 "k8s.io/api/networking/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
+)
+
+const (
+	// labelKnativeBuildName the label on the pods of a Knative build containing the name of the build
+	labelKnativeBuildName = "build.knative.dev/buildName"
+
+	// artifactRepositoryService the service of the artifact repository installed with Jenkins X
+	artifactRepositoryService = "nexus"
+)
+
+// defaultEndpointPorts the ports of the endpoints which are URLs without a port
+var defaultEndpointPorts = map[string]int{
+	"http":  80,
+	"https": 443,
+	"ssh":   22,
+	"git":   9418,
+}
+
+// networkPolicyEgress returns the egress rules which allow the builds to connect to DNS, the git server and docker
+// registry of the team, the artifact repository, the egress endpoints of the team build defaults and the Kubernetes API
+func (o *StepCreateBuildOptions) networkPolicyEgress(teamSettings *v1.TeamSettings) ([]networkingv1.NetworkPolicyEgressRule, error) {
+	kubeClient, ns, err := o.KubeClient()
+	if err != nil {
+		return nil, err
+	}
+	if o.targetNamespace != "" {
+		ns = o.targetNamespace
+	}
+	services, err := kubeClient.CoreV1().Services(ns).List(metav1.ListOptions{})
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to list the services in namespace %s", ns)
+	}
+
+	endpoints := []string{}
+	gitServer := teamSettings.GitServer
+	if gitServer == "" {
+		gitServer = gits.GitHubURL
+	}
+	endpoints = append(endpoints, gitServer)
+	if o.dockerRegistry != "" {
+		endpoints = append(endpoints, o.dockerRegistry)
+	}
+	for _, svc := range services.Items {
+		if svc.Name == artifactRepositoryService {
+			endpoints = append(endpoints, "http://"+svc.Name)
+		}
+	}
+	endpoints = append(endpoints, teamSettings.BuildDefaults.EgressEndpoints...)
+
+	rules := []networkingv1.NetworkPolicyEgressRule{dnsEgressRule()}
+	for _, endpoint := range endpoints {
+		rule, err := egressRuleForEndpoint(endpoint, services.Items)
+		if err != nil {
+			log.Warnf("Not allowing the builds to connect to %s: %s\n", endpoint, err)
+			continue
+		}
+		rules = append(rules, *rule)
+	}
+
+	// the jx steps of the builds use the Kubernetes API
+	apiEndpoints, err := kubeClient.CoreV1().Endpoints("default").Get("kubernetes", metav1.GetOptions{})
+	if err == nil {
+		for _, subset := range apiEndpoints.Subsets {
+			rule := networkingv1.NetworkPolicyEgressRule{}
+			for _, address := range subset.Addresses {
+				rule.To = append(rule.To, ipBlockPeer(net.ParseIP(address.IP)))
+			}
+			for _, port := range subset.Ports {
+				rule.Ports = append(rule.Ports, networkPolicyPort(corev1.ProtocolTCP, int(port.Port)))
+			}
+			if len(rule.To) > 0 {
+				rules = append(rules, rule)
+			}
+		}
+	} else if !apierrors.IsNotFound(err) {
+		return nil, errors.Wrap(err, "failed to find the endpoints of the Kubernetes API")
+	}
+	return rules, nil
+}
+
+// egressRuleForEndpoint returns the egress rule which allows connections to the given URL or host:port endpoint. The
+// endpoints of services in the namespace of the builds are allowed by selecting the pods of the service as the
+// policy applies to the pods behind the service rather than its cluster IP. Other hosts are resolved to their IPs
+func egressRuleForEndpoint(endpoint string, services []corev1.Service) (*networkingv1.NetworkPolicyEgressRule, error) {
+	host, port, err := parseEndpoint(endpoint)
+	if err != nil {
+		return nil, err
+	}
+	for _, svc := range services {
+		if host == svc.Name || strings.HasPrefix(host, svc.Name+"."+svc.Namespace+".") || host == svc.Name+"."+svc.Namespace || (svc.Spec.ClusterIP != "" && host == svc.Spec.ClusterIP) {
+			if len(svc.Spec.Selector) == 0 {
+				return nil, errors.Errorf("the service %s has no selector", svc.Name)
+			}
+			return &networkingv1.NetworkPolicyEgressRule{
+				To: []networkingv1.NetworkPolicyPeer{
+					{
+						PodSelector: &metav1.LabelSelector{
+							MatchLabels: svc.Spec.Selector,
+						},
+					},
+				},
+			}, nil
+		}
+	}
+	ips, err := net.LookupIP(host)
+	if err != nil {
+		return nil, err
+	}
+	rule := &networkingv1.NetworkPolicyEgressRule{
+		Ports: []networkingv1.NetworkPolicyPort{networkPolicyPort(corev1.ProtocolTCP, port)},
+	}
+	for _, ip := range ips {
+		rule.To = append(rule.To, ipBlockPeer(ip))
+	}
+	return rule, nil
+}
+
+// parseEndpoint returns the host and port of a URL or a host:port endpoint which defaults to port 443
+func parseEndpoint(endpoint string) (string, int, error) {
+	port := 443
+	if strings.Contains(endpoint, "://") {
+		u, err := url.Parse(endpoint)
+		if err != nil {
+			return "", 0, err
+		}
+		if p, ok := defaultEndpointPorts[u.Scheme]; ok {
+			port = p
+		}
+		endpoint = u.Host
+	}
+	host, portText, err := net.SplitHostPort(endpoint)
+	if err != nil {
+		// there is no port
+		return endpoint, port, nil
+	}
+	port, err = strconv.Atoi(portText)
+	if err != nil {
+		return "", 0, errors.Errorf("invalid port %s", portText)
+	}
+	return host, port, nil
+}
+
+// dnsEgressRule returns the egress rule which allows DNS lookups
+func dnsEgressRule() networkingv1.NetworkPolicyEgressRule {
+	return networkingv1.NetworkPolicyEgressRule{
+		Ports: []networkingv1.NetworkPolicyPort{networkPolicyPort(corev1.ProtocolUDP, 53), networkPolicyPort(corev1.ProtocolTCP, 53)},
+	}
+}
+
+func networkPolicyPort(protocol corev1.Protocol, number int) networkingv1.NetworkPolicyPort {
+	port := intstr.FromInt(number)
+	return networkingv1.NetworkPolicyPort{Protocol: &protocol, Port: &port}
+}
+
+func ipBlockPeer(ip net.IP) networkingv1.NetworkPolicyPeer {
+	bits := 128
+	if ip.To4() != nil {
+		bits = 32
+	}
+	return networkingv1.NetworkPolicyPeer{
+		IPBlock: &networkingv1.IPBlock{
+			CIDR: ip.String() + "/" + strconv.Itoa(bits),
+		},
+	}
+}
+
+// newNetworkPolicy returns the NetworkPolicy which limits the egress of the pods of the build to the given rules
+func newNetworkPolicy(build *Build, egress []networkingv1.NetworkPolicyEgressRule) *networkingv1.NetworkPolicy {
+	return &networkingv1.NetworkPolicy{
+		TypeMeta: metav1.TypeMeta{
+			APIVersion: "networking.k8s.io/v1",
+			Kind:       "NetworkPolicy",
+		},
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      build.Name,
+			Namespace: build.Namespace,
+		},
+		Spec: networkingv1.NetworkPolicySpec{
+			PodSelector: metav1.LabelSelector{
+				MatchLabels: map[string]string{
+					labelKnativeBuildName: build.Name,
+				},
+			},
+			PolicyTypes: []networkingv1.PolicyType{networkingv1.PolicyTypeEgress},
+			Egress:      egress,
+		},
+	}
+}
+
+// writeGeneratedResource writes a resource generated along with a build such as its NetworkPolicy next to the build
+// or writes it to the standard output if there is no output directory
+func (o *StepCreateBuildOptions) writeGeneratedResource(resource interface{}, outDir string, fileName string) error {
+	data, err := yaml.Marshal(resource)
+	if err != nil {
+		return err
+	}
+	if outDir == "" {
+		return o.writeDocument(data)
+	}
+	err = os.MkdirAll(outDir, DefaultWritePermissions)
+	if err != nil {
+		return err
+	}
+	return ioutil.WriteFile(filepath.Join(outDir, fileName), data, DefaultWritePermissions)
+}
@@ -0,0 +1,66 @@
+package cmd
+
+import (
+	"path/filepath"
+
+	"github.com/jenkins-x/jx/pkg/config"
+	"github.com/jenkins-x/jx/pkg/draft"
+	"github.com/jenkins-x/jx/pkg/log"
+	"github.com/jenkins-x/jx/pkg/util"
+)
+
+// reportPackOverrides logs how the builds of the project override the steps of the builds converted from the build
+// pack marking the overridden, added and removed steps like a diff so that the effect of the overrides can be seen at a
+// glance. The build packs are only read when the builds need them so that they are not cloned just for the report
+func (o *StepCreateBuildOptions) reportPackOverrides(pc *config.ProjectConfig) {
+	if pc.BuildPack == "" || len(pc.Builds) == 0 || (o.PacksDir == "" && !o.needsBuildPacks(pc)) {
+		return
+	}
+	packsDir, err := o.buildPacksDir()
+	if err != nil {
+		return
+	}
+	packDir := filepath.Join(packsDir, pc.BuildPack)
+	if !fileExists(filepath.Join(packDir, draft.JenkinsfileName)) {
+		return
+	}
+	converted, _, err := draft.ConvertPack(packDir)
+	if err != nil {
+		if o.Verbose {
+			log.Warnf("Failed to convert the build pack %s to compare it with the builds of %s: %s\n", pc.BuildPack, config.ProjectConfigFileName, err)
+		}
+		return
+	}
+	for _, build := range pc.Builds {
+		if build == nil {
+			continue
+		}
+		packBuild := findBranchBuild(converted.Builds, pc.CanonicalKind(build.Kind))
+		if packBuild == nil {
+			continue
+		}
+		overrides := config.StepOverrides(packBuild.Build.Steps, build.Build.Steps)
+		if len(overrides) == 0 {
+			continue
+		}
+		log.Infof("The %s build of %s overrides the steps of build pack %s:\n", util.ColorInfo(build.Kind), config.ProjectConfigFileName, util.ColorInfo(pc.BuildPack))
+		for _, override := range overrides {
+			log.Infof("%s\n", formatStepOverride(override))
+		}
+	}
+}
+
+// formatStepOverride formats the override of a step like a line of a diff coloring the overridden steps yellow, the
+// added steps green and the removed steps red
+func formatStepOverride(override config.StepOverride) string {
+	switch override.Change {
+	case config.StepOverridden:
+		return util.ColorWarning("~ " + override.Name + " (overridden)")
+	case config.StepAdded:
+		return util.ColorInfo("+ " + override.Name + " (added)")
+	case config.StepRemoved:
+		return util.ColorError("- " + override.Name + " (removed)")
+	default:
+		return "  " + override.Name
+	}
+}
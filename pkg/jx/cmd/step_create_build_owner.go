@@ -0,0 +1,76 @@
+package cmd
+
+import (
+	"github.com/jenkins-x/jx/pkg/apis/jenkins.io/v1"
+	"github.com/jenkins-x/jx/pkg/gits"
+	"github.com/jenkins-x/jx/pkg/kube"
+	"github.com/jenkins-x/jx/pkg/log"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// resolveBuildOwner returns the reference to the Environment whose git repository is the project or the
+// SourceRepository of the project so that the builds are deleted along with it, together with the namespace of the
+// owner. Returns nil if the project is not registered or the cluster cannot be reached as the builds can be generated
+// without an owner
+func (o *StepCreateBuildOptions) resolveBuildOwner() (*metav1.OwnerReference, string) {
+	dir := o.Dir
+	if dir == "" {
+		dir = "."
+	}
+	gitInfo, err := o.FindGitInfo(dir)
+	if err != nil {
+		return nil, ""
+	}
+	jxClient, ns, err := o.JXClientAndDevNamespace()
+	if err != nil {
+		return nil, ""
+	}
+	if o.targetNamespace != "" && o.targetNamespace != ns {
+		// owners cannot be in a different namespace than the resources they own
+		if o.Verbose {
+			log.Warnf("Not setting the owner of the builds as they run in namespace %s rather than %s\n", o.targetNamespace, ns)
+		}
+		return nil, ""
+	}
+	envs, err := jxClient.JenkinsV1().Environments(ns).List(metav1.ListOptions{})
+	if err == nil {
+		for i := range envs.Items {
+			env := &envs.Items[i]
+			if env.Spec.Kind == v1.EnvironmentKindTypePreview || !sameGitRepository(env.Spec.Source.URL, gitInfo) {
+				continue
+			}
+			return buildOwnerReference("Environment", &env.ObjectMeta), ns
+		}
+	}
+	name := kube.NewSourceRepository(gitInfo, "", nil).Name
+	repo, err := jxClient.JenkinsV1().SourceRepositories(ns).Get(name, metav1.GetOptions{})
+	if err != nil {
+		if o.Verbose {
+			log.Warnf("Not setting the owner of the builds as the SourceRepository %s was not found: %s\n", name, err)
+		}
+		return nil, ""
+	}
+	return buildOwnerReference("SourceRepository", &repo.ObjectMeta), ns
+}
+
+// buildOwnerReference returns the reference to the jenkins.io resource owning the builds
+func buildOwnerReference(kind string, owner *metav1.ObjectMeta) *metav1.OwnerReference {
+	return &metav1.OwnerReference{
+		APIVersion: v1.SchemeGroupVersion.String(),
+		Kind:       kind,
+		Name:       owner.Name,
+		UID:        owner.UID,
+	}
+}
+
+// sameGitRepository returns true if the git URL is the URL of the git repository
+func sameGitRepository(gitURL string, gitInfo *gits.GitRepositoryInfo) bool {
+	if gitURL == "" {
+		return false
+	}
+	info, err := gits.ParseGitURL(gitURL)
+	if err != nil {
+		return false
+	}
+	return info.Host == gitInfo.Host && info.Organisation == gitInfo.Organisation && info.Name == gitInfo.Name
+}
@@ -0,0 +1,48 @@
+package cmd
+
+import (
+	"path/filepath"
+
+	"github.com/jenkins-x/jx/pkg/config"
+	"github.com/jenkins-x/jx/pkg/draft"
+	"github.com/jenkins-x/jx/pkg/log"
+	"github.com/jenkins-x/jx/pkg/util"
+)
+
+// applyPackBuilds converts the Jenkinsfile of the legacy draft build pack of a project without any builds into the
+// equivalent builds so that projects created from old build packs keep building
+func (o *StepCreateBuildOptions) applyPackBuilds(pc *config.ProjectConfig) error {
+	if pc.BuildPack == "" || len(pc.Builds) > 0 {
+		return nil
+	}
+	packsDir, err := o.buildPacksDir()
+	if err != nil {
+		return err
+	}
+	packDir := filepath.Join(packsDir, pc.BuildPack)
+	if !fileExists(filepath.Join(packDir, draft.JenkinsfileName)) {
+		return nil
+	}
+	converted, warnings, err := draft.ConvertPack(packDir)
+	if err != nil {
+		return err
+	}
+	for _, warning := range warnings {
+		log.Warnf("Build pack %s: %s\n", pc.BuildPack, warning)
+	}
+	// the global environment variables of the pack are added to each build unless the project overrides them
+	packEnv := converted.Env
+	for _, e := range pc.Env {
+		packEnv = setEnvVar(packEnv, e)
+	}
+	for _, build := range converted.Builds {
+		env := packEnv
+		for _, e := range build.Env {
+			env = setEnvVar(env, e)
+		}
+		build.Env = env
+	}
+	pc.Builds = converted.Builds
+	log.Infof("Converted the Jenkinsfile of the build pack %s into %d builds\n", util.ColorInfo(pc.BuildPack), len(pc.Builds))
+	return nil
+}
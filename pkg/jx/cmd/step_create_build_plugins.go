@@ -0,0 +1,115 @@
+package cmd
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/ghodss/yaml"
+	"github.com/jenkins-x/jx/pkg/log"
+	"github.com/jenkins-x/jx/pkg/util"
+)
+
+const (
+	// taskgenPluginPrefix the prefix of the names of the executables on the PATH which are run as plugins of the
+	// generation of builds
+	taskgenPluginPrefix = "jx-taskgen-"
+)
+
+// taskgenPlugin a plugin which receives a generated build as JSON on its standard input and writes the mutated
+// build to its standard output so that companies can inject their own policies into the generated builds
+type taskgenPlugin struct {
+	name string
+	args []string
+}
+
+// findTaskgenPlugins returns the executables on the path whose names start with jx-taskgen- ordered by name.
+// Plugins in earlier directories of the path hide the plugins of the same name in later directories
+func findTaskgenPlugins(path string) []string {
+	found := map[string]string{}
+	for _, dir := range filepath.SplitList(path) {
+		if dir == "" {
+			continue
+		}
+		files, err := ioutil.ReadDir(dir)
+		if err != nil {
+			continue
+		}
+		for _, f := range files {
+			name := f.Name()
+			if f.IsDir() || !strings.HasPrefix(name, taskgenPluginPrefix) || f.Mode()&0111 == 0 {
+				continue
+			}
+			if _, ok := found[name]; !ok {
+				found[name] = filepath.Join(dir, name)
+			}
+		}
+	}
+	names := []string{}
+	for name := range found {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	answer := []string{}
+	for _, name := range names {
+		answer = append(answer, found[name])
+	}
+	return answer
+}
+
+// taskgenPlugins returns the plugins on the PATH followed by the OCI image plugins of the team
+func (o *StepCreateBuildOptions) taskgenPlugins() []taskgenPlugin {
+	answer := []taskgenPlugin{}
+	if o.NoPlugins {
+		return answer
+	}
+	for _, path := range findTaskgenPlugins(os.Getenv("PATH")) {
+		answer = append(answer, taskgenPlugin{name: path})
+	}
+	if o.buildDefaults != nil {
+		for _, image := range o.buildDefaults.Plugins {
+			answer = append(answer, taskgenPlugin{name: "docker", args: []string{"run", "--rm", "-i", "-e", "JX_TASKGEN_KIND", image}})
+		}
+	}
+	return answer
+}
+
+// applyTaskgenPlugins passes the build through each plugin in turn returning the build written by the last plugin
+func (o *StepCreateBuildOptions) applyTaskgenPlugins(build *Build, kind string) (*Build, error) {
+	for _, plugin := range o.taskgenPlugins() {
+		data, err := json.Marshal(build)
+		if err != nil {
+			return build, err
+		}
+		command := strings.Join(append([]string{plugin.name}, plugin.args...), " ")
+		log.Infof("Running the plugin %s\n", util.ColorInfo(command))
+		var stdout, stderr bytes.Buffer
+		c := exec.CommandContext(o.context(), plugin.name, plugin.args...)
+		c.Dir = o.Dir
+		c.Env = append(os.Environ(), "JX_TASKGEN_KIND="+kind)
+		c.Stdin = bytes.NewReader(data)
+		c.Stdout = &stdout
+		c.Stderr = &stderr
+		err = c.Run()
+		if err != nil {
+			return build, fmt.Errorf("plugin %s failed: %s %s", command, err, strings.TrimSpace(stderr.String()))
+		}
+		if len(bytes.TrimSpace(stdout.Bytes())) == 0 {
+			// plugins which only validate the build leave it unchanged
+			continue
+		}
+		mutated := &Build{}
+		err = yaml.Unmarshal(stdout.Bytes(), mutated)
+		if err != nil {
+			return build, fmt.Errorf("plugin %s did not write a build: %s", command, err)
+		}
+		build = mutated
+	}
+	return build, nil
+}
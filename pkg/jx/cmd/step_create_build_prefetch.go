@@ -0,0 +1,95 @@
+package cmd
+
+import (
+	"time"
+
+	"github.com/jenkins-x/jx/pkg/apis/jenkins.io/v1"
+	"github.com/jenkins-x/jx/pkg/config"
+	"github.com/jenkins-x/jx/pkg/log"
+	"github.com/jenkins-x/jx/pkg/util"
+	"github.com/pkg/errors"
+	"golang.org/x/sync/errgroup"
+)
+
+// loadProjectAndTeam loads the project configuration and the team settings concurrently
+func (o *StepCreateBuildOptions) loadProjectAndTeam() (*config.ProjectConfig, *v1.TeamSettings, error) {
+	var pc *config.ProjectConfig
+	var teamSettings *v1.TeamSettings
+	eg := &errgroup.Group{}
+	eg.Go(o.timed("loading the project configuration", func() error {
+		var err error
+		pc, err = o.loadProjectConfig()
+		return util.WithExitCode(err, util.ExitCodeValidation)
+	}))
+	eg.Go(o.timed("loading the team settings", func() error {
+		var err error
+		teamSettings, err = o.loadTeamSettings()
+		if err != nil {
+			return util.WithExitCode(errors.Wrap(err, "failed to load the team settings"), util.ExitCodeUnreachable)
+		}
+		return nil
+	}))
+	err := eg.Wait()
+	return pc, teamSettings, err
+}
+
+// prefetchBuildPack loads the pod template of the build pack of the project and clones the build packs if the
+// builds need them concurrently so that the generation of the builds does not wait for each of them in turn. Errors
+// are only logged as the pod template and build packs are loaded again when the builds need them
+func (o *StepCreateBuildOptions) prefetchBuildPack(pc *config.ProjectConfig) {
+	if pc.BuildPack == "" {
+		return
+	}
+	// the clients are created before the goroutines so that they are not created by both of them
+	_, _, err := o.KubeClientAndDevNamespace()
+	if err != nil {
+		log.Warnf("Failed to prefetch the build pack %s: %s\n", pc.BuildPack, err)
+		return
+	}
+	eg := &errgroup.Group{}
+	eg.Go(o.timed("loading the pod template "+pc.BuildPack, func() error {
+		_, err := o.loadPodTemplate(pc.BuildPack)
+		return err
+	}))
+	if o.PacksDir == "" && o.needsBuildPacks(pc) {
+		eg.Go(o.timed("cloning the build packs", func() error {
+			_, err := o.buildPacksDir()
+			return err
+		}))
+	}
+	err = eg.Wait()
+	if err != nil {
+		log.Warnf("Failed to prefetch the build pack %s: %s\n", pc.BuildPack, err)
+	}
+}
+
+// needsBuildPacks returns true if the builds are converted from the build pack or a Dockerfile is generated from the
+// build pack for a step which builds an image
+func (o *StepCreateBuildOptions) needsBuildPacks(pc *config.ProjectConfig) bool {
+	if len(pc.Builds) == 0 {
+		return true
+	}
+	if o.NoGenerateDockerfile || hasDockerfile(o.Dir) {
+		return false
+	}
+	for _, build := range pc.Builds {
+		for i := range build.Build.Steps {
+			if isDockerImageBuildStep(&build.Build.Steps[i]) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// timed returns the function logging how long it took with --verbose
+func (o *StepCreateBuildOptions) timed(description string, fn func() error) func() error {
+	return func() error {
+		start := time.Now()
+		err := fn()
+		if o.Verbose {
+			log.Infof("Finished %s in %s\n", description, util.ColorInfo(time.Since(start).Round(time.Millisecond).String()))
+		}
+		return err
+	}
+}
@@ -0,0 +1,125 @@
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/jenkins-x/jx/pkg/kube"
+	"github.com/jenkins-x/jx/pkg/log"
+	"github.com/jenkins-x/jx/pkg/util"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+const (
+	knativeBuildCRDName    = "builds.build.knative.dev"
+	knativeBuildReleaseURL = "https://storage.googleapis.com/knative-releases/build/latest/release.yaml"
+)
+
+// preflightFailure a capability the cluster is missing to run the generated builds along with how to fix it
+type preflightFailure struct {
+	Message     string
+	Remediation string
+}
+
+// runPreflightChecks verifies the cluster has the CRDs, service accounts, secrets and storage class required by
+// the builds generated from the given pod template, logging how to fix any missing capabilities
+func (o *StepCreateBuildOptions) runPreflightChecks(podTemplate *corev1.Pod) error {
+	failures, err := o.preflightChecks(podTemplate)
+	if err != nil {
+		return err
+	}
+	if len(failures) == 0 {
+		log.Infof("The cluster has all of the capabilities required by the generated builds\n")
+		return nil
+	}
+	for _, f := range failures {
+		log.Warnf("%s\n", f.Message)
+		log.Infof("  to fix: %s\n", util.ColorInfo(f.Remediation))
+	}
+	return fmt.Errorf("the cluster is missing %d capabilities required by the generated builds", len(failures))
+}
+
+func (o *StepCreateBuildOptions) preflightChecks(podTemplate *corev1.Pod) ([]preflightFailure, error) {
+	failures := []preflightFailure{}
+	apisClient, err := o.CreateApiExtensionsClient()
+	if err != nil {
+		return failures, err
+	}
+	kubeClient, ns, err := o.KubeClientAndDevNamespace()
+	if err != nil {
+		return failures, err
+	}
+	if o.targetNamespace != "" {
+		// the service accounts and secrets must exist in the namespace the builds run in
+		ns = o.targetNamespace
+	}
+
+	_, err = apisClient.ApiextensionsV1beta1().CustomResourceDefinitions().Get(knativeBuildCRDName, metav1.GetOptions{})
+	if err != nil {
+		failures = append(failures, preflightFailure{
+			Message:     fmt.Sprintf("The CustomResourceDefinition %s is not installed", knativeBuildCRDName),
+			Remediation: "kubectl apply -f " + knativeBuildReleaseURL,
+		})
+	}
+
+	secrets := []string{kube.SecretJenkinsGitCredentials}
+	if podTemplate != nil {
+		serviceAccount := podTemplate.Spec.ServiceAccountName
+		if serviceAccount == "" {
+			serviceAccount = podTemplate.Spec.DeprecatedServiceAccount
+		}
+		if serviceAccount != "" {
+			_, err = kubeClient.CoreV1().ServiceAccounts(ns).Get(serviceAccount, metav1.GetOptions{})
+			if err != nil {
+				failures = append(failures, preflightFailure{
+					Message:     fmt.Sprintf("The ServiceAccount %s does not exist in namespace %s", serviceAccount, ns),
+					Remediation: fmt.Sprintf("kubectl create serviceaccount %s -n %s", serviceAccount, ns),
+				})
+			}
+		}
+		for _, v := range podTemplate.Spec.Volumes {
+			if v.Secret != nil && util.StringArrayIndex(secrets, v.Secret.SecretName) < 0 {
+				secrets = append(secrets, v.Secret.SecretName)
+			}
+		}
+	}
+	for _, name := range secrets {
+		_, err = kubeClient.CoreV1().Secrets(ns).Get(name, metav1.GetOptions{})
+		if err != nil {
+			failures = append(failures, preflightFailure{
+				Message:     fmt.Sprintf("The Secret %s does not exist in namespace %s", name, ns),
+				Remediation: secretRemediation(name, ns),
+			})
+		}
+	}
+
+	storageClasses, err := kubeClient.StorageV1().StorageClasses().List(metav1.ListOptions{})
+	if err != nil {
+		return failures, err
+	}
+	hasDefault := false
+	for _, sc := range storageClasses.Items {
+		if sc.Annotations != nil && sc.Annotations[kube.AnnotationIsDefaultStorageClass] == "true" {
+			hasDefault = true
+			break
+		}
+	}
+	if !hasDefault {
+		failures = append(failures, preflightFailure{
+			Message:     "There is no default StorageClass to provision the PersistentVolumeClaims of the builds",
+			Remediation: fmt.Sprintf(`kubectl patch storageclass <name> -p '{"metadata": {"annotations": {"%s": "true"}}}'`, kube.AnnotationIsDefaultStorageClass),
+		})
+	}
+	return failures, nil
+}
+
+func secretRemediation(name string, ns string) string {
+	switch name {
+	case kube.SecretJenkinsDockerConfig:
+		return fmt.Sprintf("kubectl create secret generic %s -n %s --from-file=config.json=$HOME/.docker/config.json", name, ns)
+	case kube.SecretJenkinsGitCredentials:
+		return fmt.Sprintf("kubectl create secret generic %s -n %s --from-literal=%s=https://<user>:<token>@github.com", name, ns, jenkinsGitCredentialsSecretKey)
+	default:
+		return fmt.Sprintf("kubectl create secret generic %s -n %s --from-file=<file>", name, ns)
+	}
+}
@@ -0,0 +1,56 @@
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/jenkins-x/jx/pkg/apis/jenkins.io/v1"
+	"github.com/jenkins-x/jx/pkg/gits"
+	"github.com/jenkins-x/jx/pkg/kube"
+	"github.com/jenkins-x/jx/pkg/log"
+	"github.com/jenkins-x/jx/pkg/util"
+	"github.com/pkg/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// configureGitProxy makes the git commands of the generation such as the clones of the build packs connect to the git
+// servers through the git proxy of the team authenticating with its credentials or the Kerberos ticket of the user
+func (o *StepCreateBuildOptions) configureGitProxy(proxy *v1.GitProxy) error {
+	if proxy.URL == "" {
+		return nil
+	}
+	current, ok := o.Git().(*gits.GitCLI)
+	if !ok {
+		return util.WithExitCode(fmt.Errorf("the git proxy %s can only be configured for the git command line but the git client is %T", proxy.URL, o.Git()), util.ExitCodeValidation)
+	}
+	username := ""
+	password := ""
+	if proxy.CredentialsSecret != "" {
+		kubeClient, ns, err := o.KubeClientAndDevNamespace()
+		if err != nil {
+			return util.WithExitCode(err, util.ExitCodeUnreachable)
+		}
+		secret, err := kubeClient.CoreV1().Secrets(ns).Get(proxy.CredentialsSecret, metav1.GetOptions{})
+		if err != nil {
+			return util.WithExitCode(errors.Wrapf(err, "failed to load the credentials of the git proxy from Secret %s in namespace %s", proxy.CredentialsSecret, ns), util.ExitCodeNotFound)
+		}
+		username = string(secret.Data[kube.SecretDataUsername])
+		password = string(secret.Data[kube.SecretDataPassword])
+		if username == "" {
+			return util.WithExitCode(fmt.Errorf("the Secret %s of the git proxy in namespace %s has no %s", proxy.CredentialsSecret, ns, kube.SecretDataUsername), util.ExitCodeValidation)
+		}
+	}
+	gitConfig, err := gits.ProxyConfig(proxy.URL, proxy.AuthMethod, username, password)
+	if err != nil {
+		return util.WithExitCode(errors.Wrap(err, "invalid git proxy of the team build defaults"), util.ExitCodeValidation)
+	}
+	gitCLI := current.WithConfig(gitConfig)
+	err = gitCLI.VerifyConfigEnv()
+	if err != nil {
+		return util.WithExitCode(errors.Wrapf(err, "failed to configure the git proxy %s", proxy.URL), util.ExitCodeValidation)
+	}
+	o.GitClient = gitCLI
+	if o.Verbose {
+		log.Infof("Connecting to the git servers through the proxy %s with %s authentication\n", util.ColorInfo(proxy.URL), gitConfig["http.proxyAuthMethod"])
+	}
+	return nil
+}
@@ -0,0 +1,169 @@
+package cmd
+
+import (
+	"sort"
+	"strings"
+
+	"github.com/jenkins-x/jx/pkg/apis/jenkins.io/v1"
+	"github.com/jenkins-x/jx/pkg/log"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// quotaResourceNames the container resources each ResourceQuota resource makes the containers specify
+var quotaResourceNames = map[corev1.ResourceName]corev1.ResourceName{
+	corev1.ResourceCPU:            corev1.ResourceCPU,
+	corev1.ResourceMemory:         corev1.ResourceMemory,
+	corev1.ResourceRequestsCPU:    corev1.ResourceCPU,
+	corev1.ResourceRequestsMemory: corev1.ResourceMemory,
+	corev1.ResourceLimitsCPU:      corev1.ResourceCPU,
+	corev1.ResourceLimitsMemory:   corev1.ResourceMemory,
+}
+
+// quotaRequirements the resource requests and limits which the ResourceQuotas of a namespace require every container
+// to specify as they reject the pods whose containers do not
+type quotaRequirements struct {
+	Requests []corev1.ResourceName
+	Limits   []corev1.ResourceName
+}
+
+// empty returns true if the containers do not need to specify any resources
+func (q *quotaRequirements) empty() bool {
+	return q == nil || (len(q.Requests) == 0 && len(q.Limits) == 0)
+}
+
+// loadQuotaRequirements returns the resources the ResourceQuotas of the namespace of the builds require the steps to
+// specify which are not defaulted by the LimitRanges of the namespace. Returns nil if the quotas cannot be listed as
+// the builds can be generated without them
+func (o *StepCreateBuildOptions) loadQuotaRequirements() *quotaRequirements {
+	kubeClient, ns, err := o.KubeClientAndDevNamespace()
+	if err != nil {
+		return nil
+	}
+	if o.targetNamespace != "" {
+		ns = o.targetNamespace
+	}
+	quotas, err := kubeClient.CoreV1().ResourceQuotas(ns).List(metav1.ListOptions{})
+	if err != nil {
+		if o.Verbose {
+			log.Warnf("Failed to list the ResourceQuotas of namespace %s: %s\n", ns, err)
+		}
+		return nil
+	}
+	defaultRequests := corev1.ResourceList{}
+	defaultLimits := corev1.ResourceList{}
+	limitRanges, err := kubeClient.CoreV1().LimitRanges(ns).List(metav1.ListOptions{})
+	if err == nil {
+		for _, lr := range limitRanges.Items {
+			for _, limit := range lr.Spec.Limits {
+				if limit.Type != corev1.LimitTypeContainer {
+					continue
+				}
+				for name, q := range limit.DefaultRequest {
+					defaultRequests[name] = q
+				}
+				for name, q := range limit.Default {
+					defaultLimits[name] = q
+					// the limit is also the default request of the containers without a request
+					defaultRequests[name] = q
+				}
+			}
+		}
+	}
+	answer := &quotaRequirements{}
+	for _, quota := range quotas.Items {
+		for name := range quota.Spec.Hard {
+			resource, ok := quotaResourceNames[name]
+			if !ok {
+				continue
+			}
+			if strings.HasPrefix(string(name), "limits.") {
+				if _, defaulted := defaultLimits[resource]; !defaulted {
+					answer.Limits = appendResourceName(answer.Limits, resource)
+				}
+			} else if _, defaulted := defaultRequests[resource]; !defaulted {
+				answer.Requests = appendResourceName(answer.Requests, resource)
+			}
+		}
+	}
+	sortResourceNames(answer.Requests)
+	sortResourceNames(answer.Limits)
+	return answer
+}
+
+// applyQuotaDefaults sets the resources which the ResourceQuotas require and the steps do not specify from the quota
+// resources of the team build defaults falling back to their default resources
+func applyQuotaDefaults(steps []corev1.Container, quota *quotaRequirements, defaults *v1.BuildDefaults, explain *BuildExplanation) {
+	if quota.empty() {
+		return
+	}
+	resources := defaults.QuotaResources
+	if resources == nil {
+		resources = defaults.Resources
+	}
+	if resources == nil {
+		resources = &corev1.ResourceRequirements{}
+	}
+	for i := range steps {
+		step := &steps[i]
+		added := []string{}
+		missing := []string{}
+		// kubernetes defaults the requests of the containers to their limits
+		requests := []corev1.ResourceName{}
+		for _, name := range quota.Requests {
+			if _, ok := step.Resources.Limits[name]; !ok {
+				requests = append(requests, name)
+			}
+		}
+		step.Resources.Requests = defaultResources(step.Resources.Requests, resources.Requests, requests, "requests", &added, &missing)
+		step.Resources.Limits = defaultResources(step.Resources.Limits, resources.Limits, quota.Limits, "limits", &added, &missing)
+		if len(added) > 0 {
+			explain.explainMutation(step.Name, "defaults the %s the ResourceQuota of the namespace requires", strings.Join(added, ", "))
+		}
+		if len(missing) > 0 {
+			log.Warnf("The step %s has no %s which the ResourceQuota of the namespace of the builds requires. Add them to the quotaResources of the team build defaults\n", step.Name, strings.Join(missing, ", "))
+		}
+	}
+}
+
+// defaultResources adds the quantities of the required resources which the resource list does not contain
+func defaultResources(list corev1.ResourceList, defaults corev1.ResourceList, required []corev1.ResourceName, kind string, added *[]string, missing *[]string) corev1.ResourceList {
+	for _, name := range required {
+		if _, ok := list[name]; ok {
+			continue
+		}
+		q, ok := defaults[name]
+		if !ok {
+			*missing = append(*missing, kind+"."+string(name))
+			continue
+		}
+		if list == nil {
+			list = corev1.ResourceList{}
+		}
+		list[name] = q
+		*added = append(*added, kind+"."+string(name))
+	}
+	return list
+}
+
+// applyPriorityClass runs the build pods with the PriorityClass of the team build defaults
+func applyPriorityClass(build *Build, defaults *v1.BuildDefaults) {
+	if defaults.PriorityClassName != "" && build.Spec.PriorityClassName == "" {
+		build.Spec.PriorityClassName = defaults.PriorityClassName
+	}
+}
+
+func appendResourceName(names []corev1.ResourceName, name corev1.ResourceName) []corev1.ResourceName {
+	for _, n := range names {
+		if n == name {
+			return names
+		}
+	}
+	return append(names, name)
+}
+
+func sortResourceNames(names []corev1.ResourceName) {
+	sort.Slice(names, func(i, j int) bool {
+		return names[i] < names[j]
+	})
+}
@@ -0,0 +1,150 @@
+package cmd
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/jenkins-x/jx/pkg/kube"
+	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+const (
+	// dockerConfigVolume the in memory volume containing the docker config written by the registry login step
+	dockerConfigVolume = "docker-config"
+	// dockerConfigDir the directory the docker config volume is mounted at which $DOCKER_CONFIG points to
+	dockerConfigDir = "/docker-config"
+
+	gcloudImage   = "google/cloud-sdk:alpine"
+	awsCLIImage   = "amazon/aws-cli"
+	azureCLIImage = "mcr.microsoft.com/azure-cli"
+)
+
+// dockerRegistryHost returns the docker registry of the team from the docker registry ConfigMap in the development
+// namespace or an empty string if there is none
+func (o *CommonOptions) dockerRegistryHost() (string, error) {
+	kubeClient, ns, err := o.KubeClient()
+	if err != nil {
+		return "", err
+	}
+	devNs, _, err := kube.GetDevNamespace(kubeClient, ns)
+	if err != nil {
+		return "", err
+	}
+	cm, err := kubeClient.CoreV1().ConfigMaps(devNs).Get(kube.ConfigMapJenkinsDockerRegistry, metav1.GetOptions{})
+	if err != nil {
+		if apierrors.IsNotFound(err) {
+			return "", nil
+		}
+		return "", errors.Wrapf(err, "failed to load the ConfigMap %s in namespace %s", kube.ConfigMapJenkinsDockerRegistry, devNs)
+	}
+	return cm.Data["docker.registry"], nil
+}
+
+// isECRRegistry returns true if the registry is an AWS ECR registry such as '123456789012.dkr.ecr.us-east-1.amazonaws.com'
+func isECRRegistry(registry string) bool {
+	return strings.HasSuffix(registry, ".amazonaws.com") && strings.Index(registry, ".ecr.") > 0
+}
+
+// registryLoginStep returns the step which logs into the cloud registry with the cloud identity of the build and
+// writes the docker config used by the steps which push images or nil if the registry is not a cloud registry
+func registryLoginStep(registry string) *corev1.Container {
+	image, user, tokenCommand := "", "", ""
+	switch {
+	case registry == "gcr.io" || strings.HasSuffix(registry, ".gcr.io") || strings.HasSuffix(registry, "-docker.pkg.dev"):
+		image = gcloudImage
+		user = "oauth2accesstoken"
+		tokenCommand = "gcloud auth print-access-token"
+	case isECRRegistry(registry):
+		image = awsCLIImage
+		user = "AWS"
+		region := strings.TrimSuffix(registry[strings.Index(registry, ".ecr.")+len(".ecr."):], ".amazonaws.com")
+		tokenCommand = "aws ecr get-login-password --region " + shellQuote(region)
+	case strings.HasSuffix(registry, ".azurecr.io"):
+		image = azureCLIImage
+		user = "00000000-0000-0000-0000-000000000000"
+		name := strings.TrimSuffix(registry, ".azurecr.io")
+		tokenCommand = "az login --identity > /dev/null && az acr login --name " + shellQuote(name) + " --expose-token --output tsv --query accessToken"
+	default:
+		return nil
+	}
+	script := strings.Join([]string{
+		"set -e",
+		fmt.Sprintf("TOKEN=$(%s)", tokenCommand),
+		fmt.Sprintf("AUTH=$(printf '%%s:%%s' %s \"$TOKEN\" | base64 | tr -d '\\n')", shellQuote(user)),
+		fmt.Sprintf(`printf '{"auths":{"%%s":{"auth":"%%s"}}}' %s "$AUTH" > %s/config.json`, shellQuote(registry), dockerConfigDir),
+	}, "\n")
+	return &corev1.Container{
+		Name:         "registry-login",
+		Image:        image,
+		Command:      []string{"/bin/sh"},
+		Args:         []string{"-c", script},
+		VolumeMounts: []corev1.VolumeMount{{Name: dockerConfigVolume, MountPath: dockerConfigDir}},
+	}
+}
+
+// addRegistryLogin adds the step which logs into the cloud registry before the steps if any of them build images or
+// charts and points $DOCKER_CONFIG of the steps at the docker config it writes rather than a static docker config
+// secret. Returns the steps and the volume of the docker config or nil if no login is required
+func addRegistryLogin(steps []corev1.Container, registry string) ([]corev1.Container, *corev1.Volume) {
+	login := registryLoginStep(registry)
+	if login == nil {
+		return steps, nil
+	}
+	found := false
+	for i := range steps {
+		if isImageOrChartStep(&steps[i]) {
+			found = true
+			break
+		}
+	}
+	if !found {
+		return steps, nil
+	}
+	env := corev1.EnvVar{
+		Name:  "DOCKER_CONFIG",
+		Value: dockerConfigDir,
+	}
+	answer := []corev1.Container{*login}
+	for _, step := range steps {
+		step.Env = setEnvVar(step.Env, env)
+		step.VolumeMounts = append(append([]corev1.VolumeMount{}, step.VolumeMounts...), corev1.VolumeMount{Name: dockerConfigVolume, MountPath: dockerConfigDir})
+		answer = append(answer, step)
+	}
+	volume := &corev1.Volume{
+		Name: dockerConfigVolume,
+		VolumeSource: corev1.VolumeSource{
+			EmptyDir: &corev1.EmptyDirVolumeSource{
+				Medium: corev1.StorageMediumMemory,
+			},
+		},
+	}
+	return answer, volume
+}
+
+// ensureECRRepository inserts a step before the first step which builds images or charts which creates the ECR
+// repository of the image of the application if it does not exist as ECR does not create repositories on push
+func ensureECRRepository(steps []corev1.Container, registry string, appName string) []corev1.Container {
+	if !isECRRegistry(registry) {
+		return steps
+	}
+	for i := range steps {
+		commandLine := strings.Join(append(append([]string{}, steps[i].Command...), steps[i].Args...), " ")
+		if strings.Contains(commandLine, "jx step pre build") {
+			// the build pack already creates the repository
+			return steps
+		}
+	}
+	for i := range steps {
+		if isImageOrChartStep(&steps[i]) {
+			image := fmt.Sprintf(`%s/${DOCKER_REGISTRY_ORG:-$ORG}/%s`, registry, appName)
+			step := createStepFrom(&steps[i], "ensure-repository", []string{"/bin/sh", "-c", fmt.Sprintf(`jx step pre build --image "%s"`, image)})
+			answer := append([]corev1.Container{}, steps[0:i]...)
+			answer = append(answer, step)
+			return append(answer, steps[i:]...)
+		}
+	}
+	return steps
+}
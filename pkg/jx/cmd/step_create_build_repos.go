@@ -0,0 +1,206 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/jenkins-x/jx/pkg/auth"
+	"github.com/jenkins-x/jx/pkg/gits"
+	"github.com/jenkins-x/jx/pkg/log"
+	"github.com/jenkins-x/jx/pkg/util"
+	"github.com/pkg/errors"
+)
+
+// createRepositoryBuilds clones each of the --repo git repositories and generates their builds one after another.
+// The repositories can be hosted on different git providers as the credentials and the API clients are resolved for
+// the git server of each repository. The builds of the other repositories are still generated if one fails
+func (o *StepCreateBuildOptions) createRepositoryBuilds() error {
+	dir, err := util.CreateWorkspace("jx-create-build-")
+	if err != nil {
+		return err
+	}
+	defer os.RemoveAll(dir)
+
+	if len(o.Repos) > 1 && o.OutputDir == "" && !o.commitMode() {
+		// the builds of each repository are written to its own directory so that they can be told apart
+		return errors.Wrap(util.MissingOption("output-dir"), "the builds of more than one --repo cannot be written to the standard output")
+	}
+	saved := o.saveRepositoryBuildOptions()
+	defer o.resetRepositoryBuild(saved)
+	outputDir := o.OutputDir
+
+	// the repositories are cloned through the git proxy of the team and never prompt for credentials in batch mode
+	teamSettings, err := o.loadTeamSettings()
+	if err != nil {
+		return util.WithExitCode(errors.Wrap(err, "failed to load the team settings"), util.ExitCodeUnreachable)
+	}
+	err = o.configureGitProxy(&teamSettings.BuildDefaults.GitProxy)
+	if err != nil {
+		return err
+	}
+	o.disableGitPrompts()
+	repoOptions := *saved
+	repoOptions.gitClient = o.Git()
+
+	failed := []string{}
+	var firstErr error
+	for i, repo := range o.Repos {
+		// the builds of each repository are generated from the same options whatever the order of the repositories
+		o.resetRepositoryBuild(&repoOptions)
+		gitURL, ref := parseRepositoryRef(repo)
+		err = o.createRepositoryBuild(gitURL, ref, filepath.Join(dir, fmt.Sprintf("repo-%d", i)), outputDir)
+		if err != nil {
+			log.Warnf("Failed to generate the builds of %s: %s\n", gitURL, err)
+			failed = append(failed, gitURL)
+			if firstErr == nil {
+				firstErr = err
+			}
+		}
+	}
+	if len(failed) > 0 {
+		return errors.Wrapf(firstErr, "failed to generate the builds of %d of the %d repositories %s", len(failed), len(o.Repos), strings.Join(failed, ", "))
+	}
+	return nil
+}
+
+// repositoryBuildOptions the options of the command which the generation of the builds of a repository changes
+type repositoryBuildOptions struct {
+	dir         string
+	outputDir   string
+	branch      string
+	branchKind  string
+	packsDir    string
+	buildNumber int
+	gitClient   gits.Gitter
+}
+
+// saveRepositoryBuildOptions returns the options of the command which the generation of the builds of a repository
+// changes
+func (o *StepCreateBuildOptions) saveRepositoryBuildOptions() *repositoryBuildOptions {
+	return &repositoryBuildOptions{
+		dir:         o.Dir,
+		outputDir:   o.OutputDir,
+		branch:      o.Branch,
+		branchKind:  o.BranchKind,
+		packsDir:    o.PacksDir,
+		buildNumber: o.BuildNumber,
+		gitClient:   o.Git(),
+	}
+}
+
+// resetRepositoryBuild restores the saved options and clears the state of the generation of the builds of the
+// previous repository such as its generated Dockerfile and the choices of the wizard. The pod templates of the cluster
+// are kept as they are the same for each repository
+func (o *StepCreateBuildOptions) resetRepositoryBuild(saved *repositoryBuildOptions) {
+	o.Dir = saved.dir
+	o.OutputDir = saved.outputDir
+	o.Branch = saved.branch
+	o.BranchKind = saved.branchKind
+	o.PacksDir = saved.packsDir
+	o.BuildNumber = saved.buildNumber
+	o.GitClient = saved.gitClient
+
+	o.buildDefaults = nil
+	o.quota = nil
+	o.buildPackURL = ""
+	o.targetNamespace = ""
+	o.explanation = nil
+	o.labelConfigs = nil
+	o.directives = nil
+	o.links = nil
+	o.dockerRegistry = ""
+	o.extensionSteps = nil
+	o.generatedDockerfile = false
+	o.documents = nil
+	o.bundlePodTemplates = nil
+	o.containerOverrides = nil
+	o.applyBuilds = false
+}
+
+// createRepositoryBuild clones the repository at the ref and generates its builds into the directory of the repository
+// in the output directory
+func (o *StepCreateBuildOptions) createRepositoryBuild(gitURL string, ref string, dir string, outputDir string) error {
+	gitInfo, err := gits.ParseGitURL(gitURL)
+	if err != nil {
+		return util.WithExitCode(err, util.ExitCodeValidation)
+	}
+	log.Infof("Generating the builds of %s\n", util.ColorInfo(gitURL))
+	err = o.cloneRepository(gitInfo, gitURL, dir)
+	if err != nil {
+		return util.WithExitCode(err, util.ExitCodeUnreachable)
+	}
+	if ref != "" {
+		err = o.Git().Checkout(dir, ref)
+		if err != nil {
+			return util.WithExitCode(errors.Wrapf(err, "failed to checkout %s", ref), util.ExitCodeUnreachable)
+		}
+	}
+	o.Dir = dir
+	o.Branch = ref
+	if outputDir != "" {
+		o.OutputDir = filepath.Join(outputDir, gitInfo.Host, gitInfo.Organisation, gitInfo.Name)
+	}
+	return o.createBuilds()
+}
+
+// cloneRepository clones the repository with the credentials of the user of its git server in the git auth config
+// service. The repository is cloned anonymously if there are no credentials for its git server. The credentials are
+// passed to git in its environment so that they are not visible in the list of processes
+func (o *StepCreateBuildOptions) cloneRepository(gitInfo *gits.GitRepositoryInfo, gitURL string, dir string) error {
+	// git clones into the current directory of the command
+	err := os.MkdirAll(dir, util.DefaultWritePermissions)
+	if err != nil {
+		return err
+	}
+	gitter := o.Git()
+	authConfigSvc, err := o.CreateGitAuthConfigService()
+	if err == nil && strings.HasPrefix(gitURL, "http") {
+		config := authConfigSvc.Config()
+		server := config.GetServer(gitInfo.HostURL())
+		if server != nil {
+			// never prompt as the git servers are resolved for each repository in one run
+			userAuth, err := config.PickServerUserAuth(server, "git user name", true, gitInfo.Organisation, o.In, o.Out, o.Err)
+			if err == nil && userAuth != nil && !userAuth.IsInvalid() {
+				gitter, err = o.credentialsGitClient(gitURL, userAuth)
+				if err != nil {
+					return err
+				}
+			}
+		}
+	}
+	err = gitter.Clone(gitURL, dir)
+	if err != nil {
+		return errors.Wrapf(err, "failed to clone %s", gitURL)
+	}
+	return nil
+}
+
+// credentialsGitClient returns the git client which authenticates to the git server of the URL with the credentials
+func (o *StepCreateBuildOptions) credentialsGitClient(gitURL string, userAuth *auth.UserAuth) (gits.Gitter, error) {
+	gitCLI, ok := o.Git().(*gits.GitCLI)
+	if !ok {
+		return nil, fmt.Errorf("the credentials of %s can only be passed to the git command line but the git client is %T", gitURL, o.Git())
+	}
+	config, err := gits.CredentialsConfig(gitURL, userAuth)
+	if err != nil {
+		return nil, err
+	}
+	answer := gitCLI.WithConfig(config)
+	err = answer.VerifyConfigEnv()
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to pass the credentials of %s to git", gitURL)
+	}
+	return answer, nil
+}
+
+// parseRepositoryRef splits a repository such as 'https://gitlab.com/myorg/myapp.git#develop' into its git URL and
+// the ref which is checked out
+func parseRepositoryRef(repo string) (string, string) {
+	idx := strings.LastIndex(repo, "#")
+	if idx < 0 {
+		return repo, ""
+	}
+	return repo[0:idx], repo[idx+1:]
+}
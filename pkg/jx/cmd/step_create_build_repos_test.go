@@ -0,0 +1,75 @@
+package cmd
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/jenkins-x/jx/pkg/auth"
+	"github.com/jenkins-x/jx/pkg/gits"
+	"github.com/jenkins-x/jx/pkg/helm"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseRepositoryRef(t *testing.T) {
+	t.Parallel()
+	for repo, expected := range map[string][]string{
+		"https://gitlab.com/myorg/myapp.git":               {"https://gitlab.com/myorg/myapp.git", ""},
+		"https://github.com/myorg/myapp.git#develop":       {"https://github.com/myorg/myapp.git", "develop"},
+		"https://github.com/myorg/myapp.git#v1.2.3":        {"https://github.com/myorg/myapp.git", "v1.2.3"},
+		"https://github.com/myorg/myapp.git#feature/login": {"https://github.com/myorg/myapp.git", "feature/login"},
+		"https://git.acme.com/my#org/myapp.git#develop":    {"https://git.acme.com/my#org/myapp.git", "develop"},
+		"https://github.com/myorg/myapp.git#":              {"https://github.com/myorg/myapp.git", ""},
+	} {
+		gitURL, ref := parseRepositoryRef(repo)
+		assert.Equal(t, expected[0], gitURL, "the git URL of %s", repo)
+		assert.Equal(t, expected[1], ref, "the ref of %s", repo)
+	}
+}
+
+func TestRepositoryGitProviders(t *testing.T) {
+	// the git auth config is loaded from $JX_HOME
+	jxHome, err := ioutil.TempDir("", "test-repository-git-providers")
+	assert.NoError(t, err)
+	defer os.RemoveAll(jxHome)
+	jxHomeEnv := os.Getenv("JX_HOME")
+	defer os.Setenv("JX_HOME", jxHomeEnv)
+	os.Setenv("JX_HOME", jxHome)
+
+	authConfigSvc := &auth.AuthConfigService{FileName: filepath.Join(jxHome, GitAuthConfigFile)}
+	authConfigSvc.SetConfig(&auth.AuthConfig{
+		Servers: []*auth.AuthServer{
+			{
+				URL:         "https://github.acme.com",
+				Kind:        gits.KindGitHub,
+				Users:       []*auth.UserAuth{{Username: "jenkins", ApiToken: "github-token"}},
+				CurrentUser: "jenkins",
+			},
+			{
+				URL:         "https://gitlab.acme.com",
+				Kind:        gits.KindGitlab,
+				Users:       []*auth.UserAuth{{Username: "jenkins", ApiToken: "gitlab-token"}},
+				CurrentUser: "jenkins",
+			},
+		},
+	})
+	err = authConfigSvc.SaveConfig()
+	assert.NoError(t, err)
+
+	o := &CommonOptions{}
+	ConfigureTestOptionsWithResources(o, nil, nil, gits.NewGitCLI(), helm.NewHelmCLI("helm", helm.V2, jxHome, true))
+	for hostURL, kind := range map[string]string{
+		"https://github.acme.com": gits.KindGitHub,
+		"https://gitlab.acme.com": gits.KindGitlab,
+	} {
+		actual, err := o.GitServerHostURLKind(hostURL)
+		assert.NoError(t, err)
+		assert.Equal(t, kind, actual, "the kind of the git server %s should be the kind in the git auth config", hostURL)
+
+		provider, err := o.gitProviderForURL(hostURL+"/myorg/myapp.git", "git user name")
+		if assert.NoError(t, err) {
+			assert.Equal(t, kind, provider.Kind(), "the git provider of %s", hostURL)
+		}
+	}
+}
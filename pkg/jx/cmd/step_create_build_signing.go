@@ -0,0 +1,109 @@
+package cmd
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"strings"
+
+	"github.com/ghodss/yaml"
+	"github.com/jenkins-x/jx/pkg/config"
+	"github.com/jenkins-x/jx/pkg/kube"
+	"github.com/jenkins-x/jx/pkg/signing"
+	"github.com/pkg/errors"
+)
+
+const (
+	// EnvSigningPassphrase the environment variable containing the passphrase of the PGP key used to sign builds
+	EnvSigningPassphrase = "JX_SIGNING_PASSPHRASE"
+)
+
+// signBuild annotates the build with the build pack it was generated from and then signs it with the PGP private key
+// of the SignKey option, storing the signature in the AnnotationSignature annotation
+func (o *StepCreateBuildOptions) signBuild(build *Build, projectConfig *config.ProjectConfig) error {
+	privateKey, err := ioutil.ReadFile(o.SignKey)
+	if err != nil {
+		return errors.Wrapf(err, "failed to read the signing key %s", o.SignKey)
+	}
+	if build.Annotations == nil {
+		build.Annotations = map[string]string{}
+	}
+	build.Annotations[kube.AnnotationBuildPackRef] = o.buildPackRef(projectConfig)
+	data, err := buildSignedData(build)
+	if err != nil {
+		return err
+	}
+	signature, err := signing.SignDetached(data, privateKey, []byte(os.Getenv(EnvSigningPassphrase)))
+	if err != nil {
+		return errors.Wrapf(err, "failed to sign build %s", build.Name)
+	}
+	build.Annotations[kube.AnnotationSignature] = signature
+	return nil
+}
+
+// buildPackRef returns the git URL of the build pack along with the commit of its local clone or its git ref
+func (o *StepCreateBuildOptions) buildPackRef(projectConfig *config.ProjectConfig) string {
+	gitURL := projectConfig.BuildPackGitURL
+	if gitURL == "" {
+		gitURL = o.buildPackURL
+	}
+	dir := buildPackCloneDir(gitURL)
+	if dir != "" {
+		sha, err := o.Git().GetLatestCommitSha(dir)
+		if err == nil && sha != "" {
+			return gitURL + "@" + sha
+		}
+	}
+	if projectConfig.BuildPackGitURef != "" {
+		return gitURL + "@" + projectConfig.BuildPackGitURef
+	}
+	return gitURL
+}
+
+// buildSignedData returns the YAML of the build without its signature which is the data that is signed
+func buildSignedData(build *Build) ([]byte, error) {
+	copy := *build
+	copy.Annotations = map[string]string{}
+	for k, v := range build.Annotations {
+		if k != kube.AnnotationSignature {
+			copy.Annotations[k] = v
+		}
+	}
+	return yaml.Marshal(&copy)
+}
+
+// VerifyBuild verifies the signature of the build against the ASCII armored PGP public keys and that it was generated
+// from one of the approved build packs. An approved build pack is either a git URL which approves any commit or a git
+// URL and commit such as 'https://github.com/jenkins-x/draft-packs.git@1234abcd'. If no build packs are approved then
+// any build pack is allowed. Returns the identity of the signer
+func VerifyBuild(build *Build, publicKeys []byte, approvedBuildPacks []string) (string, error) {
+	signature := ""
+	if build.Annotations != nil {
+		signature = build.Annotations[kube.AnnotationSignature]
+	}
+	if signature == "" {
+		return "", fmt.Errorf("build %s is not signed", build.Name)
+	}
+	data, err := buildSignedData(build)
+	if err != nil {
+		return "", err
+	}
+	signer, err := signing.VerifyDetached(data, signature, publicKeys)
+	if err != nil {
+		return "", errors.Wrapf(err, "failed to verify build %s", build.Name)
+	}
+	if len(approvedBuildPacks) > 0 {
+		ref := build.Annotations[kube.AnnotationBuildPackRef]
+		approved := false
+		for _, a := range approvedBuildPacks {
+			if ref == a || strings.HasPrefix(ref, a+"@") {
+				approved = true
+				break
+			}
+		}
+		if !approved {
+			return signer, fmt.Errorf("build %s was generated from build pack %s which is not one of the approved build packs: %s", build.Name, ref, strings.Join(approvedBuildPacks, ", "))
+		}
+	}
+	return signer, nil
+}
@@ -0,0 +1,144 @@
+package cmd
+
+import (
+	"os"
+	"path/filepath"
+
+	"github.com/jenkins-x/jx/pkg/apis/jenkins.io/v1"
+	"github.com/jenkins-x/jx/pkg/config"
+	corev1 "k8s.io/api/core/v1"
+)
+
+const (
+	sonarScannerImage      = "sonarsource/sonar-scanner-cli"
+	sonarAnalysisStepName  = "sonar-analysis"
+	sonarDecorateStepName  = "sonar-decorate"
+	sonarHostURLEnv        = "SONAR_HOST_URL"
+	sonarTokenEnv          = "SONAR_TOKEN"
+	defaultSonarTokenKey   = "token"
+	sonarMavenGoal         = "org.sonarsource.scanner.maven:sonar-maven-plugin:sonar"
+	sonarScannerReportTask = ".scannerwork/report-task.txt"
+	sonarMavenReportTask   = "target/sonar/report-task.txt"
+)
+
+// sonarLanguage the analysis of the projects of a language
+type sonarLanguage struct {
+	// files the files of the project which detect the language
+	files []string
+	// maven whether the project is analysed by the Maven plugin in the image of the project rather than the scanner
+	maven bool
+	// properties the analysis properties of the language such as the coverage reports
+	properties []string
+}
+
+// sonarLanguages the languages in the order they are detected
+var sonarLanguages = []sonarLanguage{
+	{
+		files: []string{"pom.xml"},
+		maven: true,
+	},
+	{
+		files:      []string{"build.gradle", "build.gradle.kts"},
+		properties: []string{"-Dsonar.java.binaries=build/classes"},
+	},
+	{
+		files:      []string{"go.mod"},
+		properties: []string{"-Dsonar.exclusions=**/*_test.go,vendor/**", "-Dsonar.tests=.", "-Dsonar.test.inclusions=**/*_test.go", "-Dsonar.go.coverage.reportPaths=coverage.out"},
+	},
+	{
+		files:      []string{"package.json"},
+		properties: []string{"-Dsonar.exclusions=node_modules/**", "-Dsonar.javascript.lcov.reportPaths=coverage/lcov.info"},
+	},
+	{
+		files:      []string{"pyproject.toml", "setup.py", "requirements.txt"},
+		properties: []string{"-Dsonar.python.coverage.reportPaths=coverage.xml"},
+	},
+}
+
+// detectSonarLanguage returns the analysis of the language of the project or the analysis of any language
+func detectSonarLanguage(dir string) sonarLanguage {
+	for _, language := range sonarLanguages {
+		for _, file := range language.files {
+			if _, err := os.Stat(filepath.Join(dir, file)); err == nil {
+				return language
+			}
+		}
+	}
+	return sonarLanguage{}
+}
+
+// applySonarSteps adds the SonarQube analysis of the code of the project before the first step which builds an image
+// or chart and, for pull requests, a step which comments the quality gate of the analysis on the pull request
+func applySonarSteps(steps []corev1.Container, sonarQube *v1.SonarQube, projectConfig *config.ProjectConfig, dir string, projectName string, branch string, explain *BuildExplanation) []corev1.Container {
+	if sonarQube.ServerURL == "" || projectConfig.DisableSonarQube || len(steps) == 0 {
+		return steps
+	}
+	idx := len(steps)
+	for i := range steps {
+		if isImageOrChartStep(&steps[i]) {
+			idx = i
+			break
+		}
+	}
+	template := steps[0]
+	if idx > 0 {
+		template = steps[idx-1]
+	}
+	projectKey := projectConfig.SonarQubeProjectKey
+	if projectKey == "" {
+		projectKey = projectName
+	}
+	env := []corev1.EnvVar{
+		{
+			Name:  sonarHostURLEnv,
+			Value: sonarQube.ServerURL,
+		},
+	}
+	if sonarQube.TokenSecret != "" {
+		key := sonarQube.TokenSecretKey
+		if key == "" {
+			key = defaultSonarTokenKey
+		}
+		env = append(env, corev1.EnvVar{
+			Name: sonarTokenEnv,
+			ValueFrom: &corev1.EnvVarSource{
+				SecretKeyRef: &corev1.SecretKeySelector{
+					LocalObjectReference: corev1.LocalObjectReference{
+						Name: sonarQube.TokenSecret,
+					},
+					Key: key,
+				},
+			},
+		})
+	}
+
+	language := detectSonarLanguage(dir)
+	analysis := createStepFrom(&template, sonarAnalysisStepName, []string{"sonar-scanner", "-Dsonar.projectKey=" + projectKey, "-Dsonar.sources=."})
+	reportTask := sonarScannerReportTask
+	if language.maven {
+		// kubernetes expands the $(VAR) references to the environment variables of the step
+		analysis.Command = []string{"mvn"}
+		analysis.Args = []string{sonarMavenGoal, "-Dsonar.projectKey=" + projectKey, "-Dsonar.host.url=$(" + sonarHostURLEnv + ")"}
+		if sonarQube.TokenSecret != "" {
+			analysis.Args = append(analysis.Args, "-Dsonar.login=$("+sonarTokenEnv+")")
+		}
+		reportTask = sonarMavenReportTask
+	} else {
+		analysis.Image = sonarScannerImage
+	}
+	analysis.Args = append(analysis.Args, language.properties...)
+	prNumber := pullRequestNumber(branch)
+	if prNumber != "" {
+		analysis.Args = append(analysis.Args, "-Dsonar.pullrequest.key="+prNumber, "-Dsonar.pullrequest.branch="+branch)
+	}
+	analysis.Env = append(append([]corev1.EnvVar{}, template.Env...), env...)
+	sonarSteps := []corev1.Container{analysis}
+
+	if prNumber != "" && !sonarQube.DisablePRDecoration {
+		decorate := createStepFrom(&template, sonarDecorateStepName, []string{"jx", "step", "sonar", "decorate", "--report-task", reportTask, "--pull-request", prNumber})
+		decorate.Env = analysis.Env
+		sonarSteps = append(sonarSteps, decorate)
+	}
+	explain.explainSteps(sonarSteps, "the SonarQube server of the build defaults")
+	return append(append(append([]corev1.Container{}, steps[:idx]...), sonarSteps...), steps[idx:]...)
+}
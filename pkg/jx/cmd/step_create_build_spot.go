@@ -0,0 +1,40 @@
+package cmd
+
+import (
+	"strconv"
+
+	"github.com/jenkins-x/jx/pkg/apis/jenkins.io/v1"
+	"github.com/jenkins-x/jx/pkg/kube"
+)
+
+// defaultSpotMaxRetries the number of times a preempted build pod is retried when the team build defaults do not
+// configure it
+const defaultSpotMaxRetries = 2
+
+// applySpot schedules the build on the spot nodes of the team build defaults and annotates it with the number of
+// times 'jx controller preemption' retries it when its node is preempted
+func applySpot(build *Build, spot *v1.BuildSpot, explain *BuildExplanation) {
+	if !spot.Enabled {
+		return
+	}
+	if len(spot.NodeSelector) > 0 {
+		if build.Spec.NodeSelector == nil {
+			build.Spec.NodeSelector = map[string]string{}
+		}
+		for k, v := range spot.NodeSelector {
+			build.Spec.NodeSelector[k] = v
+		}
+	}
+	build.Spec.Tolerations = append(build.Spec.Tolerations, spot.Tolerations...)
+	maxRetries := spot.MaxRetries
+	if maxRetries <= 0 {
+		maxRetries = defaultSpotMaxRetries
+	}
+	if build.Annotations == nil {
+		build.Annotations = map[string]string{}
+	}
+	build.Annotations[kube.AnnotationMaxPreemptionRetries] = strconv.Itoa(maxRetries)
+	for _, step := range build.Spec.Steps {
+		explain.explainMutation(step.Name, "runs on a spot node and is retried up to %d times when the node is preempted", maxRetries)
+	}
+}
@@ -1,22 +1,36 @@
 package cmd_test
 
 import (
+	"bytes"
+	"encoding/json"
+	"fmt"
 	"io/ioutil"
 	"os"
+	"os/exec"
 	"path"
 	"path/filepath"
+	"strings"
 	"testing"
+	"time"
 
+	"github.com/ghodss/yaml"
+	"github.com/jenkins-x/jx/pkg/apis/jenkins.io/v1"
+	v1fake "github.com/jenkins-x/jx/pkg/client/clientset/versioned/fake"
+	"github.com/jenkins-x/jx/pkg/config"
 	"github.com/jenkins-x/jx/pkg/gits"
 	"github.com/jenkins-x/jx/pkg/helm"
 	"github.com/jenkins-x/jx/pkg/jx/cmd"
 	"github.com/jenkins-x/jx/pkg/kube"
 	"github.com/jenkins-x/jx/pkg/tests"
 	"github.com/jenkins-x/jx/pkg/util"
+	"github.com/spf13/cobra"
 	"github.com/stretchr/testify/assert"
 	corev1 "k8s.io/api/core/v1"
+	networkingv1 "k8s.io/api/networking/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
+	k8sTesting "k8s.io/client-go/testing"
 )
 
 const (
@@ -160,3 +174,3676 @@ func testStepCreateBuild(t *testing.T, tempDir string, testcase string, srcDir s
 	}
 	return err
 }
+
+// newStepCreateBuildTestDir creates a temporary directory which the test removes once it completes
+func newStepCreateBuildTestDir(t *testing.T, prefix string) string {
+	dir, err := ioutil.TempDir("", prefix)
+	assert.NoError(t, err)
+	return dir
+}
+
+// writeStepCreateBuildProjectConfig writes the jenkins-x.yml of the project in the directory
+func writeStepCreateBuildProjectConfig(t *testing.T, dir string, projectYaml string) {
+	err := ioutil.WriteFile(filepath.Join(dir, config.ProjectConfigFileName), []byte(projectYaml), util.DefaultWritePermissions)
+	assert.NoError(t, err)
+}
+
+// newStepCreateBuildTestOptions returns the options which generate the builds of the project in the directory using
+// the fake clients of the resources. The git CLI is used if the gitter is nil
+func newStepCreateBuildTestOptions(dir string, gitter gits.Gitter, k8sObjects []runtime.Object, jxObjects []runtime.Object) *cmd.StepCreateBuildOptions {
+	if gitter == nil {
+		gitter = gits.NewGitCLI()
+	}
+	o := &cmd.StepCreateBuildOptions{}
+	cmd.ConfigureTestOptionsWithResources(&o.CommonOptions, k8sObjects, jxObjects, gitter, helm.NewHelmCLI("helm", helm.V2, dir, true))
+	o.Dir = dir
+	return o
+}
+
+// loadStepCreateBuild loads the generated build in the file
+func loadStepCreateBuild(t *testing.T, fileName string) *cmd.Build {
+	build := &cmd.Build{}
+	data, err := ioutil.ReadFile(fileName)
+	if assert.NoError(t, err) {
+		err = yaml.Unmarshal(data, build)
+		assert.NoError(t, err)
+	}
+	return build
+}
+
+func TestStepCreateBuildTeamBuildDefaults(t *testing.T) {
+	t.Parallel()
+	testDir := newStepCreateBuildTestDir(t, "test-step-create-build-defaults")
+	defer os.RemoveAll(testDir)
+
+	projectYaml := `builds:
+  - kind: release
+    build:
+      steps:
+        - name: run-tests
+          args:
+          - make
+          - test
+`
+	writeStepCreateBuildProjectConfig(t, testDir, projectYaml)
+
+	devEnv := kube.NewPermanentEnvironment("dev")
+	devEnv.Spec.Namespace = "jx"
+	devEnv.Spec.Kind = v1.EnvironmentKindTypeDevelopment
+	devEnv.Spec.TeamSettings.BuildDefaults = v1.BuildDefaults{
+		DefaultImage:  "docker.io/golang:1.11",
+		EngineVersion: "v1alpha2",
+		ImageMirrors: map[string]string{
+			"docker.io/": "mirror.acme.com/",
+		},
+		Resources: &corev1.ResourceRequirements{
+			Requests: corev1.ResourceList{
+				corev1.ResourceCPU: resource.MustParse("100m"),
+			},
+		},
+	}
+
+	o := newStepCreateBuildTestOptions(testDir, nil, nil, []runtime.Object{devEnv})
+	o.OutputDir = testDir
+
+	err := o.Run()
+	assert.NoError(t, err)
+
+	build := loadStepCreateBuild(t, filepath.Join(testDir, actualBuildFileName))
+
+	assert.Equal(t, "build.knative.dev/v1alpha2", build.APIVersion)
+	if assert.Equal(t, 1, len(build.Spec.Steps)) {
+		step := build.Spec.Steps[0]
+		assert.Equal(t, "mirror.acme.com/golang:1.11", step.Image)
+		assert.Equal(t, "100m", step.Resources.Requests.Cpu().String())
+	}
+}
+
+func TestStepCreateBuildImageMirrorsLongestPrefix(t *testing.T) {
+	t.Parallel()
+	testDir := newStepCreateBuildTestDir(t, "test-step-create-build-mirrors")
+	defer os.RemoveAll(testDir)
+
+	projectYaml := `builds:
+  - kind: release
+    build:
+      steps:
+        - name: run-tests
+          image: docker.io/library/golang:1.11
+          args:
+          - make
+          - test
+`
+	writeStepCreateBuildProjectConfig(t, testDir, projectYaml)
+
+	devEnv := kube.NewPermanentEnvironment("dev")
+	devEnv.Spec.Namespace = "jx"
+	devEnv.Spec.Kind = v1.EnvironmentKindTypeDevelopment
+	devEnv.Spec.TeamSettings.BuildDefaults = v1.BuildDefaults{
+		EngineVersion: "v1alpha2",
+		ImageMirrors: map[string]string{
+			"docker.io/":                "mirror.acme.com/",
+			"docker.io/library/":        "library.acme.com/",
+			"docker.io/library/golang":  "golang.acme.com/golang",
+			"docker.io/library/golang2": "golang2.acme.com/golang",
+			"gcr.io/":                   "gcr.acme.com/",
+		},
+	}
+
+	o := newStepCreateBuildTestOptions(testDir, nil, nil, []runtime.Object{devEnv})
+	o.OutputDir = testDir
+
+	err := o.Run()
+	assert.NoError(t, err)
+
+	build := loadStepCreateBuild(t, filepath.Join(testDir, actualBuildFileName))
+	if assert.Equal(t, 1, len(build.Spec.Steps)) {
+		assert.Equal(t, "golang.acme.com/golang:1.11", build.Spec.Steps[0].Image)
+	}
+}
+
+func TestStepCreateBuildPreflight(t *testing.T) {
+	t.Parallel()
+	testDir := newStepCreateBuildTestDir(t, "test-step-create-build-preflight")
+	defer os.RemoveAll(testDir)
+
+	projectYaml := `buildPack: maven
+builds:
+  - kind: release
+    build:
+      steps:
+        - args:
+          - mvn
+          - deploy
+`
+	writeStepCreateBuildProjectConfig(t, testDir, projectYaml)
+
+	k8sObjects := []runtime.Object{
+		&corev1.ConfigMap{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      kube.ConfigMapJenkinsPodTemplates,
+				Namespace: "jx",
+			},
+			Data: map[string]string{
+				"maven": MavenBuildPackYaml,
+			},
+		},
+		&corev1.ServiceAccount{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "jenkins",
+				Namespace: "jx",
+			},
+		},
+	}
+
+	o := newStepCreateBuildTestOptions(testDir, nil, k8sObjects, nil)
+	o.OutputDir = testDir
+	o.Preflight = true
+
+	err := o.Run()
+	assert.Error(t, err)
+
+	// the preflight checks should fail before any builds are generated
+	exists, err := util.FileExists(filepath.Join(testDir, actualBuildFileName))
+	assert.NoError(t, err)
+	assert.False(t, exists, "should not have generated a build when the preflight checks fail")
+}
+
+func TestStepCreateBuildFromStep(t *testing.T) {
+	t.Parallel()
+	testDir := newStepCreateBuildTestDir(t, "test-step-create-build-from-step")
+	defer os.RemoveAll(testDir)
+
+	projectYaml := `builds:
+  - kind: release
+    build:
+      steps:
+        - name: build
+          image: golang:1.11
+        - name: test
+          image: golang:1.11
+        - name: deploy
+          image: golang:1.11
+`
+	writeStepCreateBuildProjectConfig(t, testDir, projectYaml)
+
+	o := newStepCreateBuildTestOptions(testDir, nil, nil, nil)
+	o.OutputDir = testDir
+	o.FromStep = "test"
+
+	err := o.Run()
+	assert.NoError(t, err)
+
+	build := loadStepCreateBuild(t, filepath.Join(testDir, actualBuildFileName))
+	if assert.Equal(t, 2, len(build.Spec.Steps)) {
+		assert.Equal(t, "test", build.Spec.Steps[0].Name)
+		assert.Equal(t, "deploy", build.Spec.Steps[1].Name)
+	}
+
+	o.FromStep = "does-not-exist"
+	err = o.Run()
+	assert.Error(t, err)
+}
+
+func TestStepCreateBuildLibrary(t *testing.T) {
+	t.Parallel()
+	testDir := newStepCreateBuildTestDir(t, "test-step-create-build-library")
+	defer os.RemoveAll(testDir)
+
+	projectYaml := `library:
+  kind: maven
+builds:
+  - kind: release
+    build:
+      steps:
+        - name: build
+          image: maven:3
+          args:
+          - mvn
+          - install
+        - name: container-build
+          image: maven:3
+          command:
+          - /bin/sh
+          - -c
+          args:
+          - skaffold build -f skaffold.yaml
+        - name: chart
+          image: maven:3
+          args:
+          - jx
+          - step
+          - helm
+          - release
+`
+	writeStepCreateBuildProjectConfig(t, testDir, projectYaml)
+
+	o := newStepCreateBuildTestOptions(testDir, nil, nil, nil)
+	o.OutputDir = testDir
+
+	err := o.Run()
+	assert.NoError(t, err)
+
+	build := loadStepCreateBuild(t, filepath.Join(testDir, actualBuildFileName))
+	names := []string{}
+	for _, step := range build.Spec.Steps {
+		names = append(names, step.Name)
+	}
+	assert.Equal(t, []string{"build", "publish", "version-pr"}, names)
+	if len(build.Spec.Steps) == 3 {
+		assert.Equal(t, []string{"deploy"}, build.Spec.Steps[1].Args)
+		assert.Equal(t, "maven:3", build.Spec.Steps[1].Image)
+	}
+}
+
+func TestStepCreateBuildTargetNamespace(t *testing.T) {
+	t.Parallel()
+	testDir := newStepCreateBuildTestDir(t, "test-step-create-build-namespace")
+	defer os.RemoveAll(testDir)
+
+	projectYaml := `builds:
+  - kind: release
+    build:
+      steps:
+        - name: build
+          image: golang:1.11
+`
+	writeStepCreateBuildProjectConfig(t, testDir, projectYaml)
+
+	o := newStepCreateBuildTestOptions(testDir, nil, nil, []runtime.Object{kube.NewPermanentEnvironment("staging")})
+	o.OutputDir = testDir
+	o.NoAudit = true
+
+	loadBuild := func() *cmd.Build {
+		build := loadStepCreateBuild(t, filepath.Join(testDir, actualBuildFileName))
+		return build
+	}
+
+	err := o.Run()
+	assert.NoError(t, err)
+	assert.Equal(t, "", loadBuild().Namespace)
+
+	o.Environment = "staging"
+	err = o.Run()
+	assert.NoError(t, err)
+	assert.Equal(t, "jx-staging", loadBuild().Namespace)
+
+	o.Environment = "does-not-exist"
+	err = o.Run()
+	assert.Error(t, err)
+
+	o.Environment = ""
+	o.Namespace = "builds"
+	err = o.Run()
+	assert.NoError(t, err)
+	assert.Equal(t, "builds", loadBuild().Namespace)
+}
+
+func TestStepCreateBuildCommit(t *testing.T) {
+	t.Parallel()
+	testDir := newStepCreateBuildTestDir(t, "test-step-create-build-commit")
+	defer os.RemoveAll(testDir)
+
+	projectYaml := `builds:
+  - kind: release
+    build:
+      steps:
+        - name: build
+          image: golang:1.11
+`
+	writeStepCreateBuildProjectConfig(t, testDir, projectYaml)
+
+	gitter := gits.NewGitCLI()
+	err := gitter.Init(testDir)
+	assert.NoError(t, err)
+	for _, args := range [][]string{{"config", "user.name", "test"}, {"config", "user.email", "test@example.com"}} {
+		_, err = (&util.Command{Dir: testDir, Name: "git", Args: args}).RunWithoutRetry()
+		assert.NoError(t, err)
+	}
+	err = gitter.Add(testDir, "*")
+	assert.NoError(t, err)
+	err = gitter.CommitDir(testDir, "initial import")
+	assert.NoError(t, err)
+
+	o := newStepCreateBuildTestOptions(testDir, gitter, nil, nil)
+	o.NoAudit = true
+	o.Commit = true
+
+	err = o.Run()
+	assert.NoError(t, err)
+	assert.FileExists(t, filepath.Join(testDir, config.DefaultPipelinesDir, actualBuildFileName))
+	changed, err := gitter.HasChanges(testDir)
+	assert.NoError(t, err)
+	assert.False(t, changed, "the generated builds should be committed")
+
+	// regenerating the same builds commits nothing
+	err = o.Run()
+	assert.NoError(t, err)
+	changed, err = gitter.HasChanges(testDir)
+	assert.NoError(t, err)
+	assert.False(t, changed)
+}
+
+func TestStepCreateBuildChartValues(t *testing.T) {
+	t.Parallel()
+	testDir := newStepCreateBuildTestDir(t, "test-step-create-build-chart-values")
+	defer os.RemoveAll(testDir)
+	appDir := filepath.Join(testDir, "myapp")
+	err := os.MkdirAll(appDir, util.DefaultWritePermissions)
+	assert.NoError(t, err)
+
+	projectYaml := `builds:
+  - kind: release
+    build:
+      steps:
+        - name: build
+          image: golang:1.11
+          args:
+          - make
+        - name: deploy
+          args:
+          - helm
+          - upgrade
+          - --install
+          - myapp
+          - charts/myapp
+          - --set
+          - image.tag=1.0.0
+`
+	writeStepCreateBuildProjectConfig(t, appDir, projectYaml)
+
+	o := newStepCreateBuildTestOptions(appDir, nil, nil, nil)
+	o.OutputDir = testDir
+	o.NoAudit = true
+	err = o.Run()
+	assert.NoError(t, err)
+
+	build := loadStepCreateBuild(t, filepath.Join(testDir, actualBuildFileName))
+	if assert.Equal(t, 2, len(build.Spec.Steps)) {
+		assert.Equal(t, []string{"make"}, build.Spec.Steps[0].Args)
+		deploy := build.Spec.Steps[1]
+		assert.Equal(t, []string{"/bin/sh", "-c"}, deploy.Command)
+		assert.Equal(t, []string{`helm upgrade --install myapp charts/myapp --set image.tag=1.0.0 --set "image.repository=${DOCKER_REGISTRY}/${DOCKER_REGISTRY_ORG:-$ORG}/myapp" --set "version=$(cat VERSION)"`}, deploy.Args)
+	}
+}
+
+func TestStepCreateBuildCanary(t *testing.T) {
+	t.Parallel()
+	testDir := newStepCreateBuildTestDir(t, "test-step-create-build-canary")
+	defer os.RemoveAll(testDir)
+	appDir := filepath.Join(testDir, "myapp")
+	err := os.MkdirAll(appDir, util.DefaultWritePermissions)
+	assert.NoError(t, err)
+
+	projectYaml := `deploymentStrategy: canary
+disableChartValues: true
+canary:
+  maxWeight: 30
+builds:
+  - kind: release
+    build:
+      steps:
+        - name: deploy
+          image: alpine/helm:2.11.0
+          args:
+          - helm
+          - upgrade
+          - --install
+          - --namespace
+          - jx-staging
+          - myapp
+          - charts/myapp
+`
+	writeStepCreateBuildProjectConfig(t, appDir, projectYaml)
+
+	o := newStepCreateBuildTestOptions(appDir, nil, nil, nil)
+	o.OutputDir = testDir
+	o.NoAudit = true
+	err = o.Run()
+	assert.NoError(t, err)
+
+	build := loadStepCreateBuild(t, filepath.Join(testDir, actualBuildFileName))
+	if assert.Equal(t, 2, len(build.Spec.Steps)) {
+		canary := build.Spec.Steps[1]
+		assert.Equal(t, "canary", canary.Name)
+		assert.Equal(t, []string{"jx"}, canary.Command)
+		assert.Equal(t, []string{"step", "canary", "--app", "myapp", "--namespace", "jx-staging", "--max-weight", "30"}, canary.Args)
+	}
+}
+
+func TestStepCreateBuildBlueGreen(t *testing.T) {
+	t.Parallel()
+	testDir := newStepCreateBuildTestDir(t, "test-step-create-build-bluegreen")
+	defer os.RemoveAll(testDir)
+	appDir := filepath.Join(testDir, "myapp")
+	err := os.MkdirAll(appDir, util.DefaultWritePermissions)
+	assert.NoError(t, err)
+
+	projectYaml := `deploymentStrategy: blueGreen
+disableChartValues: true
+blueGreen:
+  timeout: 10m
+builds:
+  - kind: release
+    build:
+      steps:
+        - name: deploy
+          image: jenkinsxio/builder-base:0.0.1
+          args:
+          - helm
+          - upgrade
+          - --install
+          - --namespace
+          - jx-staging
+          - myapp
+          - charts/myapp
+`
+	writeStepCreateBuildProjectConfig(t, appDir, projectYaml)
+
+	o := newStepCreateBuildTestOptions(appDir, nil, nil, nil)
+	o.OutputDir = testDir
+	o.NoAudit = true
+	err = o.Run()
+	assert.NoError(t, err)
+
+	build := loadStepCreateBuild(t, filepath.Join(testDir, actualBuildFileName))
+	names := []string{}
+	for _, step := range build.Spec.Steps {
+		names = append(names, step.Name)
+	}
+	assert.Equal(t, []string{"deploy", "verify", "switch", "retire"}, names)
+	if len(build.Spec.Steps) == 4 {
+		assert.Equal(t, []string{`export JX_TRACK=$(jx step bluegreen --app myapp --namespace jx-staging idle) && helm upgrade --install --namespace jx-staging "myapp-${JX_TRACK}" charts/myapp --set "track=${JX_TRACK}"`}, build.Spec.Steps[0].Args)
+		assert.Equal(t, []string{"step", "bluegreen", "--app", "myapp", "--namespace", "jx-staging", "--timeout", "10m", "verify"}, build.Spec.Steps[1].Args)
+	}
+}
+
+func TestStepCreateBuildPostDeploy(t *testing.T) {
+	t.Parallel()
+	testDir := newStepCreateBuildTestDir(t, "test-step-create-build-post-deploy")
+	defer os.RemoveAll(testDir)
+	appDir := filepath.Join(testDir, "myapp")
+	err := os.MkdirAll(appDir, util.DefaultWritePermissions)
+	assert.NoError(t, err)
+
+	projectYaml := `disableChartValues: true
+builds:
+  - kind: release
+    postDeploy:
+      - name: smoke-test
+        args:
+        - curl
+        - -f
+        - http://myapp.jx-staging/health
+    build:
+      steps:
+        - name: deploy
+          image: alpine/helm:2.11.0
+          args:
+          - helm
+          - upgrade
+          - --install
+          - --namespace
+          - jx-staging
+          - myapp
+          - charts/myapp
+`
+	writeStepCreateBuildProjectConfig(t, appDir, projectYaml)
+
+	o := newStepCreateBuildTestOptions(appDir, nil, nil, nil)
+	o.OutputDir = testDir
+	o.NoAudit = true
+	err = o.Run()
+	assert.NoError(t, err)
+
+	build := loadStepCreateBuild(t, filepath.Join(testDir, actualBuildFileName))
+	if assert.Equal(t, 2, len(build.Spec.Steps)) {
+		smokeTest := build.Spec.Steps[1]
+		assert.Equal(t, "smoke-test", smokeTest.Name)
+		assert.Equal(t, "alpine/helm:2.11.0", smokeTest.Image)
+		assert.Equal(t, []string{"/bin/sh", "-c"}, smokeTest.Command)
+		assert.Equal(t, []string{"curl -f http://myapp.jx-staging/health || { jx step helm rollback --release myapp; exit 1; }"}, smokeTest.Args)
+	}
+}
+
+func TestStepCreateBuildInfra(t *testing.T) {
+	t.Parallel()
+	testDir := newStepCreateBuildTestDir(t, "test-step-create-build-infra")
+	defer os.RemoveAll(testDir)
+	appDir := filepath.Join(testDir, "myinfra")
+	err := os.MkdirAll(appDir, util.DefaultWritePermissions)
+	assert.NoError(t, err)
+
+	projectYaml := `terraform:
+  dir: gke
+  image: hashicorp/terraform:0.11.11
+builds:
+  - kind: infra
+`
+	writeStepCreateBuildProjectConfig(t, appDir, projectYaml)
+
+	generate := func(branch string) []corev1.Container {
+		outDir := filepath.Join(testDir, branch)
+		o := newStepCreateBuildTestOptions(appDir, nil, nil, nil)
+		o.OutputDir = outDir
+		o.Branch = branch
+		o.NoAudit = true
+		err := o.Run()
+		assert.NoError(t, err)
+
+		build := loadStepCreateBuild(t, filepath.Join(outDir, "build-infra.yml"))
+		return build.Spec.Steps
+	}
+
+	steps := generate("PR-1")
+	if assert.Equal(t, 3, len(steps)) {
+		assert.Equal(t, "terraform-init", steps[0].Name)
+		assert.Equal(t, "hashicorp/terraform:0.11.11", steps[0].Image)
+		assert.Equal(t, []string{"terraform", "init", "-input=false", "gke"}, steps[0].Args)
+		assert.Equal(t, "terraform-plan", steps[1].Name)
+		assert.Contains(t, steps[1].Args[2], "terraform plan -input=false -lock=true -lock-timeout=5m -no-color -out=tfplan gke > tfplan.txt")
+		assert.Equal(t, "terraform-comment", steps[2].Name)
+		assert.Contains(t, steps[2].Args[2], "jx step pr comment")
+	}
+
+	steps = generate("master")
+	if assert.Equal(t, 2, len(steps)) {
+		assert.Equal(t, "terraform-apply", steps[1].Name)
+		assert.Equal(t, []string{"terraform", "apply", "-input=false", "-lock=true", "-lock-timeout=5m", "-auto-approve", "gke"}, steps[1].Args)
+	}
+}
+
+func TestStepCreateBuildMigrations(t *testing.T) {
+	t.Parallel()
+	testDir := newStepCreateBuildTestDir(t, "test-step-create-build-migrations")
+	defer os.RemoveAll(testDir)
+	appDir := filepath.Join(testDir, "myapp")
+	err := os.MkdirAll(appDir, util.DefaultWritePermissions)
+	assert.NoError(t, err)
+
+	projectYaml := `disableChartValues: true
+builds:
+  - kind: release
+    migrations:
+      - name: flyway
+        args:
+        - flyway
+        - migrate
+    build:
+      steps:
+        - name: deploy
+          image: alpine/helm:2.11.0
+          args:
+          - helm
+          - upgrade
+          - --install
+          - --namespace
+          - jx-staging
+          - myapp
+          - charts/myapp
+`
+	writeStepCreateBuildProjectConfig(t, appDir, projectYaml)
+
+	o := newStepCreateBuildTestOptions(appDir, nil, nil, nil)
+	o.OutputDir = testDir
+	o.NoAudit = true
+	err = o.Run()
+	assert.NoError(t, err)
+
+	build := loadStepCreateBuild(t, filepath.Join(testDir, actualBuildFileName))
+	if assert.Equal(t, 2, len(build.Spec.Steps)) {
+		migrate := build.Spec.Steps[0]
+		assert.Equal(t, "migrate-flyway", migrate.Name)
+		assert.Equal(t, "alpine/helm:2.11.0", migrate.Image)
+		assert.Equal(t, []string{"/bin/sh"}, migrate.Command)
+		assert.Equal(t, []string{"-c", `jx step migrate --app myapp --name flyway --namespace jx-staging --image "${DOCKER_REGISTRY}/${DOCKER_REGISTRY_ORG:-$ORG}/myapp:$(cat VERSION)" -- flyway migrate`}, migrate.Args)
+		assert.Equal(t, "deploy", build.Spec.Steps[1].Name)
+	}
+}
+
+func TestStepCreateBuildExplain(t *testing.T) {
+	t.Parallel()
+	testDir := newStepCreateBuildTestDir(t, "test-step-create-build-explain")
+	defer os.RemoveAll(testDir)
+	appDir := filepath.Join(testDir, "myapp")
+	err := os.MkdirAll(appDir, util.DefaultWritePermissions)
+	assert.NoError(t, err)
+
+	projectYaml := `deploymentStrategy: canary
+builds:
+  - kind: release
+    build:
+      steps:
+        - name: build
+          image: maven:3.6
+          args:
+          - mvn
+          - deploy
+        - name: deploy
+          args:
+          - helm
+          - upgrade
+          - --install
+          - myapp
+          - charts/myapp
+`
+	writeStepCreateBuildProjectConfig(t, appDir, projectYaml)
+
+	o := newStepCreateBuildTestOptions(appDir, nil, nil, nil)
+	o.OutputDir = testDir
+	o.NoAudit = true
+	o.Explain = true
+	err = o.Run()
+	assert.NoError(t, err)
+
+	data, err := ioutil.ReadFile(filepath.Join(testDir, "explain-release.json"))
+	assert.NoError(t, err)
+	explanation := &cmd.BuildExplanation{}
+	err = json.Unmarshal(data, explanation)
+	assert.NoError(t, err)
+	assert.Equal(t, "release", explanation.Kind)
+	if assert.Equal(t, 3, len(explanation.Steps)) {
+		build := explanation.Steps[0]
+		assert.Equal(t, "build", build.Name)
+		assert.Equal(t, "jenkins-x.yml builds[release]", build.Source)
+		assert.Equal(t, "the step", build.Image)
+
+		deploy := explanation.Steps[1]
+		assert.Equal(t, "deploy", deploy.Name)
+		assert.Equal(t, "the previous step", deploy.Image)
+		assert.Equal(t, []string{"deployed via the canary deployment strategy", "added the image and version chart values"}, deploy.Mutations)
+
+		canary := explanation.Steps[2]
+		assert.Equal(t, "canary", canary.Name)
+		assert.Equal(t, "the canary deployment strategy", canary.Source)
+	}
+}
+
+func TestStepCreateBuildPullRequestLabels(t *testing.T) {
+	t.Parallel()
+	testDir := newStepCreateBuildTestDir(t, "test-step-create-build-pr-labels")
+	defer os.RemoveAll(testDir)
+	appDir := filepath.Join(testDir, "myapp")
+	err := os.MkdirAll(appDir, util.DefaultWritePermissions)
+	assert.NoError(t, err)
+
+	projectYaml := `pullRequestLabels:
+  - label: skip-e2e
+    skip:
+    - e2e
+  - label: perf-test
+    env:
+    - name: PERF_TEST
+      value: "true"
+builds:
+  - kind: pullRequest
+    build:
+      steps:
+        - name: unit
+          image: maven
+          args:
+          - mvn
+          - test
+        - name: e2e
+          image: maven
+          args:
+          - mvn
+          - verify
+`
+	writeStepCreateBuildProjectConfig(t, appDir, projectYaml)
+
+	generate := func(name string, labels ...string) []corev1.Container {
+		outDir := filepath.Join(testDir, name)
+		o := newStepCreateBuildTestOptions(appDir, nil, nil, nil)
+		o.OutputDir = outDir
+		o.Branch = "PR-1"
+		o.PullRequestLabels = labels
+		o.NoAudit = true
+		err := o.Run()
+		assert.NoError(t, err)
+
+		build := loadStepCreateBuild(t, filepath.Join(outDir, "build-pullRequest.yml"))
+		return build.Spec.Steps
+	}
+
+	steps := generate("skip", "skip-e2e", "bug")
+	if assert.Equal(t, 1, len(steps)) {
+		assert.Equal(t, "unit", steps[0].Name)
+		assert.NotContains(t, steps[0].Env, corev1.EnvVar{Name: "PERF_TEST", Value: "true"})
+	}
+
+	steps = generate("perf", "perf-test")
+	if assert.Equal(t, 2, len(steps)) {
+		for _, step := range steps {
+			assert.Contains(t, step.Env, corev1.EnvVar{Name: "PERF_TEST", Value: "true"}, "step %s", step.Name)
+		}
+	}
+}
+
+func TestStepCreateBuildCommitDirectives(t *testing.T) {
+	t.Parallel()
+	testDir := newStepCreateBuildTestDir(t, "test-step-create-build-directives")
+	defer os.RemoveAll(testDir)
+	appDir := filepath.Join(testDir, "myapp")
+	err := os.MkdirAll(appDir, util.DefaultWritePermissions)
+	assert.NoError(t, err)
+
+	projectYaml := `commitDirectives:
+  - directive: skip tests
+    skip:
+    - unit
+    - e2e
+builds:
+  - kind: release
+    build:
+      steps:
+        - name: unit
+          image: maven
+          args:
+          - mvn
+          - test
+        - name: e2e
+          image: maven
+          args:
+          - mvn
+          - verify
+        - name: deploy
+          image: maven
+          args:
+          - mvn
+          - deploy
+`
+	writeStepCreateBuildProjectConfig(t, appDir, projectYaml)
+
+	generate := func(name string, message string) string {
+		outDir := filepath.Join(testDir, name)
+		git := &gits.GitFake{Commits: []gits.GitCommit{{SHA: "abc", Message: message}}}
+		o := newStepCreateBuildTestOptions(appDir, git, nil, nil)
+		o.OutputDir = outDir
+		o.Branch = "master"
+		o.NoAudit = true
+		err := o.Run()
+		assert.NoError(t, err)
+		return filepath.Join(outDir, actualBuildFileName)
+	}
+
+	fileName := generate("skip-tests", "fix typo in README [Skip Tests]")
+	build := loadStepCreateBuild(t, fileName)
+	if assert.Equal(t, 1, len(build.Spec.Steps)) {
+		assert.Equal(t, "deploy", build.Spec.Steps[0].Name)
+	}
+
+	fileName = generate("skip-ci", "update docs [skip ci]")
+	exists, err := util.FileExists(fileName)
+	assert.NoError(t, err)
+	assert.False(t, exists, "should not have created %s", fileName)
+}
+
+func TestStepCreateBuildIsolated(t *testing.T) {
+	t.Parallel()
+	testDir := newStepCreateBuildTestDir(t, "test-step-create-build-isolated")
+	defer os.RemoveAll(testDir)
+	appDir := filepath.Join(testDir, "myapp")
+	err := os.MkdirAll(appDir, util.DefaultWritePermissions)
+	assert.NoError(t, err)
+
+	projectYaml := `builds:
+  - kind: pullRequest
+    build:
+      steps:
+        - name: test
+          image: maven
+          args:
+          - mvn
+          - verify
+`
+	writeStepCreateBuildProjectConfig(t, appDir, projectYaml)
+
+	o := newStepCreateBuildTestOptions(appDir, nil, nil, nil)
+	o.OutputDir = testDir
+	o.Branch = "PR-12"
+	o.BuildNumber = 3
+	o.Isolated = true
+	o.NoAudit = true
+	err = o.Run()
+	assert.NoError(t, err)
+
+	build := loadStepCreateBuild(t, filepath.Join(testDir, "build-pullRequest.yml"))
+	ns := "build-myapp-pr-12-pullrequest-3"
+	if assert.Equal(t, 3, len(build.Spec.Steps)) {
+		steps := build.Spec.Steps
+		assert.Equal(t, "create-namespace", steps[0].Name)
+		assert.Equal(t, []string{"step", "isolate", "--namespace", ns, "--ttl", "2h0m0s"}, steps[0].Args)
+		assert.Equal(t, "test", steps[1].Name)
+		assert.Contains(t, steps[1].Env, corev1.EnvVar{Name: "ISOLATED_NAMESPACE", Value: ns})
+		assert.Equal(t, "delete-namespace", steps[2].Name)
+		assert.Equal(t, []string{"step", "isolate", "--namespace", ns, "--delete"}, steps[2].Args)
+	}
+}
+
+func TestStepCreateBuildUntrusted(t *testing.T) {
+	t.Parallel()
+	testDir := newStepCreateBuildTestDir(t, "test-step-create-build-untrusted")
+	defer os.RemoveAll(testDir)
+	appDir := filepath.Join(testDir, "myapp")
+	err := os.MkdirAll(appDir, util.DefaultWritePermissions)
+	assert.NoError(t, err)
+
+	projectYaml := `builds:
+  - kind: pullRequest
+    build:
+      volumes:
+        - name: docker-config
+          volumesource:
+            secret:
+              secretname: jenkins-docker-cfg
+        - name: cache
+          volumesource:
+            emptydir: {}
+      steps:
+        - name: test
+          image: maven
+          args:
+          - mvn
+          - verify
+          env:
+          - name: GIT_TOKEN
+            valuefrom:
+              secretkeyref:
+                name: git-token
+                key: token
+          - name: MAVEN_OPTS
+            value: -Xmx1g
+          volumemounts:
+          - name: docker-config
+            mountpath: /kaniko/.docker
+          - name: cache
+            mountpath: /root/.m2
+`
+	writeStepCreateBuildProjectConfig(t, appDir, projectYaml)
+
+	o := newStepCreateBuildTestOptions(appDir, nil, nil, nil)
+	o.OutputDir = testDir
+	o.Branch = "PR-12"
+	o.TrustLevel = "untrusted"
+	o.NoAudit = true
+	err = o.Run()
+	assert.NoError(t, err)
+
+	build := loadStepCreateBuild(t, filepath.Join(testDir, "build-pullRequest.yml"))
+	assert.Equal(t, "default", build.Spec.ServiceAccountName)
+	if assert.Equal(t, 1, len(build.Spec.Steps)) {
+		step := build.Spec.Steps[0]
+		assert.Equal(t, []corev1.EnvVar{{Name: "MAVEN_OPTS", Value: "-Xmx1g"}}, step.Env)
+		assert.Equal(t, []corev1.VolumeMount{{Name: "cache", MountPath: "/root/.m2"}}, step.VolumeMounts)
+		if assert.NotNil(t, step.SecurityContext) {
+			assert.False(t, *step.SecurityContext.Privileged)
+			assert.False(t, *step.SecurityContext.AllowPrivilegeEscalation)
+		}
+	}
+
+	data, err := ioutil.ReadFile(filepath.Join(testDir, "networkpolicy-pullRequest.yml"))
+	assert.NoError(t, err)
+	policy := &networkingv1.NetworkPolicy{}
+	err = yaml.Unmarshal(data, policy)
+	assert.NoError(t, err)
+	assert.Equal(t, build.Name, policy.Spec.PodSelector.MatchLabels["build.knative.dev/buildName"])
+	assert.Equal(t, []networkingv1.PolicyType{networkingv1.PolicyTypeEgress}, policy.Spec.PolicyTypes)
+	assert.Equal(t, 2, len(policy.Spec.Egress))
+
+	o.TrustLevel = "partial"
+	err = o.Run()
+	assert.Error(t, err)
+}
+
+func TestStepCreateBuildNetworkPolicy(t *testing.T) {
+	t.Parallel()
+	testDir := newStepCreateBuildTestDir(t, "test-step-create-build-network-policy")
+	defer os.RemoveAll(testDir)
+	appDir := filepath.Join(testDir, "myapp")
+	err := os.MkdirAll(appDir, util.DefaultWritePermissions)
+	assert.NoError(t, err)
+
+	projectYaml := `builds:
+  - kind: release
+    build:
+      steps:
+        - name: test
+          image: maven
+          args:
+          - mvn
+          - deploy
+`
+	writeStepCreateBuildProjectConfig(t, appDir, projectYaml)
+
+	devEnv := kube.NewPermanentEnvironment("dev")
+	devEnv.Spec.Namespace = "jx"
+	devEnv.Spec.Kind = v1.EnvironmentKindTypeDevelopment
+	devEnv.Spec.TeamSettings.GitServer = "https://10.1.2.3"
+	devEnv.Spec.TeamSettings.BuildDefaults.EgressEndpoints = []string{"192.168.0.7:8443"}
+	registryConfig := &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      kube.ConfigMapJenkinsDockerRegistry,
+			Namespace: "jx",
+		},
+		Data: map[string]string{
+			"docker.registry": "10.0.0.9:5000",
+		},
+	}
+	registry := &corev1.Service{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "jenkins-x-docker-registry",
+			Namespace: "jx",
+		},
+		Spec: corev1.ServiceSpec{
+			ClusterIP: "10.0.0.9",
+			Selector:  map[string]string{"app": "docker-registry"},
+		},
+	}
+	nexus := &corev1.Service{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "nexus",
+			Namespace: "jx",
+		},
+		Spec: corev1.ServiceSpec{
+			Selector: map[string]string{"app": "nexus"},
+		},
+	}
+	apiServer := &corev1.Endpoints{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "kubernetes",
+			Namespace: "default",
+		},
+		Subsets: []corev1.EndpointSubset{
+			{
+				Addresses: []corev1.EndpointAddress{{IP: "172.16.0.2"}},
+				Ports:     []corev1.EndpointPort{{Port: 6443}},
+			},
+		},
+	}
+
+	o := newStepCreateBuildTestOptions(appDir, nil, []runtime.Object{registryConfig, registry, nexus, apiServer}, []runtime.Object{devEnv})
+	o.OutputDir = testDir
+	o.NetworkPolicy = true
+	o.NoAudit = true
+	err = o.Run()
+	assert.NoError(t, err)
+
+	data, err := ioutil.ReadFile(filepath.Join(testDir, "networkpolicy-release.yml"))
+	assert.NoError(t, err)
+	policy := &networkingv1.NetworkPolicy{}
+	err = yaml.Unmarshal(data, policy)
+	assert.NoError(t, err)
+	egress := policy.Spec.Egress
+	if assert.Equal(t, 6, len(egress)) {
+		assert.Equal(t, int32(53), egress[0].Ports[0].Port.IntVal)
+		assert.Equal(t, "10.1.2.3/32", egress[1].To[0].IPBlock.CIDR)
+		assert.Equal(t, int32(443), egress[1].Ports[0].Port.IntVal)
+		assert.Equal(t, map[string]string{"app": "docker-registry"}, egress[2].To[0].PodSelector.MatchLabels)
+		assert.Equal(t, map[string]string{"app": "nexus"}, egress[3].To[0].PodSelector.MatchLabels)
+		assert.Equal(t, "192.168.0.7/32", egress[4].To[0].IPBlock.CIDR)
+		assert.Equal(t, int32(8443), egress[4].Ports[0].Port.IntVal)
+		assert.Equal(t, "172.16.0.2/32", egress[5].To[0].IPBlock.CIDR)
+		assert.Equal(t, int32(6443), egress[5].Ports[0].Port.IntVal)
+	}
+}
+
+func TestStepCreateBuildVaultReferences(t *testing.T) {
+	t.Parallel()
+	testDir := newStepCreateBuildTestDir(t, "test-step-create-build-vault")
+	defer os.RemoveAll(testDir)
+	appDir := filepath.Join(testDir, "myapp")
+	err := os.MkdirAll(appDir, util.DefaultWritePermissions)
+	assert.NoError(t, err)
+
+	projectYaml := `builds:
+  - kind: release
+    build:
+      steps:
+        - name: build
+          image: maven
+          args:
+          - mvn
+          - install
+        - name: deploy
+          image: maven
+          args:
+          - mvn
+          - deploy
+          env:
+          - name: DB_PASSWORD
+            value: vault:secret/data/myapp/db#password
+          - name: MAVEN_OPTS
+            value: -Xmx1g
+`
+	writeStepCreateBuildProjectConfig(t, appDir, projectYaml)
+
+	devEnv := kube.NewPermanentEnvironment("dev")
+	devEnv.Spec.Namespace = "jx"
+	devEnv.Spec.Kind = v1.EnvironmentKindTypeDevelopment
+	devEnv.Spec.TeamSettings.BuildDefaults.VaultAddress = "https://vault.jx:8200"
+
+	o := newStepCreateBuildTestOptions(appDir, nil, nil, []runtime.Object{devEnv})
+	o.OutputDir = testDir
+	o.NoAudit = true
+	err = o.Run()
+	assert.NoError(t, err)
+
+	data, err := ioutil.ReadFile(filepath.Join(testDir, actualBuildFileName))
+	assert.NoError(t, err)
+	assert.NotContains(t, string(data), "vault:")
+	build := &cmd.Build{}
+	err = yaml.Unmarshal(data, build)
+	assert.NoError(t, err)
+	if assert.Equal(t, 1, len(build.Spec.Volumes)) {
+		assert.Equal(t, "vault-secrets", build.Spec.Volumes[0].Name)
+		assert.Equal(t, corev1.StorageMediumMemory, build.Spec.Volumes[0].EmptyDir.Medium)
+	}
+	if assert.Equal(t, 3, len(build.Spec.Steps)) {
+		steps := build.Spec.Steps
+		assert.Equal(t, "vault-secrets", steps[0].Name)
+		assert.Equal(t, []string{"step", "vault", "--dir", "/vault/secrets", "--addr", "https://vault.jx:8200", "--secret", "secret-0=secret/data/myapp/db#password"}, steps[0].Args)
+		assert.Equal(t, "build", steps[1].Name)
+		assert.Equal(t, []string{"mvn", "install"}, append(steps[1].Command, steps[1].Args...))
+		deploy := steps[2]
+		assert.Equal(t, []string{"/bin/sh"}, deploy.Command)
+		assert.Equal(t, []string{"-c", `export DB_PASSWORD="$(cat /vault/secrets/secret-0)" && exec "$@"`, "sh", "mvn", "deploy"}, deploy.Args)
+		assert.Equal(t, []corev1.EnvVar{{Name: "MAVEN_OPTS", Value: "-Xmx1g"}}, deploy.Env)
+		assert.Contains(t, deploy.VolumeMounts, corev1.VolumeMount{Name: "vault-secrets", MountPath: "/vault/secrets"})
+	}
+}
+
+func TestStepCreateBuildIdentity(t *testing.T) {
+	t.Parallel()
+	testDir := newStepCreateBuildTestDir(t, "test-step-create-build-identity")
+	defer os.RemoveAll(testDir)
+	appDir := filepath.Join(testDir, "myapp")
+	err := os.MkdirAll(appDir, util.DefaultWritePermissions)
+	assert.NoError(t, err)
+
+	projectYaml := `builds:
+  - kind: release
+    build:
+      steps:
+        - name: build
+          image: maven
+          args:
+          - mvn
+          - deploy
+`
+	writeStepCreateBuildProjectConfig(t, appDir, projectYaml)
+
+	devEnv := kube.NewPermanentEnvironment("dev")
+	devEnv.Spec.Namespace = "jx"
+	devEnv.Spec.Kind = v1.EnvironmentKindTypeDevelopment
+	devEnv.Spec.TeamSettings.BuildDefaults.Identity = v1.BuildIdentity{
+		GCPServiceAccount: "jx-build@myproject.iam.gserviceaccount.com",
+		AWSRoleARN:        "arn:aws:iam::123456789012:role/jx-build",
+	}
+
+	o := newStepCreateBuildTestOptions(appDir, nil, nil, []runtime.Object{devEnv})
+	o.OutputDir = testDir
+	o.NoAudit = true
+	err = o.Run()
+	assert.NoError(t, err)
+
+	build := loadStepCreateBuild(t, filepath.Join(testDir, actualBuildFileName))
+	assert.Equal(t, "jx-build", build.Spec.ServiceAccountName)
+
+	data, err := ioutil.ReadFile(filepath.Join(testDir, "serviceaccount-release.yml"))
+	assert.NoError(t, err)
+	sa := &corev1.ServiceAccount{}
+	err = yaml.Unmarshal(data, sa)
+	assert.NoError(t, err)
+	assert.Equal(t, "jx-build", sa.Name)
+	assert.Equal(t, map[string]string{
+		"iam.gke.io/gcp-service-account": "jx-build@myproject.iam.gserviceaccount.com",
+		"eks.amazonaws.com/role-arn":     "arn:aws:iam::123456789012:role/jx-build",
+	}, sa.Annotations)
+}
+
+func TestStepCreateBuildRegistryLogin(t *testing.T) {
+	t.Parallel()
+	testDir := newStepCreateBuildTestDir(t, "test-step-create-build-registry-login")
+	defer os.RemoveAll(testDir)
+	appDir := filepath.Join(testDir, "myapp")
+	err := os.MkdirAll(appDir, util.DefaultWritePermissions)
+	assert.NoError(t, err)
+
+	projectYaml := `builds:
+  - kind: release
+    build:
+      steps:
+        - name: build
+          image: maven
+          args:
+          - mvn
+          - deploy
+        - name: container-build
+          image: maven
+          args:
+          - skaffold
+          - build
+`
+	writeStepCreateBuildProjectConfig(t, appDir, projectYaml)
+
+	registryConfig := &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      kube.ConfigMapJenkinsDockerRegistry,
+			Namespace: "jx",
+		},
+		Data: map[string]string{
+			"docker.registry": "123456789012.dkr.ecr.eu-west-1.amazonaws.com",
+		},
+	}
+	o := newStepCreateBuildTestOptions(appDir, nil, []runtime.Object{registryConfig}, nil)
+	o.OutputDir = testDir
+	o.NoAudit = true
+	err = o.Run()
+	assert.NoError(t, err)
+
+	build := loadStepCreateBuild(t, filepath.Join(testDir, actualBuildFileName))
+	if assert.Equal(t, 1, len(build.Spec.Volumes)) {
+		assert.Equal(t, "docker-config", build.Spec.Volumes[0].Name)
+	}
+	if assert.Equal(t, 4, len(build.Spec.Steps)) {
+		login := build.Spec.Steps[0]
+		assert.Equal(t, "registry-login", login.Name)
+		assert.Equal(t, "amazon/aws-cli", login.Image)
+		assert.Contains(t, login.Args[1], "aws ecr get-login-password --region eu-west-1")
+		assert.Contains(t, login.Args[1], "123456789012.dkr.ecr.eu-west-1.amazonaws.com")
+		assert.Equal(t, "build", build.Spec.Steps[1].Name)
+		ensure := build.Spec.Steps[2]
+		assert.Equal(t, "ensure-repository", ensure.Name)
+		assert.Equal(t, []string{"/bin/sh"}, ensure.Command)
+		assert.Equal(t, []string{"-c", `jx step pre build --image "123456789012.dkr.ecr.eu-west-1.amazonaws.com/${DOCKER_REGISTRY_ORG:-$ORG}/myapp"`}, ensure.Args)
+		assert.Equal(t, "container-build", build.Spec.Steps[3].Name)
+		for _, step := range build.Spec.Steps[1:] {
+			assert.Contains(t, step.Env, corev1.EnvVar{Name: "DOCKER_CONFIG", Value: "/docker-config"}, "step %s", step.Name)
+			assert.Contains(t, step.VolumeMounts, corev1.VolumeMount{Name: "docker-config", MountPath: "/docker-config"}, "step %s", step.Name)
+		}
+	}
+}
+
+func TestStepCreateBuildFlakyTests(t *testing.T) {
+	t.Parallel()
+	testDir := newStepCreateBuildTestDir(t, "test-step-create-build-flaky-tests")
+	defer os.RemoveAll(testDir)
+	appDir := filepath.Join(testDir, "myapp")
+	err := os.MkdirAll(appDir, util.DefaultWritePermissions)
+	assert.NoError(t, err)
+
+	projectYaml := `flakyTests:
+  steps:
+  - unit
+  reports:
+  - target/surefire-reports/TEST-*.xml
+  retryCommand: mvn test -Dtest=$FLAKY_TESTS
+builds:
+  - kind: release
+    build:
+      steps:
+        - name: unit
+          image: maven
+          args:
+          - mvn
+          - test
+        - name: deploy
+          image: maven
+          args:
+          - mvn
+          - deploy
+`
+	writeStepCreateBuildProjectConfig(t, appDir, projectYaml)
+
+	o := newStepCreateBuildTestOptions(appDir, nil, nil, nil)
+	o.OutputDir = testDir
+	o.NoAudit = true
+	err = o.Run()
+	assert.NoError(t, err)
+
+	build := loadStepCreateBuild(t, filepath.Join(testDir, actualBuildFileName))
+	steps := build.Spec.Steps
+	if assert.Equal(t, 3, len(steps)) {
+		assert.Equal(t, "unit", steps[0].Name)
+		assert.Equal(t, []string{"/bin/sh"}, steps[0].Command)
+		assert.Equal(t, []string{"-c", `"$@"; echo $? > /workspace/.jx-test-exit-code-unit`, "sh", "mvn", "test"}, steps[0].Args)
+
+		assert.Equal(t, "flaky-unit", steps[1].Name)
+		assert.Equal(t, "maven", steps[1].Image)
+		assert.Equal(t, []string{"jx"}, steps[1].Command)
+		assert.Equal(t, []string{"step", "flaky-tests", "--exit-code-file", "/workspace/.jx-test-exit-code-unit", "--min-flips", "3", "--reports", "target/surefire-reports/TEST-*.xml", "--retry-command", "mvn test -Dtest=$FLAKY_TESTS"}, steps[1].Args)
+
+		assert.Equal(t, "deploy", steps[2].Name)
+	}
+}
+
+func TestStepCreateBuildBuildpacks(t *testing.T) {
+	t.Parallel()
+	testDir := newStepCreateBuildTestDir(t, "test-step-create-build-buildpacks")
+	defer os.RemoveAll(testDir)
+	appDir := filepath.Join(testDir, "myapp")
+	err := os.MkdirAll(appDir, util.DefaultWritePermissions)
+	assert.NoError(t, err)
+
+	projectYaml := `builds:
+  - kind: release
+    build:
+      steps:
+        - name: test
+          image: maven
+          args:
+          - mvn
+          - test
+        - name: image
+          image: maven
+          args:
+          - skaffold
+          - build
+          - -f
+          - skaffold.yaml
+`
+	writeStepCreateBuildProjectConfig(t, appDir, projectYaml)
+
+	devEnv := kube.NewPermanentEnvironment("dev")
+	devEnv.Spec.Namespace = "jx"
+	devEnv.Spec.Kind = v1.EnvironmentKindTypeDevelopment
+	devEnv.Spec.TeamSettings.BuildDefaults.BuildpacksBuilder = "paketobuildpacks/builder:base"
+
+	generate := func() []corev1.Container {
+		o := newStepCreateBuildTestOptions(appDir, nil, nil, []runtime.Object{devEnv.DeepCopy()})
+		o.OutputDir = testDir
+		o.NoAudit = true
+		err := o.Run()
+		assert.NoError(t, err)
+
+		build := loadStepCreateBuild(t, filepath.Join(testDir, actualBuildFileName))
+		return build.Spec.Steps
+	}
+
+	steps := generate()
+	if assert.Equal(t, 2, len(steps)) {
+		assert.Equal(t, "test", steps[0].Name)
+		assert.Equal(t, "buildpacks", steps[1].Name)
+		assert.Equal(t, "paketobuildpacks/builder:base", steps[1].Image)
+		assert.Equal(t, []string{"/bin/sh"}, steps[1].Command)
+		assert.Equal(t, []string{"-c", `/cnb/lifecycle/creator -app="$PWD" "$DOCKER_REGISTRY/${DOCKER_REGISTRY_ORG:-$ORG}/myapp:${VERSION}"`}, steps[1].Args)
+	}
+
+	err = ioutil.WriteFile(filepath.Join(appDir, "Dockerfile"), []byte("FROM scratch\n"), util.DefaultWritePermissions)
+	assert.NoError(t, err)
+	steps = generate()
+	if assert.Equal(t, 2, len(steps)) {
+		assert.Equal(t, "image", steps[1].Name, "the Dockerfile should be used if there is one")
+	}
+}
+
+func TestStepCreateBuildImageBuilder(t *testing.T) {
+	t.Parallel()
+	testDir := newStepCreateBuildTestDir(t, "test-step-create-build-image-builder")
+	defer os.RemoveAll(testDir)
+	appDir := filepath.Join(testDir, "myapp")
+	err := os.MkdirAll(appDir, util.DefaultWritePermissions)
+	assert.NoError(t, err)
+
+	projectYaml := `imageBuilder: jib
+builds:
+  - kind: release
+    build:
+      steps:
+        - name: image
+          image: maven
+          command:
+          - /bin/sh
+          args:
+          - -c
+          - skaffold build -f skaffold.yaml
+`
+	writeStepCreateBuildProjectConfig(t, appDir, projectYaml)
+
+	generate := func(imageBuilder string) []corev1.Container {
+		o := newStepCreateBuildTestOptions(appDir, nil, nil, nil)
+		o.OutputDir = testDir
+		o.NoAudit = true
+		o.ImageBuilder = imageBuilder
+		err := o.Run()
+		assert.NoError(t, err)
+
+		build := loadStepCreateBuild(t, filepath.Join(testDir, actualBuildFileName))
+		return build.Spec.Steps
+	}
+
+	steps := generate("")
+	if assert.Equal(t, 1, len(steps)) {
+		assert.Equal(t, "jib", steps[0].Name)
+		assert.Equal(t, "maven", steps[0].Image)
+		assert.Equal(t, []string{"-c", `mvn compile com.google.cloud.tools:jib-maven-plugin:build -Dimage="$DOCKER_REGISTRY/${DOCKER_REGISTRY_ORG:-$ORG}/myapp:${VERSION}"`}, steps[0].Args)
+	}
+
+	err = ioutil.WriteFile(filepath.Join(appDir, "build.gradle"), []byte(""), util.DefaultWritePermissions)
+	assert.NoError(t, err)
+	err = ioutil.WriteFile(filepath.Join(appDir, "gradlew"), []byte(""), util.DefaultWritePermissions)
+	assert.NoError(t, err)
+	steps = generate("")
+	if assert.Equal(t, 1, len(steps)) {
+		assert.Equal(t, []string{"-c", `./gradlew jib --image="$DOCKER_REGISTRY/${DOCKER_REGISTRY_ORG:-$ORG}/myapp:${VERSION}"`}, steps[0].Args)
+	}
+
+	steps = generate("ko")
+	if assert.Equal(t, 1, len(steps)) {
+		assert.Equal(t, "ko", steps[0].Name)
+		assert.Equal(t, []string{"-c", `KO_DOCKER_REPO="$DOCKER_REGISTRY/${DOCKER_REGISTRY_ORG:-$ORG}/myapp" ko build --bare --tags "${VERSION}" .`}, steps[0].Args)
+	}
+
+	steps = generate("docker")
+	if assert.Equal(t, 1, len(steps)) {
+		assert.Equal(t, "image", steps[0].Name)
+	}
+}
+
+func TestStepCreateBuildBazel(t *testing.T) {
+	t.Parallel()
+	testDir := newStepCreateBuildTestDir(t, "test-step-create-build-bazel")
+	defer os.RemoveAll(testDir)
+	appDir := filepath.Join(testDir, "mymonorepo")
+	err := os.MkdirAll(appDir, util.DefaultWritePermissions)
+	assert.NoError(t, err)
+
+	projectYaml := `bazel:
+  image: gcr.io/cloud-builders/bazel
+  test:
+  - //services/...
+  release:
+  - //services/api:push
+  flags:
+  - --config=ci
+builds:
+  - kind: release
+  - kind: pullRequest
+`
+	writeStepCreateBuildProjectConfig(t, appDir, projectYaml)
+
+	devEnv := kube.NewPermanentEnvironment("dev")
+	devEnv.Spec.Namespace = "jx"
+	devEnv.Spec.Kind = v1.EnvironmentKindTypeDevelopment
+	devEnv.Spec.TeamSettings.BuildDefaults.Bazel = v1.BazelRemote{
+		RemoteCache:       "grpcs://cache.example.com",
+		GoogleCredentials: true,
+	}
+
+	generate := func(branch string, kind string) []corev1.Container {
+		outDir := filepath.Join(testDir, branch)
+		o := newStepCreateBuildTestOptions(appDir, nil, nil, []runtime.Object{devEnv.DeepCopy()})
+		o.OutputDir = outDir
+		o.Branch = branch
+		o.BranchKind = kind
+		o.NoAudit = true
+		err := o.Run()
+		assert.NoError(t, err)
+
+		build := loadStepCreateBuild(t, filepath.Join(outDir, "build-"+kind+".yml"))
+		return build.Spec.Steps
+	}
+
+	remoteFlags := []string{"--remote_cache=grpcs://cache.example.com", "--google_default_credentials", "--config=ci"}
+	steps := generate("PR-1", config.BuildKindPullRequest)
+	if assert.Equal(t, 2, len(steps)) {
+		assert.Equal(t, "bazel-build", steps[0].Name)
+		assert.Equal(t, "gcr.io/cloud-builders/bazel", steps[0].Image)
+		assert.Equal(t, append(append([]string{"bazel", "build"}, remoteFlags...), "//..."), steps[0].Args)
+		assert.Equal(t, "bazel-test", steps[1].Name)
+		assert.Equal(t, append(append([]string{"bazel", "test"}, remoteFlags...), "//services/..."), steps[1].Args)
+	}
+
+	steps = generate("master", config.BuildKindRelease)
+	if assert.Equal(t, 3, len(steps)) {
+		assert.Equal(t, "bazel-release-1", steps[2].Name)
+		assert.Equal(t, append(append([]string{"bazel", "run"}, remoteFlags...), "//services/api:push"), steps[2].Args)
+	}
+}
+
+func TestStepCreateBuildToolImages(t *testing.T) {
+	t.Parallel()
+	testDir := newStepCreateBuildTestDir(t, "test-step-create-build-tool-images")
+	defer os.RemoveAll(testDir)
+	appDir := filepath.Join(testDir, "myapp")
+	err := os.MkdirAll(appDir, util.DefaultWritePermissions)
+	assert.NoError(t, err)
+
+	projectYaml := `builds:
+  - kind: release
+    build:
+      steps:
+        - name: test
+          args:
+          - npm
+          - test
+        - name: lint
+          image: golangci/golangci-lint
+          args:
+          - golangci-lint
+          - run
+`
+	writeStepCreateBuildProjectConfig(t, appDir, projectYaml)
+	err = ioutil.WriteFile(filepath.Join(appDir, ".nvmrc"), []byte("v18.17.0\n"), util.DefaultWritePermissions)
+	assert.NoError(t, err)
+
+	devEnv := kube.NewPermanentEnvironment("dev")
+	devEnv.Spec.Namespace = "jx"
+	devEnv.Spec.Kind = v1.EnvironmentKindTypeDevelopment
+	devEnv.Spec.TeamSettings.BuildDefaults.DefaultImage = "jenkinsxio/builder-base"
+	devEnv.Spec.TeamSettings.BuildDefaults.ToolImages = map[string]string{
+		"node": "node:{{version}}",
+		"go":   "golang:{{version}}",
+	}
+
+	o := newStepCreateBuildTestOptions(appDir, nil, nil, []runtime.Object{devEnv})
+	o.OutputDir = testDir
+	o.NoAudit = true
+	err = o.Run()
+	assert.NoError(t, err)
+
+	build := loadStepCreateBuild(t, filepath.Join(testDir, actualBuildFileName))
+	steps := build.Spec.Steps
+	if assert.Equal(t, 2, len(steps)) {
+		assert.Equal(t, "node:18.17.0", steps[0].Image)
+		assert.Equal(t, "golangci/golangci-lint", steps[1].Image, "steps with an image should keep it")
+	}
+}
+
+func TestStepCreateBuildPackDockerfile(t *testing.T) {
+	t.Parallel()
+	testDir := newStepCreateBuildTestDir(t, "test-step-create-build-pack-dockerfile")
+	defer os.RemoveAll(testDir)
+	appDir := filepath.Join(testDir, "myapp")
+	err := os.MkdirAll(appDir, util.DefaultWritePermissions)
+	assert.NoError(t, err)
+	packsDir := filepath.Join(testDir, "packs")
+	err = os.MkdirAll(filepath.Join(packsDir, "maven"), util.DefaultWritePermissions)
+	assert.NoError(t, err)
+	dockerfile := "FROM openjdk:8-jdk-slim\nCOPY target/*.jar app.jar\nCMD java -jar app.jar\n"
+	err = ioutil.WriteFile(filepath.Join(packsDir, "maven", "Dockerfile"), []byte(dockerfile), util.DefaultWritePermissions)
+	assert.NoError(t, err)
+
+	projectYaml := `buildPack: maven
+builds:
+  - kind: release
+    build:
+      steps:
+        - name: build
+          args:
+          - mvn
+          - install
+        - name: image
+          args:
+          - skaffold
+          - build
+          - -f
+          - skaffold.yaml
+`
+	writeStepCreateBuildProjectConfig(t, appDir, projectYaml)
+
+	k8sObjects := []runtime.Object{
+		&corev1.ConfigMap{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      kube.ConfigMapJenkinsPodTemplates,
+				Namespace: "jx",
+			},
+			Data: map[string]string{
+				"maven": MavenBuildPackYaml,
+			},
+		},
+	}
+
+	generate := func(commitDockerfile bool) []corev1.Container {
+		o := newStepCreateBuildTestOptions(appDir, nil, k8sObjects, nil)
+		o.OutputDir = testDir
+		o.NoAudit = true
+		o.PacksDir = packsDir
+		o.CommitDockerfile = commitDockerfile
+		err := o.Run()
+		assert.NoError(t, err)
+
+		build := loadStepCreateBuild(t, filepath.Join(testDir, actualBuildFileName))
+		return build.Spec.Steps
+	}
+
+	steps := generate(false)
+	if assert.Equal(t, 3, len(steps)) {
+		assert.Equal(t, "generate-dockerfile", steps[1].Name)
+		assert.Equal(t, steps[2].Image, steps[1].Image)
+		assert.Equal(t, []string{"-c", "cat > Dockerfile <<'JX_DOCKERFILE_EOF'\n" + dockerfile + "JX_DOCKERFILE_EOF"}, steps[1].Args)
+		assert.Equal(t, "image", steps[2].Name)
+	}
+
+	steps = generate(true)
+	assert.Equal(t, 2, len(steps), "the Dockerfile should not be generated by a step once it is in the project")
+	data, err := ioutil.ReadFile(filepath.Join(appDir, "Dockerfile"))
+	assert.NoError(t, err)
+	assert.Equal(t, dockerfile, string(data))
+}
+
+func TestStepCreateBuildConvertsPackJenkinsfile(t *testing.T) {
+	t.Parallel()
+	testDir := newStepCreateBuildTestDir(t, "test-step-create-build-convert-pack")
+	defer os.RemoveAll(testDir)
+	appDir := filepath.Join(testDir, "myapp")
+	err := os.MkdirAll(appDir, util.DefaultWritePermissions)
+	assert.NoError(t, err)
+	packsDir := filepath.Join(testDir, "packs")
+	err = os.MkdirAll(filepath.Join(packsDir, "maven"), util.DefaultWritePermissions)
+	assert.NoError(t, err)
+	jenkinsfile := `pipeline {
+  environment {
+    ORG = 'myorg'
+  }
+  stages {
+    stage('Build Release') {
+      when {
+        branch 'master'
+      }
+      steps {
+        container('maven') {
+          sh "mvn install"
+        }
+      }
+    }
+  }
+}
+`
+	err = ioutil.WriteFile(filepath.Join(packsDir, "maven", "Jenkinsfile"), []byte(jenkinsfile), util.DefaultWritePermissions)
+	assert.NoError(t, err)
+	err = ioutil.WriteFile(filepath.Join(appDir, "Dockerfile"), []byte("FROM scratch\n"), util.DefaultWritePermissions)
+	assert.NoError(t, err)
+	writeStepCreateBuildProjectConfig(t, appDir, "buildPack: maven\n")
+
+	k8sObjects := []runtime.Object{
+		&corev1.ConfigMap{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      kube.ConfigMapJenkinsPodTemplates,
+				Namespace: "jx",
+			},
+			Data: map[string]string{
+				"maven": MavenBuildPackYaml,
+			},
+		},
+	}
+	o := newStepCreateBuildTestOptions(appDir, nil, k8sObjects, nil)
+	o.OutputDir = testDir
+	o.NoAudit = true
+	o.PacksDir = packsDir
+	err = o.Run()
+	assert.NoError(t, err)
+
+	build := loadStepCreateBuild(t, filepath.Join(testDir, actualBuildFileName))
+	steps := build.Spec.Steps
+	if assert.Equal(t, 1, len(steps)) {
+		assert.Equal(t, "build-release-1", steps[0].Name)
+		assert.Equal(t, []string{"-c", "mvn install"}, steps[0].Args)
+		assert.NotEmpty(t, steps[0].Image, "the step should use the image of the pod template of the pack")
+		assert.Contains(t, steps[0].Env, corev1.EnvVar{Name: "ORG", Value: "myorg"})
+	}
+}
+
+func TestStepCreateBuildIgnoresOtherInvalidPodTemplates(t *testing.T) {
+	t.Parallel()
+	testDir := newStepCreateBuildTestDir(t, "test-step-create-build-invalid-podtemplates")
+	defer os.RemoveAll(testDir)
+
+	projectYaml := `buildPack: maven
+builds:
+  - kind: release
+    build:
+      steps:
+        - name: build
+          args:
+          - mvn
+          - install
+`
+	writeStepCreateBuildProjectConfig(t, testDir, projectYaml)
+
+	k8sObjects := []runtime.Object{
+		&corev1.ConfigMap{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      kube.ConfigMapJenkinsPodTemplates,
+				Namespace: "jx",
+			},
+			Data: map[string]string{
+				"maven":  MavenBuildPackYaml,
+				"broken": "spec: [",
+			},
+		},
+	}
+	o := newStepCreateBuildTestOptions(testDir, nil, k8sObjects, nil)
+	o.OutputDir = testDir
+
+	err := o.Run()
+	assert.NoError(t, err, "an invalid pod template of another build pack should not break the build")
+
+	build := loadStepCreateBuild(t, filepath.Join(testDir, actualBuildFileName))
+	if assert.Equal(t, 1, len(build.Spec.Steps)) {
+		assert.Equal(t, "jenkinsxio/builder-maven:0.0.408", build.Spec.Steps[0].Image)
+	}
+}
+
+func TestStepCreateBuildYAMLAnchors(t *testing.T) {
+	t.Parallel()
+	testDir := newStepCreateBuildTestDir(t, "test-step-create-build-yaml-anchors")
+	defer os.RemoveAll(testDir)
+	appDir := filepath.Join(testDir, "myapp")
+	err := os.MkdirAll(appDir, util.DefaultWritePermissions)
+	assert.NoError(t, err)
+
+	projectYaml := `buildPack: maven
+builds:
+  - kind: release
+    build:
+      steps:
+        - name: build
+          args:
+          - mvn
+          - install
+        - name: script
+          command:
+          - /bin/sh
+          args:
+          - -c
+          - |
+            echo "building"
+            mvn verify
+        - name: deploy
+          args:
+          - mvn
+          - deploy
+          env:
+          - name: CHEESE
+            value: Edam
+`
+	writeStepCreateBuildProjectConfig(t, appDir, projectYaml)
+
+	k8sObjects := []runtime.Object{
+		&corev1.ConfigMap{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      kube.ConfigMapJenkinsPodTemplates,
+				Namespace: "jx",
+			},
+			Data: map[string]string{
+				"maven": MavenBuildPackYaml,
+			},
+		},
+	}
+	generate := func(anchors bool) ([]byte, *cmd.Build) {
+		outDir := filepath.Join(testDir, fmt.Sprintf("anchors-%t", anchors))
+		o := newStepCreateBuildTestOptions(appDir, nil, k8sObjects, nil)
+		o.OutputDir = outDir
+		o.NoAudit = true
+		o.YAMLAnchors = anchors
+		err := o.Run()
+		assert.NoError(t, err)
+
+		data, err := ioutil.ReadFile(filepath.Join(outDir, actualBuildFileName))
+		assert.NoError(t, err)
+		return data, loadStepCreateBuild(t, filepath.Join(outDir, actualBuildFileName))
+	}
+
+	plainData, plainBuild := generate(false)
+	anchoredData, anchoredBuild := generate(true)
+	assert.Equal(t, plainBuild, anchoredBuild, "the anchored YAML should load into the same build")
+	assert.True(t, len(anchoredData) < len(plainData), "the anchored YAML should be smaller")
+
+	text := string(anchoredData)
+	assert.Equal(t, 1, strings.Count(text, "volumeMounts: &volumeMounts-"), text)
+	assert.Equal(t, 2, strings.Count(text, "volumeMounts: *volumeMounts-"), text)
+	assert.Contains(t, text, "  - name: CHEESE\n", "the env of the deploy step differs so it should not be aliased")
+}
+
+func TestStepCreateBuildStreamsDocuments(t *testing.T) {
+	t.Parallel()
+	testDir := newStepCreateBuildTestDir(t, "test-step-create-build-stream")
+	defer os.RemoveAll(testDir)
+
+	projectYaml := `builds:
+  - kind: release
+    build:
+      steps:
+        - name: release
+          image: maven
+          args:
+          - mvn
+          - deploy
+  - kind: pullRequest
+    build:
+      steps:
+        - name: build
+          image: maven
+          args:
+          - mvn
+          - install
+`
+	writeStepCreateBuildProjectConfig(t, testDir, projectYaml)
+	out, err := os.Create(filepath.Join(testDir, "stdout.yaml"))
+	assert.NoError(t, err)
+	defer out.Close()
+
+	o := &cmd.StepCreateBuildOptions{}
+	cmd.ConfigureTestOptions(&o.CommonOptions, gits.NewGitCLI(), helm.NewHelmCLI("helm", helm.V2, testDir, true))
+	o.Out = out
+	o.Dir = testDir
+	o.NoAudit = true
+	err = o.Run()
+	assert.NoError(t, err)
+
+	data, err := ioutil.ReadFile(out.Name())
+	assert.NoError(t, err)
+	documents := strings.Split(string(data), "---\n")
+	if assert.Len(t, documents, 2, string(data)) {
+		for i, expected := range []string{"release", "build"} {
+			build := &cmd.Build{}
+			err = yaml.Unmarshal([]byte(documents[i]), build)
+			assert.NoError(t, err)
+			assert.Equal(t, "Build", build.Kind)
+			if assert.Len(t, build.Spec.Steps, 1) {
+				assert.Equal(t, expected, build.Spec.Steps[0].Name)
+			}
+		}
+	}
+}
+
+func TestStepCreateBuildConfigFromStdin(t *testing.T) {
+	t.Parallel()
+	testDir := newStepCreateBuildTestDir(t, "test-step-create-build-stdin")
+	defer os.RemoveAll(testDir)
+
+	projectYaml := `builds:
+  - kind: release
+    build:
+      steps:
+        - name: release
+          image: maven
+          args:
+          - mvn
+          - deploy
+`
+	stdin := filepath.Join(testDir, "stdin.yaml")
+	err := ioutil.WriteFile(stdin, []byte(projectYaml), util.DefaultWritePermissions)
+	assert.NoError(t, err)
+	in, err := os.Open(stdin)
+	assert.NoError(t, err)
+	defer in.Close()
+
+	o := &cmd.StepCreateBuildOptions{}
+	cmd.ConfigureTestOptions(&o.CommonOptions, gits.NewGitCLI(), helm.NewHelmCLI("helm", helm.V2, testDir, true))
+	o.In = in
+	o.ConfigFile = "-"
+	o.Dir = filepath.Join(testDir, "no-checkout")
+	o.OutputDir = testDir
+	o.NoAudit = true
+	err = o.Run()
+	assert.NoError(t, err)
+
+	build := loadStepCreateBuild(t, filepath.Join(testDir, "build-release.yml"))
+	if assert.Len(t, build.Spec.Steps, 1) {
+		assert.Equal(t, []string{"mvn", "deploy"}, build.Spec.Steps[0].Args)
+	}
+}
+
+// appendArgStepMutator appends an argument to the steps to test the registration of step mutators
+type appendArgStepMutator struct {
+}
+
+func (m *appendArgStepMutator) Name() string {
+	return "test-append-arg"
+}
+
+func (m *appendArgStepMutator) Mutate(ctx *cmd.StepMutatorContext, steps []corev1.Container) ([]corev1.Container, error) {
+	for i := range steps {
+		steps[i].Args = append(steps[i].Args, "--kind="+ctx.Kind)
+	}
+	return steps, nil
+}
+
+func TestStepCreateBuildStepMutators(t *testing.T) {
+	t.Parallel()
+	testDir := newStepCreateBuildTestDir(t, "test-step-create-build-step-mutators")
+	defer os.RemoveAll(testDir)
+	appDir := filepath.Join(testDir, "myapp")
+	err := os.MkdirAll(appDir, util.DefaultWritePermissions)
+	assert.NoError(t, err)
+
+	projectYaml := `builds:
+  - kind: release
+    build:
+      steps:
+        - name: build
+          image: golang:1.11
+          args:
+          - make
+          env:
+          - name: GOPROXY
+            value: direct
+`
+	writeStepCreateBuildProjectConfig(t, appDir, projectYaml)
+
+	cmd.RegisterStepMutator(&appendArgStepMutator{}, 50, false)
+	assert.Contains(t, cmd.StepMutatorNames(), "test-append-arg")
+
+	generate := func(mutators []string) (*cmd.Build, error) {
+		devEnv := kube.NewPermanentEnvironment("dev")
+		devEnv.Spec.Namespace = "jx"
+		devEnv.Spec.Kind = v1.EnvironmentKindTypeDevelopment
+		devEnv.Spec.TeamSettings.BuildDefaults.StepMutators = mutators
+		devEnv.Spec.TeamSettings.BuildDefaults.StepEnv = []corev1.EnvVar{
+			{Name: "GOPROXY", Value: "https://proxy.example.com"},
+			{Name: "HTTP_PROXY", Value: "http://proxy.example.com:3128"},
+		}
+
+		outDir := filepath.Join(testDir, strings.Join(mutators, "_"))
+		o := newStepCreateBuildTestOptions(appDir, nil, nil, []runtime.Object{devEnv})
+		o.OutputDir = outDir
+		o.NoAudit = true
+		err := o.Run()
+		if err != nil {
+			return nil, err
+		}
+		build := loadStepCreateBuild(t, filepath.Join(outDir, actualBuildFileName))
+		return build, nil
+	}
+
+	build, err := generate([]string{})
+	assert.NoError(t, err)
+	if assert.Len(t, build.Spec.Steps, 1) {
+		step := build.Spec.Steps[0]
+		assert.Equal(t, []string{"make"}, step.Args, "step mutators should be disabled by default")
+		assert.Nil(t, step.SecurityContext)
+		assert.Len(t, step.Env, 1)
+	}
+
+	build, err = generate([]string{cmd.StepMutatorEnvInjection, cmd.StepMutatorSecurityHardening, "test-append-arg"})
+	assert.NoError(t, err)
+	if assert.Len(t, build.Spec.Steps, 1) {
+		step := build.Spec.Steps[0]
+		assert.Equal(t, []string{"make", "--kind=release"}, step.Args)
+		if assert.Len(t, step.Env, 2) {
+			assert.Equal(t, "direct", step.Env[0].Value, "the environment of the step should not be replaced")
+			assert.Equal(t, "HTTP_PROXY", step.Env[1].Name)
+		}
+		if assert.NotNil(t, step.SecurityContext) {
+			assert.False(t, *step.SecurityContext.Privileged)
+			assert.False(t, *step.SecurityContext.AllowPrivilegeEscalation)
+		}
+	}
+
+	_, err = generate([]string{"enviroment-injection"})
+	if assert.Error(t, err) {
+		assert.Contains(t, err.Error(), "unknown step mutator enviroment-injection")
+	}
+}
+
+func TestStepCreateBuildTaskgenPlugins(t *testing.T) {
+	// not parallel as the plugins are found on the PATH of the process
+	testDir := newStepCreateBuildTestDir(t, "test-step-create-build-taskgen-plugins")
+	defer os.RemoveAll(testDir)
+	appDir := filepath.Join(testDir, "myapp")
+	pluginsDir := filepath.Join(testDir, "bin")
+	for _, dir := range []string{appDir, pluginsDir} {
+		err := os.MkdirAll(dir, util.DefaultWritePermissions)
+		assert.NoError(t, err)
+	}
+
+	projectYaml := `builds:
+  - kind: release
+    build:
+      steps:
+        - name: build
+          image: golang:1.11
+          args:
+          - make
+`
+	writeStepCreateBuildProjectConfig(t, appDir, projectYaml)
+	plugins := map[string]string{
+		"jx-taskgen-a-rename":   "#!/bin/sh\nsed \"s/\\\"name\\\":\\\"build\\\"/\\\"name\\\":\\\"$JX_TASKGEN_KIND-build\\\"/\"\n",
+		"jx-taskgen-b-validate": "#!/bin/sh\ncat > /dev/null\n",
+		"jx-taskgen-disabled":   "#!/bin/sh\nexit 1\n",
+	}
+	for name, script := range plugins {
+		mode := os.FileMode(0755)
+		if name == "jx-taskgen-disabled" {
+			mode = 0644
+		}
+		err := ioutil.WriteFile(filepath.Join(pluginsDir, name), []byte(script), mode)
+		assert.NoError(t, err)
+	}
+	path := os.Getenv("PATH")
+	defer os.Setenv("PATH", path)
+	os.Setenv("PATH", pluginsDir+string(os.PathListSeparator)+path)
+
+	generate := func(noPlugins bool) (*cmd.Build, error) {
+		o := newStepCreateBuildTestOptions(appDir, nil, nil, nil)
+		o.OutputDir = testDir
+		o.NoAudit = true
+		o.NoPlugins = noPlugins
+		err := o.Run()
+		if err != nil {
+			return nil, err
+		}
+		build := loadStepCreateBuild(t, filepath.Join(testDir, actualBuildFileName))
+		return build, nil
+	}
+
+	build, err := generate(false)
+	assert.NoError(t, err)
+	if assert.Len(t, build.Spec.Steps, 1) {
+		assert.Equal(t, "release-build", build.Spec.Steps[0].Name, "the build should be mutated by the plugin")
+		assert.Equal(t, "golang:1.11", build.Spec.Steps[0].Image)
+	}
+
+	build, err = generate(true)
+	assert.NoError(t, err)
+	if assert.Len(t, build.Spec.Steps, 1) {
+		assert.Equal(t, "build", build.Spec.Steps[0].Name)
+	}
+
+	err = ioutil.WriteFile(filepath.Join(pluginsDir, "jx-taskgen-c-reject"), []byte("#!/bin/sh\necho 'privileged steps are not allowed' >&2\nexit 1\n"), 0755)
+	assert.NoError(t, err)
+	_, err = generate(false)
+	if assert.Error(t, err) {
+		assert.Contains(t, err.Error(), "privileged steps are not allowed")
+	}
+}
+
+func TestStepCreateBuildTimeout(t *testing.T) {
+	// not parallel as the plugins are found on the PATH of the process
+	testDir := newStepCreateBuildTestDir(t, "test-step-create-build-timeout")
+	defer os.RemoveAll(testDir)
+	appDir := filepath.Join(testDir, "myapp")
+	pluginsDir := filepath.Join(testDir, "bin")
+	for _, dir := range []string{appDir, pluginsDir} {
+		err := os.MkdirAll(dir, util.DefaultWritePermissions)
+		assert.NoError(t, err)
+	}
+	projectYaml := `builds:
+  - kind: release
+    build:
+      steps:
+        - name: build
+          image: golang:1.11
+          args:
+          - make
+`
+	writeStepCreateBuildProjectConfig(t, appDir, projectYaml)
+	err := ioutil.WriteFile(filepath.Join(pluginsDir, "jx-taskgen-hang"), []byte("#!/bin/sh\nexec sleep 30\n"), 0755)
+	assert.NoError(t, err)
+	path := os.Getenv("PATH")
+	defer os.Setenv("PATH", path)
+	os.Setenv("PATH", pluginsDir+string(os.PathListSeparator)+path)
+
+	o := newStepCreateBuildTestOptions(appDir, nil, nil, nil)
+	o.OutputDir = testDir
+	o.NoAudit = true
+	o.Timeout = 500 * time.Millisecond
+	start := time.Now()
+	err = o.Run()
+	assert.Error(t, err)
+	assert.True(t, time.Since(start) < 10*time.Second, "the command should not wait for the hanging plugin")
+}
+
+func TestStepCreateBuildExtensionSteps(t *testing.T) {
+	t.Parallel()
+	testDir := newStepCreateBuildTestDir(t, "test-step-create-build-extension-steps")
+	defer os.RemoveAll(testDir)
+	appDir := filepath.Join(testDir, "myapp")
+	err := os.MkdirAll(appDir, util.DefaultWritePermissions)
+	assert.NoError(t, err)
+
+	projectYaml := `builds:
+  - kind: release
+    build:
+      steps:
+        - name: build
+          image: maven:3.6
+          args:
+          - mvn
+          - deploy
+  - kind: pullRequest
+    build:
+      steps:
+        - name: build
+          image: maven:3.6
+          args:
+          - mvn
+          - install
+        - name: sonar-scan
+          image: maven:3.6
+          args:
+          - mvn
+          - sonar:sonar
+          - -Dsonar.pullrequest.key=1
+`
+	writeStepCreateBuildProjectConfig(t, appDir, projectYaml)
+
+	sonar := &v1.Extension{
+		ObjectMeta: metav1.ObjectMeta{Name: "sonar", Namespace: "jx"},
+		Spec: v1.ExtensionSpec{
+			Name: "sonar",
+			PipelineSteps: []v1.ExtensionPipelineStep{
+				{
+					Step: corev1.Container{
+						Name: "sonar-scan",
+						Args: []string{"mvn", "sonar:sonar"},
+					},
+				},
+			},
+		},
+	}
+	licenses := &v1.Extension{
+		ObjectMeta: metav1.ObjectMeta{Name: "licenses", Namespace: "jx"},
+		Spec: v1.ExtensionSpec{
+			PipelineSteps: []v1.ExtensionPipelineStep{
+				{
+					When:  v1.ExtensionWhenPre,
+					Kinds: []string{config.BuildKindRelease},
+					Step: corev1.Container{
+						Image: "licensefinder/license_finder",
+						Args:  []string{"license_finder"},
+					},
+				},
+			},
+		},
+	}
+
+	generate := func(kind string) []corev1.Container {
+		outDir := filepath.Join(testDir, kind)
+		o := newStepCreateBuildTestOptions(appDir, nil, nil, []runtime.Object{sonar, licenses})
+		o.OutputDir = outDir
+		o.BranchKind = kind
+		o.NoAudit = true
+		err := o.Run()
+		assert.NoError(t, err)
+
+		build := loadStepCreateBuild(t, filepath.Join(outDir, "build-"+kind+".yml"))
+		return build.Spec.Steps
+	}
+
+	steps := generate(config.BuildKindRelease)
+	if assert.Len(t, steps, 3) {
+		assert.Equal(t, "licenses", steps[0].Name, "pre steps should be added before the steps of the build")
+		assert.Equal(t, "licensefinder/license_finder", steps[0].Image)
+		assert.Equal(t, "build", steps[1].Name)
+		assert.Equal(t, "sonar-scan", steps[2].Name)
+		assert.Equal(t, "maven:3.6", steps[2].Image, "the image should default to the image of the last step")
+		assert.Equal(t, []string{"mvn", "sonar:sonar"}, steps[2].Args)
+	}
+
+	steps = generate(config.BuildKindPullRequest)
+	if assert.Len(t, steps, 2) {
+		assert.Equal(t, "build", steps[0].Name)
+		assert.Equal(t, "sonar-scan", steps[1].Name)
+		assert.Contains(t, steps[1].Args, "-Dsonar.pullrequest.key=1", "the step of the project should replace the step of the extension")
+	}
+}
+
+func TestStepCreateBuildSonarQube(t *testing.T) {
+	t.Parallel()
+	testDir := newStepCreateBuildTestDir(t, "test-step-create-build-sonarqube")
+	defer os.RemoveAll(testDir)
+
+	projectYaml := `builds:
+  - kind: release
+    build:
+      steps:
+        - name: build
+          image: maven:3.6
+          args:
+          - make
+          - build
+        - name: image
+          image: maven:3.6
+          args:
+          - docker
+          - build
+          - .
+  - kind: pullRequest
+    build:
+      steps:
+        - name: build
+          image: maven:3.6
+          args:
+          - make
+          - build
+`
+	generate := func(language string, kind string, branch string) []corev1.Container {
+		appDir := filepath.Join(testDir, language+"-"+kind)
+		err := os.MkdirAll(appDir, util.DefaultWritePermissions)
+		assert.NoError(t, err)
+		writeStepCreateBuildProjectConfig(t, appDir, projectYaml)
+		err = ioutil.WriteFile(filepath.Join(appDir, language), []byte{}, util.DefaultWritePermissions)
+		assert.NoError(t, err)
+
+		devEnv := kube.NewPermanentEnvironment("dev")
+		devEnv.Spec.Namespace = "jx"
+		devEnv.Spec.Kind = v1.EnvironmentKindTypeDevelopment
+		devEnv.Spec.TeamSettings.BuildDefaults.SonarQube = v1.SonarQube{
+			ServerURL:   "https://sonar.example.com",
+			TokenSecret: "sonar-token",
+		}
+
+		o := newStepCreateBuildTestOptions(appDir, nil, nil, []runtime.Object{devEnv})
+		o.OutputDir = appDir
+		o.BranchKind = kind
+		o.Branch = branch
+		o.NoAudit = true
+		err = o.Run()
+		assert.NoError(t, err)
+
+		build := loadStepCreateBuild(t, filepath.Join(appDir, "build-"+kind+".yml"))
+		return build.Spec.Steps
+	}
+
+	steps := generate("go.mod", config.BuildKindRelease, "master")
+	if assert.Len(t, steps, 3) {
+		assert.Equal(t, "build", steps[0].Name)
+		assert.Equal(t, "sonar-analysis", steps[1].Name, "the analysis should be before the image is built")
+		assert.Equal(t, "sonarsource/sonar-scanner-cli", steps[1].Image)
+		assert.Contains(t, steps[1].Args, "-Dsonar.projectKey=go.mod-release")
+		assert.Contains(t, steps[1].Args, "-Dsonar.go.coverage.reportPaths=coverage.out")
+		token := kube.GetEnvVar(&steps[1], "SONAR_TOKEN")
+		if assert.NotNil(t, token) && assert.NotNil(t, token.ValueFrom) {
+			assert.Equal(t, "sonar-token", token.ValueFrom.SecretKeyRef.Name)
+			assert.Equal(t, "token", token.ValueFrom.SecretKeyRef.Key)
+		}
+		assert.Equal(t, "image", steps[2].Name)
+	}
+
+	steps = generate("pom.xml", config.BuildKindPullRequest, "PR-12")
+	if assert.Len(t, steps, 3) {
+		assert.Equal(t, "sonar-analysis", steps[1].Name)
+		assert.Equal(t, "maven:3.6", steps[1].Image, "Maven projects should be analysed in the image of the project")
+		assert.Equal(t, []string{"mvn"}, steps[1].Command)
+		assert.Contains(t, steps[1].Args, "-Dsonar.login=$(SONAR_TOKEN)")
+		assert.Contains(t, steps[1].Args, "-Dsonar.pullrequest.key=12")
+		assert.Equal(t, "sonar-decorate", steps[2].Name)
+		assert.Equal(t, []string{"step", "sonar", "decorate", "--report-task", "target/sonar/report-task.txt", "--pull-request", "12"}, steps[2].Args)
+		assert.NotNil(t, kube.GetEnvVar(&steps[2], "SONAR_HOST_URL"))
+	}
+}
+
+func TestStepCreateBuildDependencyScan(t *testing.T) {
+	t.Parallel()
+	testDir := newStepCreateBuildTestDir(t, "test-step-create-build-dependency-scan")
+	defer os.RemoveAll(testDir)
+
+	projectYaml := `builds:
+  - kind: release
+    build:
+      steps:
+        - name: build
+          image: maven:3.6
+          args:
+          - make
+          - build
+        - name: image
+          image: maven:3.6
+          args:
+          - docker
+          - build
+          - .
+`
+	generate := func(name string, dependencyScan string) ([]corev1.Container, error) {
+		appDir := filepath.Join(testDir, name)
+		err := os.MkdirAll(appDir, util.DefaultWritePermissions)
+		assert.NoError(t, err)
+		writeStepCreateBuildProjectConfig(t, appDir, projectYaml+dependencyScan)
+
+		devEnv := kube.NewPermanentEnvironment("dev")
+		devEnv.Spec.Namespace = "jx"
+		devEnv.Spec.Kind = v1.EnvironmentKindTypeDevelopment
+		devEnv.Spec.TeamSettings.BuildDefaults.DependencyScan = v1.DependencyScan{
+			Enabled:         true,
+			ReportBucketURL: "gs://mybucket/reports",
+		}
+
+		o := newStepCreateBuildTestOptions(appDir, nil, nil, []runtime.Object{devEnv})
+		o.OutputDir = appDir
+		o.BranchKind = config.BuildKindRelease
+		o.NoAudit = true
+		err = o.Run()
+		if err != nil {
+			return nil, err
+		}
+
+		build := loadStepCreateBuild(t, filepath.Join(appDir, "build-release.yml"))
+		return build.Spec.Steps, nil
+	}
+
+	steps, err := generate("team", "")
+	assert.NoError(t, err)
+	if assert.Len(t, steps, 4) {
+		assert.Equal(t, "dependency-scan", steps[1].Name, "the dependencies should be scanned before the image is built")
+		assert.Equal(t, "owasp/dependency-check", steps[1].Image)
+		assert.Equal(t, "dependency-scan-report", steps[2].Name)
+		assert.Equal(t, "maven:3.6", steps[2].Image)
+		assert.Equal(t, []string{"step", "dependency-scan", "--tool", "owasp", "--report", "dependency-scan/dependency-check-report.json", "--threshold", "high", "--report-bucket-url", "gs://mybucket/reports"}, steps[2].Args)
+		assert.Equal(t, "image", steps[3].Name)
+	}
+
+	steps, err = generate("snyk", `dependencyScan:
+  tool: snyk
+  threshold: critical
+  tokenSecret: snyk-token
+`)
+	assert.NoError(t, err)
+	if assert.Len(t, steps, 4) {
+		assert.Equal(t, "snyk/snyk-cli", steps[1].Image)
+		token := kube.GetEnvVar(&steps[1], "SNYK_TOKEN")
+		if assert.NotNil(t, token) && assert.NotNil(t, token.ValueFrom) {
+			assert.Equal(t, "snyk-token", token.ValueFrom.SecretKeyRef.Name)
+		}
+		assert.Equal(t, []string{"step", "dependency-scan", "--tool", "snyk", "--report", "dependency-scan/snyk.json", "--threshold", "critical", "--report-bucket-url", "gs://mybucket/reports"}, steps[2].Args)
+	}
+
+	steps, err = generate("disabled", `dependencyScan:
+  disabled: true
+`)
+	assert.NoError(t, err)
+	assert.Len(t, steps, 2, "the project should disable the dependency scan of the team")
+
+	_, err = generate("invalid", `dependencyScan:
+  threshold: severe
+`)
+	assert.Error(t, err)
+}
+
+func TestStepCreateBuildMergeSteps(t *testing.T) {
+	t.Parallel()
+	testDir := newStepCreateBuildTestDir(t, "test-step-create-build-merge-steps")
+	defer os.RemoveAll(testDir)
+
+	projectYaml := `mergeSteps:
+  enabled: true
+  exclude:
+  - test
+builds:
+  - kind: release
+    build:
+      steps:
+        - name: credentials
+          image: maven:3.6
+          args:
+          - jx
+          - step
+          - git
+          - credentials
+        - name: more-credentials
+          image: maven:3.6
+          args:
+          - jx
+          - step
+          - git
+          - credentials
+        - name: build
+          image: maven:3.6
+          command:
+          - /bin/sh
+          - -c
+          - cd app && make build
+        - name: version
+          image: maven:3.6
+          args:
+          - echo
+          - "version: 1.0"
+        - name: test
+          image: maven:3.6
+          args:
+          - make
+          - test
+        - name: lint
+          image: golang:1.11
+          args:
+          - make
+          - lint
+        - name: image
+          image: golang:1.11
+          args:
+          - make
+          - image
+          env:
+          - name: DOCKER_HOST
+            value: tcp://localhost:2375
+`
+	writeStepCreateBuildProjectConfig(t, testDir, projectYaml)
+
+	o := newStepCreateBuildTestOptions(testDir, nil, nil, nil)
+	o.OutputDir = testDir
+	o.BranchKind = config.BuildKindRelease
+	o.NoAudit = true
+	err := o.Run()
+	assert.NoError(t, err)
+
+	build := loadStepCreateBuild(t, filepath.Join(testDir, "build-release.yml"))
+	steps := build.Spec.Steps
+	if assert.Len(t, steps, 4) {
+		assert.Equal(t, "credentials", steps[0].Name)
+		assert.Equal(t, []string{"/bin/sh"}, steps[0].Command)
+		assert.Equal(t, []string{"-e", "-c", "jx step git credentials && (cd app && make build\n) && echo 'version: 1.0'"}, steps[0].Args, "the repeated command should only be run once")
+		assert.Equal(t, "test", steps[1].Name, "excluded steps should not be merged")
+		assert.Equal(t, []string{"make", "test"}, append(steps[1].Command, steps[1].Args...))
+		assert.Equal(t, "lint", steps[2].Name, "steps with different env should not be merged")
+		assert.Equal(t, "image", steps[3].Name)
+	}
+}
+
+func TestStepCreateBuildCache(t *testing.T) {
+	t.Parallel()
+	testDir := newStepCreateBuildTestDir(t, "test-step-create-build-cache")
+	defer os.RemoveAll(testDir)
+
+	projectYaml := `builds:
+  - kind: release
+    build:
+      steps:
+        - name: build
+          image: maven:3.6
+          args:
+          - make
+          - build
+`
+	writeStepCreateBuildProjectConfig(t, testDir, projectYaml)
+	cacheDir := filepath.Join(testDir, "cache")
+
+	generate := func(enabled bool, cacheTTL time.Duration) []corev1.Container {
+		devEnv := kube.NewPermanentEnvironment("dev")
+		devEnv.Spec.Namespace = "jx"
+		devEnv.Spec.Kind = v1.EnvironmentKindTypeDevelopment
+		devEnv.Spec.TeamSettings.BuildDefaults.DependencyScan.Enabled = enabled
+
+		o := newStepCreateBuildTestOptions(testDir, nil, nil, []runtime.Object{devEnv})
+		o.OutputDir = testDir
+		o.BranchKind = config.BuildKindRelease
+		o.NoAudit = true
+		o.CacheTTL = cacheTTL
+		o.CacheDir = cacheDir
+		err := o.Run()
+		assert.NoError(t, err)
+
+		build := loadStepCreateBuild(t, filepath.Join(testDir, "build-release.yml"))
+		return build.Spec.Steps
+	}
+
+	assert.Len(t, generate(true, time.Hour), 3)
+	files, err := filepath.Glob(filepath.Join(cacheDir, "teamsettings-*-jx.json"))
+	assert.NoError(t, err)
+	assert.Len(t, files, 1, "the team settings should be cached")
+
+	assert.Len(t, generate(false, time.Hour), 3, "the cached team settings should be used")
+	assert.Len(t, generate(false, 0), 1, "the cache should be disabled without a TTL")
+	assert.Len(t, generate(false, time.Nanosecond), 1, "the team settings should be loaded again when the cache expires")
+	assert.Len(t, generate(true, time.Hour), 1, "the team settings loaded again should be cached")
+}
+
+func TestStepCreateBuildPipelineBundle(t *testing.T) {
+	t.Parallel()
+	testDir := newStepCreateBuildTestDir(t, "test-step-create-build-pipeline-bundle")
+	defer os.RemoveAll(testDir)
+	appDir := filepath.Join(testDir, "myapp")
+	err := os.MkdirAll(appDir, util.DefaultWritePermissions)
+	assert.NoError(t, err)
+	packsDir := filepath.Join(testDir, "packs")
+	err = os.MkdirAll(filepath.Join(packsDir, "maven"), util.DefaultWritePermissions)
+	assert.NoError(t, err)
+	err = os.MkdirAll(filepath.Join(packsDir, ".git"), util.DefaultWritePermissions)
+	assert.NoError(t, err)
+	jenkinsfile := `pipeline {
+  stages {
+    stage('Build Release') {
+      when {
+        branch 'master'
+      }
+      steps {
+        container('maven') {
+          sh "mvn install"
+        }
+      }
+    }
+  }
+}
+`
+	err = ioutil.WriteFile(filepath.Join(packsDir, "maven", "Jenkinsfile"), []byte(jenkinsfile), util.DefaultWritePermissions)
+	assert.NoError(t, err)
+	err = ioutil.WriteFile(filepath.Join(appDir, "Dockerfile"), []byte("FROM scratch\n"), util.DefaultWritePermissions)
+	assert.NoError(t, err)
+	writeStepCreateBuildProjectConfig(t, appDir, "buildPack: maven\n")
+
+	k8sObjects := []runtime.Object{
+		&corev1.ConfigMap{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      kube.ConfigMapJenkinsPodTemplates,
+				Namespace: "jx",
+			},
+			Data: map[string]string{
+				"maven": MavenBuildPackYaml,
+			},
+		},
+	}
+	bundle := filepath.Join(testDir, "pipelinebundle.tar.gz")
+	eo := &cmd.StepExportPipelineBundleOptions{}
+	cmd.ConfigureTestOptionsWithResources(&eo.CommonOptions, k8sObjects, nil, gits.NewGitCLI(), helm.NewHelmCLI("helm", helm.V2, testDir, true))
+	eo.PacksDir = packsDir
+	eo.OutputFile = bundle
+	err = eo.Run()
+	assert.NoError(t, err)
+
+	extractedDir := filepath.Join(testDir, "extracted")
+	err = util.UnTargzDir(bundle, extractedDir)
+	assert.NoError(t, err)
+	assert.FileExists(t, filepath.Join(extractedDir, "buildpacks", "maven", "Jenkinsfile"))
+	exists, err := util.FileExists(filepath.Join(extractedDir, "buildpacks", ".git"))
+	assert.NoError(t, err)
+	assert.False(t, exists, "the git repository of the build packs should not be bundled")
+	images, err := ioutil.ReadFile(filepath.Join(extractedDir, "images.txt"))
+	assert.NoError(t, err)
+	assert.NotEmpty(t, strings.TrimSpace(string(images)), "the images of the pod templates should be listed")
+
+	// the cluster the builds are generated in has no pod templates
+	o := newStepCreateBuildTestOptions(appDir, nil, nil, nil)
+	o.OutputDir = testDir
+	o.NoAudit = true
+	o.Bundle = bundle
+	err = o.Run()
+	assert.NoError(t, err)
+
+	build := loadStepCreateBuild(t, filepath.Join(testDir, actualBuildFileName))
+	steps := build.Spec.Steps
+	if assert.Equal(t, 1, len(steps)) {
+		assert.Equal(t, []string{"-c", "mvn install"}, steps[0].Args)
+		assert.NotEmpty(t, steps[0].Image, "the step should use the image of the pod template of the bundle")
+	}
+}
+
+func TestStepCreateBuildStrictTLS(t *testing.T) {
+	t.Parallel()
+	testDir := newStepCreateBuildTestDir(t, "test-step-create-build-strict-tls")
+	defer os.RemoveAll(testDir)
+
+	generate := func(projectYaml string, buildPackURL string, strictTLS bool) error {
+		writeStepCreateBuildProjectConfig(t, testDir, projectYaml)
+		devEnv := kube.NewPermanentEnvironment("dev")
+		devEnv.Spec.Namespace = "jx"
+		devEnv.Spec.Kind = v1.EnvironmentKindTypeDevelopment
+		devEnv.Spec.TeamSettings.BuildPackURL = buildPackURL
+
+		o := newStepCreateBuildTestOptions(testDir, nil, nil, []runtime.Object{devEnv})
+		o.OutputDir = testDir
+		o.BranchKind = config.BuildKindRelease
+		o.NoAudit = true
+		o.StrictTLS = strictTLS
+		return o.Run()
+	}
+
+	insecureProjectYaml := `builds:
+  - kind: release
+    build:
+      steps:
+        - name: push
+          image: gcr.io/kaniko-project/executor:v0.9.0
+          args:
+          - --destination=registry.local:5000/myapp
+          - --skip-tls-verify
+`
+	err := generate(insecureProjectYaml, "", false)
+	assert.NoError(t, err)
+	err = generate(insecureProjectYaml, "", true)
+	if assert.Error(t, err, "a step disabling TLS verification should fail with --strict-tls") {
+		assert.Equal(t, util.ExitCodeValidation, util.ExitCode(err))
+		assert.Contains(t, err.Error(), "step push")
+		assert.Contains(t, err.Error(), "--skip-tls-verify")
+	}
+
+	err = generate("buildPack: maven\n", "http://github.com/jenkins-x/draft-packs.git", true)
+	if assert.Error(t, err, "cloning the build packs without TLS should fail with --strict-tls") {
+		assert.Equal(t, util.ExitCodeValidation, util.ExitCode(err))
+		assert.Contains(t, err.Error(), "http://github.com/jenkins-x/draft-packs.git")
+	}
+}
+
+func TestStepCreateBuildPipelineKinds(t *testing.T) {
+	t.Parallel()
+	testDir := newStepCreateBuildTestDir(t, "test-step-create-build-pipeline-kinds")
+	defer os.RemoveAll(testDir)
+
+	projectYaml := `pipelineKinds:
+  - name: nightly
+    aliases:
+    - cron
+    extends: release
+    branches: master
+  - name: perf
+    extends: pr
+builds:
+  - kind: release
+    build:
+      steps:
+        - name: build
+          image: maven:3.6
+          args:
+          - mvn
+          - deploy
+  - kind: pr
+    build:
+      steps:
+        - name: build
+          image: maven:3.6
+          args:
+          - mvn
+          - verify
+  - kind: perf
+    build:
+      steps:
+        - name: perf-test
+          image: maven:3.6
+          args:
+          - mvn
+          - gatling:test
+`
+	writeStepCreateBuildProjectConfig(t, testDir, projectYaml)
+
+	generate := func(branch string, kind string) map[string][]corev1.Container {
+		outDir, err := ioutil.TempDir(testDir, "out")
+		assert.NoError(t, err)
+		o := newStepCreateBuildTestOptions(testDir, nil, nil, nil)
+		o.OutputDir = outDir
+		o.Branch = branch
+		o.BranchKind = kind
+		o.NoAudit = true
+		err = o.Run()
+		assert.NoError(t, err)
+
+		answer := map[string][]corev1.Container{}
+		files, err := filepath.Glob(filepath.Join(outDir, "build-*.yml"))
+		assert.NoError(t, err)
+		for _, f := range files {
+			build := loadStepCreateBuild(t, f)
+			kind := strings.TrimSuffix(strings.TrimPrefix(filepath.Base(f), "build-"), ".yml")
+			answer[kind] = build.Spec.Steps
+		}
+		return answer
+	}
+
+	builds := generate("master", "")
+	assert.Len(t, builds, 4)
+	if assert.Len(t, builds["nightly"], 1, "the nightly kind should use the build of the release kind it extends") {
+		assert.Equal(t, []string{"mvn", "deploy"}, builds["nightly"][0].Args)
+	}
+	if assert.Len(t, builds["pullRequest"], 1, "the pr alias should resolve to the pullRequest kind") {
+		assert.Equal(t, []string{"mvn", "verify"}, builds["pullRequest"][0].Args)
+	}
+	if assert.Len(t, builds["perf"], 1) {
+		assert.Equal(t, "perf-test", builds["perf"][0].Name)
+	}
+
+	builds = generate("PR-12", "")
+	assert.Nil(t, builds["nightly"], "the nightly kind should only be generated for master")
+	assert.NotNil(t, builds["perf"])
+
+	builds = generate("master", "cron")
+	assert.Len(t, builds, 1)
+	assert.NotNil(t, builds["nightly"], "the kind should be selected by its alias")
+}
+
+func TestStepCreateBuildFeatureBranch(t *testing.T) {
+	t.Parallel()
+	testDir := newStepCreateBuildTestDir(t, "test-step-create-build-feature-branch")
+	defer os.RemoveAll(testDir)
+
+	projectYaml := `workflowPreset: gitflow
+feature:
+  steps:
+    - name: deploy-feature
+      image: jenkinsxio/jx:1.3.1
+      args:
+      - jx
+      - preview
+builds:
+  - kind: release
+    build:
+      steps:
+        - name: build
+          image: maven:3.6
+          args:
+          - mvn
+          - deploy
+`
+	writeStepCreateBuildProjectConfig(t, testDir, projectYaml)
+
+	outDir := filepath.Join(testDir, "out")
+	o := newStepCreateBuildTestOptions(testDir, nil, nil, nil)
+	o.OutputDir = outDir
+	o.Branch = "feature/JIRA-123_Add Login"
+	o.NoAudit = true
+	err := o.Run()
+	assert.NoError(t, err)
+
+	build := loadStepCreateBuild(t, filepath.Join(outDir, "build-feature.yml"))
+	if assert.Len(t, build.Spec.Steps, 2) {
+		assert.Equal(t, "build", build.Spec.Steps[0].Name)
+		assert.Equal(t, "deploy-feature", build.Spec.Steps[1].Name)
+		for _, step := range build.Spec.Steps {
+			env := map[string]string{}
+			for _, e := range step.Env {
+				env[e.Name] = e.Value
+			}
+			assert.Equal(t, "jira-123-add-login", env[config.EnvFeatureName], "step %s", step.Name)
+			assert.Equal(t, "feature/JIRA-123_Add Login", env[config.EnvFeatureBranch], "step %s", step.Name)
+		}
+	}
+}
+
+func TestStepCreateBuildContainerOverride(t *testing.T) {
+	t.Parallel()
+	testDir := newStepCreateBuildTestDir(t, "test-step-create-build-container-override")
+	defer os.RemoveAll(testDir)
+
+	projectYaml := `buildPack: maven
+builds:
+  - kind: release
+    build:
+      steps:
+        - name: build
+          args:
+          - mvn
+          - install
+        - name: test-1
+          args:
+          - mvn
+          - test
+`
+	writeStepCreateBuildProjectConfig(t, testDir, projectYaml)
+	java11PodTemplate := `apiVersion: v1
+kind: Pod
+metadata:
+  name: jenkins-maven-java11
+spec:
+  containers:
+  - name: maven
+    image: jenkinsxio/builder-maven-java11:0.1.1
+    env:
+    - name: JAVA_VERSION
+      value: "11"
+`
+	k8sObjects := []runtime.Object{
+		&corev1.ConfigMap{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      kube.ConfigMapJenkinsPodTemplates,
+				Namespace: "jx",
+			},
+			Data: map[string]string{
+				"maven":        MavenBuildPackYaml,
+				"maven-java11": java11PodTemplate,
+			},
+		},
+	}
+
+	generate := func(containers ...string) ([]corev1.Container, error) {
+		outDir, err := ioutil.TempDir(testDir, "out")
+		assert.NoError(t, err)
+		o := newStepCreateBuildTestOptions(testDir, nil, k8sObjects, nil)
+		o.OutputDir = outDir
+		o.BranchKind = config.BuildKindRelease
+		o.NoAudit = true
+		o.NoGenerateDockerfile = true
+		o.Containers = containers
+		err = o.Run()
+		if err != nil {
+			return nil, err
+		}
+		build := loadStepCreateBuild(t, filepath.Join(outDir, actualBuildFileName))
+		return build.Spec.Steps, nil
+	}
+
+	steps, err := generate()
+	assert.NoError(t, err)
+	if assert.Len(t, steps, 2) {
+		assert.Equal(t, steps[0].Image, steps[1].Image)
+	}
+
+	steps, err = generate("test=maven-java11")
+	assert.NoError(t, err)
+	if assert.Len(t, steps, 2) {
+		assert.NotEqual(t, "jenkinsxio/builder-maven-java11:0.1.1", steps[0].Image, "the build lifecycle should keep the container of the build pack")
+		assert.Equal(t, "jenkinsxio/builder-maven-java11:0.1.1", steps[1].Image)
+		assert.NotNil(t, kube.GetEnvVar(&steps[1], "JAVA_VERSION"), "the step should have the environment variables of the overriding pod template")
+	}
+
+	_, err = generate("test")
+	if assert.Error(t, err) {
+		assert.Equal(t, util.ExitCodeMissingOption, util.ExitCode(err))
+	}
+	_, err = generate("test=does-not-exist")
+	assert.Error(t, err, "a missing pod template should fail")
+}
+
+func TestStepCreateBuildStepFilters(t *testing.T) {
+	t.Parallel()
+	testDir := newStepCreateBuildTestDir(t, "test-step-create-build-step-filters")
+	defer os.RemoveAll(testDir)
+
+	projectYaml := `builds:
+  - kind: release
+    build:
+      steps:
+        - name: build
+          image: golang:1.11
+        - name: test-1
+          image: golang:1.11
+        - name: test-2
+          image: golang:1.11
+        - name: deploy
+          image: golang:1.11
+    approvals:
+      - name: production
+        before: deploy
+`
+	writeStepCreateBuildProjectConfig(t, testDir, projectYaml)
+
+	generate := func(configure func(o *cmd.StepCreateBuildOptions)) ([]string, error) {
+		outDir, err := ioutil.TempDir(testDir, "out")
+		assert.NoError(t, err)
+		o := newStepCreateBuildTestOptions(testDir, nil, nil, nil)
+		o.OutputDir = outDir
+		o.NoAudit = true
+		configure(o)
+		err = o.Run()
+		if err != nil {
+			return nil, err
+		}
+		build := loadStepCreateBuild(t, filepath.Join(outDir, actualBuildFileName))
+		names := []string{}
+		for _, step := range build.Spec.Steps {
+			names = append(names, step.Name)
+		}
+		return names, nil
+	}
+
+	names, err := generate(func(o *cmd.StepCreateBuildOptions) {
+		o.StartStep = "test-1"
+		o.EndStep = "test-2"
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"test-1", "test-2"}, names)
+
+	names, err = generate(func(o *cmd.StepCreateBuildOptions) {
+		o.OnlyLifecycles = []string{"test"}
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"test-1", "test-2"}, names)
+
+	names, err = generate(func(o *cmd.StepCreateBuildOptions) {
+		o.StartStep = "deploy"
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"approval-production", "deploy"}, names, "the approval of a kept step should be kept")
+
+	_, err = generate(func(o *cmd.StepCreateBuildOptions) {
+		o.StartStep = "test-2"
+		o.EndStep = "build"
+	})
+	assert.Error(t, err, "the end step should not be before the start step")
+
+	_, err = generate(func(o *cmd.StepCreateBuildOptions) {
+		o.OnlyLifecycles = []string{"lint"}
+	})
+	assert.Error(t, err, "a lifecycle without steps should fail")
+
+	_, err = generate(func(o *cmd.StepCreateBuildOptions) {
+		o.StartStep = "test-1"
+		o.FromStep = "test-1"
+	})
+	assert.Error(t, err)
+}
+
+func TestStepCreateBuildWizard(t *testing.T) {
+	t.Parallel()
+	testDir := newStepCreateBuildTestDir(t, "test-step-create-build-wizard")
+	defer os.RemoveAll(testDir)
+	appDir := filepath.Join(testDir, "myapp")
+	err := os.MkdirAll(appDir, util.DefaultWritePermissions)
+	assert.NoError(t, err)
+	packsDir := filepath.Join(testDir, "packs")
+	err = os.MkdirAll(filepath.Join(packsDir, "maven"), util.DefaultWritePermissions)
+	assert.NoError(t, err)
+	jenkinsfile := `pipeline {
+  stages {
+    stage('Build Release') {
+      when {
+        branch 'master'
+      }
+      steps {
+        sh "mvn install"
+      }
+    }
+  }
+}
+`
+	err = ioutil.WriteFile(filepath.Join(packsDir, "maven", "Jenkinsfile"), []byte(jenkinsfile), util.DefaultWritePermissions)
+	assert.NoError(t, err)
+	err = ioutil.WriteFile(filepath.Join(appDir, "Dockerfile"), []byte("FROM scratch\n"), util.DefaultWritePermissions)
+	assert.NoError(t, err)
+	// the jenkins-x.yml file of the project has no build pack or builds
+	writeStepCreateBuildProjectConfig(t, appDir, "mergeSteps:\n  enabled: true\n")
+
+	k8sObjects := []runtime.Object{
+		&corev1.ConfigMap{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      kube.ConfigMapJenkinsPodTemplates,
+				Namespace: "jx",
+			},
+			Data: map[string]string{
+				"go":    MavenBuildPackYaml,
+				"maven": MavenBuildPackYaml,
+			},
+		},
+	}
+
+	c, _, term := tests.NewTerminal(t)
+	defer c.Close()
+	donec := make(chan struct{})
+	go func() {
+		defer close(donec)
+		c.ExpectString("What do you want to do with the builds?")
+		c.SendLine("")
+		c.ExpectString("Output directory:")
+		c.SendLine("")
+		c.ExpectString("Pick the build pack of the project:")
+		c.SendLine("maven")
+		c.ExpectString("Pick the kind of build to create:")
+		c.SendLine("release")
+		c.ExpectEOF()
+	}()
+
+	o := newStepCreateBuildTestOptions(appDir, nil, k8sObjects, nil)
+	o.BatchMode = false
+	o.In = term.In
+	o.Out = term.Out
+	o.Err = term.Err
+	o.PacksDir = packsDir
+	o.Branch = "master"
+	o.NoAudit = true
+	err = o.Run()
+	c.Tty().Close()
+	<-donec
+	assert.NoError(t, err)
+
+	data, err := ioutil.ReadFile(filepath.Join(appDir, "out", actualBuildFileName))
+	assert.NoError(t, err, "the builds should be written to the default output directory of the project")
+	assert.Contains(t, string(data), "mvn install", "the builds should be converted from the picked build pack")
+	data, err = ioutil.ReadFile(filepath.Join(appDir, config.ProjectConfigFileName))
+	assert.NoError(t, err)
+	assert.Equal(t, "mergeSteps:\n  enabled: true\n", string(data), "an existing jenkins-x.yml file should not be rewritten")
+}
+
+func TestStepCreateBuildBatchModeNeverPrompts(t *testing.T) {
+	t.Parallel()
+	testDir := newStepCreateBuildTestDir(t, "test-step-create-build-batch-mode")
+	defer os.RemoveAll(testDir)
+	packsDir := filepath.Join(testDir, "packs")
+	err := os.MkdirAll(filepath.Join(packsDir, "maven"), util.DefaultWritePermissions)
+	assert.NoError(t, err)
+	jenkinsfile := `pipeline {
+  stages {
+    stage('Build Release') {
+      when {
+        branch 'master'
+      }
+      steps {
+        sh "mvn install"
+      }
+    }
+  }
+}
+`
+	err = ioutil.WriteFile(filepath.Join(packsDir, "maven", "Jenkinsfile"), []byte(jenkinsfile), util.DefaultWritePermissions)
+	assert.NoError(t, err)
+	k8sObjects := []runtime.Object{
+		&corev1.ConfigMap{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      kube.ConfigMapJenkinsPodTemplates,
+				Namespace: "jx",
+			},
+			Data: map[string]string{
+				"maven": MavenBuildPackYaml,
+			},
+		},
+	}
+
+	generate := func(projectYaml string) error {
+		appDir, err := ioutil.TempDir(testDir, "app")
+		assert.NoError(t, err)
+		err = ioutil.WriteFile(filepath.Join(appDir, "Dockerfile"), []byte("FROM scratch\n"), util.DefaultWritePermissions)
+		assert.NoError(t, err)
+		if projectYaml != "" {
+			writeStepCreateBuildProjectConfig(t, appDir, projectYaml)
+		}
+		c, term := tests.NewBatchModeTerminal(t)
+		defer c.Close()
+
+		o := newStepCreateBuildTestOptions(appDir, nil, k8sObjects, nil)
+		assert.True(t, o.BatchMode)
+		o.In = term.In
+		o.Out = term.Out
+		o.Err = term.Err
+		o.PacksDir = packsDir
+		o.Branch = "master"
+		o.NoAudit = true
+		return o.Run()
+	}
+
+	err = generate("buildPack: maven\n")
+	assert.NoError(t, err, "the missing output and kind should default to all the builds on the standard output")
+
+	err = generate("")
+	if assert.Error(t, err, "a project without builds or a build pack should fail rather than prompt for the build pack") {
+		assert.Equal(t, util.ExitCodeValidation, util.ExitCode(err))
+	}
+}
+
+func TestStepCreateBuildOwnerReferences(t *testing.T) {
+	t.Parallel()
+	testDir := newStepCreateBuildTestDir(t, "test-step-create-build-owner-references")
+	defer os.RemoveAll(testDir)
+
+	projectYaml := `builds:
+  - kind: release
+    build:
+      steps:
+        - name: build
+          image: golang:1.11
+`
+	writeStepCreateBuildProjectConfig(t, testDir, projectYaml)
+	gitter := gits.NewGitCLI()
+	err := gitter.Init(testDir)
+	assert.NoError(t, err)
+	err = gitter.AddRemote(testDir, "origin", "https://github.com/myorg/myrepo.git")
+	assert.NoError(t, err)
+
+	generate := func(namespace string, jxObjects ...runtime.Object) *cmd.Build {
+		outDir, err := ioutil.TempDir(testDir, "out")
+		assert.NoError(t, err)
+		o := newStepCreateBuildTestOptions(testDir, gitter, nil, jxObjects)
+		o.OutputDir = outDir
+		o.Namespace = namespace
+		o.NoAudit = true
+		err = o.Run()
+		assert.NoError(t, err)
+		build := loadStepCreateBuild(t, filepath.Join(outDir, actualBuildFileName))
+		return build
+	}
+
+	repo := &v1.SourceRepository{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "myorg-myrepo",
+			Namespace: "jx",
+			UID:       "repo-uid",
+		},
+	}
+	build := generate("", repo)
+	assert.Equal(t, "jx", build.Namespace)
+	assert.Equal(t, []metav1.OwnerReference{{
+		APIVersion: "jenkins.io/v1",
+		Kind:       "SourceRepository",
+		Name:       "myorg-myrepo",
+		UID:        "repo-uid",
+	}}, build.OwnerReferences)
+
+	env := kube.NewPermanentEnvironmentWithGit("staging", "https://github.com/myorg/myrepo.git")
+	env.Namespace = "jx"
+	env.UID = "env-uid"
+	build = generate("", repo, env)
+	if assert.Len(t, build.OwnerReferences, 1) {
+		assert.Equal(t, "Environment", build.OwnerReferences[0].Kind)
+		assert.Equal(t, "staging", build.OwnerReferences[0].Name)
+	}
+
+	build = generate("jx-staging", repo)
+	assert.Equal(t, "jx-staging", build.Namespace)
+	assert.Empty(t, build.OwnerReferences, "owners cannot be in another namespace")
+
+	build = generate("")
+	assert.Empty(t, build.Namespace)
+	assert.Empty(t, build.OwnerReferences)
+}
+
+func TestStepCreateBuildPriorityClassAndQuota(t *testing.T) {
+	t.Parallel()
+	testDir := newStepCreateBuildTestDir(t, "test-step-create-build-priority-class")
+	defer os.RemoveAll(testDir)
+
+	projectYaml := `builds:
+  - kind: release
+    build:
+      steps:
+        - name: build
+          image: golang:1.11
+        - name: test
+          image: golang:1.11
+`
+	writeStepCreateBuildProjectConfig(t, testDir, projectYaml)
+
+	devEnv := kube.NewPermanentEnvironment("dev")
+	devEnv.Spec.Namespace = "jx"
+	devEnv.Spec.Kind = v1.EnvironmentKindTypeDevelopment
+	devEnv.Spec.TeamSettings.BuildDefaults = v1.BuildDefaults{
+		PriorityClassName: "ci-low",
+		QuotaResources: &corev1.ResourceRequirements{
+			Requests: corev1.ResourceList{
+				corev1.ResourceCPU:    resource.MustParse("200m"),
+				corev1.ResourceMemory: resource.MustParse("256Mi"),
+			},
+			Limits: corev1.ResourceList{
+				corev1.ResourceMemory: resource.MustParse("512Mi"),
+			},
+		},
+	}
+	k8sObjects := []runtime.Object{
+		&corev1.ResourceQuota{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "ci",
+				Namespace: "jx",
+			},
+			Spec: corev1.ResourceQuotaSpec{
+				Hard: corev1.ResourceList{
+					corev1.ResourceRequestsCPU:    resource.MustParse("8"),
+					corev1.ResourceRequestsMemory: resource.MustParse("16Gi"),
+					corev1.ResourceLimitsMemory:   resource.MustParse("32Gi"),
+				},
+			},
+		},
+		&corev1.LimitRange{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "ci",
+				Namespace: "jx",
+			},
+			Spec: corev1.LimitRangeSpec{
+				Limits: []corev1.LimitRangeItem{
+					{
+						Type: corev1.LimitTypeContainer,
+						DefaultRequest: corev1.ResourceList{
+							corev1.ResourceMemory: resource.MustParse("128Mi"),
+						},
+					},
+				},
+			},
+		},
+	}
+
+	o := newStepCreateBuildTestOptions(testDir, nil, k8sObjects, []runtime.Object{devEnv})
+	o.OutputDir = testDir
+	o.NoAudit = true
+
+	err := o.Run()
+	assert.NoError(t, err)
+
+	build := loadStepCreateBuild(t, filepath.Join(testDir, actualBuildFileName))
+
+	assert.Equal(t, "ci-low", build.Spec.PriorityClassName)
+	if assert.Len(t, build.Spec.Steps, 2) {
+		resources := build.Spec.Steps[0].Resources
+		assert.Equal(t, "200m", resources.Requests.Cpu().String())
+		assert.Equal(t, "512Mi", resources.Limits.Memory().String())
+		_, ok := resources.Requests[corev1.ResourceMemory]
+		assert.False(t, ok, "the LimitRange defaults the memory requests")
+		_, ok = resources.Limits[corev1.ResourceCPU]
+		assert.False(t, ok, "the ResourceQuota does not limit the cpu limits")
+		assert.Equal(t, resources, build.Spec.Steps[1].Resources)
+	}
+}
+
+func TestStepCreateBuildSpot(t *testing.T) {
+	t.Parallel()
+	testDir := newStepCreateBuildTestDir(t, "test-step-create-build-spot")
+	defer os.RemoveAll(testDir)
+
+	projectYaml := `builds:
+  - kind: release
+    build:
+      steps:
+        - name: build
+          image: golang:1.11
+`
+	writeStepCreateBuildProjectConfig(t, testDir, projectYaml)
+
+	devEnv := kube.NewPermanentEnvironment("dev")
+	devEnv.Spec.Namespace = "jx"
+	devEnv.Spec.Kind = v1.EnvironmentKindTypeDevelopment
+	devEnv.Spec.TeamSettings.BuildDefaults.Spot = v1.BuildSpot{
+		Enabled: true,
+		NodeSelector: map[string]string{
+			"cloud.google.com/gke-preemptible": "true",
+		},
+		Tolerations: []corev1.Toleration{
+			{
+				Key:      "cloud.google.com/gke-preemptible",
+				Operator: corev1.TolerationOpEqual,
+				Value:    "true",
+				Effect:   corev1.TaintEffectNoSchedule,
+			},
+		},
+	}
+
+	generate := func(noSpot bool) *cmd.Build {
+		outDir, err := ioutil.TempDir(testDir, "out")
+		assert.NoError(t, err)
+		o := newStepCreateBuildTestOptions(testDir, nil, nil, []runtime.Object{devEnv})
+		o.OutputDir = outDir
+		o.NoAudit = true
+		o.NoSpot = noSpot
+		err = o.Run()
+		assert.NoError(t, err)
+		build := loadStepCreateBuild(t, filepath.Join(outDir, actualBuildFileName))
+		return build
+	}
+
+	build := generate(false)
+	assert.Equal(t, map[string]string{"cloud.google.com/gke-preemptible": "true"}, build.Spec.NodeSelector)
+	assert.Equal(t, devEnv.Spec.TeamSettings.BuildDefaults.Spot.Tolerations, build.Spec.Tolerations)
+	assert.Equal(t, "2", build.Annotations[kube.AnnotationMaxPreemptionRetries])
+
+	build = generate(true)
+	assert.Empty(t, build.Spec.NodeSelector)
+	assert.Empty(t, build.Spec.Tolerations)
+	assert.Empty(t, build.Annotations[kube.AnnotationMaxPreemptionRetries])
+}
+
+func TestStepCreateBuildAutoscaling(t *testing.T) {
+	t.Parallel()
+	testDir := newStepCreateBuildTestDir(t, "test-step-create-build-autoscaling")
+	defer os.RemoveAll(testDir)
+
+	projectYaml := `builds:
+  - kind: release
+    build:
+      steps:
+        - name: build
+          image: golang:1.11
+`
+	writeStepCreateBuildProjectConfig(t, testDir, projectYaml)
+
+	safeToEvict := false
+	devEnv := kube.NewPermanentEnvironment("dev")
+	devEnv.Spec.Namespace = "jx"
+	devEnv.Spec.Kind = v1.EnvironmentKindTypeDevelopment
+	devEnv.Spec.TeamSettings.BuildDefaults.Autoscaling = v1.BuildAutoscaling{
+		SafeToEvict: &safeToEvict,
+		PreferredNodeLabels: map[string]string{
+			"pool":                          "builds",
+			"cloud.google.com/gke-nodepool": "build-pool",
+		},
+		SpreadTopologyKey: "topology.kubernetes.io/zone",
+		PackBuilds:        true,
+	}
+
+	generate := func(env *v1.Environment) *cmd.Build {
+		outDir, err := ioutil.TempDir(testDir, "out")
+		assert.NoError(t, err)
+		o := newStepCreateBuildTestOptions(testDir, nil, nil, []runtime.Object{env})
+		o.OutputDir = outDir
+		o.NoAudit = true
+		err = o.Run()
+		assert.NoError(t, err)
+		return loadStepCreateBuild(t, filepath.Join(outDir, actualBuildFileName))
+	}
+
+	buildPodTerm := func(topologyKey string) corev1.WeightedPodAffinityTerm {
+		return corev1.WeightedPodAffinityTerm{
+			Weight: 100,
+			PodAffinityTerm: corev1.PodAffinityTerm{
+				LabelSelector: &metav1.LabelSelector{
+					MatchExpressions: []metav1.LabelSelectorRequirement{
+						{
+							Key:      "build.knative.dev/buildName",
+							Operator: metav1.LabelSelectorOpExists,
+						},
+					},
+				},
+				TopologyKey: topologyKey,
+			},
+		}
+	}
+
+	build := generate(devEnv)
+	assert.Equal(t, "false", build.Annotations["cluster-autoscaler.kubernetes.io/safe-to-evict"])
+	expected := &corev1.Affinity{
+		NodeAffinity: &corev1.NodeAffinity{
+			PreferredDuringSchedulingIgnoredDuringExecution: []corev1.PreferredSchedulingTerm{
+				{
+					Weight: 100,
+					Preference: corev1.NodeSelectorTerm{
+						MatchExpressions: []corev1.NodeSelectorRequirement{
+							{
+								Key:      "cloud.google.com/gke-nodepool",
+								Operator: corev1.NodeSelectorOpIn,
+								Values:   []string{"build-pool"},
+							},
+							{
+								Key:      "pool",
+								Operator: corev1.NodeSelectorOpIn,
+								Values:   []string{"builds"},
+							},
+						},
+					},
+				},
+			},
+		},
+		PodAffinity: &corev1.PodAffinity{
+			PreferredDuringSchedulingIgnoredDuringExecution: []corev1.WeightedPodAffinityTerm{
+				buildPodTerm("kubernetes.io/hostname"),
+			},
+		},
+		PodAntiAffinity: &corev1.PodAntiAffinity{
+			PreferredDuringSchedulingIgnoredDuringExecution: []corev1.WeightedPodAffinityTerm{
+				buildPodTerm("topology.kubernetes.io/zone"),
+			},
+		},
+	}
+	assert.Equal(t, expected, build.Spec.Affinity)
+
+	devEnv = devEnv.DeepCopy()
+	devEnv.Spec.TeamSettings.BuildDefaults.Autoscaling = v1.BuildAutoscaling{}
+	build = generate(devEnv)
+	assert.Nil(t, build.Spec.Affinity)
+	_, ok := build.Annotations["cluster-autoscaler.kubernetes.io/safe-to-evict"]
+	assert.False(t, ok, "the safe-to-evict annotation should only be added when the team sets it")
+}
+
+func TestStepCreateBuildEstimate(t *testing.T) {
+	t.Parallel()
+	testDir := newStepCreateBuildTestDir(t, "test-step-create-build-estimate")
+	defer os.RemoveAll(testDir)
+
+	projectYaml := `builds:
+  - kind: release
+    build:
+      steps:
+        - name: build
+          image: golang:1.11
+`
+	writeStepCreateBuildProjectConfig(t, testDir, projectYaml)
+	gitter := gits.NewGitCLI()
+	err := gitter.Init(testDir)
+	assert.NoError(t, err)
+	err = gitter.AddRemote(testDir, "origin", "https://github.com/myorg/myrepo.git")
+	assert.NoError(t, err)
+
+	devEnv := kube.NewPermanentEnvironment("dev")
+	devEnv.Spec.Namespace = "jx"
+	devEnv.Spec.Kind = v1.EnvironmentKindTypeDevelopment
+	devEnv.Spec.TeamSettings.BuildDefaults.Resources = &corev1.ResourceRequirements{
+		Requests: corev1.ResourceList{
+			corev1.ResourceCPU:    resource.MustParse("1"),
+			corev1.ResourceMemory: resource.MustParse("2Gi"),
+		},
+	}
+	started := metav1.NewTime(time.Now().Add(-time.Hour))
+	activity := func(build string, pipeline string, status v1.ActivityStatusType, duration time.Duration) runtime.Object {
+		completed := metav1.NewTime(started.Add(duration))
+		return &v1.PipelineActivity{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      kube.ToValidName(pipeline + "-" + build),
+				Namespace: "jx",
+			},
+			Spec: v1.PipelineActivitySpec{
+				Pipeline:           pipeline,
+				Build:              build,
+				Status:             status,
+				StartedTimestamp:   &started,
+				CompletedTimestamp: &completed,
+			},
+		}
+	}
+
+	estimate := func(jxObjects ...runtime.Object) string {
+		out, err := ioutil.TempFile(testDir, "stdout")
+		assert.NoError(t, err)
+		defer out.Close()
+		o := newStepCreateBuildTestOptions(testDir, gitter, nil, append(jxObjects, devEnv))
+		o.Out = out
+		o.Branch = "master"
+		o.NoAudit = true
+		o.Estimate = true
+		o.CPUCost = 0.03
+		o.MemoryCost = 0.005
+		err = o.Run()
+		assert.NoError(t, err)
+		data, err := ioutil.ReadFile(out.Name())
+		assert.NoError(t, err)
+		return string(data)
+	}
+
+	output := estimate(
+		activity("1", "myorg/myrepo/master", v1.ActivityStatusTypeSucceeded, 10*time.Minute),
+		activity("2", "myorg/myrepo/master", v1.ActivityStatusTypeSucceeded, 30*time.Minute),
+		activity("3", "myorg/myrepo/master", v1.ActivityStatusTypeSucceeded, 20*time.Minute),
+		activity("4", "myorg/myrepo/master", v1.ActivityStatusTypeFailed, time.Minute),
+		activity("1", "myorg/other/master", v1.ActivityStatusTypeSucceeded, time.Hour),
+	)
+	assert.Contains(t, output, "KIND")
+	assert.Contains(t, output, "20m0s")
+	assert.Contains(t, output, "3 runs")
+	// a third of an hour of 1 core at $0.03 and 2 GiB at $0.005
+	assert.Contains(t, output, "$0.0133")
+	assert.NotContains(t, output, "kind: Build", "the builds should not be written")
+
+	output = estimate()
+	assert.Contains(t, output, "1m0s")
+	assert.Contains(t, output, "heuristics")
+}
+
+func TestStepCreateBuildLinks(t *testing.T) {
+	t.Parallel()
+	testDir := newStepCreateBuildTestDir(t, "test-step-create-build-links")
+	defer os.RemoveAll(testDir)
+
+	projectYaml := `issueTracker:
+  kind: jira
+  url: https://myorg.atlassian.net
+builds:
+  - kind: pullRequest
+    build:
+      steps:
+        - name: build
+          image: golang:1.11
+`
+	writeStepCreateBuildProjectConfig(t, testDir, projectYaml)
+	gitter := gits.NewGitCLI()
+	err := gitter.Init(testDir)
+	assert.NoError(t, err)
+	for _, args := range [][]string{{"config", "user.name", "test"}, {"config", "user.email", "test@example.com"}} {
+		_, err = (&util.Command{Dir: testDir, Name: "git", Args: args}).RunWithoutRetry()
+		assert.NoError(t, err)
+	}
+	err = gitter.AddRemote(testDir, "origin", "https://github.com/myorg/myrepo.git")
+	assert.NoError(t, err)
+	err = gitter.Add(testDir, "*")
+	assert.NoError(t, err)
+	err = gitter.CommitDir(testDir, "fix: handle missing values JX-12\n\nsee JX-34 and JX-12")
+	assert.NoError(t, err)
+	sha, err := gitter.GetLatestCommitSha(testDir)
+	assert.NoError(t, err)
+
+	o := newStepCreateBuildTestOptions(testDir, gitter, nil, nil)
+	o.OutputDir = filepath.Join(testDir, "out")
+	o.Branch = "PR-7"
+	o.NoAudit = true
+	err = o.Run()
+	assert.NoError(t, err)
+	build := loadStepCreateBuild(t, filepath.Join(o.OutputDir, "build-pullRequest.yml"))
+
+	assert.Equal(t, sha, build.Annotations[kube.AnnotationCommitSHA])
+	assert.Equal(t, "https://github.com/myorg/myrepo/commit/"+sha, build.Annotations[kube.AnnotationCommitURL])
+	assert.Equal(t, "https://github.com/myorg/myrepo/pull/7", build.Annotations[kube.AnnotationPullRequestURL])
+	assert.Equal(t, "JX-12,JX-34", build.Annotations[kube.AnnotationIssues])
+	assert.Equal(t, "jira", build.Annotations[kube.AnnotationIssueTrackerKind])
+	assert.Equal(t, "https://myorg.atlassian.net", build.Annotations[kube.AnnotationIssueTrackerURL])
+}
+
+func TestStepCreateBuildReadOnlyTeamSettings(t *testing.T) {
+	t.Parallel()
+	testDir := newStepCreateBuildTestDir(t, "test-step-create-build-read-only")
+	defer os.RemoveAll(testDir)
+
+	projectYaml := `builds:
+  - kind: release
+    build:
+      steps:
+        - name: build
+          image: golang:1.11
+`
+	writeStepCreateBuildProjectConfig(t, testDir, projectYaml)
+
+	o := newStepCreateBuildTestOptions(testDir, nil, nil, nil)
+	o.OutputDir = testDir
+	o.NoAudit = true
+	jxClient, ns, err := o.JXClientAndDevNamespace()
+	assert.NoError(t, err)
+	err = jxClient.JenkinsV1().Environments(ns).Delete(kube.LabelValueDevEnvironment, nil)
+	assert.NoError(t, err)
+
+	err = o.Run()
+	assert.NoError(t, err, "the default team settings should be used when there is no Development environment")
+	build := loadStepCreateBuild(t, filepath.Join(testDir, actualBuildFileName))
+	assert.Equal(t, 1, len(build.Spec.Steps))
+
+	envs, err := jxClient.JenkinsV1().Environments(ns).List(metav1.ListOptions{})
+	assert.NoError(t, err)
+	assert.Empty(t, envs.Items, "the Development environment should not be created")
+	apiClient, err := o.CreateApiExtensionsClient()
+	assert.NoError(t, err)
+	crds, err := apiClient.ApiextensionsV1beta1().CustomResourceDefinitions().List(metav1.ListOptions{})
+	assert.NoError(t, err)
+	assert.Empty(t, crds.Items, "no CRDs should be registered")
+}
+
+func TestStepCreateBuildGitProxy(t *testing.T) {
+	// the git commands are run by a fake git on the PATH which records their environment
+	testDir := newStepCreateBuildTestDir(t, "test-step-create-build-git-proxy")
+	defer os.RemoveAll(testDir)
+
+	projectYaml := `builds:
+  - kind: release
+    build:
+      steps:
+        - name: build
+          image: golang:1.11
+`
+	writeStepCreateBuildProjectConfig(t, testDir, projectYaml)
+
+	gitPath, err := exec.LookPath("git")
+	assert.NoError(t, err)
+	binDir := filepath.Join(testDir, "bin")
+	err = os.MkdirAll(binDir, util.DefaultWritePermissions)
+	assert.NoError(t, err)
+	envFile := filepath.Join(testDir, "git-env")
+	path := os.Getenv("PATH")
+	defer os.Setenv("PATH", path)
+	os.Setenv("PATH", binDir+string(os.PathListSeparator)+path)
+
+	devEnv := kube.NewPermanentEnvironment("dev")
+	devEnv.Spec.Namespace = "jx"
+	devEnv.Spec.Kind = v1.EnvironmentKindTypeDevelopment
+	devEnv.Spec.TeamSettings.BuildDefaults.GitProxy = v1.GitProxy{
+		URL:        "http://proxy.example.com:8080",
+		AuthMethod: gits.ProxyAuthNTLM,
+	}
+
+	generate := func(version string) error {
+		script := fmt.Sprintf("#!/bin/sh\nenv >> %s\nif [ \"$1\" = \"version\" ]; then echo \"git version %s\"; exit 0; fi\nexec %s \"$@\"\n", envFile, version, gitPath)
+		err := ioutil.WriteFile(filepath.Join(binDir, "git"), []byte(script), 0755)
+		assert.NoError(t, err)
+		o := newStepCreateBuildTestOptions(testDir, nil, nil, []runtime.Object{devEnv})
+		o.OutputDir = filepath.Join(testDir, "out")
+		o.Branch = "master"
+		o.NoAudit = true
+		return o.Run()
+	}
+
+	err = generate("2.39.5")
+	assert.NoError(t, err)
+	data, err := ioutil.ReadFile(envFile)
+	assert.NoError(t, err)
+	env := strings.Split(string(data), "\n")
+	assert.Contains(t, env, "GIT_CONFIG_COUNT=2")
+	assert.Contains(t, env, "GIT_CONFIG_KEY_0=http.proxy")
+	assert.Contains(t, env, "GIT_CONFIG_VALUE_0=http://proxy.example.com:8080")
+	assert.Contains(t, env, "GIT_CONFIG_KEY_1=http.proxyAuthMethod")
+	assert.Contains(t, env, "GIT_CONFIG_VALUE_1=ntlm")
+	assert.Contains(t, env, "GIT_TERMINAL_PROMPT=0")
+
+	err = generate("2.20.1")
+	if assert.Error(t, err, "git versions which ignore the configuration in the environment should fail") {
+		assert.Equal(t, util.ExitCodeValidation, util.ExitCode(err))
+	}
+}
+
+func TestStepCreateBuildRepositories(t *testing.T) {
+	t.Parallel()
+	testDir := newStepCreateBuildTestDir(t, "test-step-create-build-repos")
+	defer os.RemoveAll(testDir)
+
+	projectYaml := `builds:
+  - kind: release
+    build:
+      steps:
+        - name: build
+          image: golang:1.11
+`
+	gitter := gits.NewGitCLI()
+	createRepo := func(name string) string {
+		repoDir := filepath.Join(testDir, "myorg", name+".git")
+		err := os.MkdirAll(repoDir, util.DefaultWritePermissions)
+		assert.NoError(t, err)
+		writeStepCreateBuildProjectConfig(t, repoDir, projectYaml)
+		err = gitter.Init(repoDir)
+		assert.NoError(t, err)
+		for _, args := range [][]string{{"config", "user.name", "test"}, {"config", "user.email", "test@example.com"}} {
+			_, err = (&util.Command{Dir: repoDir, Name: "git", Args: args}).RunWithoutRetry()
+			assert.NoError(t, err)
+		}
+		err = gitter.Add(repoDir, "*")
+		assert.NoError(t, err)
+		err = gitter.CommitDir(repoDir, "initial import")
+		assert.NoError(t, err)
+		return "file://" + repoDir
+	}
+	app1 := createRepo("app1")
+	app2 := createRepo("app2")
+	missing := "file://" + filepath.Join(testDir, "myorg", "missing.git")
+
+	o := newStepCreateBuildTestOptions(testDir, gitter, nil, nil)
+	o.OutputDir = filepath.Join(testDir, "out")
+	o.NoAudit = true
+	o.Repos = []string{app1, missing, app2 + "#master"}
+	err := o.Run()
+	if assert.Error(t, err, "the repository which cannot be cloned should fail the command") {
+		assert.Contains(t, err.Error(), "failed to generate the builds of 1 of the 3 repositories "+missing)
+		assert.Equal(t, util.ExitCodeUnreachable, util.ExitCode(err))
+	}
+	for _, gitURL := range []string{app1, app2} {
+		gitInfo, err := gits.ParseGitURL(gitURL)
+		assert.NoError(t, err)
+		build := loadStepCreateBuild(t, filepath.Join(o.OutputDir, gitInfo.Host, gitInfo.Organisation, gitInfo.Name, actualBuildFileName))
+		assert.Equal(t, 1, len(build.Spec.Steps), "the builds of %s should be generated", gitURL)
+	}
+	assert.Equal(t, testDir, o.Dir, "the options should be restored once the builds are generated")
+
+	o.OutputDir = ""
+	err = o.Run()
+	if assert.Error(t, err, "the builds of more than one repository should not be written to the standard output") {
+		assert.Equal(t, util.ExitCodeMissingOption, util.ExitCode(err))
+	}
+}
+
+func TestStepCreateBuildLoadErrors(t *testing.T) {
+	t.Parallel()
+	testDir := newStepCreateBuildTestDir(t, "test-step-create-build-load-errors")
+	defer os.RemoveAll(testDir)
+
+	projectYaml := `builds:
+  - kind: release
+    build:
+      steps:
+        - name: build
+          image: golang:1.11
+`
+	writeStepCreateBuildProjectConfig(t, testDir, projectYaml)
+
+	o := newStepCreateBuildTestOptions(testDir, nil, nil, nil)
+	o.OutputDir = testDir
+	o.NoAudit = true
+	jxClient, _, err := o.JXClientAndDevNamespace()
+	assert.NoError(t, err)
+	jxClient.(*v1fake.Clientset).PrependReactor("get", "environments", func(action k8sTesting.Action) (bool, runtime.Object, error) {
+		return true, nil, fmt.Errorf("the server is currently unable to handle the request")
+	})
+	err = o.Run()
+	if assert.Error(t, err, "the error loading the team settings should be returned") {
+		assert.Contains(t, err.Error(), "failed to load the team settings")
+		assert.Equal(t, util.ExitCodeUnreachable, util.ExitCode(err))
+	}
+
+	writeStepCreateBuildProjectConfig(t, testDir, "builds: [")
+	o = newStepCreateBuildTestOptions(testDir, nil, nil, nil)
+	o.OutputDir = testDir
+	o.NoAudit = true
+	err = o.Run()
+	if assert.Error(t, err, "the error loading the project configuration should be returned") {
+		assert.Equal(t, util.ExitCodeValidation, util.ExitCode(err))
+	}
+}
+
+func TestStepCreateBuildFlagDefaultsOfOtherCommands(t *testing.T) {
+	t.Parallel()
+	testDir := newStepCreateBuildTestDir(t, "test-step-create-build-flag-defaults-other-commands")
+	defer os.RemoveAll(testDir)
+
+	projectYaml := `builds:
+  - kind: release
+    build:
+      steps:
+        - name: build
+          image: golang:1.11
+`
+	writeStepCreateBuildProjectConfig(t, testDir, projectYaml)
+	err := os.MkdirAll(filepath.Join(testDir, ".jx"), util.DefaultWritePermissions)
+	assert.NoError(t, err)
+	err = ioutil.WriteFile(filepath.Join(testDir, ".jx", "createbuild.yaml"), []byte("kind: release\nno-spot: true\n"), util.DefaultWritePermissions)
+	assert.NoError(t, err)
+
+	o := newStepCreateBuildTestOptions(testDir, nil, nil, nil)
+	o.OutputDir = testDir
+	o.NoAudit = true
+	port := 8080
+	o.Cmd = &cobra.Command{Use: "taskgen"}
+	o.Cmd.Flags().IntVarP(&port, "port", "", 8080, "")
+	err = o.Run()
+	assert.NoError(t, err, "the defaults of step create build should not be applied to the flags of another command")
+	assert.FileExists(t, filepath.Join(testDir, actualBuildFileName))
+}
+
+// BenchmarkStepCreateBuild guards the time and allocations of generating large builds written to the standard output
+func BenchmarkStepCreateBuild(b *testing.B) {
+	testDir, err := ioutil.TempDir("", "bench-step-create-build")
+	if err != nil {
+		b.Fatal(err)
+	}
+	defer os.RemoveAll(testDir)
+
+	var projectYaml bytes.Buffer
+	projectYaml.WriteString("builds:\n")
+	for _, kind := range []string{config.BuildKindRelease, config.BuildKindPullRequest} {
+		fmt.Fprintf(&projectYaml, "  - kind: %s\n    build:\n      steps:\n", kind)
+		for i := 0; i < 500; i++ {
+			fmt.Fprintf(&projectYaml, "        - name: step-%d\n          image: maven:3.6\n          args:\n          - make\n          - target-%d\n          env:\n          - name: GOPROXY\n            value: https://proxy.golang.org\n", i, i)
+		}
+	}
+	err = ioutil.WriteFile(filepath.Join(testDir, config.ProjectConfigFileName), projectYaml.Bytes(), util.DefaultWritePermissions)
+	if err != nil {
+		b.Fatal(err)
+	}
+	devNull, err := os.OpenFile(os.DevNull, os.O_WRONLY, 0)
+	if err != nil {
+		b.Fatal(err)
+	}
+	defer devNull.Close()
+
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		o := &cmd.StepCreateBuildOptions{}
+		cmd.ConfigureTestOptionsWithResources(&o.CommonOptions, nil, nil, gits.NewGitCLI(), helm.NewHelmCLI("helm", helm.V2, testDir, true))
+		o.Out = devNull
+		o.Dir = testDir
+		o.NoAudit = true
+		err = o.Run()
+		if err != nil {
+			b.Fatal(err)
+		}
+	}
+}
@@ -0,0 +1,159 @@
+package cmd
+
+import (
+	"crypto/tls"
+	"fmt"
+	"net"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/jenkins-x/jx/pkg/config"
+	"github.com/jenkins-x/jx/pkg/log"
+	"github.com/jenkins-x/jx/pkg/util"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/client-go/rest"
+)
+
+// strictTLSDialTimeout how long the connection to an endpoint verified with --strict-tls may take
+const strictTLSDialTimeout = 10 * time.Second
+
+// insecureRegistryArgs the arguments of the image builders and registry tools which disable TLS or the verification
+// of the certificate of a registry
+var insecureRegistryArgs = []string{
+	"--insecure",
+	"--insecure-pull",
+	"--insecure-registry",
+	"--skip-tls-verify",
+	"--skip-tls-verify-pull",
+	"--skip-tls-verify-registry",
+	"--tls-verify=false",
+	"--src-tls-verify=false",
+	"--dest-tls-verify=false",
+}
+
+// verifyStrictTLSEndpoints verifies the Kubernetes API server, the git servers and the docker registry which the
+// generation connects to are accessed with TLS 1.2 or later and verified certificates
+func (o *StepCreateBuildOptions) verifyStrictTLSEndpoints(pc *config.ProjectConfig) error {
+	verified := map[string]bool{}
+	verify := func(description string, endpoint string, tlsConfig *tls.Config) error {
+		hostPort, err := strictTLSHostPort(description, endpoint)
+		if err != nil || hostPort == "" || verified[hostPort] {
+			return err
+		}
+		verified[hostPort] = true
+		err = dialStrictTLS(hostPort, tlsConfig)
+		if err != nil {
+			return util.WithExitCode(fmt.Errorf("strict TLS: failed to connect to the %s %s with TLS 1.2 or later and a verified certificate: %s", description, endpoint, err), util.ExitCodeValidation)
+		}
+		if o.Verbose {
+			log.Infof("Verified the %s %s uses TLS 1.2 or later\n", description, util.ColorInfo(endpoint))
+		}
+		return nil
+	}
+
+	if o.Factory != nil {
+		kubeConfig, err := o.Factory.CreateKubeConfig()
+		if err == nil && kubeConfig != nil {
+			if kubeConfig.Insecure {
+				return util.WithExitCode(fmt.Errorf("strict TLS: the Kubernetes API server %s is accessed without verifying its certificate", kubeConfig.Host), util.ExitCodeValidation)
+			}
+			tlsConfig, err := rest.TLSConfigFor(kubeConfig)
+			if err != nil {
+				return err
+			}
+			err = verify("Kubernetes API server", kubeConfig.Host, tlsConfig)
+			if err != nil {
+				return err
+			}
+		}
+	}
+	if o.PacksDir == "" && o.buildPackURL != "" && o.needsBuildPacks(pc) {
+		err := verify("build packs git server", o.buildPackURL, nil)
+		if err != nil {
+			return err
+		}
+	}
+	gitInfo, err := o.FindGitInfo(o.Dir)
+	if err == nil && gitInfo != nil && gitInfo.URL != "" {
+		err = verify("git server", gitInfo.URL, nil)
+		if err != nil {
+			return err
+		}
+	}
+	if o.dockerRegistry != "" {
+		return verify("docker registry", o.dockerRegistry, nil)
+	}
+	return nil
+}
+
+// strictTLSHostPort returns the host and port of the TLS endpoint of the URL, an empty string for git URLs which use
+// ssh or an error for URLs which are accessed without TLS
+func strictTLSHostPort(description string, endpoint string) (string, error) {
+	if strings.HasPrefix(endpoint, "git@") || strings.HasPrefix(endpoint, "ssh://") {
+		return "", nil
+	}
+	rawURL := endpoint
+	if !strings.Contains(rawURL, "://") {
+		rawURL = "https://" + rawURL
+	}
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return "", util.WithExitCode(fmt.Errorf("strict TLS: the %s %s is not a valid URL: %s", description, endpoint, err), util.ExitCodeValidation)
+	}
+	if u.Scheme != "https" {
+		return "", util.WithExitCode(fmt.Errorf("strict TLS: the %s %s is accessed with %s rather than https", description, endpoint, u.Scheme), util.ExitCodeValidation)
+	}
+	port := u.Port()
+	if port == "" {
+		port = "443"
+	}
+	return net.JoinHostPort(u.Hostname(), port), nil
+}
+
+// dialStrictTLS connects to the endpoint requiring TLS 1.2 or later and a certificate verified by the certificate
+// authorities of the configuration or the system
+func dialStrictTLS(hostPort string, tlsConfig *tls.Config) error {
+	if tlsConfig == nil {
+		tlsConfig = &tls.Config{}
+	} else {
+		tlsConfig = tlsConfig.Clone()
+	}
+	tlsConfig.MinVersion = tls.VersionTLS12
+	tlsConfig.InsecureSkipVerify = false
+	if tlsConfig.ServerName == "" {
+		host, _, err := net.SplitHostPort(hostPort)
+		if err != nil {
+			return err
+		}
+		tlsConfig.ServerName = host
+	}
+	conn, err := tls.DialWithDialer(&net.Dialer{Timeout: strictTLSDialTimeout}, "tcp", hostPort, tlsConfig)
+	if err != nil {
+		return err
+	}
+	return conn.Close()
+}
+
+// verifyStrictTLSSteps verifies the steps do not disable TLS or the verification of certificates when pushing to or
+// pulling from registries and git servers
+func verifyStrictTLSSteps(steps []corev1.Container) error {
+	for _, step := range steps {
+		for _, arg := range append(append([]string{}, step.Command...), step.Args...) {
+			for _, insecureArg := range insecureRegistryArgs {
+				if arg == insecureArg || (strings.HasPrefix(arg, insecureArg+"=") && !strings.HasSuffix(arg, "=false")) {
+					return util.WithExitCode(fmt.Errorf("strict TLS: step %s disables TLS verification with %s", step.Name, arg), util.ExitCodeValidation)
+				}
+			}
+			if strings.Contains(arg, "http.sslVerify=false") {
+				return util.WithExitCode(fmt.Errorf("strict TLS: step %s disables the verification of the certificates of git servers with %s", step.Name, arg), util.ExitCodeValidation)
+			}
+		}
+		for _, env := range step.Env {
+			if env.Name == "GIT_SSL_NO_VERIFY" && env.Value != "" && env.Value != "false" {
+				return util.WithExitCode(fmt.Errorf("strict TLS: step %s disables the verification of the certificates of git servers with $GIT_SSL_NO_VERIFY", step.Name), util.ExitCodeValidation)
+			}
+		}
+	}
+	return nil
+}
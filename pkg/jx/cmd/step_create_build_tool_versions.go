@@ -0,0 +1,25 @@
+package cmd
+
+import (
+	"strings"
+
+	"github.com/jenkins-x/jx/pkg/config"
+)
+
+// toolImageVersionPlaceholder the placeholder in the tool images of the build defaults which is replaced with the
+// version of the tool pinned by the project
+const toolImageVersionPlaceholder = "{{version}}"
+
+// resolveToolImage returns the image of the first tool whose version the project pins and which has a tool image in
+// the build defaults along with the tool or empty strings if there is none
+func resolveToolImage(toolImages map[string]string, versions map[string]string) (string, string) {
+	for _, tool := range config.ToolNames {
+		version := versions[tool]
+		image := toolImages[tool]
+		if version == "" || image == "" {
+			continue
+		}
+		return strings.Replace(image, toolImageVersionPlaceholder, version, -1), tool
+	}
+	return "", ""
+}
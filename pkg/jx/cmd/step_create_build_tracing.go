@@ -0,0 +1,55 @@
+package cmd
+
+import (
+	"github.com/jenkins-x/jx/pkg/apis/jenkins.io/v1"
+	"github.com/jenkins-x/jx/pkg/kube"
+	"github.com/jenkins-x/jx/pkg/tracing"
+	corev1 "k8s.io/api/core/v1"
+)
+
+// traceStepArgs the arguments of jx which run the command of a step in a span
+var traceStepArgs = []string{"step", "trace"}
+
+// applyTracing propagates the trace context of the build to its steps so that the tools run by the steps report their
+// spans as children of the span of their step. The ID of the trace is annotated on the build so that
+// 'jx controller tracing' exports the spans of the pipeline and its steps when the build completes
+func applyTracing(build *Build, tracingConfig *v1.BuildTracing, serviceName string, explain *BuildExplanation) error {
+	if !tracingConfig.Enabled {
+		return nil
+	}
+	traceID, err := tracing.NewTraceID()
+	if err != nil {
+		return err
+	}
+	if build.Annotations == nil {
+		build.Annotations = map[string]string{}
+	}
+	build.Annotations[kube.AnnotationTraceID] = traceID
+	for i := range build.Spec.Steps {
+		step := &build.Spec.Steps[i]
+		step.Env = setEnvVar(step.Env, corev1.EnvVar{Name: tracing.EnvTraceParent, Value: tracing.TraceParent(traceID, tracing.SpanID(traceID, step.Name))})
+		step.Env = setEnvVar(step.Env, corev1.EnvVar{Name: tracing.EnvServiceName, Value: serviceName})
+		if tracingConfig.Endpoint != "" {
+			step.Env = setEnvVar(step.Env, corev1.EnvVar{Name: tracing.EnvExporterEndpoint, Value: tracingConfig.Endpoint})
+		}
+		if tracingConfig.WrapSteps && wrapTracedStep(step) {
+			explain.explainMutation(step.Name, "runs its command with 'jx step trace' which exports a span of the command")
+		}
+	}
+	return nil
+}
+
+// wrapTracedStep runs the command of the step with 'jx step trace'. Returns false if the step has no command as the
+// entrypoint of its image is not known or if it is already traced
+func wrapTracedStep(step *corev1.Container) bool {
+	commandLine := append(append([]string{}, step.Command...), step.Args...)
+	if len(commandLine) == 0 {
+		return false
+	}
+	if len(commandLine) > len(traceStepArgs) && commandLine[0] == "jx" && commandLine[1] == traceStepArgs[0] && commandLine[2] == traceStepArgs[1] {
+		return false
+	}
+	step.Command = []string{"jx"}
+	step.Args = append(append(append([]string{}, traceStepArgs...), "--name", step.Name, "--"), commandLine...)
+	return true
+}
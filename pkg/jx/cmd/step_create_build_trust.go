@@ -0,0 +1,77 @@
+package cmd
+
+import (
+	"github.com/jenkins-x/jx/pkg/util"
+	corev1 "k8s.io/api/core/v1"
+	networkingv1 "k8s.io/api/networking/v1"
+)
+
+const (
+	// trustLevelTrusted the trust level of builds which have full access to the secrets and service account of the team
+	trustLevelTrusted = "trusted"
+	// trustLevelUntrusted the trust level of builds of pull requests from forks which are hardened
+	trustLevelUntrusted = "untrusted"
+
+	// defaultUntrustedServiceAccount the service account of untrusted builds if the team does not specify one
+	defaultUntrustedServiceAccount = "default"
+)
+
+var trustLevels = []string{trustLevelTrusted, trustLevelUntrusted}
+
+// hardenUntrustedBuild removes the secret volume mounts and secret environment variables of the steps, runs the steps
+// without privileges and runs the build as the given restricted service account
+func hardenUntrustedBuild(build *Build, secretVolumes []string, serviceAccount string, explain *BuildExplanation) {
+	if serviceAccount == "" {
+		serviceAccount = defaultUntrustedServiceAccount
+	}
+	build.Spec.ServiceAccountName = serviceAccount
+	privileged := false
+	allowPrivilegeEscalation := false
+	for i := range build.Spec.Steps {
+		step := &build.Spec.Steps[i]
+		mounts := []corev1.VolumeMount{}
+		for _, vm := range step.VolumeMounts {
+			if util.StringArrayIndex(secretVolumes, vm.Name) < 0 {
+				mounts = append(mounts, vm)
+			}
+		}
+		step.VolumeMounts = mounts
+		envVars := []corev1.EnvVar{}
+		for _, env := range step.Env {
+			if env.ValueFrom == nil || env.ValueFrom.SecretKeyRef == nil {
+				envVars = append(envVars, env)
+			}
+		}
+		step.Env = envVars
+		if step.SecurityContext == nil {
+			step.SecurityContext = &corev1.SecurityContext{}
+		}
+		step.SecurityContext.Privileged = &privileged
+		step.SecurityContext.AllowPrivilegeEscalation = &allowPrivilegeEscalation
+		explain.explainMutation(step.Name, "removed the secrets and privileges as the build is %s", trustLevelUntrusted)
+	}
+}
+
+// secretVolumeNames returns the names of the volumes which mount secrets
+func secretVolumeNames(volumes ...[]corev1.Volume) []string {
+	answer := []string{}
+	for _, vs := range volumes {
+		for _, v := range vs {
+			if v.Secret != nil {
+				answer = append(answer, v.Name)
+			}
+		}
+	}
+	return answer
+}
+
+// untrustedNetworkPolicy returns the NetworkPolicy which limits the egress of the pods of an untrusted build to DNS
+// lookups and HTTPS and SSH connections such as to the git host and the registry
+func untrustedNetworkPolicy(build *Build) *networkingv1.NetworkPolicy {
+	return newNetworkPolicy(build, []networkingv1.NetworkPolicyEgressRule{
+		dnsEgressRule(),
+		{
+			Ports: []networkingv1.NetworkPolicyPort{networkPolicyPort(corev1.ProtocolTCP, 443), networkPolicyPort(corev1.ProtocolTCP, 22)},
+		},
+	})
+}
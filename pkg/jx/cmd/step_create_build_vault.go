@@ -0,0 +1,124 @@
+package cmd
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/jenkins-x/jx/pkg/util"
+	corev1 "k8s.io/api/core/v1"
+)
+
+const (
+	// vaultSecretsVolume the in memory volume containing the secrets read from Vault by the first step
+	vaultSecretsVolume = "vault-secrets"
+	// vaultSecretsDir the directory the vault secrets volume is mounted at
+	vaultSecretsDir = "/vault/secrets"
+	// vaultSharedSecretMode the permissions of the secret files when the steps using them run as other users than the
+	// step reading them. The in memory volume is only mounted by the steps of the build pod
+	vaultSharedSecretMode = "0444"
+)
+
+// resolveVaultReferences replaces the environment variables whose values reference a secret in Vault such as
+// 'vault:secret/myapp/db#password' so the secrets are never stored in the build. A first step reads the secrets into
+// files of an in memory volume and the steps using them export the environment variables from the files before
+// running their command. The first step reads them from the Vault address of the team or otherwise $VAULT_ADDR. Returns
+// the steps and the volume of the secrets or nil if there are no references
+func resolveVaultReferences(steps []corev1.Container, vaultAddress string, explain *BuildExplanation) ([]corev1.Container, *corev1.Volume, error) {
+	files := map[string]string{}
+	secrets := []string{}
+	answer := []corev1.Container{}
+	users := []corev1.Container{}
+	for _, step := range steps {
+		envVars := []corev1.EnvVar{}
+		exports := []string{}
+		for _, env := range step.Env {
+			if !strings.HasPrefix(env.Value, vaultReferencePrefix) {
+				envVars = append(envVars, env)
+				continue
+			}
+			_, _, err := parseVaultReference(env.Value)
+			if err != nil {
+				return steps, nil, util.WithExitCode(err, util.ExitCodeValidation)
+			}
+			file := files[env.Value]
+			if file == "" {
+				file = fmt.Sprintf("secret-%d", len(files))
+				files[env.Value] = file
+				secrets = append(secrets, "--secret", file+"="+strings.TrimPrefix(env.Value, vaultReferencePrefix))
+			}
+			exports = append(exports, fmt.Sprintf(`export %s="$(cat %s/%s)"`, env.Name, vaultSecretsDir, file))
+		}
+		if len(exports) > 0 {
+			commandLine := append(append([]string{}, step.Command...), step.Args...)
+			if len(commandLine) == 0 {
+				return steps, nil, fmt.Errorf("The step %s uses secrets from Vault so it must specify its command", step.Name)
+			}
+			step.Env = envVars
+			step.Command = []string{"/bin/sh"}
+			step.Args = append([]string{"-c", strings.Join(exports, " && ") + ` && exec "$@"`, "sh"}, commandLine...)
+			step.VolumeMounts = append(append([]corev1.VolumeMount{}, step.VolumeMounts...), corev1.VolumeMount{Name: vaultSecretsVolume, MountPath: vaultSecretsDir})
+			explain.explainMutation(step.Name, "exported the environment variables referencing secrets in Vault")
+			users = append(users, step)
+		}
+		answer = append(answer, step)
+	}
+	if len(secrets) == 0 {
+		return steps, nil, nil
+	}
+
+	args := []string{"jx", "step", "vault", "--dir", vaultSecretsDir}
+	if vaultAddress != "" {
+		args = append(args, "--addr", vaultAddress)
+	}
+	first := answer[0]
+	// the secret files can only be read by the user writing them unless the steps using them run as other users
+	runAsUser, sameUser := secretsRunAsUser(first.Image, users)
+	if !sameUser {
+		args = append(args, "--mode", vaultSharedSecretMode)
+	}
+	read := createStepFrom(&first, "vault-secrets", append(args, secrets...))
+	read.VolumeMounts = []corev1.VolumeMount{{Name: vaultSecretsVolume, MountPath: vaultSecretsDir}}
+	if runAsUser != nil {
+		read.SecurityContext = &corev1.SecurityContext{RunAsUser: runAsUser}
+	}
+	volume := &corev1.Volume{
+		Name: vaultSecretsVolume,
+		VolumeSource: corev1.VolumeSource{
+			EmptyDir: &corev1.EmptyDirVolumeSource{
+				Medium: corev1.StorageMediumMemory,
+			},
+		},
+	}
+	return append([]corev1.Container{read}, answer...), volume, nil
+}
+
+// secretsRunAsUser returns the user the steps using the secrets run as and true if they all run as the same user as
+// the step reading the secrets with the given image. Steps without a runAsUser run as the user of their image
+func secretsRunAsUser(image string, steps []corev1.Container) (*int64, bool) {
+	var runAsUser *int64
+	for i, step := range steps {
+		var user *int64
+		if step.SecurityContext != nil {
+			user = step.SecurityContext.RunAsUser
+		}
+		if user == nil && step.Image != image {
+			return nil, false
+		}
+		if i > 0 && !sameRunAsUser(user, runAsUser) {
+			return nil, false
+		}
+		runAsUser = user
+	}
+	if runAsUser != nil {
+		uid := *runAsUser
+		return &uid, true
+	}
+	return nil, true
+}
+
+func sameRunAsUser(user *int64, other *int64) bool {
+	if user == nil || other == nil {
+		return user == other
+	}
+	return *user == *other
+}
@@ -0,0 +1,53 @@
+package cmd
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+)
+
+func TestResolveVaultReferencesUser(t *testing.T) {
+	t.Parallel()
+	uid := int64(1000)
+	secretEnv := []corev1.EnvVar{{Name: "DB_PASSWORD", Value: "vault:secret/data/myapp/db#password"}}
+
+	steps := []corev1.Container{
+		{
+			Name:            "deploy",
+			Image:           "maven",
+			Args:            []string{"mvn", "deploy"},
+			Env:             secretEnv,
+			SecurityContext: &corev1.SecurityContext{RunAsUser: &uid},
+		},
+	}
+	steps, _, err := resolveVaultReferences(steps, "", nil)
+	if assert.NoError(t, err) && assert.Equal(t, 2, len(steps)) {
+		read := steps[0]
+		assert.Equal(t, "vault-secrets", read.Name)
+		assert.NotContains(t, read.Args, "--mode")
+		if assert.NotNil(t, read.SecurityContext) && assert.NotNil(t, read.SecurityContext.RunAsUser) {
+			assert.Equal(t, uid, *read.SecurityContext.RunAsUser, "the secrets should be written by the user of the steps using them")
+		}
+	}
+
+	steps = []corev1.Container{
+		{
+			Name:  "build",
+			Image: "maven",
+			Args:  []string{"mvn", "install"},
+		},
+		{
+			Name:  "deploy",
+			Image: "helm",
+			Args:  []string{"helm", "install"},
+			Env:   secretEnv,
+		},
+	}
+	steps, _, err = resolveVaultReferences(steps, "", nil)
+	if assert.NoError(t, err) && assert.Equal(t, 3, len(steps)) {
+		read := steps[0]
+		assert.Equal(t, []string{"step", "vault", "--dir", vaultSecretsDir, "--mode", vaultSharedSecretMode, "--secret", "secret-0=secret/data/myapp/db#password"}, read.Args, "the secrets should be readable by the user of the other image")
+		assert.Nil(t, read.SecurityContext)
+	}
+}
@@ -0,0 +1,23 @@
+package cmd
+
+import (
+	"github.com/jenkins-x/jx/pkg/log"
+	"github.com/jenkins-x/jx/pkg/util"
+	corev1 "k8s.io/api/core/v1"
+)
+
+// warnOutdatedBuilders logs a warning for each builder image of the pod template which is older than the version
+// stream of --versions-dir
+func (o *StepCreateBuildOptions) warnOutdatedBuilders(name string, podTemplate *corev1.Pod) {
+	if o.VersionsDir == "" || podTemplate == nil {
+		return
+	}
+	outdated, err := findOutdatedBuilders(map[string]*corev1.Pod{name: podTemplate}, o.VersionsDir)
+	if err != nil {
+		log.Warnf("Failed to check the builder images of the pod template %s against the version stream: %s\n", name, err)
+		return
+	}
+	for _, b := range outdated {
+		log.Warnf("The builder image %s:%s of the pod template %s is behind the version %s of the version stream. Upgrade it via: %s\n", b.Image, b.Version, util.ColorInfo(name), util.ColorInfo(b.Latest), util.ColorInfo("jx upgrade builders"))
+	}
+}
@@ -0,0 +1,53 @@
+package cmd
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strconv"
+	"testing"
+
+	"github.com/jenkins-x/jx/pkg/util"
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+)
+
+func TestFindOutdatedBuilders(t *testing.T) {
+	t.Parallel()
+	versionsDir, err := ioutil.TempDir("", "test-find-outdated-builders")
+	assert.NoError(t, err)
+	defer os.RemoveAll(versionsDir)
+	dockerDir := filepath.Join(versionsDir, "docker", "jenkinsxio")
+	err = os.MkdirAll(dockerDir, util.DefaultWritePermissions)
+	assert.NoError(t, err)
+	for image, version := range map[string]string{
+		"builder-maven": "0.1.20",
+		"builder-go":    "0.1.10",
+	} {
+		err = ioutil.WriteFile(filepath.Join(dockerDir, image+".yml"), []byte("version: "+version+"\n"), util.DefaultWritePermissions)
+		assert.NoError(t, err)
+	}
+
+	podTemplate := func(images ...string) *corev1.Pod {
+		pod := &corev1.Pod{}
+		for i, image := range images {
+			pod.Spec.Containers = append(pod.Spec.Containers, corev1.Container{Name: "c" + strconv.Itoa(i), Image: image})
+		}
+		return pod
+	}
+	outdated, err := findOutdatedBuilders(map[string]*corev1.Pod{
+		"maven":  podTemplate("jenkinsxio/builder-maven:0.1.9", "jenkinsxio/builder-go:0.1.10"),
+		"go":     podTemplate("jenkinsxio/builder-go:0.1.11"),
+		"custom": podTemplate("myorg/builder-custom:1.0.0"),
+	}, versionsDir)
+	assert.NoError(t, err)
+	assert.Equal(t, []*outdatedBuilder{
+		{
+			PodTemplate: "maven",
+			Container:   "c0",
+			Image:       "jenkinsxio/builder-maven",
+			Version:     "0.1.9",
+			Latest:      "0.1.20",
+		},
+	}, outdated)
+}
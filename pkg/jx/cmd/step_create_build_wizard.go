@@ -0,0 +1,155 @@
+package cmd
+
+import (
+	"os"
+	"path/filepath"
+	"sort"
+
+	"github.com/jenkins-x/jx/pkg/config"
+	"github.com/jenkins-x/jx/pkg/gits"
+	"github.com/jenkins-x/jx/pkg/log"
+	"github.com/jenkins-x/jx/pkg/util"
+	"github.com/mattn/go-isatty"
+	"github.com/pkg/errors"
+)
+
+const (
+	wizardOutputDir    = "write them to a directory"
+	wizardOutputApply  = "write them to a directory and apply them to the current cluster"
+	wizardOutputStdout = "write them to the standard output"
+	wizardAllKinds     = "all"
+
+	// defaultWizardOutputDir the directory of the project the wizard suggests writing the builds to
+	defaultWizardOutputDir = "out"
+)
+
+// interactive returns true if the missing inputs can be prompted for as the command is not in batch mode and its
+// input is a terminal
+func (o *StepCreateBuildOptions) interactive() bool {
+	if o.BatchMode || o.In == nil {
+		return false
+	}
+	return isatty.IsTerminal(o.In.Fd()) || isatty.IsCygwinTerminal(o.In.Fd())
+}
+
+// disableGitPrompts makes the git commands fail rather than prompt on the terminal for credentials in batch mode
+func (o *StepCreateBuildOptions) disableGitPrompts() {
+	if gitCLI, ok := o.Git().(*gits.GitCLI); ok && o.BatchMode {
+		o.GitClient = gitCLI.WithoutPrompts()
+	}
+}
+
+// runOutputWizard prompts for where the builds are written when no output is given and whether the builds written to
+// a directory are applied to the current cluster
+func (o *StepCreateBuildOptions) runOutputWizard() error {
+	if !o.interactive() || o.OutputDir != "" || o.commitMode() || o.Estimate {
+		return nil
+	}
+	choices := []string{wizardOutputDir, wizardOutputApply, wizardOutputStdout}
+	choice, err := util.PickRequiredNameWithDefault(choices, "What do you want to do with the builds?", wizardOutputDir, o.In, o.Out, o.Err)
+	if err != nil {
+		return err
+	}
+	if choice == wizardOutputStdout {
+		return nil
+	}
+	o.OutputDir, err = util.PickValue("Output directory:", filepath.Join(o.Dir, defaultWizardOutputDir), true, o.In, o.Out, o.Err)
+	if err != nil {
+		return err
+	}
+	o.applyBuilds = choice == wizardOutputApply
+	return nil
+}
+
+// runProjectWizard prompts for the build pack of a project without builds or a build pack and the kind of build to
+// create when they are not given
+func (o *StepCreateBuildOptions) runProjectWizard(pc *config.ProjectConfig) error {
+	if !o.interactive() {
+		return nil
+	}
+	if pc.BuildPack == "" && len(pc.Builds) == 0 {
+		names, err := o.podTemplateNames()
+		if err != nil {
+			return err
+		}
+		if len(names) > 0 {
+			pc.BuildPack, err = util.PickRequiredNameWithDefault(names, "Pick the build pack of the project:", "", o.In, o.Out, o.Err)
+			if err != nil {
+				return err
+			}
+			err = o.saveWizardBuildPack(pc)
+			if err != nil {
+				return err
+			}
+		}
+	}
+	if o.BranchKind == "" {
+		kinds := append([]string{wizardAllKinds}, config.BuildKindNames...)
+		for _, k := range pc.PipelineKinds {
+			kinds = append(kinds, k.Name)
+		}
+		kind, err := util.PickRequiredNameWithDefault(kinds, "Pick the kind of build to create:", wizardAllKinds, o.In, o.Out, o.Err)
+		if err != nil {
+			return err
+		}
+		if kind != wizardAllKinds {
+			o.BranchKind = kind
+		}
+	}
+	return nil
+}
+
+// saveWizardBuildPack offers to save the picked build pack in a new jenkins-x.yml file so that it is not prompted for
+// again. An existing file is never rewritten so that its formatting and comments are kept
+func (o *StepCreateBuildOptions) saveWizardBuildPack(pc *config.ProjectConfig) error {
+	if o.ConfigFile != "" {
+		return nil
+	}
+	fileName := filepath.Join(o.Dir, config.ProjectConfigFileName)
+	if _, err := os.Stat(fileName); err == nil {
+		return nil
+	}
+	if !util.Confirm("Save the build pack "+pc.BuildPack+" in "+config.ProjectConfigFileName+"?", true, "", o.In, o.Out, o.Err) {
+		return nil
+	}
+	err := pc.SaveConfig(fileName)
+	if err != nil {
+		return errors.Wrapf(err, "failed to save %s", fileName)
+	}
+	log.Infof("Saved the build pack in %s\n", util.ColorInfo(fileName))
+	return nil
+}
+
+// podTemplateNames returns the sorted names of the pod templates of the pipeline bundle or the team
+func (o *StepCreateBuildOptions) podTemplateNames() ([]string, error) {
+	data := o.bundlePodTemplates
+	if data == nil {
+		kubeClient, ns, err := o.KubeClientAndDevNamespace()
+		if err != nil {
+			return nil, err
+		}
+		data, err = o.loadPodTemplatesData(kubeClient, ns)
+		if err != nil {
+			return nil, err
+		}
+	}
+	names := []string{}
+	for name := range data {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names, nil
+}
+
+// applyGeneratedBuilds applies the builds written to the output directory to the current cluster
+func (o *StepCreateBuildOptions) applyGeneratedBuilds() error {
+	ao := &StepApplyBuildOptions{
+		StepOptions: StepOptions{
+			CommonOptions: o.CommonOptions,
+		},
+		Dir:     o.OutputDir,
+		NoAudit: o.NoAudit,
+	}
+	ao.Args = nil
+	return ao.Run()
+}
@@ -0,0 +1,161 @@
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"io/ioutil"
+	"path/filepath"
+	"strings"
+
+	"github.com/jenkins-x/jx/pkg/cloud/buckets"
+	"github.com/jenkins-x/jx/pkg/config"
+	"github.com/jenkins-x/jx/pkg/cve"
+	"github.com/jenkins-x/jx/pkg/jx/cmd/templates"
+	"github.com/jenkins-x/jx/pkg/log"
+	"github.com/jenkins-x/jx/pkg/util"
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+	"gopkg.in/AlecAivazis/survey.v1/terminal"
+)
+
+var (
+	stepDependencyScanLong = templates.LongDesc(`
+		Reports the vulnerabilities and license issues of the dependencies of a project found by OWASP dependency-check or snyk and fails if any of them is at least as severe as the threshold.
+
+		The files of the directory of the report are uploaded to the report bucket in the folder of the repository and build.
+`)
+
+	stepDependencyScanExample = templates.Examples(`
+		# fail the build if OWASP dependency-check found a high or critical vulnerability
+		jx step dependency-scan --report dependency-scan/dependency-check-report.json
+
+		# upload the snyk report without failing the build
+		jx step dependency-scan --tool snyk --report dependency-scan/snyk.json --threshold none --report-bucket-url gs://mybucket/reports
+	`)
+)
+
+// StepDependencyScanOptions contains the command line flags
+type StepDependencyScanOptions struct {
+	StepOptions
+
+	Tool            string
+	Report          string
+	Threshold       string
+	ReportBucketURL string
+	Repository      string
+}
+
+// NewCmdStepDependencyScan creates the command
+func NewCmdStepDependencyScan(f Factory, in terminal.FileReader, out terminal.FileWriter, errOut io.Writer) *cobra.Command {
+	options := &StepDependencyScanOptions{
+		StepOptions: StepOptions{
+			CommonOptions: CommonOptions{
+				Factory: f,
+				In:      in,
+				Out:     out,
+				Err:     errOut,
+			},
+		},
+	}
+
+	cmd := &cobra.Command{
+		Use:     "dependency-scan",
+		Short:   "Reports the vulnerabilities of the dependencies of a project and fails if any reaches the threshold",
+		Long:    stepDependencyScanLong,
+		Example: stepDependencyScanExample,
+		Run: func(cmd *cobra.Command, args []string) {
+			options.Cmd = cmd
+			options.Args = args
+			err := options.Run()
+			CheckErr(err)
+		},
+	}
+	options.addCommonFlags(cmd)
+
+	cmd.Flags().StringVarP(&options.Tool, "tool", "t", config.DependencyScanOWASP, "The tool which wrote the report: "+strings.Join(config.DependencyScanTools, ", "))
+	cmd.Flags().StringVarP(&options.Report, "report", "r", "", "The JSON report of the tool")
+	cmd.Flags().StringVarP(&options.Threshold, "threshold", "", config.SeverityHigh, "The lowest severity which fails the build: "+strings.Join(config.Severities, ", ")+" or "+config.SeverityNone+" to only report the vulnerabilities")
+	cmd.Flags().StringVarP(&options.ReportBucketURL, "report-bucket-url", "", "", "The URL of the bucket which the reports are uploaded to")
+	cmd.Flags().StringVarP(&options.Repository, "repository", "", "", "The owner/repository the reports belong to. Defaults to the git repository of the current directory")
+	return cmd
+}
+
+// Run implements this command
+func (o *StepDependencyScanOptions) Run() error {
+	if o.Report == "" {
+		return util.MissingOption("report")
+	}
+	scanConfig := &config.DependencyScanConfig{
+		Tool:      o.Tool,
+		Threshold: o.Threshold,
+	}
+	err := scanConfig.Validate()
+	if err != nil {
+		return err
+	}
+	vulnerabilities, err := cve.LoadDependencyReport(o.Tool, o.Report)
+	if err != nil {
+		return err
+	}
+	if o.ReportBucketURL != "" {
+		err = o.uploadReports()
+		if err != nil {
+			return err
+		}
+	}
+	if len(vulnerabilities) == 0 {
+		log.Infof("No vulnerabilities were found in the dependencies\n")
+		return nil
+	}
+	for _, v := range vulnerabilities {
+		log.Infof("%s %s %s\n", util.ColorWarning(strings.ToUpper(v.Severity)), v.ID, util.ColorInfo(v.Dependency))
+	}
+	log.Infof("Found %d vulnerabilities in the dependencies\n", len(vulnerabilities))
+	if o.Threshold == config.SeverityNone {
+		return nil
+	}
+	failed := cve.AtLeast(vulnerabilities, o.Threshold)
+	if len(failed) > 0 {
+		return fmt.Errorf("found %d vulnerabilities in the dependencies which are at least %s", len(failed), o.Threshold)
+	}
+	return nil
+}
+
+// uploadReports uploads the files of the directory of the report to the folder of the build in the report bucket
+func (o *StepDependencyScanOptions) uploadReports() error {
+	if o.Repository == "" {
+		gitInfo, err := o.FindGitInfo("")
+		if err != nil {
+			return err
+		}
+		o.Repository = gitInfo.Organisation + "/" + gitInfo.Name
+	}
+	build := o.getBuildNumber()
+	if build == "" {
+		build = "local"
+	}
+	dir := filepath.Dir(o.Report)
+	files, err := ioutil.ReadDir(dir)
+	if err != nil {
+		return err
+	}
+	for _, file := range files {
+		if file.IsDir() {
+			continue
+		}
+		data, err := ioutil.ReadFile(filepath.Join(dir, file.Name()))
+		if err != nil {
+			return err
+		}
+		contentType := "application/json"
+		if strings.HasSuffix(file.Name(), ".html") {
+			contentType = "text/html; charset=utf-8"
+		}
+		u, err := buckets.WriteFile(o.ReportBucketURL, strings.Join([]string{o.Repository, build, "dependency-scan", file.Name()}, "/"), data, contentType)
+		if err != nil {
+			return errors.Wrapf(err, "failed to upload the dependency scan report %s", file.Name())
+		}
+		log.Infof("Uploaded the dependency scan report to %s\n", util.ColorInfo(u))
+	}
+	return nil
+}
@@ -0,0 +1,45 @@
+package cmd
+
+import (
+	"io"
+
+	"github.com/spf13/cobra"
+	"gopkg.in/AlecAivazis/survey.v1/terminal"
+)
+
+// StepExportOptions contains the command line flags
+type StepExportOptions struct {
+	StepOptions
+}
+
+// NewCmdStepExport Creates a new Command object
+func NewCmdStepExport(f Factory, in terminal.FileReader, out terminal.FileWriter, errOut io.Writer) *cobra.Command {
+	options := &StepExportOptions{
+		StepOptions: StepOptions{
+			CommonOptions: CommonOptions{
+				Factory: f,
+				In:      in,
+				Out:     out,
+				Err:     errOut,
+			},
+		},
+	}
+
+	cmd := &cobra.Command{
+		Use:   "export",
+		Short: "export [command]",
+		Run: func(cmd *cobra.Command, args []string) {
+			options.Cmd = cmd
+			options.Args = args
+			err := options.Run()
+			CheckErr(err)
+		},
+	}
+	cmd.AddCommand(NewCmdStepExportPipelineBundle(f, in, out, errOut))
+	return cmd
+}
+
+// Run implements this command
+func (o *StepExportOptions) Run() error {
+	return o.Cmd.Help()
+}
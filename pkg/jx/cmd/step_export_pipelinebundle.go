@@ -0,0 +1,220 @@
+package cmd
+
+import (
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/ghodss/yaml"
+	"github.com/jenkins-x/jx/pkg/draft"
+	"github.com/jenkins-x/jx/pkg/jx/cmd/templates"
+	"github.com/jenkins-x/jx/pkg/kube"
+	"github.com/jenkins-x/jx/pkg/log"
+	"github.com/jenkins-x/jx/pkg/util"
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+	"gopkg.in/AlecAivazis/survey.v1/terminal"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+const defaultPipelineBundleFile = "pipelinebundle.tar.gz"
+
+var (
+	stepExportPipelineBundleLong = templates.LongDesc(`
+		Exports the build packs and pod templates of the team into a pipeline bundle so that builds can be generated in clusters without internet access.
+
+		The bundle is a gzipped tarball containing the build packs without their git history, the pod templates of the team and the list of the images used by the pod templates and build packs which have to be mirrored into the registry of the cluster. Images which are not pinned to a version are reported.
+
+		Generate the builds from the bundle with 'jx step create build --bundle'.
+`)
+
+	stepExportPipelineBundleExample = templates.Examples(`
+		# export the build packs and pod templates of the team
+		jx step export pipelinebundle
+
+		# generate the builds in a cluster without internet access
+		jx step create build --bundle pipelinebundle.tar.gz
+	`)
+)
+
+// StepExportPipelineBundleOptions contains the command line flags
+type StepExportPipelineBundleOptions struct {
+	StepOptions
+
+	OutputFile string
+	PacksDir   string
+}
+
+// NewCmdStepExportPipelineBundle creates the command
+func NewCmdStepExportPipelineBundle(f Factory, in terminal.FileReader, out terminal.FileWriter, errOut io.Writer) *cobra.Command {
+	options := &StepExportPipelineBundleOptions{
+		StepOptions: StepOptions{
+			CommonOptions: CommonOptions{
+				Factory: f,
+				In:      in,
+				Out:     out,
+				Err:     errOut,
+			},
+		},
+	}
+
+	cmd := &cobra.Command{
+		Use:     "pipelinebundle",
+		Short:   "Exports the build packs and pod templates of the team for generating builds in clusters without internet access",
+		Long:    stepExportPipelineBundleLong,
+		Example: stepExportPipelineBundleExample,
+		Aliases: []string{"pipeline-bundle"},
+		Run: func(cmd *cobra.Command, args []string) {
+			options.Cmd = cmd
+			options.Args = args
+			err := options.Run()
+			CheckErr(err)
+		},
+	}
+	options.addCommonFlags(cmd)
+
+	cmd.Flags().StringVarP(&options.OutputFile, "output", "o", defaultPipelineBundleFile, "The file the bundle is written to")
+	cmd.Flags().StringVarP(&options.PacksDir, "packs-dir", "", "", "The directory containing the build packs. Defaults to the build packs of the team")
+	return cmd
+}
+
+// Run implements this command
+func (o *StepExportPipelineBundleOptions) Run() error {
+	if o.OutputFile == "" {
+		return util.MissingOption("output")
+	}
+	packsDir := o.PacksDir
+	if packsDir == "" {
+		var err error
+		packsDir, err = loadBuildPacks(o.CommonOptions)
+		if err != nil {
+			return err
+		}
+	}
+	kubeClient, ns, err := o.KubeClientAndDevNamespace()
+	if err != nil {
+		return err
+	}
+	cm, err := kubeClient.CoreV1().ConfigMaps(ns).Get(kube.ConfigMapJenkinsPodTemplates, metav1.GetOptions{})
+	if err != nil {
+		return errors.Wrapf(err, "failed to find ConfigMap %s in namespace %s", kube.ConfigMapJenkinsPodTemplates, ns)
+	}
+
+	dir, err := util.CreateWorkspace("jx-pipelinebundle-")
+	if err != nil {
+		return err
+	}
+	defer os.RemoveAll(dir)
+	err = copyBuildPacks(packsDir, filepath.Join(dir, pipelineBundleBuildPacksDir))
+	if err != nil {
+		return errors.Wrapf(err, "failed to copy the build packs of %s", packsDir)
+	}
+	data, err := yaml.Marshal(cm.Data)
+	if err != nil {
+		return err
+	}
+	err = ioutil.WriteFile(filepath.Join(dir, pipelineBundlePodTemplatesFile), data, DefaultWritePermissions)
+	if err != nil {
+		return err
+	}
+	images, err := pipelineBundleImages(packsDir, cm.Data)
+	if err != nil {
+		return err
+	}
+	for _, image := range images {
+		if !pinnedImage(image) {
+			log.Warnf("The image %s is not pinned to a version so the mirrored image may differ from the image the builds were tested with\n", image)
+		}
+	}
+	err = ioutil.WriteFile(filepath.Join(dir, pipelineBundleImagesFile), []byte(strings.Join(images, "\n")+"\n"), DefaultWritePermissions)
+	if err != nil {
+		return err
+	}
+	err = util.TargzDir(dir, o.OutputFile)
+	if err != nil {
+		return errors.Wrapf(err, "failed to write the pipeline bundle %s", o.OutputFile)
+	}
+	log.Infof("Exported the build packs, %d pod templates and %d images to the pipeline bundle %s\n", len(cm.Data), len(images), util.ColorInfo(o.OutputFile))
+	return nil
+}
+
+// copyBuildPacks copies the build packs without the git repository they were cloned from
+func copyBuildPacks(packsDir string, dir string) error {
+	return filepath.Walk(packsDir, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		if info.IsDir() && info.Name() == ".git" {
+			return filepath.SkipDir
+		}
+		rel, err := filepath.Rel(packsDir, path)
+		if err != nil {
+			return err
+		}
+		target := filepath.Join(dir, rel)
+		if info.IsDir() {
+			return os.MkdirAll(target, util.DefaultWritePermissions)
+		}
+		if !info.Mode().IsRegular() {
+			return nil
+		}
+		return util.CopyFile(path, target)
+	})
+}
+
+// pipelineBundleImages returns the sorted images of the containers of the pod templates and the steps of the build
+// packs
+func pipelineBundleImages(packsDir string, podTemplatesData map[string]string) ([]string, error) {
+	images := map[string]bool{}
+	podTemplates, err := kube.ParsePodTemplates(podTemplatesData)
+	if err != nil {
+		return nil, err
+	}
+	for _, podTemplate := range podTemplates {
+		for _, container := range append(podTemplate.Spec.InitContainers, podTemplate.Spec.Containers...) {
+			if container.Image != "" {
+				images[container.Image] = true
+			}
+		}
+	}
+	files, err := ioutil.ReadDir(packsDir)
+	if err != nil {
+		return nil, err
+	}
+	for _, f := range files {
+		packDir := filepath.Join(packsDir, f.Name())
+		if !f.IsDir() || !fileExists(filepath.Join(packDir, draft.JenkinsfileName)) {
+			continue
+		}
+		converted, _, err := draft.ConvertPack(packDir)
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed to convert the Jenkinsfile of build pack %s", f.Name())
+		}
+		for _, build := range converted.Builds {
+			for _, step := range build.Build.Steps {
+				if step.Image != "" {
+					images[step.Image] = true
+				}
+			}
+		}
+	}
+	answer := []string{}
+	for image := range images {
+		answer = append(answer, image)
+	}
+	sort.Strings(answer)
+	return answer, nil
+}
+
+// pinnedImage returns true if the image has a digest or a tag other than latest
+func pinnedImage(image string) bool {
+	if strings.Contains(image, "@") {
+		return true
+	}
+	name := image[strings.LastIndex(image, "/")+1:]
+	i := strings.LastIndex(name, ":")
+	return i >= 0 && name[i+1:] != "latest"
+}
@@ -0,0 +1,192 @@
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"io/ioutil"
+	"strconv"
+	"strings"
+
+	"github.com/jenkins-x/jx/pkg/config"
+	"github.com/jenkins-x/jx/pkg/history"
+	"github.com/jenkins-x/jx/pkg/junit"
+	"github.com/jenkins-x/jx/pkg/jx/cmd/templates"
+	"github.com/jenkins-x/jx/pkg/log"
+	"github.com/jenkins-x/jx/pkg/util"
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+	"gopkg.in/AlecAivazis/survey.v1/terminal"
+)
+
+var (
+	stepFlakyTestsLong = templates.LongDesc(`
+		Records the outcomes of the tests in the JUnit reports of a build in the test history of the repository and reports the flaky tests whose outcome keeps flipping between passing and failing.
+
+		If the tests failed and all the failed tests are flaky the retry command is run with the failed tests in $FLAKY_TESTS as a comma separated list of Class#method so that only the flaky tests are rerun.
+
+		The test history is stored in the history bucket of the team.
+`)
+
+	stepFlakyTestsExample = templates.Examples(`
+		# record the outcomes of the tests and rerun the failed tests if they are all flaky
+		jx step flaky-tests --reports 'target/surefire-reports/TEST-*.xml' --exit-code-file /workspace/.jx-test-exit-code --retry-command 'mvn test -Dtest=$FLAKY_TESTS'
+	`)
+)
+
+// StepFlakyTestsOptions contains the command line flags
+type StepFlakyTestsOptions struct {
+	StepOptions
+
+	Reports          []string
+	ExitCodeFile     string
+	RetryCommand     string
+	MinFlips         int
+	HistoryBucketURL string
+	Repository       string
+}
+
+// NewCmdStepFlakyTests creates the command
+func NewCmdStepFlakyTests(f Factory, in terminal.FileReader, out terminal.FileWriter, errOut io.Writer) *cobra.Command {
+	options := &StepFlakyTestsOptions{
+		StepOptions: StepOptions{
+			CommonOptions: CommonOptions{
+				Factory: f,
+				In:      in,
+				Out:     out,
+				Err:     errOut,
+			},
+		},
+	}
+
+	cmd := &cobra.Command{
+		Use:     "flaky-tests",
+		Short:   "Records the outcomes of the tests and reruns the failed tests if they are all flaky",
+		Long:    stepFlakyTestsLong,
+		Example: stepFlakyTestsExample,
+		Run: func(cmd *cobra.Command, args []string) {
+			options.Cmd = cmd
+			options.Args = args
+			err := options.Run()
+			CheckErr(err)
+		},
+	}
+	options.addCommonFlags(cmd)
+
+	cmd.Flags().StringArrayVarP(&options.Reports, "reports", "r", nil, "The glob patterns of the JUnit reports of the tests")
+	cmd.Flags().StringVarP(&options.ExitCodeFile, "exit-code-file", "", "", "The file containing the exit code of the tests. If not specified the tests failed if any test in the reports failed")
+	cmd.Flags().StringVarP(&options.RetryCommand, "retry-command", "", "", "The command which reruns the failed flaky tests passed in $FLAKY_TESTS")
+	cmd.Flags().IntVarP(&options.MinFlips, "min-flips", "", config.DefaultFlakyTestMinFlips, "The number of times the outcome of a test must flip in its recent runs for the test to be flaky")
+	cmd.Flags().StringVarP(&options.HistoryBucketURL, "history-bucket-url", "", "", "The URL of the bucket containing the test history. Defaults to the historyBucketUrl of the build defaults of the team")
+	cmd.Flags().StringVarP(&options.Repository, "repository", "", "", "The owner/repository the tests belong to. Defaults to the git repository of the current directory")
+	return cmd
+}
+
+// Run implements this command
+func (o *StepFlakyTestsOptions) Run() error {
+	if len(o.Reports) == 0 {
+		return util.MissingOption("reports")
+	}
+	exitCode, err := o.testExitCode()
+	if err != nil {
+		return err
+	}
+	testCases, err := junit.ParseReports(o.Reports)
+	if err != nil {
+		return err
+	}
+	if o.HistoryBucketURL == "" {
+		teamSettings, err := o.TeamSettings()
+		if err != nil {
+			return err
+		}
+		o.HistoryBucketURL = teamSettings.BuildDefaults.HistoryBucketURL
+	}
+	if o.Repository == "" {
+		gitInfo, err := o.FindGitInfo("")
+		if err != nil {
+			return err
+		}
+		o.Repository = gitInfo.Organisation + "/" + gitInfo.Name
+	}
+
+	testHistory := history.TestHistory{}
+	if o.HistoryBucketURL != "" {
+		testHistory, err = history.LoadTestHistory(o.HistoryBucketURL, o.Repository)
+		if err != nil {
+			return err
+		}
+	} else {
+		log.Warnf("There is no history bucket so the flaky tests cannot be detected across builds\n")
+	}
+	testHistory.Record(testCases)
+	if o.HistoryBucketURL != "" {
+		err = history.SaveTestHistory(o.HistoryBucketURL, o.Repository, testHistory)
+		if err != nil {
+			return errors.Wrapf(err, "failed to save the test history of %s", o.Repository)
+		}
+	}
+
+	failed := []string{}
+	flaky := []string{}
+	notFlaky := []string{}
+	for _, testCase := range testCases {
+		id := testCase.ID()
+		isFlaky := testHistory.IsFlaky(id, o.MinFlips)
+		if isFlaky && util.StringArrayIndex(flaky, id) < 0 {
+			flaky = append(flaky, id)
+		}
+		if testCase.Status == junit.StatusFailed && util.StringArrayIndex(failed, id) < 0 {
+			failed = append(failed, id)
+			if !isFlaky {
+				notFlaky = append(notFlaky, id)
+			}
+		}
+	}
+	if len(flaky) > 0 {
+		log.Warnf("Flaky tests: %s\n", strings.Join(flaky, ", "))
+	}
+	if exitCode < 0 {
+		exitCode = len(failed)
+	}
+	if exitCode == 0 {
+		return nil
+	}
+	if len(failed) == 0 {
+		return fmt.Errorf("The tests failed with exit code %d but no test failed in the reports", exitCode)
+	}
+	if len(notFlaky) > 0 || o.RetryCommand == "" {
+		return fmt.Errorf("%d tests failed: %s", len(failed), strings.Join(failed, ", "))
+	}
+
+	log.Infof("Retrying the failed flaky tests %s\n", util.ColorInfo(strings.Join(failed, ", ")))
+	cmd := util.Command{
+		Name: "sh",
+		Args: []string{"-c", o.RetryCommand},
+		Env: map[string]string{
+			"FLAKY_TESTS": strings.Join(failed, ","),
+		},
+		Out: o.Out,
+		Err: o.Err,
+	}
+	_, err = cmd.RunWithoutRetry()
+	if err != nil {
+		return errors.Wrap(err, "the retry of the failed flaky tests failed")
+	}
+	return nil
+}
+
+// testExitCode returns the exit code of the tests from the exit code file or -1 if there is none
+func (o *StepFlakyTestsOptions) testExitCode() (int, error) {
+	if o.ExitCodeFile == "" {
+		return -1, nil
+	}
+	data, err := ioutil.ReadFile(o.ExitCodeFile)
+	if err != nil {
+		return 0, errors.Wrapf(err, "failed to read the exit code of the tests")
+	}
+	exitCode, err := strconv.Atoi(strings.TrimSpace(string(data)))
+	if err != nil {
+		return 0, util.InvalidOptionf("exit-code-file", o.ExitCodeFile, "the file must contain the exit code of the tests")
+	}
+	return exitCode, nil
+}
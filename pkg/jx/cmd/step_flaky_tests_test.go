@@ -0,0 +1,77 @@
+package cmd_test
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/jenkins-x/jx/pkg/gits"
+	"github.com/jenkins-x/jx/pkg/helm"
+	"github.com/jenkins-x/jx/pkg/history"
+	"github.com/jenkins-x/jx/pkg/jx/cmd"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestStepFlakyTests(t *testing.T) {
+	t.Parallel()
+	testDir, err := ioutil.TempDir("", "test-step-flaky-tests")
+	require.NoError(t, err)
+	defer os.RemoveAll(testDir)
+	bucketURL := "file://" + filepath.ToSlash(filepath.Join(testDir, "bucket"))
+	reportFile := filepath.Join(testDir, "TEST-FooTest.xml")
+	exitCodeFile := filepath.Join(testDir, "exit-code")
+	retryFile := filepath.Join(testDir, "retried")
+
+	report := func(flakyPassed bool, brokenPassed bool) string {
+		testCase := func(name string, passed bool) string {
+			if passed {
+				return `<testcase classname="FooTest" name="` + name + `"/>`
+			}
+			return `<testcase classname="FooTest" name="` + name + `"><failure/></testcase>`
+		}
+		return "<testsuite>" + testCase("testFlaky", flakyPassed) + testCase("testBroken", brokenPassed) + testCase("testOk", true) + "</testsuite>"
+	}
+	run := func(flakyPassed bool, brokenPassed bool) error {
+		err := ioutil.WriteFile(reportFile, []byte(report(flakyPassed, brokenPassed)), 0644)
+		require.NoError(t, err)
+		exitCode := "0"
+		if !flakyPassed || !brokenPassed {
+			exitCode = "1"
+		}
+		err = ioutil.WriteFile(exitCodeFile, []byte(exitCode+"\n"), 0644)
+		require.NoError(t, err)
+		os.Remove(retryFile)
+
+		o := &cmd.StepFlakyTestsOptions{}
+		cmd.ConfigureTestOptionsWithResources(&o.CommonOptions, nil, nil, gits.NewGitCLI(), helm.NewHelmCLI("helm", helm.V2, testDir, true))
+		o.Reports = []string{filepath.Join(testDir, "TEST-*.xml")}
+		o.ExitCodeFile = exitCodeFile
+		o.RetryCommand = `echo "$FLAKY_TESTS" > ` + retryFile
+		o.MinFlips = 3
+		o.HistoryBucketURL = bucketURL
+		o.Repository = "myorg/myapp"
+		return o.Run()
+	}
+
+	// the flaky test flips 3 times
+	assert.NoError(t, run(true, true))
+	assert.Error(t, run(false, true), "the failed test is not flaky yet")
+	assert.NoError(t, run(true, true))
+
+	err = run(false, true)
+	assert.NoError(t, err, "the failed flaky test should have been retried")
+	data, err := ioutil.ReadFile(retryFile)
+	require.NoError(t, err)
+	assert.Equal(t, "FooTest#testFlaky\n", string(data))
+
+	err = run(false, false)
+	assert.Error(t, err, "a test which is not flaky failed")
+	assert.Contains(t, err.Error(), "FooTest#testBroken")
+
+	testHistory, err := history.LoadTestHistory(bucketURL, "myorg/myapp")
+	require.NoError(t, err)
+	assert.Equal(t, []bool{true, false, true, false, false}, testHistory["FooTest#testFlaky"])
+	assert.Equal(t, []string{"FooTest#testFlaky"}, testHistory.FlakyTests(3))
+}
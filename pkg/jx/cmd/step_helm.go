@@ -60,6 +60,7 @@ func NewCmdStepHelm(f Factory, in terminal.FileReader, out terminal.FileWriter,
 	cmd.AddCommand(NewCmdStepHelmEnv(f, in, out, errOut))
 	cmd.AddCommand(NewCmdStepHelmInstall(f, in, out, errOut))
 	cmd.AddCommand(NewCmdStepHelmRelease(f, in, out, errOut))
+	cmd.AddCommand(NewCmdStepHelmRollback(f, in, out, errOut))
 	cmd.AddCommand(NewCmdStepHelmVersion(f, in, out, errOut))
 	return cmd
 }
@@ -0,0 +1,122 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+
+	"github.com/jenkins-x/jx/pkg/jx/cmd/templates"
+	"github.com/jenkins-x/jx/pkg/log"
+	"github.com/jenkins-x/jx/pkg/util"
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+	"gopkg.in/AlecAivazis/survey.v1/terminal"
+)
+
+// HelmFn runs helm with the given arguments returning its output
+type HelmFn func(args ...string) (string, error)
+
+// StepHelmRollbackOptions contains the command line flags
+type StepHelmRollbackOptions struct {
+	StepHelmOptions
+
+	Release string
+
+	// testing
+	FakeHelm HelmFn
+}
+
+// helmRevision a revision of a helm release as output by 'helm history --output json'
+type helmRevision struct {
+	Revision int    `json:"revision"`
+	Status   string `json:"status"`
+}
+
+var (
+	stepHelmRollbackLong = templates.LongDesc(`
+		Rolls back a helm release to its previous revision.
+
+		Used by the generated builds to roll back a deployment whose post deploy steps failed.
+`)
+
+	stepHelmRollbackExample = templates.Examples(`
+		# roll back the myapp release to its previous revision
+		jx step helm rollback --release myapp
+`)
+)
+
+// NewCmdStepHelmRollback creates the command
+func NewCmdStepHelmRollback(f Factory, in terminal.FileReader, out terminal.FileWriter, errOut io.Writer) *cobra.Command {
+	options := StepHelmRollbackOptions{
+		StepHelmOptions: StepHelmOptions{
+			StepOptions: StepOptions{
+				CommonOptions: CommonOptions{
+					Factory: f,
+					In:      in,
+					Out:     out,
+					Err:     errOut,
+				},
+			},
+		},
+	}
+	cmd := &cobra.Command{
+		Use:     "rollback",
+		Short:   "Rolls back a helm release to its previous revision",
+		Long:    stepHelmRollbackLong,
+		Example: stepHelmRollbackExample,
+		Run: func(cmd *cobra.Command, args []string) {
+			options.Cmd = cmd
+			options.Args = args
+			err := options.Run()
+			CheckErr(err)
+		},
+	}
+	options.addCommonFlags(cmd)
+
+	cmd.Flags().StringVarP(&options.Release, "release", "r", "", "The name of the helm release to roll back")
+	return cmd
+}
+
+// Run implements this command
+func (o *StepHelmRollbackOptions) Run() error {
+	if o.Release == "" {
+		return util.MissingOption("release")
+	}
+	text, err := o.helm("history", o.Release, "--max", "10", "--output", "json")
+	if err != nil {
+		return errors.Wrapf(err, "failed to find the history of release %s", o.Release)
+	}
+	revisions := []helmRevision{}
+	err = json.Unmarshal([]byte(text), &revisions)
+	if err != nil {
+		return errors.Wrapf(err, "failed to parse the history of release %s", o.Release)
+	}
+	sort.Slice(revisions, func(i, j int) bool {
+		return revisions[i].Revision > revisions[j].Revision
+	})
+	previous := 0
+	for i, r := range revisions {
+		if i > 0 && (r.Status == "SUPERSEDED" || r.Status == "DEPLOYED") {
+			previous = r.Revision
+			break
+		}
+	}
+	if previous == 0 {
+		return fmt.Errorf("release %s has no previous revision to roll back to", o.Release)
+	}
+	_, err = o.helm("rollback", o.Release, strconv.Itoa(previous))
+	if err != nil {
+		return errors.Wrapf(err, "failed to roll back release %s", o.Release)
+	}
+	log.Infof("Rolled back release %s to revision %s\n", util.ColorInfo(o.Release), util.ColorInfo(strconv.Itoa(previous)))
+	return nil
+}
+
+func (o *StepHelmRollbackOptions) helm(args ...string) (string, error) {
+	if o.FakeHelm != nil {
+		return o.FakeHelm(args...)
+	}
+	return o.getCommandOutput("", "helm", args...)
+}
@@ -0,0 +1,34 @@
+package cmd_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/jenkins-x/jx/pkg/jx/cmd"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestStepHelmRollback(t *testing.T) {
+	t.Parallel()
+	history := `[{"revision":3,"status":"SUPERSEDED"},{"revision":5,"status":"DEPLOYED"},{"revision":4,"status":"FAILED"}]`
+	commands := []string{}
+	o := &cmd.StepHelmRollbackOptions{
+		Release: "myapp",
+		FakeHelm: func(args ...string) (string, error) {
+			commands = append(commands, strings.Join(args, " "))
+			if args[0] == "history" {
+				return history, nil
+			}
+			return "", nil
+		},
+	}
+	err := o.Run()
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"history myapp --max 10 --output json", "rollback myapp 3"}, commands)
+
+	commands = []string{}
+	history = `[{"revision":1,"status":"DEPLOYED"}]`
+	err = o.Run()
+	assert.Error(t, err)
+	assert.Equal(t, []string{"history myapp --max 10 --output json"}, commands)
+}
@@ -0,0 +1,117 @@
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/jenkins-x/jx/pkg/jx/cmd/templates"
+	"github.com/jenkins-x/jx/pkg/kube"
+	"github.com/jenkins-x/jx/pkg/log"
+	"github.com/jenkins-x/jx/pkg/util"
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+	"gopkg.in/AlecAivazis/survey.v1/terminal"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+var (
+	stepIsolateLong = templates.LongDesc(`
+		Creates or deletes the namespace in which a single build deploys and tests so that builds cannot interfere with each other.
+
+		The namespace is labelled so that it is deleted by 'jx gc namespaces' once it expires if the build fails before deleting it.
+`)
+
+	stepIsolateExample = templates.Examples(`
+		# create the namespace of a build which expires in 2 hours
+		jx step isolate --namespace build-myapp-pr-12-3 --ttl 2h
+
+		# delete the namespace of a build
+		jx step isolate --namespace build-myapp-pr-12-3 --delete
+	`)
+)
+
+// StepIsolateOptions contains the command line flags
+type StepIsolateOptions struct {
+	StepOptions
+
+	Namespace string
+	TTL       time.Duration
+	Delete    bool
+}
+
+// NewCmdStepIsolate creates the command
+func NewCmdStepIsolate(f Factory, in terminal.FileReader, out terminal.FileWriter, errOut io.Writer) *cobra.Command {
+	options := &StepIsolateOptions{
+		StepOptions: StepOptions{
+			CommonOptions: CommonOptions{
+				Factory: f,
+				In:      in,
+				Out:     out,
+				Err:     errOut,
+			},
+		},
+	}
+
+	cmd := &cobra.Command{
+		Use:     "isolate",
+		Short:   "Creates or deletes the namespace of a single build",
+		Long:    stepIsolateLong,
+		Example: stepIsolateExample,
+		Run: func(cmd *cobra.Command, args []string) {
+			options.Cmd = cmd
+			options.Args = args
+			err := options.Run()
+			CheckErr(err)
+		},
+	}
+	options.addCommonFlags(cmd)
+
+	cmd.Flags().StringVarP(&options.Namespace, "namespace", "n", "", "The name of the namespace of the build")
+	cmd.Flags().DurationVarP(&options.TTL, "ttl", "", defaultIsolatedTTL, "The duration after which the namespace is garbage collected if it was not deleted")
+	cmd.Flags().BoolVarP(&options.Delete, "delete", "", false, "Deletes the namespace instead of creating it")
+	return cmd
+}
+
+// Run implements this command
+func (o *StepIsolateOptions) Run() error {
+	if o.Namespace == "" {
+		return util.MissingOption("namespace")
+	}
+	kubeClient, _, err := o.KubeClient()
+	if err != nil {
+		return err
+	}
+	if o.Delete {
+		ns, err := kubeClient.CoreV1().Namespaces().Get(o.Namespace, metav1.GetOptions{})
+		if err != nil {
+			if apierrors.IsNotFound(err) {
+				return nil
+			}
+			return errors.Wrapf(err, "failed to find namespace %s", o.Namespace)
+		}
+		// only delete namespaces created for a build so that a typo cannot delete an environment
+		if ns.Labels[kube.LabelIsolated] != "true" {
+			return fmt.Errorf("The namespace %s was not created for a build as it does not have the label %s", o.Namespace, kube.LabelIsolated)
+		}
+		err = kubeClient.CoreV1().Namespaces().Delete(o.Namespace, &metav1.DeleteOptions{})
+		if err != nil && !apierrors.IsNotFound(err) {
+			return errors.Wrapf(err, "failed to delete namespace %s", o.Namespace)
+		}
+		log.Infof("Deleted the namespace %s of the build\n", util.ColorInfo(o.Namespace))
+		return nil
+	}
+	labels := map[string]string{
+		kube.LabelIsolated: "true",
+	}
+	annotations := map[string]string{
+		kube.AnnotationExpires: time.Now().Add(o.TTL).UTC().Format(time.RFC3339),
+	}
+	err = kube.EnsureNamespaceCreated(kubeClient, o.Namespace, labels, annotations)
+	if err != nil {
+		return err
+	}
+	log.Infof("Created the namespace %s of the build\n", util.ColorInfo(o.Namespace))
+	return nil
+}
@@ -0,0 +1,69 @@
+package cmd_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/jenkins-x/jx/pkg/gits"
+	"github.com/jenkins-x/jx/pkg/helm"
+	"github.com/jenkins-x/jx/pkg/jx/cmd"
+	"github.com/jenkins-x/jx/pkg/kube"
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+)
+
+func TestStepIsolate(t *testing.T) {
+	t.Parallel()
+	staging := &corev1.Namespace{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: "jx-staging",
+		},
+	}
+	expired := &corev1.Namespace{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: "build-myapp-pr-1-pullrequest-1",
+			Labels: map[string]string{
+				kube.LabelIsolated: "true",
+			},
+			Annotations: map[string]string{
+				kube.AnnotationExpires: time.Now().Add(-time.Minute).UTC().Format(time.RFC3339),
+			},
+		},
+	}
+	o := &cmd.StepIsolateOptions{
+		Namespace: "build-myapp-pr-1-pullrequest-2",
+		TTL:       time.Hour,
+	}
+	cmd.ConfigureTestOptionsWithResources(&o.CommonOptions, []runtime.Object{staging, expired}, nil, gits.NewGitCLI(), helm.NewHelmCLI("helm", helm.V2, "", true))
+	kubeClient, _, err := o.KubeClient()
+	assert.NoError(t, err)
+
+	err = o.Run()
+	assert.NoError(t, err)
+	ns, err := kubeClient.CoreV1().Namespaces().Get(o.Namespace, metav1.GetOptions{})
+	assert.NoError(t, err)
+	assert.Equal(t, "true", ns.Labels[kube.LabelIsolated])
+	assert.NotEmpty(t, ns.Annotations[kube.AnnotationExpires])
+
+	gc := &cmd.GCNamespacesOptions{
+		CommonOptions: o.CommonOptions,
+	}
+	err = gc.Run()
+	assert.NoError(t, err)
+	_, err = kubeClient.CoreV1().Namespaces().Get(expired.Name, metav1.GetOptions{})
+	assert.Error(t, err, "should have deleted the expired namespace")
+	_, err = kubeClient.CoreV1().Namespaces().Get(o.Namespace, metav1.GetOptions{})
+	assert.NoError(t, err, "should not have deleted the namespace which has not expired")
+
+	o.Delete = true
+	err = o.Run()
+	assert.NoError(t, err)
+	_, err = kubeClient.CoreV1().Namespaces().Get(o.Namespace, metav1.GetOptions{})
+	assert.Error(t, err, "should have deleted the namespace")
+
+	o.Namespace = staging.Name
+	err = o.Run()
+	assert.Error(t, err, "should not delete a namespace which was not created for a build")
+}
@@ -0,0 +1,48 @@
+package cmd
+
+import (
+	"io"
+
+	"github.com/spf13/cobra"
+	"gopkg.in/AlecAivazis/survey.v1/terminal"
+)
+
+// StepJiraOptions contains the command line flags
+type StepJiraOptions struct {
+	StepOptions
+}
+
+// NewCmdStepSonar creates the command
+func NewCmdStepJira(f Factory, in terminal.FileReader, out terminal.FileWriter, errOut io.Writer) *cobra.Command {
+	options := &StepJiraOptions{
+		StepOptions: StepOptions{
+			CommonOptions: CommonOptions{
+				Factory: f,
+				In:      in,
+				Out:     out,
+				Err:     errOut,
+			},
+		},
+	}
+
+	cmd := &cobra.Command{
+		Use:   "jira",
+		Short: "JIRA pipeline steps",
+		Run: func(cmd *cobra.Command, args []string) {
+			options.Cmd = cmd
+			options.Args = args
+			err := options.Run()
+			CheckErr(err)
+		},
+	}
+
+	cmd.AddCommand(NewCmdStepJiraRelease(f, in, out, errOut))
+	options.addCommonFlags(cmd)
+
+	return cmd
+}
+
+// Run implements this command
+func (o *StepJiraOptions) Run() error {
+	return o.Cmd.Help()
+}
@@ -0,0 +1,208 @@
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/jenkins-x/jx/pkg/config"
+	"github.com/jenkins-x/jx/pkg/issues"
+	"github.com/jenkins-x/jx/pkg/jx/cmd/templates"
+	"github.com/jenkins-x/jx/pkg/log"
+	"github.com/jenkins-x/jx/pkg/util"
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+	"gopkg.in/AlecAivazis/survey.v1/terminal"
+)
+
+const (
+	jiraReleaseStepName = "jira-release"
+)
+
+// defaultJiraReleaseStatuses the statuses the issues of a release are transitioned to in order of preference
+var defaultJiraReleaseStatuses = []string{"Released", "Done"}
+
+var (
+	stepJiraReleaseLong = templates.LongDesc(`
+		Transitions the JIRA issues referenced by the commits of a release to Released or Done and adds the version of the release to their fix versions.
+
+		The issues are found in the commit messages since the previous tag. The JIRA server is the issueTracker of jenkins-x.yml whose release section configures the statuses the issues are transitioned to. The version is created in the project of the issues if it does not exist yet.
+
+		'jx step create build' adds this step to the end of the release builds when the release section of the issueTracker is configured.
+`)
+
+	stepJiraReleaseExample = templates.Examples(`
+		# transitions the issues of the release in the VERSION file
+		jx step jira release
+
+		# transitions the given issues to Closed and adds version 1.2.3 to their fix versions
+		jx step jira release --version 1.2.3 --issue JX-12 --issue JX-34 --status Closed
+		`)
+)
+
+// StepJiraReleaseOptions contains the command line flags
+type StepJiraReleaseOptions struct {
+	StepJiraOptions
+
+	Dir              string
+	Version          string
+	PreviousRevision string
+	Issues           []string
+	Statuses         []string
+	NoFixVersion     bool
+	FailOnError      bool
+}
+
+// NewCmdStepJiraRelease creates the command
+func NewCmdStepJiraRelease(f Factory, in terminal.FileReader, out terminal.FileWriter, errOut io.Writer) *cobra.Command {
+	options := &StepJiraReleaseOptions{
+		StepJiraOptions: StepJiraOptions{
+			StepOptions: StepOptions{
+				CommonOptions: CommonOptions{
+					Factory: f,
+					In:      in,
+					Out:     out,
+					Err:     errOut,
+				},
+			},
+		},
+	}
+
+	cmd := &cobra.Command{
+		Use:     "release",
+		Short:   "Transitions the JIRA issues of a release and adds the version to their fix versions",
+		Long:    stepJiraReleaseLong,
+		Example: stepJiraReleaseExample,
+		Run: func(cmd *cobra.Command, args []string) {
+			options.Cmd = cmd
+			options.Args = args
+			err := options.Run()
+			CheckErr(err)
+		},
+	}
+	options.addCommonFlags(cmd)
+
+	cmd.Flags().StringVarP(&options.Dir, "dir", "d", "", "The directory of the project. Defaults to the current directory")
+	cmd.Flags().StringVarP(&options.Version, "version", "v", "", "The version of the release. Defaults to $VERSION or the VERSION file")
+	cmd.Flags().StringVarP(&options.PreviousRevision, "previous-rev", "p", "", "The previous tag revision. The issues are found in the commit messages after it")
+	cmd.Flags().StringArrayVarP(&options.Issues, "issue", "i", nil, "The keys of the issues of the release. Defaults to the issues referenced by the commit messages since the previous tag")
+	cmd.Flags().StringArrayVarP(&options.Statuses, "status", "s", nil, "The statuses the issues are transitioned to in order of preference. Defaults to the statuses of the release section of the issueTracker or "+strings.Join(defaultJiraReleaseStatuses, ", "))
+	cmd.Flags().BoolVarP(&options.NoFixVersion, "no-fix-version", "", false, "Disables adding the version to the fix versions of the issues")
+	cmd.Flags().BoolVarP(&options.FailOnError, "fail-on-error", "", false, "Fails if any of the issues could not be transitioned or updated")
+	return cmd
+}
+
+// Run implements this command
+func (o *StepJiraReleaseOptions) Run() error {
+	dir := o.Dir
+	if dir == "" {
+		dir = "."
+	}
+	pc, _, err := config.LoadProjectConfig(dir)
+	if err != nil {
+		return err
+	}
+	tracker := pc.IssueTracker
+	if tracker == nil || tracker.Kind != issues.Jira {
+		return util.WithExitCode(fmt.Errorf("No JIRA issueTracker is configured in %s", config.ProjectConfigFileName), util.ExitCodeValidation)
+	}
+	version, err := o.releaseVersion(dir)
+	if err != nil {
+		return err
+	}
+	statuses := o.Statuses
+	if len(statuses) == 0 && tracker.Release != nil {
+		statuses = tracker.Release.Statuses
+	}
+	if len(statuses) == 0 {
+		statuses = defaultJiraReleaseStatuses
+	}
+	noFixVersion := o.NoFixVersion || (tracker.Release != nil && tracker.Release.NoFixVersion)
+
+	keys := o.Issues
+	if len(keys) == 0 {
+		keys, err = o.releaseIssueKeys(dir)
+		if err != nil {
+			return errors.Wrap(err, "failed to find the issues of the release")
+		}
+	}
+	if len(keys) == 0 {
+		log.Infof("No JIRA issues are referenced by the commits of release %s\n", util.ColorInfo(version))
+		return nil
+	}
+
+	provider, err := o.createIssueProvider(dir)
+	if err != nil {
+		return util.WithExitCode(err, util.ExitCodeUnreachable)
+	}
+	jiraService, ok := provider.(*issues.JiraService)
+	if !ok {
+		return util.WithExitCode(fmt.Errorf("The issueTracker of %s is not a JIRA server", config.ProjectConfigFileName), util.ExitCodeValidation)
+	}
+	failed := []string{}
+	for _, key := range keys {
+		status, err := jiraService.TransitionIssue(key, statuses)
+		if err != nil {
+			log.Warnf("%s\n", err)
+			failed = append(failed, key)
+			continue
+		}
+		log.Infof("Issue %s is %s\n", util.ColorInfo(jiraService.IssueURL(key)), util.ColorInfo(status))
+		if noFixVersion {
+			continue
+		}
+		err = jiraService.AddFixVersion(key, version)
+		if err != nil {
+			log.Warnf("%s\n", err)
+			failed = append(failed, key)
+			continue
+		}
+		log.Infof("Added fix version %s to issue %s\n", util.ColorInfo(version), util.ColorInfo(key))
+	}
+	if len(failed) > 0 && o.FailOnError {
+		return fmt.Errorf("failed to release the issues %s", strings.Join(failed, ", "))
+	}
+	return nil
+}
+
+// releaseVersion returns the version of the release from the --version flag, $VERSION or the VERSION file
+func (o *StepJiraReleaseOptions) releaseVersion(dir string) (string, error) {
+	version := o.Version
+	if version == "" {
+		version = os.Getenv("VERSION")
+	}
+	if version == "" {
+		data, err := ioutil.ReadFile(filepath.Join(dir, defaultVersionFile))
+		if err == nil {
+			version = strings.TrimSpace(string(data))
+		}
+	}
+	if version == "" {
+		return "", util.MissingOption("version")
+	}
+	return version, nil
+}
+
+// releaseIssueKeys returns the keys of the JIRA issues referenced by the commit messages since the previous tag or of
+// the head commit if there is no previous tag
+func (o *StepJiraReleaseOptions) releaseIssueKeys(dir string) ([]string, error) {
+	previousRev := o.PreviousRevision
+	if previousRev == "" {
+		// ignore errors as there may be no previous tag
+		previousRev, _ = o.Git().GetPreviousGitTagSHA(dir)
+	}
+	var messages string
+	var err error
+	if previousRev != "" {
+		messages, err = (&util.Command{Dir: dir, Name: "git", Args: []string{"log", "--format=%B", previousRev + "..HEAD"}}).RunWithoutRetry()
+	} else {
+		messages, err = o.Git().GetLatestCommitMessage(dir)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return issueKeys(JIRAIssueRegex, messages), nil
+}
@@ -0,0 +1,205 @@
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/jenkins-x/jx/pkg/jx/cmd/templates"
+	"github.com/jenkins-x/jx/pkg/kube"
+	"github.com/jenkins-x/jx/pkg/log"
+	"github.com/jenkins-x/jx/pkg/util"
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+	"gopkg.in/AlecAivazis/survey.v1/terminal"
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+)
+
+const (
+	// labelMigration the label on the jobs which migrate the database of an application
+	labelMigration = "jenkins.io/migration"
+)
+
+var (
+	stepMigrateLong = templates.LongDesc(`
+		Runs a database migration of an application as a Job in the namespace of an environment and waits for it to complete.
+
+		Only one migration of an application runs at a time in a namespace; a migration waits for any previous migration to complete before it starts. The step fails if the migration fails so the application is not deployed.
+`)
+
+	stepMigrateExample = templates.Examples(`
+		# run the flyway migrations of myapp in the staging environment
+		jx step migrate --app myapp --namespace jx-staging --image myorg/myapp-migrations:1.0.1 -- flyway migrate
+	`)
+)
+
+// StepMigrateOptions contains the command line flags
+type StepMigrateOptions struct {
+	StepOptions
+
+	App          string
+	Name         string
+	Namespace    string
+	Image        string
+	Timeout      string
+	PollDuration time.Duration
+}
+
+// NewCmdStepMigrate creates the command
+func NewCmdStepMigrate(f Factory, in terminal.FileReader, out terminal.FileWriter, errOut io.Writer) *cobra.Command {
+	options := &StepMigrateOptions{
+		StepOptions: StepOptions{
+			CommonOptions: CommonOptions{
+				Factory: f,
+				In:      in,
+				Out:     out,
+				Err:     errOut,
+			},
+		},
+	}
+
+	cmd := &cobra.Command{
+		Use:     "migrate -- command [args...]",
+		Short:   "Runs a database migration of an application as a Job and waits for it to complete",
+		Long:    stepMigrateLong,
+		Example: stepMigrateExample,
+		Run: func(cmd *cobra.Command, args []string) {
+			options.Cmd = cmd
+			options.Args = args
+			err := options.Run()
+			CheckErr(err)
+		},
+	}
+	options.addCommonFlags(cmd)
+
+	cmd.Flags().StringVarP(&options.App, "app", "a", "", "The name of the application whose database is migrated")
+	cmd.Flags().StringVarP(&options.Name, "name", "", "migrate", "The name of the migration")
+	cmd.Flags().StringVarP(&options.Namespace, "namespace", "n", "", "The namespace of the environment to migrate. Defaults to the current namespace")
+	cmd.Flags().StringVarP(&options.Image, "image", "i", "", "The image which runs the migration")
+	cmd.Flags().StringVarP(&options.Timeout, "timeout", "t", "30m", "The duration to wait for the migration and any previous migration to complete")
+	cmd.Flags().DurationVarP(&options.PollDuration, "poll", "", 5*time.Second, "The duration between checks of the migration status")
+	return cmd
+}
+
+// Run implements this command
+func (o *StepMigrateOptions) Run() error {
+	if o.App == "" {
+		return util.MissingOption("app")
+	}
+	if o.Image == "" {
+		return util.MissingOption("image")
+	}
+	if len(o.Args) == 0 {
+		return fmt.Errorf("Missing argument: the command which runs the migration")
+	}
+	timeout, err := time.ParseDuration(o.Timeout)
+	if err != nil {
+		return util.InvalidOptionError("timeout", o.Timeout, err)
+	}
+	kubeClient, ns, err := o.KubeClient()
+	if err != nil {
+		return err
+	}
+	if o.Namespace != "" {
+		ns = o.Namespace
+	}
+	end := time.Now().Add(timeout)
+
+	// the job is named after the application so only one of its migrations runs at a time in the namespace
+	jobName := kube.ToValidName(o.App + "-migration")
+	err = o.waitForPreviousMigration(kubeClient, ns, jobName, end)
+	if err != nil {
+		return err
+	}
+	_, err = kubeClient.BatchV1().Jobs(ns).Create(o.migrationJob(jobName, ns))
+	if err != nil {
+		if apierrors.IsAlreadyExists(err) {
+			return fmt.Errorf("another migration of %s started in namespace %s", o.App, ns)
+		}
+		return errors.Wrapf(err, "failed to create the migration job %s", jobName)
+	}
+	log.Infof("Running migration %s of %s in namespace %s\n", util.ColorInfo(o.Name), util.ColorInfo(o.App), util.ColorInfo(ns))
+
+	for {
+		job, err := kubeClient.BatchV1().Jobs(ns).Get(jobName, metav1.GetOptions{})
+		if err != nil {
+			return errors.Wrapf(err, "failed to get the migration job %s", jobName)
+		}
+		if job.Status.Succeeded > 0 {
+			log.Infof("Migration %s of %s succeeded\n", util.ColorInfo(o.Name), util.ColorInfo(o.App))
+			return nil
+		}
+		if job.Status.Failed > 0 {
+			return fmt.Errorf("migration %s of %s failed; see the logs of job %s in namespace %s", o.Name, o.App, jobName, ns)
+		}
+		if time.Now().After(end) {
+			return fmt.Errorf("migration %s of %s did not complete within %s", o.Name, o.App, o.Timeout)
+		}
+		time.Sleep(o.PollDuration)
+	}
+}
+
+// waitForPreviousMigration waits for any running migration of the application to complete then deletes its job
+func (o *StepMigrateOptions) waitForPreviousMigration(kubeClient kubernetes.Interface, ns string, jobName string, end time.Time) error {
+	jobs := kubeClient.BatchV1().Jobs(ns)
+	for {
+		job, err := jobs.Get(jobName, metav1.GetOptions{})
+		if err != nil {
+			if apierrors.IsNotFound(err) {
+				return nil
+			}
+			return errors.Wrapf(err, "failed to get the migration job %s", jobName)
+		}
+		if job.Status.Succeeded > 0 || job.Status.Failed > 0 {
+			policy := metav1.DeletePropagationBackground
+			err = jobs.Delete(jobName, &metav1.DeleteOptions{PropagationPolicy: &policy})
+			if err != nil && !apierrors.IsNotFound(err) {
+				return errors.Wrapf(err, "failed to delete the previous migration job %s", jobName)
+			}
+			return nil
+		}
+		if time.Now().After(end) {
+			return fmt.Errorf("the previous migration of %s did not complete within %s", o.App, o.Timeout)
+		}
+		log.Infof("Waiting for the previous migration of %s in namespace %s to complete\n", util.ColorInfo(o.App), util.ColorInfo(ns))
+		time.Sleep(o.PollDuration)
+	}
+}
+
+// migrationJob returns the job which runs the migration once without retrying it
+func (o *StepMigrateOptions) migrationJob(name string, ns string) *batchv1.Job {
+	backoffLimit := int32(0)
+	labels := map[string]string{
+		"app":          o.App,
+		labelMigration: o.Name,
+	}
+	return &batchv1.Job{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      name,
+			Namespace: ns,
+			Labels:    labels,
+		},
+		Spec: batchv1.JobSpec{
+			BackoffLimit: &backoffLimit,
+			Template: corev1.PodTemplateSpec{
+				ObjectMeta: metav1.ObjectMeta{
+					Labels: labels,
+				},
+				Spec: corev1.PodSpec{
+					RestartPolicy: corev1.RestartPolicyNever,
+					Containers: []corev1.Container{
+						{
+							Name:    "migrate",
+							Image:   o.Image,
+							Command: o.Args,
+						},
+					},
+				},
+			},
+		},
+	}
+}
@@ -0,0 +1,76 @@
+package cmd_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/jenkins-x/jx/pkg/gits"
+	"github.com/jenkins-x/jx/pkg/helm"
+	"github.com/jenkins-x/jx/pkg/jx/cmd"
+	"github.com/stretchr/testify/assert"
+	batchv1 "k8s.io/api/batch/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/kubernetes/fake"
+	k8sTesting "k8s.io/client-go/testing"
+)
+
+func TestStepMigrate(t *testing.T) {
+	t.Parallel()
+	previous := &batchv1.Job{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "myapp-migration",
+			Namespace: "jx-staging",
+		},
+		Status: batchv1.JobStatus{
+			Succeeded: 1,
+		},
+	}
+	o := &cmd.StepMigrateOptions{
+		App:          "myapp",
+		Name:         "flyway",
+		Namespace:    "jx-staging",
+		Image:        "myorg/myapp:1.0.1",
+		Timeout:      "10s",
+		PollDuration: time.Millisecond,
+	}
+	o.Args = []string{"flyway", "migrate"}
+	cmd.ConfigureTestOptionsWithResources(&o.CommonOptions, []runtime.Object{previous}, nil, gits.NewGitCLI(), helm.NewHelmCLI("helm", helm.V2, "", true))
+	kubeClient, _, err := o.KubeClient()
+	assert.NoError(t, err)
+
+	// the fake client does not run jobs so the created job completes immediately
+	var created *batchv1.Job
+	failed := false
+	fakeClient := kubeClient.(*fake.Clientset)
+	fakeClient.PrependReactor("create", "jobs", func(action k8sTesting.Action) (bool, runtime.Object, error) {
+		created = action.(k8sTesting.CreateAction).GetObject().(*batchv1.Job).DeepCopy()
+		if failed {
+			created.Status.Failed = 1
+		} else {
+			created.Status.Succeeded = 1
+		}
+		return true, created, nil
+	})
+	fakeClient.PrependReactor("get", "jobs", func(action k8sTesting.Action) (bool, runtime.Object, error) {
+		return created != nil, created, nil
+	})
+	fakeClient.PrependReactor("delete", "jobs", func(action k8sTesting.Action) (bool, runtime.Object, error) {
+		created = nil
+		return false, nil, nil
+	})
+
+	err = o.Run()
+	assert.NoError(t, err)
+	job := created
+	containers := job.Spec.Template.Spec.Containers
+	if assert.Equal(t, 1, len(containers)) {
+		assert.Equal(t, "myorg/myapp:1.0.1", containers[0].Image)
+		assert.Equal(t, []string{"flyway", "migrate"}, containers[0].Command)
+	}
+	assert.Equal(t, "flyway", job.Labels["jenkins.io/migration"])
+
+	failed = true
+	err = o.Run()
+	assert.Error(t, err)
+}
@@ -40,6 +40,7 @@ func NewCmdStepPR(f Factory, in terminal.FileReader, out terminal.FileWriter, er
 	}
 
 	cmd.AddCommand(NewCmdStepPRComment(f, in, out, errOut))
+	cmd.AddCommand(NewCmdStepPRSummary(f, in, out, errOut))
 	options.addCommonFlags(cmd)
 
 	return cmd
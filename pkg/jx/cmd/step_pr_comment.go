@@ -2,6 +2,9 @@ package cmd
 
 import (
 	"io"
+	"io/ioutil"
+
+	"github.com/pkg/errors"
 
 	"github.com/spf13/cobra"
 	"gopkg.in/AlecAivazis/survey.v1/terminal"
@@ -22,6 +25,7 @@ type StepPRCommentOptions struct {
 
 type StepPRCommentFlags struct {
 	Comment    string
+	File       string
 	URL        string
 	Owner      string
 	Repository string
@@ -55,6 +59,7 @@ func NewCmdStepPRComment(f Factory, in terminal.FileReader, out terminal.FileWri
 	}
 
 	cmd.Flags().StringVarP(&options.Flags.Comment, "comment", "c", "", "comment to add to the Pull Request")
+	cmd.Flags().StringVarP(&options.Flags.File, "file", "f", "", "file containing the comment to add to the Pull Request")
 	cmd.Flags().StringVarP(&options.Flags.Owner, "owner", "o", "", "Git organisation / owner")
 	cmd.Flags().StringVarP(&options.Flags.Repository, "repository", "r", "", "Git repository")
 	cmd.Flags().StringVarP(&options.Flags.PR, "pull-request", "p", "", "Git Pull Request number")
@@ -75,6 +80,13 @@ func (o *StepPRCommentOptions) Run() error {
 	if o.Flags.Repository == "" {
 		return fmt.Errorf("no Git repository provided")
 	}
+	if o.Flags.File != "" {
+		data, err := ioutil.ReadFile(o.Flags.File)
+		if err != nil {
+			return errors.Wrapf(err, "failed to read the comment file %s", o.Flags.File)
+		}
+		o.Flags.Comment = string(data)
+	}
 	if o.Flags.Comment == "" {
 		return fmt.Errorf("no comment provided")
 	}
@@ -0,0 +1,272 @@
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/jenkins-x/jx/pkg/apis/jenkins.io/v1"
+	"github.com/jenkins-x/jx/pkg/gits"
+	"github.com/jenkins-x/jx/pkg/jx/cmd/templates"
+	"github.com/jenkins-x/jx/pkg/kube"
+	"github.com/jenkins-x/jx/pkg/log"
+	"github.com/jenkins-x/jx/pkg/util"
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+	"gopkg.in/AlecAivazis/survey.v1/terminal"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// prSummaryCommentMarker the hidden marker of the pull request comment containing the summary of the pipeline
+const prSummaryCommentMarker = "jx-pipeline-summary"
+
+var (
+	stepPRSummaryLong = templates.LongDesc(`
+		Adds a comment to a Pull Request summarising the results of its pipeline: the status and duration of each stage, the image built, the URL of the preview environment, the code coverage and the findings of the scans.
+
+		The Pull Request only ever has a single summary comment which is updated in place each time this step runs so that rebuilds do not flood the Pull Request with comments.
+
+		The coverage and findings default to the facts recorded on the PipelineActivity of the build.
+`)
+
+	stepPRSummaryExample = templates.Examples(`
+		# update the summary comment of the Pull Request of the current pipeline
+		jx step pr summary
+
+		# include the image built and the coverage measured by the pipeline
+		jx step pr summary --image $DOCKER_REGISTRY/$ORG/$APP_NAME:$VERSION --coverage 83%
+	`)
+)
+
+// StepPRSummaryOptions contains the command line flags
+type StepPRSummaryOptions struct {
+	StepPROptions
+
+	Pipeline    string
+	Build       string
+	Owner       string
+	Repository  string
+	PullRequest string
+	Image       string
+	PreviewURL  string
+	Coverage    string
+	Findings    string
+	GitProvider gits.GitProvider
+}
+
+// NewCmdStepPRSummary creates the command
+func NewCmdStepPRSummary(f Factory, in terminal.FileReader, out terminal.FileWriter, errOut io.Writer) *cobra.Command {
+	options := &StepPRSummaryOptions{
+		StepPROptions: StepPROptions{
+			StepOptions: StepOptions{
+				CommonOptions: CommonOptions{
+					Factory: f,
+					In:      in,
+					Out:     out,
+					Err:     errOut,
+				},
+			},
+		},
+	}
+
+	cmd := &cobra.Command{
+		Use:     "summary",
+		Short:   "Adds or updates a comment on a Pull Request summarising the results of its pipeline",
+		Long:    stepPRSummaryLong,
+		Example: stepPRSummaryExample,
+		Run: func(cmd *cobra.Command, args []string) {
+			options.Cmd = cmd
+			options.Args = args
+			err := options.Run()
+			CheckErr(err)
+		},
+	}
+	options.addCommonFlags(cmd)
+
+	cmd.Flags().StringVarP(&options.Pipeline, "pipeline", "", "", "The pipeline name. Defaults to the $JOB_NAME of the current pipeline")
+	cmd.Flags().StringVarP(&options.Build, "build", "b", "", "The build number. Defaults to the $BUILD_NUMBER of the current pipeline")
+	cmd.Flags().StringVarP(&options.Owner, "owner", "o", "", "Git organisation / owner. Defaults to $REPO_OWNER or the git owner of the pipeline")
+	cmd.Flags().StringVarP(&options.Repository, "repository", "r", "", "Git repository. Defaults to $REPO_NAME or the git repository of the pipeline")
+	cmd.Flags().StringVarP(&options.PullRequest, "pull-request", "p", "", "Git Pull Request number. Defaults to $PULL_NUMBER or the number of a PR-N branch")
+	cmd.Flags().StringVarP(&options.Image, "image", "i", "", "The image built by the pipeline")
+	cmd.Flags().StringVarP(&options.PreviewURL, "preview-url", "", "", "The URL of the preview environment. Defaults to the preview environment of the pipeline")
+	cmd.Flags().StringVarP(&options.Coverage, "coverage", "", "", "The code coverage. Defaults to the coverage fact of the pipeline")
+	cmd.Flags().StringVarP(&options.Findings, "findings", "", "", "The number of findings of the scans. Defaults to the total bugs fact of the pipeline")
+	return cmd
+}
+
+// Run implements this command
+func (o *StepPRSummaryOptions) Run() error {
+	pipeline := o.Pipeline
+	if pipeline == "" {
+		pipeline = o.getJobName()
+	}
+	build := o.Build
+	if build == "" {
+		build = o.getBuildNumber()
+	}
+	if pipeline == "" || build == "" {
+		return fmt.Errorf("could not detect the pipeline and build number; please specify the --pipeline and --build options")
+	}
+	if o.PullRequest == "" {
+		o.PullRequest = pullRequestNumber(os.Getenv("BRANCH_NAME"))
+	}
+	if o.PullRequest == "" {
+		return util.MissingOption("pull-request")
+	}
+	prNumber, err := strconv.Atoi(o.PullRequest)
+	if err != nil {
+		return util.InvalidOptionError("pull-request", o.PullRequest, err)
+	}
+
+	jxClient, ns, err := o.JXClientAndDevNamespace()
+	if err != nil {
+		return err
+	}
+	name := kube.ToValidName(pipeline + "-" + build)
+	activity, err := jxClient.JenkinsV1().PipelineActivities(ns).Get(name, metav1.GetOptions{})
+	if err != nil {
+		return errors.Wrapf(err, "failed to find the PipelineActivity %s in namespace %s", name, ns)
+	}
+
+	if o.Owner == "" {
+		o.Owner = os.Getenv("REPO_OWNER")
+	}
+	if o.Owner == "" {
+		o.Owner = activity.Spec.GitOwner
+	}
+	if o.Repository == "" {
+		o.Repository = os.Getenv("REPO_NAME")
+	}
+	if o.Repository == "" {
+		o.Repository = activity.Spec.GitRepository
+	}
+	if o.Owner == "" {
+		return util.MissingOption("owner")
+	}
+	if o.Repository == "" {
+		return util.MissingOption("repository")
+	}
+
+	provider := o.GitProvider
+	if provider == nil {
+		gitURL := activity.Spec.GitURL
+		if gitURL == "" {
+			gitInfo, err := o.Git().Info("")
+			if err != nil {
+				return err
+			}
+			gitURL = gitInfo.URL
+		}
+		provider, err = o.gitProviderForURL(gitURL, "user name to submit the summary comment as")
+		if err != nil {
+			return err
+		}
+	}
+
+	pr := &gits.GitPullRequest{
+		Owner:  o.Owner,
+		Repo:   o.Repository,
+		Number: &prNumber,
+	}
+	err = gits.AddOrUpdatePRComment(provider, pr, prSummaryCommentMarker, o.summary(activity))
+	if err != nil {
+		return errors.Wrapf(err, "failed to comment on pull request %s/%s#%d", o.Owner, o.Repository, prNumber)
+	}
+	log.Infof("Updated the pipeline summary of pull request %s\n", util.ColorInfo(fmt.Sprintf("%s/%s#%d", o.Owner, o.Repository, prNumber)))
+	return nil
+}
+
+// summary renders the markdown summary of the pipeline activity
+func (o *StepPRSummaryOptions) summary(activity *v1.PipelineActivity) string {
+	spec := &activity.Spec
+	var buf strings.Builder
+	fmt.Fprintf(&buf, "### Pipeline %s %s\n\n", statusEmoji(spec.Status), summaryLink(fmt.Sprintf("build #%s", spec.Build), spec.BuildURL))
+	stages := []v1.CoreActivityStep{}
+	previewURL := o.PreviewURL
+	for _, step := range spec.Steps {
+		if step.Stage != nil {
+			stages = append(stages, step.Stage.CoreActivityStep)
+		}
+		if step.Preview != nil && previewURL == "" {
+			previewURL = step.Preview.ApplicationURL
+		}
+	}
+	if len(stages) > 0 {
+		buf.WriteString("| Stage | Status | Duration |\n")
+		buf.WriteString("| --- | --- | --- |\n")
+		for _, stage := range stages {
+			fmt.Fprintf(&buf, "| %s | %s %s | %s |\n", stage.Name, statusEmoji(stage.Status), stage.Status, stepDuration(&stage))
+		}
+		buf.WriteString("\n")
+	}
+
+	coverage := o.Coverage
+	if coverage == "" {
+		if value, ok := activityMeasurement(activity, v1.CodeCoverageMeasurementCoverage); ok {
+			coverage = fmt.Sprintf("%d%%", value)
+		}
+	}
+	findings := o.Findings
+	if findings == "" {
+		if value, ok := activityMeasurement(activity, v1.StaticProgramAnalysisTotalBugs); ok {
+			findings = strconv.Itoa(value)
+		}
+	}
+	details := [][]string{
+		{"Image", "`" + o.Image + "`", o.Image},
+		{"Preview", previewURL, previewURL},
+		{"Coverage", coverage, coverage},
+		{"Scan findings", findings, findings},
+	}
+	for _, detail := range details {
+		if detail[2] != "" {
+			fmt.Fprintf(&buf, "* **%s:** %s\n", detail[0], detail[1])
+		}
+	}
+	return buf.String()
+}
+
+// activityMeasurement returns the value of the first measurement of the facts of the activity with the given name
+func activityMeasurement(activity *v1.PipelineActivity, name string) (int, bool) {
+	for _, fact := range activity.Spec.Facts {
+		for _, measurement := range fact.Measurements {
+			if measurement.Name == name {
+				return measurement.MeasurementValue, true
+			}
+		}
+	}
+	return 0, false
+}
+
+func statusEmoji(status v1.ActivityStatusType) string {
+	switch status {
+	case v1.ActivityStatusTypeSucceeded:
+		return ":white_check_mark:"
+	case v1.ActivityStatusTypeFailed, v1.ActivityStatusTypeError:
+		return ":x:"
+	case v1.ActivityStatusTypeAborted:
+		return ":no_entry_sign:"
+	case v1.ActivityStatusTypeRunning:
+		return ":hourglass:"
+	default:
+		return ":grey_question:"
+	}
+}
+
+func stepDuration(step *v1.CoreActivityStep) string {
+	if step.StartedTimestamp == nil || step.CompletedTimestamp == nil {
+		return ""
+	}
+	return step.CompletedTimestamp.Sub(step.StartedTimestamp.Time).Round(time.Second).String()
+}
+
+func summaryLink(text string, url string) string {
+	if url == "" {
+		return text
+	}
+	return fmt.Sprintf("[%s](%s)", text, url)
+}
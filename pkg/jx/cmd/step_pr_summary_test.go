@@ -0,0 +1,92 @@
+package cmd_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/jenkins-x/jx/pkg/apis/jenkins.io/v1"
+	"github.com/jenkins-x/jx/pkg/gits"
+	"github.com/jenkins-x/jx/pkg/helm"
+	"github.com/jenkins-x/jx/pkg/jx/cmd"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+)
+
+func TestStepPRSummary(t *testing.T) {
+	t.Parallel()
+	started := metav1.NewTime(time.Date(2018, 11, 1, 10, 0, 0, 0, time.UTC))
+	completed := metav1.NewTime(started.Add(90 * time.Second))
+	activity := &v1.PipelineActivity{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "myorg-myapp-pr-12-3",
+			Namespace: "jx",
+		},
+		Spec: v1.PipelineActivitySpec{
+			Pipeline:      "myorg/myapp/PR-12",
+			Build:         "3",
+			Status:        v1.ActivityStatusTypeRunning,
+			GitOwner:      "myorg",
+			GitRepository: "myapp",
+			Steps: []v1.PipelineActivityStep{
+				{
+					Kind: v1.ActivityStepKindTypeStage,
+					Stage: &v1.StageActivityStep{
+						CoreActivityStep: v1.CoreActivityStep{
+							Name:               "Build",
+							Status:             v1.ActivityStatusTypeSucceeded,
+							StartedTimestamp:   &started,
+							CompletedTimestamp: &completed,
+						},
+					},
+				},
+				{
+					Kind: v1.ActivityStepKindTypePreview,
+					Preview: &v1.PreviewActivityStep{
+						ApplicationURL: "http://myapp.jx-myorg-myapp-pr-12.example.com",
+					},
+				},
+			},
+			Facts: []v1.Fact{
+				{
+					Name: "coverage",
+					Measurements: []v1.Measurement{
+						{Name: v1.CodeCoverageMeasurementCoverage, MeasurementValue: 83},
+					},
+				},
+			},
+		},
+	}
+	number := 12
+	repo := gits.NewFakeRepository("myorg", "myapp")
+	repo.PullRequests[number] = &gits.FakePullRequest{
+		PullRequest: &gits.GitPullRequest{Owner: "myorg", Repo: "myapp", Number: &number},
+	}
+	provider := gits.NewFakeProvider(repo)
+
+	o := &cmd.StepPRSummaryOptions{
+		Pipeline:    activity.Spec.Pipeline,
+		Build:       activity.Spec.Build,
+		PullRequest: "12",
+		Image:       "docker.io/myorg/myapp:0.0.1-SNAPSHOT-PR-12-3",
+		GitProvider: provider,
+	}
+	cmd.ConfigureTestOptionsWithResources(&o.CommonOptions, nil, []runtime.Object{activity}, gits.NewGitCLI(), helm.NewHelmCLI("helm", helm.V2, "", true))
+
+	err := o.Run()
+	require.NoError(t, err)
+	comments := repo.PullRequests[number].Comments
+	require.Len(t, comments, 1)
+	assert.Contains(t, comments[0].Body, "| Build | :white_check_mark: Succeeded | 1m30s |")
+	assert.Contains(t, comments[0].Body, "`docker.io/myorg/myapp:0.0.1-SNAPSHOT-PR-12-3`")
+	assert.Contains(t, comments[0].Body, "http://myapp.jx-myorg-myapp-pr-12.example.com")
+	assert.Contains(t, comments[0].Body, "**Coverage:** 83%")
+
+	o.Image = "docker.io/myorg/myapp:0.0.1-SNAPSHOT-PR-12-4"
+	err = o.Run()
+	require.NoError(t, err)
+	comments = repo.PullRequests[number].Comments
+	require.Len(t, comments, 1, "the summary comment should be updated rather than added again")
+	assert.Contains(t, comments[0].Body, "0.0.1-SNAPSHOT-PR-12-4")
+}
@@ -0,0 +1,48 @@
+package cmd
+
+import (
+	"io"
+
+	"github.com/spf13/cobra"
+	"gopkg.in/AlecAivazis/survey.v1/terminal"
+)
+
+// StepSchemaOptions contains the command line flags
+type StepSchemaOptions struct {
+	StepOptions
+}
+
+// NewCmdStepSchema creates the command
+func NewCmdStepSchema(f Factory, in terminal.FileReader, out terminal.FileWriter, errOut io.Writer) *cobra.Command {
+	options := &StepSchemaOptions{
+		StepOptions: StepOptions{
+			CommonOptions: CommonOptions{
+				Factory: f,
+				In:      in,
+				Out:     out,
+				Err:     errOut,
+			},
+		},
+	}
+
+	cmd := &cobra.Command{
+		Use:   "schema",
+		Short: "Prints the JSON Schemas of the configuration files of Jenkins X",
+		Run: func(cmd *cobra.Command, args []string) {
+			options.Cmd = cmd
+			options.Args = args
+			err := options.Run()
+			CheckErr(err)
+		},
+	}
+
+	cmd.AddCommand(NewCmdStepSchemaPipeline(f, in, out, errOut))
+	options.addCommonFlags(cmd)
+
+	return cmd
+}
+
+// Run implements this command
+func (o *StepSchemaOptions) Run() error {
+	return o.Cmd.Help()
+}
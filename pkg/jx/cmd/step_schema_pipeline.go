@@ -0,0 +1,87 @@
+package cmd
+
+import (
+	"io"
+	"io/ioutil"
+
+	"github.com/jenkins-x/jx/pkg/config"
+	"github.com/jenkins-x/jx/pkg/jx/cmd/templates"
+	"github.com/jenkins-x/jx/pkg/log"
+	"github.com/jenkins-x/jx/pkg/util"
+	"github.com/spf13/cobra"
+	"gopkg.in/AlecAivazis/survey.v1/terminal"
+)
+
+var (
+	stepSchemaPipelineLong = templates.LongDesc(`
+		Prints the JSON Schema of the jenkins-x.yml file which configures the pipelines of a project.
+
+		Editors supporting JSON Schema such as VS Code and IntelliJ use the schema to validate and complete the file.
+`)
+
+	stepSchemaPipelineExample = templates.Examples(`
+		# prints the schema of jenkins-x.yml
+		jx step schema pipeline
+
+		# writes the schema into a file which an editor is configured to use
+		jx step schema pipeline -o jenkins-x-schema.json
+	`)
+)
+
+// StepSchemaPipelineOptions contains the command line flags
+type StepSchemaPipelineOptions struct {
+	StepSchemaOptions
+
+	OutputFile string
+}
+
+// NewCmdStepSchemaPipeline creates the command
+func NewCmdStepSchemaPipeline(f Factory, in terminal.FileReader, out terminal.FileWriter, errOut io.Writer) *cobra.Command {
+	options := &StepSchemaPipelineOptions{
+		StepSchemaOptions: StepSchemaOptions{
+			StepOptions: StepOptions{
+				CommonOptions: CommonOptions{
+					Factory: f,
+					In:      in,
+					Out:     out,
+					Err:     errOut,
+				},
+			},
+		},
+	}
+
+	cmd := &cobra.Command{
+		Use:     "pipeline",
+		Short:   "Prints the JSON Schema of the jenkins-x.yml file",
+		Long:    stepSchemaPipelineLong,
+		Example: stepSchemaPipelineExample,
+		Run: func(cmd *cobra.Command, args []string) {
+			options.Cmd = cmd
+			options.Args = args
+			err := options.Run()
+			CheckErr(err)
+		},
+	}
+	options.addCommonFlags(cmd)
+
+	cmd.Flags().StringVarP(&options.OutputFile, optionOutputFile, "o", "", "The file to write the schema into. Defaults to printing it")
+	return cmd
+}
+
+// Run implements this command
+func (o *StepSchemaPipelineOptions) Run() error {
+	data, err := config.ProjectConfigSchema()
+	if err != nil {
+		return err
+	}
+	if o.OutputFile != "" {
+		err = ioutil.WriteFile(o.OutputFile, data, util.DefaultWritePermissions)
+		if err != nil {
+			return err
+		}
+		log.Infof("Wrote the schema of %s to %s\n", config.ProjectConfigFileName, util.ColorInfo(o.OutputFile))
+		return nil
+	}
+	_, err = o.Out.Write(append(data, '\n'))
+	return err
+}
@@ -0,0 +1,140 @@
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"time"
+
+	"github.com/jenkins-x/jx/pkg/jx/cmd/templates"
+	"github.com/jenkins-x/jx/pkg/log"
+	"github.com/jenkins-x/jx/pkg/sonar"
+	"github.com/jenkins-x/jx/pkg/util"
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+	"gopkg.in/AlecAivazis/survey.v1/terminal"
+)
+
+var (
+	stepSonarDecorateLong = templates.LongDesc(`
+		Comments the SonarQube quality gate of the analysis of a pull request on the pull request via the git provider.
+
+		The analysis is found from the report-task.txt file written by the SonarQube scanner. The server is authenticated with the token in $SONAR_TOKEN.
+`)
+
+	stepSonarDecorateExample = templates.Examples(`
+		# comments the quality gate of the analysis of the sonar-scanner on pull request 12
+		jx step sonar decorate --pull-request 12
+
+		# comments the quality gate of the analysis of Maven and fails if the quality gate failed
+		jx step sonar decorate --report-task target/sonar/report-task.txt --fail-on-error
+		`)
+)
+
+// StepSonarDecorateOptions contains the command line flags
+type StepSonarDecorateOptions struct {
+	StepSonarOptions
+
+	ReportTask  string
+	ServerURL   string
+	PullRequest string
+	Timeout     time.Duration
+	FailOnError bool
+}
+
+// NewCmdStepSonarDecorate creates the command
+func NewCmdStepSonarDecorate(f Factory, in terminal.FileReader, out terminal.FileWriter, errOut io.Writer) *cobra.Command {
+	options := &StepSonarDecorateOptions{
+		StepSonarOptions: StepSonarOptions{
+			StepOptions: StepOptions{
+				CommonOptions: CommonOptions{
+					Factory: f,
+					In:      in,
+					Out:     out,
+					Err:     errOut,
+				},
+			},
+		},
+	}
+
+	cmd := &cobra.Command{
+		Use:     "decorate",
+		Short:   "Comments the SonarQube quality gate of a pull request on the pull request",
+		Long:    stepSonarDecorateLong,
+		Example: stepSonarDecorateExample,
+		Run: func(cmd *cobra.Command, args []string) {
+			options.Cmd = cmd
+			options.Args = args
+			err := options.Run()
+			CheckErr(err)
+		},
+	}
+	options.addCommonFlags(cmd)
+
+	cmd.Flags().StringVarP(&options.ReportTask, "report-task", "", sonarScannerReportTask, "The report-task.txt file written by the SonarQube scanner")
+	cmd.Flags().StringVarP(&options.ServerURL, "server", "", "", "The URL of the SonarQube server. Defaults to $"+sonarHostURLEnv+" or the server of the report task")
+	cmd.Flags().StringVarP(&options.PullRequest, "pull-request", "p", "", "The number of the pull request. Defaults to the pull request being built")
+	cmd.Flags().DurationVarP(&options.Timeout, "timeout", "", 5*time.Minute, "How long to wait for the SonarQube server to process the analysis")
+	cmd.Flags().BoolVarP(&options.FailOnError, "fail-on-error", "", false, "Fails if the quality gate failed")
+	return cmd
+}
+
+// Run implements this command
+func (o *StepSonarDecorateOptions) Run() error {
+	prNumber := o.PullRequest
+	if prNumber == "" {
+		prNumber = pullRequestNumber(os.Getenv("BRANCH_NAME"))
+	}
+	if prNumber == "" {
+		return util.MissingOption("pull-request")
+	}
+	task, err := sonar.LoadReportTask(o.ReportTask)
+	if err != nil {
+		return errors.Wrapf(err, "failed to load the SonarQube report task %s", o.ReportTask)
+	}
+	serverURL := o.ServerURL
+	if serverURL == "" {
+		serverURL = os.Getenv(sonarHostURLEnv)
+	}
+	if serverURL == "" {
+		serverURL = task.ServerURL
+	}
+	if serverURL == "" {
+		return util.MissingOption("server")
+	}
+	client := sonar.NewClient(serverURL, os.Getenv(sonarTokenEnv))
+	log.Infof("Waiting for SonarQube to process the analysis %s\n", util.ColorInfo(task.CeTaskID))
+	analysisID, err := client.WaitForAnalysis(task.CeTaskID, o.Timeout, 2*time.Second)
+	if err != nil {
+		return err
+	}
+	status, err := client.QualityGate(analysisID)
+	if err != nil {
+		return err
+	}
+	log.Infof("The SonarQube quality gate of %s is %s\n", util.ColorInfo(task.ProjectKey), util.ColorInfo(status.Status))
+
+	gitInfo, err := o.Git().Info("")
+	if err != nil {
+		return err
+	}
+	comment := &StepPRCommentOptions{
+		StepPROptions: StepPROptions{
+			StepOptions: o.StepOptions,
+		},
+		Flags: StepPRCommentFlags{
+			Comment:    status.Markdown(task.DashboardURL),
+			Owner:      gitInfo.Organisation,
+			Repository: gitInfo.Name,
+			PR:         prNumber,
+		},
+	}
+	err = comment.Run()
+	if err != nil {
+		return errors.Wrapf(err, "failed to comment the SonarQube quality gate on pull request %s", prNumber)
+	}
+	if o.FailOnError && status.Status == sonar.QualityGateError {
+		return fmt.Errorf("the SonarQube quality gate of %s failed", task.ProjectKey)
+	}
+	return nil
+}
@@ -0,0 +1,45 @@
+package cmd
+
+import (
+	"io"
+
+	"github.com/spf13/cobra"
+	"gopkg.in/AlecAivazis/survey.v1/terminal"
+)
+
+// StepSyncOptions contains the command line flags
+type StepSyncOptions struct {
+	StepOptions
+}
+
+// NewCmdStepSync Creates a new Command object
+func NewCmdStepSync(f Factory, in terminal.FileReader, out terminal.FileWriter, errOut io.Writer) *cobra.Command {
+	options := &StepSyncOptions{
+		StepOptions: StepOptions{
+			CommonOptions: CommonOptions{
+				Factory: f,
+				In:      in,
+				Out:     out,
+				Err:     errOut,
+			},
+		},
+	}
+
+	cmd := &cobra.Command{
+		Use:   "sync",
+		Short: "sync [command]",
+		Run: func(cmd *cobra.Command, args []string) {
+			options.Cmd = cmd
+			options.Args = args
+			err := options.Run()
+			CheckErr(err)
+		},
+	}
+	cmd.AddCommand(NewCmdStepSyncPodTemplates(f, in, out, errOut))
+	return cmd
+}
+
+// Run implements this command
+func (o *StepSyncOptions) Run() error {
+	return o.Cmd.Help()
+}
@@ -0,0 +1,327 @@
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/ghodss/yaml"
+	"github.com/jenkins-x/jx/pkg/jx/cmd/templates"
+	"github.com/jenkins-x/jx/pkg/kube"
+	"github.com/jenkins-x/jx/pkg/log"
+	"github.com/jenkins-x/jx/pkg/util"
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+	"gopkg.in/AlecAivazis/survey.v1/terminal"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/client-go/kubernetes"
+)
+
+const (
+	defaultPodTemplatesPath = "podTemplates"
+)
+
+var (
+	stepSyncPodTemplatesLong = templates.LongDesc(`
+		Synchronizes the pod templates of the current team with a versioned git repository.
+
+		Each pod template is stored in the git repository as a YAML file named after the pod template such as 'podTemplates/maven.yaml'.
+		By default the pod templates in the git repository are applied to the ConfigMap of pod templates. Use '--export' to write the pod templates
+		in the ConfigMap into a local checkout of the git repository instead.
+
+		The differences between the git repository and the ConfigMap are displayed for each pod template.
+`)
+
+	stepSyncPodTemplatesExample = templates.Examples(`
+		# Apply the pod templates from a git repository to the current team
+		jx step sync podtemplates --git-url https://github.com/myorg/my-builders.git
+
+		# View the differences without applying them
+		jx step sync podtemplates --git-url https://github.com/myorg/my-builders.git --dry-run
+
+		# Export the pod templates of the current team into a local checkout
+		jx step sync podtemplates --dir ~/my-builders --export
+	`)
+)
+
+// StepSyncPodTemplatesOptions contains the command line flags
+type StepSyncPodTemplatesOptions struct {
+	StepOptions
+
+	GitURL string
+	GitRef string
+	Dir    string
+	Path   string
+	Export bool
+	Prune  bool
+	DryRun bool
+}
+
+// NewCmdStepSyncPodTemplates Creates a new Command object
+func NewCmdStepSyncPodTemplates(f Factory, in terminal.FileReader, out terminal.FileWriter, errOut io.Writer) *cobra.Command {
+	options := &StepSyncPodTemplatesOptions{
+		StepOptions: StepOptions{
+			CommonOptions: CommonOptions{
+				Factory: f,
+				In:      in,
+				Out:     out,
+				Err:     errOut,
+			},
+		},
+	}
+
+	cmd := &cobra.Command{
+		Use:     "podtemplates",
+		Short:   "Synchronizes the pod templates of the current team with a versioned git repository",
+		Long:    stepSyncPodTemplatesLong,
+		Example: stepSyncPodTemplatesExample,
+		Aliases: []string{"podtemplate", "pod templates"},
+		Run: func(cmd *cobra.Command, args []string) {
+			options.Cmd = cmd
+			options.Args = args
+			err := options.Run()
+			CheckErr(err)
+		},
+	}
+	options.addCommonFlags(cmd)
+
+	cmd.Flags().StringVarP(&options.GitURL, "git-url", "u", "", "The git repository URL containing the pod templates")
+	cmd.Flags().StringVarP(&options.GitRef, "git-ref", "r", "master", "The git reference (branch, tag, sha) of the git repository to use")
+	cmd.Flags().StringVarP(&options.Dir, "dir", "d", "", "A local checkout of the git repository to use instead of cloning the git URL")
+	cmd.Flags().StringVarP(&options.Path, "path", "p", defaultPodTemplatesPath, "The path within the git repository containing the pod template YAML files")
+	cmd.Flags().BoolVarP(&options.Export, "export", "e", false, "Exports the pod templates of the current team into the local checkout rather than applying them")
+	cmd.Flags().BoolVarP(&options.Prune, "prune", "", false, "Removes pod templates which are not in the source of the synchronization")
+	cmd.Flags().BoolVarP(&options.DryRun, "dry-run", "", false, "Only displays the differences without applying them")
+	return cmd
+}
+
+// Run implements this command
+func (o *StepSyncPodTemplatesOptions) Run() error {
+	dir := o.Dir
+	if o.Export && dir == "" {
+		return util.MissingOption("dir")
+	}
+	if dir == "" {
+		if o.GitURL == "" {
+			return util.MissingOption("git-url")
+		}
+		tmpDir, err := ioutil.TempDir("", "jx-podtemplates-")
+		if err != nil {
+			return err
+		}
+		defer os.RemoveAll(tmpDir)
+
+		err = o.Git().Clone(o.GitURL, tmpDir)
+		if err != nil {
+			return errors.Wrapf(err, "failed to clone %s", o.GitURL)
+		}
+		if o.GitRef != "" {
+			err = o.Git().Checkout(tmpDir, o.GitRef)
+			if err != nil {
+				return errors.Wrapf(err, "failed to checkout %s of %s", o.GitRef, o.GitURL)
+			}
+		}
+		dir = tmpDir
+	}
+	templatesDir := filepath.Join(dir, o.Path)
+
+	kubeClient, ns, err := o.KubeClientAndDevNamespace()
+	if err != nil {
+		return err
+	}
+	current, err := kube.LoadUnresolvedPodTemplates(kubeClient, ns)
+	if err != nil {
+		return err
+	}
+	if o.Export {
+		return o.exportPodTemplates(current, templatesDir)
+	}
+	return o.importPodTemplates(kubeClient, ns, current, templatesDir)
+}
+
+func (o *StepSyncPodTemplatesOptions) importPodTemplates(kubeClient kubernetes.Interface, ns string, current map[string]*corev1.Pod, templatesDir string) error {
+	source, err := loadPodTemplateFiles(templatesDir)
+	if err != nil {
+		return err
+	}
+	changes := 0
+	for _, name := range sortedPodTemplateNames(source) {
+		pod := source[name]
+		err = kube.ValidatePodTemplate(name, pod)
+		if err != nil {
+			return err
+		}
+		changed, err := o.logPodTemplateDiff(name, current[name], pod)
+		if err != nil {
+			return err
+		}
+		if !changed {
+			continue
+		}
+		changes++
+		if !o.DryRun {
+			err = kube.SavePodTemplate(kubeClient, ns, name, pod)
+			if err != nil {
+				return err
+			}
+		}
+	}
+	if o.Prune {
+		for _, name := range sortedPodTemplateNames(current) {
+			if source[name] != nil {
+				continue
+			}
+			log.Infof("Removing pod template %s\n", util.ColorWarning(name))
+			changes++
+			if !o.DryRun {
+				err = kube.DeletePodTemplate(kubeClient, ns, name)
+				if err != nil {
+					return err
+				}
+			}
+		}
+	}
+	if changes > 0 && !o.DryRun {
+		invalidatePodTemplatesCache(ns)
+	}
+	o.logSummary(changes, "ConfigMap "+kube.ConfigMapJenkinsPodTemplates)
+	return nil
+}
+
+func (o *StepSyncPodTemplatesOptions) exportPodTemplates(current map[string]*corev1.Pod, templatesDir string) error {
+	existing, err := loadPodTemplateFiles(templatesDir)
+	if err != nil {
+		return err
+	}
+	if !o.DryRun {
+		err = os.MkdirAll(templatesDir, DefaultWritePermissions)
+		if err != nil {
+			return err
+		}
+	}
+	changes := 0
+	for _, name := range sortedPodTemplateNames(current) {
+		pod := current[name]
+		changed, err := o.logPodTemplateDiff(name, existing[name], pod)
+		if err != nil {
+			return err
+		}
+		if !changed {
+			continue
+		}
+		changes++
+		if !o.DryRun {
+			data, err := yaml.Marshal(pod)
+			if err != nil {
+				return err
+			}
+			err = ioutil.WriteFile(filepath.Join(templatesDir, name+".yaml"), data, DefaultWritePermissions)
+			if err != nil {
+				return err
+			}
+		}
+	}
+	if o.Prune {
+		for _, name := range sortedPodTemplateNames(existing) {
+			if current[name] != nil {
+				continue
+			}
+			log.Infof("Removing pod template %s\n", util.ColorWarning(name))
+			changes++
+			if !o.DryRun {
+				for _, ext := range []string{".yaml", ".yml"} {
+					fileName := filepath.Join(templatesDir, name+ext)
+					exists, err := util.FileExists(fileName)
+					if err == nil && exists {
+						err = os.Remove(fileName)
+						if err != nil {
+							return err
+						}
+					}
+				}
+			}
+		}
+	}
+	o.logSummary(changes, templatesDir)
+	return nil
+}
+
+// logPodTemplateDiff logs the differences between the old and new pod template returning true if they differ
+func (o *StepSyncPodTemplatesOptions) logPodTemplateDiff(name string, oldPod *corev1.Pod, newPod *corev1.Pod) (bool, error) {
+	newData, err := yaml.Marshal(newPod)
+	if err != nil {
+		return false, err
+	}
+	if oldPod == nil {
+		log.Infof("Adding pod template %s\n", util.ColorInfo(name))
+		return true, nil
+	}
+	oldData, err := yaml.Marshal(oldPod)
+	if err != nil {
+		return false, err
+	}
+	diff := util.DiffLines(string(oldData), string(newData))
+	if diff == "" {
+		o.Debugf("Pod template %s is up to date\n", name)
+		return false, nil
+	}
+	log.Infof("Updating pod template %s\n%s\n", util.ColorInfo(name), util.ColorDiffLines(diff))
+	return true, nil
+}
+
+func (o *StepSyncPodTemplatesOptions) logSummary(changes int, target string) {
+	if changes == 0 {
+		log.Infof("The pod templates in %s are up to date\n", util.ColorInfo(target))
+	} else if o.DryRun {
+		log.Infof("Dry run: %d pod template changes would be made to %s\n", changes, util.ColorInfo(target))
+	} else {
+		log.Infof("Made %d pod template changes to %s\n", changes, util.ColorInfo(target))
+	}
+}
+
+// loadPodTemplateFiles loads the pod template YAML files in the given directory keyed by their name
+func loadPodTemplateFiles(dir string) (map[string]*corev1.Pod, error) {
+	answer := map[string]*corev1.Pod{}
+	exists, err := util.FileExists(dir)
+	if err != nil || !exists {
+		return answer, err
+	}
+	files, err := ioutil.ReadDir(dir)
+	if err != nil {
+		return answer, err
+	}
+	for _, f := range files {
+		fileName := f.Name()
+		ext := filepath.Ext(fileName)
+		if f.IsDir() || (ext != ".yaml" && ext != ".yml") {
+			continue
+		}
+		name := strings.TrimSuffix(fileName, ext)
+		data, err := ioutil.ReadFile(filepath.Join(dir, fileName))
+		if err != nil {
+			return answer, err
+		}
+		pod, err := kube.ParsePodTemplate(name, string(data))
+		if err != nil {
+			return answer, err
+		}
+		if answer[name] != nil {
+			return answer, fmt.Errorf("Duplicate pod template %s in directory %s", name, dir)
+		}
+		answer[name] = pod
+	}
+	return answer, nil
+}
+
+func sortedPodTemplateNames(podTemplates map[string]*corev1.Pod) []string {
+	names := []string{}
+	for name := range podTemplates {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
@@ -0,0 +1,122 @@
+package cmd_test
+
+import (
+	"io/ioutil"
+	"os"
+	"path"
+	"path/filepath"
+	"testing"
+
+	"github.com/jenkins-x/jx/pkg/gits"
+	"github.com/jenkins-x/jx/pkg/helm"
+	"github.com/jenkins-x/jx/pkg/jx/cmd"
+	"github.com/jenkins-x/jx/pkg/kube"
+	"github.com/jenkins-x/jx/pkg/util"
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+)
+
+func TestStepSyncPodTemplates(t *testing.T) {
+	t.Parallel()
+	k8sObjects := []runtime.Object{
+		&corev1.ConfigMap{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      kube.ConfigMapJenkinsPodTemplates,
+				Namespace: "jx",
+			},
+			Data: map[string]string{
+				"maven":  MavenBuildPackYaml,
+				"gradle": MavenBuildPackYaml,
+			},
+		},
+	}
+	o := &cmd.StepSyncPodTemplatesOptions{}
+	cmd.ConfigureTestOptionsWithResources(&o.CommonOptions, k8sObjects, nil, gits.NewGitCLI(), helm.NewHelmCLI("helm", helm.V2, "", true))
+	o.Dir = path.Join("test_data", "step_sync_podtemplates")
+	o.Path = "podTemplates"
+	o.Prune = true
+	o.DryRun = true
+
+	err := o.Run()
+	assert.NoError(t, err)
+
+	kubeClient, ns, err := o.KubeClientAndDevNamespace()
+	assert.NoError(t, err)
+	podTemplates, err := kube.LoadPodTemplates(kubeClient, ns)
+	assert.NoError(t, err)
+	assert.Equal(t, 2, len(podTemplates), "dry run should not modify the pod templates")
+	assert.Equal(t, "jenkinsxio/builder-maven:0.0.408", podTemplates["maven"].Spec.Containers[0].Image)
+
+	o.DryRun = false
+	err = o.Run()
+	assert.NoError(t, err)
+
+	podTemplates, err = kube.LoadPodTemplates(kubeClient, ns)
+	assert.NoError(t, err)
+	assert.Equal(t, 2, len(podTemplates))
+	assert.Nil(t, podTemplates["gradle"], "gradle pod template should have been pruned")
+	assert.Equal(t, "jenkinsxio/builder-maven:0.0.500", podTemplates["maven"].Spec.Containers[0].Image)
+	assert.Equal(t, "jenkinsxio/builder-maven-java11:0.0.1", podTemplates["maven-java11"].Spec.Containers[0].Image)
+}
+
+func TestStepSyncPodTemplatesGitRef(t *testing.T) {
+	t.Parallel()
+	repoDir, err := ioutil.TempDir("", "test-step-sync-podtemplates")
+	assert.NoError(t, err)
+	defer os.RemoveAll(repoDir)
+
+	// the tag v1 has an older image than the master branch
+	gitter := gits.NewGitCLI()
+	err = gitter.Init(repoDir)
+	assert.NoError(t, err)
+	for _, args := range [][]string{{"config", "user.name", "test"}, {"config", "user.email", "test@example.com"}} {
+		_, err = (&util.Command{Dir: repoDir, Name: "git", Args: args}).RunWithoutRetry()
+		assert.NoError(t, err)
+	}
+	templatesDir := filepath.Join(repoDir, "podTemplates")
+	err = os.MkdirAll(templatesDir, util.DefaultWritePermissions)
+	assert.NoError(t, err)
+	for _, version := range []string{"0.0.500", "0.0.600"} {
+		podTemplate := "apiVersion: v1\nkind: Pod\nmetadata:\n  name: jenkins-maven\nspec:\n  containers:\n  - name: maven\n    image: jenkinsxio/builder-maven:" + version + "\n"
+		err = ioutil.WriteFile(filepath.Join(templatesDir, "maven.yaml"), []byte(podTemplate), util.DefaultWritePermissions)
+		assert.NoError(t, err)
+		err = gitter.Add(repoDir, "*")
+		assert.NoError(t, err)
+		err = gitter.CommitDir(repoDir, "builder-maven "+version)
+		assert.NoError(t, err)
+		if version == "0.0.500" {
+			_, err = (&util.Command{Dir: repoDir, Name: "git", Args: []string{"tag", "v1"}}).RunWithoutRetry()
+			assert.NoError(t, err)
+		}
+	}
+
+	k8sObjects := []runtime.Object{
+		&corev1.ConfigMap{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      kube.ConfigMapJenkinsPodTemplates,
+				Namespace: "jx",
+			},
+			Data: map[string]string{
+				"maven": MavenBuildPackYaml,
+			},
+		},
+	}
+	o := &cmd.StepSyncPodTemplatesOptions{}
+	cmd.ConfigureTestOptionsWithResources(&o.CommonOptions, k8sObjects, nil, gitter, helm.NewHelmCLI("helm", helm.V2, "", true))
+	o.GitURL = "file://" + repoDir
+	o.GitRef = "v1"
+	o.Path = "podTemplates"
+
+	err = o.Run()
+	assert.NoError(t, err, "the tag should be checked out")
+
+	kubeClient, ns, err := o.KubeClientAndDevNamespace()
+	assert.NoError(t, err)
+	podTemplates, err := kube.LoadPodTemplates(kubeClient, ns)
+	assert.NoError(t, err)
+	if assert.NotNil(t, podTemplates["maven"]) {
+		assert.Equal(t, "jenkinsxio/builder-maven:0.0.500", podTemplates["maven"].Spec.Containers[0].Image)
+	}
+}
@@ -0,0 +1,137 @@
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/jenkins-x/jx/pkg/jx/cmd/templates"
+	"github.com/jenkins-x/jx/pkg/log"
+	"github.com/jenkins-x/jx/pkg/tracing"
+	"github.com/jenkins-x/jx/pkg/util"
+	"github.com/spf13/cobra"
+	"gopkg.in/AlecAivazis/survey.v1/terminal"
+)
+
+var (
+	stepTraceLong = templates.LongDesc(`
+		Runs a command and exports a span of how long it took to the OTLP endpoint in $OTEL_EXPORTER_OTLP_ENDPOINT.
+
+		The span is a child of the span in the W3C trace context in $TRACEPARENT, which 'jx step create build' sets for each step when the tracing of the team build defaults is enabled, so that the commands of the steps appear in the traces of the pipelines. The trace context of the span of the command is passed on to the command in $TRACEPARENT.
+
+		The command exits with the exit code of the command. Failures to export the span are only logged.
+`)
+
+	stepTraceExample = templates.Examples(`
+		# run the tests in a span of the trace of the step
+		jx step trace --name test -- mvn test
+	`)
+)
+
+// StepTraceOptions contains the command line flags
+type StepTraceOptions struct {
+	StepOptions
+
+	Name     string
+	Endpoint string
+}
+
+// NewCmdStepTrace creates the command
+func NewCmdStepTrace(f Factory, in terminal.FileReader, out terminal.FileWriter, errOut io.Writer) *cobra.Command {
+	options := &StepTraceOptions{
+		StepOptions: StepOptions{
+			CommonOptions: CommonOptions{
+				Factory: f,
+				In:      in,
+				Out:     out,
+				Err:     errOut,
+			},
+		},
+	}
+
+	cmd := &cobra.Command{
+		Use:     "trace -- command [args...]",
+		Short:   "Runs a command and exports a span of how long it took",
+		Long:    stepTraceLong,
+		Example: stepTraceExample,
+		Run: func(cmd *cobra.Command, args []string) {
+			options.Cmd = cmd
+			options.Args = args
+			err := options.Run()
+			CheckErr(err)
+		},
+	}
+	options.addCommonFlags(cmd)
+
+	cmd.Flags().StringVarP(&options.Name, "name", "", "", "The name of the span. Defaults to the name of the command")
+	cmd.Flags().StringVarP(&options.Endpoint, "endpoint", "", "", "The OTLP/HTTP endpoint the span is exported to. Defaults to $"+tracing.EnvExporterEndpoint)
+	return cmd
+}
+
+// Run implements this command
+func (o *StepTraceOptions) Run() error {
+	if len(o.Args) == 0 {
+		return util.WithExitCode(fmt.Errorf("Missing argument for the command to run. Usage: jx step trace -- command [args...]"), util.ExitCodeMissingOption)
+	}
+	name := o.Name
+	if name == "" {
+		name = o.Args[0]
+	}
+	endpoint := o.Endpoint
+	if endpoint == "" {
+		endpoint = os.Getenv(tracing.EnvExporterEndpoint)
+	}
+	traceID, parentSpanID, err := tracing.ParseTraceParent(os.Getenv(tracing.EnvTraceParent))
+	if err != nil {
+		// the command is run in a new trace when it is not run by a traced step
+		traceID, err = tracing.NewTraceID()
+		if err != nil {
+			return err
+		}
+		parentSpanID = ""
+	}
+	spanID, err := tracing.NewSpanID()
+	if err != nil {
+		return err
+	}
+
+	command := exec.Command(o.Args[0], o.Args[1:]...)
+	command.Stdin = os.Stdin
+	command.Stdout = o.Out
+	command.Stderr = o.Err
+	command.Env = append(os.Environ(), tracing.EnvTraceParent+"="+tracing.TraceParent(traceID, spanID))
+	start := time.Now()
+	runErr := command.Run()
+	span := tracing.Span{
+		TraceID:      traceID,
+		SpanID:       spanID,
+		ParentSpanID: parentSpanID,
+		Name:         name,
+		Start:        start,
+		End:          time.Now(),
+		Attributes: map[string]string{
+			"command": strings.Join(o.Args, " "),
+		},
+		Failed: runErr != nil,
+	}
+	if endpoint != "" {
+		serviceName := os.Getenv(tracing.EnvServiceName)
+		if serviceName == "" {
+			serviceName = name
+		}
+		err = tracing.Export(endpoint, serviceName, []tracing.Span{span})
+		if err != nil {
+			log.Warnf("Failed to export the span of %s: %s\n", name, err)
+		}
+	}
+	if exitErr, ok := runErr.(*exec.ExitError); ok {
+		if status, ok := exitErr.Sys().(syscall.WaitStatus); ok {
+			return util.WithExitCode(runErr, status.ExitStatus())
+		}
+	}
+	return runErr
+}
@@ -0,0 +1,45 @@
+package cmd
+
+import (
+	"io"
+
+	"github.com/spf13/cobra"
+	"gopkg.in/AlecAivazis/survey.v1/terminal"
+)
+
+// StepUpdateOptions contains the command line flags
+type StepUpdateOptions struct {
+	StepOptions
+}
+
+// NewCmdStepSync Creates a new Command object
+func NewCmdStepUpdate(f Factory, in terminal.FileReader, out terminal.FileWriter, errOut io.Writer) *cobra.Command {
+	options := &StepUpdateOptions{
+		StepOptions: StepOptions{
+			CommonOptions: CommonOptions{
+				Factory: f,
+				In:      in,
+				Out:     out,
+				Err:     errOut,
+			},
+		},
+	}
+
+	cmd := &cobra.Command{
+		Use:   "update",
+		Short: "update [command]",
+		Run: func(cmd *cobra.Command, args []string) {
+			options.Cmd = cmd
+			options.Args = args
+			err := options.Run()
+			CheckErr(err)
+		},
+	}
+	cmd.AddCommand(NewCmdStepUpdateDependents(f, in, out, errOut))
+	return cmd
+}
+
+// Run implements this command
+func (o *StepUpdateOptions) Run() error {
+	return o.Cmd.Help()
+}
@@ -0,0 +1,221 @@
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/jenkins-x/jx/pkg/config"
+	"github.com/jenkins-x/jx/pkg/gits"
+	"github.com/jenkins-x/jx/pkg/jx/cmd/templates"
+	"github.com/jenkins-x/jx/pkg/log"
+	"github.com/jenkins-x/jx/pkg/util"
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+	"gopkg.in/AlecAivazis/survey.v1/terminal"
+)
+
+var (
+	stepUpdateDependentsLong = templates.LongDesc(`
+		Creates pull requests on the dependent repositories of this project which update them to a new version.
+
+		The dependent repositories and the files containing the version are defined in the 'dependents' section of the ` + config.ProjectConfigFileName + ` file.
+`)
+
+	stepUpdateDependentsExample = templates.Examples(`
+		# Update the dependent repositories to the version in the VERSION file
+		jx step update dependents
+
+		# Update the dependent repositories to a specific version
+		jx step update dependents --version 1.2.3
+	`)
+)
+
+// StepUpdateDependentsOptions contains the command line flags
+type StepUpdateDependentsOptions struct {
+	StepOptions
+
+	Dir     string
+	Version string
+	Name    string
+}
+
+// NewCmdStepUpdateDependents creates the command
+func NewCmdStepUpdateDependents(f Factory, in terminal.FileReader, out terminal.FileWriter, errOut io.Writer) *cobra.Command {
+	options := &StepUpdateDependentsOptions{
+		StepOptions: StepOptions{
+			CommonOptions: CommonOptions{
+				Factory: f,
+				In:      in,
+				Out:     out,
+				Err:     errOut,
+			},
+		},
+	}
+
+	cmd := &cobra.Command{
+		Use:     "dependents",
+		Short:   "Creates pull requests on the dependent repositories of this project which update them to a new version",
+		Long:    stepUpdateDependentsLong,
+		Example: stepUpdateDependentsExample,
+		Run: func(cmd *cobra.Command, args []string) {
+			options.Cmd = cmd
+			options.Args = args
+			err := options.Run()
+			CheckErr(err)
+		},
+	}
+	options.addCommonFlags(cmd)
+
+	cmd.Flags().StringVarP(&options.Dir, "dir", "d", "", "The directory of the project containing the "+config.ProjectConfigFileName+" file")
+	cmd.Flags().StringVarP(&options.Version, "version", "v", "", "The new version. Defaults to the contents of the VERSION file")
+	cmd.Flags().StringVarP(&options.Name, "name", "n", "", "The name of this project used in the pull requests. Defaults to the name of the directory")
+	return cmd
+}
+
+// Run implements this command
+func (o *StepUpdateDependentsOptions) Run() error {
+	dir := o.Dir
+	if dir == "" {
+		var err error
+		dir, err = os.Getwd()
+		if err != nil {
+			return err
+		}
+	}
+	pc, _, err := config.LoadProjectConfig(dir)
+	if err != nil {
+		return err
+	}
+	if len(pc.Dependents) == 0 {
+		log.Infof("No dependents defined in %s\n", config.ProjectConfigFileName)
+		return nil
+	}
+	version := o.Version
+	if version == "" {
+		data, err := ioutil.ReadFile(filepath.Join(dir, "VERSION"))
+		if err != nil {
+			return errors.Wrap(err, "no --version specified and failed to read the VERSION file")
+		}
+		version = strings.TrimSpace(string(data))
+	}
+	name := o.Name
+	if name == "" {
+		name = filepath.Base(dir)
+	}
+	for _, dependent := range pc.Dependents {
+		if dependent == nil {
+			continue
+		}
+		err = o.updateDependent(dependent, name, version)
+		if err != nil {
+			return errors.Wrapf(err, "failed to update dependent repository %s", dependent.GitURL)
+		}
+	}
+	return nil
+}
+
+func (o *StepUpdateDependentsOptions) updateDependent(dependent *config.DependentConfig, name string, version string) error {
+	if dependent.GitURL == "" {
+		return util.MissingOption("gitUrl")
+	}
+	gitInfo, err := gits.ParseGitURL(dependent.GitURL)
+	if err != nil {
+		return err
+	}
+	dir, err := util.CreateWorkspace("jx-dependent-")
+	if err != nil {
+		return err
+	}
+	defer os.RemoveAll(dir)
+
+	base := dependent.Branch
+	if base == "" {
+		base = "master"
+	}
+	err = o.Git().Clone(dependent.GitURL, dir)
+	if err != nil {
+		return err
+	}
+	if base != "master" {
+		err = o.Git().Checkout(dir, base)
+		if err != nil {
+			return err
+		}
+	}
+	branchName := o.Git().ConvertToValidBranchName("update-" + name + "-" + version)
+	err = o.Git().CreateBranch(dir, branchName)
+	if err != nil {
+		return err
+	}
+	err = o.Git().Checkout(dir, branchName)
+	if err != nil {
+		return err
+	}
+
+	changed := false
+	for _, f := range dependent.Files {
+		fileName := filepath.Join(dir, f.Path)
+		data, err := ioutil.ReadFile(fileName)
+		if err != nil {
+			return errors.Wrapf(err, "failed to read file %s", f.Path)
+		}
+		text, fileChanged, err := f.UpdateVersion(string(data), version)
+		if err != nil {
+			return err
+		}
+		if fileChanged {
+			err = ioutil.WriteFile(fileName, []byte(text), DefaultWritePermissions)
+			if err != nil {
+				return err
+			}
+			changed = true
+		}
+	}
+	if !changed {
+		log.Infof("Dependent repository %s is already on version %s\n", util.ColorInfo(dependent.GitURL), util.ColorInfo(version))
+		return nil
+	}
+
+	title := fmt.Sprintf("chore: update %s to %s", name, version)
+	err = o.Git().Add(dir, "*")
+	if err != nil {
+		return err
+	}
+	err = o.Git().CommitDir(dir, title)
+	if err != nil {
+		return err
+	}
+	err = o.Git().Push(dir)
+	if err != nil {
+		return err
+	}
+
+	authConfigSvc, err := o.CreateGitAuthConfigService()
+	if err != nil {
+		return err
+	}
+	gitKind, err := o.GitServerKind(gitInfo)
+	if err != nil {
+		return err
+	}
+	provider, err := gitInfo.PickOrCreateProvider(authConfigSvc, "user name to submit the Pull Request", o.BatchMode, gitKind, o.Git(), o.In, o.Out, o.Err)
+	if err != nil {
+		return err
+	}
+	pr, err := provider.CreatePullRequest(&gits.GitPullRequestArguments{
+		GitRepositoryInfo: gitInfo,
+		Title:             title,
+		Body:              fmt.Sprintf("Updates %s to version %s", name, version),
+		Base:              base,
+		Head:              branchName,
+	})
+	if err != nil {
+		return err
+	}
+	log.Infof("Created Pull Request: %s\n", util.ColorInfo(pr.URL))
+	return nil
+}
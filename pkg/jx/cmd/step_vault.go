@@ -0,0 +1,230 @@
+package cmd
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/jenkins-x/jx/pkg/jx/cmd/templates"
+	"github.com/jenkins-x/jx/pkg/log"
+	"github.com/jenkins-x/jx/pkg/util"
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+	"gopkg.in/AlecAivazis/survey.v1/terminal"
+)
+
+const (
+	// vaultReferencePrefix the prefix of the environment variable values which reference a secret in Vault
+	vaultReferencePrefix = "vault:"
+
+	// serviceAccountTokenFile the token of the service account of the pod used to login to Vault
+	serviceAccountTokenFile = "/var/run/secrets/kubernetes.io/serviceaccount/token"
+
+	// defaultVaultSecretMode the permissions of the secret files which only the user writing them can read
+	defaultVaultSecretMode = "0400"
+)
+
+var (
+	stepVaultLong = templates.LongDesc(`
+		Reads secrets from Vault and writes each of them to a file in a directory so that the steps of a build can use them without the secrets being stored in the build.
+
+		The secrets are specified as NAME=path#key where path is the path of the secret in Vault and key is the key of the value to read. The token to read them is taken from $VAULT_TOKEN or otherwise obtained by logging into Vault with the service account of the pod.
+
+		The secret files can only be read by the user writing them unless other permissions are given with --mode, such as when the steps using them run as other users.
+`)
+
+	stepVaultExample = templates.Examples(`
+		# write the password of the database of myapp to /vault/secrets/DB_PASSWORD
+		jx step vault --dir /vault/secrets --secret DB_PASSWORD=secret/myapp/db#password
+	`)
+)
+
+// StepVaultOptions contains the command line flags
+type StepVaultOptions struct {
+	StepOptions
+
+	Dir     string
+	Secrets []string
+	Address string
+	Role    string
+	Token   string
+	Mode    string
+}
+
+// NewCmdStepVault creates the command
+func NewCmdStepVault(f Factory, in terminal.FileReader, out terminal.FileWriter, errOut io.Writer) *cobra.Command {
+	options := &StepVaultOptions{
+		StepOptions: StepOptions{
+			CommonOptions: CommonOptions{
+				Factory: f,
+				In:      in,
+				Out:     out,
+				Err:     errOut,
+			},
+		},
+	}
+
+	cmd := &cobra.Command{
+		Use:     "vault",
+		Short:   "Reads secrets from Vault into files used by the steps of a build",
+		Long:    stepVaultLong,
+		Example: stepVaultExample,
+		Run: func(cmd *cobra.Command, args []string) {
+			options.Cmd = cmd
+			options.Args = args
+			err := options.Run()
+			CheckErr(err)
+		},
+	}
+	options.addCommonFlags(cmd)
+
+	cmd.Flags().StringVarP(&options.Dir, "dir", "d", "", "The directory to write the secrets to")
+	cmd.Flags().StringArrayVarP(&options.Secrets, "secret", "s", nil, "The secrets to read as NAME=path#key")
+	cmd.Flags().StringVarP(&options.Address, "addr", "", os.Getenv("VAULT_ADDR"), "The address of Vault. Defaults to $VAULT_ADDR")
+	cmd.Flags().StringVarP(&options.Role, "role", "", "jx-build", "The Vault role to login as when there is no token")
+	cmd.Flags().StringVarP(&options.Mode, "mode", "", defaultVaultSecretMode, "The octal permissions of the secret files")
+	return cmd
+}
+
+// Run implements this command
+func (o *StepVaultOptions) Run() error {
+	if o.Dir == "" {
+		return util.MissingOption("dir")
+	}
+	if o.Address == "" {
+		return util.MissingOption("addr")
+	}
+	if len(o.Secrets) == 0 {
+		return util.MissingOption("secret")
+	}
+	modeText := o.Mode
+	if modeText == "" {
+		modeText = defaultVaultSecretMode
+	}
+	mode, err := strconv.ParseUint(modeText, 8, 32)
+	if err != nil {
+		return util.InvalidOptionError("mode", o.Mode, err)
+	}
+	token := o.Token
+	if token == "" {
+		token = os.Getenv("VAULT_TOKEN")
+	}
+	if token == "" {
+		token, err = o.login()
+		if err != nil {
+			return err
+		}
+	}
+	err = os.MkdirAll(o.Dir, DefaultWritePermissions)
+	if err != nil {
+		return err
+	}
+	for _, secret := range o.Secrets {
+		values := strings.SplitN(secret, "=", 2)
+		if len(values) != 2 {
+			return util.InvalidOptionf("secret", secret, "the secret must be specified as NAME=path#key")
+		}
+		path, key, err := parseVaultReference(vaultReferencePrefix + values[1])
+		if err != nil {
+			return util.InvalidOptionError("secret", secret, err)
+		}
+		value, err := o.readSecret(token, path, key)
+		if err != nil {
+			return err
+		}
+		err = ioutil.WriteFile(filepath.Join(o.Dir, values[0]), []byte(value), os.FileMode(mode))
+		if err != nil {
+			return err
+		}
+		log.Infof("Read secret %s from %s\n", util.ColorInfo(values[0]), util.ColorInfo(path))
+	}
+	return nil
+}
+
+// login logs into Vault with the token of the service account of the pod and returns the Vault token
+func (o *StepVaultOptions) login() (string, error) {
+	jwt, err := ioutil.ReadFile(serviceAccountTokenFile)
+	if err != nil {
+		return "", errors.Wrap(err, "failed to read the service account token to login to Vault as there is no $VAULT_TOKEN")
+	}
+	body, err := json.Marshal(map[string]string{
+		"role": o.Role,
+		"jwt":  string(jwt),
+	})
+	if err != nil {
+		return "", err
+	}
+	result := struct {
+		Auth struct {
+			ClientToken string `json:"client_token"`
+		} `json:"auth"`
+	}{}
+	err = o.vaultRequest(http.MethodPost, "auth/kubernetes/login", "", bytes.NewReader(body), &result)
+	if err != nil {
+		return "", errors.Wrapf(err, "failed to login to Vault as role %s", o.Role)
+	}
+	return result.Auth.ClientToken, nil
+}
+
+// readSecret reads the value of the key of the secret at the given path supporting both versions of the key value
+// secrets engine
+func (o *StepVaultOptions) readSecret(token string, path string, key string) (string, error) {
+	result := struct {
+		Data map[string]interface{} `json:"data"`
+	}{}
+	err := o.vaultRequest(http.MethodGet, path, token, nil, &result)
+	if err != nil {
+		return "", errors.Wrapf(err, "failed to read secret %s from Vault", path)
+	}
+	data := result.Data
+	if nested, ok := data["data"].(map[string]interface{}); ok {
+		if _, ok := data["metadata"]; ok {
+			data = nested
+		}
+	}
+	value, ok := data[key]
+	if !ok {
+		return "", fmt.Errorf("The secret %s in Vault has no key %s", path, key)
+	}
+	return fmt.Sprintf("%v", value), nil
+}
+
+func (o *StepVaultOptions) vaultRequest(method string, path string, token string, body io.Reader, result interface{}) error {
+	req, err := http.NewRequest(method, strings.TrimSuffix(o.Address, "/")+"/v1/"+strings.TrimPrefix(path, "/"), body)
+	if err != nil {
+		return err
+	}
+	if token != "" {
+		req.Header.Set("X-Vault-Token", token)
+	}
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	data, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return err
+	}
+	if resp.StatusCode >= 300 {
+		return fmt.Errorf("status %s: %s", resp.Status, strings.TrimSpace(string(data)))
+	}
+	return json.Unmarshal(data, result)
+}
+
+// parseVaultReference parses a reference such as 'vault:secret/myapp/db#password' into the path and key of the secret
+func parseVaultReference(reference string) (string, string, error) {
+	text := strings.TrimPrefix(reference, vaultReferencePrefix)
+	idx := strings.LastIndex(text, "#")
+	if !strings.HasPrefix(reference, vaultReferencePrefix) || idx <= 0 || idx == len(text)-1 {
+		return "", "", fmt.Errorf("The Vault reference %s must be of the form %spath#key", reference, vaultReferencePrefix)
+	}
+	return text[0:idx], text[idx+1:], nil
+}
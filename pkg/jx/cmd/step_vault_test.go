@@ -0,0 +1,74 @@
+package cmd_test
+
+import (
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/jenkins-x/jx/pkg/jx/cmd"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestStepVault(t *testing.T) {
+	t.Parallel()
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("X-Vault-Token") != "s.mytoken" {
+			w.WriteHeader(http.StatusForbidden)
+			return
+		}
+		switch r.URL.Path {
+		case "/v1/secret/data/myapp/db":
+			w.Write([]byte(`{"data": {"data": {"password": "s3cret"}, "metadata": {"version": 1}}}`))
+		case "/v1/kv/myapp/api":
+			w.Write([]byte(`{"data": {"token": "abc"}}`))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	testDir, err := ioutil.TempDir("", "test-step-vault")
+	assert.NoError(t, err)
+	defer os.RemoveAll(testDir)
+
+	o := &cmd.StepVaultOptions{
+		Dir:     testDir,
+		Address: server.URL,
+		Token:   "s.mytoken",
+		Secrets: []string{"secret-0=secret/data/myapp/db#password", "secret-1=kv/myapp/api#token"},
+	}
+	err = o.Run()
+	assert.NoError(t, err)
+
+	data, err := ioutil.ReadFile(filepath.Join(testDir, "secret-0"))
+	assert.NoError(t, err)
+	assert.Equal(t, "s3cret", string(data))
+	data, err = ioutil.ReadFile(filepath.Join(testDir, "secret-1"))
+	assert.NoError(t, err)
+	assert.Equal(t, "abc", string(data))
+	info, err := os.Stat(filepath.Join(testDir, "secret-0"))
+	if assert.NoError(t, err) {
+		assert.Equal(t, os.FileMode(0400), info.Mode().Perm(), "only the user reading the secrets should be able to read them by default")
+	}
+
+	o.Mode = "0444"
+	o.Secrets = []string{"secret-3=kv/myapp/api#token"}
+	err = o.Run()
+	assert.NoError(t, err)
+	info, err = os.Stat(filepath.Join(testDir, "secret-3"))
+	if assert.NoError(t, err) {
+		assert.Equal(t, os.FileMode(0444), info.Mode().Perm())
+	}
+
+	o.Mode = "rw"
+	err = o.Run()
+	assert.Error(t, err)
+	o.Mode = ""
+
+	o.Secrets = []string{"secret-2=kv/myapp/api#missing"}
+	err = o.Run()
+	assert.Error(t, err)
+}
@@ -0,0 +1,102 @@
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"io/ioutil"
+
+	"github.com/ghodss/yaml"
+	"github.com/jenkins-x/jx/pkg/jx/cmd/templates"
+	"github.com/jenkins-x/jx/pkg/log"
+	"github.com/jenkins-x/jx/pkg/util"
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+	"gopkg.in/AlecAivazis/survey.v1/terminal"
+)
+
+// StepVerifyBuildOptions contains the command line flags
+type StepVerifyBuildOptions struct {
+	StepOptions
+
+	PublicKeys         string
+	ApprovedBuildPacks []string
+}
+
+var (
+	stepVerifyBuildLong = templates.LongDesc(`
+		Verifies the signatures of builds generated by 'jx step create build --sign-key' before they are applied.
+
+		Fails if any build is unsigned, was modified after it was signed, was signed by an unknown key or was generated from a build pack which is not approved.
+`)
+
+	stepVerifyBuildExample = templates.Examples(`
+		# verify a signed build before applying it
+		jx step verify build --keys release-keys.asc build-release.yml && kubectl apply -f build-release.yml
+
+		# only allow builds generated from the given build pack repository
+		jx step verify build --keys release-keys.asc --build-pack https://github.com/jenkins-x/draft-packs.git build-release.yml
+	`)
+)
+
+// NewCmdStepVerifyBuild creates the command
+func NewCmdStepVerifyBuild(f Factory, in terminal.FileReader, out terminal.FileWriter, errOut io.Writer) *cobra.Command {
+	options := &StepVerifyBuildOptions{
+		StepOptions: StepOptions{
+			CommonOptions: CommonOptions{
+				Factory: f,
+				In:      in,
+				Out:     out,
+				Err:     errOut,
+			},
+		},
+	}
+
+	cmd := &cobra.Command{
+		Use:     "build [files]",
+		Short:   "Verifies the signatures of generated builds",
+		Long:    stepVerifyBuildLong,
+		Example: stepVerifyBuildExample,
+		Run: func(cmd *cobra.Command, args []string) {
+			options.Cmd = cmd
+			options.Args = args
+			err := options.Run()
+			CheckErr(err)
+		},
+	}
+	options.addCommonFlags(cmd)
+
+	cmd.Flags().StringVarP(&options.PublicKeys, "keys", "k", "", "The file containing the ASCII armored PGP public keys trusted to sign builds")
+	cmd.Flags().StringArrayVarP(&options.ApprovedBuildPacks, "build-pack", "", []string{}, "The git URL of an approved build pack optionally followed by '@' and a commit. If not specified any build pack is allowed")
+	return cmd
+}
+
+// Run implements this command
+func (o *StepVerifyBuildOptions) Run() error {
+	if o.PublicKeys == "" {
+		return util.MissingOption("keys")
+	}
+	if len(o.Args) == 0 {
+		return fmt.Errorf("Missing argument: the build files to verify")
+	}
+	publicKeys, err := ioutil.ReadFile(o.PublicKeys)
+	if err != nil {
+		return errors.Wrapf(err, "failed to read the public keys %s", o.PublicKeys)
+	}
+	for _, fileName := range o.Args {
+		data, err := ioutil.ReadFile(fileName)
+		if err != nil {
+			return errors.Wrapf(err, "failed to read build %s", fileName)
+		}
+		build := &Build{}
+		err = yaml.Unmarshal(data, build)
+		if err != nil {
+			return errors.Wrapf(err, "failed to parse build %s", fileName)
+		}
+		signer, err := VerifyBuild(build, publicKeys, o.ApprovedBuildPacks)
+		if err != nil {
+			return errors.Wrapf(err, "verification of %s failed", fileName)
+		}
+		log.Infof("Verified %s signed by %s\n", util.ColorInfo(fileName), util.ColorInfo(signer))
+	}
+	return nil
+}
@@ -0,0 +1,85 @@
+package cmd_test
+
+import (
+	"bytes"
+	"io/ioutil"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/jenkins-x/jx/pkg/config"
+	"github.com/jenkins-x/jx/pkg/gits"
+	"github.com/jenkins-x/jx/pkg/helm"
+	"github.com/jenkins-x/jx/pkg/jx/cmd"
+	"github.com/jenkins-x/jx/pkg/util"
+	"github.com/stretchr/testify/assert"
+	"golang.org/x/crypto/openpgp"
+	"golang.org/x/crypto/openpgp/armor"
+)
+
+func TestStepVerifyBuild(t *testing.T) {
+	t.Parallel()
+	testDir, err := ioutil.TempDir("", "test-step-verify-build")
+	assert.NoError(t, err)
+
+	entity, err := openpgp.NewEntity("release", "", "release@example.com", nil)
+	assert.NoError(t, err)
+	var privateKey, publicKey bytes.Buffer
+	w, err := armor.Encode(&privateKey, openpgp.PrivateKeyType, nil)
+	assert.NoError(t, err)
+	assert.NoError(t, entity.SerializePrivate(w, nil))
+	assert.NoError(t, w.Close())
+	w, err = armor.Encode(&publicKey, openpgp.PublicKeyType, nil)
+	assert.NoError(t, err)
+	assert.NoError(t, entity.Serialize(w))
+	assert.NoError(t, w.Close())
+
+	privateKeyFile := filepath.Join(testDir, "private.asc")
+	publicKeyFile := filepath.Join(testDir, "public.asc")
+	assert.NoError(t, ioutil.WriteFile(privateKeyFile, privateKey.Bytes(), util.DefaultWritePermissions))
+	assert.NoError(t, ioutil.WriteFile(publicKeyFile, publicKey.Bytes(), util.DefaultWritePermissions))
+
+	projectYaml := `buildPackGitURL: https://github.com/myorg/my-packs.git
+buildPackGitRef: v1.0.0
+builds:
+  - kind: release
+    build:
+      steps:
+        - name: build
+          image: golang:1.11
+`
+	err = ioutil.WriteFile(filepath.Join(testDir, config.ProjectConfigFileName), []byte(projectYaml), util.DefaultWritePermissions)
+	assert.NoError(t, err)
+
+	o := &cmd.StepCreateBuildOptions{}
+	cmd.ConfigureTestOptionsWithResources(&o.CommonOptions, nil, nil, gits.NewGitCLI(), helm.NewHelmCLI("helm", helm.V2, testDir, true))
+	o.Dir = testDir
+	o.OutputDir = testDir
+	o.SignKey = privateKeyFile
+	o.NoAudit = true
+
+	err = o.Run()
+	assert.NoError(t, err)
+
+	buildFile := filepath.Join(testDir, "build-release.yml")
+	verify := func(approvedBuildPacks ...string) error {
+		v := &cmd.StepVerifyBuildOptions{
+			PublicKeys:         publicKeyFile,
+			ApprovedBuildPacks: approvedBuildPacks,
+		}
+		cmd.ConfigureTestOptionsWithResources(&v.CommonOptions, nil, nil, gits.NewGitCLI(), helm.NewHelmCLI("helm", helm.V2, testDir, true))
+		v.Args = []string{buildFile}
+		return v.Run()
+	}
+
+	assert.NoError(t, verify())
+	assert.NoError(t, verify("https://github.com/myorg/my-packs.git"))
+	assert.NoError(t, verify("https://github.com/myorg/my-packs.git@v1.0.0"))
+	assert.Error(t, verify("https://github.com/jenkins-x/draft-packs.git"), "should not allow an unapproved build pack")
+
+	data, err := ioutil.ReadFile(buildFile)
+	assert.NoError(t, err)
+	tampered := strings.Replace(string(data), "golang:1.11", "evil:latest", -1)
+	assert.NoError(t, ioutil.WriteFile(buildFile, []byte(tampered), util.DefaultWritePermissions))
+	assert.Error(t, verify(), "should not verify a modified build")
+}
@@ -0,0 +1,167 @@
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+
+	"github.com/jenkins-x/jx/pkg/config"
+	"github.com/jenkins-x/jx/pkg/jx/cmd/templates"
+	"github.com/jenkins-x/jx/pkg/log"
+	"github.com/jenkins-x/jx/pkg/util"
+	"github.com/spf13/cobra"
+	"gopkg.in/AlecAivazis/survey.v1/terminal"
+)
+
+// StepVerifyPipelinesOptions contains the command line flags
+type StepVerifyPipelinesOptions struct {
+	StepOptions
+
+	Dir          string
+	PipelinesDir string
+}
+
+var (
+	stepVerifyPipelinesLong = templates.LongDesc(`
+		Verifies the builds committed by 'jx step create build --commit' are up to date.
+
+		The builds are regenerated and compared to the committed builds. If they differ the differences are displayed and the command fails so the committed builds can be regenerated.
+`)
+
+	stepVerifyPipelinesExample = templates.Examples(`
+		# verify the committed builds are up to date
+		jx step verify pipelines
+
+		# verify the builds committed in a different directory are up to date
+		jx step verify pipelines --pipelines-dir builds
+	`)
+)
+
+// NewCmdStepVerifyPipelines creates the command
+func NewCmdStepVerifyPipelines(f Factory, in terminal.FileReader, out terminal.FileWriter, errOut io.Writer) *cobra.Command {
+	options := &StepVerifyPipelinesOptions{
+		StepOptions: StepOptions{
+			CommonOptions: CommonOptions{
+				Factory: f,
+				In:      in,
+				Out:     out,
+				Err:     errOut,
+			},
+		},
+	}
+
+	cmd := &cobra.Command{
+		Use:     "pipelines",
+		Short:   "Verifies the committed builds are up to date",
+		Long:    stepVerifyPipelinesLong,
+		Example: stepVerifyPipelinesExample,
+		Run: func(cmd *cobra.Command, args []string) {
+			options.Cmd = cmd
+			options.Args = args
+			err := options.Run()
+			CheckErr(err)
+		},
+	}
+	options.addCommonFlags(cmd)
+
+	cmd.Flags().StringVarP(&options.Dir, "dir", "d", "", "The directory of the project")
+	cmd.Flags().StringVarP(&options.PipelinesDir, "pipelines-dir", "", "", fmt.Sprintf("The directory of the committed builds relative to the project. Defaults to the pipelinesDir of %s or %s", config.ProjectConfigFileName, config.DefaultPipelinesDir))
+	return cmd
+}
+
+// Run implements this command
+func (o *StepVerifyPipelinesOptions) Run() error {
+	projectConfig, _, err := config.LoadProjectConfig(o.Dir)
+	if err != nil {
+		return err
+	}
+	pipelinesDir := o.PipelinesDir
+	if pipelinesDir == "" {
+		pipelinesDir = projectConfig.PipelinesDir
+	}
+	if pipelinesDir == "" {
+		pipelinesDir = config.DefaultPipelinesDir
+	}
+	committedDir := filepath.Join(o.Dir, pipelinesDir)
+
+	tmpDir, err := util.CreateWorkspace("jx-verify-pipelines-")
+	if err != nil {
+		return err
+	}
+	defer os.RemoveAll(tmpDir)
+	so := &StepCreateBuildOptions{
+		StepOptions: o.StepOptions,
+		Dir:         o.Dir,
+		OutputDir:   tmpDir,
+		NoAudit:     true,
+	}
+	err = so.Run()
+	if err != nil {
+		return err
+	}
+
+	stale, err := diffPipelines(committedDir, tmpDir)
+	if err != nil {
+		return err
+	}
+	if len(stale) == 0 {
+		log.Infof("The committed builds in %s are up to date\n", util.ColorInfo(pipelinesDir))
+		return nil
+	}
+	for _, diff := range stale {
+		log.Info(diff)
+	}
+	return fmt.Errorf("%d of the committed builds in %s are out of date. Run 'jx step create build --commit' to regenerate them", len(stale), pipelinesDir)
+}
+
+// diffPipelines compares the committed build files with the generated build files returning the changed lines of each
+// file which differs, is missing or is no longer generated
+func diffPipelines(committedDir string, generatedDir string) ([]string, error) {
+	contents := func(dir string) (map[string]string, error) {
+		answer := map[string]string{}
+		fileNames, err := filepath.Glob(filepath.Join(dir, "build-*.yml"))
+		if err != nil {
+			return answer, err
+		}
+		for _, fileName := range fileNames {
+			data, err := ioutil.ReadFile(fileName)
+			if err != nil {
+				return answer, err
+			}
+			answer[filepath.Base(fileName)] = string(data)
+		}
+		return answer, nil
+	}
+	committed, err := contents(committedDir)
+	if err != nil {
+		return nil, err
+	}
+	generated, err := contents(generatedDir)
+	if err != nil {
+		return nil, err
+	}
+	names := []string{}
+	for name := range committed {
+		names = append(names, name)
+	}
+	for name := range generated {
+		if _, ok := committed[name]; !ok {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+
+	answer := []string{}
+	for _, name := range names {
+		if committed[name] == generated[name] {
+			continue
+		}
+		fileName := filepath.Join(committedDir, name)
+		diff := fmt.Sprintf("--- %s (committed)\n+++ %s (generated)\n%s\n", fileName, fileName, util.DiffLines(committed[name], generated[name]))
+		answer = append(answer, diff)
+	}
+	return answer, nil
+}
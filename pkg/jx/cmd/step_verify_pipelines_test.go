@@ -0,0 +1,52 @@
+package cmd_test
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/jenkins-x/jx/pkg/config"
+	"github.com/jenkins-x/jx/pkg/gits"
+	"github.com/jenkins-x/jx/pkg/helm"
+	"github.com/jenkins-x/jx/pkg/jx/cmd"
+	"github.com/jenkins-x/jx/pkg/util"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestStepVerifyPipelines(t *testing.T) {
+	t.Parallel()
+	testDir, err := ioutil.TempDir("", "test-step-verify-pipelines")
+	assert.NoError(t, err)
+	defer os.RemoveAll(testDir)
+
+	projectYaml := `builds:
+  - kind: release
+    build:
+      steps:
+        - name: build
+          image: golang:1.11
+`
+	projectFile := filepath.Join(testDir, config.ProjectConfigFileName)
+	err = ioutil.WriteFile(projectFile, []byte(projectYaml), util.DefaultWritePermissions)
+	assert.NoError(t, err)
+
+	co := &cmd.StepCreateBuildOptions{}
+	cmd.ConfigureTestOptionsWithResources(&co.CommonOptions, nil, nil, gits.NewGitCLI(), helm.NewHelmCLI("helm", helm.V2, testDir, true))
+	co.Dir = testDir
+	co.OutputDir = filepath.Join(testDir, config.DefaultPipelinesDir)
+	co.NoAudit = true
+	err = co.Run()
+	assert.NoError(t, err)
+
+	o := &cmd.StepVerifyPipelinesOptions{}
+	cmd.ConfigureTestOptionsWithResources(&o.CommonOptions, nil, nil, gits.NewGitCLI(), helm.NewHelmCLI("helm", helm.V2, testDir, true))
+	o.Dir = testDir
+	err = o.Run()
+	assert.NoError(t, err)
+
+	err = ioutil.WriteFile(projectFile, []byte(projectYaml+"        - name: test\n          image: golang:1.11\n"), util.DefaultWritePermissions)
+	assert.NoError(t, err)
+	err = o.Run()
+	assert.Error(t, err, "the committed build should be out of date")
+}
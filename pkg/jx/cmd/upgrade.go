@@ -58,6 +58,7 @@ func NewCmdUpgrade(f Factory, in terminal.FileReader, out terminal.FileWriter, e
 	cmd.AddCommand(NewCmdUpgradeAddons(f, in, out, errOut))
 	cmd.AddCommand(NewCmdUpgradeCLI(f, in, out, errOut))
 	cmd.AddCommand(NewCmdUpgradeBinaries(f, in, out, errOut))
+	cmd.AddCommand(NewCmdUpgradeBuilders(f, in, out, errOut))
 	cmd.AddCommand(NewCmdUpgradeCluster(f, in, out, errOut))
 	cmd.AddCommand(NewCmdUpgradeIngress(f, in, out, errOut))
 	cmd.AddCommand(NewCmdUpgradePlatform(f, in, out, errOut))
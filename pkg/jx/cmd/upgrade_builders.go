@@ -0,0 +1,259 @@
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+
+	"github.com/jenkins-x/jx/pkg/config"
+	"github.com/jenkins-x/jx/pkg/jx/cmd/templates"
+	"github.com/jenkins-x/jx/pkg/kube"
+	"github.com/jenkins-x/jx/pkg/log"
+	"github.com/jenkins-x/jx/pkg/util"
+	"github.com/jenkins-x/jx/pkg/versionstream"
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+	"gopkg.in/AlecAivazis/survey.v1/terminal"
+	corev1 "k8s.io/api/core/v1"
+)
+
+var (
+	upgradeBuildersLong = templates.LongDesc(`
+		Upgrades the builder images used by the pod templates of the current team to the versions in the version stream.
+
+		The version stream is a git repository which contains the stable version of each builder image in a file such as 'docker/jenkinsxio/builder-maven.yml'
+
+		With --regenerate the builds of the project in --dir are regenerated into its pipelines directory and committed, as 'jx step create build --commit' does, if its build pack uses one of the upgraded pod templates.
+`)
+
+	upgradeBuildersExample = templates.Examples(`
+		# Displays any outdated builder images in the pod templates
+		jx upgrade builders --dry-run
+
+		# Upgrades the builder images in the pod templates
+		jx upgrade builders
+
+		# Upgrades the builder images using a custom version stream
+		jx upgrade builders --versions-repo https://github.com/myorg/jenkins-x-versions.git
+
+		# Upgrades the builder images then regenerates and commits the builds of the project in the current directory
+		jx upgrade builders --regenerate
+	`)
+)
+
+// UpgradeBuildersOptions the options for the upgrade builders command
+type UpgradeBuildersOptions struct {
+	CreateOptions
+
+	VersionsRepository string
+	VersionsRef        string
+	VersionsDir        string
+	DryRun             bool
+	Regenerate         bool
+	Dir                string
+}
+
+// outdatedBuilder a builder image in a pod template which is older than the version stream
+type outdatedBuilder struct {
+	PodTemplate string
+	Container   string
+	Image       string
+	Version     string
+	Latest      string
+}
+
+// NewCmdUpgradeBuilders defines the command
+func NewCmdUpgradeBuilders(f Factory, in terminal.FileReader, out terminal.FileWriter, errOut io.Writer) *cobra.Command {
+	options := &UpgradeBuildersOptions{
+		CreateOptions: CreateOptions{
+			CommonOptions: CommonOptions{
+				Factory: f,
+				In:      in,
+				Out:     out,
+				Err:     errOut,
+			},
+		},
+	}
+
+	cmd := &cobra.Command{
+		Use:     "builders",
+		Short:   "Upgrades the builder images used by the pod templates if there are newer versions in the version stream",
+		Long:    upgradeBuildersLong,
+		Example: upgradeBuildersExample,
+		Aliases: []string{"builder"},
+		Run: func(cmd *cobra.Command, args []string) {
+			options.Cmd = cmd
+			options.Args = args
+			err := options.Run()
+			CheckErr(err)
+		},
+	}
+	options.addCommonFlags(cmd)
+
+	cmd.Flags().StringVarP(&options.VersionsRepository, "versions-repo", "", versionstream.DefaultVersionsURL, "The git repository of the version stream")
+	cmd.Flags().StringVarP(&options.VersionsRef, "versions-ref", "", "master", "The git reference (branch, tag, sha) of the version stream")
+	cmd.Flags().StringVarP(&options.VersionsDir, "versions-dir", "", "", "A local checkout of the version stream to use instead of cloning the versions repository")
+	cmd.Flags().BoolVarP(&options.DryRun, "dry-run", "", false, "Only displays the outdated builder images without upgrading them")
+	cmd.Flags().BoolVarP(&options.Regenerate, "regenerate", "", false, "Regenerates and commits the builds of the project in --dir if its build pack uses one of the upgraded pod templates")
+	cmd.Flags().StringVarP(&options.Dir, "dir", "d", "", "The directory of the project whose builds are regenerated with --regenerate. Defaults to the current directory")
+	return cmd
+}
+
+// Run implements the command
+func (o *UpgradeBuildersOptions) Run() error {
+	dir := o.VersionsDir
+	if dir == "" {
+		tmpDir, err := ioutil.TempDir("", "jx-versions-")
+		if err != nil {
+			return err
+		}
+		defer os.RemoveAll(tmpDir)
+
+		err = o.Git().Clone(o.VersionsRepository, tmpDir)
+		if err != nil {
+			return errors.Wrapf(err, "failed to clone the version stream %s", o.VersionsRepository)
+		}
+		if o.VersionsRef != "" {
+			err = o.Git().Checkout(tmpDir, o.VersionsRef)
+			if err != nil {
+				return errors.Wrapf(err, "failed to checkout %s of the version stream %s", o.VersionsRef, o.VersionsRepository)
+			}
+		}
+		dir = tmpDir
+	}
+
+	kubeClient, ns, err := o.KubeClientAndDevNamespace()
+	if err != nil {
+		return err
+	}
+	podTemplates, err := kube.LoadUnresolvedPodTemplates(kubeClient, ns)
+	if err != nil {
+		return err
+	}
+
+	outdated, err := findOutdatedBuilders(podTemplates, dir)
+	if err != nil {
+		return err
+	}
+	if len(outdated) == 0 {
+		log.Infof("The builder images in the pod templates are up to date\n")
+		return nil
+	}
+
+	table := o.CreateTable()
+	table.AddRow("POD TEMPLATE", "CONTAINER", "IMAGE", "VERSION", "LATEST")
+	for _, b := range outdated {
+		table.AddRow(b.PodTemplate, b.Container, b.Image, b.Version, util.ColorInfo(b.Latest))
+	}
+	table.Render()
+
+	if o.DryRun {
+		log.Warnf("%d builder images are outdated\n", len(outdated))
+		return nil
+	}
+	if !o.BatchMode && !util.Confirm(fmt.Sprintf("Upgrade %d builder images?", len(outdated)), true, "Updates the images of the pod templates to the versions in the version stream", o.In, o.Out, o.Err) {
+		return nil
+	}
+	for _, b := range outdated {
+		pod := podTemplates[b.PodTemplate]
+		for i := range pod.Spec.Containers {
+			c := &pod.Spec.Containers[i]
+			if c.Name == b.Container {
+				c.Image = b.Image + ":" + b.Latest
+			}
+		}
+		err = kube.SavePodTemplate(kubeClient, ns, b.PodTemplate, pod)
+		if err != nil {
+			return err
+		}
+		log.Infof("Upgraded pod template %s container %s to %s\n", util.ColorInfo(b.PodTemplate), util.ColorInfo(b.Container), util.ColorInfo(b.Image+":"+b.Latest))
+	}
+	invalidatePodTemplatesCache(ns)
+	if o.Regenerate {
+		return o.regenerateBuilds(podTemplates, outdated)
+	}
+	log.Infof("Regenerate any builds using these pod templates via: %s\n", util.ColorInfo("jx step create build"))
+	return nil
+}
+
+// regenerateBuilds regenerates and commits the builds of the project in the directory if its build pack uses one of
+// the upgraded pod templates directly or via the pod templates it extends
+func (o *UpgradeBuildersOptions) regenerateBuilds(podTemplates map[string]*corev1.Pod, outdated []*outdatedBuilder) error {
+	dir := o.Dir
+	if dir == "" {
+		var err error
+		dir, err = os.Getwd()
+		if err != nil {
+			return err
+		}
+	}
+	projectConfig, _, err := config.LoadProjectConfig(dir)
+	if err != nil {
+		return err
+	}
+	used := extendedPodTemplateNames(podTemplates, projectConfig.BuildPack)
+	upgraded := false
+	for _, b := range outdated {
+		if util.StringArrayIndex(used, b.PodTemplate) >= 0 {
+			upgraded = true
+		}
+	}
+	if !upgraded {
+		log.Infof("The builds of %s do not use the upgraded pod templates\n", util.ColorInfo(dir))
+		return nil
+	}
+	so := &StepCreateBuildOptions{
+		StepOptions: StepOptions{
+			CommonOptions: o.CommonOptions,
+		},
+		Dir:     dir,
+		Commit:  true,
+		NoAudit: true,
+	}
+	err = so.Run()
+	if err != nil {
+		return errors.Wrapf(err, "failed to regenerate the builds of %s", dir)
+	}
+	log.Infof("Regenerated the builds of %s\n", util.ColorInfo(dir))
+	return nil
+}
+
+// extendedPodTemplateNames returns the name of the pod template followed by the names of the pod templates it extends
+func extendedPodTemplateNames(podTemplates map[string]*corev1.Pod, name string) []string {
+	names := []string{}
+	for name != "" && util.StringArrayIndex(names, name) < 0 {
+		names = append(names, name)
+		pod := podTemplates[name]
+		if pod == nil || pod.Annotations == nil {
+			break
+		}
+		name = pod.Annotations[kube.PodTemplateExtendsAnnotation]
+	}
+	return names
+}
+
+// findOutdatedBuilders returns the containers of the pod templates whose images are older than the version stream in
+// the given directory
+func findOutdatedBuilders(podTemplates map[string]*corev1.Pod, versionsDir string) ([]*outdatedBuilder, error) {
+	outdated := []*outdatedBuilder{}
+	for _, name := range sortedPodTemplateNames(podTemplates) {
+		for _, c := range podTemplates[name].Spec.Containers {
+			image, version := versionstream.SplitImageTag(c.Image)
+			latest, err := versionstream.LoadImageVersion(versionsDir, image)
+			if err != nil {
+				return outdated, err
+			}
+			if versionstream.IsNewerVersion(version, latest) {
+				outdated = append(outdated, &outdatedBuilder{
+					PodTemplate: name,
+					Container:   c.Name,
+					Image:       image,
+					Version:     version,
+					Latest:      latest,
+				})
+			}
+		}
+	}
+	return outdated, nil
+}
@@ -0,0 +1,179 @@
+package cmd_test
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/jenkins-x/jx/pkg/config"
+	"github.com/jenkins-x/jx/pkg/gits"
+	"github.com/jenkins-x/jx/pkg/jx/cmd"
+	"github.com/jenkins-x/jx/pkg/kube"
+	"github.com/jenkins-x/jx/pkg/util"
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+)
+
+func TestUpgradeBuildersRegenerate(t *testing.T) {
+	t.Parallel()
+	assertUpgradeBuildersRegenerate(t, "maven", map[string]string{
+		"maven": MavenBuildPackYaml,
+	})
+}
+
+func TestUpgradeBuildersRegenerateExtendedPodTemplate(t *testing.T) {
+	t.Parallel()
+	assertUpgradeBuildersRegenerate(t, "maven-custom", map[string]string{
+		"maven":        MavenBuildPackYaml,
+		"maven-custom": "extends: maven\nmetadata:\n  name: jenkins-maven-custom\n",
+	})
+}
+
+// assertUpgradeBuildersRegenerate asserts that the builds of a project using the build pack are regenerated once the
+// builder image of the maven pod template is upgraded
+func assertUpgradeBuildersRegenerate(t *testing.T, buildPack string, podTemplates map[string]string) {
+	testDir := newStepCreateBuildTestDir(t, "test-upgrade-builders-regenerate")
+	defer os.RemoveAll(testDir)
+	versionsDir := filepath.Join(testDir, "versions")
+	err := os.MkdirAll(filepath.Join(versionsDir, "docker", "jenkinsxio"), util.DefaultWritePermissions)
+	assert.NoError(t, err)
+	err = ioutil.WriteFile(filepath.Join(versionsDir, "docker", "jenkinsxio", "builder-maven.yml"), []byte("version: 0.0.500\n"), util.DefaultWritePermissions)
+	assert.NoError(t, err)
+
+	projectDir := filepath.Join(testDir, "myapp")
+	err = os.MkdirAll(projectDir, util.DefaultWritePermissions)
+	assert.NoError(t, err)
+	projectYaml := `buildPack: ` + buildPack + `
+builds:
+  - kind: release
+    build:
+      steps:
+        - name: build
+          args:
+          - mvn
+          - install
+`
+	writeStepCreateBuildProjectConfig(t, projectDir, projectYaml)
+	gitter := gits.NewGitCLI()
+	err = gitter.Init(projectDir)
+	assert.NoError(t, err)
+	for _, args := range [][]string{{"config", "user.name", "test"}, {"config", "user.email", "test@example.com"}} {
+		_, err = (&util.Command{Dir: projectDir, Name: "git", Args: args}).RunWithoutRetry()
+		assert.NoError(t, err)
+	}
+	err = gitter.Add(projectDir, "*")
+	assert.NoError(t, err)
+	err = gitter.CommitDir(projectDir, "initial import")
+	assert.NoError(t, err)
+
+	k8sObjects := []runtime.Object{
+		&corev1.ConfigMap{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      kube.ConfigMapJenkinsPodTemplates,
+				Namespace: "jx",
+			},
+			Data: podTemplates,
+		},
+	}
+	so := newStepCreateBuildTestOptions(projectDir, gitter, k8sObjects, nil)
+	o := &cmd.UpgradeBuildersOptions{
+		CreateOptions: cmd.CreateOptions{
+			CommonOptions: so.CommonOptions,
+		},
+		VersionsDir: versionsDir,
+		Regenerate:  true,
+		Dir:         projectDir,
+	}
+	o.BatchMode = true
+	out, err := ioutil.TempFile(testDir, "output")
+	assert.NoError(t, err)
+	defer out.Close()
+	o.Out = out
+
+	err = o.Run()
+	assert.NoError(t, err)
+
+	kubeClient, ns, err := o.KubeClientAndDevNamespace()
+	assert.NoError(t, err)
+	podTemplate, err := kube.LoadPodTemplate(kubeClient, ns, "maven")
+	if assert.NoError(t, err) {
+		assert.Equal(t, "jenkinsxio/builder-maven:0.0.500", podTemplate.Spec.Containers[0].Image)
+	}
+
+	build := loadStepCreateBuild(t, filepath.Join(projectDir, config.DefaultPipelinesDir, actualBuildFileName))
+	if assert.Len(t, build.Spec.Steps, 1) {
+		assert.Equal(t, "jenkinsxio/builder-maven:0.0.500", build.Spec.Steps[0].Image, "the build should be regenerated with the upgraded builder image")
+	}
+	changed, err := gitter.HasChanges(projectDir)
+	assert.NoError(t, err)
+	assert.False(t, changed, "the regenerated builds should be committed")
+}
+
+func TestUpgradeBuildersVersionsRef(t *testing.T) {
+	t.Parallel()
+	versionsDir, err := ioutil.TempDir("", "test-upgrade-builders-versions")
+	assert.NoError(t, err)
+	defer os.RemoveAll(versionsDir)
+
+	// the tag v1 of the version stream has an older version than the master branch
+	gitter := gits.NewGitCLI()
+	err = gitter.Init(versionsDir)
+	assert.NoError(t, err)
+	for _, args := range [][]string{{"config", "user.name", "test"}, {"config", "user.email", "test@example.com"}} {
+		_, err = (&util.Command{Dir: versionsDir, Name: "git", Args: args}).RunWithoutRetry()
+		assert.NoError(t, err)
+	}
+	err = os.MkdirAll(filepath.Join(versionsDir, "docker", "jenkinsxio"), util.DefaultWritePermissions)
+	assert.NoError(t, err)
+	for _, version := range []string{"0.0.500", "0.0.600"} {
+		err = ioutil.WriteFile(filepath.Join(versionsDir, "docker", "jenkinsxio", "builder-maven.yml"), []byte("version: "+version+"\n"), util.DefaultWritePermissions)
+		assert.NoError(t, err)
+		err = gitter.Add(versionsDir, "*")
+		assert.NoError(t, err)
+		err = gitter.CommitDir(versionsDir, "builder-maven "+version)
+		assert.NoError(t, err)
+		if version == "0.0.500" {
+			_, err = (&util.Command{Dir: versionsDir, Name: "git", Args: []string{"tag", "v1"}}).RunWithoutRetry()
+			assert.NoError(t, err)
+		}
+	}
+
+	k8sObjects := []runtime.Object{
+		&corev1.ConfigMap{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      kube.ConfigMapJenkinsPodTemplates,
+				Namespace: "jx",
+			},
+			Data: map[string]string{
+				"maven": MavenBuildPackYaml,
+			},
+		},
+	}
+	so := newStepCreateBuildTestOptions(versionsDir, gitter, k8sObjects, nil)
+	o := &cmd.UpgradeBuildersOptions{
+		CreateOptions: cmd.CreateOptions{
+			CommonOptions: so.CommonOptions,
+		},
+		VersionsRepository: "file://" + versionsDir,
+		VersionsRef:        "v1",
+	}
+	o.BatchMode = true
+	out, err := ioutil.TempFile("", "test-upgrade-builders-output")
+	assert.NoError(t, err)
+	defer os.Remove(out.Name())
+	defer out.Close()
+	o.Out = out
+
+	err = o.Run()
+	assert.NoError(t, err, "the tag of the version stream should be checked out")
+
+	kubeClient, ns, err := o.KubeClientAndDevNamespace()
+	assert.NoError(t, err)
+	podTemplate, err := kube.LoadPodTemplate(kubeClient, ns, "maven")
+	if assert.NoError(t, err) {
+		assert.Equal(t, "jenkinsxio/builder-maven:0.0.500", podTemplate.Spec.Containers[0].Image)
+	}
+}
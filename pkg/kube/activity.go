@@ -289,6 +289,29 @@ func GetOrCreateStage(a *v1.PipelineActivity, stageName string) (*v1.PipelineAct
 	return &spec.Steps[len(spec.Steps)-1], stage, true
 }
 
+// GetOrCreateApproval gets or creates the Approval step for the given name
+func GetOrCreateApproval(a *v1.PipelineActivity, name string) (*v1.PipelineActivityStep, *v1.ApprovalActivityStep, bool) {
+	spec := &a.Spec
+	for i := range spec.Steps {
+		step := &spec.Steps[i]
+		approval := step.Approval
+		if approval != nil && approval.Name == name {
+			return step, approval, false
+		}
+	}
+
+	approval := &v1.ApprovalActivityStep{
+		CoreActivityStep: v1.CoreActivityStep{
+			Name: name,
+		},
+	}
+	spec.Steps = append(spec.Steps, v1.PipelineActivityStep{
+		Kind:     v1.ActivityStepKindTypeApproval,
+		Approval: approval,
+	})
+	return &spec.Steps[len(spec.Steps)-1], approval, true
+}
+
 // GetOrCreatePromote gets or creates the Promote step for the key
 func (k *PromoteStepActivityKey) GetOrCreatePromote(activities typev1.PipelineActivityInterface) (*v1.PipelineActivity, *v1.PipelineActivityStep, *v1.PromoteActivityStep, bool, error) {
 	a, _, err := k.GetOrCreate(activities)
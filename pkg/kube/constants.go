@@ -64,6 +64,9 @@ const (
 	// SecretJenkinsGitCredentials the git credentials secret
 	SecretJenkinsGitCredentials = "jenkins-git-credentials"
 
+	// SecretJenkinsDockerConfig the docker config secret used to push images
+	SecretJenkinsDockerConfig = "jenkins-docker-cfg"
+
 	// SecretJenkinsChartMuseum the chart museum secret
 	SecretJenkinsChartMuseum = "jenkins-x-chartmuseum"
 
@@ -149,6 +152,9 @@ const (
 	// ValueKindCVE an addon auth PipelineEvent
 	ValueKindRelease = "Release"
 
+	// ValueKindPipelineAudit a ConfigMap containing a record of the pipeline audit log
+	ValueKindPipelineAudit = "PipelineAudit"
+
 	// ValueKindEditNamespace for edit namespace
 	ValueKindEditNamespace = "editspace"
 
@@ -214,6 +220,52 @@ const (
 	// AnnotationLocalDir the local directory that is sync'd to the DevPod
 	AnnotationLocalDir = "jenkins.io/local-dir"
 
+	// AnnotationTriggers the comma separated pipelines, such as 'myorg/myrepo/master', triggered when a PipelineActivity succeeds
+	AnnotationTriggers = "jenkins.io/triggers"
+	// AnnotationTriggered indicates the pipelines in the AnnotationTriggers annotation of a PipelineActivity have been triggered
+	AnnotationTriggered = "jenkins.io/triggered"
+
+	// AnnotationMaxDuration the maximum duration of a build pod such as '30m' after which it is cancelled
+	AnnotationMaxDuration = "jenkins.io/max-duration"
+	// AnnotationMaxParallelPods the maximum number of build pods of the same pipeline which can run at the same time
+	AnnotationMaxParallelPods = "jenkins.io/max-parallel-pods"
+	// AnnotationMaxPreemptionRetries the number of times a build pod on a spot or preemptible node is retried from the step it was preempted in
+	AnnotationMaxPreemptionRetries = "jenkins.io/max-preemption-retries"
+	// AnnotationPreemptionRetries the number of times the build pod was retried after being preempted
+	AnnotationPreemptionRetries = "jenkins.io/preemption-retries"
+	// AnnotationRetriedBy the name of the pod retrying a preempted build pod
+	AnnotationRetriedBy = "jenkins.io/retried-by"
+	// AnnotationTraceID the ID of the distributed trace of a build pod
+	AnnotationTraceID = "jenkins.io/trace-id"
+	// AnnotationTraceExported indicates the spans of the trace of a completed build pod have been exported
+	AnnotationTraceExported = "jenkins.io/trace-exported"
+	// AnnotationCommitSHA the SHA of the commit a build was generated for
+	AnnotationCommitSHA = "jenkins.io/commit-sha"
+	// AnnotationCommitURL the URL to browse the commit a build was generated for
+	AnnotationCommitURL = "jenkins.io/commit-url"
+	// AnnotationPullRequestURL the URL of the pull request a build was generated for
+	AnnotationPullRequestURL = "jenkins.io/pull-request-url"
+	// AnnotationIssues the comma separated keys of the issues referenced by the commit or the branch a build was generated for such as 'JX-123,JX-456'
+	AnnotationIssues = "jenkins.io/issues"
+	// AnnotationIssueTrackerKind the kind of the issue tracker of the issues of a build when it is not the git provider such as 'jira'
+	AnnotationIssueTrackerKind = "jenkins.io/issue-tracker-kind"
+	// AnnotationIssueTrackerURL the URL of the issue tracker of the issues of a build when it is not the git provider
+	AnnotationIssueTrackerURL = "jenkins.io/issue-tracker-url"
+	// AnnotationIssuesCommented indicates the issues of a completed PipelineActivity have been commented with the links to the build
+	AnnotationIssuesCommented = "jenkins.io/issues-commented"
+	// AnnotationSignature the ASCII armored PGP signature of a generated pipeline resource
+	AnnotationSignature = "jenkins.io/signature"
+	// AnnotationBuildPackRef the git URL and commit of the build pack a pipeline resource was generated from such as 'https://github.com/jenkins-x/draft-packs.git@1234abcd'
+	AnnotationBuildPackRef = "jenkins.io/build-pack-ref"
+
+	// LabelPipeline the name of the pipeline a build pod belongs to
+	LabelPipeline = "jenkins.io/pipeline"
+
+	// LabelIsolated the label on the namespaces created for a single build which are deleted when the build completes
+	LabelIsolated = "jenkins.io/isolated"
+	// AnnotationExpires the time in RFC3339 format after which a namespace created for a single build is garbage collected
+	AnnotationExpires = "jenkins.io/expires"
+
 	// AnnotationIsDefaultStorageClass used to indicate a storageclass is default
 	AnnotationIsDefaultStorageClass = "storageclass.kubernetes.io/is-default-class"
 
@@ -188,6 +188,33 @@ func RegisterReleaseCRD(apiClient apiextensionsclientset.Interface) error {
 	return registerCRD(apiClient, name, names, columns)
 }
 
+// RegisterSourceRepositoryCRD ensures that the CRD is registered for SourceRepository
+func RegisterSourceRepositoryCRD(apiClient apiextensionsclientset.Interface) error {
+	name := "sourcerepositories." + jenkinsio.GroupName
+	names := &v1beta1.CustomResourceDefinitionNames{
+		Kind:       "SourceRepository",
+		ListKind:   "SourceRepositoryList",
+		Plural:     "sourcerepositories",
+		Singular:   "sourcerepository",
+		ShortNames: []string{"sourcerepo", "srcrepo", "sr"},
+	}
+	columns := []v1beta1.CustomResourceColumnDefinition{
+		{
+			Name:        "URL",
+			Type:        "string",
+			Description: "The URL of the git repository",
+			JSONPath:    ".spec.url",
+		},
+		{
+			Name:        "Build Pack",
+			Type:        "string",
+			Description: "The build pack of the git repository",
+			JSONPath:    ".spec.buildPack",
+		},
+	}
+	return registerCRD(apiClient, name, names, columns)
+}
+
 // RegisterUserCRD ensures that the CRD is registered for User
 func RegisterUserCRD(apiClient apiextensionsclientset.Interface) error {
 	name := "users." + jenkinsio.GroupName
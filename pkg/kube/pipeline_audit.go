@@ -0,0 +1,101 @@
+package kube
+
+import (
+	"crypto/sha256"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+)
+
+const (
+	// PipelineAuditActionGenerate the action of generating pipeline resources
+	PipelineAuditActionGenerate = "generate"
+	// PipelineAuditActionApply the action of applying pipeline resources to the cluster
+	PipelineAuditActionApply = "apply"
+
+	pipelineAuditDataKey = "record"
+)
+
+// PipelineAuditRecord records who generated or applied the resources of a pipeline and from which sources
+type PipelineAuditRecord struct {
+	Pipeline     string    `json:"pipeline,omitempty"`
+	Kind         string    `json:"kind,omitempty"`
+	Action       string    `json:"action,omitempty"`
+	User         string    `json:"user,omitempty"`
+	Cluster      string    `json:"cluster,omitempty"`
+	Timestamp    time.Time `json:"timestamp"`
+	SourceURL    string    `json:"sourceUrl,omitempty"`
+	SourceSHA    string    `json:"sourceSha,omitempty"`
+	BuildPack    string    `json:"buildPack,omitempty"`
+	BuildPackURL string    `json:"buildPackUrl,omitempty"`
+	BuildPackSHA string    `json:"buildPackSha,omitempty"`
+	OutputHash   string    `json:"outputHash,omitempty"`
+}
+
+// HashPipelineOutput returns the hex encoded SHA-256 hash of the generated pipeline resources
+func HashPipelineOutput(data []byte) string {
+	return fmt.Sprintf("%x", sha256.Sum256(data))
+}
+
+// SavePipelineAuditRecord saves the record into the pipeline audit log in the given namespace. Each record is stored
+// in its own ConfigMap labelled with the pipeline so the log can be queried via labels
+func SavePipelineAuditRecord(kubeClient kubernetes.Interface, ns string, record *PipelineAuditRecord) error {
+	if record.Timestamp.IsZero() {
+		record.Timestamp = time.Now()
+	}
+	data, err := json.Marshal(record)
+	if err != nil {
+		return errors.Wrap(err, "failed to marshal the pipeline audit record")
+	}
+	cm := &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: ToValidName(fmt.Sprintf("pipeline-audit-%s-%s-%x", record.Pipeline, record.Kind, record.Timestamp.UnixNano())),
+			Labels: map[string]string{
+				LabelKind:     ValueKindPipelineAudit,
+				LabelPipeline: ToValidName(record.Pipeline),
+			},
+		},
+		Data: map[string]string{
+			pipelineAuditDataKey: string(data),
+		},
+	}
+	_, err = kubeClient.CoreV1().ConfigMaps(ns).Create(cm)
+	if err != nil {
+		return errors.Wrapf(err, "failed to create pipeline audit ConfigMap %s in namespace %s", cm.Name, ns)
+	}
+	return nil
+}
+
+// LoadPipelineAuditRecords loads the records of the pipeline audit log in the given namespace sorted by time.
+// If the pipeline is specified only the records of that pipeline are returned
+func LoadPipelineAuditRecords(kubeClient kubernetes.Interface, ns string, pipeline string) ([]*PipelineAuditRecord, error) {
+	selector := LabelKind + "=" + ValueKindPipelineAudit
+	if pipeline != "" {
+		selector += "," + LabelPipeline + "=" + ToValidName(pipeline)
+	}
+	list, err := kubeClient.CoreV1().ConfigMaps(ns).List(metav1.ListOptions{
+		LabelSelector: selector,
+	})
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to list the pipeline audit ConfigMaps in namespace %s", ns)
+	}
+	answer := []*PipelineAuditRecord{}
+	for _, cm := range list.Items {
+		record := &PipelineAuditRecord{}
+		err = json.Unmarshal([]byte(cm.Data[pipelineAuditDataKey]), record)
+		if err != nil {
+			return answer, errors.Wrapf(err, "failed to unmarshal the pipeline audit record in ConfigMap %s", cm.Name)
+		}
+		answer = append(answer, record)
+	}
+	sort.Slice(answer, func(i, j int) bool {
+		return answer[i].Timestamp.Before(answer[j].Timestamp)
+	})
+	return answer, nil
+}
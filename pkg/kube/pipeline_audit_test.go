@@ -0,0 +1,60 @@
+package kube_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/jenkins-x/jx/pkg/kube"
+	"github.com/stretchr/testify/assert"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+func TestSaveAndLoadPipelineAuditRecords(t *testing.T) {
+	t.Parallel()
+	ns := "jx"
+	kubeClient := fake.NewSimpleClientset()
+	now := time.Now()
+
+	records := []*kube.PipelineAuditRecord{
+		{
+			Pipeline:   "myapp",
+			Kind:       "release",
+			Action:     kube.PipelineAuditActionGenerate,
+			User:       "alice",
+			Timestamp:  now,
+			SourceURL:  "https://github.com/myorg/myapp.git",
+			SourceSHA:  "0123456789abcdef",
+			OutputHash: kube.HashPipelineOutput([]byte("steps: []")),
+		},
+		{
+			Pipeline:  "myapp",
+			Kind:      "pullRequest",
+			Action:    kube.PipelineAuditActionGenerate,
+			Timestamp: now.Add(-time.Minute),
+		},
+		{
+			Pipeline:  "other",
+			Kind:      "release",
+			Action:    kube.PipelineAuditActionApply,
+			Timestamp: now.Add(-time.Hour),
+		},
+	}
+	for _, r := range records {
+		err := kube.SavePipelineAuditRecord(kubeClient, ns, r)
+		assert.NoError(t, err)
+	}
+
+	all, err := kube.LoadPipelineAuditRecords(kubeClient, ns, "")
+	assert.NoError(t, err)
+	if assert.Equal(t, 3, len(all)) {
+		assert.Equal(t, "other", all[0].Pipeline)
+		assert.Equal(t, "pullRequest", all[1].Kind)
+		assert.Equal(t, "alice", all[2].User)
+		assert.Equal(t, records[0].OutputHash, all[2].OutputHash)
+		assert.Equal(t, 64, len(all[2].OutputHash))
+	}
+
+	myapp, err := kube.LoadPipelineAuditRecords(kubeClient, ns, "myapp")
+	assert.NoError(t, err)
+	assert.Equal(t, 2, len(myapp))
+}
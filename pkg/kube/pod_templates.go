@@ -0,0 +1,368 @@
+package kube
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/ghodss/yaml"
+	"github.com/jenkins-x/jx/pkg/util"
+	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+)
+
+const (
+	// PodTemplateExtendsAnnotation the annotation on a Pod Template which names the Pod Template it extends
+	PodTemplateExtendsAnnotation = "jenkins.io/extends"
+)
+
+// podTemplateExtends is used to parse the optional top level 'extends:' key of a Pod Template
+type podTemplateExtends struct {
+	Extends string `json:"extends,omitempty"`
+}
+
+// LoadPodTemplates loads all of the Pod Templates from the ConfigMap in the given namespace keyed by name
+// with any Pod Templates which extend another Pod Template merged with their parent
+func LoadPodTemplates(kubeClient kubernetes.Interface, ns string) (map[string]*corev1.Pod, error) {
+	podTemplates, err := LoadUnresolvedPodTemplates(kubeClient, ns)
+	if err != nil {
+		return podTemplates, err
+	}
+	return ResolvePodTemplates(podTemplates)
+}
+
+// LoadUnresolvedPodTemplates loads all of the Pod Templates from the ConfigMap in the given namespace keyed by name
+// without resolving any Pod Templates they extend
+func LoadUnresolvedPodTemplates(kubeClient kubernetes.Interface, ns string) (map[string]*corev1.Pod, error) {
+	cm, err := kubeClient.CoreV1().ConfigMaps(ns).Get(ConfigMapJenkinsPodTemplates, metav1.GetOptions{})
+	if err != nil {
+		return map[string]*corev1.Pod{}, errors.Wrapf(err, "failed to find ConfigMap %s in namespace %s", ConfigMapJenkinsPodTemplates, ns)
+	}
+	return ParsePodTemplates(cm.Data)
+}
+
+// ParsePodTemplates parses the Pod Templates of the data of the Pod Templates ConfigMap keyed by name without
+// resolving any Pod Templates they extend
+func ParsePodTemplates(data map[string]string) (map[string]*corev1.Pod, error) {
+	answer := map[string]*corev1.Pod{}
+	for name, podTemplateYaml := range data {
+		pod, err := ParsePodTemplate(name, podTemplateYaml)
+		if err != nil {
+			return answer, err
+		}
+		answer[name] = pod
+	}
+	return answer, nil
+}
+
+// LoadPodTemplate loads the Pod Template of the given name from the ConfigMap in the given namespace
+// merging it with any Pod Templates it extends
+func LoadPodTemplate(kubeClient kubernetes.Interface, ns string, name string) (*corev1.Pod, error) {
+	cm, err := kubeClient.CoreV1().ConfigMaps(ns).Get(ConfigMapJenkinsPodTemplates, metav1.GetOptions{})
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to find ConfigMap %s in namespace %s", ConfigMapJenkinsPodTemplates, ns)
+	}
+	return ParsePodTemplateNamed(cm.Data, name)
+}
+
+// PodTemplateNamed returns the Pod Template of the given name of the unresolved Pod Templates merging it with any
+// Pod Templates it extends
+func PodTemplateNamed(podTemplates map[string]*corev1.Pod, name string) (*corev1.Pod, error) {
+	if podTemplates[name] == nil {
+		names := []string{}
+		for k := range podTemplates {
+			names = append(names, k)
+		}
+		return nil, podTemplateNotFound(name, names)
+	}
+	return resolvePodTemplate(name, podTemplates, []string{})
+}
+
+// ParsePodTemplateNamed parses the Pod Template of the given name of the data of the Pod Templates ConfigMap merging
+// it with any Pod Templates it extends. The other Pod Templates are not parsed so that an invalid Pod Template only
+// breaks the Pod Templates which use it
+func ParsePodTemplateNamed(data map[string]string, name string) (*corev1.Pod, error) {
+	if _, ok := data[name]; !ok {
+		names := []string{}
+		for k := range data {
+			names = append(names, k)
+		}
+		return nil, podTemplateNotFound(name, names)
+	}
+	podTemplates := map[string]*corev1.Pod{}
+	for next := name; next != "" && podTemplates[next] == nil; {
+		podTemplateYaml, ok := data[next]
+		if !ok {
+			// resolving the Pod Template reports the missing parent
+			break
+		}
+		pod, err := ParsePodTemplate(next, podTemplateYaml)
+		if err != nil {
+			return nil, err
+		}
+		podTemplates[next] = pod
+		next = pod.Annotations[PodTemplateExtendsAnnotation]
+	}
+	return resolvePodTemplate(name, podTemplates, []string{})
+}
+
+func podTemplateNotFound(name string, names []string) error {
+	sort.Strings(names)
+	return fmt.Errorf("No pod template is defined in ConfigMap %s for %s. Available pod templates: %s", ConfigMapJenkinsPodTemplates, name, strings.Join(names, ", "))
+}
+
+// ParsePodTemplate parses the YAML of a Pod Template. Any top level 'extends:' key is converted into the
+// PodTemplateExtendsAnnotation annotation
+func ParsePodTemplate(name string, podTemplateYaml string) (*corev1.Pod, error) {
+	pod := &corev1.Pod{}
+	err := yaml.Unmarshal([]byte(podTemplateYaml), pod)
+	if err != nil {
+		return pod, errors.Wrapf(err, "failed to parse the YAML of pod template %s", name)
+	}
+	ext := &podTemplateExtends{}
+	err = yaml.Unmarshal([]byte(podTemplateYaml), ext)
+	if err != nil {
+		return pod, errors.Wrapf(err, "failed to parse the YAML of pod template %s", name)
+	}
+	if ext.Extends != "" {
+		if pod.Annotations == nil {
+			pod.Annotations = map[string]string{}
+		}
+		pod.Annotations[PodTemplateExtendsAnnotation] = ext.Extends
+	}
+	return pod, nil
+}
+
+// ResolvePodTemplates returns the Pod Templates with any Pod Templates which extend another Pod Template
+// merged with their parent
+func ResolvePodTemplates(podTemplates map[string]*corev1.Pod) (map[string]*corev1.Pod, error) {
+	answer := map[string]*corev1.Pod{}
+	for name := range podTemplates {
+		pod, err := resolvePodTemplate(name, podTemplates, []string{})
+		if err != nil {
+			return answer, err
+		}
+		answer[name] = pod
+	}
+	return answer, nil
+}
+
+func resolvePodTemplate(name string, podTemplates map[string]*corev1.Pod, visited []string) (*corev1.Pod, error) {
+	pod := podTemplates[name]
+	if pod == nil {
+		return nil, fmt.Errorf("No pod template is defined for %s", name)
+	}
+	parentName := ""
+	if pod.Annotations != nil {
+		parentName = pod.Annotations[PodTemplateExtendsAnnotation]
+	}
+	if parentName == "" {
+		return pod.DeepCopy(), nil
+	}
+	visited = append(visited, name)
+	if util.StringArrayIndex(visited, parentName) >= 0 {
+		return nil, fmt.Errorf("Cyclic pod template inheritance: %s extends %s", strings.Join(visited, " extends "), parentName)
+	}
+	if podTemplates[parentName] == nil {
+		return nil, fmt.Errorf("The pod template %s extends pod template %s which does not exist", name, parentName)
+	}
+	parent, err := resolvePodTemplate(parentName, podTemplates, visited)
+	if err != nil {
+		return nil, err
+	}
+	return MergePodTemplates(parent, pod), nil
+}
+
+// MergePodTemplates returns a new Pod Template which overlays the child Pod Template on its parent.
+//
+// Containers are matched by name; a child container overrides the image, command and arguments of the parent
+// container if they are specified and is merged with the parents environment variables, volume mounts and resources.
+// Volumes, labels, annotations and node selectors are merged by name with the child taking precedence
+func MergePodTemplates(parent *corev1.Pod, child *corev1.Pod) *corev1.Pod {
+	answer := parent.DeepCopy()
+	c := child.DeepCopy()
+
+	answer.Name = c.Name
+	answer.Labels = mergeStringMaps(answer.Labels, c.Labels)
+	answer.Annotations = mergeStringMaps(answer.Annotations, c.Annotations)
+	if answer.Annotations != nil {
+		delete(answer.Annotations, PodTemplateExtendsAnnotation)
+		if len(answer.Annotations) == 0 {
+			answer.Annotations = nil
+		}
+	}
+
+	spec := &answer.Spec
+	if c.Spec.ServiceAccountName != "" {
+		spec.ServiceAccountName = c.Spec.ServiceAccountName
+	}
+	if c.Spec.DeprecatedServiceAccount != "" {
+		spec.DeprecatedServiceAccount = c.Spec.DeprecatedServiceAccount
+	}
+	spec.NodeSelector = mergeStringMaps(spec.NodeSelector, c.Spec.NodeSelector)
+	for _, v := range c.Spec.Volumes {
+		idx := -1
+		for i := range spec.Volumes {
+			if spec.Volumes[i].Name == v.Name {
+				idx = i
+				break
+			}
+		}
+		if idx >= 0 {
+			spec.Volumes[idx] = v
+		} else {
+			spec.Volumes = append(spec.Volumes, v)
+		}
+	}
+	for _, container := range c.Spec.Containers {
+		idx := -1
+		for i := range spec.Containers {
+			if spec.Containers[i].Name == container.Name {
+				idx = i
+				break
+			}
+		}
+		if idx >= 0 {
+			mergeContainer(&spec.Containers[idx], &container)
+		} else {
+			spec.Containers = append(spec.Containers, container)
+		}
+	}
+	return answer
+}
+
+func mergeContainer(parent *corev1.Container, child *corev1.Container) {
+	if child.Image != "" {
+		parent.Image = child.Image
+	}
+	if len(child.Command) > 0 {
+		parent.Command = child.Command
+	}
+	if len(child.Args) > 0 {
+		parent.Args = child.Args
+	}
+	if child.WorkingDir != "" {
+		parent.WorkingDir = child.WorkingDir
+	}
+	if child.SecurityContext != nil {
+		parent.SecurityContext = child.SecurityContext
+	}
+	for _, env := range child.Env {
+		e := GetEnvVar(parent, env.Name)
+		if e != nil {
+			*e = env
+		} else {
+			parent.Env = append(parent.Env, env)
+		}
+	}
+	for _, vm := range child.VolumeMounts {
+		m := GetVolumeMount(&parent.VolumeMounts, vm.Name)
+		if m != nil {
+			*m = vm
+		} else {
+			parent.VolumeMounts = append(parent.VolumeMounts, vm)
+		}
+	}
+	parent.Resources.Requests = mergeResourceLists(parent.Resources.Requests, child.Resources.Requests)
+	parent.Resources.Limits = mergeResourceLists(parent.Resources.Limits, child.Resources.Limits)
+}
+
+func mergeStringMaps(parent map[string]string, child map[string]string) map[string]string {
+	if len(child) == 0 {
+		return parent
+	}
+	answer := map[string]string{}
+	for k, v := range parent {
+		answer[k] = v
+	}
+	for k, v := range child {
+		answer[k] = v
+	}
+	return answer
+}
+
+func mergeResourceLists(parent corev1.ResourceList, child corev1.ResourceList) corev1.ResourceList {
+	if len(child) == 0 {
+		return parent
+	}
+	answer := corev1.ResourceList{}
+	for k, v := range parent {
+		answer[k] = v
+	}
+	for k, v := range child {
+		answer[k] = v
+	}
+	return answer
+}
+
+// ValidatePodTemplate validates that the given Pod Template can be used to run pipeline steps. Pod Templates
+// which extend another Pod Template may omit their containers or images
+func ValidatePodTemplate(name string, pod *corev1.Pod) error {
+	extends := pod.Annotations != nil && pod.Annotations[PodTemplateExtendsAnnotation] != ""
+	containers := pod.Spec.Containers
+	if len(containers) == 0 && !extends {
+		return fmt.Errorf("The pod template %s has no containers", name)
+	}
+	for i, c := range containers {
+		if c.Name == "" {
+			return fmt.Errorf("The pod template %s has no name for container %d", name, i)
+		}
+		if c.Image == "" && !extends {
+			return fmt.Errorf("The pod template %s has no image for container %s", name, c.Name)
+		}
+	}
+	return nil
+}
+
+// SavePodTemplate validates and saves the Pod Template of the given name into the ConfigMap in the given namespace
+func SavePodTemplate(kubeClient kubernetes.Interface, ns string, name string, pod *corev1.Pod) error {
+	err := ValidatePodTemplate(name, pod)
+	if err != nil {
+		return err
+	}
+	data, err := yaml.Marshal(pod)
+	if err != nil {
+		return errors.Wrapf(err, "failed to marshal pod template %s to YAML", name)
+	}
+	configMaps := kubeClient.CoreV1().ConfigMaps(ns)
+	cm, err := configMaps.Get(ConfigMapJenkinsPodTemplates, metav1.GetOptions{})
+	if err != nil {
+		if !apierrors.IsNotFound(err) {
+			return errors.Wrapf(err, "failed to get ConfigMap %s in namespace %s", ConfigMapJenkinsPodTemplates, ns)
+		}
+		cm = &corev1.ConfigMap{
+			ObjectMeta: metav1.ObjectMeta{
+				Name: ConfigMapJenkinsPodTemplates,
+			},
+			Data: map[string]string{
+				name: string(data),
+			},
+		}
+		_, err = configMaps.Create(cm)
+		return err
+	}
+	if cm.Data == nil {
+		cm.Data = map[string]string{}
+	}
+	cm.Data[name] = string(data)
+	_, err = configMaps.Update(cm)
+	return err
+}
+
+// DeletePodTemplate removes the Pod Template of the given name from the ConfigMap in the given namespace
+func DeletePodTemplate(kubeClient kubernetes.Interface, ns string, name string) error {
+	configMaps := kubeClient.CoreV1().ConfigMaps(ns)
+	cm, err := configMaps.Get(ConfigMapJenkinsPodTemplates, metav1.GetOptions{})
+	if err != nil {
+		return errors.Wrapf(err, "failed to find ConfigMap %s in namespace %s", ConfigMapJenkinsPodTemplates, ns)
+	}
+	if cm.Data == nil || cm.Data[name] == "" {
+		return fmt.Errorf("No pod template is defined in ConfigMap %s for %s", ConfigMapJenkinsPodTemplates, name)
+	}
+	delete(cm.Data, name)
+	_, err = configMaps.Update(cm)
+	return err
+}
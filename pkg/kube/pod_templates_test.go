@@ -0,0 +1,195 @@
+package kube_test
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/jenkins-x/jx/pkg/kube"
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/kubernetes/fake"
+	k8sTesting "k8s.io/client-go/testing"
+)
+
+func TestSaveLoadAndDeletePodTemplates(t *testing.T) {
+	t.Parallel()
+	ns := "jx"
+	kubeClient := fake.NewSimpleClientset()
+
+	invalid := &corev1.Pod{}
+	err := kube.SavePodTemplate(kubeClient, ns, "invalid", invalid)
+	assert.Error(t, err, "should not save a pod template without containers")
+
+	pod := &corev1.Pod{
+		Spec: corev1.PodSpec{
+			Containers: []corev1.Container{
+				{
+					Name:  "maven",
+					Image: "jenkinsxio/builder-maven:0.0.408",
+				},
+			},
+		},
+	}
+	err = kube.SavePodTemplate(kubeClient, ns, "maven", pod)
+	assert.NoError(t, err)
+
+	loaded, err := kube.LoadPodTemplate(kubeClient, ns, "maven")
+	assert.NoError(t, err)
+	assert.Equal(t, "jenkinsxio/builder-maven:0.0.408", loaded.Spec.Containers[0].Image)
+
+	podTemplates, err := kube.LoadPodTemplates(kubeClient, ns)
+	assert.NoError(t, err)
+	assert.Equal(t, 1, len(podTemplates))
+
+	_, err = kube.LoadPodTemplate(kubeClient, ns, "gradle")
+	assert.Error(t, err)
+
+	err = kube.DeletePodTemplate(kubeClient, ns, "maven")
+	assert.NoError(t, err)
+
+	podTemplates, err = kube.LoadPodTemplates(kubeClient, ns)
+	assert.NoError(t, err)
+	assert.Equal(t, 0, len(podTemplates))
+}
+
+func TestSavePodTemplateGetError(t *testing.T) {
+	t.Parallel()
+	ns := "jx"
+	kubeClient := fake.NewSimpleClientset()
+	kubeClient.PrependReactor("get", "configmaps", func(action k8sTesting.Action) (bool, runtime.Object, error) {
+		return true, nil, fmt.Errorf("the server is unavailable")
+	})
+	created := false
+	kubeClient.PrependReactor("create", "configmaps", func(action k8sTesting.Action) (bool, runtime.Object, error) {
+		created = true
+		return false, nil, nil
+	})
+
+	pod := &corev1.Pod{
+		Spec: corev1.PodSpec{
+			Containers: []corev1.Container{
+				{
+					Name:  "maven",
+					Image: "jenkinsxio/builder-maven:0.0.408",
+				},
+			},
+		},
+	}
+	err := kube.SavePodTemplate(kubeClient, ns, "maven", pod)
+	assert.Error(t, err)
+	assert.False(t, created, "the ConfigMap should only be created if it does not exist")
+}
+
+func TestPodTemplateExtends(t *testing.T) {
+	t.Parallel()
+	mavenYaml := `apiVersion: v1
+kind: Pod
+metadata:
+  name: jenkins-maven
+spec:
+  volumes:
+  - name: workspace-volume
+    emptyDir: {}
+  containers:
+  - name: maven
+    image: jenkinsxio/builder-maven:0.0.408
+    env:
+    - name: JAVA_VERSION
+      value: "8"
+    - name: XDG_CONFIG_HOME
+      value: /home/jenkins
+    resources:
+      requests:
+        cpu: 400m
+        memory: 512Mi
+`
+	java11Yaml := `extends: maven
+apiVersion: v1
+kind: Pod
+metadata:
+  name: jenkins-maven-java11
+spec:
+  containers:
+  - name: maven
+    image: jenkinsxio/builder-maven-java11:0.0.1
+    env:
+    - name: JAVA_VERSION
+      value: "11"
+    resources:
+      requests:
+        memory: 1Gi
+`
+	maven, err := kube.ParsePodTemplate("maven", mavenYaml)
+	assert.NoError(t, err)
+	java11, err := kube.ParsePodTemplate("maven-java11", java11Yaml)
+	assert.NoError(t, err)
+	assert.Equal(t, "maven", java11.Annotations[kube.PodTemplateExtendsAnnotation])
+
+	resolved, err := kube.ResolvePodTemplates(map[string]*corev1.Pod{
+		"maven":        maven,
+		"maven-java11": java11,
+	})
+	assert.NoError(t, err)
+
+	pod := resolved["maven-java11"]
+	if assert.NotNil(t, pod) && assert.Equal(t, 1, len(pod.Spec.Containers)) {
+		c := pod.Spec.Containers[0]
+		assert.Equal(t, "jenkins-maven-java11", pod.Name)
+		assert.Equal(t, "jenkinsxio/builder-maven-java11:0.0.1", c.Image)
+		assert.Equal(t, "11", kube.GetEnvVar(&c, "JAVA_VERSION").Value)
+		assert.Equal(t, "/home/jenkins", kube.GetEnvVar(&c, "XDG_CONFIG_HOME").Value)
+		assert.Equal(t, "400m", c.Resources.Requests.Cpu().String())
+		assert.Equal(t, "1Gi", c.Resources.Requests.Memory().String())
+		assert.Equal(t, 1, len(pod.Spec.Volumes))
+		assert.Empty(t, pod.Annotations[kube.PodTemplateExtendsAnnotation])
+	}
+	assert.Equal(t, "jenkinsxio/builder-maven:0.0.408", resolved["maven"].Spec.Containers[0].Image)
+
+	maven.Annotations = map[string]string{kube.PodTemplateExtendsAnnotation: "maven-java11"}
+	_, err = kube.ResolvePodTemplates(map[string]*corev1.Pod{
+		"maven":        maven,
+		"maven-java11": java11,
+	})
+	assert.Error(t, err, "should detect cyclic inheritance")
+}
+
+func TestParsePodTemplateNamed(t *testing.T) {
+	t.Parallel()
+	data := map[string]string{
+		"maven": `apiVersion: v1
+kind: Pod
+metadata:
+  name: jenkins-maven
+spec:
+  containers:
+  - name: maven
+    image: jenkinsxio/builder-maven:0.0.408
+`,
+		"maven-java11": `extends: maven
+spec:
+  containers:
+  - name: maven
+    image: jenkinsxio/builder-maven-java11:0.0.1
+`,
+		"broken":   "spec: [",
+		"orphan":   "extends: missing\n",
+		"cyclic-a": "extends: cyclic-b\n",
+		"cyclic-b": "extends: cyclic-a\n",
+	}
+
+	pod, err := kube.ParsePodTemplateNamed(data, "maven-java11")
+	if assert.NoError(t, err, "an invalid pod template should not break the pod templates which do not use it") {
+		assert.Equal(t, 1, len(pod.Spec.Containers))
+		assert.Equal(t, "jenkinsxio/builder-maven-java11:0.0.1", pod.Spec.Containers[0].Image)
+	}
+
+	_, err = kube.ParsePodTemplateNamed(data, "broken")
+	assert.Error(t, err)
+	_, err = kube.ParsePodTemplateNamed(data, "orphan")
+	assert.Error(t, err, "should report the missing parent")
+	_, err = kube.ParsePodTemplateNamed(data, "cyclic-a")
+	assert.Error(t, err, "should detect cyclic inheritance")
+	_, err = kube.ParsePodTemplateNamed(data, "gradle")
+	assert.Error(t, err)
+}
@@ -0,0 +1,60 @@
+package kube
+
+import (
+	"sort"
+
+	"github.com/jenkins-x/jx/pkg/apis/jenkins.io/v1"
+	"github.com/jenkins-x/jx/pkg/client/clientset/versioned"
+	"github.com/jenkins-x/jx/pkg/gits"
+	"github.com/jenkins-x/jx/pkg/util"
+	"github.com/pkg/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// NewSourceRepository creates a SourceRepository for the given git repository
+func NewSourceRepository(gitInfo *gits.GitRepositoryInfo, buildPack string, pipelineKinds []string) *v1.SourceRepository {
+	hostURL := gitInfo.HostURLWithoutUser()
+	return &v1.SourceRepository{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: ToValidName(gitInfo.Organisation + "-" + gitInfo.Name),
+		},
+		Spec: v1.SourceRepositorySpec{
+			Org:           gitInfo.Organisation,
+			Repo:          gitInfo.Name,
+			Provider:      hostURL,
+			ProviderKind:  gits.SaasGitKind(hostURL),
+			URL:           gitInfo.URL,
+			BuildPack:     buildPack,
+			PipelineKinds: pipelineKinds,
+		},
+	}
+}
+
+// GetOrCreateSourceRepository creates or updates the given SourceRepository merging its pipeline kinds with
+// the pipeline kinds of any existing SourceRepository
+func GetOrCreateSourceRepository(jxClient versioned.Interface, ns string, repo *v1.SourceRepository) (*v1.SourceRepository, error) {
+	repoInterface := jxClient.JenkinsV1().SourceRepositories(ns)
+	name := repo.Name
+	old, err := repoInterface.Get(name, metav1.GetOptions{})
+	if err == nil {
+		kinds := old.Spec.PipelineKinds
+		for _, k := range repo.Spec.PipelineKinds {
+			if util.StringArrayIndex(kinds, k) < 0 {
+				kinds = append(kinds, k)
+			}
+		}
+		sort.Strings(kinds)
+		old.Spec = repo.Spec
+		old.Spec.PipelineKinds = kinds
+		answer, err := repoInterface.Update(old)
+		if err != nil {
+			return answer, errors.Wrapf(err, "Failed to update SourceRepository %s in namespace %s", name, ns)
+		}
+		return answer, nil
+	}
+	answer, err := repoInterface.Create(repo)
+	if err != nil {
+		return answer, errors.Wrapf(err, "Failed to create SourceRepository %s in namespace %s", name, ns)
+	}
+	return answer, nil
+}
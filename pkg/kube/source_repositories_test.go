@@ -0,0 +1,32 @@
+package kube_test
+
+import (
+	"testing"
+
+	"github.com/jenkins-x/jx/pkg/client/clientset/versioned/fake"
+	"github.com/jenkins-x/jx/pkg/gits"
+	"github.com/jenkins-x/jx/pkg/kube"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGetOrCreateSourceRepository(t *testing.T) {
+	t.Parallel()
+	jxClient := fake.NewSimpleClientset()
+	ns := "jx"
+	gitInfo, err := gits.ParseGitURL("https://github.com/myorg/my-app.git")
+	assert.NoError(t, err)
+
+	repo, err := kube.GetOrCreateSourceRepository(jxClient, ns, kube.NewSourceRepository(gitInfo, "go", []string{"release"}))
+	assert.NoError(t, err)
+	assert.Equal(t, "myorg-my-app", repo.Name)
+	assert.Equal(t, "myorg", repo.Spec.Org)
+	assert.Equal(t, "my-app", repo.Spec.Repo)
+	assert.Equal(t, "https://github.com", repo.Spec.Provider)
+	assert.Equal(t, "github", repo.Spec.ProviderKind)
+	assert.Equal(t, []string{"release"}, repo.Spec.PipelineKinds)
+
+	repo, err = kube.GetOrCreateSourceRepository(jxClient, ns, kube.NewSourceRepository(gitInfo, "maven", []string{"pullRequest", "release"}))
+	assert.NoError(t, err)
+	assert.Equal(t, "maven", repo.Spec.BuildPack)
+	assert.Equal(t, []string{"pullRequest", "release"}, repo.Spec.PipelineKinds)
+}
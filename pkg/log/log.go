@@ -2,26 +2,43 @@ package log
 
 import (
 	"fmt"
-	"github.com/sirupsen/logrus"
+	"io"
 	"os"
 
+	"github.com/sirupsen/logrus"
+
 	"github.com/fatih/color"
 )
 
+// output the writer of the informational messages
+var output io.Writer = os.Stdout
+
+// SetOutput writes the messages of the log to the writer such as os.Stderr so that the standard output of a command
+// only contains the data it generates. It returns a function which restores the previous writers
+func SetOutput(w io.Writer) func() {
+	previous, previousColor := output, color.Output
+	output = w
+	color.Output = w
+	return func() {
+		output = previous
+		color.Output = previousColor
+	}
+}
+
 func Infof(msg string, args ...interface{}) {
 	Info(fmt.Sprintf(msg, args...))
 }
 
 func Info(msg string) {
-	fmt.Print(msg)
+	fmt.Fprint(output, msg)
 }
 
 func Infoln(msg string) {
-	fmt.Println(msg)
+	fmt.Fprintln(output, msg)
 }
 
 func Blank() {
-	fmt.Println()
+	fmt.Fprintln(output)
 }
 
 func Warnf(msg string, args ...interface{}) {
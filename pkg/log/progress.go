@@ -0,0 +1,104 @@
+package log
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/mattn/go-isatty"
+)
+
+var (
+	// ProgressInterval how often the progress of an operation is logged when the output is not a terminal
+	ProgressInterval = 15 * time.Second
+
+	spinnerInterval = 100 * time.Millisecond
+	spinnerFrames   = []string{"|", "/", "-", "\\"}
+)
+
+// Progress reports the progress of a long running operation so that it does not appear to hang. A spinner is shown
+// if the output is a terminal and the command is not in batch mode, otherwise the operation is logged periodically
+// once it takes longer than the ProgressInterval
+type Progress struct {
+	out      io.Writer
+	terminal bool
+	start    time.Time
+	message  string
+	lock     sync.Mutex
+	done     chan struct{}
+	stopped  chan struct{}
+}
+
+// StartProgress starts reporting the progress of the operation described by the message until it is stopped
+func StartProgress(out io.Writer, batchMode bool, format string, args ...interface{}) *Progress {
+	terminal := false
+	if f, ok := out.(*os.File); ok && !batchMode {
+		terminal = isatty.IsTerminal(f.Fd()) || isatty.IsCygwinTerminal(f.Fd())
+	}
+	p := &Progress{
+		out:      out,
+		terminal: terminal,
+		start:    time.Now(),
+		message:  fmt.Sprintf(format, args...),
+		done:     make(chan struct{}),
+		stopped:  make(chan struct{}),
+	}
+	go p.run()
+	return p
+}
+
+// Update changes the message describing the current state of the operation
+func (p *Progress) Update(format string, args ...interface{}) {
+	p.lock.Lock()
+	p.message = fmt.Sprintf(format, args...)
+	p.lock.Unlock()
+	if !p.terminal {
+		fmt.Fprintf(p.out, "%s\n", p.currentMessage())
+	}
+}
+
+// Stop stops reporting the progress
+func (p *Progress) Stop() {
+	select {
+	case <-p.done:
+		return
+	default:
+		close(p.done)
+	}
+	<-p.stopped
+}
+
+func (p *Progress) currentMessage() string {
+	p.lock.Lock()
+	defer p.lock.Unlock()
+	return p.message
+}
+
+func (p *Progress) run() {
+	defer close(p.stopped)
+	interval := ProgressInterval
+	if p.terminal {
+		interval = spinnerInterval
+	}
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for frame := 0; ; frame++ {
+		select {
+		case <-p.done:
+			if p.terminal {
+				// clear the spinner line
+				fmt.Fprint(p.out, "\r\033[K")
+			}
+			return
+		case <-ticker.C:
+			elapsed := time.Since(p.start).Round(time.Second)
+			if p.terminal {
+				fmt.Fprintf(p.out, "\r\033[K%s %s (%s)", spinnerFrames[frame%len(spinnerFrames)], p.currentMessage(), elapsed)
+			} else {
+				fmt.Fprintf(p.out, "%s (%s elapsed)\n", p.currentMessage(), elapsed)
+			}
+		}
+	}
+}
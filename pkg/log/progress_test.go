@@ -0,0 +1,44 @@
+package log_test
+
+import (
+	"bytes"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/jenkins-x/jx/pkg/log"
+	"github.com/stretchr/testify/assert"
+)
+
+type syncBuffer struct {
+	lock   sync.Mutex
+	buffer bytes.Buffer
+}
+
+func (b *syncBuffer) Write(p []byte) (int, error) {
+	b.lock.Lock()
+	defer b.lock.Unlock()
+	return b.buffer.Write(p)
+}
+
+func (b *syncBuffer) String() string {
+	b.lock.Lock()
+	defer b.lock.Unlock()
+	return b.buffer.String()
+}
+
+func TestProgressLogsPeriodicallyWithoutTerminal(t *testing.T) {
+	log.ProgressInterval = 10 * time.Millisecond
+	out := &syncBuffer{}
+	progress := log.StartProgress(out, true, "Cloning %s", "packs")
+	time.Sleep(35 * time.Millisecond)
+	progress.Update("Regenerating %d/%d", 1, 2)
+	progress.Stop()
+	progress.Stop()
+
+	lines := strings.Split(strings.TrimSpace(out.String()), "\n")
+	assert.True(t, len(lines) >= 2, "expected periodic lines but got %v", lines)
+	assert.True(t, strings.HasPrefix(lines[0], "Cloning packs ("), "unexpected line %s", lines[0])
+	assert.Equal(t, "Regenerating 1/2", lines[len(lines)-1])
+}
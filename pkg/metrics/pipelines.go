@@ -0,0 +1,180 @@
+package metrics
+
+import (
+	"strings"
+
+	"github.com/jenkins-x/jx/pkg/apis/jenkins.io/v1"
+	"github.com/jenkins-x/jx/pkg/client/clientset/versioned"
+	"github.com/prometheus/client_golang/prometheus"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+const (
+	namespace = "jx"
+	subsystem = "pipeline"
+)
+
+// DurationBuckets the buckets in seconds of the pipeline duration histograms
+var DurationBuckets = []float64{30, 60, 120, 300, 600, 900, 1800, 3600, 7200}
+
+// PipelineCollector a Prometheus collector which exposes metrics about the PipelineActivity resources
+// in a namespace such as run durations, stage durations, queue times and success rates by repository
+type PipelineCollector struct {
+	jxClient versioned.Interface
+	ns       string
+
+	runs          *prometheus.Desc
+	running       *prometheus.Desc
+	successRatio  *prometheus.Desc
+	runDuration   *prometheus.Desc
+	stageDuration *prometheus.Desc
+	queueDuration *prometheus.Desc
+}
+
+// histogram accumulates the observations of a histogram
+type histogram struct {
+	count   uint64
+	sum     float64
+	buckets map[float64]uint64
+}
+
+func (h *histogram) observe(value float64) {
+	if h.buckets == nil {
+		h.buckets = map[float64]uint64{}
+		for _, b := range DurationBuckets {
+			h.buckets[b] = 0
+		}
+	}
+	h.count++
+	h.sum += value
+	for _, b := range DurationBuckets {
+		if value <= b {
+			h.buckets[b]++
+		}
+	}
+}
+
+// NewPipelineCollector creates a collector of the PipelineActivity resources in the given namespace
+func NewPipelineCollector(jxClient versioned.Interface, ns string) *PipelineCollector {
+	return &PipelineCollector{
+		jxClient: jxClient,
+		ns:       ns,
+		runs: prometheus.NewDesc(prometheus.BuildFQName(namespace, subsystem, "runs_total"),
+			"The number of completed pipeline runs by repository and status", []string{"repository", "status"}, nil),
+		running: prometheus.NewDesc(prometheus.BuildFQName(namespace, subsystem, "runs_running"),
+			"The number of pipeline runs currently running by repository", []string{"repository"}, nil),
+		successRatio: prometheus.NewDesc(prometheus.BuildFQName(namespace, subsystem, "success_ratio"),
+			"The ratio of completed pipeline runs which succeeded by repository", []string{"repository"}, nil),
+		runDuration: prometheus.NewDesc(prometheus.BuildFQName(namespace, subsystem, "run_duration_seconds"),
+			"The duration of completed pipeline runs by repository", []string{"repository"}, nil),
+		stageDuration: prometheus.NewDesc(prometheus.BuildFQName(namespace, subsystem, "stage_duration_seconds"),
+			"The duration of completed pipeline stages by repository and stage", []string{"repository", "stage"}, nil),
+		queueDuration: prometheus.NewDesc(prometheus.BuildFQName(namespace, subsystem, "queue_duration_seconds"),
+			"The time pipeline runs wait between being created and starting by repository", []string{"repository"}, nil),
+	}
+}
+
+// Describe implements prometheus.Collector
+func (c *PipelineCollector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- c.runs
+	ch <- c.running
+	ch <- c.successRatio
+	ch <- c.runDuration
+	ch <- c.stageDuration
+	ch <- c.queueDuration
+}
+
+// Collect implements prometheus.Collector by listing the current PipelineActivity resources
+func (c *PipelineCollector) Collect(ch chan<- prometheus.Metric) {
+	activities, err := c.jxClient.JenkinsV1().PipelineActivities(c.ns).List(metav1.ListOptions{})
+	if err != nil {
+		ch <- prometheus.NewInvalidMetric(c.runs, err)
+		return
+	}
+
+	runs := map[string]map[v1.ActivityStatusType]int{}
+	running := map[string]int{}
+	runDurations := map[string]*histogram{}
+	stageDurations := map[string]map[string]*histogram{}
+	queueDurations := map[string]*histogram{}
+
+	for i := range activities.Items {
+		spec := &activities.Items[i].Spec
+		repository := ActivityRepository(&activities.Items[i])
+		if runs[repository] == nil {
+			runs[repository] = map[v1.ActivityStatusType]int{}
+		}
+		switch spec.Status {
+		case v1.ActivityStatusTypeSucceeded, v1.ActivityStatusTypeFailed, v1.ActivityStatusTypeError, v1.ActivityStatusTypeAborted:
+			runs[repository][spec.Status]++
+			if spec.StartedTimestamp != nil && spec.CompletedTimestamp != nil {
+				observe(runDurations, repository, spec.CompletedTimestamp.Sub(spec.StartedTimestamp.Time).Seconds())
+			}
+		case v1.ActivityStatusTypeRunning, v1.ActivityStatusTypePending, v1.ActivityStatusTypeWaitingForApproval:
+			running[repository]++
+		}
+
+		created := activities.Items[i].CreationTimestamp
+		if spec.StartedTimestamp != nil && !created.IsZero() && spec.StartedTimestamp.After(created.Time) {
+			observe(queueDurations, repository, spec.StartedTimestamp.Sub(created.Time).Seconds())
+		}
+
+		for _, step := range spec.Steps {
+			stage := step.Stage
+			if stage == nil || stage.StartedTimestamp == nil || stage.CompletedTimestamp == nil {
+				continue
+			}
+			if stageDurations[repository] == nil {
+				stageDurations[repository] = map[string]*histogram{}
+			}
+			observe(stageDurations[repository], stage.Name, stage.CompletedTimestamp.Sub(stage.StartedTimestamp.Time).Seconds())
+		}
+	}
+
+	for repository, statuses := range runs {
+		completed := 0
+		for status, count := range statuses {
+			completed += count
+			ch <- prometheus.MustNewConstMetric(c.runs, prometheus.CounterValue, float64(count), repository, string(status))
+		}
+		if completed > 0 {
+			ratio := float64(statuses[v1.ActivityStatusTypeSucceeded]) / float64(completed)
+			ch <- prometheus.MustNewConstMetric(c.successRatio, prometheus.GaugeValue, ratio, repository)
+		}
+		ch <- prometheus.MustNewConstMetric(c.running, prometheus.GaugeValue, float64(running[repository]), repository)
+	}
+	for repository, h := range runDurations {
+		ch <- prometheus.MustNewConstHistogram(c.runDuration, h.count, h.sum, h.buckets, repository)
+	}
+	for repository, h := range queueDurations {
+		ch <- prometheus.MustNewConstHistogram(c.queueDuration, h.count, h.sum, h.buckets, repository)
+	}
+	for repository, stages := range stageDurations {
+		for stage, h := range stages {
+			ch <- prometheus.MustNewConstHistogram(c.stageDuration, h.count, h.sum, h.buckets, repository, stage)
+		}
+	}
+}
+
+func observe(histograms map[string]*histogram, key string, value float64) {
+	h := histograms[key]
+	if h == nil {
+		h = &histogram{}
+		histograms[key] = h
+	}
+	h.observe(value)
+}
+
+// ActivityRepository returns the 'owner/repository' name of the git repository of the activity which defaults
+// to the name of the pipeline without the branch
+func ActivityRepository(activity *v1.PipelineActivity) string {
+	spec := &activity.Spec
+	if spec.GitOwner != "" && spec.GitRepository != "" {
+		return spec.GitOwner + "/" + spec.GitRepository
+	}
+	paths := strings.Split(spec.Pipeline, "/")
+	if len(paths) > 1 {
+		paths = paths[:len(paths)-1]
+	}
+	return strings.Join(paths, "/")
+}
@@ -0,0 +1,91 @@
+package metrics_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/jenkins-x/jx/pkg/apis/jenkins.io/v1"
+	"github.com/jenkins-x/jx/pkg/client/clientset/versioned/fake"
+	"github.com/jenkins-x/jx/pkg/metrics"
+	"github.com/prometheus/client_golang/prometheus"
+	dto "github.com/prometheus/client_model/go"
+	"github.com/stretchr/testify/assert"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestPipelineCollector(t *testing.T) {
+	t.Parallel()
+	now := time.Now()
+	activity := func(name string, status v1.ActivityStatusType, queued time.Duration, duration time.Duration) *v1.PipelineActivity {
+		created := metav1.NewTime(now.Add(-time.Hour))
+		started := metav1.NewTime(created.Add(queued))
+		completed := metav1.NewTime(started.Add(duration))
+		return &v1.PipelineActivity{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:              name,
+				Namespace:         "jx",
+				CreationTimestamp: created,
+			},
+			Spec: v1.PipelineActivitySpec{
+				Pipeline:           "myorg/myapp/master",
+				Status:             status,
+				StartedTimestamp:   &started,
+				CompletedTimestamp: &completed,
+				Steps: []v1.PipelineActivityStep{
+					{
+						Kind: v1.ActivityStepKindTypeStage,
+						Stage: &v1.StageActivityStep{
+							CoreActivityStep: v1.CoreActivityStep{
+								Name:               "Build",
+								StartedTimestamp:   &started,
+								CompletedTimestamp: &completed,
+							},
+						},
+					},
+				},
+			},
+		}
+	}
+	jxClient := fake.NewSimpleClientset(
+		activity("myorg-myapp-master-1", v1.ActivityStatusTypeSucceeded, time.Second*10, time.Minute*2),
+		activity("myorg-myapp-master-2", v1.ActivityStatusTypeSucceeded, time.Second*20, time.Minute*4),
+		activity("myorg-myapp-master-3", v1.ActivityStatusTypeFailed, time.Second*30, time.Minute),
+		activity("myorg-myapp-master-4", v1.ActivityStatusTypeRunning, time.Second*40, 0),
+	)
+
+	registry := prometheus.NewRegistry()
+	registry.MustRegister(metrics.NewPipelineCollector(jxClient, "jx"))
+	families, err := registry.Gather()
+	assert.NoError(t, err)
+
+	actual := map[string]*dto.MetricFamily{}
+	for _, family := range families {
+		actual[family.GetName()] = family
+	}
+
+	assert.Equal(t, "myorg/myapp", actual["jx_pipeline_success_ratio"].Metric[0].Label[0].GetValue())
+	assert.InDelta(t, 2.0/3.0, actual["jx_pipeline_success_ratio"].Metric[0].GetGauge().GetValue(), 0.001)
+	assert.Equal(t, 1.0, actual["jx_pipeline_runs_running"].Metric[0].GetGauge().GetValue())
+	assert.Equal(t, 2, len(actual["jx_pipeline_runs_total"].Metric))
+
+	runDuration := actual["jx_pipeline_run_duration_seconds"].Metric[0].GetHistogram()
+	assert.Equal(t, uint64(3), runDuration.GetSampleCount())
+	assert.Equal(t, 420.0, runDuration.GetSampleSum())
+
+	queueDuration := actual["jx_pipeline_queue_duration_seconds"].Metric[0].GetHistogram()
+	assert.Equal(t, uint64(4), queueDuration.GetSampleCount())
+	assert.Equal(t, 100.0, queueDuration.GetSampleSum())
+
+	stageDuration := actual["jx_pipeline_stage_duration_seconds"].Metric[0]
+	assert.Equal(t, uint64(4), stageDuration.GetHistogram().GetSampleCount())
+}
+
+func TestActivityRepository(t *testing.T) {
+	t.Parallel()
+	assert.Equal(t, "myorg/myapp", metrics.ActivityRepository(&v1.PipelineActivity{
+		Spec: v1.PipelineActivitySpec{Pipeline: "myorg/myapp/master"},
+	}))
+	assert.Equal(t, "owner/repo", metrics.ActivityRepository(&v1.PipelineActivity{
+		Spec: v1.PipelineActivitySpec{Pipeline: "myorg/myapp/master", GitOwner: "owner", GitRepository: "repo"},
+	}))
+}
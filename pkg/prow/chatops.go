@@ -0,0 +1,77 @@
+package prow
+
+import (
+	"regexp"
+	"strings"
+)
+
+const (
+	// ChatOpsTest the chat command to run the pipelines of a pull request such as '/test all' or '/test <context>'
+	ChatOpsTest = "test"
+	// ChatOpsRetest the chat command to re-run the pipelines of a pull request
+	ChatOpsRetest = "retest"
+	// ChatOpsApprove the chat command to approve a pipeline waiting for approval
+	ChatOpsApprove = "approve"
+
+	// PullRequestKind the kind of pipeline which runs on pull requests
+	PullRequestKind = "pullRequest"
+)
+
+var chatOpsCommandRegex = regexp.MustCompile(`(?m)^/(test|retest|approve)((?:[ \t]+[^\s,]+,?)*)[ \t]*$`)
+
+// ChatOpsCommand a Prow style chat command such as '/test all' in a comment on a pull request
+type ChatOpsCommand struct {
+	Name string
+	Args []string
+}
+
+// ParseChatOpsCommands parses the chat commands in the given pull request comment
+func ParseChatOpsCommands(comment string) []*ChatOpsCommand {
+	answer := []*ChatOpsCommand{}
+	for _, match := range chatOpsCommandRegex.FindAllStringSubmatch(comment, -1) {
+		command := &ChatOpsCommand{
+			Name: match[1],
+		}
+		for _, arg := range strings.Fields(match[2]) {
+			arg = strings.TrimSuffix(arg, ",")
+			if arg != "" {
+				command.Args = append(command.Args, arg)
+			}
+		}
+		answer = append(answer, command)
+	}
+	return answer
+}
+
+// MatchesContext returns true if this command should run the pipeline with the given context
+func (c *ChatOpsCommand) MatchesContext(context string) bool {
+	switch c.Name {
+	case ChatOpsRetest:
+		return true
+	case ChatOpsTest:
+		for _, arg := range c.Args {
+			if arg == "all" || arg == "this" || arg == context {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// DefaultContext returns the default context name reported on pull requests for the given kind of pipeline
+func DefaultContext(kind string) string {
+	if kind == "" || kind == PullRequestKind {
+		return ServerlessJenins
+	}
+	return kind
+}
+
+// TestTrigger returns the Prow trigger regular expression which runs a presubmit via '/test all', '/test this'
+// or '/test <context>' for any of the given contexts
+func TestTrigger(contexts ...string) string {
+	names := "all| this"
+	for _, context := range contexts {
+		names += "| " + regexp.QuoteMeta(context)
+	}
+	return "(?m)^/test( " + names + "),?(\\s+|$)"
+}
@@ -0,0 +1,36 @@
+package prow_test
+
+import (
+	"regexp"
+	"testing"
+
+	"github.com/jenkins-x/jx/pkg/prow"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseChatOpsCommands(t *testing.T) {
+	t.Parallel()
+	commands := prow.ParseChatOpsCommands("looks good\n/test integration, lint\n/approve\nthanks /retest")
+	if assert.Equal(t, 2, len(commands)) {
+		assert.Equal(t, prow.ChatOpsTest, commands[0].Name)
+		assert.Equal(t, []string{"integration", "lint"}, commands[0].Args)
+		assert.True(t, commands[0].MatchesContext("lint"))
+		assert.False(t, commands[0].MatchesContext(prow.ServerlessJenins))
+		assert.Equal(t, prow.ChatOpsApprove, commands[1].Name)
+	}
+
+	commands = prow.ParseChatOpsCommands("/retest")
+	if assert.Equal(t, 1, len(commands)) {
+		assert.True(t, commands[0].MatchesContext(prow.ServerlessJenins))
+	}
+}
+
+func TestTestTrigger(t *testing.T) {
+	t.Parallel()
+	assert.Equal(t, "(?m)^/test( all| this),?(\\s+|$)", prow.TestTrigger())
+
+	r := regexp.MustCompile(prow.TestTrigger("integration"))
+	assert.True(t, r.MatchString("/test integration"))
+	assert.True(t, r.MatchString("/test all"))
+	assert.False(t, r.MatchString("/test lint"))
+}
@@ -103,7 +103,7 @@ func (o *Options) createPreSubmitEnvironment() config.Presubmit {
 
 	ps.BuildSpec = spec
 	ps.RerunCommand = "/test this"
-	ps.Trigger = "(?m)^/test( all| this),?(\\s+|$)"
+	ps.Trigger = TestTrigger()
 
 	return ps
 }
@@ -162,7 +162,7 @@ func (o *Options) createPreSubmitApplication() config.Presubmit {
 	ps.Context = ServerlessJenins
 	ps.Name = ServerlessJenins
 	ps.RerunCommand = "/test this"
-	ps.Trigger = "(?m)^/test( all| this),?(\\s+|$)"
+	ps.Trigger = TestTrigger()
 	ps.AlwaysRun = false
 	ps.SkipReport = false
 	ps.Agent = KnativeBuildAgent
@@ -178,7 +178,7 @@ func (o *Options) createPreSubmitApplication() config.Presubmit {
 
 	ps.BuildSpec = spec
 	ps.RerunCommand = "/test this"
-	ps.Trigger = "(?m)^/test( all| this),?(\\s+|$)"
+	ps.Trigger = TestTrigger()
 
 	return ps
 }
@@ -0,0 +1,61 @@
+package signing
+
+import (
+	"bytes"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/pkg/errors"
+	"golang.org/x/crypto/openpgp"
+)
+
+// SignDetached signs the data with the first private key in the ASCII armored key ring returning an ASCII armored
+// detached signature. The passphrase is used to decrypt the private key if it is encrypted
+func SignDetached(data []byte, armoredPrivateKey []byte, passphrase []byte) (string, error) {
+	keyRing, err := openpgp.ReadArmoredKeyRing(bytes.NewReader(armoredPrivateKey))
+	if err != nil {
+		return "", errors.Wrap(err, "failed to read the PGP private key")
+	}
+	var signer *openpgp.Entity
+	for _, entity := range keyRing {
+		if entity.PrivateKey != nil {
+			signer = entity
+			break
+		}
+	}
+	if signer == nil {
+		return "", fmt.Errorf("no PGP private key found")
+	}
+	if signer.PrivateKey.Encrypted {
+		err = signer.PrivateKey.Decrypt(passphrase)
+		if err != nil {
+			return "", errors.Wrap(err, "failed to decrypt the PGP private key")
+		}
+	}
+	var buffer bytes.Buffer
+	err = openpgp.ArmoredDetachSign(&buffer, signer, bytes.NewReader(data), nil)
+	if err != nil {
+		return "", errors.Wrap(err, "failed to sign")
+	}
+	return buffer.String(), nil
+}
+
+// VerifyDetached verifies the ASCII armored detached signature of the data was created by one of the keys in the
+// ASCII armored public key ring returning the identities of the signer
+func VerifyDetached(data []byte, signature string, armoredKeyRing []byte) (string, error) {
+	keyRing, err := openpgp.ReadArmoredKeyRing(bytes.NewReader(armoredKeyRing))
+	if err != nil {
+		return "", errors.Wrap(err, "failed to read the PGP public keys")
+	}
+	signer, err := openpgp.CheckArmoredDetachedSignature(keyRing, bytes.NewReader(data), strings.NewReader(signature))
+	if err != nil {
+		return "", errors.Wrap(err, "invalid signature")
+	}
+	names := []string{}
+	for name := range signer.Identities {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return strings.Join(names, ", "), nil
+}
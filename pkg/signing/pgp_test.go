@@ -0,0 +1,49 @@
+package signing_test
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/jenkins-x/jx/pkg/signing"
+	"github.com/stretchr/testify/assert"
+	"golang.org/x/crypto/openpgp"
+	"golang.org/x/crypto/openpgp/armor"
+)
+
+func armoredKeys(t *testing.T, name string) ([]byte, []byte) {
+	entity, err := openpgp.NewEntity(name, "", name+"@example.com", nil)
+	assert.NoError(t, err)
+
+	var private bytes.Buffer
+	w, err := armor.Encode(&private, openpgp.PrivateKeyType, nil)
+	assert.NoError(t, err)
+	assert.NoError(t, entity.SerializePrivate(w, nil))
+	assert.NoError(t, w.Close())
+
+	var public bytes.Buffer
+	w, err = armor.Encode(&public, openpgp.PublicKeyType, nil)
+	assert.NoError(t, err)
+	assert.NoError(t, entity.Serialize(w))
+	assert.NoError(t, w.Close())
+	return private.Bytes(), public.Bytes()
+}
+
+func TestSignAndVerifyDetached(t *testing.T) {
+	t.Parallel()
+	privateKey, publicKey := armoredKeys(t, "release")
+	_, otherPublicKey := armoredKeys(t, "other")
+	data := []byte("apiVersion: build.knative.dev/v1alpha1\nkind: Build\n")
+
+	signature, err := signing.SignDetached(data, privateKey, nil)
+	assert.NoError(t, err)
+
+	signer, err := signing.VerifyDetached(data, signature, publicKey)
+	assert.NoError(t, err)
+	assert.Equal(t, "release <release@example.com>", signer)
+
+	_, err = signing.VerifyDetached(append(data, []byte("# tampered")...), signature, publicKey)
+	assert.Error(t, err, "should fail to verify modified data")
+
+	_, err = signing.VerifyDetached(data, signature, otherPublicKey)
+	assert.Error(t, err, "should fail to verify with an unknown key")
+}
@@ -0,0 +1,200 @@
+package sonar
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/jenkins-x/jx/pkg/util"
+)
+
+const (
+	// QualityGateOK the status of a quality gate which passed
+	QualityGateOK = "OK"
+	// QualityGateWarn the status of a quality gate with warnings
+	QualityGateWarn = "WARN"
+	// QualityGateError the status of a quality gate which failed
+	QualityGateError = "ERROR"
+
+	taskSuccess    = "SUCCESS"
+	taskPending    = "PENDING"
+	taskRunning    = "IN_PROGRESS"
+	taskPath       = "/api/ce/task?id=%s"
+	gateStatusPath = "/api/qualitygates/project_status?analysisId=%s"
+)
+
+// ReportTask the report-task.txt file written by the SonarQube scanners which describes the analysis submitted to
+// the server
+type ReportTask struct {
+	ProjectKey   string
+	ServerURL    string
+	DashboardURL string
+	CeTaskID     string
+	CeTaskURL    string
+}
+
+// Condition a condition of a quality gate
+type Condition struct {
+	Status         string `json:"status"`
+	MetricKey      string `json:"metricKey"`
+	Comparator     string `json:"comparator"`
+	ErrorThreshold string `json:"errorThreshold"`
+	ActualValue    string `json:"actualValue"`
+}
+
+// ProjectStatus the status of the quality gate of an analysis
+type ProjectStatus struct {
+	Status     string      `json:"status"`
+	Conditions []Condition `json:"conditions"`
+}
+
+// Client a client of the web API of a SonarQube server
+type Client struct {
+	ServerURL  string
+	Token      string
+	HTTPClient *http.Client
+}
+
+// NewClient creates a client of the SonarQube server authenticating with the token
+func NewClient(serverURL string, token string) *Client {
+	return &Client{
+		ServerURL:  strings.TrimSuffix(serverURL, "/"),
+		Token:      token,
+		HTTPClient: http.DefaultClient,
+	}
+}
+
+// LoadReportTask loads the report-task.txt file written by a SonarQube scanner
+func LoadReportTask(fileName string) (*ReportTask, error) {
+	data, err := ioutil.ReadFile(fileName)
+	if err != nil {
+		return nil, err
+	}
+	answer := &ReportTask{}
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	for scanner.Scan() {
+		values := strings.SplitN(strings.TrimSpace(scanner.Text()), "=", 2)
+		if len(values) != 2 {
+			continue
+		}
+		switch values[0] {
+		case "projectKey":
+			answer.ProjectKey = values[1]
+		case "serverUrl":
+			answer.ServerURL = values[1]
+		case "dashboardUrl":
+			answer.DashboardURL = values[1]
+		case "ceTaskId":
+			answer.CeTaskID = values[1]
+		case "ceTaskUrl":
+			answer.CeTaskURL = values[1]
+		}
+	}
+	if answer.CeTaskID == "" {
+		return answer, fmt.Errorf("no ceTaskId in the SonarQube report task %s", fileName)
+	}
+	return answer, nil
+}
+
+// WaitForAnalysis waits for the server to process the analysis of the task returning the id of the analysis
+func (c *Client) WaitForAnalysis(taskID string, timeout time.Duration, interval time.Duration) (string, error) {
+	deadline := time.Now().Add(timeout)
+	for {
+		result := struct {
+			Task struct {
+				Status       string `json:"status"`
+				AnalysisID   string `json:"analysisId"`
+				ErrorMessage string `json:"errorMessage"`
+			} `json:"task"`
+		}{}
+		err := c.get(fmt.Sprintf(taskPath, url.QueryEscape(taskID)), &result)
+		if err != nil {
+			return "", err
+		}
+		task := result.Task
+		switch task.Status {
+		case taskSuccess:
+			return task.AnalysisID, nil
+		case taskPending, taskRunning:
+		default:
+			return "", fmt.Errorf("the SonarQube analysis task %s has status %s %s", taskID, task.Status, task.ErrorMessage)
+		}
+		if time.Now().After(deadline) {
+			return "", fmt.Errorf("timed out after %s waiting for the SonarQube analysis task %s", timeout.String(), taskID)
+		}
+		time.Sleep(interval)
+	}
+}
+
+// QualityGate returns the status of the quality gate of the analysis
+func (c *Client) QualityGate(analysisID string) (*ProjectStatus, error) {
+	result := struct {
+		ProjectStatus ProjectStatus `json:"projectStatus"`
+	}{}
+	err := c.get(fmt.Sprintf(gateStatusPath, url.QueryEscape(analysisID)), &result)
+	if err != nil {
+		return nil, err
+	}
+	return &result.ProjectStatus, nil
+}
+
+func (c *Client) get(path string, result interface{}) error {
+	req, err := http.NewRequest(http.MethodGet, c.ServerURL+path, nil)
+	if err != nil {
+		return err
+	}
+	if c.Token != "" {
+		// SonarQube authenticates tokens as the user name without a password
+		req.Header.Add("Authorization", "Basic "+util.BasicAuth(c.Token, ""))
+	}
+	resp, err := c.HTTPClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("error getting %s from SonarQube: %s", path, err)
+	}
+	defer resp.Body.Close()
+	data, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return err
+	}
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("error response getting %s from SonarQube: %s %s", path, resp.Status, strings.TrimSpace(string(data)))
+	}
+	return json.Unmarshal(data, result)
+}
+
+// Markdown returns the Markdown of the quality gate which pull requests are decorated with
+func (s *ProjectStatus) Markdown(dashboardURL string) string {
+	var buf bytes.Buffer
+	title := "SonarQube Quality Gate"
+	if dashboardURL != "" {
+		title = util.MarkdownLink(title, dashboardURL)
+	}
+	buf.WriteString(fmt.Sprintf("%s %s **%s**\n", qualityGateIcon(s.Status), title, s.Status))
+	if len(s.Conditions) > 0 {
+		buf.WriteString("\n| Status | Metric | Value | Threshold |\n")
+		buf.WriteString("| --- | --- | --- | --- |\n")
+		for _, c := range s.Conditions {
+			buf.WriteString(fmt.Sprintf("| %s | %s | %s | %s %s |\n", qualityGateIcon(c.Status), c.MetricKey, c.ActualValue, c.Comparator, c.ErrorThreshold))
+		}
+	}
+	return buf.String()
+}
+
+func qualityGateIcon(status string) string {
+	switch status {
+	case QualityGateOK:
+		return ":white_check_mark:"
+	case QualityGateWarn:
+		return ":warning:"
+	case QualityGateError:
+		return ":x:"
+	default:
+		return ":grey_question:"
+	}
+}
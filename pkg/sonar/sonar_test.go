@@ -0,0 +1,73 @@
+package sonar_test
+
+import (
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/jenkins-x/jx/pkg/sonar"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSonarQualityGate(t *testing.T) {
+	t.Parallel()
+	dir, err := ioutil.TempDir("", "test-sonar")
+	assert.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	requests := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		user, password, ok := r.BasicAuth()
+		if !ok || user != "mytoken" || password != "" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		switch r.URL.Path {
+		case "/api/ce/task":
+			assert.Equal(t, "AWxyz", r.URL.Query().Get("id"))
+			requests++
+			status := "IN_PROGRESS"
+			if requests > 1 {
+				status = "SUCCESS"
+			}
+			fmt.Fprintf(w, `{"task": {"id": "AWxyz", "status": "%s", "analysisId": "AWabc"}}`, status)
+		case "/api/qualitygates/project_status":
+			assert.Equal(t, "AWabc", r.URL.Query().Get("analysisId"))
+			fmt.Fprint(w, `{"projectStatus": {"status": "ERROR", "conditions": [{"status": "ERROR", "metricKey": "new_coverage", "comparator": "LT", "errorThreshold": "80", "actualValue": "42.5"}]}}`)
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	reportTaskFile := filepath.Join(dir, "report-task.txt")
+	reportTask := fmt.Sprintf("projectKey=myapp\nserverUrl=%s\ndashboardUrl=%s/dashboard?id=myapp&pullRequest=12\nceTaskId=AWxyz\nceTaskUrl=%s/api/ce/task?id=AWxyz\n", server.URL, server.URL, server.URL)
+	err = ioutil.WriteFile(reportTaskFile, []byte(reportTask), 0644)
+	assert.NoError(t, err)
+
+	task, err := sonar.LoadReportTask(reportTaskFile)
+	assert.NoError(t, err)
+	assert.Equal(t, "myapp", task.ProjectKey)
+	assert.Equal(t, "AWxyz", task.CeTaskID)
+
+	client := sonar.NewClient(server.URL+"/", "mytoken")
+	analysisID, err := client.WaitForAnalysis(task.CeTaskID, time.Minute, time.Millisecond)
+	assert.NoError(t, err)
+	assert.Equal(t, "AWabc", analysisID)
+	assert.Equal(t, 2, requests, "the task should be polled until the analysis completes")
+
+	status, err := client.QualityGate(analysisID)
+	assert.NoError(t, err)
+	assert.Equal(t, sonar.QualityGateError, status.Status)
+	markdown := status.Markdown(task.DashboardURL)
+	assert.Contains(t, markdown, ":x: [SonarQube Quality Gate]("+task.DashboardURL+") **ERROR**")
+	assert.Contains(t, markdown, "| :x: | new_coverage | 42.5 | LT 80 |")
+
+	_, err = sonar.NewClient(server.URL, "wrong").QualityGate(analysisID)
+	assert.Error(t, err)
+}
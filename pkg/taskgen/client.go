@@ -0,0 +1,50 @@
+package taskgen
+
+import (
+	"bytes"
+	"io"
+
+	"golang.org/x/net/context"
+)
+
+// taskgen.pb.go is generated from taskgen.proto with protoc and the protoc-gen-go version of Gopkg.lock
+//go:generate protoc --go_out=plugins=grpc,paths=source_relative:. taskgen.proto
+
+// Generate requests the generation of the builds of a git repository passing each progress message to the progress
+// function and returning the generated resources as YAML documents or nil if no builds were generated
+func Generate(ctx context.Context, client TaskgenClient, request *GenerateRequest, progress func(string)) ([]byte, error) {
+	var buf bytes.Buffer
+	err := GenerateTo(ctx, client, request, progress, &buf)
+	if err != nil || buf.Len() == 0 {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// GenerateTo requests the generation of the builds of a git repository passing each progress message to the progress
+// function and writing the YAML documents of the generated resources to the writer as they are received so that large
+// generations are not held in memory
+func GenerateTo(ctx context.Context, client TaskgenClient, request *GenerateRequest, progress func(string), out io.Writer) error {
+	stream, err := client.Generate(ctx, request)
+	if err != nil {
+		return err
+	}
+	for {
+		response, err := stream.Recv()
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		if response.Progress != "" && progress != nil {
+			progress(response.Progress)
+		}
+		if len(response.Resources) > 0 {
+			_, err = out.Write(response.Resources)
+			if err != nil {
+				return err
+			}
+		}
+	}
+}
@@ -104,6 +104,29 @@ func NewTerminal(t *testing.T) (*expect.Console, *vt10x.State, *terminal.Stdio)
 	return c, state, term
 }
 
+// batchModeReader the input of a terminal which fails the test when it is read
+type batchModeReader struct {
+	*os.File
+	t *testing.T
+}
+
+// Read fails the test as a command in batch mode should never prompt
+func (r *batchModeReader) Read(p []byte) (int, error) {
+	r.t.Errorf("Test: %s read from the terminal in batch mode\n%s", r.t.Name(), string(debug.Stack()))
+	return 0, io.EOF
+}
+
+// NewBatchModeTerminal mock terminal whose input fails the test if it is read so that tests can assert that a
+// command in batch mode never prompts even though its input is a terminal
+func NewBatchModeTerminal(t *testing.T) (*expect.Console, *terminal.Stdio) {
+	c, _, term := NewTerminal(t)
+	term.In = &batchModeReader{
+		File: c.Tty(),
+		t:    t,
+	}
+	return c, term
+}
+
 // TestCloser closes io
 func TestCloser(t *testing.T, closer io.Closer) {
 	if err := closer.Close(); err != nil {
@@ -0,0 +1,214 @@
+package tracing
+
+import (
+	"bytes"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	// EnvTraceParent the environment variable containing the W3C trace context of the span of a step
+	EnvTraceParent = "TRACEPARENT"
+	// EnvServiceName the environment variable containing the name of the service the spans are reported as
+	EnvServiceName = "OTEL_SERVICE_NAME"
+	// EnvExporterEndpoint the environment variable containing the OTLP/HTTP endpoint the spans are exported to
+	EnvExporterEndpoint = "OTEL_EXPORTER_OTLP_ENDPOINT"
+
+	// tracesPath the path of the OTLP/HTTP endpoint receiving traces
+	tracesPath = "/v1/traces"
+
+	traceParentVersion = "00"
+	traceFlagsSampled  = "01"
+
+	spanKindInternal = 1
+	statusCodeOK     = 1
+	statusCodeError  = 2
+
+	scopeName = "jx"
+)
+
+// Span a completed span of a trace such as the run of a pipeline or of one of its steps
+type Span struct {
+	TraceID      string
+	SpanID       string
+	ParentSpanID string
+	Name         string
+	Start        time.Time
+	End          time.Time
+	Attributes   map[string]string
+	Failed       bool
+}
+
+// NewTraceID returns a random trace ID
+func NewTraceID() (string, error) {
+	return randomID(16)
+}
+
+// NewSpanID returns a random span ID
+func NewSpanID() (string, error) {
+	return randomID(8)
+}
+
+// SpanID returns the span ID of the named span of the trace. The IDs are derived from the trace ID so that the spans
+// of the steps can be exported by a different process than the one which propagated their context
+func SpanID(traceID string, name string) string {
+	sum := sha256.Sum256([]byte(traceID + "/" + name))
+	return hex.EncodeToString(sum[:8])
+}
+
+// TraceParent returns the W3C trace context of the span
+func TraceParent(traceID string, spanID string) string {
+	return strings.Join([]string{traceParentVersion, traceID, spanID, traceFlagsSampled}, "-")
+}
+
+// ParseTraceParent returns the trace ID and the span ID of the W3C trace context
+func ParseTraceParent(traceParent string) (string, string, error) {
+	parts := strings.Split(traceParent, "-")
+	if len(parts) != 4 || len(parts[1]) != 32 || len(parts[2]) != 16 {
+		return "", "", fmt.Errorf("invalid trace context %s", traceParent)
+	}
+	return parts[1], parts[2], nil
+}
+
+// Export sends the spans to the OTLP/HTTP endpoint such as 'http://jaeger-collector:4318' as the spans of the
+// service
+func Export(endpoint string, serviceName string, spans []Span) error {
+	if len(spans) == 0 {
+		return nil
+	}
+	data, err := json.Marshal(exportRequest(serviceName, spans))
+	if err != nil {
+		return err
+	}
+	u := strings.TrimSuffix(endpoint, "/")
+	if !strings.HasSuffix(u, tracesPath) {
+		u += tracesPath
+	}
+	client := http.Client{Timeout: 30 * time.Second}
+	resp, err := client.Post(u, "application/json", bytes.NewReader(data))
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		body, _ := ioutil.ReadAll(resp.Body)
+		return fmt.Errorf("failed to export %d spans to %s: status %d %s", len(spans), u, resp.StatusCode, strings.TrimSpace(string(body)))
+	}
+	return nil
+}
+
+type otlpRequest struct {
+	ResourceSpans []otlpResourceSpans `json:"resourceSpans"`
+}
+
+type otlpResourceSpans struct {
+	Resource   otlpResource     `json:"resource"`
+	ScopeSpans []otlpScopeSpans `json:"scopeSpans"`
+}
+
+type otlpResource struct {
+	Attributes []otlpAttribute `json:"attributes"`
+}
+
+type otlpScopeSpans struct {
+	Scope otlpScope  `json:"scope"`
+	Spans []otlpSpan `json:"spans"`
+}
+
+type otlpScope struct {
+	Name string `json:"name"`
+}
+
+type otlpSpan struct {
+	TraceID           string          `json:"traceId"`
+	SpanID            string          `json:"spanId"`
+	ParentSpanID      string          `json:"parentSpanId,omitempty"`
+	Name              string          `json:"name"`
+	Kind              int             `json:"kind"`
+	StartTimeUnixNano string          `json:"startTimeUnixNano"`
+	EndTimeUnixNano   string          `json:"endTimeUnixNano"`
+	Attributes        []otlpAttribute `json:"attributes,omitempty"`
+	Status            otlpStatus      `json:"status"`
+}
+
+type otlpAttribute struct {
+	Key   string    `json:"key"`
+	Value otlpValue `json:"value"`
+}
+
+type otlpValue struct {
+	StringValue string `json:"stringValue"`
+}
+
+type otlpStatus struct {
+	Code int `json:"code"`
+}
+
+// exportRequest returns the OTLP JSON request exporting the spans
+func exportRequest(serviceName string, spans []Span) *otlpRequest {
+	answer := []otlpSpan{}
+	for _, span := range spans {
+		status := otlpStatus{Code: statusCodeOK}
+		if span.Failed {
+			status.Code = statusCodeError
+		}
+		answer = append(answer, otlpSpan{
+			TraceID:           span.TraceID,
+			SpanID:            span.SpanID,
+			ParentSpanID:      span.ParentSpanID,
+			Name:              span.Name,
+			Kind:              spanKindInternal,
+			StartTimeUnixNano: strconv.FormatInt(span.Start.UnixNano(), 10),
+			EndTimeUnixNano:   strconv.FormatInt(span.End.UnixNano(), 10),
+			Attributes:        attributes(span.Attributes),
+			Status:            status,
+		})
+	}
+	return &otlpRequest{
+		ResourceSpans: []otlpResourceSpans{
+			{
+				Resource: otlpResource{
+					Attributes: attributes(map[string]string{"service.name": serviceName}),
+				},
+				ScopeSpans: []otlpScopeSpans{
+					{
+						Scope: otlpScope{Name: scopeName},
+						Spans: answer,
+					},
+				},
+			},
+		},
+	}
+}
+
+// attributes returns the attributes sorted by key
+func attributes(values map[string]string) []otlpAttribute {
+	keys := []string{}
+	for k := range values {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	answer := []otlpAttribute{}
+	for _, k := range keys {
+		answer = append(answer, otlpAttribute{Key: k, Value: otlpValue{StringValue: values[k]}})
+	}
+	return answer
+}
+
+func randomID(size int) (string, error) {
+	data := make([]byte, size)
+	_, err := rand.Read(data)
+	if err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(data), nil
+}
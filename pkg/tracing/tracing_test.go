@@ -0,0 +1,76 @@
+package tracing_test
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/jenkins-x/jx/pkg/tracing"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTraceParent(t *testing.T) {
+	t.Parallel()
+	traceID, err := tracing.NewTraceID()
+	assert.NoError(t, err)
+	assert.Len(t, traceID, 32)
+
+	spanID := tracing.SpanID(traceID, "build")
+	assert.Len(t, spanID, 16)
+	assert.Equal(t, spanID, tracing.SpanID(traceID, "build"), "span IDs should be derived from the trace ID")
+	assert.NotEqual(t, spanID, tracing.SpanID(traceID, "test"))
+
+	traceParent := tracing.TraceParent(traceID, spanID)
+	assert.Equal(t, "00-"+traceID+"-"+spanID+"-01", traceParent)
+	parsedTraceID, parsedSpanID, err := tracing.ParseTraceParent(traceParent)
+	assert.NoError(t, err)
+	assert.Equal(t, traceID, parsedTraceID)
+	assert.Equal(t, spanID, parsedSpanID)
+
+	_, _, err = tracing.ParseTraceParent("00-1234-5678-01")
+	assert.Error(t, err)
+}
+
+func TestExport(t *testing.T) {
+	t.Parallel()
+	var body map[string]interface{}
+	path := ""
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		path = r.URL.Path
+		data, err := ioutil.ReadAll(r.Body)
+		assert.NoError(t, err)
+		err = json.Unmarshal(data, &body)
+		assert.NoError(t, err)
+	}))
+	defer server.Close()
+
+	start := time.Unix(1500000000, 0)
+	err := tracing.Export(server.URL, "myorg/myapp/master", []tracing.Span{
+		{
+			TraceID:      "0af7651916cd43dd8448eb211c80319c",
+			SpanID:       "b7ad6b7169203331",
+			ParentSpanID: "00f067aa0ba902b7",
+			Name:         "build",
+			Start:        start,
+			End:          start.Add(time.Minute),
+			Attributes:   map[string]string{"build": "1"},
+			Failed:       true,
+		},
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, "/v1/traces", path)
+
+	resourceSpans := body["resourceSpans"].([]interface{})[0].(map[string]interface{})
+	resource := resourceSpans["resource"].(map[string]interface{})
+	assert.Equal(t, []interface{}{map[string]interface{}{"key": "service.name", "value": map[string]interface{}{"stringValue": "myorg/myapp/master"}}}, resource["attributes"])
+	span := resourceSpans["scopeSpans"].([]interface{})[0].(map[string]interface{})["spans"].([]interface{})[0].(map[string]interface{})
+	assert.Equal(t, "0af7651916cd43dd8448eb211c80319c", span["traceId"])
+	assert.Equal(t, "00f067aa0ba902b7", span["parentSpanId"])
+	assert.Equal(t, "build", span["name"])
+	assert.Equal(t, "1500000000000000000", span["startTimeUnixNano"])
+	assert.Equal(t, "1500000060000000000", span["endTimeUnixNano"])
+	assert.Equal(t, map[string]interface{}{"code": float64(2)}, span["status"])
+}
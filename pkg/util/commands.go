@@ -1,6 +1,7 @@
 package util
 
 import (
+	"context"
 	"io"
 	"os"
 	"os/exec"
@@ -23,6 +24,8 @@ type Command struct {
 	Out                io.Writer
 	Err                io.Writer
 	Env                map[string]string
+	// Context kills the command when it is cancelled if it is not nil
+	Context context.Context
 }
 
 // SetName Setter method for Name to enable use of interface instead of Command struct
@@ -123,7 +126,12 @@ func (c *Command) RunWithoutRetry() (string, error) {
 }
 
 func (c *Command) run() (string, error) {
-	e := exec.Command(c.Name, c.Args...)
+	var e *exec.Cmd
+	if c.Context != nil {
+		e = exec.CommandContext(c.Context, c.Name, c.Args...)
+	} else {
+		e = exec.Command(c.Name, c.Args...)
+	}
 	if c.Dir != "" {
 		e.Dir = c.Dir
 	}
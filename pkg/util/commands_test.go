@@ -1,6 +1,7 @@
 package util_test
 
 import (
+	"context"
 	"os"
 	"path/filepath"
 	"testing"
@@ -243,3 +244,18 @@ func TestRunQuiet(t *testing.T) {
 	os.Remove(exPath + "/" + tmpFileName)
 
 }
+
+func TestRunWithContext(t *testing.T) {
+	t.Parallel()
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+	cmd := util.Command{
+		Name:    "sleep",
+		Args:    []string{"30"},
+		Context: ctx,
+	}
+	start := time.Now()
+	_, err := cmd.RunWithoutRetry()
+	assert.Error(t, err)
+	assert.True(t, time.Since(start) < 10*time.Second, "the command should be killed when the context is cancelled")
+}
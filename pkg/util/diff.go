@@ -0,0 +1,48 @@
+package util
+
+import (
+	"strings"
+
+	"github.com/sergi/go-diff/diffmatchpatch"
+)
+
+// DiffLines returns a line based diff of the two texts where removed lines are prefixed with '-' and
+// added lines are prefixed with '+'. Unchanged lines are omitted. An empty string is returned if the texts are equal
+func DiffLines(oldText string, newText string) string {
+	if oldText == newText {
+		return ""
+	}
+	dmp := diffmatchpatch.New()
+	a, b, lines := dmp.DiffLinesToChars(oldText, newText)
+	diffs := dmp.DiffCharsToLines(dmp.DiffMain(a, b, false), lines)
+
+	buffer := []string{}
+	for _, d := range diffs {
+		prefix := ""
+		switch d.Type {
+		case diffmatchpatch.DiffDelete:
+			prefix = "- "
+		case diffmatchpatch.DiffInsert:
+			prefix = "+ "
+		default:
+			continue
+		}
+		for _, line := range strings.Split(strings.TrimSuffix(d.Text, "\n"), "\n") {
+			buffer = append(buffer, prefix+line)
+		}
+	}
+	return strings.Join(buffer, "\n")
+}
+
+// ColorDiffLines colors the output of DiffLines so that removed lines are red and added lines are green
+func ColorDiffLines(diff string) string {
+	lines := strings.Split(diff, "\n")
+	for i, line := range lines {
+		if strings.HasPrefix(line, "-") {
+			lines[i] = ColorError(line)
+		} else if strings.HasPrefix(line, "+") {
+			lines[i] = ColorInfo(line)
+		}
+	}
+	return strings.Join(lines, "\n")
+}
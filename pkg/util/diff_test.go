@@ -0,0 +1,14 @@
+package util_test
+
+import (
+	"testing"
+
+	"github.com/jenkins-x/jx/pkg/util"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDiffLines(t *testing.T) {
+	t.Parallel()
+	assert.Equal(t, "", util.DiffLines("a\nb\n", "a\nb\n"))
+	assert.Equal(t, "- b\n+ c\n+ d", util.DiffLines("a\nb\n", "a\nc\nd\n"))
+}
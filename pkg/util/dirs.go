@@ -57,6 +57,20 @@ func CacheDir() (string, error) {
 	return path, nil
 }
 
+// WorkspacesDir returns the directory of the temporary workspaces tracked by jx such as the clones of repositories
+func WorkspacesDir() (string, error) {
+	h, err := ConfigDir()
+	if err != nil {
+		return "", err
+	}
+	path := filepath.Join(h, "workspaces")
+	err = os.MkdirAll(path, DefaultWritePermissions)
+	if err != nil {
+		return "", err
+	}
+	return path, nil
+}
+
 func EnvironmentsDir() (string, error) {
 	h, err := ConfigDir()
 	if err != nil {
@@ -17,3 +17,64 @@ func CombineErrors(errs ...error) error {
 	}
 	return errors.NewAggregate(answer)
 }
+
+// The exit codes of the failure classes so that scripts can branch on the class of failure of a command
+const (
+	// ExitCodeMissingOption a required option is missing or an option is invalid
+	ExitCodeMissingOption = 2
+	// ExitCodeNotFound a resource such as a build pack or pod template could not be found
+	ExitCodeNotFound = 3
+	// ExitCodeUnreachable the cluster or a remote service could not be reached
+	ExitCodeUnreachable = 4
+	// ExitCodeValidation the configuration or a generated resource is not valid
+	ExitCodeValidation = 5
+	// ExitCodeGeneration a resource could not be generated
+	ExitCodeGeneration = 6
+	// ExitCodeInterrupted the command was interrupted by SIGINT or SIGTERM
+	ExitCodeInterrupted = 130
+)
+
+// ExitCodeError an error which makes a command exit with the exit code of its failure class
+type ExitCodeError struct {
+	Code int
+	Err  error
+}
+
+// Error returns the message of the error
+func (e *ExitCodeError) Error() string {
+	return e.Err.Error()
+}
+
+// Cause returns the underlying error
+func (e *ExitCodeError) Cause() error {
+	return e.Err
+}
+
+// WithExitCode returns the error with the exit code unless it already has an exit code
+func WithExitCode(err error, code int) error {
+	if err == nil || ExitCode(err) != 1 {
+		return err
+	}
+	return &ExitCodeError{Code: code, Err: err}
+}
+
+// ExitCode returns the exit code of the first error in the chain of causes with an exit code, 1 for other errors
+// and 0 if there is no error
+func ExitCode(err error) int {
+	if err == nil {
+		return 0
+	}
+	for err != nil {
+		if e, ok := err.(*ExitCodeError); ok {
+			return e.Code
+		}
+		cause, ok := err.(interface {
+			Cause() error
+		})
+		if !ok {
+			break
+		}
+		err = cause.Cause()
+	}
+	return 1
+}
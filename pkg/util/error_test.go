@@ -0,0 +1,26 @@
+package util_test
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/jenkins-x/jx/pkg/util"
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestExitCode(t *testing.T) {
+	t.Parallel()
+	assert.Equal(t, 0, util.ExitCode(nil))
+	assert.Equal(t, 1, util.ExitCode(fmt.Errorf("failed")))
+	assert.Equal(t, util.ExitCodeMissingOption, util.ExitCode(util.MissingOption("name")))
+	assert.Equal(t, util.ExitCodeMissingOption, util.ExitCode(util.InvalidOption("kind", "foo", []string{"release"})))
+
+	err := util.WithExitCode(fmt.Errorf("not found"), util.ExitCodeNotFound)
+	assert.Equal(t, "not found", err.Error())
+	assert.Equal(t, util.ExitCodeNotFound, util.ExitCode(errors.Wrap(err, "failed to load")))
+
+	// the exit code of the underlying error is kept
+	assert.Equal(t, util.ExitCodeNotFound, util.ExitCode(util.WithExitCode(errors.Wrap(err, "failed"), util.ExitCodeGeneration)))
+	assert.Nil(t, util.WithExitCode(nil, util.ExitCodeGeneration))
+}
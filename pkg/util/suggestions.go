@@ -16,11 +16,11 @@ func InvalidOptionError(option string, value string, err error) error {
 
 func InvalidOptionf(option string, value string, message string, a ...interface{}) error {
 	text := fmt.Sprintf(message, a...)
-	return fmt.Errorf("Invalid option: --%s %s\n%s", option, value, text)
+	return WithExitCode(fmt.Errorf("Invalid option: --%s %s\n%s", option, value, text), ExitCodeMissingOption)
 }
 
 func MissingOption(name string) error {
-	return fmt.Errorf("Missing option: --%s", name)
+	return WithExitCode(fmt.Errorf("Missing option: --%s", name), ExitCodeMissingOption)
 }
 
 func InvalidOption(name string, value string, values []string) error {
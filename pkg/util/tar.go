@@ -0,0 +1,121 @@
+package util
+
+import (
+	"archive/tar"
+	"compress/gzip"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// TargzDir writes the files of the directory into a gzipped tarball keeping their paths relative to the directory
+func TargzDir(dir string, tarball string) (err error) {
+	f, err := os.Create(tarball)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		closeErr := f.Close()
+		if err == nil {
+			err = closeErr
+		}
+	}()
+	zwriter := gzip.NewWriter(f)
+	tarWriter := tar.NewWriter(zwriter)
+	err = filepath.Walk(dir, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		rel, err := filepath.Rel(dir, path)
+		if err != nil || rel == "." {
+			return err
+		}
+		if !info.IsDir() && !info.Mode().IsRegular() {
+			// links and devices are not bundled
+			return nil
+		}
+		header, err := tar.FileInfoHeader(info, "")
+		if err != nil {
+			return err
+		}
+		header.Name = filepath.ToSlash(rel)
+		if info.IsDir() {
+			header.Name += "/"
+		}
+		err = tarWriter.WriteHeader(header)
+		if err != nil || info.IsDir() {
+			return err
+		}
+		file, err := os.Open(path)
+		if err != nil {
+			return err
+		}
+		defer file.Close()
+		_, err = io.Copy(tarWriter, file)
+		return err
+	})
+	if err != nil {
+		return err
+	}
+	err = tarWriter.Close()
+	if err != nil {
+		return err
+	}
+	return zwriter.Close()
+}
+
+// UnTargzDir extracts all of the files of a gzipped tarball into the target directory keeping their paths
+func UnTargzDir(tarball string, target string) error {
+	f, err := os.Open(tarball)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	zreader, err := gzip.NewReader(f)
+	if err != nil {
+		return err
+	}
+	defer zreader.Close()
+	tarReader := tar.NewReader(zreader)
+	for {
+		header, err := tarReader.Next()
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		path := filepath.Join(target, filepath.FromSlash(header.Name))
+		if path != filepath.Clean(target) && !strings.HasPrefix(path, filepath.Clean(target)+string(os.PathSeparator)) {
+			return fmt.Errorf("the file %s of tarball %s is outside of the target directory", header.Name, tarball)
+		}
+		switch header.Typeflag {
+		case tar.TypeDir:
+			err = os.MkdirAll(path, DefaultWritePermissions)
+			if err != nil {
+				return err
+			}
+		case tar.TypeReg, tar.TypeRegA:
+			err = os.MkdirAll(filepath.Dir(path), DefaultWritePermissions)
+			if err != nil {
+				return err
+			}
+			err = writeTarFile(path, header.FileInfo().Mode(), tarReader)
+			if err != nil {
+				return err
+			}
+		}
+	}
+}
+
+func writeTarFile(path string, mode os.FileMode, reader io.Reader) error {
+	file, err := os.OpenFile(path, os.O_CREATE|os.O_TRUNC|os.O_WRONLY, mode)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+	_, err = io.Copy(file, reader)
+	return err
+}
@@ -0,0 +1,40 @@
+package util_test
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/jenkins-x/jx/pkg/util"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTargzDir(t *testing.T) {
+	t.Parallel()
+	testDir, err := ioutil.TempDir("", "test-targz-dir")
+	assert.NoError(t, err)
+	defer os.RemoveAll(testDir)
+
+	sourceDir := filepath.Join(testDir, "source")
+	err = os.MkdirAll(filepath.Join(sourceDir, "a", "b"), util.DefaultWritePermissions)
+	assert.NoError(t, err)
+	err = ioutil.WriteFile(filepath.Join(sourceDir, "a", "b", "c.txt"), []byte("c"), util.DefaultWritePermissions)
+	assert.NoError(t, err)
+	err = ioutil.WriteFile(filepath.Join(sourceDir, "d.txt"), []byte("d"), util.DefaultWritePermissions)
+	assert.NoError(t, err)
+
+	tarball := filepath.Join(testDir, "source.tar.gz")
+	err = util.TargzDir(sourceDir, tarball)
+	assert.NoError(t, err)
+
+	targetDir := filepath.Join(testDir, "target")
+	err = util.UnTargzDir(tarball, targetDir)
+	assert.NoError(t, err)
+	data, err := ioutil.ReadFile(filepath.Join(targetDir, "a", "b", "c.txt"))
+	assert.NoError(t, err)
+	assert.Equal(t, "c", string(data))
+	data, err = ioutil.ReadFile(filepath.Join(targetDir, "d.txt"))
+	assert.NoError(t, err)
+	assert.Equal(t, "d", string(data))
+}
@@ -0,0 +1,120 @@
+package util
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"runtime"
+	"sort"
+	"syscall"
+	"time"
+)
+
+// workspaceFileName the file in each workspace describing the process which created it
+const workspaceFileName = ".jx-workspace.json"
+
+// Workspace a temporary directory such as the clone of a repository which is tracked so that the workspaces left
+// behind by processes which were killed can be garbage collected
+type Workspace struct {
+	// Dir the directory of the workspace
+	Dir string `json:"-"`
+	// PID the process which created the workspace
+	PID int `json:"pid"`
+	// Host the host of the process which created the workspace
+	Host string `json:"host"`
+	// Created when the workspace was created
+	Created time.Time `json:"created"`
+}
+
+// CreateWorkspace creates a tracked workspace directory in the workspaces directory whose name starts with the prefix.
+// The workspace should be removed with os.RemoveAll when it is no longer needed
+func CreateWorkspace(prefix string) (string, error) {
+	workspacesDir, err := WorkspacesDir()
+	if err != nil {
+		return "", err
+	}
+	dir, err := ioutil.TempDir(workspacesDir, prefix)
+	if err != nil {
+		return "", err
+	}
+	host, _ := os.Hostname()
+	data, err := json.Marshal(&Workspace{
+		PID:     os.Getpid(),
+		Host:    host,
+		Created: time.Now(),
+	})
+	if err == nil {
+		err = ioutil.WriteFile(filepath.Join(dir, workspaceFileName), data, DefaultWritePermissions)
+	}
+	if err != nil {
+		os.RemoveAll(dir)
+		return "", err
+	}
+	return dir, nil
+}
+
+// ListWorkspaces returns the tracked workspaces ordered by when they were created. Directories of the workspaces
+// directory which do not describe their process are returned as created when they were last modified
+func ListWorkspaces() ([]*Workspace, error) {
+	workspacesDir, err := WorkspacesDir()
+	if err != nil {
+		return nil, err
+	}
+	files, err := ioutil.ReadDir(workspacesDir)
+	if err != nil {
+		return nil, err
+	}
+	answer := []*Workspace{}
+	for _, f := range files {
+		if !f.IsDir() {
+			continue
+		}
+		workspace := &Workspace{}
+		dir := filepath.Join(workspacesDir, f.Name())
+		data, err := ioutil.ReadFile(filepath.Join(dir, workspaceFileName))
+		if err != nil || json.Unmarshal(data, workspace) != nil {
+			workspace = &Workspace{
+				Created: f.ModTime(),
+			}
+		}
+		workspace.Dir = dir
+		answer = append(answer, workspace)
+	}
+	sort.Slice(answer, func(i, j int) bool {
+		return answer[i].Created.Before(answer[j].Created)
+	})
+	return answer, nil
+}
+
+// IsStale returns true if the workspace is older than the maximum age or the process which created it on this host
+// is no longer running
+func (w *Workspace) IsStale(maxAge time.Duration) bool {
+	if time.Since(w.Created) > maxAge {
+		return true
+	}
+	if w.PID <= 0 {
+		return false
+	}
+	host, err := os.Hostname()
+	if err != nil || host != w.Host {
+		// the processes of other hosts sharing the home directory cannot be checked
+		return false
+	}
+	return !processRunning(w.PID)
+}
+
+// processRunning returns true if the process is running
+func processRunning(pid int) bool {
+	p, err := os.FindProcess(pid)
+	if err != nil {
+		return false
+	}
+	if runtime.GOOS == "windows" {
+		// finding a process on windows fails if it is not running
+		return true
+	}
+	err = p.Signal(syscall.Signal(0))
+	// the processes of other users cannot be signalled
+	return err == nil || err == syscall.EPERM
+}
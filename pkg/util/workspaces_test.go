@@ -0,0 +1,48 @@
+package util_test
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/jenkins-x/jx/pkg/util"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWorkspaces(t *testing.T) {
+	// not parallel as the workspaces are found in $JX_HOME
+	jxHome, err := ioutil.TempDir("", "test-workspaces")
+	assert.NoError(t, err)
+	defer os.RemoveAll(jxHome)
+	defer os.Setenv("JX_HOME", os.Getenv("JX_HOME"))
+	os.Setenv("JX_HOME", jxHome)
+
+	dir, err := util.CreateWorkspace("jx-test-")
+	assert.NoError(t, err)
+	assert.True(t, strings.HasPrefix(dir, filepath.Join(jxHome, "workspaces", "jx-test-")))
+
+	// a workspace of a process which is no longer running
+	host, err := os.Hostname()
+	assert.NoError(t, err)
+	killedDir, err := util.CreateWorkspace("jx-killed-")
+	assert.NoError(t, err)
+	data, err := json.Marshal(map[string]interface{}{"pid": 999999999, "host": host, "created": time.Now()})
+	assert.NoError(t, err)
+	err = ioutil.WriteFile(filepath.Join(killedDir, ".jx-workspace.json"), data, util.DefaultWritePermissions)
+	assert.NoError(t, err)
+
+	workspaces, err := util.ListWorkspaces()
+	assert.NoError(t, err)
+	if assert.Len(t, workspaces, 2) {
+		assert.Equal(t, dir, workspaces[0].Dir)
+		assert.Equal(t, os.Getpid(), workspaces[0].PID)
+		assert.False(t, workspaces[0].IsStale(time.Hour), "the workspace of this process should not be stale")
+		assert.True(t, workspaces[0].IsStale(time.Nanosecond), "the workspace should be stale when it is too old")
+		assert.Equal(t, killedDir, workspaces[1].Dir)
+		assert.True(t, workspaces[1].IsStale(time.Hour), "the workspace of a process which is not running should be stale")
+	}
+}
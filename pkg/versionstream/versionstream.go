@@ -0,0 +1,78 @@
+package versionstream
+
+import (
+	"fmt"
+	"io/ioutil"
+	"path/filepath"
+	"strings"
+
+	"github.com/blang/semver"
+	"github.com/jenkins-x/jx/pkg/util"
+	"gopkg.in/yaml.v2"
+)
+
+const (
+	// DefaultVersionsURL the default git repository containing the version stream
+	DefaultVersionsURL = "https://github.com/jenkins-x/jenkins-x-versions.git"
+
+	// DockerDir the directory in the version stream containing the versions of docker images
+	DockerDir = "docker"
+)
+
+// StableVersion the stable version of a resource in the version stream
+type StableVersion struct {
+	// Version the stable version of the resource
+	Version string `yaml:"version,omitempty"`
+}
+
+// LoadImageVersion loads the stable version of the given docker image (without a tag) from the version stream
+// in the given directory. An empty string is returned if the version stream does not contain the image.
+//
+// The version of an image such as 'jenkinsxio/builder-maven' is stored in the file 'docker/jenkinsxio/builder-maven.yml'
+func LoadImageVersion(dir string, image string) (string, error) {
+	fileName := filepath.Join(dir, DockerDir, filepath.FromSlash(image)+".yml")
+	exists, err := util.FileExists(fileName)
+	if err != nil || !exists {
+		return "", err
+	}
+	data, err := ioutil.ReadFile(fileName)
+	if err != nil {
+		return "", fmt.Errorf("Failed to load file %s due to %s", fileName, err)
+	}
+	version := &StableVersion{}
+	err = yaml.Unmarshal(data, version)
+	if err != nil {
+		return "", fmt.Errorf("Failed to unmarshal YAML file %s due to %s", fileName, err)
+	}
+	return version.Version, nil
+}
+
+// SplitImageTag splits the given docker image into its name and tag. The digest of an image such as
+// 'image@sha256:...' is not a tag so it is removed from the name and the tag is empty unless the image also has one
+func SplitImageTag(image string) (string, string) {
+	if idx := strings.Index(image, "@"); idx >= 0 {
+		image = image[0:idx]
+	}
+	idx := strings.LastIndex(image, ":")
+	if idx < 0 || strings.Contains(image[idx+1:], "/") {
+		return image, ""
+	}
+	return image[0:idx], image[idx+1:]
+}
+
+// IsNewerVersion returns true if the candidate version is newer than the current version. If either version
+// is not a semantic version then any different version is considered newer
+func IsNewerVersion(current string, candidate string) bool {
+	if candidate == "" || candidate == current {
+		return false
+	}
+	currentVersion, err := semver.ParseTolerant(current)
+	if err != nil {
+		return true
+	}
+	candidateVersion, err := semver.ParseTolerant(candidate)
+	if err != nil {
+		return true
+	}
+	return candidateVersion.GT(currentVersion)
+}
@@ -0,0 +1,53 @@
+package versionstream_test
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/jenkins-x/jx/pkg/versionstream"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSplitImageTag(t *testing.T) {
+	t.Parallel()
+	name, tag := versionstream.SplitImageTag("jenkinsxio/builder-maven:0.0.408")
+	assert.Equal(t, "jenkinsxio/builder-maven", name)
+	assert.Equal(t, "0.0.408", tag)
+
+	name, tag = versionstream.SplitImageTag("localhost:5000/builder-maven")
+	assert.Equal(t, "localhost:5000/builder-maven", name)
+	assert.Equal(t, "", tag)
+
+	name, tag = versionstream.SplitImageTag("jenkinsxio/builder-maven@sha256:4f9e6d2a1c")
+	assert.Equal(t, "jenkinsxio/builder-maven", name)
+	assert.Equal(t, "", tag)
+
+	name, tag = versionstream.SplitImageTag("localhost:5000/builder-maven:0.0.408@sha256:4f9e6d2a1c")
+	assert.Equal(t, "localhost:5000/builder-maven", name)
+	assert.Equal(t, "0.0.408", tag)
+}
+
+func TestLoadImageVersion(t *testing.T) {
+	t.Parallel()
+	dir, err := ioutil.TempDir("", "test-versionstream")
+	assert.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	imageDir := filepath.Join(dir, versionstream.DockerDir, "jenkinsxio")
+	assert.NoError(t, os.MkdirAll(imageDir, 0755))
+	assert.NoError(t, ioutil.WriteFile(filepath.Join(imageDir, "builder-maven.yml"), []byte("version: 0.0.500\n"), 0644))
+
+	version, err := versionstream.LoadImageVersion(dir, "jenkinsxio/builder-maven")
+	assert.NoError(t, err)
+	assert.Equal(t, "0.0.500", version)
+
+	version, err = versionstream.LoadImageVersion(dir, "jenkinsxio/builder-go")
+	assert.NoError(t, err)
+	assert.Equal(t, "", version)
+
+	assert.True(t, versionstream.IsNewerVersion("0.0.408", "0.0.500"))
+	assert.False(t, versionstream.IsNewerVersion("0.0.500", "0.0.408"))
+	assert.False(t, versionstream.IsNewerVersion("0.0.500", ""))
+}